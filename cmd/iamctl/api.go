@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"backend/internal/infrastructure/config"
+	"backend/internal/presentation/handlers"
+	"backend/internal/presentation/middleware"
+)
+
+// apiClient calls the admin routes of the /api/v1 API.
+type apiClient struct {
+	config *config.CLIConfig
+	http   *http.Client
+}
+
+func newAPIClient() *apiClient {
+	return &apiClient{config: config.NewCLIConfig(), http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// apiError is a response of the API with a status outside 2xx.
+type apiError struct {
+	Status     string
+	StatusCode int
+	Message    string
+	Code       string
+}
+
+func (e *apiError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s: %s (%s)", e.Status, e.Message, e.Code)
+	}
+	return fmt.Sprintf("%s: %s", e.Status, e.Message)
+}
+
+// call sends body, unless nil, as JSON and returns the response body. An idempotency key lets a
+// script retry a create whose response it never got without creating the resource twice.
+func (c *apiClient) call(ctx context.Context, method, path string, body interface{}, idempotencyKey string) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.config.APIURL+"/api/v1"+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.config.OperatorToken != "":
+		req.Header.Set("X-Operator-Token", c.config.OperatorToken)
+	case c.config.Token != "":
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+	if idempotencyKey != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, idempotencyKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 300 {
+		apiErr := &apiError{Status: resp.Status, StatusCode: resp.StatusCode}
+		var body handlers.ErrorResponse
+		if json.Unmarshal(data, &body) == nil && body.Error != "" {
+			apiErr.Message, apiErr.Code = body.Error, body.Code
+		} else {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return nil, apiErr
+	}
+	return data, nil
+}
+
+// post sends a create or update request and prints the response body.
+func (c *apiClient) post(ctx context.Context, path string, body interface{}, idempotencyKey string) error {
+	data, err := c.call(ctx, http.MethodPost, path, body, idempotencyKey)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		out.Reset()
+		out.Write(data)
+	}
+	fmt.Println(out.String())
+	return nil
+}
+
+func createDomain(ctx context.Context, args []string) error {
+	fset := flag.NewFlagSet("create-domain", flag.ContinueOnError)
+	var req handlers.CreateDomainRequest
+	fset.StringVar(&req.Name, "name", "", "display name of the domain (required)")
+	fset.StringVar(&req.Domain, "domain", "", "hostname of the domain, e.g. acme.example.com (required)")
+	fset.StringVar(&req.Residency, "residency", "", "residency shard holding the domain's data (default: the primary database)")
+	fset.StringVar(&req.LoginMode, "login-mode", "", "password or passwordless (default: password)")
+	idempotencyKey := fset.String("idempotency-key", "", "Idempotency-Key sent with the request")
+	if err := parseFlags(fset, args, "name", "domain"); err != nil {
+		return err
+	}
+	return newAPIClient().post(ctx, "/domains", req, *idempotencyKey)
+}
+
+func createUser(ctx context.Context, args []string) error {
+	fset := flag.NewFlagSet("create-user", flag.ContinueOnError)
+	var req handlers.CreateUserRequest
+	fset.StringVar(&req.DomainID, "domain-id", "", "domain of the user (required)")
+	fset.StringVar(&req.RoleID, "role-id", "", "role of the user (required)")
+	fset.StringVar(&req.Username, "username", "", "username (required)")
+	fset.StringVar(&req.Email, "email", "", "email address (required)")
+	fset.StringVar(&req.FirstName, "first-name", "", "first name (required)")
+	fset.StringVar(&req.LastName, "last-name", "", "last name (required)")
+	passwordStdin := fset.Bool("password-stdin", false, "read the password from the first line of standard input")
+	idempotencyKey := fset.String("idempotency-key", "", "Idempotency-Key sent with the request")
+	if err := parseFlags(fset, args, "domain-id", "role-id", "username", "email", "first-name", "last-name"); err != nil {
+		return err
+	}
+	if *passwordStdin {
+		password, err := readPassword()
+		if err != nil {
+			return err
+		}
+		req.Password = password
+	}
+	return newAPIClient().post(ctx, "/users", req, *idempotencyKey)
+}
+
+func resetPassword(ctx context.Context, args []string) error {
+	fset := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	userID := fset.String("user-id", "", "user whose password is replaced (required)")
+	if err := parseFlags(fset, args, "user-id"); err != nil {
+		return err
+	}
+	// The password is read from standard input so it never shows up in the shell history or
+	// the process list
+	password, err := readPassword()
+	if err != nil {
+		return err
+	}
+	return newAPIClient().post(ctx, "/users/"+*userID+"/reset-password", handlers.ResetPasswordRequest{NewPassword: password}, "")
+}
+
+// readPassword reads a password from the first line of standard input.
+func readPassword() (string, error) {
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprint(os.Stderr, "Password: ")
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("no password on standard input")
+	}
+	return password, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/signing"
+)
+
+// rotateJWTKeys writes a new signing key and prints the settings that make the server sign with
+// it while still accepting the tokens of the current key. The server picks the key up on restart.
+func rotateJWTKeys(ctx context.Context, args []string) error {
+	fset := flag.NewFlagSet("rotate-jwt-keys", flag.ContinueOnError)
+	alg := fset.String("alg", "ES256", "algorithm of the new key: ES256 (P-256) or RS256")
+	bits := fset.Int("bits", 3072, "size of an RS256 key")
+	out := fset.String("out", "", "file the new PEM private key is written to; must not exist (required)")
+	if err := parseFlags(fset, args, "out"); err != nil {
+		return err
+	}
+
+	block, err := generateSigningKey(*alg, *bits)
+	if err != nil {
+		return err
+	}
+	data := pem.EncodeToMemory(block)
+	key, err := signing.ParsePrivateKey(data)
+	if err != nil {
+		return err
+	}
+	// O_EXCL so a mistyped path never replaces a key that is in use
+	file, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote %s key %s to %s\n", key.Algorithm(), key.ID, *out)
+	current := config.NewJWTConfig()
+	previous := current.PreviousKeyFiles
+	switch current.KeySource() {
+	case "file":
+		previous = append([]string{current.PrivateKeyFile}, previous...)
+	case "env":
+		fmt.Fprintln(os.Stderr, "JWT_PRIVATE_KEY holds the current key: save it to a file and add that file to JWT_PREVIOUS_KEY_FILES, or its tokens are rejected after the restart.")
+	default:
+		fmt.Fprintln(os.Stderr, "Tokens are currently signed with JWT_SECRET, which can't be kept as a previous key: tokens issued so far are rejected after the restart.")
+	}
+	fmt.Fprintln(os.Stderr, "Set these and restart the server; drop the previous keys once the tokens they signed have expired:")
+	fmt.Printf("JWT_PRIVATE_KEY=\nJWT_PRIVATE_KEY_FILE=%s\n", *out)
+	fmt.Printf("JWT_PREVIOUS_KEY_FILES=%s\n", strings.Join(previous, ","))
+	return nil
+}
+
+func generateSigningKey(alg string, bits int) (*pem.Block, error) {
+	switch strings.ToUpper(alg) {
+	case "ES256":
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}, nil
+	case "RS256":
+		if bits < 2048 {
+			return nil, fmt.Errorf("RS256 keys need at least 2048 bits")
+		}
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, err
+		}
+		return &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}, nil
+	}
+	return nil, fmt.Errorf("unsupported algorithm %q; use ES256 or RS256", alg)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+)
+
+// migrate applies the migrations of the directory that aren't recorded yet, in file name order,
+// to the primary database and every residency shard. It keeps the same schema_migrations
+// bookkeeping as run_migrations.sh, so the two can be mixed.
+func migrate(ctx context.Context, args []string) error {
+	startup, err := config.NewStartupConfig()
+	if err != nil {
+		return err
+	}
+	fset := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dir := fset.String("dir", startup.MigrationsDir, "directory of the migration files")
+	dryRun := fset.Bool("dry-run", false, "list the pending migrations without applying them")
+	if err := parseFlags(fset, args); err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(*dir, "*.sql"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no migrations found in %s", *dir)
+	}
+	sort.Strings(files)
+
+	router, closeDBs, err := openDatabases()
+	if err != nil {
+		return err
+	}
+	defer closeDBs()
+	schema := repositories.NewSchemaRepository(router)
+
+	for _, residency := range router.Residencies() {
+		recorded, err := schema.RecordedMigrations(ctx, residency)
+		if err != nil {
+			return fmt.Errorf("%s: %w", residency, err)
+		}
+		pending := 0
+		for _, file := range files {
+			version := filepath.Base(file)
+			if recorded[version] {
+				continue
+			}
+			pending++
+			if *dryRun {
+				fmt.Printf("%s: %s pending\n", residency, version)
+				continue
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			if err := schema.ApplyMigration(ctx, residency, version, repositories.SplitStatements(string(data))); err != nil {
+				return fmt.Errorf("%s: %s: %w", residency, version, err)
+			}
+			fmt.Printf("%s: applied %s\n", residency, version)
+		}
+		if pending == 0 {
+			fmt.Printf("%s: up to date\n", residency)
+		}
+	}
+	return nil
+}
+
+// openDatabases connects to the primary database and the residency shards configured for the
+// server.
+func openDatabases() (*repositories.ShardRouter, func(), error) {
+	dbConfig, err := config.NewDatabaseConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := dbConfig.OpenDB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	shardDSNs, err := config.NewShardDSNs()
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	shards, err := dbConfig.OpenShards(shardDSNs)
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to connect to residency shard: %w", err)
+	}
+	closeDBs := func() {
+		db.Close()
+		for _, shard := range shards {
+			shard.Close()
+		}
+	}
+	return repositories.NewShardRouter(db, shards, nil), closeDBs, nil
+}
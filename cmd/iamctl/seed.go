@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"backend/internal/domain/entities"
+	"backend/internal/presentation/handlers"
+)
+
+// The seed fixtures are the same on every run, so a frontend developer can sign in as any seeded
+// user on a fresh database without looking anything up.
+const (
+	seedUsersPerDomain = 25
+	seedEditors        = 8
+	seedPassword       = "Dev-passw0rd!"
+)
+
+type seedDomain struct {
+	name string
+	host string
+}
+
+type seedRole struct {
+	name        string
+	permissions []string
+}
+
+var seedDomains = []seedDomain{
+	{name: "Acme Corp", host: "acme.localhost"},
+	{name: "Globex", host: "globex.localhost"},
+}
+
+// The first user of each domain gets the first role, the next seedEditors users the second and
+// the others the last.
+var seedRoles = []seedRole{
+	{name: "admin", permissions: []string{"domain:admin"}},
+	{name: "editor", permissions: []string{"users:read", "users:write", "groups:read", "groups:write"}},
+	{name: "viewer", permissions: []string{"users:read", "groups:read"}},
+}
+
+var (
+	seedFirstNames = []string{"Ava", "Ben", "Chloe", "Dimas", "Eka", "Farah", "Gita", "Hana", "Irfan", "Joko", "Kirana", "Lukas", "Maya"}
+	seedLastNames  = []string{"Santoso", "Wijaya", "Pratama", "Smith", "Nguyen", "Garcia", "Putri", "Tanaka", "Hidayat", "Kowalski", "Lestari"}
+)
+
+// seed loads the development fixtures through the API: two domains, each with the seed roles and
+// seedUsersPerDomain users who all sign in with the seed password. Each step creates only what is
+// missing, so running it again completes a seed that stopped halfway and otherwise changes
+// nothing; seeded users who already exist keep their password.
+func seed(ctx context.Context, args []string) error {
+	fset := flag.NewFlagSet("seed", flag.ContinueOnError)
+	password := fset.String("password", seedPassword, "password of every seeded user")
+	if err := parseFlags(fset, args); err != nil {
+		return err
+	}
+
+	client := newAPIClient()
+	for _, fixture := range seedDomains {
+		if err := client.seedDomain(ctx, fixture, *password); err != nil {
+			return fmt.Errorf("%s: %w", fixture.host, err)
+		}
+	}
+	fmt.Printf("Seeded users sign in with username user01 to user%02d and password %s\n", seedUsersPerDomain, *password)
+	return nil
+}
+
+func (c *apiClient) seedDomain(ctx context.Context, fixture seedDomain, password string) error {
+	domain, created, err := c.seedDomainOf(ctx, fixture)
+	if err != nil {
+		return err
+	}
+	domainPath := "/domains/" + domain.DomainID.String()
+
+	var existingRoles []entities.Role
+	if err := c.get(ctx, domainPath+"/roles", &existingRoles); err != nil {
+		return err
+	}
+	rolesByName := make(map[string]entities.Role, len(existingRoles))
+	for _, role := range existingRoles {
+		rolesByName[role.RoleName] = role
+	}
+	roles := make([]entities.Role, len(seedRoles))
+	var createdRoles []string
+	for i, fixtureRole := range seedRoles {
+		if role, ok := rolesByName[fixtureRole.name]; ok {
+			roles[i] = role
+			continue
+		}
+		req := handlers.CreateRoleRequest{
+			RoleName:   fixtureRole.name,
+			RoleClaims: map[string]interface{}{"permissions": fixtureRole.permissions},
+		}
+		if err := c.create(ctx, domainPath+"/roles", req, &roles[i]); err != nil {
+			return fmt.Errorf("role %s: %w", fixtureRole.name, err)
+		}
+		createdRoles = append(createdRoles, fixtureRole.name)
+	}
+
+	var existingUsers []entities.User
+	if err := c.get(ctx, domainPath+"/users", &existingUsers); err != nil {
+		return err
+	}
+	usernames := make(map[string]bool, len(existingUsers))
+	for _, user := range existingUsers {
+		usernames[user.Username] = true
+	}
+	createdUsers := 0
+	for n := 1; n <= seedUsersPerDomain; n++ {
+		username := fmt.Sprintf("user%02d", n)
+		if usernames[username] {
+			continue
+		}
+		role := roles[len(roles)-1]
+		switch {
+		case n == 1:
+			role = roles[0]
+		case n <= 1+seedEditors:
+			role = roles[1]
+		}
+		req := handlers.CreateUserRequest{
+			DomainID:  domain.DomainID.String(),
+			RoleID:    role.ID.String(),
+			FirstName: seedFirstNames[(n-1)%len(seedFirstNames)],
+			LastName:  seedLastNames[(n-1)%len(seedLastNames)],
+			Username:  username,
+			Email:     username + "@" + fixture.host,
+			Password:  password,
+		}
+		if err := c.create(ctx, "/users", req, nil); err != nil {
+			return fmt.Errorf("user %s: %w", username, err)
+		}
+		createdUsers++
+	}
+
+	switch {
+	case created:
+		fmt.Printf("%s: created domain %s with roles %s and %d users\n", fixture.host, domain.DomainID, strings.Join(createdRoles, ", "), createdUsers)
+	case len(createdRoles) == 0 && createdUsers == 0:
+		fmt.Printf("%s: already seeded, skipped\n", fixture.host)
+	default:
+		fmt.Printf("%s: completed domain %s with %d missing roles and %d missing users\n", fixture.host, domain.DomainID, len(createdRoles), createdUsers)
+	}
+	return nil
+}
+
+// seedDomainOf returns the domain of the fixture, creating it when it doesn't exist yet.
+func (c *apiClient) seedDomainOf(ctx context.Context, fixture seedDomain) (*entities.Domain, bool, error) {
+	var domain entities.Domain
+	err := c.get(ctx, "/domains/resolve?host="+url.QueryEscape(fixture.host), &domain)
+	var apiErr *apiError
+	switch {
+	case err == nil:
+		return &domain, false, nil
+	case !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound:
+		return nil, false, err
+	}
+	if err := c.create(ctx, "/domains", handlers.CreateDomainRequest{Name: fixture.name, Domain: fixture.host}, &domain); err != nil {
+		return nil, false, err
+	}
+	return &domain, true, nil
+}
+
+// get decodes the resource at path into out.
+func (c *apiClient) get(ctx context.Context, path string, out interface{}) error {
+	data, err := c.call(ctx, http.MethodGet, path, nil, "")
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// create posts a create request and decodes the created resource into out, unless nil.
+func (c *apiClient) create(ctx context.Context, path string, body interface{}, out interface{}) error {
+	data, err := c.call(ctx, http.MethodPost, path, body, "")
+	if err != nil || out == nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
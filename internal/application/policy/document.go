@@ -0,0 +1,75 @@
+// Package policy evaluates attribute-based access control (ABAC) policy documents.
+package policy
+
+import (
+	"encoding/json"
+	"strings"
+
+	domainerrors "backend/internal/domain/errors"
+)
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Document is a single policy rule. It applies when the request's resource and action match one of
+// the listed patterns ("*" and trailing wildcards such as "invoices:*" are supported) and every
+// condition holds.
+//
+//	{
+//	  "effect": "allow",
+//	  "resources": ["invoices"],
+//	  "actions": ["read", "update"],
+//	  "conditions": [
+//	    {"attribute": "resource.owner_id", "operator": "equals", "value": "${user.id}"},
+//	    {"attribute": "claims.department", "operator": "in", "value": ["finance", "audit"]}
+//	  ]
+//	}
+type Document struct {
+	Effect     string      `json:"effect"`
+	Resources  []string    `json:"resources"`
+	Actions    []string    `json:"actions"`
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// Condition compares the attribute at a dotted path (user.*, domain.*, claims.*, resource.*,
+// context.*) against a literal value. A string value of the form "${path}" is resolved from the
+// attributes instead, so policies can compare two attributes.
+type Condition struct {
+	Attribute string      `json:"attribute"`
+	Operator  string      `json:"operator"`
+	Value     interface{} `json:"value,omitempty"`
+}
+
+// Parse decodes and validates a policy document.
+func Parse(raw []byte) (*Document, error) {
+	var doc Document
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, domainerrors.Validation("invalid policy document: %v", err).Wrap(err)
+	}
+	if err := doc.Validate(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (d *Document) Validate() error {
+	if d.Effect != EffectAllow && d.Effect != EffectDeny {
+		return domainerrors.Validation("invalid policy document: effect must be allow or deny")
+	}
+	if len(d.Resources) == 0 || len(d.Actions) == 0 {
+		return domainerrors.Validation("invalid policy document: resources and actions are required")
+	}
+	for _, condition := range d.Conditions {
+		if condition.Attribute == "" {
+			return domainerrors.Validation("invalid policy document: condition attribute is required")
+		}
+		if _, ok := operators[condition.Operator]; !ok {
+			return domainerrors.Validation("invalid policy document: unknown operator %q", condition.Operator)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Policy is a named document as stored for a domain.
+type Policy struct {
+	ID       string
+	Name     string
+	Document *Document
+}
+
+// Request describes the access being asked about. Attributes are keyed by namespace
+// (user, domain, claims, resource, context) and may nest.
+type Request struct {
+	Resource   string
+	Action     string
+	Attributes map[string]interface{}
+}
+
+type Decision struct {
+	Allowed         bool     `json:"allowed"`
+	Reason          string   `json:"reason"`
+	MatchedPolicies []string `json:"matched_policies"`
+}
+
+// Evaluate applies deny-overrides semantics: any matching deny policy wins, otherwise access is
+// allowed if at least one allow policy matches. Without a match the request is denied.
+func Evaluate(policies []Policy, req Request) *Decision {
+	var allows, denies []string
+	for _, p := range policies {
+		if !p.Document.matches(req) {
+			continue
+		}
+		if p.Document.Effect == EffectDeny {
+			denies = append(denies, p.Name)
+		} else {
+			allows = append(allows, p.Name)
+		}
+	}
+
+	switch {
+	case len(denies) > 0:
+		return &Decision{Allowed: false, Reason: "denied by policy", MatchedPolicies: denies}
+	case len(allows) > 0:
+		return &Decision{Allowed: true, Reason: "allowed by policy", MatchedPolicies: allows}
+	default:
+		return &Decision{Allowed: false, Reason: "no matching policy", MatchedPolicies: []string{}}
+	}
+}
+
+func (d *Document) matches(req Request) bool {
+	if !matchAny(d.Resources, req.Resource) || !matchAny(d.Actions, req.Action) {
+		return false
+	}
+	for _, condition := range d.Conditions {
+		if !condition.holds(req.Attributes) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == value {
+			return true
+		}
+		if strings.HasSuffix(pattern, "*") && strings.HasPrefix(value, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Condition) holds(attributes map[string]interface{}) bool {
+	actual, found := lookup(attributes, c.Attribute)
+	expected := c.Value
+	if ref, ok := expected.(string); ok && strings.HasPrefix(ref, "${") && strings.HasSuffix(ref, "}") {
+		var resolved bool
+		expected, resolved = lookup(attributes, ref[2:len(ref)-1])
+		if !resolved && c.Operator != "exists" && c.Operator != "not_exists" {
+			return false
+		}
+	}
+	return operators[c.Operator](actual, found, expected)
+}
+
+// lookup walks a dotted path through nested maps.
+func lookup(attributes map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = attributes
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+type operator func(actual interface{}, found bool, expected interface{}) bool
+
+var operators = map[string]operator{
+	"equals": func(a interface{}, found bool, e interface{}) bool {
+		return found && equal(a, e)
+	},
+	"not_equals": func(a interface{}, found bool, e interface{}) bool {
+		return !found || !equal(a, e)
+	},
+	"in": func(a interface{}, found bool, e interface{}) bool {
+		return found && containsValue(e, a)
+	},
+	"not_in": func(a interface{}, found bool, e interface{}) bool {
+		return !found || !containsValue(e, a)
+	},
+	"contains": func(a interface{}, found bool, e interface{}) bool {
+		if s, ok := a.(string); ok {
+			return strings.Contains(s, fmt.Sprint(e))
+		}
+		return found && containsValue(a, e)
+	},
+	"starts_with": func(a interface{}, found bool, e interface{}) bool {
+		s, ok := a.(string)
+		return ok && strings.HasPrefix(s, fmt.Sprint(e))
+	},
+	"exists": func(_ interface{}, found bool, _ interface{}) bool {
+		return found
+	},
+	"not_exists": func(_ interface{}, found bool, _ interface{}) bool {
+		return !found
+	},
+	"gt":  numeric(func(a, e float64) bool { return a > e }),
+	"gte": numeric(func(a, e float64) bool { return a >= e }),
+	"lt":  numeric(func(a, e float64) bool { return a < e }),
+	"lte": numeric(func(a, e float64) bool { return a <= e }),
+}
+
+func numeric(compare func(a, e float64) bool) operator {
+	return func(a interface{}, found bool, e interface{}) bool {
+		av, aok := toNumber(a)
+		ev, eok := toNumber(e)
+		return found && aok && eok && compare(av, ev)
+	}
+}
+
+// equal compares numbers numerically and everything else by its string form, so that
+// JSON numbers, UUIDs and strings compare naturally.
+func equal(a, b interface{}) bool {
+	_, aString := a.(string)
+	_, bString := b.(string)
+	if av, ok := toNumber(a); ok && !aString {
+		if bv, ok := toNumber(b); ok && !bString {
+			return av == bv
+		}
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func containsValue(list interface{}, value interface{}) bool {
+	items, ok := list.([]interface{})
+	if !ok {
+		if strs, ok := list.([]string); ok {
+			for _, s := range strs {
+				items = append(items, s)
+			}
+		}
+	}
+	for _, item := range items {
+		if equal(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
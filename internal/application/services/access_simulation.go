@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"backend/internal/application/policy"
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// maxSimulatedChecks bounds the checks of one access simulation.
+const maxSimulatedChecks = 100
+
+// AccessSimulationRequest asks how a user, or any holder of a role, would fare on a list of
+// checks. Exactly one of UserID and RoleID is set.
+type AccessSimulationRequest struct {
+	UserID  *uuid.UUID
+	RoleID  *uuid.UUID
+	Checks  []AccessCheck
+	Context map[string]interface{} // the request context policies see for every check
+}
+
+type AccessCheck struct {
+	Resource           string
+	Action             string
+	ResourceAttributes map[string]interface{}
+}
+
+type AccessSimulationResult struct {
+	DomainID uuid.UUID          `json:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	UserID   *uuid.UUID         `json:"user_id,omitempty" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	RoleID   *uuid.UUID         `json:"role_id,omitempty" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Results  []*SimulatedAccess `json:"results"`
+}
+
+// SimulatedAccess is the outcome of one check. Allowed and MatchedRule are what /authz/check
+// would answer from the role claims; Policies is what /auth/authorize would answer from the
+// domain's ABAC policies.
+type SimulatedAccess struct {
+	Resource    string           `json:"resource" example:"documents"`
+	Action      string           `json:"action" example:"read"`
+	Allowed     bool             `json:"allowed"`
+	MatchedRule string           `json:"matched_rule,omitempty" example:"documents:*"`
+	MatchedRole *RoleRef         `json:"matched_role,omitempty"` // the role granting MatchedRule, direct role first
+	Policies    *policy.Decision `json:"policies"`
+}
+
+type RoleRef struct {
+	ID   uuid.UUID `json:"id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Name string    `json:"name" example:"editor"`
+}
+
+// roleGrant is the permissions one of the subject's roles grants.
+type roleGrant struct {
+	role    *entities.Role
+	granted []string
+}
+
+// SimulateAccess evaluates each check for the user, or a holder of the role, against the role
+// claims and the domain's policies, and explains what decided it. Nothing is recorded in the
+// decision log.
+func (s *authzService) SimulateAccess(ctx context.Context, req *AccessSimulationRequest) (*AccessSimulationResult, error) {
+	ctx, span := tracer.Start(ctx, "AuthzService.SimulateAccess")
+	defer span.End()
+
+	if (req.UserID == nil) == (req.RoleID == nil) {
+		return nil, domainerrors.Validation("provide either user_id or role_id")
+	}
+	if len(req.Checks) == 0 {
+		return nil, domainerrors.Validation("at least one check is required")
+	}
+	if len(req.Checks) > maxSimulatedChecks {
+		return nil, domainerrors.Validation("at most %d checks can be simulated at once", maxSimulatedChecks)
+	}
+	checks := make([]AccessCheck, len(req.Checks))
+	for i, check := range req.Checks {
+		check.Resource = strings.TrimSpace(check.Resource)
+		check.Action = strings.TrimSpace(check.Action)
+		if check.Resource == "" || check.Action == "" {
+			return nil, domainerrors.Validation("check %d: resource and action are required", i+1)
+		}
+		checks[i] = check
+	}
+
+	result := &AccessSimulationResult{UserID: req.UserID, RoleID: req.RoleID}
+	var grants []roleGrant
+	var attributes map[string]interface{}
+	if req.UserID != nil {
+		user, err := s.userRepo.GetByID(ctx, *req.UserID)
+		if err != nil {
+			return nil, notFoundOr(err, "user not found")
+		}
+		result.DomainID = user.DomainID
+		roles, err := s.resolver.effectiveRoles(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if grants, err = s.roleGrants(ctx, user.DomainID, roles); err != nil {
+			return nil, err
+		}
+		if attributes, err = s.policies.userAttributes(ctx, user); err != nil {
+			return nil, err
+		}
+	} else {
+		role, err := s.roleRepo.GetByID(ctx, *req.RoleID)
+		if err != nil {
+			return nil, notFoundOr(err, "role not found")
+		}
+		result.DomainID = role.DomainID
+		if grants, err = s.roleGrants(ctx, role.DomainID, []*entities.Role{role}); err != nil {
+			return nil, err
+		}
+		if attributes, err = s.policies.roleAttributes(ctx, role); err != nil {
+			return nil, err
+		}
+	}
+	policies, err := s.policies.policies(ctx, result.DomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var granted []string
+	for _, grant := range grants {
+		granted = append(granted, grant.granted...)
+	}
+	result.Results = make([]*SimulatedAccess, 0, len(checks))
+	for _, check := range checks {
+		access := &SimulatedAccess{
+			Resource: check.Resource,
+			Action:   check.Action,
+			Policies: evaluatePolicies(policies, attributes, check.Resource, check.Action, check.ResourceAttributes, req.Context),
+		}
+		access.Allowed, access.MatchedRule = evaluate(granted, check.Resource, check.Action)
+		if access.Allowed {
+			for _, grant := range grants {
+				if slices.Contains(grant.granted, access.MatchedRule) {
+					access.MatchedRole = &RoleRef{ID: grant.role.ID, Name: grant.role.RoleName}
+					break
+				}
+			}
+		}
+		result.Results = append(result.Results, access)
+	}
+	return result, nil
+}
+
+func (s *authzService) roleGrants(ctx context.Context, domainID uuid.UUID, roles []*entities.Role) ([]roleGrant, error) {
+	grants := make([]roleGrant, 0, len(roles))
+	for _, role := range roles {
+		granted, err := s.resolver.roleGrants(ctx, domainID, role, nil)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, roleGrant{role: role, granted: granted})
+	}
+	return grants, nil
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+const maxDeletionGraceDays = 90
+
+type AccountDeletionService interface {
+	RequestDeletion(ctx context.Context, userID uuid.UUID) (*entities.User, error)
+	CancelDeletion(ctx context.Context, userID uuid.UUID) (*entities.User, error)
+	DeleteScheduledUsers(ctx context.Context) (int, error)
+	RunDeletionSweep(ctx context.Context, interval time.Duration)
+}
+
+type accountDeletionService struct {
+	userRepo   repositories.UserRepository
+	domainRepo repositories.DomainRepository
+	users      UserService
+	events     EventService
+	mailer     DomainMailer
+	config     *config.AccountDeletionConfig
+}
+
+func NewAccountDeletionService(userRepo repositories.UserRepository, domainRepo repositories.DomainRepository, users UserService, events EventService, mailer DomainMailer, cfg *config.AccountDeletionConfig) AccountDeletionService {
+	return &accountDeletionService{userRepo: userRepo, domainRepo: domainRepo, users: users, events: events, mailer: mailer, config: cfg}
+}
+
+func validateAccountDeletion(settings *entities.AccountDeletionSettings) error {
+	if settings.GraceDays < 0 || settings.GraceDays > maxDeletionGraceDays {
+		return domainerrors.Validation("grace_days must be between 0 and %d", maxDeletionGraceDays)
+	}
+	return nil
+}
+
+// RequestDeletion schedules the deletion of the user's own account once the domain's grace period
+// has passed. Requesting again while a deletion is pending keeps the original date.
+func (s *accountDeletionService) RequestDeletion(ctx context.Context, userID uuid.UUID) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "AccountDeletionService.RequestDeletion")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundOr(err, "user not found")
+	}
+	if user.BreakGlass {
+		return nil, errBreakGlassManaged()
+	}
+	if user.DeletionScheduledAt != nil {
+		return user, nil
+	}
+	domain, err := s.domainRepo.GetByID(ctx, user.DomainID)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	if !domain.AccountDeletion.Enabled {
+		return nil, domainerrors.Forbidden("this domain does not allow users to delete their own account").WithCode("account_deletion_disabled")
+	}
+
+	grace := s.config.GracePeriod
+	if domain.AccountDeletion.GraceDays > 0 {
+		grace = time.Duration(domain.AccountDeletion.GraceDays) * 24 * time.Hour
+	}
+	at := time.Now().Add(grace).UTC()
+	if err := s.userRepo.ScheduleDeletion(ctx, user.ID, &at); err != nil {
+		return nil, err
+	}
+	user.DeletionScheduledAt = &at
+	s.events.Publish(ctx, user.DomainID, EventUserDeletionScheduled, user.ID, user)
+
+	s.notify(ctx, user, "Your "+domain.Name+" account will be deleted",
+		fmt.Sprintf("We received a request to delete your %s account %s. It will be deleted permanently on %s.\n\nSign in and cancel the deletion before then to keep your account. If you did not request this, cancel it and change your password.",
+			domain.Name, user.Username, at.Format(time.RFC1123)))
+	return user, nil
+}
+
+// CancelDeletion keeps the user's account by cancelling its pending deletion.
+func (s *accountDeletionService) CancelDeletion(ctx context.Context, userID uuid.UUID) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "AccountDeletionService.CancelDeletion")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundOr(err, "user not found")
+	}
+	if user.DeletionScheduledAt == nil {
+		return nil, domainerrors.Conflict("no account deletion is pending").WithCode("no_deletion_pending")
+	}
+	if err := s.userRepo.ScheduleDeletion(ctx, user.ID, nil); err != nil {
+		return nil, err
+	}
+	user.DeletionScheduledAt = nil
+	s.events.Publish(ctx, user.DomainID, EventUserDeletionCancelled, user.ID, user)
+
+	s.notify(ctx, user, "Your account deletion was cancelled",
+		fmt.Sprintf("The deletion of your account %s has been cancelled and your account stays active.", user.Username))
+	return user, nil
+}
+
+// DeleteScheduledUsers deletes every account whose grace period has ended and returns how many
+// were deleted. A failure on one user doesn't stop the others.
+func (s *accountDeletionService) DeleteScheduledUsers(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "AccountDeletionService.DeleteScheduledUsers")
+	defer span.End()
+
+	users, err := s.userRepo.ListDueForDeletion(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, user := range users {
+		if err := s.users.DeleteUser(ctx, user.ID); err != nil {
+			log.Printf("Failed to delete user %s scheduled for deletion: %v", user.ID, err)
+			continue
+		}
+		s.notify(ctx, user, "Your account has been deleted",
+			fmt.Sprintf("Your account %s has been deleted as you requested.", user.Username))
+		deleted++
+	}
+	return deleted, nil
+}
+
+// RunDeletionSweep calls DeleteScheduledUsers every interval until ctx is cancelled.
+func (s *accountDeletionService) RunDeletionSweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.DeleteScheduledUsers(ctx)
+			if err != nil {
+				log.Printf("Account deletion sweep failed: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Account deletion sweep deleted %d account(s)", deleted)
+			}
+		}
+	}
+}
+
+// notify queues an email to the user, which is sent and retried in the background. Failing to
+// queue it is only logged.
+func (s *accountDeletionService) notify(ctx context.Context, user *entities.User, subject, body string) {
+	if err := s.mailer.Send(ctx, user.DomainID, user.Email, subject, body); err != nil {
+		log.Printf("Failed to queue account deletion notice to %s: %v", user.Email, err)
+	}
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/google/uuid"
+)
+
+// Resources of the admin API that AuthorizeResource looks up by ID.
+const (
+	AdminResourceUser       = "user"
+	AdminResourceRole       = "role"
+	AdminResourceGroup      = "group"
+	AdminResourcePolicy     = "policy"
+	AdminResourcePermission = "permission"
+	AdminResourceAPIKey     = "api_key"
+	AdminResourceOrgUnit    = "org_unit"
+)
+
+// AdminPrincipal is the caller of an admin route: a domain admin, a system admin, an org unit
+// admin, or the platform operator, who acts as a system admin without an account.
+type AdminPrincipal struct {
+	UserID   uuid.UUID // Nil for the operator
+	DomainID uuid.UUID // the domain of the admin's account, from the token claims; Nil for the operator
+	System   bool
+	// OrgUnitID is set for org unit admins: the unit of their account, whose subtree's users they manage
+	OrgUnitID uuid.UUID
+}
+
+// CanManage reports whether the admin may manage the users, roles and settings of a domain. Org
+// unit admins manage none of a domain as a whole.
+func (p *AdminPrincipal) CanManage(domainID uuid.UUID) bool {
+	return p.System || (p.DomainID == domainID && !p.Delegated())
+}
+
+// Delegated reports whether the admin is an org unit admin.
+func (p *AdminPrincipal) Delegated() bool {
+	return p.OrgUnitID != uuid.Nil
+}
+
+type adminPrincipalKey struct{}
+
+// WithAdminPrincipal attaches the caller of an admin route to ctx, and scopes its database
+// statements to the caller's domain, or to every tenant for system admins.
+func WithAdminPrincipal(ctx context.Context, principal *AdminPrincipal) context.Context {
+	if principal.System {
+		ctx = tenancy.AsSystem(ctx)
+	} else {
+		ctx = tenancy.WithDomain(ctx, principal.DomainID)
+	}
+	return context.WithValue(ctx, adminPrincipalKey{}, principal)
+}
+
+// AdminPrincipalFrom returns the caller attached with WithAdminPrincipal, or nil when admin
+// authorization is not enforced and every caller may manage every domain.
+func AdminPrincipalFrom(ctx context.Context) *AdminPrincipal {
+	principal, _ := ctx.Value(adminPrincipalKey{}).(*AdminPrincipal)
+	return principal
+}
+
+// adminCanManage reports whether the caller in ctx, if any, may manage the domain.
+func adminCanManage(ctx context.Context, domainID uuid.UUID) bool {
+	principal := AdminPrincipalFrom(ctx)
+	return principal == nil || principal.CanManage(domainID)
+}
+
+type AdminAuthorizationService interface {
+	ResolveAdmin(ctx context.Context, token, routeScope string) (*AdminPrincipal, error)
+	AuthorizeResource(ctx context.Context, principal *AdminPrincipal, resource string, id uuid.UUID) error
+}
+
+type adminAuthorizationService struct {
+	auth           AuthService
+	userRepo       repositories.UserRepository
+	roleRepo       repositories.RoleRepository
+	groupRepo      repositories.GroupRepository
+	policyRepo     repositories.PolicyRepository
+	permissionRepo repositories.PermissionRepository
+	apiKeyRepo     repositories.APIKeyRepository
+	orgUnitRepo    repositories.OrgUnitRepository
+	systemDomainID uuid.UUID
+}
+
+func NewAdminAuthorizationService(auth AuthService, userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, groupRepo repositories.GroupRepository, policyRepo repositories.PolicyRepository, permissionRepo repositories.PermissionRepository, apiKeyRepo repositories.APIKeyRepository, orgUnitRepo repositories.OrgUnitRepository, systemDomainID uuid.UUID) AdminAuthorizationService {
+	return &adminAuthorizationService{
+		auth:           auth,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		groupRepo:      groupRepo,
+		policyRepo:     policyRepo,
+		permissionRepo: permissionRepo,
+		apiKeyRepo:     apiKeyRepo,
+		orgUnitRepo:    orgUnitRepo,
+		systemDomainID: systemDomainID,
+	}
+}
+
+// ResolveAdmin identifies the admin behind a bearer token. The account needs domain:admin, or
+// system:admin, which only counts in the system domain so a domain admin who can edit their own
+// roles cannot promote themselves to manage other domains. Without either, org_unit:admin makes
+// the account an org unit admin of its own org unit.
+//
+// A token narrowed with a scope only counts the admin permissions among its scopes, so a token
+// narrowed to users:read can't delete roles. On a route guarded by routeScope, such as users:read
+// on the user routes, holding that scope is enough to use the account's admin permissions.
+func (s *adminAuthorizationService) ResolveAdmin(ctx context.Context, token, routeScope string) (*AdminPrincipal, error) {
+	ctx, span := tracer.Start(ctx, "AdminAuthorizationService.ResolveAdmin")
+	defer span.End()
+
+	if token == "" {
+		return nil, domainerrors.Unauthorized("a bearer token or operator token is required")
+	}
+	claims, err := s.auth.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	// A support engineer acting as a user gets the user's access, not admin access
+	if claims.Act != nil {
+		return nil, domainerrors.Forbidden("impersonation tokens cannot use the admin API").WithCode("impersonation_forbidden")
+	}
+	effective, err := s.auth.GetEffectivePermissions(ctx, claims.UserID)
+	if err != nil {
+		return nil, domainerrors.Unauthorized("invalid token")
+	}
+
+	principal := &AdminPrincipal{UserID: claims.UserID, DomainID: claims.DomainID}
+	routeGranted := routeScope != "" && claims.HasScope(routeScope)
+	admin, unitAdmin := false, false
+	for _, permission := range effective.Permissions {
+		if !routeGranted && !claims.HasScope(permission) {
+			continue
+		}
+		switch permission {
+		case entities.PermissionDomainAdmin:
+			admin = true
+		case entities.PermissionSystemAdmin:
+			if s.systemDomainID != uuid.Nil && claims.DomainID == s.systemDomainID {
+				admin = true
+				principal.System = true
+			}
+		case entities.PermissionOrgUnitAdmin:
+			unitAdmin = true
+		}
+	}
+	if admin {
+		return principal, nil
+	}
+	if unitAdmin {
+		user, err := s.userRepo.GetByID(ctx, claims.UserID)
+		if err != nil {
+			return nil, domainerrors.Unauthorized("invalid token")
+		}
+		if user.OrgUnitID == nil {
+			return nil, domainerrors.Forbidden("%s requires an account in an org unit", entities.PermissionOrgUnitAdmin).WithCode("org_unit_required")
+		}
+		principal.OrgUnitID = *user.OrgUnitID
+		return principal, nil
+	}
+	return nil, domainerrors.Forbidden("%s or %s permission required", entities.PermissionDomainAdmin, entities.PermissionSystemAdmin).WithCode("admin_required")
+}
+
+// AuthorizeResource checks the admin may manage the user, role, group, policy, permission, API key
+// or org unit with the given ID. One of another domain is reported not found, like a missing one,
+// so IDs can't be probed across domains.
+func (s *adminAuthorizationService) AuthorizeResource(ctx context.Context, principal *AdminPrincipal, resource string, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "AdminAuthorizationService.AuthorizeResource")
+	defer span.End()
+
+	if principal.System {
+		return nil
+	}
+	if principal.Delegated() {
+		return s.authorizeDelegated(ctx, principal, resource, id)
+	}
+	domainID, name, err := s.domainOf(ctx, resource, id)
+	if err != nil {
+		return notFoundOr(err, name+" not found")
+	}
+	if !principal.CanManage(domainID) {
+		return domainerrors.NotFound("%s not found", name)
+	}
+	return nil
+}
+
+// authorizeDelegated checks an org unit admin may manage the resource: a user of their unit's
+// subtree who isn't a domain or system admin. Users outside the subtree are reported not found.
+func (s *adminAuthorizationService) authorizeDelegated(ctx context.Context, principal *AdminPrincipal, resource string, id uuid.UUID) error {
+	if resource != AdminResourceUser {
+		return errOrgUnitAdminScope()
+	}
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return notFoundOr(err, "user not found")
+	}
+	if !s.inOrgUnit(ctx, principal, user) {
+		return domainerrors.NotFound("user not found")
+	}
+	effective, err := s.auth.GetEffectivePermissions(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, permission := range effective.Permissions {
+		if permission == entities.PermissionDomainAdmin || permission == entities.PermissionSystemAdmin {
+			return domainerrors.Forbidden("org unit admins cannot manage domain or system admins").WithCode("org_unit_forbidden")
+		}
+	}
+	return nil
+}
+
+// inOrgUnit reports whether the user belongs to the org unit admin's subtree.
+func (s *adminAuthorizationService) inOrgUnit(ctx context.Context, principal *AdminPrincipal, user *entities.User) bool {
+	if user.DomainID != principal.DomainID || user.OrgUnitID == nil {
+		return false
+	}
+	found, err := s.orgUnitRepo.InSubtree(ctx, user.DomainID, principal.OrgUnitID, *user.OrgUnitID)
+	return err == nil && found
+}
+
+func errOrgUnitAdminScope() error {
+	return domainerrors.Forbidden("org unit admins can only manage the users of their org unit").WithCode("org_unit_forbidden")
+}
+
+// domainOf returns the domain of a resource, and the name its errors use.
+func (s *adminAuthorizationService) domainOf(ctx context.Context, resource string, id uuid.UUID) (uuid.UUID, string, error) {
+	switch resource {
+	case AdminResourceUser:
+		user, err := s.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return uuid.Nil, "user", err
+		}
+		return user.DomainID, "user", nil
+	case AdminResourceRole:
+		role, err := s.roleRepo.GetByID(ctx, id)
+		if err != nil {
+			return uuid.Nil, "role", err
+		}
+		return role.DomainID, "role", nil
+	case AdminResourceGroup:
+		group, err := s.groupRepo.GetByID(ctx, id)
+		if err != nil {
+			return uuid.Nil, "group", err
+		}
+		return group.DomainID, "group", nil
+	case AdminResourcePolicy:
+		policy, err := s.policyRepo.GetByID(ctx, id)
+		if err != nil {
+			return uuid.Nil, "policy", err
+		}
+		return policy.DomainID, "policy", nil
+	case AdminResourcePermission:
+		permission, err := s.permissionRepo.GetByID(ctx, id)
+		if err != nil {
+			return uuid.Nil, "permission", err
+		}
+		return permission.DomainID, "permission", nil
+	case AdminResourceAPIKey:
+		key, err := s.apiKeyRepo.GetByID(ctx, id)
+		if err != nil {
+			return uuid.Nil, "API key", err
+		}
+		return key.DomainID, "API key", nil
+	case AdminResourceOrgUnit:
+		unit, err := s.orgUnitRepo.GetByID(ctx, id)
+		if err != nil {
+			return uuid.Nil, "org unit", err
+		}
+		return unit.DomainID, "org unit", nil
+	}
+	return uuid.Nil, resource, fmt.Errorf("unknown admin resource %q", resource)
+}
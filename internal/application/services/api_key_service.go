@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/ratelimit"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+const apiKeyPrefix = "nrm_"
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, domainID uuid.UUID, name string, ratePerMinute, dailyQuota *int) (*CreatedAPIKey, error)
+	GetAPIKey(ctx context.Context, id uuid.UUID) (*entities.APIKey, error)
+	ListAPIKeys(ctx context.Context, domainID uuid.UUID) ([]*entities.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
+	GetLimits(ctx context.Context, id uuid.UUID) (*APIKeyLimits, error)
+	SetLimits(ctx context.Context, id uuid.UUID, ratePerMinute, dailyQuota *int) (*APIKeyLimits, error)
+	GetUsage(ctx context.Context, id uuid.UUID) (*APIKeyUsage, error)
+	Authenticate(ctx context.Context, rawKey string) (*entities.APIKey, error)
+	Consume(key *entities.APIKey) (*APIKeyUsage, bool)
+}
+
+// CreatedAPIKey carries the plaintext secret, which is only ever returned at creation.
+type CreatedAPIKey struct {
+	*entities.APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyLimits shows the limits in force for a key and whether they are overrides.
+type APIKeyLimits struct {
+	KeyID              uuid.UUID `json:"key_id"`
+	RateLimitPerMinute int       `json:"rate_limit_per_minute"`
+	DailyQuota         int       `json:"daily_quota"`
+	RateLimitOverride  bool      `json:"rate_limit_override"`
+	DailyQuotaOverride bool      `json:"daily_quota_override"`
+}
+
+type APIKeyUsage struct {
+	KeyID  uuid.UUID       `json:"key_id"`
+	Minute ratelimit.Usage `json:"minute"`
+	Day    ratelimit.Usage `json:"day"`
+}
+
+type apiKeyService struct {
+	repo       repositories.APIKeyRepository
+	domainRepo repositories.DomainRepository
+	limits     *config.RateLimitConfig
+	perMinute  *ratelimit.Counter
+	perDay     *ratelimit.Counter
+}
+
+func NewAPIKeyService(repo repositories.APIKeyRepository, domainRepo repositories.DomainRepository, limits *config.RateLimitConfig) APIKeyService {
+	return &apiKeyService{
+		repo:       repo,
+		domainRepo: domainRepo,
+		limits:     limits,
+		perMinute:  ratelimit.NewCounter(time.Minute),
+		perDay:     ratelimit.NewCounter(24 * time.Hour),
+	}
+}
+
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, domainID uuid.UUID, name string, ratePerMinute, dailyQuota *int) (*CreatedAPIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domainerrors.Validation("name is required")
+	}
+	if err := validateLimits(ratePerMinute, dailyQuota); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	rawKey := apiKeyPrefix + hex.EncodeToString(secret)
+
+	key := &entities.APIKey{
+		DomainID:           domainID,
+		Name:               name,
+		Prefix:             rawKey[:len(apiKeyPrefix)+8],
+		KeyHash:            hashAPIKey(rawKey),
+		RateLimitPerMinute: ratePerMinute,
+		DailyQuota:         dailyQuota,
+	}
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	return &CreatedAPIKey{APIKey: key, Key: rawKey}, nil
+}
+
+func (s *apiKeyService) GetAPIKey(ctx context.Context, id uuid.UUID) (*entities.APIKey, error) {
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domainerrors.NotFound("API key not found")
+	}
+	return key, nil
+}
+
+func (s *apiKeyService) ListAPIKeys(ctx context.Context, domainID uuid.UUID) ([]*entities.APIKey, error) {
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	return s.repo.GetByDomainID(ctx, domainID)
+}
+
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return domainerrors.NotFound("API key not found")
+	}
+	if err := s.repo.Revoke(ctx, id); err != nil {
+		return err
+	}
+	s.perMinute.Reset(id.String())
+	s.perDay.Reset(id.String())
+	return nil
+}
+
+func (s *apiKeyService) GetLimits(ctx context.Context, id uuid.UUID) (*APIKeyLimits, error) {
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domainerrors.NotFound("API key not found")
+	}
+	return s.effectiveLimits(key), nil
+}
+
+// SetLimits replaces both overrides; a nil value falls back to the server default.
+func (s *apiKeyService) SetLimits(ctx context.Context, id uuid.UUID, ratePerMinute, dailyQuota *int) (*APIKeyLimits, error) {
+	if err := validateLimits(ratePerMinute, dailyQuota); err != nil {
+		return nil, err
+	}
+
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domainerrors.NotFound("API key not found")
+	}
+
+	if err := s.repo.UpdateLimits(ctx, id, ratePerMinute, dailyQuota); err != nil {
+		return nil, err
+	}
+	key.RateLimitPerMinute = ratePerMinute
+	key.DailyQuota = dailyQuota
+	return s.effectiveLimits(key), nil
+}
+
+func (s *apiKeyService) GetUsage(ctx context.Context, id uuid.UUID) (*APIKeyUsage, error) {
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domainerrors.NotFound("API key not found")
+	}
+
+	limits := s.effectiveLimits(key)
+	now := time.Now()
+	return &APIKeyUsage{
+		KeyID:  key.ID,
+		Minute: s.perMinute.Peek(key.ID.String(), limits.RateLimitPerMinute, now),
+		Day:    s.perDay.Peek(key.ID.String(), limits.DailyQuota, now),
+	}, nil
+}
+
+// Authenticate resolves a raw key presented by a client; revoked and unknown keys are rejected alike.
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey string) (*entities.APIKey, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, domainerrors.Unauthorized("invalid API key")
+	}
+	key, err := s.repo.GetByHash(ctx, hashAPIKey(rawKey))
+	if err != nil || key.RevokedAt != nil {
+		return nil, domainerrors.Unauthorized("invalid API key")
+	}
+	return key, nil
+}
+
+// Consume records one request against the key's per-minute limit and daily quota.
+func (s *apiKeyService) Consume(key *entities.APIKey) (*APIKeyUsage, bool) {
+	limits := s.effectiveLimits(key)
+	now := time.Now()
+	usage := &APIKeyUsage{KeyID: key.ID}
+
+	var allowed bool
+	usage.Minute, allowed = s.perMinute.Take(key.ID.String(), limits.RateLimitPerMinute, now)
+	if !allowed {
+		usage.Day = s.perDay.Peek(key.ID.String(), limits.DailyQuota, now)
+		return usage, false
+	}
+	usage.Day, allowed = s.perDay.Take(key.ID.String(), limits.DailyQuota, now)
+	return usage, allowed
+}
+
+func (s *apiKeyService) effectiveLimits(key *entities.APIKey) *APIKeyLimits {
+	limits := &APIKeyLimits{
+		KeyID:              key.ID,
+		RateLimitPerMinute: s.limits.DefaultPerMinute,
+		DailyQuota:         s.limits.DefaultDailyQuota,
+	}
+	if key.RateLimitPerMinute != nil {
+		limits.RateLimitPerMinute = *key.RateLimitPerMinute
+		limits.RateLimitOverride = true
+	}
+	if key.DailyQuota != nil {
+		limits.DailyQuota = *key.DailyQuota
+		limits.DailyQuotaOverride = true
+	}
+	return limits
+}
+
+func validateLimits(ratePerMinute, dailyQuota *int) error {
+	if ratePerMinute != nil && *ratePerMinute <= 0 {
+		return domainerrors.Validation("rate limit and quota must be positive")
+	}
+	if dailyQuota != nil && *dailyQuota <= 0 {
+		return domainerrors.Validation("rate limit and quota must be positive")
+	}
+	return nil
+}
+
+func hashAPIKey(rawKey string) string {
+	hash := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(hash[:])
+}
@@ -1,36 +1,114 @@
 package services
 
 import (
-	"crypto/sha256"
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/faults"
+	"backend/internal/infrastructure/metrics"
 	"backend/internal/infrastructure/repositories"
+	"backend/internal/infrastructure/signing"
+	"backend/internal/infrastructure/tenancy"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
 type AuthService interface {
-	Login(domainID uuid.UUID, username, password string) (*LoginResponse, error)
-	ValidateToken(tokenString string) (*TokenClaims, error)
-	GetProfile(userID uuid.UUID) (*UserProfile, error)
+	Login(ctx context.Context, domainID uuid.UUID, username, password, clientIP string, opts LoginOptions) (*LoginResponse, error)
+	StartPasswordlessLogin(ctx context.Context, domainID uuid.UUID, email, clientIP string) error
+	VerifyPasswordlessLogin(ctx context.Context, domainID uuid.UUID, email, code, token, clientIP string) (*LoginResponse, error)
+	ChangeExpiredPassword(ctx context.Context, changeToken, newPassword string) (*LoginResponse, error)
+	ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) error
+	RevokeToken(ctx context.Context, tokenString string) error
+	RunRevocationSweep(ctx context.Context, interval time.Duration)
+	StartSession(ctx context.Context, userID uuid.UUID) (*HostedSession, error)
+	RefreshSession(ctx context.Context, domainID uuid.UUID, sessionToken string) (*LoginResponse, error)
+	EndSession(ctx context.Context, sessionToken string) error
+	ValidateToken(ctx context.Context, tokenString string) (*TokenClaims, error)
+	Impersonate(ctx context.Context, impersonator *TokenClaims, userID uuid.UUID, reason string) (*ImpersonationResponse, error)
+	ClientCredentials(ctx context.Context, clientID, clientSecret, clientIP string, scopes []string) (*ClientCredentialsToken, error)
+	GetProfile(ctx context.Context, userID uuid.UUID) (*UserProfile, error)
+	GetEffectivePermissions(ctx context.Context, userID uuid.UUID) (*EffectivePermissions, error)
+	ResolveDomainID(ctx context.Context, hostname string) (uuid.UUID, error)
+	GetCapabilities(ctx context.Context, domainID uuid.UUID) (*Capabilities, error)
+	SigningKeyID() string
+	SigningAlgorithm() string
+	JWKS() signing.JWKSet
 }
 
 type LoginResponse struct {
-	AccessToken string       `json:"access_token"`
-	User        *UserProfile `json:"user"`
+	AccessToken string          `json:"access_token"`
+	Scope       string          `json:"scope,omitempty"` // the scopes granted, when some were requested
+	User        *UserProfile    `json:"user"`
+	Risk        *RiskAssessment `json:"risk"`
+	// Device is set when the login asked to remember the device
+	Device *RememberedDevice `json:"device,omitempty"`
+}
+
+// LoginOptions are the optional parts of a password login.
+type LoginOptions struct {
+	CaptchaToken   string   // answers a CAPTCHA challenge of an earlier attempt
+	DeviceToken    string   // token of a trusted device, which skips MFA challenges
+	RememberDevice bool     // trust the device and return its token
+	DeviceName     string   // label of a newly remembered device, such as its user agent
+	Scopes         []string // narrow the token to those of the user's permissions
+}
+
+// Login methods and failure reasons reported in LoginAttempt.
+const (
+	loginMethodPassword          = "password"
+	loginMethodPasswordless      = "passwordless"
+	loginMethodClientCredentials = "client_credentials"
+
+	loginFailureUnknownUser     = "unknown_user"
+	loginFailureInvalidPassword = "invalid_password"
+	loginFailureInvalidCode     = "invalid_code"
+	loginFailureInvalidSecret   = "invalid_secret"
+	loginFailureRejected        = "rejected" // valid credentials, but the account may not sign in
+	loginFailureBlocked         = "blocked"  // refused by the risk policy before credentials were checked
+)
+
+// LoginAttempt is the payload of EventLoginSucceeded, EventLoginFailed, EventLoginChallenged and
+// EventLoginCodeSent.
+type LoginAttempt struct {
+	UserID    *uuid.UUID `json:"user_id,omitempty"` // nil when no account matched
+	Username  string     `json:"username"`          // as entered; the email for passwordless logins
+	Method    string     `json:"method"`
+	ClientIP  string     `json:"client_ip"`
+	Reason    string     `json:"reason,omitempty"`    // why a failed attempt was refused
+	Challenge string     `json:"challenge,omitempty"` // captcha or mfa, for a challenged attempt
+	At        time.Time  `json:"at"`
+}
+
+// LoginChallengeError is returned when the login risk score requires the client to pass a
+// CAPTCHA or MFA step-up before credentials are checked.
+type LoginChallengeError struct {
+	Risk *RiskAssessment
+	// Rejected means a CAPTCHA token was sent but the provider didn't accept it
+	Rejected bool
+}
+
+func (e *LoginChallengeError) Error() string {
+	return "challenge required: " + e.Risk.Action
 }
 
 type UserProfile struct {
-	ID        uuid.UUID      `json:"id"`
-	Username  string         `json:"username"`
-	Email     string         `json:"email"`
-	FirstName string         `json:"first_name"`
-	LastName  string         `json:"last_name"`
-	Role      *RoleProfile   `json:"role"`
-	Domain    *DomainProfile `json:"domain"`
+	ID        uuid.UUID       `json:"id"`
+	Username  string          `json:"username"`
+	Email     string          `json:"email"`
+	FirstName string          `json:"first_name"`
+	LastName  string          `json:"last_name"`
+	AvatarURL *string         `json:"avatar_url"`
+	Role      *RoleProfile    `json:"role"`
+	Domain    *DomainProfile  `json:"domain"`
+	Groups    []*GroupProfile `json:"groups"`
 }
 
 type RoleProfile struct {
@@ -40,144 +118,520 @@ type RoleProfile struct {
 	Claims      map[string]interface{} `json:"claims"`
 }
 
+type GroupProfile struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
 type DomainProfile struct {
 	ID          uuid.UUID `json:"id"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 }
 
+type EffectivePermissions struct {
+	UserID           uuid.UUID   `json:"user_id"`
+	DomainID         uuid.UUID   `json:"domain_id"`
+	RoleID           uuid.UUID   `json:"role_id"`
+	InheritedRoleIDs []uuid.UUID `json:"inherited_role_ids"`
+	Permissions      []string    `json:"permissions"`
+}
+
 type TokenClaims struct {
-	UserID   uuid.UUID `json:"user_id"`
-	DomainID uuid.UUID `json:"domain_id"`
-	Username string    `json:"username"`
-	RoleID   uuid.UUID `json:"role_id"`
+	UserID   uuid.UUID   `json:"user_id"`
+	DomainID uuid.UUID   `json:"domain_id"`
+	Username string      `json:"username"`
+	RoleID   uuid.UUID   `json:"role_id"`
+	Groups   []uuid.UUID `json:"groups,omitempty"`
+	// BreakGlass marks a short-lived session of an emergency access account
+	BreakGlass bool `json:"break_glass,omitempty"`
+	// Purpose restricts a token to a single step such as a password change; access tokens leave it empty
+	Purpose string `json:"purpose,omitempty"`
+	// Act names the support engineer acting as the user in an impersonation token (RFC 8693)
+	Act *TokenActor `json:"act,omitempty"`
+	// Scope narrows the token to these space-separated permissions; unscoped tokens leave it empty
+	Scope string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type authService struct {
-	userRepo    repositories.UserRepository
-	roleRepo    repositories.RoleRepository
-	domainRepo  repositories.DomainRepository
-	jwtSecret   []byte
-	tokenExpiry time.Duration
+	userRepo      repositories.UserRepository
+	roleRepo      repositories.RoleRepository
+	domainRepo    repositories.DomainRepository
+	groupRepo     repositories.GroupRepository
+	codeRepo      repositories.LoginCodeRepository
+	revokedTokens repositories.RevokedTokenRepository
+	riskService   LoginRiskService
+	events        EventService
+	mailer        DomainMailer
+	emails        EmailService
+	devices       TrustedDeviceService
+	logins        LoginHistoryService
+	passwordless  *config.PasswordlessConfig
+	breakGlass    *config.BreakGlassConfig
+	impersonation *config.ImpersonationConfig
+	session       *config.HostedSessionConfig
+	passwords     *passwordStore
+	resolver      *permissionResolver
+	keys          *signing.KeySet
+	tokenExpiry   time.Duration
 }
 
-func NewAuthService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, domainRepo repositories.DomainRepository, jwtSecret string) AuthService {
+func NewAuthService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, domainRepo repositories.DomainRepository, permRepo repositories.PermissionRepository, groupRepo repositories.GroupRepository, codeRepo repositories.LoginCodeRepository, historyRepo repositories.PasswordHistoryRepository, revokedTokens repositories.RevokedTokenRepository, riskService LoginRiskService, events EventService, mailer DomainMailer, emails EmailService, devices TrustedDeviceService, logins LoginHistoryService, passwordless *config.PasswordlessConfig, breakGlass *config.BreakGlassConfig, impersonation *config.ImpersonationConfig, session *config.HostedSessionConfig, keys *signing.KeySet) AuthService {
 	return &authService{
-		userRepo:    userRepo,
-		roleRepo:    roleRepo,
-		domainRepo:  domainRepo,
-		jwtSecret:   []byte(jwtSecret),
-		tokenExpiry: 24 * time.Hour, // 24 hours
+		userRepo:      userRepo,
+		roleRepo:      roleRepo,
+		domainRepo:    domainRepo,
+		groupRepo:     groupRepo,
+		codeRepo:      codeRepo,
+		revokedTokens: revokedTokens,
+		riskService:   riskService,
+		events:        events,
+		mailer:        mailer,
+		emails:        emails,
+		devices:       devices,
+		logins:        logins,
+		passwordless:  passwordless,
+		breakGlass:    breakGlass,
+		impersonation: impersonation,
+		session:       session,
+		passwords:     &passwordStore{userRepo: userRepo, historyRepo: historyRepo},
+		resolver:      &permissionResolver{roleRepo: roleRepo, permRepo: permRepo, groupRepo: groupRepo},
+		keys:          keys,
+		tokenExpiry:   24 * time.Hour, // default when the domain sets no access_token_ttl_minutes
 	}
 }
 
-func (s *authService) Login(domainID uuid.UUID, username, password string) (*LoginResponse, error) {
-	// Find user by username
-	user, err := s.userRepo.GetByUsername(username)
+// Login checks the credentials after the risk policy has scored the client.
+func (s *authService) Login(ctx context.Context, domainID uuid.UUID, username, password, clientIP string, opts LoginOptions) (resp *LoginResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Login")
+	// Sign-ins name their domain, whose tenant data is all they reach
+	ctx = tenancy.WithDomain(ctx, domainID)
+	defer span.End()
+	defer func() { metrics.RecordLogin(err == nil) }()
+	defer padFailure(ctx, time.Now(), &err)
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+		return nil, domainerrors.Unauthorized("invalid username or password")
 	}
 
-	// Check if user belongs to the specified domain
-	if user.DomainID != domainID {
-		return nil, fmt.Errorf("invalid credentials")
+	// Find user by username within the domain; usernames are only unique per domain
+	user, userErr := s.userRepo.GetByUsernameAndDomain(ctx, username, domainID)
+	breakGlass := userErr == nil && user.BreakGlass
+
+	// Break-glass accounts keep a local password so they work when other login methods are down
+	if domain.LoginMode == entities.LoginModePasswordless && !breakGlass {
+		return nil, domainerrors.Forbidden("this domain uses passwordless login")
+	}
+
+	// Score the client before touching credentials so risky IPs can't keep guessing. Break-glass
+	// accounts skip CAPTCHA and MFA challenges, whose providers may be the outage, but not blocks.
+	// Devices the user trusts skip MFA challenges.
+	risk, err := s.assessRisk(ctx, domainID, clientIP, opts.CaptchaToken)
+	var challenge *LoginChallengeError
+	if errors.As(err, &challenge) && (breakGlass || (challenge.Risk.Action == RiskActionMFA &&
+		userErr == nil && s.devices.IsTrusted(ctx, user, opts.DeviceToken, clientIP))) {
+		risk, err = challenge.Risk, nil
+	}
+	if err != nil {
+		s.publishRiskOutcome(ctx, domainID, username, loginMethodPassword, clientIP, err)
+		return nil, err
+	}
+
+	if userErr != nil {
+		// Check the password anyway, so unknown usernames take as long to reject as wrong passwords
+		s.verifyPassword(dummyPasswordHash, password)
+		s.riskService.RecordFailure(clientIP)
+		s.publishLogin(ctx, domainID, nil, username, loginMethodPassword, clientIP, loginFailureUnknownUser)
+		return nil, domainerrors.Unauthorized("invalid username or password")
+	}
+	// Service accounts sign in with the client credentials grant only
+	if user.IsService() {
+		s.verifyPassword(dummyPasswordHash, password)
+		s.riskService.RecordFailure(clientIP)
+		s.publishLogin(ctx, domainID, user, username, loginMethodPassword, clientIP, loginFailureRejected)
+		return nil, domainerrors.Unauthorized("invalid username or password")
 	}
 
 	// Verify password
 	if !s.verifyPassword(user.PasswordHash, password) {
-		return nil, fmt.Errorf("invalid credentials")
+		s.riskService.RecordFailure(clientIP)
+		s.publishLogin(ctx, domainID, user, username, loginMethodPassword, clientIP, loginFailureInvalidPassword)
+		if breakGlass {
+			s.alertBreakGlassLogin(ctx, user, clientIP, false)
+		}
+		return nil, domainerrors.Unauthorized("invalid username or password")
 	}
+	s.riskService.RecordSuccess(clientIP)
 
-	// Generate JWT token
-	token, err := s.generateToken(user)
+	// Break-glass passwords are rotated by operators, so they never expire at login
+	if !breakGlass && passwordExpired(domain, user, time.Now()) {
+		return nil, s.requirePasswordChange(user)
+	}
+
+	resp, err = s.issueLogin(ctx, user, risk, opts.Scopes)
+	s.publishLoginResult(ctx, user, username, loginMethodPassword, clientIP, err)
+	if err == nil && breakGlass {
+		s.alertBreakGlassLogin(ctx, user, clientIP, true)
+	}
+	// Break-glass accounts skip challenges anyway, so there is nothing to remember
+	if err == nil && opts.RememberDevice && !breakGlass {
+		// The login stands without it; the device is asked again next time
+		if resp.Device, err = s.devices.Remember(ctx, user, opts.DeviceToken, opts.DeviceName, clientIP); err != nil {
+			log.Printf("Failed to remember the device of user %s: %v", user.ID, err)
+			err = nil
+		}
+	}
+	return resp, err
+}
+
+// publishLogin records a sign-in attempt in the event log and, for a known account, its login
+// history; an empty failure records a success and a nil user an attempt on an unknown account.
+func (s *authService) publishLogin(ctx context.Context, domainID uuid.UUID, user *entities.User, username, method, clientIP, failure string) {
+	attempt := &LoginAttempt{Username: username, Method: method, ClientIP: clientIP, Reason: failure, At: time.Now().UTC()}
+	subjectID := uuid.Nil
+	if user != nil {
+		subjectID = user.ID
+		attempt.UserID = &user.ID
+		s.logins.Record(ctx, user, method, clientIP, failure)
+	}
+	eventType := EventLoginSucceeded
+	if failure != "" {
+		eventType = EventLoginFailed
+	}
+	s.events.Publish(ctx, domainID, eventType, subjectID, attempt)
+}
+
+// publishRiskOutcome records a sign-in attempt the risk policy stopped before credentials were
+// checked: a CAPTCHA or MFA challenge as EventLoginChallenged, and a block as a failed attempt.
+func (s *authService) publishRiskOutcome(ctx context.Context, domainID uuid.UUID, username, method, clientIP string, err error) {
+	var challenge *LoginChallengeError
+	switch {
+	case errors.As(err, &challenge):
+		attempt := &LoginAttempt{Username: username, Method: method, ClientIP: clientIP, Challenge: challenge.Risk.Action, At: time.Now().UTC()}
+		s.events.Publish(ctx, domainID, EventLoginChallenged, uuid.Nil, attempt)
+	case errors.Is(err, domainerrors.ErrForbidden):
+		s.publishLogin(ctx, domainID, nil, username, method, clientIP, loginFailureBlocked)
+	}
+}
+
+// publishLoginResult records the outcome of issueLogin for a user whose credentials were accepted.
+func (s *authService) publishLoginResult(ctx context.Context, user *entities.User, username, method, clientIP string, err error) {
+	failure := ""
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		failure = loginFailureRejected
+	}
+	s.publishLogin(ctx, user.DomainID, user, username, method, clientIP, failure)
+}
+
+// assessRisk scores the client and turns block and challenge outcomes into errors. A CAPTCHA
+// challenge is passed when captchaToken verifies.
+func (s *authService) assessRisk(ctx context.Context, domainID uuid.UUID, clientIP, captchaToken string) (*RiskAssessment, error) {
+	risk, err := s.riskService.Assess(ctx, domainID, clientIP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assess login risk: %w", err)
+	}
+	switch risk.Action {
+	case RiskActionBlock:
+		return nil, domainerrors.Forbidden("login blocked due to high risk")
+	case RiskActionCaptcha:
+		if captchaToken == "" {
+			return nil, &LoginChallengeError{Risk: risk}
+		}
+		passed, err := s.riskService.VerifyCaptcha(ctx, captchaToken, clientIP)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify CAPTCHA: %w", err)
+		}
+		if !passed {
+			return nil, &LoginChallengeError{Risk: risk, Rejected: true}
+		}
+	case RiskActionMFA:
+		return nil, &LoginChallengeError{Risk: risk}
+	}
+	return risk, nil
+}
+
+// issueLogin builds the profile and access token for an authenticated user, narrowed to the
+// requested scopes if any.
+func (s *authService) issueLogin(ctx context.Context, user *entities.User, risk *RiskAssessment, scopes []string) (*LoginResponse, error) {
+	if accountDisabled(user, time.Now()) {
+		return nil, domainerrors.Forbidden("account is disabled")
+	}
+
+	domain, err := s.domainRepo.GetByID(ctx, user.DomainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain: %w", err)
+	}
+	if err := domainAccessError(user, domain); err != nil {
+		return nil, err
 	}
 
-	// Get user profile with role and domain
-	userProfile, err := s.buildUserProfile(user)
+	// Get user profile with role, domain and groups
+	userProfile, err := s.buildUserProfile(ctx, user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build user profile: %w", err)
 	}
 
+	scope, err := s.grantScopes(ctx, user, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate JWT token
+	token, _, err := s.generateToken(ctx, user, domain, userProfile, nil, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
 	return &LoginResponse{
 		AccessToken: token,
+		Scope:       scope,
 		User:        userProfile,
+		Risk:        risk,
 	}, nil
 }
 
-func (s *authService) ValidateToken(tokenString string) (*TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return s.jwtSecret, nil
-	})
+// SigningKeyID is the kid of the token signing key, a fingerprint that lets environments be
+// compared without revealing the key.
+func (s *authService) SigningKeyID() string {
+	return s.keys.Signing.ID
+}
+
+func (s *authService) SigningAlgorithm() string {
+	return s.keys.Signing.Algorithm()
+}
+
+// JWKS returns the public keys tokens can be verified with; it is empty with HS256 signing.
+func (s *authService) JWKS() signing.JWKSet {
+	return s.keys.JWKS()
+}
+
+func (s *authService) ValidateToken(ctx context.Context, tokenString string) (*TokenClaims, error) {
+	if faults.FailTokenValidation() {
+		metrics.RecordTokenValidation(false)
+		return nil, domainerrors.Unauthorized("invalid token").Wrap(faults.ErrInjected)
+	}
+
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		metrics.RecordTokenValidation(false)
+		return nil, err
+	}
+
+	// Single-purpose tokens, such as password change tokens, are not access tokens
+	if claims.Purpose != "" {
+		metrics.RecordTokenValidation(false)
+		return nil, domainerrors.Unauthorized("invalid token claims")
+	}
+	ctx = tenancy.WithDomain(ctx, claims.DomainID)
+	if err := s.checkRevoked(ctx, claims); err != nil {
+		metrics.RecordTokenValidation(false)
+		return nil, err
+	}
+	if err := s.checkSession(ctx, claims); err != nil {
+		metrics.RecordTokenValidation(false)
+		return nil, err
+	}
+	metrics.RecordTokenValidation(true)
+	return claims, nil
+}
 
+// parseToken verifies the signature and lifetime of a token issued by this service.
+func (s *authService) parseToken(tokenString string) (*TokenClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, s.keys.Keyfunc)
 	if err != nil {
-		return nil, fmt.Errorf("invalid token: %w", err)
+		return nil, domainerrors.Unauthorized("invalid token").Wrap(err)
 	}
 
 	if claims, ok := token.Claims.(*TokenClaims); ok && token.Valid {
 		return claims, nil
 	}
+	return nil, domainerrors.Unauthorized("invalid token claims")
+}
 
-	return nil, fmt.Errorf("invalid token claims")
+// checkSession rejects tokens of disabled users and of suspended domains or those being deleted,
+// and tokens issued before the user's sessions were revoked.
+func (s *authService) checkSession(ctx context.Context, claims *TokenClaims) error {
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return domainerrors.Unauthorized("invalid token")
+	}
+	if accountDisabled(user, time.Now()) {
+		return domainerrors.Forbidden("account is disabled")
+	}
+	if domain, err := s.domainRepo.GetByID(ctx, user.DomainID); err == nil {
+		if err := domainAccessError(user, domain); err != nil {
+			return err
+		}
+	}
+	if sessionRevoked(user, claims) {
+		return domainerrors.Unauthorized("token revoked")
+	}
+	return nil
 }
 
-func (s *authService) GetProfile(userID uuid.UUID) (*UserProfile, error) {
-	user, err := s.userRepo.GetByID(userID)
+func (s *authService) GetProfile(ctx context.Context, userID uuid.UUID) (*UserProfile, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.GetProfile")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("user not found")
+		return nil, domainerrors.NotFound("user not found")
 	}
 
-	return s.buildUserProfile(user)
+	return s.buildUserProfile(ctx, user)
 }
 
-func (s *authService) generateToken(user *entities.User) (string, error) {
+// GetEffectivePermissions returns the union of role claims and catalog permissions across the
+// user's direct role and roles inherited through groups.
+func (s *authService) GetEffectivePermissions(ctx context.Context, userID uuid.UUID) (*EffectivePermissions, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.GetEffectivePermissions")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+
+	roles, err := s.resolver.effectiveRoles(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.resolver.grants(ctx, user, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	inherited := make([]uuid.UUID, 0, len(roles)-1)
+	for _, role := range roles[1:] {
+		inherited = append(inherited, role.ID)
+	}
+
+	return &EffectivePermissions{
+		UserID:           user.ID,
+		DomainID:         user.DomainID,
+		RoleID:           user.RoleID,
+		InheritedRoleIDs: inherited,
+		Permissions:      permissions,
+	}, nil
+}
+
+// ResolveDomainID maps a hostname (canonical or alias) to its tenant so clients can log in without knowing the domain UUID.
+func (s *authService) ResolveDomainID(ctx context.Context, hostname string) (uuid.UUID, error) {
+	domain, err := s.domainRepo.GetByHostname(ctx, normalizeHostname(hostname))
+	if err != nil {
+		return uuid.Nil, domainerrors.NotFound("domain not found")
+	}
+	return domain.DomainID, nil
+}
+
+// generateToken issues an access token with the lifetime, audience, claim template and extra
+// claims of the user's domain, and returns when it expires. A non-nil act issues an impersonation
+// token for the support engineer it names; a non-empty scope narrows the token to it.
+func (s *authService) generateToken(ctx context.Context, user *entities.User, domain *entities.Domain, profile *UserProfile, act *TokenActor, scope string) (string, time.Time, error) {
+	template, err := s.templateClaims(ctx, user, domain, profile)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	var groupIDs []uuid.UUID
+	for _, group := range profile.Groups {
+		groupIDs = append(groupIDs, group.ID)
+	}
+
+	// Break-glass sessions expire quickly so emergency access doesn't linger
+	expiry := accessTokenTTL(domain, s.tokenExpiry)
+	if user.BreakGlass {
+		expiry = s.breakGlass.SessionTTL
+	}
+	if act != nil {
+		expiry = min(expiry, s.impersonation.TokenTTL)
+	}
+	now := time.Now()
+	expiresAt := now.Add(expiry)
+
 	claims := TokenClaims{
-		UserID:   user.ID,
-		DomainID: user.DomainID,
-		Username: user.Username,
-		RoleID:   user.RoleID,
+		UserID:     user.ID,
+		DomainID:   user.DomainID,
+		Username:   user.Username,
+		RoleID:     user.RoleID,
+		Groups:     groupIDs,
+		BreakGlass: user.BreakGlass,
+		Act:        act,
+		Scope:      scope,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.tokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(tokenIssueTime(user, now)),
+			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "nusarithm-iam",
 			Subject:   user.ID.String(),
+			Audience:  domain.TokenSettings.Audience,
+			ID:        uuid.NewString(), // jti, for revoking this token alone
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.jwtSecret)
+	token, err := s.keys.Sign(accessTokenClaims{TokenClaims: claims, template: template, extra: domain.TokenSettings.ExtraClaims, namespace: claimNamespace(domain)})
+	return token, expiresAt, err
 }
 
 func (s *authService) verifyPassword(hashedPassword, password string) bool {
-	hash := sha256.Sum256([]byte(password))
-	return fmt.Sprintf("%x", hash) == hashedPassword
+	return passwordMatches(hashedPassword, password)
+}
+
+// minLoginResponseTime is how long failed sign-ins and passwordless starts take at least. The
+// database writes made only for existing accounts, such as their login history, fit well within
+// it, so response times don't tell whether an account exists.
+const minLoginResponseTime = 250 * time.Millisecond
+
+// padLatency waits until minLoginResponseTime has passed since start, or the request is canceled.
+func padLatency(ctx context.Context, start time.Time) {
+	timer := time.NewTimer(time.Until(start.Add(minLoginResponseTime)))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
 }
 
-func (s *authService) buildUserProfile(user *entities.User) (*UserProfile, error) {
+// padFailure pads the response time of a sign-in that failed with *err; meant to be deferred.
+func padFailure(ctx context.Context, start time.Time, err *error) {
+	if *err != nil {
+		padLatency(ctx, start)
+	}
+}
+
+func (s *authService) buildUserProfile(ctx context.Context, user *entities.User) (*UserProfile, error) {
 	// Get role information
-	role, err := s.roleRepo.GetByID(user.RoleID)
+	role, err := s.roleRepo.GetByID(ctx, user.RoleID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get role: %w", err)
 	}
 
 	// Get domain information
-	domain, err := s.domainRepo.GetByID(user.DomainID)
+	domain, err := s.domainRepo.GetByID(ctx, user.DomainID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get domain: %w", err)
 	}
 
+	// Get group membership
+	groups, err := s.groupRepo.GetByUserID(ctx, user.DomainID, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups: %w", err)
+	}
+	groupProfiles := make([]*GroupProfile, 0, len(groups))
+	for _, group := range groups {
+		groupProfiles = append(groupProfiles, &GroupProfile{ID: group.ID, Name: group.Name})
+	}
+
 	return &UserProfile{
 		ID:        user.ID,
 		Username:  user.Username,
 		Email:     user.Email,
 		FirstName: user.FirstName,
 		LastName:  user.LastName,
+		AvatarURL: user.AvatarURL,
 		Role: &RoleProfile{
 			ID:          role.ID,
 			Name:        role.RoleName,
@@ -189,5 +643,6 @@ func (s *authService) buildUserProfile(user *entities.User) (*UserProfile, error
 			Name:        domain.Name,
 			Description: domain.Domain, // Using domain field as description
 		},
+		Groups: groupProfiles,
 	}, nil
 }
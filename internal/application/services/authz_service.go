@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/metrics"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+type AuthzService interface {
+	WhoCan(ctx context.Context, domainID uuid.UUID, resource, action string, page, limit int) (*repositories.UserListResult, error)
+	Check(ctx context.Context, userID uuid.UUID, resource, action string) (*entities.AuthzDecision, error)
+	Simulate(ctx context.Context, roleID uuid.UUID, roleClaims map[string]interface{}, permissions []string, window time.Duration, limit int) (*SimulationResult, error)
+	ListDecisions(ctx context.Context, filter repositories.AuthzDecisionFilter, page, limit int) (*repositories.AuthzDecisionListResult, error)
+	SimulateAccess(ctx context.Context, req *AccessSimulationRequest) (*AccessSimulationResult, error)
+}
+
+type SimulationResult struct {
+	RoleID        uuid.UUID          `json:"role_id"`
+	Since         time.Time          `json:"since"`
+	Evaluated     int                `json:"evaluated"`
+	SampleRate    float64            `json:"sample_rate"` // allowed decisions are replayed from a sample when < 1
+	WouldBeDenied []*SimulatedDenial `json:"would_be_denied"`
+}
+
+// SimulatedDenial groups replayed decisions for the same principal, resource and action.
+type SimulatedDenial struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Resource    string    `json:"resource"`
+	Action      string    `json:"action"`
+	MatchedRule string    `json:"matched_rule"`
+	Occurrences int       `json:"occurrences"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+type authzService struct {
+	userRepo     repositories.UserRepository
+	roleRepo     repositories.RoleRepository
+	domainRepo   repositories.DomainRepository
+	permRepo     repositories.PermissionRepository
+	decisionRepo repositories.AuthzDecisionRepository
+	decisionLog  *config.DecisionLogConfig
+	resolver     *permissionResolver
+	policies     *policyEvaluator
+}
+
+func NewAuthzService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, domainRepo repositories.DomainRepository, permRepo repositories.PermissionRepository, groupRepo repositories.GroupRepository, policyRepo repositories.PolicyRepository, decisionRepo repositories.AuthzDecisionRepository, decisionLog *config.DecisionLogConfig) AuthzService {
+	return &authzService{
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		domainRepo:   domainRepo,
+		permRepo:     permRepo,
+		decisionRepo: decisionRepo,
+		decisionLog:  decisionLog,
+		resolver:     &permissionResolver{roleRepo: roleRepo, permRepo: permRepo, groupRepo: groupRepo},
+		policies:     newPolicyEvaluator(policyRepo, domainRepo, roleRepo, permRepo, groupRepo),
+	}
+}
+
+// WhoCan lists the users in a domain whose direct or group-inherited roles allow action on resource.
+func (s *authzService) WhoCan(ctx context.Context, domainID uuid.UUID, resource, action string, page, limit int) (*repositories.UserListResult, error) {
+	ctx, span := tracer.Start(ctx, "AuthzService.WhoCan")
+	defer span.End()
+
+	resource = strings.TrimSpace(resource)
+	action = strings.TrimSpace(action)
+	if resource == "" || action == "" {
+		return nil, domainerrors.Validation("resource and action are required")
+	}
+
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	// Set default values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	return s.userRepo.ListByRoleClaims(ctx, domainID, grantingClaims(resource, action), page, limit)
+}
+
+// Check evaluates whether the user may perform action on resource and records the decision
+// when the decision log policy selects it.
+func (s *authzService) Check(ctx context.Context, userID uuid.UUID, resource, action string) (*entities.AuthzDecision, error) {
+	ctx, span := tracer.Start(ctx, "AuthzService.Check")
+	defer span.End()
+
+	resource = strings.TrimSpace(resource)
+	action = strings.TrimSpace(action)
+	if resource == "" || action == "" {
+		return nil, domainerrors.Validation("resource and action are required")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+
+	granted, err := s.resolver.grants(ctx, user, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, matchedRule := evaluate(granted, resource, action)
+	decision := &entities.AuthzDecision{
+		DomainID:    user.DomainID,
+		UserID:      user.ID,
+		RoleID:      user.RoleID,
+		Resource:    resource,
+		Action:      action,
+		Allowed:     allowed,
+		MatchedRule: matchedRule,
+	}
+
+	logged := s.decisionLog.ShouldLog(allowed)
+	if logged {
+		// A failed write must not change the outcome of the check itself
+		if err := s.decisionRepo.Create(ctx, decision); err != nil {
+			log.Printf("Failed to record authorization decision: %v", err)
+			logged = false
+		}
+	}
+	if !logged {
+		decision.CreatedAt = time.Now()
+	}
+	metrics.RecordAuthzDecision(allowed, logged)
+	return decision, nil
+}
+
+// Simulate replays recent allowed decisions of the role's holders, direct or through groups,
+// against a hypothetical claim set and reports those that would now be denied. A nil
+// roleClaims or permissions keeps the current value.
+func (s *authzService) Simulate(ctx context.Context, roleID uuid.UUID, roleClaims map[string]interface{}, permissions []string, window time.Duration, limit int) (*SimulationResult, error) {
+	ctx, span := tracer.Start(ctx, "AuthzService.Simulate")
+	defer span.End()
+
+	if roleClaims == nil && permissions == nil {
+		return nil, domainerrors.Validation("provide role_claims and/or permissions to simulate")
+	}
+
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, domainerrors.NotFound("role not found")
+	}
+
+	if roleClaims == nil {
+		roleClaims = role.RoleClaims
+	}
+
+	var assigned []*entities.Permission
+	if permissions == nil {
+		assigned, err = s.permRepo.GetByRoleID(ctx, role.DomainID, role.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get role permissions: %w", err)
+		}
+	} else {
+		for _, name := range permissions {
+			assigned = append(assigned, &entities.Permission{Name: name})
+		}
+	}
+
+	// Set default values
+	if window <= 0 {
+		window = 7 * 24 * time.Hour
+	}
+	if limit <= 0 || limit > 10000 {
+		limit = 1000
+	}
+
+	since := time.Now().Add(-window)
+	decisions, err := s.decisionRepo.ListAllowedByRole(ctx, role.DomainID, role.ID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recorded decisions: %w", err)
+	}
+
+	override := &roleOverride{RoleID: role.ID, Claims: roleClaims, Permissions: assigned}
+	result := &SimulationResult{
+		RoleID:        role.ID,
+		Since:         since,
+		Evaluated:     len(decisions),
+		SampleRate:    s.decisionLog.SampleRate,
+		WouldBeDenied: []*SimulatedDenial{},
+	}
+
+	// Other roles a user holds may still grant access, so evaluate each principal's full set
+	grantsByUser := make(map[uuid.UUID][]string)
+	denials := make(map[string]*SimulatedDenial)
+	for _, decision := range decisions {
+		granted, ok := grantsByUser[decision.UserID]
+		if !ok {
+			user, err := s.userRepo.GetByID(ctx, decision.UserID)
+			if err != nil {
+				// Deleted users can no longer be affected
+				grantsByUser[decision.UserID] = nil
+				continue
+			}
+			granted, err = s.resolver.grants(ctx, user, override)
+			if err != nil {
+				return nil, err
+			}
+			grantsByUser[decision.UserID] = granted
+		}
+		if granted == nil {
+			continue
+		}
+		if allowed, _ := evaluate(granted, decision.Resource, decision.Action); allowed {
+			continue
+		}
+
+		key := decision.UserID.String() + "|" + decision.Resource + "|" + decision.Action
+		denial, ok := denials[key]
+		if !ok {
+			// Decisions arrive newest first, so the first one seen is the latest
+			denial = &SimulatedDenial{
+				UserID:      decision.UserID,
+				Resource:    decision.Resource,
+				Action:      decision.Action,
+				MatchedRule: decision.MatchedRule,
+				LastSeen:    decision.CreatedAt,
+			}
+			denials[key] = denial
+			result.WouldBeDenied = append(result.WouldBeDenied, denial)
+		}
+		denial.Occurrences++
+	}
+
+	return result, nil
+}
+
+// ListDecisions returns logged decisions of a domain, newest first, for audit.
+func (s *authzService) ListDecisions(ctx context.Context, filter repositories.AuthzDecisionFilter, page, limit int) (*repositories.AuthzDecisionListResult, error) {
+	if _, err := s.domainRepo.GetByID(ctx, filter.DomainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	// Set default values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	return s.decisionRepo.ListWithPagination(ctx, filter, page, limit)
+}
+
+// grantingClaims returns every claim key that grants action on resource, most specific first.
+func grantingClaims(resource, action string) []string {
+	return []string{
+		resource + ":" + action,
+		resource + ":*",
+		"*:" + action,
+		"*:*",
+		"*",
+	}
+}
+
+// evaluate reports whether granted permits action on resource and which entry matched.
+func evaluate(granted []string, resource, action string) (bool, string) {
+	set := make(map[string]struct{}, len(granted))
+	for _, name := range granted {
+		set[name] = struct{}{}
+	}
+	for _, claim := range grantingClaims(resource, action) {
+		if _, ok := set[claim]; ok {
+			return true, claim
+		}
+	}
+	return false, ""
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // registers GIF decoding
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/infrastructure/storage"
+
+	"github.com/google/uuid"
+)
+
+// maxAvatarPixels bounds the decoded size of an upload, so a small file can't claim dimensions
+// that exhaust memory when decoded.
+const maxAvatarPixels = 25_000_000
+
+type AvatarService interface {
+	SetAvatar(ctx context.Context, userID uuid.UUID, upload io.Reader) (*entities.User, error)
+}
+
+type avatarService struct {
+	userRepo repositories.UserRepository
+	store    storage.ObjectStorage
+	events   EventService
+	config   *config.AvatarConfig
+}
+
+func NewAvatarService(userRepo repositories.UserRepository, store storage.ObjectStorage, events EventService, cfg *config.AvatarConfig) AvatarService {
+	return &avatarService{userRepo: userRepo, store: store, events: events, config: cfg}
+}
+
+// SetAvatar validates the uploaded image, crops and scales it to the configured size and stores
+// it, replacing the user's previous avatar. Re-encoding also strips metadata such as EXIF
+// locations.
+func (s *avatarService) SetAvatar(ctx context.Context, userID uuid.UUID, upload io.Reader) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "AvatarService.SetAvatar")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundOr(err, "user not found")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(upload, int64(s.config.MaxBytes)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avatar: %w", err)
+	}
+	if len(data) > s.config.MaxBytes {
+		return nil, domainerrors.Validation("avatar must be at most %d bytes", s.config.MaxBytes).WithCode("avatar_too_large")
+	}
+	body, contentType, ext, err := processAvatar(data, s.config.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate avatar key: %w", err)
+	}
+	// A new key per upload, so caches never serve the previous avatar
+	key := fmt.Sprintf("avatars/%s/%s-%s%s", user.DomainID, user.ID, hex.EncodeToString(suffix), ext)
+	if err := s.store.Put(ctx, key, body, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store avatar: %w", err)
+	}
+
+	previous := user.AvatarKey
+	avatarURL := s.store.URL(key)
+	user.AvatarKey, user.AvatarURL = &key, &avatarURL
+	if err := s.userRepo.UpdateAvatar(ctx, user); err != nil {
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete unused avatar %s: %v", key, err)
+		}
+		return nil, err
+	}
+	// The new avatar is in place either way; a leftover object only takes space
+	if previous != nil {
+		if err := s.store.Delete(ctx, *previous); err != nil {
+			log.Printf("Failed to delete the previous avatar of user %s: %v", user.ID, err)
+		}
+	}
+	s.events.Publish(ctx, user.DomainID, EventUserUpdated, user.ID, user)
+	return user, nil
+}
+
+// processAvatar decodes a JPEG, PNG or GIF upload and returns it as a square thumbnail: JPEG for
+// JPEG uploads, PNG otherwise to keep transparency.
+func processAvatar(data []byte, size int) (body []byte, contentType, ext string, err error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", "", domainerrors.Validation("avatar must be a JPEG, PNG or GIF image").WithCode("invalid_avatar")
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxAvatarPixels {
+		return nil, "", "", domainerrors.Validation("avatar must be at most %d pixels", maxAvatarPixels).WithCode("invalid_avatar")
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", "", domainerrors.Validation("avatar image is corrupt").WithCode("invalid_avatar")
+	}
+
+	thumbnail := squareThumbnail(img, size)
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		if err := jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/jpeg", ".jpg", nil
+	}
+	if err := png.Encode(&buf, thumbnail); err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), "image/png", ".png", nil
+}
+
+// squareThumbnail crops the centred square of img and scales it down to at most size pixels a
+// side, averaging the source pixels that fall into each target pixel. Smaller images aren't
+// scaled up.
+func squareThumbnail(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	left := bounds.Min.X + (bounds.Dx()-side)/2
+	top := bounds.Min.Y + (bounds.Dy()-side)/2
+	out := min(side, size)
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, out, out))
+	for y := 0; y < out; y++ {
+		y0, y1 := top+y*side/out, top+(y+1)*side/out
+		for x := 0; x < out; x++ {
+			x0, x1 := left+x*side/out, left+(x+1)*side/out
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			thumbnail.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return thumbnail
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// MaxBatchGetIDs bounds the IDs of one batch lookup.
+const MaxBatchGetIDs = 100
+
+// UserBatch holds the users found by a batch lookup in the order their IDs were requested, and
+// the requested IDs without a user.
+type UserBatch struct {
+	Users   []*entities.User `json:"users"`
+	Missing []uuid.UUID      `json:"missing" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+}
+
+// RoleBatch holds the roles found by a batch lookup in the order their IDs were requested, and
+// the requested IDs without a role.
+type RoleBatch struct {
+	Roles   []*entities.Role `json:"roles"`
+	Missing []uuid.UUID      `json:"missing" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+}
+
+// GetUsersByIDs returns up to MaxBatchGetIDs users, fetched with one query per database. An ID
+// repeated in the request is only answered once; users of domains a domain admin doesn't manage are
+// reported missing.
+func (s *userService) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) (*UserBatch, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUsersByIDs")
+	defer span.End()
+
+	ids, err := uniqueBatchIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*entities.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+	batch := &UserBatch{Users: []*entities.User{}, Missing: []uuid.UUID{}}
+	for _, id := range ids {
+		if user, ok := byID[id]; ok && adminCanManage(ctx, user.DomainID) {
+			batch.Users = append(batch.Users, user)
+		} else {
+			batch.Missing = append(batch.Missing, id)
+		}
+	}
+	return batch, nil
+}
+
+// GetRolesByIDs returns up to MaxBatchGetIDs roles, fetched with one query per database. An ID
+// repeated in the request is only answered once; roles of domains a domain admin doesn't manage are
+// reported missing.
+func (s *roleService) GetRolesByIDs(ctx context.Context, ids []uuid.UUID) (*RoleBatch, error) {
+	ctx, span := tracer.Start(ctx, "RoleService.GetRolesByIDs")
+	defer span.End()
+
+	ids, err := uniqueBatchIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*entities.Role, len(roles))
+	for _, role := range roles {
+		byID[role.ID] = role
+	}
+	batch := &RoleBatch{Roles: []*entities.Role{}, Missing: []uuid.UUID{}}
+	for _, id := range ids {
+		if role, ok := byID[id]; ok && adminCanManage(ctx, role.DomainID) {
+			batch.Roles = append(batch.Roles, role)
+		} else {
+			batch.Missing = append(batch.Missing, id)
+		}
+	}
+	return batch, nil
+}
+
+// uniqueBatchIDs checks the size of a batch lookup and drops repeated IDs, keeping the first
+// occurrence of each.
+func uniqueBatchIDs(ids []uuid.UUID) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, domainerrors.Validation("ids must not be empty")
+	}
+	if len(ids) > MaxBatchGetIDs {
+		return nil, domainerrors.Validation("at most %d ids are allowed", MaxBatchGetIDs).WithCode("batch_too_large")
+	}
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique, nil
+}
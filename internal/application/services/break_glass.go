@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// Break-glass passwords guard accounts that bypass login challenges, so they must be long.
+const breakGlassMinPasswordLength = 16
+
+// BreakGlassLogin is the payload of EventBreakGlassLogin.
+type BreakGlassLogin struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	ClientIP  string    `json:"client_ip"`
+	Succeeded bool      `json:"succeeded"`
+	At        time.Time `json:"at"`
+}
+
+func errBreakGlassManaged() error {
+	return domainerrors.Forbidden("break-glass accounts are managed by platform operators").WithCode("break_glass_account")
+}
+
+// ListBreakGlassAccounts returns the break-glass accounts of every domain.
+func (s *userService) ListBreakGlassAccounts(ctx context.Context) ([]*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.ListBreakGlassAccounts")
+	defer span.End()
+
+	return s.repo.ListBreakGlass(ctx)
+}
+
+// CreateBreakGlassAccount creates an emergency access account. It always gets a local password,
+// even in passwordless domains, and never an end date or external ID.
+func (s *userService) CreateBreakGlassAccount(ctx context.Context, domainID, roleID uuid.UUID, firstName, lastName, username, email, password string) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.CreateBreakGlassAccount")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil || role.DomainID != domainID {
+		return nil, domainerrors.Validation("role does not belong to this domain")
+	}
+	if len(password) < breakGlassMinPasswordLength {
+		return nil, errBreakGlassPasswordLength()
+	}
+	if err := checkPasswordPolicy(domain, password); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUnique(ctx, domainID, username, email, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	user := &entities.User{
+		DomainID:     domainID,
+		RoleID:       roleID,
+		FirstName:    firstName,
+		LastName:     lastName,
+		Username:     username,
+		Email:        email,
+		PasswordHash: s.hashPassword(password),
+		BreakGlass:   true,
+	}
+	if err := s.repo.Create(ctx, user); err != nil {
+		return nil, conflictFromDB(err)
+	}
+	s.events.Publish(ctx, domainID, EventUserCreated, user.ID, user)
+	return user, nil
+}
+
+// RotateBreakGlassPassword replaces the password of a break-glass account, typically after it
+// has been used.
+func (s *userService) RotateBreakGlassPassword(ctx context.Context, id uuid.UUID, password string) error {
+	ctx, span := tracer.Start(ctx, "UserService.RotateBreakGlassPassword")
+	defer span.End()
+
+	user, err := s.getBreakGlass(ctx, id)
+	if err != nil {
+		return err
+	}
+	domain, err := s.domainRepo.GetByID(ctx, user.DomainID)
+	if err != nil {
+		return domainerrors.NotFound("domain not found")
+	}
+	if len(password) < breakGlassMinPasswordLength {
+		return errBreakGlassPasswordLength()
+	}
+	if err := s.passwords.set(ctx, domain, user, password); err != nil {
+		return err
+	}
+	s.events.Publish(ctx, user.DomainID, EventUserUpdated, user.ID, user)
+	return nil
+}
+
+func (s *userService) DeleteBreakGlassAccount(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "UserService.DeleteBreakGlassAccount")
+	defer span.End()
+
+	user, err := s.getBreakGlass(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+	s.events.Publish(ctx, user.DomainID, EventUserDeleted, user.ID, user)
+	return nil
+}
+
+func errBreakGlassPasswordLength() error {
+	return domainerrors.Validation("break-glass password must be at least %d characters", breakGlassMinPasswordLength)
+}
+
+func (s *userService) getBreakGlass(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil || !user.BreakGlass {
+		return nil, domainerrors.NotFound("break-glass account not found")
+	}
+	return user, nil
+}
+
+// alertBreakGlassLogin records a sign-in attempt on a break-glass account in the event log and
+// emails the account and the configured alert addresses. Emails are sent after the request has
+// been answered, so failures are only logged.
+func (s *authService) alertBreakGlassLogin(ctx context.Context, user *entities.User, clientIP string, succeeded bool) {
+	attempt := &BreakGlassLogin{
+		UserID:    user.ID,
+		Username:  user.Username,
+		ClientIP:  clientIP,
+		Succeeded: succeeded,
+		At:        time.Now().UTC(),
+	}
+	log.Printf("ALERT: break-glass account %s (%s) sign-in from %s, succeeded=%t", user.Username, user.ID, clientIP, succeeded)
+	s.events.Publish(ctx, user.DomainID, EventBreakGlassLogin, user.ID, attempt)
+
+	outcome := "signed in"
+	if !succeeded {
+		outcome = "failed to sign in"
+	}
+	subject := fmt.Sprintf("ALERT: break-glass account %s used", user.Username)
+	body := fmt.Sprintf("The break-glass account %s (%s) %s from %s at %s.\n\nIf this was not an approved emergency, rotate its password and review the event log immediately.",
+		user.Username, user.ID, outcome, clientIP, attempt.At.Format(time.RFC3339))
+	recipients := append([]string{user.Email}, s.breakGlass.AlertEmails...)
+
+	go func(ctx context.Context) {
+		for _, to := range recipients {
+			if err := s.mailer.Send(ctx, user.DomainID, to, subject, body); err != nil {
+				log.Printf("Failed to send break-glass alert to %s: %v", to, err)
+			}
+		}
+	}(context.WithoutCancel(ctx))
+}
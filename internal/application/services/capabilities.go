@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// Capabilities describes what a domain's login offers so client apps can adapt their UI. Features
+// this server does not implement are reported as disabled rather than left out, so the shape is
+// stable for clients.
+type Capabilities struct {
+	DomainID     uuid.UUID              `json:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	DomainName   string                 `json:"domain_name" example:"Acme Corp"`
+	LoginMode    string                 `json:"login_mode" enums:"password,passwordless" example:"password"`
+	Password     PasswordCapability     `json:"password"`
+	Passwordless PasswordlessCapability `json:"passwordless"`
+	MFA          MFACapability          `json:"mfa"`
+	Challenges   []string               `json:"challenges" enums:"captcha,mfa" example:"captcha"` // risk challenges the login may answer with
+	// Captcha is the widget whose token answers a CAPTCHA challenge; nil when none is configured
+	Captcha      *CaptchaWidget         `json:"captcha"`
+	Registration RegistrationCapability `json:"registration"`
+	Federation   FederationCapability   `json:"federation"`
+	SCIM         SCIMCapability         `json:"scim"`
+}
+
+type PasswordCapability struct {
+	Enabled bool `json:"enabled" example:"true"`
+	// Policy is the effective policy new passwords must meet; nil when passwords are disabled
+	Policy *entities.PasswordPolicy `json:"policy"`
+}
+
+type PasswordlessCapability struct {
+	Enabled bool     `json:"enabled" example:"false"`
+	Methods []string `json:"methods" enums:"email_code,magic_link" example:"email_code"`
+}
+
+type RegistrationCapability struct {
+	// Open means anyone may sign up through /auth/register; otherwise a registration code is needed
+	Open bool `json:"open" example:"false"`
+}
+
+type MFACapability struct {
+	// StepUp means risky logins may be challenged for MFA by the domain's risk policy
+	StepUp  bool     `json:"step_up" example:"false"`
+	Methods []string `json:"methods"`
+}
+
+type FederationCapability struct {
+	Protocols []string `json:"protocols"`
+}
+
+type SCIMCapability struct {
+	Enabled bool `json:"enabled" example:"false"`
+}
+
+// GetCapabilities reports the login features enabled for the domain.
+func (s *authService) GetCapabilities(ctx context.Context, domainID uuid.UUID) (*Capabilities, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.GetCapabilities")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	riskPolicy, err := s.riskService.GetPolicy(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities := &Capabilities{
+		DomainID:     domain.DomainID,
+		DomainName:   domain.Name,
+		LoginMode:    domain.LoginMode,
+		Passwordless: PasswordlessCapability{Methods: []string{}},
+		MFA:          MFACapability{StepUp: riskPolicy.MFAThreshold != nil, Methods: []string{}},
+		Challenges:   []string{},
+		Registration: RegistrationCapability{Open: domain.Registration.Open},
+		Federation:   FederationCapability{Protocols: []string{}},
+	}
+
+	if domain.LoginMode == entities.LoginModePasswordless {
+		capabilities.Passwordless = PasswordlessCapability{Enabled: true, Methods: []string{"email_code", "magic_link"}}
+	} else {
+		capabilities.Password = PasswordCapability{Enabled: true, Policy: effectivePasswordPolicy(domain)}
+	}
+
+	if riskPolicy.CaptchaThreshold != nil || riskPolicy.CaptchaAfterFailures != nil {
+		capabilities.Challenges = append(capabilities.Challenges, RiskActionCaptcha)
+		capabilities.Captcha = s.riskService.CaptchaWidget()
+	}
+	if riskPolicy.MFAThreshold != nil {
+		capabilities.Challenges = append(capabilities.Challenges, RiskActionMFA)
+	}
+	return capabilities, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+)
+
+// maxTemplateClaimsBytes bounds the claims a template adds, keeping tokens small enough for
+// headers and cookies.
+const maxTemplateClaimsBytes = 4096
+
+// claimsOverage is set when template claims were left out for size; consumers then read them
+// from /auth/profile and /auth/permissions.
+const claimsOverage = "claims_overage"
+
+// templateUserAttributes maps the user attributes a template may embed to their values.
+var templateUserAttributes = map[string]func(*entities.User) interface{}{
+	"email":      func(user *entities.User) interface{} { return user.Email },
+	"first_name": func(user *entities.User) interface{} { return user.FirstName },
+	"last_name":  func(user *entities.User) interface{} { return user.LastName },
+	"external_id": func(user *entities.User) interface{} {
+		if user.ExternalID == nil {
+			return nil
+		}
+		return *user.ExternalID
+	},
+}
+
+func validateClaimTemplate(template *entities.TokenClaimTemplate) error {
+	seen := make(map[string]bool, len(template.UserAttributes))
+	for _, attribute := range template.UserAttributes {
+		if _, ok := templateUserAttributes[attribute]; !ok {
+			return domainerrors.Validation("unknown user attribute %q; use email, first_name, last_name or external_id", attribute)
+		}
+		if seen[attribute] {
+			return domainerrors.Validation("user attribute %q is listed twice", attribute)
+		}
+		seen[attribute] = true
+	}
+	return nil
+}
+
+// templateClaims builds the claims the domain's template embeds in the user's access token. The
+// largest optional claims are dropped, permissions first, while they exceed maxTemplateClaimsBytes.
+func (s *authService) templateClaims(ctx context.Context, user *entities.User, domain *entities.Domain, profile *UserProfile) (map[string]interface{}, error) {
+	template := domain.TokenSettings.ClaimTemplate
+	claims := make(map[string]interface{})
+	for _, attribute := range template.UserAttributes {
+		if value, ok := templateUserAttributes[attribute]; ok {
+			claims[attribute] = value(user)
+		}
+	}
+	if template.GroupNames {
+		names := make([]string, 0, len(profile.Groups))
+		for _, group := range profile.Groups {
+			names = append(names, group.Name)
+		}
+		claims["group_names"] = names
+	}
+	if template.RoleClaims && profile.Role != nil {
+		claims["role_claims"] = profile.Role.Claims
+	}
+	if template.Permissions {
+		permissions, err := s.resolver.grants(ctx, user, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve permissions: %w", err)
+		}
+		claims["permissions"] = permissions
+	}
+
+	for _, name := range []string{"permissions", "role_claims", "group_names"} {
+		encoded, err := json.Marshal(claims)
+		if err != nil {
+			return nil, err
+		}
+		if len(encoded) <= maxTemplateClaimsBytes {
+			break
+		}
+		if _, ok := claims[name]; ok {
+			delete(claims, name)
+			claims[claimsOverage] = true
+		}
+	}
+	return claims, nil
+}
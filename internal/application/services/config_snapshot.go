@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+)
+
+// ConfigSnapshot describes how this instance is configured, for comparing environments during
+// an incident. It never contains secrets; keys are identified by fingerprint only.
+type ConfigSnapshot struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Build       BuildInfo        `json:"build"`
+	Config      *config.Snapshot `json:"config"`
+	Features    map[string]bool  `json:"features"`
+	Keys        []SigningKeyInfo `json:"keys"`
+	Migrations  MigrationStatus  `json:"migrations"`
+}
+
+type BuildInfo struct {
+	GoVersion string `json:"go_version" example:"go1.24.0"`
+	Revision  string `json:"revision,omitempty" example:"6c0f412"` // VCS revision when built from a checkout
+	Modified  bool   `json:"modified,omitempty" example:"false"`
+}
+
+type SigningKeyInfo struct {
+	KeyID     string `json:"key_id" example:"9f86d081884c7d65"`
+	Algorithm string `json:"algorithm" enums:"HS256,RS256,ES256" example:"RS256"`
+	Use       string `json:"use" example:"access_token"`
+}
+
+type MigrationStatus struct {
+	// Latest is the newest migration file shipped with this build
+	Latest string `json:"latest" example:"026_create_invitations_table.sql"`
+	// Applied is the newest migration recorded in each database by residency; empty when the
+	// database was migrated without run_migrations.sh
+	Applied map[string]string `json:"applied"`
+}
+
+type ConfigSnapshotService interface {
+	Snapshot(ctx context.Context) (*ConfigSnapshot, error)
+}
+
+type configSnapshotService struct {
+	schemaRepo repositories.SchemaRepository
+	auth       AuthService
+	config     *config.AppConfig
+}
+
+func NewConfigSnapshotService(schemaRepo repositories.SchemaRepository, auth AuthService, cfg *config.AppConfig) ConfigSnapshotService {
+	return &configSnapshotService{schemaRepo: schemaRepo, auth: auth, config: cfg}
+}
+
+func (s *configSnapshotService) Snapshot(ctx context.Context) (*ConfigSnapshot, error) {
+	ctx, span := tracer.Start(ctx, "ConfigSnapshotService.Snapshot")
+	defer span.End()
+
+	cfg := s.config.Snapshot()
+	applied, err := s.schemaRepo.AppliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfigSnapshot{
+		GeneratedAt: time.Now().UTC(),
+		Build:       buildInfo(),
+		Config:      cfg,
+		Features: map[string]bool{
+			"residency_shards":       len(cfg.Database.Shards) > 0,
+			"read_replicas":          len(cfg.Database.Replicas) > 0,
+			"row_level_security":     cfg.Database.RowLevelSecurity,
+			"smtp":                   cfg.Mail.SMTPHost != "",
+			"tracing":                cfg.Tracing.Enabled,
+			"ip_reputation_feed":     cfg.LoginRisk.ReputationFeed,
+			"decision_log":           cfg.DecisionLog.Enabled,
+			"user_expiry_sweep":      s.config.UserExpiry.SweepInterval > 0,
+			"account_deletion_sweep": s.config.AccountDeletion.SweepInterval > 0,
+			"integration_health":     s.config.IntegrationHealth.CheckInterval > 0,
+			"health_probes":          s.config.Health.ProbeInterval > 0,
+			"webhook_delivery":       s.config.Webhooks.DeliveryInterval > 0,
+			"event_broker":           s.config.Broker.Enabled(),
+			"login_telemetry_export": s.config.Telemetry.Enabled() && s.config.Telemetry.ExportInterval > 0,
+			"operator_api":           cfg.Operator.TokenConfigured,
+			"shared_rate_limits":     cfg.RequestRateLimit.Store == "redis",
+			"shared_cache":           cfg.Cache.Store == "redis",
+			"shared_revocations":     cfg.TokenRevocation.Store == "redis",
+			"asymmetric_signing":     cfg.JWT.KeySource != "secret",
+			"fault_injection":        cfg.FaultInjection.Enabled,
+		},
+		Keys:       []SigningKeyInfo{{KeyID: s.auth.SigningKeyID(), Algorithm: s.auth.SigningAlgorithm(), Use: "access_token"}},
+		Migrations: MigrationStatus{Latest: config.LatestMigration(cfg.MigrationsDir), Applied: applied},
+	}, nil
+}
+
+func buildInfo() BuildInfo {
+	info := BuildInfo{}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = build.GoVersion
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
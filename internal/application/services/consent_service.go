@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ConsentService manages the profile fields users share with client apps and answers
+// /oauth/userinfo with only those fields. Client apps are identified by the domain's API keys.
+type ConsentService interface {
+	ListConsents(ctx context.Context, userID uuid.UUID) ([]*entities.ProfileConsent, error)
+	GrantConsent(ctx context.Context, userID, clientID uuid.UUID, fields []string) (*entities.ProfileConsent, error)
+	RevokeConsent(ctx context.Context, userID, clientID uuid.UUID) error
+	UserInfo(ctx context.Context, userID uuid.UUID, client *entities.APIKey) (map[string]interface{}, error)
+}
+
+type consentService struct {
+	repo       repositories.ProfileConsentRepository
+	userRepo   repositories.UserRepository
+	apiKeyRepo repositories.APIKeyRepository
+	auth       AuthService
+}
+
+func NewConsentService(repo repositories.ProfileConsentRepository, userRepo repositories.UserRepository, apiKeyRepo repositories.APIKeyRepository, auth AuthService) ConsentService {
+	return &consentService{repo: repo, userRepo: userRepo, apiKeyRepo: apiKeyRepo, auth: auth}
+}
+
+func (s *consentService) ListConsents(ctx context.Context, userID uuid.UUID) ([]*entities.ProfileConsent, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.ListConsents")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+	consents, err := s.repo.ListByUser(ctx, user.DomainID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, consent := range consents {
+		// Consents outlive revoked keys until the user removes them; those keep an empty name
+		if client, err := s.apiKeyRepo.GetByID(ctx, consent.ClientID); err == nil {
+			consent.ClientName = client.Name
+		}
+	}
+	return consents, nil
+}
+
+// GrantConsent replaces the set of fields the user shares with the client.
+func (s *consentService) GrantConsent(ctx context.Context, userID, clientID uuid.UUID, fields []string) (*entities.ProfileConsent, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.GrantConsent")
+	defer span.End()
+
+	granted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !slices.Contains(entities.ProfileFields, field) {
+			return nil, domainerrors.Validation("unknown profile field %q", field)
+		}
+		if !slices.Contains(granted, field) {
+			granted = append(granted, field)
+		}
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+	client, err := s.clientFor(ctx, user, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	consent := &entities.ProfileConsent{
+		DomainID:   user.DomainID,
+		UserID:     user.ID,
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		Fields:     granted,
+	}
+	if err := s.repo.Upsert(ctx, consent); err != nil {
+		return nil, err
+	}
+	return consent, nil
+}
+
+func (s *consentService) RevokeConsent(ctx context.Context, userID, clientID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "ConsentService.RevokeConsent")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domainerrors.NotFound("user not found")
+	}
+	return notFoundOr(s.repo.Delete(ctx, user.DomainID, user.ID, clientID), "consent not found")
+}
+
+// UserInfo returns the user's ID as sub plus the profile fields the user consented to share with
+// the client. Without a consent only sub is returned.
+func (s *consentService) UserInfo(ctx context.Context, userID uuid.UUID, client *entities.APIKey) (map[string]interface{}, error) {
+	ctx, span := tracer.Start(ctx, "ConsentService.UserInfo")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+	if client.DomainID != user.DomainID {
+		return nil, domainerrors.Forbidden("client belongs to another domain")
+	}
+
+	var fields []string
+	consent, err := s.repo.Get(ctx, user.DomainID, user.ID, client.ID)
+	switch {
+	case err == nil:
+		fields = consent.Fields
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	info := map[string]interface{}{"sub": user.ID}
+	if len(fields) == 0 {
+		return info, nil
+	}
+
+	profile, err := s.auth.GetProfile(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{
+		entities.ProfileFieldUsername:  profile.Username,
+		entities.ProfileFieldEmail:     profile.Email,
+		entities.ProfileFieldFirstName: profile.FirstName,
+		entities.ProfileFieldLastName:  profile.LastName,
+		entities.ProfileFieldRole:      profile.Role,
+		entities.ProfileFieldGroups:    profile.Groups,
+	}
+	for _, field := range fields {
+		info[field] = values[field]
+	}
+	return info, nil
+}
+
+// clientFor looks up an active API key of the user's domain.
+func (s *consentService) clientFor(ctx context.Context, user *entities.User, clientID uuid.UUID) (*entities.APIKey, error) {
+	client, err := s.apiKeyRepo.GetByID(ctx, clientID)
+	if err != nil || client.RevokedAt != nil || client.DomainID != user.DomainID {
+		return nil, domainerrors.NotFound("client not found")
+	}
+	return client, nil
+}
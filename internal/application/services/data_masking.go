@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// maxAuditedUserIDs bounds the user IDs recorded for one unmasked view; exports record the count.
+const maxAuditedUserIDs = 100
+
+// maskedUserFields masks each field that can be hidden from viewers without pii:read.
+var maskedUserFields = map[string]func(*entities.User){
+	"email": func(user *entities.User) {
+		local, host, found := strings.Cut(user.Email, "@")
+		if !found {
+			user.Email = maskText(user.Email)
+			return
+		}
+		user.Email = maskText(local) + "@" + host
+	},
+	"first_name": func(user *entities.User) { user.FirstName = maskText(user.FirstName) },
+	"last_name":  func(user *entities.User) { user.LastName = maskText(user.LastName) },
+	"external_id": func(user *entities.User) {
+		if user.ExternalID != nil {
+			masked := maskText(*user.ExternalID)
+			user.ExternalID = &masked
+		}
+	},
+}
+
+// maskText keeps the first character so admins can still tell values apart.
+func maskText(value string) string {
+	if value == "" {
+		return ""
+	}
+	first := []rune(value)[0]
+	return string(first) + "***"
+}
+
+// PIIView is the audit record of an admin seeing unmasked user fields.
+type PIIView struct {
+	ViewerID uuid.UUID   `json:"viewer_id"`
+	View     string      `json:"view"`
+	Fields   []string    `json:"fields"`
+	Count    int         `json:"count"`
+	UserIDs  []uuid.UUID `json:"user_ids"` // the first 100 users seen
+}
+
+// PIIViewer is the admin a response is prepared for, resolved from the request's bearer token.
+type PIIViewer struct {
+	UserID   uuid.UUID
+	DomainID uuid.UUID
+	ReadPII  bool // holds pii:read in DomainID
+}
+
+type DataMaskingService interface {
+	ResolveViewer(ctx context.Context, token string) *PIIViewer
+	NewMasker(viewer *PIIViewer, view string) *UserMasker
+}
+
+type dataMaskingService struct {
+	domainRepo repositories.DomainRepository
+	auth       AuthService
+	events     EventService
+}
+
+func NewDataMaskingService(domainRepo repositories.DomainRepository, auth AuthService, events EventService) DataMaskingService {
+	return &dataMaskingService{domainRepo: domainRepo, auth: auth, events: events}
+}
+
+// ResolveViewer identifies the admin behind a bearer token. A missing or invalid token gives a nil
+// viewer, who sees every masked field masked.
+func (s *dataMaskingService) ResolveViewer(ctx context.Context, token string) *PIIViewer {
+	if token == "" {
+		return nil
+	}
+	claims, err := s.auth.ValidateToken(ctx, token)
+	if err != nil {
+		return nil
+	}
+	viewer := &PIIViewer{UserID: claims.UserID, DomainID: claims.DomainID}
+	// Impersonation tokens don't carry admin access, so their viewer sees masked fields masked
+	if claims.Act != nil {
+		return viewer
+	}
+	if effective, err := s.auth.GetEffectivePermissions(ctx, claims.UserID); err == nil {
+		for _, permission := range effective.Permissions {
+			if permission == entities.PermissionReadPII {
+				viewer.ReadPII = true
+				break
+			}
+		}
+	}
+	return viewer
+}
+
+// NewMasker prepares the users of one response, named by view in the audit log.
+func (s *dataMaskingService) NewMasker(viewer *PIIViewer, view string) *UserMasker {
+	return &UserMasker{service: s, viewer: viewer, view: view, domains: make(map[uuid.UUID]*entities.DataMaskingSettings), seen: make(map[uuid.UUID]*PIIView)}
+}
+
+// GetDataMasking returns the user fields the domain masks.
+func (s *domainService) GetDataMasking(ctx context.Context, domainID uuid.UUID) (*entities.DataMaskingSettings, error) {
+	domain, err := s.repo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	return &domain.DataMasking, nil
+}
+
+// UpdateDataMasking replaces the user fields the domain masks.
+func (s *domainService) UpdateDataMasking(ctx context.Context, domainID uuid.UUID, settings *entities.DataMaskingSettings) (*entities.DataMaskingSettings, error) {
+	domain, err := s.repo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	if err := validateDataMasking(settings); err != nil {
+		return nil, err
+	}
+	domain.DataMasking = *settings
+	if err := s.repo.Update(ctx, domain); err != nil {
+		return nil, err
+	}
+	return &domain.DataMasking, nil
+}
+
+func validateDataMasking(settings *entities.DataMaskingSettings) error {
+	seen := make(map[string]bool, len(settings.Fields))
+	for _, field := range settings.Fields {
+		if _, ok := maskedUserFields[field]; !ok {
+			return domainerrors.Validation("unknown field %q; use email, first_name, last_name or external_id", field)
+		}
+		if seen[field] {
+			return domainerrors.Validation("field %q is listed twice", field)
+		}
+		seen[field] = true
+	}
+	return nil
+}
+
+// UserMasker masks the users of one response for its viewer and records the users whose masked
+// fields the viewer saw unmasked. Call Finish once the response is written.
+type UserMasker struct {
+	service *dataMaskingService
+	viewer  *PIIViewer
+	view    string
+	domains map[uuid.UUID]*entities.DataMaskingSettings
+	seen    map[uuid.UUID]*PIIView
+}
+
+// Mask returns the user with the fields its domain masks hidden from the viewer. Users are copied
+// before masking, never changed in place.
+func (m *UserMasker) Mask(ctx context.Context, user *entities.User) (*entities.User, error) {
+	settings, ok := m.domains[user.DomainID]
+	if !ok {
+		domain, err := m.service.domainRepo.GetByID(ctx, user.DomainID)
+		if err != nil {
+			return nil, err
+		}
+		settings = &domain.DataMasking
+		m.domains[user.DomainID] = settings
+	}
+	if len(settings.Fields) == 0 {
+		return user, nil
+	}
+
+	if m.viewer != nil && m.viewer.ReadPII && m.viewer.DomainID == user.DomainID {
+		view, ok := m.seen[user.DomainID]
+		if !ok {
+			view = &PIIView{ViewerID: m.viewer.UserID, View: m.view, Fields: settings.Fields}
+			m.seen[user.DomainID] = view
+		}
+		view.Count++
+		if len(view.UserIDs) < maxAuditedUserIDs {
+			view.UserIDs = append(view.UserIDs, user.ID)
+		}
+		return user, nil
+	}
+
+	masked := *user
+	for _, field := range settings.Fields {
+		if mask, ok := maskedUserFields[field]; ok {
+			mask(&masked)
+		}
+	}
+	return &masked, nil
+}
+
+// MaskAll masks every user of a list.
+func (m *UserMasker) MaskAll(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	masked := make([]*entities.User, len(users))
+	for i, user := range users {
+		var err error
+		if masked[i], err = m.Mask(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	return masked, nil
+}
+
+// Finish records the unmasked views in the event log of each domain they belong to.
+func (m *UserMasker) Finish(ctx context.Context) {
+	for domainID, view := range m.seen {
+		m.service.events.Publish(ctx, domainID, EventPIIViewed, view.ViewerID, view)
+	}
+}
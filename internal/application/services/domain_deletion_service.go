@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// domainDeletionLease is how long a worker holds a deletion without saving progress before
+// another instance may take it over. Each batch saves progress, so it only runs out when the
+// worker is gone.
+const domainDeletionLease = 2 * time.Minute
+
+// DomainDeletionService deletes domains in the background: a requested deletion marks the domain,
+// which stops its sign-ins at once, and a worker then deletes its data in batches.
+type DomainDeletionService interface {
+	RequestDeletion(ctx context.Context, domainID uuid.UUID) (*entities.DomainDeletion, error)
+	GetDeletion(ctx context.Context, domainID uuid.UUID) (*entities.DomainDeletion, error)
+	RunWorker(ctx context.Context, interval time.Duration)
+}
+
+type domainDeletionService struct {
+	repo       repositories.DomainDeletionRepository
+	domainRepo repositories.DomainRepository
+	config     *config.DomainDeletionConfig
+	// wake starts the worker of this instance before its next interval
+	wake chan struct{}
+}
+
+func NewDomainDeletionService(repo repositories.DomainDeletionRepository, domainRepo repositories.DomainRepository, cfg *config.DomainDeletionConfig) DomainDeletionService {
+	return &domainDeletionService{repo: repo, domainRepo: domainRepo, config: cfg, wake: make(chan struct{}, 1)}
+}
+
+// RequestDeletion marks the domain for deletion and queues it for the worker. Requesting it again
+// returns the deletion under way, or resumes a failed one where it stopped.
+func (s *domainDeletionService) RequestDeletion(ctx context.Context, domainID uuid.UUID) (*entities.DomainDeletion, error) {
+	ctx, span := tracer.Start(ctx, "DomainDeletionService.RequestDeletion")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	if err := s.domainRepo.MarkForDeletion(ctx, domainID); err != nil {
+		return nil, err
+	}
+
+	deletion, err := s.repo.GetLatest(ctx, domainID)
+	switch {
+	case err == nil && deletion.Status == entities.DomainDeletionFailed:
+		deletion.Status = entities.DomainDeletionPending
+		deletion.Error = ""
+		deletion.FinishedAt = nil
+		if err := s.repo.Save(ctx, deletion, 0); err != nil {
+			return nil, err
+		}
+	case err == nil && deletion.Status != entities.DomainDeletionCompleted:
+		// Already queued or running
+	case err == nil, errors.Is(err, sql.ErrNoRows):
+		deletion = &entities.DomainDeletion{
+			DomainID:   domainID,
+			DomainName: domain.Name,
+			Status:     entities.DomainDeletionPending,
+			Deleted:    map[string]int64{},
+		}
+		if err := s.repo.Create(ctx, deletion); err != nil {
+			// A concurrent request queued it first
+			if repositories.UniqueViolation(err) == "idx_domain_deletions_active" {
+				return s.repo.GetLatest(ctx, domainID)
+			}
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return deletion, nil
+}
+
+// GetDeletion returns the domain's most recent deletion, which can still be read once the domain
+// is gone.
+func (s *domainDeletionService) GetDeletion(ctx context.Context, domainID uuid.UUID) (*entities.DomainDeletion, error) {
+	deletion, err := s.repo.GetLatest(ctx, domainID)
+	if err != nil {
+		return nil, notFoundOr(err, "no deletion was requested for this domain")
+	}
+	return deletion, nil
+}
+
+// RunWorker runs queued deletions every interval, and as soon as one is requested on this
+// instance, until ctx is cancelled.
+func (s *domainDeletionService) RunWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+		for {
+			deletion, err := s.repo.ClaimNext(ctx, domainDeletionLease)
+			if errors.Is(err, sql.ErrNoRows) {
+				break
+			}
+			if err != nil {
+				log.Printf("Failed to claim a domain deletion: %v", err)
+				break
+			}
+			s.run(ctx, deletion)
+		}
+	}
+}
+
+// run deletes the domain's data one step at a time, resuming at the step a previous worker
+// reached, and then the domain. Progress is saved after every batch.
+func (s *domainDeletionService) run(ctx context.Context, deletion *entities.DomainDeletion) {
+	ctx, span := tracer.Start(ctx, "DomainDeletionService.run")
+	defer span.End()
+
+	if deletion.Deleted == nil {
+		deletion.Deleted = map[string]int64{}
+	}
+	steps := repositories.DomainPurgeSteps
+	if i := slices.Index(steps, deletion.Step); i > 0 {
+		steps = steps[i:]
+	}
+
+	for _, step := range steps {
+		deletion.Step = step
+		for {
+			deleted, err := s.repo.PurgeBatch(ctx, deletion.DomainID, step, s.config.BatchSize)
+			if err != nil {
+				s.fail(ctx, deletion, err)
+				return
+			}
+			deletion.Deleted[step] += deleted
+			if err := s.repo.Save(ctx, deletion, domainDeletionLease); err != nil {
+				log.Printf("Failed to save the progress of deleting domain %s: %v", deletion.DomainID, err)
+			}
+			if deleted < int64(s.config.BatchSize) {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				// The lease runs out and another instance resumes the deletion
+				return
+			case <-time.After(s.config.BatchPause):
+			}
+		}
+	}
+
+	deletion.Step = "domain"
+	if err := s.domainRepo.Delete(ctx, deletion.DomainID); err != nil {
+		s.fail(ctx, deletion, err)
+		return
+	}
+	now := time.Now().UTC()
+	deletion.Status = entities.DomainDeletionCompleted
+	deletion.Step = ""
+	deletion.FinishedAt = &now
+	if err := s.repo.Save(ctx, deletion, 0); err != nil {
+		log.Printf("Failed to save the completed deletion of domain %s: %v", deletion.DomainID, err)
+	}
+	log.Printf("Deleted domain %s (%s)", deletion.DomainID, deletion.DomainName)
+}
+
+func (s *domainDeletionService) fail(ctx context.Context, deletion *entities.DomainDeletion, err error) {
+	log.Printf("Failed to delete domain %s at step %s: %v", deletion.DomainID, deletion.Step, err)
+	now := time.Now().UTC()
+	deletion.Status = entities.DomainDeletionFailed
+	deletion.Error = "failed to delete " + deletion.Step
+	deletion.FinishedAt = &now
+	if err := s.repo.Save(ctx, deletion, 0); err != nil {
+		log.Printf("Failed to save the failed deletion of domain %s: %v", deletion.DomainID, err)
+	}
+}
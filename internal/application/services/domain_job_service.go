@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxDomainTags       = 20
+	maxDomainTagLength  = 64
+	defaultDomainJobs   = 50
+	maxDomainJobsListed = 200
+)
+
+type DomainJobService interface {
+	StartJob(ctx context.Context, action string, selector entities.DomainSelector, message *entities.DomainMessage, settings *entities.DomainSettingsChange) (*entities.DomainJob, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*entities.DomainJob, error)
+	ListJobs(ctx context.Context, limit int) ([]*entities.DomainJob, error)
+	SetDomainLabels(ctx context.Context, domainID uuid.UUID, plan string, tags []string) (*entities.Domain, error)
+	FailInterruptedJobs(ctx context.Context)
+}
+
+type domainJobService struct {
+	repo       repositories.DomainJobRepository
+	domainRepo repositories.DomainRepository
+	userRepo   repositories.UserRepository
+	mailer     DomainMailer
+}
+
+func NewDomainJobService(repo repositories.DomainJobRepository, domainRepo repositories.DomainRepository, userRepo repositories.UserRepository, mailer DomainMailer) DomainJobService {
+	return &domainJobService{repo: repo, domainRepo: domainRepo, userRepo: userRepo, mailer: mailer}
+}
+
+// StartJob validates the operation, resolves the selected domains and runs the job in the
+// background. The domains are fixed when the job starts; domains labelled later aren't included.
+func (s *domainJobService) StartJob(ctx context.Context, action string, selector entities.DomainSelector, message *entities.DomainMessage, settings *entities.DomainSettingsChange) (*entities.DomainJob, error) {
+	ctx, span := tracer.Start(ctx, "DomainJobService.StartJob")
+	defer span.End()
+
+	job := &entities.DomainJob{Action: action, Selector: selector, Status: entities.DomainJobPending, Results: []entities.DomainJobResult{}}
+	switch action {
+	case entities.DomainJobSuspend, entities.DomainJobUnsuspend:
+	case entities.DomainJobMessage:
+		if message == nil || strings.TrimSpace(message.Subject) == "" || strings.TrimSpace(message.Body) == "" {
+			return nil, domainerrors.Validation("message requires a subject and a body")
+		}
+		job.Message = message
+	case entities.DomainJobUpdateSettings:
+		if err := validateSettingsChange(settings); err != nil {
+			return nil, err
+		}
+		job.Settings = settings
+	default:
+		return nil, domainerrors.Validation("action must be suspend, unsuspend, message or update_settings")
+	}
+
+	domains, err := s.selectDomains(ctx, &job.Selector)
+	if err != nil {
+		return nil, err
+	}
+	job.Total = len(domains)
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	go s.run(context.WithoutCancel(ctx), job, domains)
+	return job, nil
+}
+
+// domainSuspended reports whether the user's domain is suspended. Break-glass users keep access so
+// operators can still reach a suspended domain.
+func domainSuspended(user *entities.User, domain *entities.Domain) bool {
+	return domain.SuspendedAt != nil && !user.BreakGlass
+}
+
+func errDomainSuspended() error {
+	return domainerrors.Forbidden("domain is suspended").WithCode("domain_suspended")
+}
+
+// domainAccessError rejects sign-ins and tokens of a domain queued for deletion, and of a suspended
+// domain unless the user is a break-glass user.
+func domainAccessError(user *entities.User, domain *entities.Domain) error {
+	if domain.DeletionRequestedAt != nil {
+		return domainerrors.Forbidden("domain is being deleted").WithCode("domain_deleting")
+	}
+	if domainSuspended(user, domain) {
+		return errDomainSuspended()
+	}
+	return nil
+}
+
+// validateSettingsChange checks each setting the way the single-domain endpoints do, so a bulk
+// change can't store what they would reject.
+func validateSettingsChange(settings *entities.DomainSettingsChange) error {
+	if settings == nil || (settings.LoginMode == nil && settings.PasswordPolicy == nil && settings.AccountDeletion == nil &&
+		settings.TokenSettings == nil && settings.DataMasking == nil) {
+		return domainerrors.Validation("update_settings requires at least one setting")
+	}
+	if settings.LoginMode != nil {
+		mode, err := normalizeLoginMode(*settings.LoginMode)
+		if err != nil {
+			return err
+		}
+		settings.LoginMode = &mode
+	}
+	if settings.PasswordPolicy != nil {
+		if err := validatePasswordPolicy(settings.PasswordPolicy); err != nil {
+			return err
+		}
+	}
+	if settings.AccountDeletion != nil {
+		if err := validateAccountDeletion(settings.AccountDeletion); err != nil {
+			return err
+		}
+	}
+	if settings.TokenSettings != nil {
+		if err := validateTokenSettings(settings.TokenSettings); err != nil {
+			return err
+		}
+	}
+	if settings.DataMasking != nil {
+		if err := validateDataMasking(settings.DataMasking); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *domainJobService) selectDomains(ctx context.Context, selector *entities.DomainSelector) ([]*entities.Domain, error) {
+	selector.Plan = strings.TrimSpace(selector.Plan)
+	tags, err := normalizeDomainTags(selector.Tags)
+	if err != nil {
+		return nil, err
+	}
+	selector.Tags = tags
+
+	if selector.All {
+		if selector.Plan != "" || len(selector.Tags) > 0 || len(selector.DomainIDs) > 0 {
+			return nil, domainerrors.Validation("all can't be combined with other criteria")
+		}
+		return s.domainRepo.List(ctx)
+	}
+	if selector.Plan == "" && len(selector.Tags) == 0 && len(selector.DomainIDs) == 0 {
+		return nil, domainerrors.Validation("selector requires a plan, tags, domain_ids or all")
+	}
+	return s.domainRepo.ListBySelector(ctx, selector.Plan, selector.Tags, selector.DomainIDs)
+}
+
+// run applies the job to each domain in turn, saving progress after every domain so the job can
+// be followed while it runs. A failure on one domain doesn't stop the others.
+func (s *domainJobService) run(ctx context.Context, job *entities.DomainJob, domains []*entities.Domain) {
+	started := time.Now().UTC()
+	job.Status = entities.DomainJobRunning
+	job.StartedAt = &started
+	s.save(ctx, job)
+
+	for _, domain := range domains {
+		result := entities.DomainJobResult{DomainID: domain.DomainID, Name: domain.Name, Status: "succeeded"}
+		detail, err := s.apply(ctx, job, domain)
+		if err != nil {
+			result.Status = "failed"
+			result.Error = err.Error()
+			job.Failed++
+		} else {
+			result.Detail = detail
+			job.Succeeded++
+		}
+		job.Results = append(job.Results, result)
+		s.save(ctx, job)
+	}
+
+	finished := time.Now().UTC()
+	job.Status = entities.DomainJobCompleted
+	job.FinishedAt = &finished
+	s.save(ctx, job)
+}
+
+func (s *domainJobService) save(ctx context.Context, job *entities.DomainJob) {
+	if err := s.repo.Update(ctx, job); err != nil {
+		log.Printf("Failed to save domain job %s: %v", job.ID, err)
+	}
+}
+
+// apply runs the job on one domain, re-reading it first so edits made since the job started
+// aren't overwritten.
+func (s *domainJobService) apply(ctx context.Context, job *entities.DomainJob, selected *entities.Domain) (string, error) {
+	domain, err := s.domainRepo.GetByID(ctx, selected.DomainID)
+	if err != nil {
+		return "", notFoundOr(err, "domain not found")
+	}
+	switch job.Action {
+	case entities.DomainJobSuspend:
+		if domain.SuspendedAt != nil {
+			return "already suspended", nil
+		}
+		now := time.Now().UTC()
+		domain.SuspendedAt = &now
+		return "suspended", s.domainRepo.Update(ctx, domain)
+	case entities.DomainJobUnsuspend:
+		if domain.SuspendedAt == nil {
+			return "not suspended", nil
+		}
+		domain.SuspendedAt = nil
+		return "unsuspended", s.domainRepo.Update(ctx, domain)
+	case entities.DomainJobMessage:
+		return s.message(ctx, domain, job.Message)
+	case entities.DomainJobUpdateSettings:
+		applySettingsChange(domain, job.Settings)
+		return "settings updated", s.domainRepo.Update(ctx, domain)
+	}
+	return "", fmt.Errorf("unknown action %q", job.Action)
+}
+
+// message emails every active user of the domain. The domain fails only when no email could be
+// sent at all.
+func (s *domainJobService) message(ctx context.Context, domain *entities.Domain, message *entities.DomainMessage) (string, error) {
+	users, err := s.userRepo.GetByDomainID(ctx, domain.DomainID)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	sent, failed := 0, 0
+	var lastErr error
+	for _, user := range users {
+		if accountDisabled(user, now) || user.Email == "" {
+			continue
+		}
+		if err := s.mailer.Send(ctx, domain.DomainID, user.Email, message.Subject, message.Body); err != nil {
+			failed++
+			lastErr = err
+			continue
+		}
+		sent++
+	}
+	if sent == 0 && lastErr != nil {
+		return "", fmt.Errorf("no email could be sent: %w", lastErr)
+	}
+	if failed > 0 {
+		return fmt.Sprintf("emailed %d users, %d failed", sent, failed), nil
+	}
+	return fmt.Sprintf("emailed %d users", sent), nil
+}
+
+func applySettingsChange(domain *entities.Domain, settings *entities.DomainSettingsChange) {
+	if settings.LoginMode != nil {
+		domain.LoginMode = *settings.LoginMode
+	}
+	if settings.PasswordPolicy != nil {
+		domain.PasswordPolicy = *settings.PasswordPolicy
+	}
+	if settings.AccountDeletion != nil {
+		domain.AccountDeletion = *settings.AccountDeletion
+	}
+	if settings.TokenSettings != nil {
+		domain.TokenSettings = *settings.TokenSettings
+	}
+	if settings.DataMasking != nil {
+		domain.DataMasking = *settings.DataMasking
+	}
+}
+
+func (s *domainJobService) GetJob(ctx context.Context, id uuid.UUID) (*entities.DomainJob, error) {
+	ctx, span := tracer.Start(ctx, "DomainJobService.GetJob")
+	defer span.End()
+
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "domain job not found")
+	}
+	return job, nil
+}
+
+// ListJobs returns the most recent jobs first.
+func (s *domainJobService) ListJobs(ctx context.Context, limit int) ([]*entities.DomainJob, error) {
+	ctx, span := tracer.Start(ctx, "DomainJobService.ListJobs")
+	defer span.End()
+
+	if limit <= 0 {
+		limit = defaultDomainJobs
+	}
+	if limit > maxDomainJobsListed {
+		limit = maxDomainJobsListed
+	}
+	jobs, err := s.repo.List(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	if jobs == nil {
+		jobs = []*entities.DomainJob{}
+	}
+	return jobs, nil
+}
+
+// SetDomainLabels replaces the plan and tags bulk operations select the domain by.
+func (s *domainJobService) SetDomainLabels(ctx context.Context, domainID uuid.UUID, plan string, tags []string) (*entities.Domain, error) {
+	ctx, span := tracer.Start(ctx, "DomainJobService.SetDomainLabels")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	if domain.Tags, err = normalizeDomainTags(tags); err != nil {
+		return nil, err
+	}
+	domain.Plan = strings.TrimSpace(plan)
+	if err := s.domainRepo.Update(ctx, domain); err != nil {
+		return nil, err
+	}
+	return domain, nil
+}
+
+// normalizeDomainTags lowercases and de-duplicates tags, keeping their order.
+func normalizeDomainTags(tags []string) ([]string, error) {
+	if len(tags) > maxDomainTags {
+		return nil, domainerrors.Validation("at most %d tags are allowed", maxDomainTags)
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || len(tag) > maxDomainTagLength {
+			return nil, domainerrors.Validation("tags must be between 1 and %d characters", maxDomainTagLength)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// FailInterruptedJobs marks jobs left pending or running by a restart as failed, since nothing
+// will resume them.
+func (s *domainJobService) FailInterruptedJobs(ctx context.Context) {
+	failed, err := s.repo.FailUnfinished(ctx, "interrupted by a server restart")
+	if err != nil {
+		log.Printf("Failed to mark interrupted domain jobs: %v", err)
+	} else if failed > 0 {
+		log.Printf("Marked %d interrupted domain job(s) as failed", failed)
+	}
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+	"maps"
+	"math/big"
+	"strings"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/repositories"
+)
+
+// DefaultRoleTemplates are the roles of an onboarded domain: admin, held by its first user, then
+// member for everyone else.
+var DefaultRoleTemplates = []entities.RoleTemplate{
+	{Name: "admin", RoleClaims: map[string]interface{}{entities.PermissionDomainAdmin: true, entities.PermissionReadPII: true}},
+	{Name: "member", RoleClaims: map[string]interface{}{}},
+}
+
+// OnboardingAdmin is the first admin of an onboarded domain.
+type OnboardingAdmin struct {
+	Username  string
+	Email     string
+	FirstName string
+	LastName  string
+}
+
+// DomainOnboarding is a domain ready for use: its roles, in the order of DefaultRoleTemplates, and
+// its first admin, whose credentials were emailed to them.
+type DomainOnboarding struct {
+	Domain *entities.Domain `json:"domain"`
+	Roles  []*entities.Role `json:"roles"`
+	Admin  *entities.User   `json:"admin"`
+}
+
+type DomainOnboardingService interface {
+	OnboardDomain(ctx context.Context, name, domainStr, residency, loginMode string, passwordPolicy *entities.PasswordPolicy, admin OnboardingAdmin) (*DomainOnboarding, error)
+}
+
+type domainOnboardingService struct {
+	domains  DomainService
+	roleRepo repositories.RoleRepository
+	userRepo repositories.UserRepository
+	events   EventService
+	mailer   DomainMailer
+	tx       repositories.TxManager
+}
+
+func NewDomainOnboardingService(domains DomainService, roleRepo repositories.RoleRepository, userRepo repositories.UserRepository, events EventService, mailer DomainMailer, tx repositories.TxManager) DomainOnboardingService {
+	return &domainOnboardingService{domains: domains, roleRepo: roleRepo, userRepo: userRepo, events: events, mailer: mailer, tx: tx}
+}
+
+// OnboardDomain creates a domain with the default roles and an admin holding the first of them,
+// and emails the admin how to sign in: with a generated temporary password, or with a code in
+// passwordless domains. The domain row lives on the primary and the rest on the domain's shard,
+// so one transaction can't cover both; when any later step fails the domain is deleted again,
+// taking whatever reached its shard with it.
+func (s *domainOnboardingService) OnboardDomain(ctx context.Context, name, domainStr, residency, loginMode string, passwordPolicy *entities.PasswordPolicy, admin OnboardingAdmin) (*DomainOnboarding, error) {
+	ctx, span := tracer.Start(ctx, "DomainOnboardingService.OnboardDomain")
+	defer span.End()
+
+	domain, err := s.domains.CreateDomain(ctx, name, domainStr, residency, loginMode, passwordPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	onboarding, password, err := s.bootstrap(ctx, domain, admin)
+	if err == nil {
+		err = s.sendCredentials(ctx, domain, onboarding.Admin, password)
+	}
+	if err != nil {
+		if _, deleteErr := s.domains.DeleteDomain(ctx, domain.DomainID, true, false); deleteErr != nil {
+			log.Printf("Failed to delete domain %s after its onboarding failed: %v", domain.DomainID, deleteErr)
+		}
+		return nil, err
+	}
+	return onboarding, nil
+}
+
+// bootstrap creates the roles and the admin in one transaction on the domain's shard, and returns
+// the admin's temporary password, which is empty in passwordless domains.
+func (s *domainOnboardingService) bootstrap(ctx context.Context, domain *entities.Domain, admin OnboardingAdmin) (*DomainOnboarding, string, error) {
+	var password string
+	if domain.LoginMode != entities.LoginModePasswordless {
+		var err error
+		if password, err = temporaryPassword(domain); err != nil {
+			return nil, "", err
+		}
+	}
+
+	onboarding := &DomainOnboarding{Domain: domain, Roles: []*entities.Role{}}
+	err := s.tx.WithinTenantTx(ctx, domain.DomainID, func(ctx context.Context) error {
+		for _, template := range DefaultRoleTemplates {
+			role := &entities.Role{DomainID: domain.DomainID, RoleName: template.Name, RoleClaims: maps.Clone(template.RoleClaims)}
+			if err := s.roleRepo.Create(ctx, role); err != nil {
+				return err
+			}
+			if err := s.events.Record(ctx, domain.DomainID, EventRoleCreated, role.ID, role); err != nil {
+				return err
+			}
+			onboarding.Roles = append(onboarding.Roles, role)
+		}
+
+		user := &entities.User{
+			DomainID:  domain.DomainID,
+			RoleID:    onboarding.Roles[0].ID,
+			FirstName: strings.TrimSpace(admin.FirstName),
+			LastName:  strings.TrimSpace(admin.LastName),
+			Username:  strings.TrimSpace(admin.Username),
+			Email:     strings.TrimSpace(admin.Email),
+		}
+		if password != "" {
+			user.PasswordHash = hashPassword(password)
+		}
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return conflictFromDB(err)
+		}
+		onboarding.Admin = user
+		return s.events.Record(ctx, domain.DomainID, EventUserCreated, user.ID, user)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return onboarding, password, nil
+}
+
+func (s *domainOnboardingService) sendCredentials(ctx context.Context, domain *entities.Domain, admin *entities.User, password string) error {
+	signIn := "Sign in with your email address; a login code will be sent to it each time."
+	if password != "" {
+		signIn = fmt.Sprintf("Your temporary password is:\n%s\n\nChange it after you first sign in.", password)
+	}
+	body := fmt.Sprintf("You are the administrator of %s.\n\nUsername: %s\n%s",
+		domain.Name, admin.Username, signIn)
+	if err := s.mailer.Send(ctx, domain.DomainID, admin.Email, "Your administrator account for "+domain.Name, body); err != nil {
+		log.Printf("Failed to send onboarding credentials: %v", err)
+		return fmt.Errorf("failed to send the admin's credentials")
+	}
+	return nil
+}
+
+// Characters of generated passwords, leaving out look-alikes such as 0/O and 1/l/I.
+const (
+	passwordUppercase = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	passwordLowercase = "abcdefghijkmnopqrstuvwxyz"
+	passwordDigits    = "23456789"
+	passwordSymbols   = "!#$%&*+-=?@^_"
+)
+
+// temporaryPassword generates a password meeting any policy of the domain: at least 16 characters,
+// or the policy's minimum length, with every character class.
+func temporaryPassword(domain *entities.Domain) (string, error) {
+	length := max(effectivePasswordPolicy(domain).MinLength, 16)
+	classes := []string{passwordUppercase, passwordLowercase, passwordDigits, passwordSymbols}
+	all := strings.Join(classes, "")
+
+	password := make([]byte, 0, length)
+	for i := 0; i < length; i++ {
+		// The first characters cover each class; the shuffle below moves them
+		set := all
+		if i < len(classes) {
+			set = classes[i]
+		}
+		n, err := randomIndex(len(set))
+		if err != nil {
+			return "", err
+		}
+		password = append(password, set[n])
+	}
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+func randomIndex(n int) (int, error) {
+	value, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate password: %w", err)
+	}
+	return int(value.Int64()), nil
+}
@@ -1,50 +1,93 @@
 package services
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+
 	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
 	"backend/internal/infrastructure/repositories"
 
 	"github.com/google/uuid"
 )
 
 type DomainService interface {
-	GetDomainByID(id uuid.UUID) (*entities.Domain, error)
-	CreateDomain(name, domainStr string) (*entities.Domain, error)
-	ListDomains() ([]*entities.Domain, error)
-	ListDomainsWithPagination(search string, page, limit int) (*repositories.DomainListResult, error)
-	UpdateDomain(id uuid.UUID, name, domainStr string) (*entities.Domain, error)
-	DeleteDomain(id uuid.UUID) error
+	GetDomainByID(ctx context.Context, id uuid.UUID) (*entities.Domain, error)
+	CreateDomain(ctx context.Context, name, domainStr, residency, loginMode string, passwordPolicy *entities.PasswordPolicy) (*entities.Domain, error)
+	ListDomains(ctx context.Context) ([]*entities.Domain, error)
+	ListDomainsWithPagination(ctx context.Context, search string, page, limit int) (*repositories.DomainListResult, error)
+	ListDomainsAfter(ctx context.Context, search, cursor string, limit int) (*repositories.DomainCursorPage, error)
+	UpdateDomain(ctx context.Context, id uuid.UUID, name, domainStr, loginMode string, passwordPolicy *entities.PasswordPolicy, registration *entities.RegistrationSettings, branding *entities.DomainBranding, accountDeletion *entities.AccountDeletionSettings) (*entities.Domain, error)
+	DeleteDomain(ctx context.Context, id uuid.UUID, force, dryRun bool) (*DomainDeletionPlan, error)
+	ResolveDomain(ctx context.Context, hostname string) (*entities.Domain, error)
+	ListAliases(ctx context.Context, domainID uuid.UUID) ([]*entities.DomainAlias, error)
+	AddAlias(ctx context.Context, domainID uuid.UUID, hostname string, isPrimary bool) (*entities.DomainAlias, error)
+	SetPrimaryAlias(ctx context.Context, domainID, aliasID uuid.UUID) error
+	RemoveAlias(ctx context.Context, domainID, aliasID uuid.UUID) error
+	GetPasswordPolicy(ctx context.Context, id uuid.UUID) (*entities.PasswordPolicy, error)
+	GetTokenSettings(ctx context.Context, id uuid.UUID) (*entities.DomainTokenSettings, error)
+	UpdateTokenSettings(ctx context.Context, id uuid.UUID, settings *entities.DomainTokenSettings) (*entities.DomainTokenSettings, error)
+	GetDataMasking(ctx context.Context, id uuid.UUID) (*entities.DataMaskingSettings, error)
+	UpdateDataMasking(ctx context.Context, id uuid.UUID, settings *entities.DataMaskingSettings) (*entities.DataMaskingSettings, error)
 }
 
 type domainService struct {
-	repo repositories.DomainRepository
+	repo      repositories.DomainRepository
+	aliasRepo repositories.DomainAliasRepository
+	roleRepo  repositories.RoleRepository
 }
 
-func NewDomainService(repo repositories.DomainRepository) DomainService {
-	return &domainService{repo: repo}
+func NewDomainService(repo repositories.DomainRepository, aliasRepo repositories.DomainAliasRepository, roleRepo repositories.RoleRepository) DomainService {
+	return &domainService{repo: repo, aliasRepo: aliasRepo, roleRepo: roleRepo}
 }
 
-func (s *domainService) GetDomainByID(id uuid.UUID) (*entities.Domain, error) {
-	return s.repo.GetByID(id)
+func (s *domainService) GetDomainByID(ctx context.Context, id uuid.UUID) (*entities.Domain, error) {
+	domain, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	return domain, nil
 }
 
-func (s *domainService) CreateDomain(name, domainStr string) (*entities.Domain, error) {
+// CreateDomain creates the domain; a nil passwordPolicy uses the default policy.
+func (s *domainService) CreateDomain(ctx context.Context, name, domainStr, residency, loginMode string, passwordPolicy *entities.PasswordPolicy) (*entities.Domain, error) {
+	loginMode, err := normalizeLoginMode(loginMode)
+	if err != nil {
+		return nil, err
+	}
+	if passwordPolicy == nil {
+		passwordPolicy = &entities.PasswordPolicy{}
+	}
+	if err := validatePasswordPolicy(passwordPolicy); err != nil {
+		return nil, err
+	}
+
 	domain := &entities.Domain{
-		Name:   name,
-		Domain: domainStr,
+		Name:           name,
+		Domain:         domainStr,
+		Residency:      strings.ToLower(strings.TrimSpace(residency)),
+		LoginMode:      loginMode,
+		PasswordPolicy: *passwordPolicy,
 	}
-	err := s.repo.Create(domain)
+	err = s.repo.Create(ctx, domain)
 	if err != nil {
 		return nil, err
 	}
 	return domain, nil
 }
 
-func (s *domainService) ListDomains() ([]*entities.Domain, error) {
-	return s.repo.List()
+func (s *domainService) ListDomains(ctx context.Context) ([]*entities.Domain, error) {
+	return s.repo.List(ctx)
 }
 
-func (s *domainService) ListDomainsWithPagination(search string, page, limit int) (*repositories.DomainListResult, error) {
+func (s *domainService) ListDomainsWithPagination(ctx context.Context, search string, page, limit int) (*repositories.DomainListResult, error) {
+	ctx, span := tracer.Start(ctx, "DomainService.ListDomainsWithPagination")
+	defer span.End()
+
 	// Set default values
 	if page <= 0 {
 		page = 1
@@ -53,22 +96,263 @@ func (s *domainService) ListDomainsWithPagination(search string, page, limit int
 		limit = 10
 	}
 
-	return s.repo.ListWithPagination(search, page, limit)
+	return s.repo.ListWithPagination(ctx, search, page, limit)
 }
 
-func (s *domainService) UpdateDomain(id uuid.UUID, name, domainStr string) (*entities.Domain, error) {
-	domain := &entities.Domain{
-		DomainID: id,
-		Name:     name,
-		Domain:   domainStr,
+// ListDomainsAfter lists domains oldest first, resuming after the cursor of the previous page; an
+// empty cursor starts at the first domain.
+func (s *domainService) ListDomainsAfter(ctx context.Context, search, cursor string, limit int) (*repositories.DomainCursorPage, error) {
+	ctx, span := tracer.Start(ctx, "DomainService.ListDomainsAfter")
+	defer span.End()
+
+	after, err := repositories.ParseListCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	return s.repo.ListAfter(ctx, search, after, limit)
+}
+
+// UpdateDomain updates the domain; an empty loginMode or a nil passwordPolicy, registration,
+// branding or accountDeletion keeps the current setting. Registration settings are only set here because a new
+// domain has no roles to default to yet.
+func (s *domainService) UpdateDomain(ctx context.Context, id uuid.UUID, name, domainStr, loginMode string, passwordPolicy *entities.PasswordPolicy, registration *entities.RegistrationSettings, branding *entities.DomainBranding, accountDeletion *entities.AccountDeletionSettings) (*entities.Domain, error) {
+	domain, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	if strings.TrimSpace(loginMode) != "" {
+		if domain.LoginMode, err = normalizeLoginMode(loginMode); err != nil {
+			return nil, err
+		}
+	}
+	if passwordPolicy != nil {
+		if err := validatePasswordPolicy(passwordPolicy); err != nil {
+			return nil, err
+		}
+		domain.PasswordPolicy = *passwordPolicy
+	}
+	if registration != nil {
+		if err := s.validateRegistration(ctx, id, registration); err != nil {
+			return nil, err
+		}
+		domain.Registration = *registration
+	}
+	if branding != nil {
+		if err := validateBranding(branding); err != nil {
+			return nil, err
+		}
+		domain.Branding = *branding
+	}
+	if accountDeletion != nil {
+		if err := validateAccountDeletion(accountDeletion); err != nil {
+			return nil, err
+		}
+		domain.AccountDeletion = *accountDeletion
+	}
+	domain.Name = name
+	domain.Domain = domainStr
+
+	err = s.repo.Update(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	return domain, nil
+}
+
+// validateRegistration requires open registration to name a default role, and any default role
+// to belong to the domain.
+func (s *domainService) validateRegistration(ctx context.Context, domainID uuid.UUID, registration *entities.RegistrationSettings) error {
+	if registration.DefaultRoleID == nil {
+		if registration.Open {
+			return domainerrors.Validation("open registration requires a default_role_id")
+		}
+		return nil
+	}
+	role, err := s.roleRepo.GetByID(ctx, *registration.DefaultRoleID)
+	if err != nil || role.DomainID != domainID {
+		return domainerrors.Validation("default role does not exist in this domain")
+	}
+	return nil
+}
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validateBranding only accepts hex colors and absolute http(s) URLs, since colors end up in the
+// login page's stylesheet and redirect URIs get the access token appended as a fragment.
+func validateBranding(branding *entities.DomainBranding) error {
+	branding.DisplayName = strings.TrimSpace(branding.DisplayName)
+	for _, color := range []struct{ name, value string }{
+		{"primary_color", branding.PrimaryColor}, {"background_color", branding.BackgroundColor},
+	} {
+		if color.value != "" && !hexColorPattern.MatchString(color.value) {
+			return domainerrors.Validation("%s must be a hex color such as #1a73e8", color.name)
+		}
+	}
+	if branding.LogoURL != "" && !isHTTPURL(branding.LogoURL) {
+		return domainerrors.Validation("logo_url must be an absolute http or https URL")
+	}
+	if len(branding.RedirectURIs) > 20 {
+		return domainerrors.Validation("at most 20 redirect_uris are allowed")
+	}
+	for _, uri := range branding.RedirectURIs {
+		if !isHTTPURL(uri) || strings.Contains(uri, "#") {
+			return domainerrors.Validation("redirect URI %q must be an absolute http or https URL without a fragment", uri)
+		}
+	}
+	return nil
+}
+
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+// DomainDeletionPlan is the effect of deleting a domain: it and every row counted in Deleted are
+// removed. Unless forced, only a domain without tenant data can be deleted; its events and
+// aliases don't count.
+type DomainDeletionPlan struct {
+	Domain  *entities.Domain              `json:"domain"`
+	Deleted repositories.DomainDependents `json:"deleted"`
+	DryRun  bool                          `json:"dry_run"`
+}
+
+// DeleteDomain deletes a domain with all of its data. A dry run checks the same conditions and
+// returns the plan without deleting anything.
+func (s *domainService) DeleteDomain(ctx context.Context, id uuid.UUID, force, dryRun bool) (*DomainDeletionPlan, error) {
+	ctx, span := tracer.Start(ctx, "DomainService.DeleteDomain")
+	defer span.End()
+
+	domain, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
 	}
-	err := s.repo.Update(domain)
+	dependents, err := s.repo.CountDependents(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if !force && dependents.Users+dependents.Roles+dependents.Permissions+dependents.Groups+dependents.Policies+
+		dependents.Invitations+dependents.RegistrationCodes+dependents.Webhooks+dependents.APIKeys > 0 {
+		return nil, &DomainInUseError{
+			Err:        domainerrors.Conflict("domain still has users, roles or other data; pass force to delete them with it").WithCode("domain_in_use"),
+			Dependents: *dependents,
+		}
+	}
+	plan := &DomainDeletionPlan{Domain: domain, Deleted: *dependents, DryRun: dryRun}
+	if dryRun {
+		return plan, nil
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// DomainInUseError reports a domain that can't be deleted without force, with the rows it still
+// holds. It unwraps to a conflict.
+type DomainInUseError struct {
+	Err        *domainerrors.Error
+	Dependents repositories.DomainDependents
+}
+
+func (e *DomainInUseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DomainInUseError) Unwrap() error {
+	return e.Err
+}
+
+func (s *domainService) ResolveDomain(ctx context.Context, hostname string) (*entities.Domain, error) {
+	domain, err := s.repo.GetByHostname(ctx, normalizeHostname(hostname))
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
 	return domain, nil
 }
 
-func (s *domainService) DeleteDomain(id uuid.UUID) error {
-	return s.repo.Delete(id)
+func (s *domainService) ListAliases(ctx context.Context, domainID uuid.UUID) ([]*entities.DomainAlias, error) {
+	if _, err := s.repo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	return s.aliasRepo.GetByDomainID(ctx, domainID)
+}
+
+func (s *domainService) AddAlias(ctx context.Context, domainID uuid.UUID, hostname string, isPrimary bool) (*entities.DomainAlias, error) {
+	if _, err := s.repo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	hostname = normalizeHostname(hostname)
+	if hostname == "" {
+		return nil, domainerrors.Validation("hostname is required")
+	}
+
+	// A hostname may only resolve to a single tenant
+	if _, err := s.repo.GetByHostname(ctx, hostname); err == nil {
+		return nil, domainerrors.Conflict("hostname is already registered to a domain").WithCode("hostname_taken")
+	}
+
+	existing, err := s.aliasRepo.GetByDomainID(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first alias registered for a domain becomes its primary
+	alias := &entities.DomainAlias{
+		DomainID:  domainID,
+		Hostname:  hostname,
+		IsPrimary: len(existing) == 0,
+	}
+	if err := s.aliasRepo.Create(ctx, alias); err != nil {
+		return nil, err
+	}
+
+	if isPrimary && !alias.IsPrimary {
+		if err := s.aliasRepo.SetPrimary(ctx, domainID, alias.ID); err != nil {
+			return nil, err
+		}
+		alias.IsPrimary = true
+	}
+	return alias, nil
+}
+
+func (s *domainService) SetPrimaryAlias(ctx context.Context, domainID, aliasID uuid.UUID) error {
+	if err := s.aliasRepo.SetPrimary(ctx, domainID, aliasID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domainerrors.NotFound("alias not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *domainService) RemoveAlias(ctx context.Context, domainID, aliasID uuid.UUID) error {
+	if err := s.aliasRepo.Delete(ctx, domainID, aliasID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domainerrors.NotFound("alias not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func normalizeHostname(hostname string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostname)), ".")
+}
+
+// normalizeLoginMode defaults to password login and rejects unknown modes.
+func normalizeLoginMode(mode string) (string, error) {
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case "":
+		return entities.LoginModePassword, nil
+	case entities.LoginModePassword, entities.LoginModePasswordless:
+		return mode, nil
+	default:
+		return "", domainerrors.Validation("login mode must be password or passwordless")
+	}
 }
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/mail"
+	"strings"
+	"text/template"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Email templates. Every template can use .Product, the domain's product name, .Domain, its name,
+// and .SupportEmail; the fields each template adds are in emailTemplateSamples.
+const (
+	EmailInvitation    = "invitation"
+	EmailVerification  = "verification"
+	EmailPasswordReset = "password_reset"
+)
+
+const (
+	maxEmailFooterLength   = 2000
+	maxEmailTemplateLength = 10000
+)
+
+// DefaultEmailTemplates are the built-in templates, used unless a domain's branding overrides them.
+var DefaultEmailTemplates = map[string]entities.EmailTemplate{
+	EmailInvitation: {
+		Subject: "You're invited to {{.Product}}",
+		Body: "You have been invited to join {{.Product}}.\n\nAccept the invitation and set up your account with this link:\n{{.Link}}\n\n" +
+			"The link expires in {{.ExpiresIn}}. If you were not expecting this invitation, ignore this email.",
+	},
+	EmailVerification: {
+		Subject: "Your login code",
+		Body: "Your login code is {{.Code}}\n\nOr sign in with this link:\n{{.Link}}\n\n" +
+			"The code and link expire in {{.ExpiresIn}}. If you did not request them, ignore this email.",
+	},
+	EmailPasswordReset: {
+		Subject: "Your {{.Product}} password was reset",
+		Body: "Hello {{.Username}},\n\nAn administrator reset the password of your {{.Product}} account and signed out its sessions.\n\n" +
+			"If you did not ask for this, contact {{if .SupportEmail}}{{.SupportEmail}}{{else}}your administrator{{end}} immediately.",
+	},
+}
+
+// emailTemplateSamples are the fields each template adds, with the values previews render.
+var emailTemplateSamples = map[string]map[string]any{
+	EmailInvitation:    {"Link": "https://app.example.com/auth/accept-invitation?token=sample", "ExpiresIn": "168h0m0s"},
+	EmailVerification:  {"Code": "123456", "Link": "https://app.example.com/auth/magic-link?token=sample", "ExpiresIn": "10m0s"},
+	EmailPasswordReset: {"Username": "jdoe"},
+}
+
+// RenderedEmail is a template filled in for one message.
+type RenderedEmail struct {
+	Subject string `json:"subject" example:"You're invited to Acme Portal"`
+	Body    string `json:"body" example:"You have been invited to join Acme Portal."`
+}
+
+// EmailService sends emails rendered from templates in the domain's branding, through a DomainMailer.
+type EmailService interface {
+	Send(ctx context.Context, domainID uuid.UUID, to, name string, data map[string]any) error
+	Preview(ctx context.Context, domainID uuid.UUID, name string) (*RenderedEmail, error)
+	GetBranding(ctx context.Context, domainID uuid.UUID) (*entities.EmailBranding, error)
+	UpdateBranding(ctx context.Context, branding *entities.EmailBranding) (*entities.EmailBranding, error)
+	DeleteBranding(ctx context.Context, domainID uuid.UUID) error
+}
+
+type emailService struct {
+	repo       repositories.DomainEmailBrandingRepository
+	domainRepo repositories.DomainRepository
+	mailer     DomainMailer
+}
+
+func NewEmailService(repo repositories.DomainEmailBrandingRepository, domainRepo repositories.DomainRepository, mailer DomainMailer) EmailService {
+	return &emailService{repo: repo, domainRepo: domainRepo, mailer: mailer}
+}
+
+// Send renders the named template with data and the domain's branding and sends it. A domain
+// template that fails to render is logged and the built-in one used instead.
+func (s *emailService) Send(ctx context.Context, domainID uuid.UUID, to, name string, data map[string]any) error {
+	ctx, span := tracer.Start(ctx, "EmailService.Send")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return notFoundOr(err, "domain not found")
+	}
+	branding, err := s.repo.GetByDomainID(ctx, domainID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Failed to load the email branding of domain %s, using the defaults: %v", domainID, err)
+	}
+	if err != nil {
+		branding = &entities.EmailBranding{DomainID: domainID}
+	}
+
+	email, err := renderEmail(domain, branding, name, data)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, domainID, to, email.Subject, email.Body)
+}
+
+// Preview renders the named template in the domain's branding with sample values.
+func (s *emailService) Preview(ctx context.Context, domainID uuid.UUID, name string) (*RenderedEmail, error) {
+	if _, ok := DefaultEmailTemplates[name]; !ok {
+		return nil, domainerrors.NotFound("unknown email template %q", name)
+	}
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	branding, err := s.repo.GetByDomainID(ctx, domainID)
+	if errors.Is(err, sql.ErrNoRows) {
+		branding, err = &entities.EmailBranding{DomainID: domainID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return renderEmail(domain, branding, name, emailTemplateSamples[name])
+}
+
+func (s *emailService) GetBranding(ctx context.Context, domainID uuid.UUID) (*entities.EmailBranding, error) {
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	branding, err := s.repo.GetByDomainID(ctx, domainID)
+	if err != nil {
+		return nil, notFoundOr(err, "domain uses the default email branding")
+	}
+	return branding, nil
+}
+
+// UpdateBranding replaces the domain's branding. Template overrides are checked by rendering them
+// with sample values, so one using an unknown field is rejected.
+func (s *emailService) UpdateBranding(ctx context.Context, branding *entities.EmailBranding) (*entities.EmailBranding, error) {
+	ctx, span := tracer.Start(ctx, "EmailService.UpdateBranding")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, branding.DomainID)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+
+	branding.ProductName = strings.TrimSpace(branding.ProductName)
+	branding.SupportEmail = strings.TrimSpace(branding.SupportEmail)
+	if strings.ContainsAny(branding.ProductName, "\r\n") {
+		return nil, domainerrors.Validation("product_name must be a single line")
+	}
+	if branding.SupportEmail != "" {
+		if _, err := mail.ParseAddress(branding.SupportEmail); err != nil {
+			return nil, domainerrors.Validation("support_email must be a valid email address")
+		}
+	}
+	if len(branding.Footer) > maxEmailFooterLength {
+		return nil, domainerrors.Validation("footer must be at most %d characters", maxEmailFooterLength)
+	}
+	if branding.Templates == nil {
+		branding.Templates = map[string]entities.EmailTemplate{}
+	}
+	for name, override := range branding.Templates {
+		if _, ok := DefaultEmailTemplates[name]; !ok {
+			return nil, domainerrors.Validation("unknown email template %q", name).WithCode("invalid_email_template")
+		}
+		if len(override.Subject) > maxEmailTemplateLength || len(override.Body) > maxEmailTemplateLength {
+			return nil, domainerrors.Validation("template %s must be at most %d characters", name, maxEmailTemplateLength).WithCode("invalid_email_template")
+		}
+		if _, err := renderTemplate(override, emailData(domain, branding, emailTemplateSamples[name])); err != nil {
+			return nil, domainerrors.Validation("template %s: %v", name, err).WithCode("invalid_email_template")
+		}
+	}
+
+	if err := s.repo.Upsert(ctx, branding); err != nil {
+		return nil, err
+	}
+	return branding, nil
+}
+
+func (s *emailService) DeleteBranding(ctx context.Context, domainID uuid.UUID) error {
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return notFoundOr(err, "domain not found")
+	}
+	return notFoundOr(s.repo.Delete(ctx, domainID), "domain uses the default email branding")
+}
+
+// renderEmail fills in the domain's override of the named template, or the built-in one, and
+// appends the branding's footer.
+func renderEmail(domain *entities.Domain, branding *entities.EmailBranding, name string, data map[string]any) (*RenderedEmail, error) {
+	builtIn, ok := DefaultEmailTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+	fields := emailData(domain, branding, data)
+
+	var email *RenderedEmail
+	if override, ok := branding.Templates[name]; ok {
+		rendered, err := renderTemplate(override, fields)
+		if err != nil {
+			log.Printf("Failed to render the %s email template of domain %s, using the default: %v", name, domain.DomainID, err)
+		}
+		email = rendered
+	}
+	if email == nil {
+		rendered, err := renderTemplate(builtIn, fields)
+		if err != nil {
+			return nil, err
+		}
+		email = rendered
+	}
+	if footer := strings.TrimSpace(branding.Footer); footer != "" {
+		email.Body += "\n\n--\n" + footer
+	}
+	return email, nil
+}
+
+func emailData(domain *entities.Domain, branding *entities.EmailBranding, data map[string]any) map[string]any {
+	product := branding.ProductName
+	if product == "" {
+		product = domain.Name
+	}
+	fields := map[string]any{"Product": product, "Domain": domain.Name, "SupportEmail": branding.SupportEmail}
+	maps.Copy(fields, data)
+	return fields
+}
+
+// renderTemplate executes a template, failing on fields it doesn't know. The subject is kept to
+// one line since it becomes a header.
+func renderTemplate(tmpl entities.EmailTemplate, data map[string]any) (*RenderedEmail, error) {
+	render := func(name, text string) (string, error) {
+		t, err := template.New(name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return "", err
+		}
+		var b bytes.Buffer
+		if err := t.Execute(&b, data); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+
+	subject, err := render("subject", tmpl.Subject)
+	if err != nil {
+		return nil, err
+	}
+	body, err := render("body", tmpl.Body)
+	if err != nil {
+		return nil, err
+	}
+	subject = strings.Join(strings.Fields(subject), " ")
+	if subject == "" {
+		return nil, fmt.Errorf("the subject is empty")
+	}
+	return &RenderedEmail{Subject: subject, Body: body}, nil
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+
+	domainerrors "backend/internal/domain/errors"
+)
+
+// notFoundOr reports sql.ErrNoRows from a lookup as a not-found error with the given message and
+// passes any other error through unchanged.
+func notFoundOr(err error, message string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return domainerrors.NotFound("%s", message)
+	}
+	return err
+}
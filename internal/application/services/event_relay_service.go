@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/broker"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/metrics"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// EventSchemaVersion is the version of the EventEnvelope published to the broker. Fields are only
+// ever added within a version.
+const EventSchemaVersion = 1
+
+const maxOutboxErrLen = 500
+
+// EventEnvelope is the JSON body of every event published to the broker. Consumers deduplicate
+// on ID and order a domain's events by Sequence, which is gapless per domain.
+type EventEnvelope struct {
+	SchemaVersion int        `json:"schema_version" example:"1"`
+	ID            uuid.UUID  `json:"id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Type          string     `json:"type" example:"user.created"`
+	DomainID      uuid.UUID  `json:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Sequence      int64      `json:"sequence" example:"42"`
+	SubjectID     *uuid.UUID `json:"subject_id,omitempty" format:"uuid"`
+	// ImpersonatorID is set when a support engineer caused the event while impersonating a user
+	ImpersonatorID *uuid.UUID      `json:"impersonator_id,omitempty" format:"uuid"`
+	Time           time.Time       `json:"time"`
+	Data           json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+func NewEventEnvelope(event *entities.Event) *EventEnvelope {
+	return &EventEnvelope{
+		SchemaVersion:  EventSchemaVersion,
+		ID:             event.ID,
+		Type:           event.Type,
+		DomainID:       event.DomainID,
+		Sequence:       event.Sequence,
+		SubjectID:      event.SubjectID,
+		ImpersonatorID: event.ImpersonatorID,
+		Time:           event.CreatedAt.UTC(),
+		Data:           event.Payload,
+	}
+}
+
+// EventRelayService publishes the events queued in the outbox to the message broker.
+type EventRelayService interface {
+	RelayDue(ctx context.Context) (int, error)
+	RunRelay(ctx context.Context, interval time.Duration)
+}
+
+type eventRelayService struct {
+	outbox    repositories.EventOutboxRepository
+	publisher broker.Publisher
+	config    *config.BrokerConfig
+}
+
+func NewEventRelayService(outbox repositories.EventOutboxRepository, publisher broker.Publisher, cfg *config.BrokerConfig) EventRelayService {
+	return &eventRelayService{outbox: outbox, publisher: publisher, config: cfg}
+}
+
+// RelayDue publishes the outbox entries that are due and returns how many were published. Each
+// domain's events are published in order; after a failure the domain's remaining entries wait for
+// the retry too, so a consumer never sees a later event before an earlier one of the same domain.
+func (s *eventRelayService) RelayDue(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "EventRelayService.RelayDue")
+	defer span.End()
+
+	// The lease outlasts a publish, so an entry is not picked up again while it is in flight
+	due, err := s.outbox.ClaimDue(ctx, 2*s.config.PublishTimeout+time.Minute, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var domains []uuid.UUID
+	byDomain := make(map[uuid.UUID][]*repositories.OutboxEntry)
+	for _, entry := range due {
+		domainID := entry.Event.DomainID
+		if _, ok := byDomain[domainID]; !ok {
+			domains = append(domains, domainID)
+		}
+		byDomain[domainID] = append(byDomain[domainID], entry)
+	}
+
+	published := 0
+	for _, domainID := range domains {
+		published += s.relay(ctx, domainID, byDomain[domainID])
+	}
+	return published, nil
+}
+
+// relay publishes one domain's entries and records the outcome, returning how many were published.
+func (s *eventRelayService) relay(ctx context.Context, domainID uuid.UUID, entries []*repositories.OutboxEntry) int {
+	ids := make([]int64, len(entries))
+	messages := make([]broker.Message, len(entries))
+	attempts := 0
+	for i, entry := range entries {
+		body, err := json.Marshal(NewEventEnvelope(entry.Event))
+		if err != nil {
+			log.Printf("Failed to encode event %s for the broker: %v", entry.Event.ID, err)
+			return 0
+		}
+		ids[i] = entry.ID
+		messages[i] = broker.Message{
+			ID:       entry.Event.ID.String(),
+			DomainID: domainID.String(),
+			Type:     entry.Event.Type,
+			Body:     body,
+		}
+		attempts = max(attempts, entry.Attempts)
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, s.config.PublishTimeout)
+	err := s.publisher.Publish(publishCtx, messages)
+	cancel()
+
+	// Record the outcome even if shutdown cancelled ctx mid-publish, so the lease isn't left to expire
+	ctx = context.WithoutCancel(ctx)
+	if err != nil {
+		metrics.RecordBrokerEvents("retry", len(entries))
+		reason := err.Error()
+		if len(reason) > maxOutboxErrLen {
+			reason = reason[:maxOutboxErrLen]
+		}
+		if err := s.outbox.RecordFailure(ctx, domainID, ids, s.backoff(attempts+1), reason); err != nil {
+			log.Printf("Failed to record broker publish failure for domain %s: %v", domainID, err)
+		}
+		log.Printf("Failed to publish %d events of domain %s to the broker: %v", len(entries), domainID, err)
+		return 0
+	}
+
+	metrics.RecordBrokerEvents("published", len(entries))
+	if err := s.outbox.Delete(ctx, domainID, ids); err != nil {
+		// The events were published; they go out again once the lease expires, which consumers
+		// tolerate by deduplicating on the event ID
+		log.Printf("Failed to clear published events of domain %s from the outbox: %v", domainID, err)
+	}
+	return len(entries)
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (s *eventRelayService) backoff(attempts int) time.Duration {
+	wait := s.config.RetryBackoff
+	for i := 1; i < attempts && wait < s.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, s.config.MaxBackoff)
+}
+
+// RunRelay calls RelayDue every interval until ctx is cancelled.
+func (s *eventRelayService) RunRelay(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RelayDue(ctx); err != nil {
+				log.Printf("Event relay failed: %v", err)
+			}
+		}
+	}
+}
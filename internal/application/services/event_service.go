@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Event types recorded in the event log.
+const (
+	EventUserCreated           = "user.created"
+	EventUserUpdated           = "user.updated"
+	EventUserDeleted           = "user.deleted"
+	EventUserDisabled          = "user.disabled"
+	EventBreakGlassLogin       = "user.break_glass_login"
+	EventUserDeletionScheduled = "user.deletion_scheduled"
+	EventUserDeletionCancelled = "user.deletion_cancelled"
+	EventPIIViewed             = "user.pii_viewed"
+	EventUserImpersonated      = "user.impersonated"
+	EventRoleCreated           = "role.created"
+	EventRoleUpdated           = "role.updated"
+	EventRoleDeleted           = "role.deleted"
+	EventLoginSucceeded        = "login.succeeded"
+	EventLoginFailed           = "login.failed"
+	EventLoginChallenged       = "login.challenged"
+	EventLoginCodeSent         = "login.code_sent"
+
+	EventIntegrationUnhealthy = "integration.unhealthy"
+	EventIntegrationRecovered = "integration.recovered"
+)
+
+// EventTypes lists every event type, to validate the event filters of webhooks.
+var EventTypes = []string{
+	EventUserCreated, EventUserUpdated, EventUserDeleted, EventUserDisabled, EventBreakGlassLogin,
+	EventUserDeletionScheduled, EventUserDeletionCancelled, EventPIIViewed, EventUserImpersonated,
+	EventRoleCreated, EventRoleUpdated, EventRoleDeleted,
+	EventLoginSucceeded, EventLoginFailed, EventLoginChallenged, EventLoginCodeSent,
+	EventIntegrationUnhealthy, EventIntegrationRecovered,
+}
+
+const (
+	defaultEventPageSize = 100
+	maxEventPageSize     = 1000
+)
+
+type EventService interface {
+	Record(ctx context.Context, domainID uuid.UUID, eventType string, subjectID uuid.UUID, payload interface{}) error
+	Publish(ctx context.Context, domainID uuid.UUID, eventType string, subjectID uuid.UUID, payload interface{})
+	ListEvents(ctx context.Context, domainID uuid.UUID, since int64, limit int) (*EventPage, error)
+}
+
+// EventPage is one page of the event log. Pass NextSince as since to fetch the following page.
+type EventPage struct {
+	Events    []*entities.Event `json:"events"`
+	NextSince int64             `json:"next_since"`
+	HasMore   bool              `json:"has_more"`
+}
+
+type eventService struct {
+	repo       repositories.EventRepository
+	domainRepo repositories.DomainRepository
+}
+
+func NewEventService(repo repositories.EventRepository, domainRepo repositories.DomainRepository) EventService {
+	return &eventService{repo: repo, domainRepo: domainRepo}
+}
+
+// Record appends an event to the domain's log, queueing it for the domain's webhooks, and returns
+// any failure, for use inside a TxManager transaction where the event must be stored together with
+// the change. A nil subjectID records an event without a subject. Events recorded for a request
+// made with an impersonation token name the impersonating engineer.
+func (s *eventService) Record(ctx context.Context, domainID uuid.UUID, eventType string, subjectID uuid.UUID, payload interface{}) error {
+	ctx, span := tracer.Start(ctx, "EventService.Record")
+	defer span.End()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	event := &entities.Event{
+		DomainID: domainID,
+		Type:     eventType,
+		Payload:  data,
+	}
+	if subjectID != uuid.Nil {
+		event.SubjectID = &subjectID
+	}
+	if actor := ImpersonatorFrom(ctx); actor != nil {
+		event.ImpersonatorID = &actor.UserID
+	}
+	if err := s.repo.Append(ctx, event); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// Publish appends an event to the domain's log. It runs after the change has been committed and
+// a failure is only logged, so the change itself is never rolled back because of the event log.
+func (s *eventService) Publish(ctx context.Context, domainID uuid.UUID, eventType string, subjectID uuid.UUID, payload interface{}) {
+	if err := s.Record(ctx, domainID, eventType, subjectID, payload); err != nil {
+		log.Printf("Failed to publish event: %v", err)
+	}
+}
+
+// ListEvents returns events of the domain with a sequence greater than since, oldest first.
+func (s *eventService) ListEvents(ctx context.Context, domainID uuid.UUID, since int64, limit int) (*EventPage, error) {
+	ctx, span := tracer.Start(ctx, "EventService.ListEvents")
+	defer span.End()
+
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	if since < 0 {
+		since = 0
+	}
+	if limit <= 0 {
+		limit = defaultEventPageSize
+	}
+	if limit > maxEventPageSize {
+		limit = maxEventPageSize
+	}
+
+	// Fetch one extra event to learn whether another page follows
+	events, err := s.repo.ListSince(ctx, domainID, since, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &EventPage{Events: events, NextSince: since}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.HasMore = true
+	}
+	if len(page.Events) > 0 {
+		page.NextSince = page.Events[len(page.Events)-1].Sequence
+	} else {
+		page.Events = []*entities.Event{}
+	}
+	return page, nil
+}
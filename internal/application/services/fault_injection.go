@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/faults"
+)
+
+// FaultInjection describes the artificial failures this instance is injecting.
+type FaultInjection struct {
+	Active                     bool       `json:"active" example:"true"`
+	DBLatency                  string     `json:"db_latency" example:"500ms"`
+	DBLatencyRate              float64    `json:"db_latency_rate" example:"1"`
+	TokenValidationFailureRate float64    `json:"token_validation_failure_rate" example:"0.25"`
+	DeliveryFailureRate        float64    `json:"delivery_failure_rate" example:"0"`
+	ExpiresAt                  *time.Time `json:"expires_at,omitempty"`
+}
+
+type FaultInjectionService interface {
+	GetFaults(ctx context.Context) *FaultInjection
+	SetFaults(ctx context.Context, settings faults.Settings, duration time.Duration) (*FaultInjection, error)
+	ClearFaults(ctx context.Context)
+}
+
+type faultInjectionService struct {
+	config *config.FaultInjectionConfig
+}
+
+func NewFaultInjectionService(cfg *config.FaultInjectionConfig) FaultInjectionService {
+	return &faultInjectionService{config: cfg}
+}
+
+func (s *faultInjectionService) GetFaults(ctx context.Context) *FaultInjection {
+	settings := faults.Active()
+	if settings == nil {
+		return &FaultInjection{DBLatency: "0s"}
+	}
+	return &FaultInjection{
+		Active:                     true,
+		DBLatency:                  settings.DBLatency.String(),
+		DBLatencyRate:              settings.DBLatencyRate,
+		TokenValidationFailureRate: settings.TokenValidationFailureRate,
+		DeliveryFailureRate:        settings.DeliveryFailureRate,
+		ExpiresAt:                  &settings.ExpiresAt,
+	}
+}
+
+// SetFaults replaces the injected faults for the given duration, which defaults to and may not
+// exceed the configured maximum so forgotten faults wear off.
+func (s *faultInjectionService) SetFaults(ctx context.Context, settings faults.Settings, duration time.Duration) (*FaultInjection, error) {
+	switch {
+	case settings.DBLatency < 0:
+		return nil, domainerrors.Validation("db_latency must not be negative")
+	case !validRate(settings.DBLatencyRate), !validRate(settings.TokenValidationFailureRate), !validRate(settings.DeliveryFailureRate):
+		return nil, domainerrors.Validation("rates must be between 0 and 1")
+	case duration < 0 || duration > s.config.MaxDuration:
+		return nil, domainerrors.Validation("duration must be between 0 and %s", s.config.MaxDuration)
+	}
+	if duration == 0 {
+		duration = s.config.MaxDuration
+	}
+
+	settings.ExpiresAt = time.Now().Add(duration)
+	faults.Set(settings)
+	return s.GetFaults(ctx), nil
+}
+
+func (s *faultInjectionService) ClearFaults(ctx context.Context) {
+	faults.Clear()
+}
+
+func validRate(rate float64) bool {
+	return rate >= 0 && rate <= 1
+}
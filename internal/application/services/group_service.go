@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+type GroupService interface {
+	GetGroupByID(ctx context.Context, id uuid.UUID) (*entities.Group, error)
+	ListGroups(ctx context.Context, domainID uuid.UUID) ([]*entities.Group, error)
+	CreateGroup(ctx context.Context, domainID uuid.UUID, name, description string) (*entities.Group, error)
+	UpdateGroup(ctx context.Context, id uuid.UUID, name, description string) (*entities.Group, error)
+	DeleteGroup(ctx context.Context, id uuid.UUID) error
+	ListMembers(ctx context.Context, groupID uuid.UUID) ([]*entities.User, error)
+	ListUserGroups(ctx context.Context, userID uuid.UUID) ([]*entities.Group, error)
+	AddMember(ctx context.Context, groupID, userID uuid.UUID) error
+	RemoveMember(ctx context.Context, groupID, userID uuid.UUID) error
+	ListRoles(ctx context.Context, groupID uuid.UUID) ([]*entities.Role, error)
+	AddRole(ctx context.Context, groupID, roleID uuid.UUID) error
+	RemoveRole(ctx context.Context, groupID, roleID uuid.UUID) error
+}
+
+type groupService struct {
+	repo       repositories.GroupRepository
+	userRepo   repositories.UserRepository
+	roleRepo   repositories.RoleRepository
+	domainRepo repositories.DomainRepository
+}
+
+func NewGroupService(repo repositories.GroupRepository, userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, domainRepo repositories.DomainRepository) GroupService {
+	return &groupService{
+		repo:       repo,
+		userRepo:   userRepo,
+		roleRepo:   roleRepo,
+		domainRepo: domainRepo,
+	}
+}
+
+func (s *groupService) GetGroupByID(ctx context.Context, id uuid.UUID) (*entities.Group, error) {
+	group, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "group not found")
+	}
+	return group, nil
+}
+
+func (s *groupService) ListGroups(ctx context.Context, domainID uuid.UUID) ([]*entities.Group, error) {
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	return s.repo.GetByDomainID(ctx, domainID)
+}
+
+func (s *groupService) CreateGroup(ctx context.Context, domainID uuid.UUID, name, description string) (*entities.Group, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domainerrors.Validation("group name is required")
+	}
+
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	if _, err := s.repo.GetByName(ctx, domainID, name); err == nil {
+		return nil, domainerrors.Conflict("group name is already used in this domain").WithCode("group_name_taken")
+	}
+
+	group := &entities.Group{
+		DomainID:    domainID,
+		Name:        name,
+		Description: strings.TrimSpace(description),
+	}
+	if err := s.repo.Create(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (s *groupService) UpdateGroup(ctx context.Context, id uuid.UUID, name, description string) (*entities.Group, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domainerrors.Validation("group name is required")
+	}
+
+	group, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domainerrors.NotFound("group not found")
+	}
+
+	if existing, err := s.repo.GetByName(ctx, group.DomainID, name); err == nil && existing.ID != group.ID {
+		return nil, domainerrors.Conflict("group name is already used in this domain").WithCode("group_name_taken")
+	}
+
+	group.Name = name
+	group.Description = strings.TrimSpace(description)
+	if err := s.repo.Update(ctx, group); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *groupService) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	group, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domainerrors.NotFound("group not found")
+	}
+	members, err := s.repo.ListMembers(ctx, group.DomainID, group.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.revokeMemberSessions(ctx, members)
+}
+
+func (s *groupService) ListMembers(ctx context.Context, groupID uuid.UUID) ([]*entities.User, error) {
+	group, err := s.repo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, domainerrors.NotFound("group not found")
+	}
+	return s.repo.ListMembers(ctx, group.DomainID, group.ID)
+}
+
+// ListUserGroups returns the groups the user is a member of.
+func (s *groupService) ListUserGroups(ctx context.Context, userID uuid.UUID) ([]*entities.Group, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+	return s.repo.GetByUserID(ctx, user.DomainID, user.ID)
+}
+
+func (s *groupService) AddMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	group, err := s.repo.GetByID(ctx, groupID)
+	if err != nil {
+		return domainerrors.NotFound("group not found")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domainerrors.NotFound("user not found")
+	}
+	if user.DomainID != group.DomainID {
+		return domainerrors.Validation("user belongs to a different domain than the group")
+	}
+
+	return s.repo.AddMember(ctx, group.DomainID, group.ID, user.ID)
+}
+
+func (s *groupService) RemoveMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	group, err := s.repo.GetByID(ctx, groupID)
+	if err != nil {
+		return domainerrors.NotFound("group not found")
+	}
+
+	if err := s.repo.RemoveMember(ctx, group.DomainID, group.ID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domainerrors.NotFound("user is not a member of this group")
+		}
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.revokeMemberSessions(ctx, []*entities.User{user})
+}
+
+func (s *groupService) ListRoles(ctx context.Context, groupID uuid.UUID) ([]*entities.Role, error) {
+	group, err := s.repo.GetByID(ctx, groupID)
+	if err != nil {
+		return nil, domainerrors.NotFound("group not found")
+	}
+
+	roleIDs, err := s.repo.ListRoleIDs(ctx, group.DomainID, group.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*entities.Role, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		role, err := s.roleRepo.GetByID(ctx, roleID)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func (s *groupService) AddRole(ctx context.Context, groupID, roleID uuid.UUID) error {
+	group, err := s.repo.GetByID(ctx, groupID)
+	if err != nil {
+		return domainerrors.NotFound("group not found")
+	}
+
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return domainerrors.NotFound("role not found")
+	}
+	if role.DomainID != group.DomainID {
+		return domainerrors.Validation("role belongs to a different domain than the group")
+	}
+
+	return s.repo.AddRole(ctx, group.DomainID, group.ID, role.ID)
+}
+
+func (s *groupService) RemoveRole(ctx context.Context, groupID, roleID uuid.UUID) error {
+	group, err := s.repo.GetByID(ctx, groupID)
+	if err != nil {
+		return domainerrors.NotFound("group not found")
+	}
+
+	if err := s.repo.RemoveRole(ctx, group.DomainID, group.ID, roleID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domainerrors.NotFound("role is not assigned to this group")
+		}
+		return err
+	}
+
+	members, err := s.repo.ListMembers(ctx, group.DomainID, group.ID)
+	if err != nil {
+		return err
+	}
+	return s.revokeMemberSessions(ctx, members)
+}
+
+// revokeMemberSessions revokes the tokens of users who lost a group, and the roles it gives,
+// since tokens carry the groups they were issued with.
+func (s *groupService) revokeMemberSessions(ctx context.Context, members []*entities.User) error {
+	for _, member := range members {
+		if err := revokeSessions(ctx, s.userRepo, member); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"backend/internal/infrastructure/cache"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+)
+
+// Features a dependency failure can impact.
+const (
+	FeatureLogin           = "login"
+	FeatureTokenValidation = "token_validation"
+	FeatureAdminReads      = "admin_reads"
+	FeatureAdminWrites     = "admin_writes"
+)
+
+// Impacts on a feature, from least to most severe. Partial means unavailable for the domains of
+// an affected residency shard only.
+const (
+	ImpactSlow        = "slow"
+	ImpactPartial     = "partial"
+	ImpactUnavailable = "unavailable"
+)
+
+var impactSeverity = map[string]int{ImpactSlow: 1, ImpactPartial: 2, ImpactUnavailable: 3}
+
+// Overall health statuses. Only unavailable makes the instance not ready.
+const (
+	HealthOK          = "ok"
+	HealthDegraded    = "degraded"
+	HealthUnavailable = "unavailable"
+)
+
+// DependencyStatus is the probed state of one dependency.
+type DependencyStatus struct {
+	Name   string `json:"name" example:"database:default"`
+	Status string `json:"status" enums:"ok,down,read_only" example:"ok"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HealthReport describes the dependencies and the features their failures impact.
+type HealthReport struct {
+	Status       string             `json:"status" enums:"ok,degraded,unavailable" example:"degraded"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+	Degradation  map[string]string  `json:"degradation"` // impacted feature to slow, partial or unavailable
+	CheckedAt    time.Time          `json:"checked_at"`
+}
+
+// Header renders the impacted features for the Degradation response header, e.g.
+// "admin_writes=unavailable, login=slow", or "" when nothing is impacted.
+func (r *HealthReport) Header() string {
+	features := make([]string, 0, len(r.Degradation))
+	for feature, impact := range r.Degradation {
+		features = append(features, feature+"="+impact)
+	}
+	sort.Strings(features)
+	return strings.Join(features, ", ")
+}
+
+func (r *HealthReport) add(dependency DependencyStatus, impact string, features ...string) {
+	r.Dependencies = append(r.Dependencies, dependency)
+	if impact == "" {
+		return
+	}
+	for _, feature := range features {
+		if impactSeverity[impact] > impactSeverity[r.Degradation[feature]] {
+			r.Degradation[feature] = impact
+		}
+	}
+}
+
+type HealthService interface {
+	Check(ctx context.Context) *HealthReport
+	Current() *HealthReport
+	RunHealthProbes(ctx context.Context, interval time.Duration)
+}
+
+type healthService struct {
+	repo          repositories.HealthRepository
+	cache         cache.Cache // nil when caching is off
+	revokedTokens repositories.RevokedTokenRepository
+	config        *config.HealthConfig
+	current       atomic.Pointer[HealthReport]
+}
+
+func NewHealthService(repo repositories.HealthRepository, lookupCache cache.Cache, revokedTokens repositories.RevokedTokenRepository, cfg *config.HealthConfig) HealthService {
+	return &healthService{repo: repo, cache: lookupCache, revokedTokens: revokedTokens, config: cfg}
+}
+
+// Check probes every dependency now and keeps the report for Current.
+func (s *healthService) Check(ctx context.Context) *HealthReport {
+	ctx, span := tracer.Start(ctx, "HealthService.Check")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.ProbeTimeout)
+	defer cancel()
+
+	report := &HealthReport{Status: HealthOK, Dependencies: []DependencyStatus{}, Degradation: map[string]string{}, CheckedAt: time.Now().UTC()}
+	primaryDown := false
+	for _, db := range s.repo.ProbeDatabases(ctx) {
+		dependency := DependencyStatus{Name: "database:" + db.Residency, Status: "ok"}
+		impact := ImpactPartial
+		if db.Primary {
+			impact = ImpactUnavailable
+		}
+		switch {
+		case !db.Reachable:
+			dependency.Status, dependency.Error = "down", db.Err.Error()
+			primaryDown = primaryDown || db.Primary
+			report.add(dependency, impact, FeatureLogin, FeatureTokenValidation, FeatureAdminReads, FeatureAdminWrites)
+		case db.ReadOnly:
+			dependency.Status = "read_only"
+			report.add(dependency, impact, FeatureAdminWrites)
+		default:
+			report.add(dependency, "")
+		}
+	}
+
+	// Cached role and domain lookups fall back to the database
+	if s.cache != nil {
+		dependency := DependencyStatus{Name: "cache", Status: "ok"}
+		if _, _, err := s.cache.Get(ctx, "health:probe"); err != nil {
+			dependency.Status, dependency.Error = "down", err.Error()
+			report.add(dependency, ImpactSlow, FeatureLogin, FeatureTokenValidation, FeatureAdminReads)
+		} else {
+			report.add(dependency, "")
+		}
+	}
+
+	// Token validation fails closed while revocations can't be checked
+	dependency := DependencyStatus{Name: "revocation_store", Status: "ok"}
+	if _, err := s.revokedTokens.IsRevoked(ctx, "health-probe"); err != nil {
+		dependency.Status, dependency.Error = "down", err.Error()
+		report.add(dependency, ImpactUnavailable, FeatureTokenValidation)
+	} else {
+		report.add(dependency, "")
+	}
+
+	switch {
+	case primaryDown:
+		report.Status = HealthUnavailable
+	case len(report.Degradation) > 0:
+		report.Status = HealthDegraded
+	}
+	s.remember(report)
+	return report
+}
+
+// Current returns the latest report, or nil before the first probe.
+func (s *healthService) Current() *HealthReport {
+	return s.current.Load()
+}
+
+// remember keeps the report and logs when the degradation changes.
+func (s *healthService) remember(report *HealthReport) {
+	previous := s.current.Swap(report)
+	was := ""
+	if previous != nil {
+		was = previous.Header()
+	}
+	if now := report.Header(); now != was {
+		if now == "" {
+			log.Println("Dependencies recovered; no features are degraded")
+		} else {
+			log.Printf("Degraded features: %s", now)
+		}
+	}
+}
+
+// RunHealthProbes calls Check now and then every interval until ctx is cancelled.
+func (s *healthService) RunHealthProbes(ctx context.Context, interval time.Duration) {
+	s.Check(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Check(ctx)
+		}
+	}
+}
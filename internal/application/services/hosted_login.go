@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// HostedLoginPage is one sign-in request to the hosted login page at /login.
+type HostedLoginPage struct {
+	Domain      *entities.Domain
+	RedirectURI string
+	State       string
+	// Client is the app asking for profile fields and Fields the fields it asks for; Client is nil
+	// when the app asks for none
+	Client *entities.APIKey
+	Fields []string
+}
+
+// HostedLoginService backs the hosted login page for domains without their own sign-in frontend.
+// Credentials are checked by AuthService; this service validates where the page may send users
+// and records the profile fields they agree to share.
+type HostedLoginService interface {
+	PreparePage(ctx context.Context, domainID uuid.UUID, redirectURI, state string, clientID *uuid.UUID, fields []string) (*HostedLoginPage, error)
+	Complete(ctx context.Context, page *HostedLoginPage, login *LoginResponse, shared []string) (string, error)
+	AllowsOrigin(ctx context.Context, domainID uuid.UUID, origin string) (bool, error)
+}
+
+type hostedLoginService struct {
+	domainRepo repositories.DomainRepository
+	apiKeyRepo repositories.APIKeyRepository
+	consents   ConsentService
+}
+
+func NewHostedLoginService(domainRepo repositories.DomainRepository, apiKeyRepo repositories.APIKeyRepository, consents ConsentService) HostedLoginService {
+	return &hostedLoginService{domainRepo: domainRepo, apiKeyRepo: apiKeyRepo, consents: consents}
+}
+
+// PreparePage checks a sign-in request: redirectURI must be one of the domain's branding redirect
+// URIs, and a client asking for fields must be an active API key of the domain.
+func (s *hostedLoginService) PreparePage(ctx context.Context, domainID uuid.UUID, redirectURI, state string, clientID *uuid.UUID, fields []string) (*HostedLoginPage, error) {
+	ctx, span := tracer.Start(ctx, "HostedLoginService.PreparePage")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	if !slices.Contains(domain.Branding.RedirectURIs, redirectURI) {
+		return nil, domainerrors.Validation("redirect_uri is not registered for this domain")
+	}
+	page := &HostedLoginPage{Domain: domain, RedirectURI: redirectURI, State: state}
+
+	if clientID == nil || len(fields) == 0 {
+		return page, nil
+	}
+	client, err := s.apiKeyRepo.GetByID(ctx, *clientID)
+	if err != nil || client.RevokedAt != nil || client.DomainID != domainID {
+		return nil, domainerrors.Validation("client_id is not an active client of this domain")
+	}
+	for _, field := range fields {
+		if !slices.Contains(entities.ProfileFields, field) {
+			return nil, domainerrors.Validation("unknown profile field %q", field)
+		}
+		if !slices.Contains(page.Fields, field) {
+			page.Fields = append(page.Fields, field)
+		}
+	}
+	page.Client = client
+	return page, nil
+}
+
+// Complete records consent for the requested fields the user agreed to share and returns the URL
+// to send the browser back to, with the access token in the fragment so it stays out of server
+// logs and Referer headers.
+func (s *hostedLoginService) Complete(ctx context.Context, page *HostedLoginPage, login *LoginResponse, shared []string) (string, error) {
+	ctx, span := tracer.Start(ctx, "HostedLoginService.Complete")
+	defer span.End()
+
+	if page.Client != nil {
+		granted := make([]string, 0, len(shared))
+		for _, field := range shared {
+			if slices.Contains(page.Fields, field) {
+				granted = append(granted, field)
+			}
+		}
+		if _, err := s.consents.GrantConsent(ctx, login.User.ID, page.Client.ID, granted); err != nil {
+			return "", err
+		}
+	}
+
+	fragment := url.Values{"access_token": {login.AccessToken}, "token_type": {"Bearer"}}
+	if page.State != "" {
+		fragment.Set("state", page.State)
+	}
+	return page.RedirectURI + "#" + fragment.Encode(), nil
+}
+
+// AllowsOrigin reports whether origin, a scheme://host as browsers send it in the Origin header,
+// serves one of the domain's branding redirect URIs.
+func (s *hostedLoginService) AllowsOrigin(ctx context.Context, domainID uuid.UUID, origin string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "HostedLoginService.AllowsOrigin")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return false, domainerrors.NotFound("domain not found")
+	}
+	for _, redirectURI := range domain.Branding.RedirectURIs {
+		target, err := url.Parse(redirectURI)
+		if err == nil && target.Host != "" && strings.EqualFold(target.Scheme+"://"+target.Host, origin) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// tokenPurposeSession marks the hosted login session token kept in a cookie, which can only be
+// exchanged for access tokens at /auth/session/refresh
+const tokenPurposeSession = "session"
+
+// HostedSession is the session started by a sign-in on the hosted login page.
+type HostedSession struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+func errNoSession() error {
+	return domainerrors.Unauthorized("no active session").WithCode("login_required")
+}
+
+// StartSession issues a hosted login session for the user, lasting the domain's refresh_ttl_minutes
+// when set. Break-glass sessions last no longer than their access tokens.
+func (s *authService) StartSession(ctx context.Context, userID uuid.UUID) (*HostedSession, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.StartSession")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+
+	domain, err := s.domainRepo.GetByID(ctx, user.DomainID)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	ttl := refreshTTL(domain, s.session.TTL)
+	if user.BreakGlass && s.breakGlass.SessionTTL < ttl {
+		ttl = s.breakGlass.SessionTTL
+	}
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := TokenClaims{
+		UserID:     user.ID,
+		DomainID:   user.DomainID,
+		Username:   user.Username,
+		RoleID:     user.RoleID,
+		BreakGlass: user.BreakGlass,
+		Purpose:    tokenPurposeSession,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(tokenIssueTime(user, now)),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "nusarithm-iam",
+			Subject:   user.ID.String(),
+			ID:        uuid.NewString(),
+		},
+	}
+	token, err := s.keys.Sign(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session token: %w", err)
+	}
+	return &HostedSession{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// RefreshSession issues a new access token for the session of the domain. A missing, expired or
+// revoked session returns Unauthorized with code login_required; sessions end with the user's
+// other tokens, e.g. when the password changes.
+func (s *authService) RefreshSession(ctx context.Context, domainID uuid.UUID, sessionToken string) (*LoginResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.RefreshSession")
+	ctx = tenancy.WithDomain(ctx, domainID)
+	defer span.End()
+
+	claims, err := s.parseToken(sessionToken)
+	if err != nil || claims.Purpose != tokenPurposeSession || claims.DomainID != domainID {
+		return nil, errNoSession()
+	}
+	if err := s.checkRevoked(ctx, claims); err != nil {
+		return nil, sessionError(err)
+	}
+	if err := s.checkSession(ctx, claims); err != nil {
+		return nil, sessionError(err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, errNoSession()
+	}
+	return s.issueLogin(ctx, user, nil, nil)
+}
+
+// sessionError reports rejected sessions as login_required and passes other errors through.
+func sessionError(err error) error {
+	if errors.Is(err, domainerrors.ErrUnauthorized) {
+		return errNoSession()
+	}
+	return err
+}
+
+// EndSession revokes a hosted login session. Invalid or expired sessions are ignored.
+func (s *authService) EndSession(ctx context.Context, sessionToken string) error {
+	ctx, span := tracer.Start(ctx, "AuthService.EndSession")
+	defer span.End()
+
+	claims, err := s.parseToken(sessionToken)
+	if err != nil || claims.Purpose != tokenPurposeSession || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+	return s.revokedTokens.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+)
+
+// maxIdempotencyKeyLength is the longest Idempotency-Key accepted.
+const maxIdempotencyKeyLength = 255
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, scope, key, requestHash string) (*repositories.IdempotencyRecord, error)
+	Complete(ctx context.Context, scope, key string, status int, contentType string, body []byte) error
+	Release(ctx context.Context, scope, key string) error
+	RunSweep(ctx context.Context, interval time.Duration)
+}
+
+type idempotencyService struct {
+	repo   repositories.IdempotencyKeyRepository
+	config *config.IdempotencyConfig
+}
+
+func NewIdempotencyService(repo repositories.IdempotencyKeyRepository, cfg *config.IdempotencyConfig) IdempotencyService {
+	return &idempotencyService{repo: repo, config: cfg}
+}
+
+// Begin claims the key for a request. It returns nil when the request should run, or the stored
+// response of an earlier request with the key to replay. A key still held by a running request, or
+// used before for a different request, is refused.
+func (s *idempotencyService) Begin(ctx context.Context, scope, key, requestHash string) (*repositories.IdempotencyRecord, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, domainerrors.Validation("Idempotency-Key must be 1 to %d characters", maxIdempotencyKeyLength).WithCode("invalid_idempotency_key")
+	}
+
+	now := time.Now()
+	existing, err := s.repo.Reserve(ctx, &repositories.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   now.Add(s.config.TTL),
+	}, now.Add(-s.config.LockTimeout))
+	if err != nil || existing == nil {
+		return nil, err
+	}
+	if existing.RequestHash != requestHash {
+		return nil, domainerrors.Validation("Idempotency-Key was already used for a different request").WithCode("idempotency_key_reused")
+	}
+	if existing.StatusCode == 0 {
+		return nil, domainerrors.Conflict("a request with this Idempotency-Key is still in progress; retry later").WithCode("idempotency_key_in_progress")
+	}
+	return existing, nil
+}
+
+// Complete stores the response of the request holding the key, for retries to replay.
+func (s *idempotencyService) Complete(ctx context.Context, scope, key string, status int, contentType string, body []byte) error {
+	return s.repo.Complete(ctx, &repositories.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		StatusCode:  status,
+		ContentType: contentType,
+		Body:        body,
+	})
+}
+
+// Release frees the key of a request that failed, so a retry runs again.
+func (s *idempotencyService) Release(ctx context.Context, scope, key string) error {
+	return s.repo.Release(ctx, scope, key)
+}
+
+// RunSweep removes expired keys every interval until ctx is cancelled.
+func (s *idempotencyService) RunSweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.repo.DeleteExpired(ctx, time.Now())
+			if err != nil {
+				log.Printf("Idempotency key sweep failed: %v", err)
+			} else if removed > 0 {
+				log.Printf("Idempotency key sweep removed %d expired key(s)", removed)
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const maxImpersonationReasonLength = 500
+
+// TokenActor is the act claim of an impersonation token: the support engineer behind it.
+type TokenActor struct {
+	UserID   uuid.UUID `json:"sub" format:"uuid" example:"5b1c9d2e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"`
+	Username string    `json:"username" example:"support.alice"`
+}
+
+// ImpersonationResponse carries a token acting as the user. It can't be used on the admin API, to
+// start another impersonation, or to change the user's own account, sessions or consents.
+type ImpersonationResponse struct {
+	AccessToken  string       `json:"access_token"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+	User         *UserProfile `json:"user"`
+	Impersonator *TokenActor  `json:"impersonator"`
+}
+
+// Impersonation is the payload of EventUserImpersonated.
+type Impersonation struct {
+	ImpersonatorID       uuid.UUID `json:"impersonator_id"`
+	ImpersonatorUsername string    `json:"impersonator_username"`
+	Reason               string    `json:"reason"`
+	ExpiresAt            time.Time `json:"expires_at"`
+}
+
+type impersonatorKey struct{}
+
+// WithImpersonator attaches the support engineer behind a request made with an impersonation
+// token to ctx. Events recorded with ctx name them.
+func WithImpersonator(ctx context.Context, actor *TokenActor) context.Context {
+	return context.WithValue(ctx, impersonatorKey{}, actor)
+}
+
+// ImpersonatorFrom returns the engineer attached with WithImpersonator, or nil.
+func ImpersonatorFrom(ctx context.Context) *TokenActor {
+	actor, _ := ctx.Value(impersonatorKey{}).(*TokenActor)
+	return actor
+}
+
+// MaybeImpersonation reports whether a token claims to be an impersonation token, without
+// verifying it, so requests with ordinary tokens skip validating them twice.
+func MaybeImpersonation(tokenString string) bool {
+	var claims TokenClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, &claims); err != nil {
+		return false
+	}
+	return claims.Act != nil
+}
+
+// Impersonate issues a short-lived token acting as a user of the impersonator's domain, for a
+// support engineer holding the impersonate permission. The token carries the engineer in its act
+// claim and the start is recorded in the user's event log.
+func (s *authService) Impersonate(ctx context.Context, impersonator *TokenClaims, userID uuid.UUID, reason string) (*ImpersonationResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Impersonate")
+	defer span.End()
+
+	if impersonator.Act != nil {
+		return nil, domainerrors.Forbidden("an impersonation token cannot start another impersonation").WithCode("impersonation_nested")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, domainerrors.Validation("reason is required")
+	}
+	if len(reason) > maxImpersonationReasonLength {
+		return nil, domainerrors.Validation("reason must be at most %d characters", maxImpersonationReasonLength)
+	}
+
+	effective, err := s.GetEffectivePermissions(ctx, impersonator.UserID)
+	if err != nil {
+		return nil, domainerrors.Unauthorized("invalid token")
+	}
+	// A token narrowed with a scope only impersonates when impersonate is among its scopes
+	if !slices.Contains(effective.Permissions, entities.PermissionImpersonate) || !impersonator.HasScope(entities.PermissionImpersonate) {
+		return nil, domainerrors.Forbidden("%s permission required", entities.PermissionImpersonate).WithCode("impersonate_required")
+	}
+
+	// Users of other domains are reported not found, like missing ones
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user.DomainID != impersonator.DomainID {
+		return nil, domainerrors.NotFound("user not found")
+	}
+	if user.ID == impersonator.UserID {
+		return nil, domainerrors.Validation("you cannot impersonate yourself")
+	}
+	if user.BreakGlass {
+		return nil, domainerrors.Forbidden("break-glass accounts cannot be impersonated")
+	}
+	if user.IsService() {
+		return nil, domainerrors.Forbidden("service accounts cannot be impersonated")
+	}
+	if accountDisabled(user, time.Now()) {
+		return nil, domainerrors.Forbidden("account is disabled")
+	}
+
+	domain, err := s.domainRepo.GetByID(ctx, user.DomainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain: %w", err)
+	}
+	if err := domainAccessError(user, domain); err != nil {
+		return nil, err
+	}
+	profile, err := s.buildUserProfile(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user profile: %w", err)
+	}
+
+	actor := &TokenActor{UserID: impersonator.UserID, Username: impersonator.Username}
+	token, expiresAt, err := s.generateToken(ctx, user, domain, profile, actor, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	s.events.Publish(WithImpersonator(ctx, actor), user.DomainID, EventUserImpersonated, user.ID, &Impersonation{
+		ImpersonatorID:       impersonator.UserID,
+		ImpersonatorUsername: impersonator.Username,
+		Reason:               reason,
+		ExpiresAt:            expiresAt.UTC(),
+	})
+	return &ImpersonationResponse{AccessToken: token, ExpiresAt: expiresAt, User: profile, Impersonator: actor}, nil
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/mailer"
+	"backend/internal/infrastructure/netguard"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+type IntegrationHealthService interface {
+	ListIntegrations(ctx context.Context, domainID uuid.UUID) ([]*entities.IntegrationHealth, error)
+	CheckAll(ctx context.Context) (int, error)
+	RunHealthChecks(ctx context.Context, interval time.Duration)
+}
+
+// integrationCheck probes one kind of tenant integration.
+type integrationCheck struct {
+	kind       string
+	configured func(ctx context.Context) ([]uuid.UUID, error) // domains with the integration enabled
+	enabledFor func(ctx context.Context, domainID uuid.UUID) (bool, error)
+	check      func(ctx context.Context, domainID uuid.UUID) error
+}
+
+type integrationHealthService struct {
+	repo       repositories.IntegrationHealthRepository
+	domainRepo repositories.DomainRepository
+	checks     []integrationCheck
+	events     EventService
+	mailer     mailer.Mailer
+	cfg        *config.IntegrationHealthConfig
+}
+
+// webhookHealthDeliveries is how many of a webhook's latest deliveries its health check reads.
+const webhookHealthDeliveries = 20
+
+// NewIntegrationHealthService checks the domains' own SMTP senders and their webhooks. Alerts go
+// through the platform mailer since the integration being reported may be the domain's mail sender.
+func NewIntegrationHealthService(repo repositories.IntegrationHealthRepository, domainRepo repositories.DomainRepository, mailSettingsRepo repositories.DomainMailSettingsRepository, webhookRepo repositories.WebhookRepository, events EventService, platformMailer mailer.Mailer, guard *netguard.Guard, cfg *config.IntegrationHealthConfig) IntegrationHealthService {
+	smtpCheck := integrationCheck{
+		kind:       entities.IntegrationSMTP,
+		configured: mailSettingsRepo.ListEnabledDomainIDs,
+		enabledFor: func(ctx context.Context, domainID uuid.UUID) (bool, error) {
+			settings, err := mailSettingsRepo.GetByDomainID(ctx, domainID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return false, nil
+			}
+			return err == nil && settings.Enabled, err
+		},
+		check: func(ctx context.Context, domainID uuid.UUID) error {
+			settings, err := mailSettingsRepo.GetByDomainID(ctx, domainID)
+			if err != nil {
+				return err
+			}
+			return mailer.Verify(ctx, mailConfigFor(settings), guard.Dialer(domainSMTPTimeout))
+		},
+	}
+
+	// Webhooks aren't probed; an endpoint is failing when the latest attempt to deliver to it failed
+	webhookCheck := integrationCheck{
+		kind:       entities.IntegrationWebhooks,
+		configured: webhookRepo.ListEnabledDomainIDs,
+		enabledFor: func(ctx context.Context, domainID uuid.UUID) (bool, error) {
+			webhooks, err := webhookRepo.ListByDomain(ctx, domainID)
+			if err != nil {
+				return false, err
+			}
+			return slices.ContainsFunc(webhooks, func(w *entities.Webhook) bool { return w.Enabled }), nil
+		},
+		check: func(ctx context.Context, domainID uuid.UUID) error {
+			webhooks, err := webhookRepo.ListByDomain(ctx, domainID)
+			if err != nil {
+				return err
+			}
+			var failing []string
+			for _, webhook := range webhooks {
+				if !webhook.Enabled {
+					continue
+				}
+				deliveries, err := webhookRepo.ListDeliveries(ctx, domainID, webhook.ID, "", webhookHealthDeliveries)
+				if err != nil {
+					return err
+				}
+				if last := lastAttempted(deliveries); last != nil && last.Error != nil {
+					failing = append(failing, fmt.Sprintf("%s: %s", webhook.URL, *last.Error))
+				}
+			}
+			if len(failing) > 0 {
+				return errors.New(strings.Join(failing, "; "))
+			}
+			return nil
+		},
+	}
+
+	return &integrationHealthService{
+		repo:       repo,
+		domainRepo: domainRepo,
+		checks:     []integrationCheck{smtpCheck, webhookCheck},
+		events:     events,
+		mailer:     platformMailer,
+		cfg:        cfg,
+	}
+}
+
+// lastAttempted returns the delivery attempted most recently, or nil if none was attempted yet.
+func lastAttempted(deliveries []*entities.WebhookDelivery) *entities.WebhookDelivery {
+	var last *entities.WebhookDelivery
+	for _, delivery := range deliveries {
+		if delivery.LastAttemptAt != nil && (last == nil || delivery.LastAttemptAt.After(*last.LastAttemptAt)) {
+			last = delivery
+		}
+	}
+	return last
+}
+
+// ListIntegrations returns the health of each integration the domain has enabled; integrations
+// not checked yet have status unknown.
+func (s *integrationHealthService) ListIntegrations(ctx context.Context, domainID uuid.UUID) ([]*entities.IntegrationHealth, error) {
+	ctx, span := tracer.Start(ctx, "IntegrationHealthService.ListIntegrations")
+	defer span.End()
+
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	integrations := make([]*entities.IntegrationHealth, 0, len(s.checks))
+	for _, check := range s.checks {
+		enabled, err := check.enabledFor(ctx, domainID)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			continue
+		}
+
+		health, err := s.repo.Get(ctx, domainID, check.kind)
+		if errors.Is(err, sql.ErrNoRows) {
+			health = &entities.IntegrationHealth{DomainID: domainID, Kind: check.kind, Status: entities.IntegrationUnknown}
+		} else if err != nil {
+			return nil, err
+		}
+		integrations = append(integrations, health)
+	}
+	return integrations, nil
+}
+
+// CheckAll runs every health check of every domain and returns how many integrations are
+// unhealthy. A failure to record one result doesn't stop the others.
+func (s *integrationHealthService) CheckAll(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "IntegrationHealthService.CheckAll")
+	defer span.End()
+
+	unhealthy := 0
+	for _, check := range s.checks {
+		domainIDs, err := check.configured(ctx)
+		if err != nil {
+			return unhealthy, err
+		}
+		for _, domainID := range domainIDs {
+			health, err := s.runCheck(ctx, check, domainID)
+			if err != nil {
+				log.Printf("Failed to record %s health of domain %s: %v", check.kind, domainID, err)
+				continue
+			}
+			if health.Status == entities.IntegrationUnhealthy {
+				unhealthy++
+			}
+		}
+	}
+	return unhealthy, nil
+}
+
+func (s *integrationHealthService) runCheck(ctx context.Context, check integrationCheck, domainID uuid.UUID) (*entities.IntegrationHealth, error) {
+	previous, err := s.repo.Get(ctx, domainID, check.kind)
+	if errors.Is(err, sql.ErrNoRows) {
+		previous = &entities.IntegrationHealth{}
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	health := &entities.IntegrationHealth{
+		DomainID:      domainID,
+		Kind:          check.kind,
+		Status:        entities.IntegrationHealthy,
+		LastCheckedAt: &now,
+		LastSuccessAt: previous.LastSuccessAt,
+	}
+	if checkErr := check.check(ctx, domainID); checkErr != nil {
+		message := checkErr.Error()
+		health.Status = entities.IntegrationUnhealthy
+		health.ConsecutiveFailures = previous.ConsecutiveFailures + 1
+		health.LastError = &message
+	} else {
+		health.LastSuccessAt = &now
+	}
+
+	if err := s.repo.Save(ctx, health); err != nil {
+		return nil, err
+	}
+
+	// Alert once when failures reach the threshold and once when an alerted integration recovers
+	threshold := max(s.cfg.AlertThreshold, 1)
+	switch {
+	case health.ConsecutiveFailures == threshold:
+		s.alert(ctx, EventIntegrationUnhealthy, health,
+			fmt.Sprintf("The %s integration of domain %s has failed %d consecutive health checks. Last error: %s",
+				health.Kind, domainID, health.ConsecutiveFailures, *health.LastError))
+	case health.Status == entities.IntegrationHealthy && previous.ConsecutiveFailures >= threshold:
+		s.alert(ctx, EventIntegrationRecovered, health,
+			fmt.Sprintf("The %s integration of domain %s is healthy again after %d failed health checks.",
+				health.Kind, domainID, previous.ConsecutiveFailures))
+	}
+	return health, nil
+}
+
+// alert records the event in the domain's event log and emails the configured addresses;
+// email failures are only logged.
+func (s *integrationHealthService) alert(ctx context.Context, eventType string, health *entities.IntegrationHealth, message string) {
+	log.Printf("ALERT: %s", message)
+	s.events.Publish(ctx, health.DomainID, eventType, health.DomainID, health)
+
+	subject := fmt.Sprintf("%s integration %s", health.Kind, health.Status)
+	for _, to := range s.cfg.AlertEmails {
+		if err := s.mailer.Send(ctx, to, subject, message); err != nil {
+			log.Printf("Failed to send integration alert to %s: %v", to, err)
+		}
+	}
+}
+
+// RunHealthChecks calls CheckAll every interval until ctx is cancelled.
+func (s *integrationHealthService) RunHealthChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			unhealthy, err := s.CheckAll(ctx)
+			if err != nil {
+				log.Printf("Integration health checks failed: %v", err)
+			} else if unhealthy > 0 {
+				log.Printf("Integration health checks found %d unhealthy integration(s)", unhealthy)
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// storedHealth keeps the latest health of each integration.
+type storedHealth struct {
+	repositories.IntegrationHealthRepository
+	health map[string]*entities.IntegrationHealth
+}
+
+func (s *storedHealth) Get(ctx context.Context, domainID uuid.UUID, kind string) (*entities.IntegrationHealth, error) {
+	health, ok := s.health[domainID.String()+kind]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	stored := *health
+	return &stored, nil
+}
+
+func (s *storedHealth) Save(ctx context.Context, health *entities.IntegrationHealth) error {
+	stored := *health
+	s.health[health.DomainID.String()+health.Kind] = &stored
+	return nil
+}
+
+// noMailSettings has no domain sending through its own sender.
+type noMailSettings struct {
+	repositories.DomainMailSettingsRepository
+}
+
+func (noMailSettings) ListEnabledDomainIDs(ctx context.Context) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func (noMailSettings) GetByDomainID(ctx context.Context, domainID uuid.UUID) (*entities.DomainMailSettings, error) {
+	return nil, sql.ErrNoRows
+}
+
+// oneWebhook is a domain's single enabled webhook and its deliveries.
+type oneWebhook struct {
+	repositories.WebhookRepository
+	webhook    *entities.Webhook
+	deliveries []*entities.WebhookDelivery
+}
+
+func (w *oneWebhook) ListEnabledDomainIDs(ctx context.Context) ([]uuid.UUID, error) {
+	return []uuid.UUID{w.webhook.DomainID}, nil
+}
+
+func (w *oneWebhook) ListByDomain(ctx context.Context, domainID uuid.UUID) ([]*entities.Webhook, error) {
+	return []*entities.Webhook{w.webhook}, nil
+}
+
+func (w *oneWebhook) ListDeliveries(ctx context.Context, domainID, webhookID uuid.UUID, status string, limit int) ([]*entities.WebhookDelivery, error) {
+	return w.deliveries, nil
+}
+
+func (w *oneWebhook) attempted(at time.Time, failure *string) {
+	w.deliveries = append(w.deliveries, &entities.WebhookDelivery{ID: uuid.New(), WebhookID: w.webhook.ID, LastAttemptAt: &at, Error: failure})
+}
+
+type recordedEvents struct {
+	EventService
+	types []string
+}
+
+func (e *recordedEvents) Publish(ctx context.Context, domainID uuid.UUID, eventType string, subjectID uuid.UUID, payload interface{}) {
+	e.types = append(e.types, eventType)
+}
+
+type sentAlerts struct {
+	subjects []string
+}
+
+func (m *sentAlerts) Send(ctx context.Context, to, subject, body string) error {
+	m.subjects = append(m.subjects, subject)
+	return nil
+}
+
+func TestWebhookIntegrationHealth(t *testing.T) {
+	ctx := context.Background()
+	domainID := uuid.New()
+	webhooks := &oneWebhook{webhook: &entities.Webhook{ID: uuid.New(), DomainID: domainID, URL: "https://hooks.acme.example.com/iam", Enabled: true}}
+	failure := "endpoint returned status 503"
+	webhooks.attempted(time.Now().Add(-time.Hour), nil)
+	webhooks.attempted(time.Now().Add(-time.Minute), &failure)
+
+	events, alerts := &recordedEvents{}, &sentAlerts{}
+	s := NewIntegrationHealthService(&storedHealth{health: map[string]*entities.IntegrationHealth{}}, fakeDomains{}, noMailSettings{}, webhooks,
+		events, alerts, nil, &config.IntegrationHealthConfig{AlertThreshold: 2, AlertEmails: []string{"ops@acme.example.com"}})
+
+	for range 2 {
+		if unhealthy, err := s.CheckAll(ctx); err != nil || unhealthy != 1 {
+			t.Fatalf("CheckAll() = %d, %v; want the webhooks unhealthy", unhealthy, err)
+		}
+	}
+	integrations, err := s.ListIntegrations(ctx, domainID)
+	if err != nil || len(integrations) != 1 {
+		t.Fatalf("ListIntegrations() = %v, %v; want the webhooks", integrations, err)
+	}
+	health := integrations[0]
+	if health.Kind != entities.IntegrationWebhooks || health.Status != entities.IntegrationUnhealthy || health.ConsecutiveFailures != 2 ||
+		health.LastError == nil || !strings.Contains(*health.LastError, webhooks.webhook.URL) {
+		t.Errorf("health = %+v; want the webhooks unhealthy after 2 checks, naming the endpoint", health)
+	}
+	if !slices.Equal(events.types, []string{EventIntegrationUnhealthy}) || len(alerts.subjects) != 1 {
+		t.Errorf("events %v and %d alert emails; want one unhealthy alert", events.types, len(alerts.subjects))
+	}
+
+	// A later delivery gets through
+	webhooks.attempted(time.Now(), nil)
+	if unhealthy, err := s.CheckAll(ctx); err != nil || unhealthy != 0 {
+		t.Fatalf("CheckAll() = %d, %v; want the webhooks healthy again", unhealthy, err)
+	}
+	if !slices.Equal(events.types, []string{EventIntegrationUnhealthy, EventIntegrationRecovered}) {
+		t.Errorf("events = %v; want the recovery alerted", events.types)
+	}
+}
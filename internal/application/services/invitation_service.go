@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/google/uuid"
+)
+
+type InvitationService interface {
+	CreateInvitation(ctx context.Context, domainID, roleID uuid.UUID, email, firstName, lastName string) (*entities.Invitation, error)
+	ListInvitations(ctx context.Context, domainID uuid.UUID, status string) ([]*entities.Invitation, error)
+	ResendInvitation(ctx context.Context, domainID, id uuid.UUID) (*entities.Invitation, error)
+	RevokeInvitation(ctx context.Context, domainID, id uuid.UUID) error
+	AcceptInvitation(ctx context.Context, domainID uuid.UUID, token, username, firstName, lastName, password string) (*entities.User, error)
+}
+
+type invitationService struct {
+	repo       repositories.InvitationRepository
+	domainRepo repositories.DomainRepository
+	roleRepo   repositories.RoleRepository
+	userRepo   repositories.UserRepository
+	users      UserService
+	emails     EmailService
+	config     *config.InvitationConfig
+}
+
+func NewInvitationService(repo repositories.InvitationRepository, domainRepo repositories.DomainRepository, roleRepo repositories.RoleRepository, userRepo repositories.UserRepository, users UserService, emails EmailService, cfg *config.InvitationConfig) InvitationService {
+	return &invitationService{repo: repo, domainRepo: domainRepo, roleRepo: roleRepo, userRepo: userRepo, users: users, emails: emails, config: cfg}
+}
+
+// CreateInvitation emails an invite link for the role to the address. An address may have only one
+// pending invitation per domain and must not belong to an existing user.
+func (s *invitationService) CreateInvitation(ctx context.Context, domainID, roleID uuid.UUID, email, firstName, lastName string) (*entities.Invitation, error) {
+	ctx, span := tracer.Start(ctx, "InvitationService.CreateInvitation")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	if role, err := s.roleRepo.GetByID(ctx, roleID); err != nil || role.DomainID != domainID {
+		return nil, domainerrors.Validation("role does not exist in this domain")
+	}
+
+	email = strings.TrimSpace(email)
+	if _, err := s.userRepo.GetByEmailAndDomain(ctx, email, domainID); err == nil {
+		return nil, domainerrors.Conflict("email is already used in this domain").WithCode("email_taken")
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if _, err := s.repo.GetPendingByEmail(ctx, domainID, email); err == nil {
+		return nil, domainerrors.Conflict("a pending invitation already exists for this email; resend it instead").WithCode("invitation_pending")
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	invitation := &entities.Invitation{
+		DomainID:  domainID,
+		Email:     email,
+		RoleID:    roleID,
+		FirstName: strings.TrimSpace(firstName),
+		LastName:  strings.TrimSpace(lastName),
+	}
+	rawToken, err := s.issueToken(invitation)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	if err := s.send(ctx, domain, invitation, rawToken); err != nil {
+		// An invitation nobody received would only block re-inviting the address
+		if revokeErr := s.repo.Revoke(ctx, domainID, invitation.ID); revokeErr != nil {
+			log.Printf("Failed to revoke unsent invitation %s: %v", invitation.ID, revokeErr)
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// ListInvitations returns the domain's invitations, optionally only those with the given status.
+func (s *invitationService) ListInvitations(ctx context.Context, domainID uuid.UUID, status string) ([]*entities.Invitation, error) {
+	switch status {
+	case "", entities.InvitationPending, entities.InvitationAccepted, entities.InvitationRevoked, entities.InvitationExpired:
+	default:
+		return nil, domainerrors.Validation("status must be one of pending, accepted, revoked or expired")
+	}
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	return s.repo.ListByDomain(ctx, domainID, status)
+}
+
+// ResendInvitation emails a fresh link and restarts the expiry. The previous link stops working;
+// expired invitations can be resent, accepted and revoked ones cannot.
+func (s *invitationService) ResendInvitation(ctx context.Context, domainID, id uuid.UUID) (*entities.Invitation, error) {
+	ctx, span := tracer.Start(ctx, "InvitationService.ResendInvitation")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	invitation, err := s.repo.GetByID(ctx, domainID, id)
+	if err != nil {
+		return nil, notFoundOr(err, "invitation not found")
+	}
+
+	closed := domainerrors.Conflict("invitation has already been %s", invitation.Status).WithCode("invitation_closed")
+	if invitation.Status == entities.InvitationAccepted || invitation.Status == entities.InvitationRevoked {
+		return nil, closed
+	}
+
+	rawToken, err := s.issueToken(invitation)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Renew(ctx, invitation); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, closed
+		}
+		return nil, err
+	}
+	if err := s.send(ctx, domain, invitation, rawToken); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (s *invitationService) RevokeInvitation(ctx context.Context, domainID, id uuid.UUID) error {
+	if err := s.repo.Revoke(ctx, domainID, id); err != nil {
+		return notFoundOr(err, "invitation not found or no longer pending")
+	}
+	return nil
+}
+
+// AcceptInvitation creates the invited user with the chosen password. The username defaults to
+// the invited email and the names to those given in the invitation.
+func (s *invitationService) AcceptInvitation(ctx context.Context, domainID uuid.UUID, token, username, firstName, lastName, password string) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "InvitationService.AcceptInvitation")
+	ctx = tenancy.WithDomain(ctx, domainID)
+	defer span.End()
+
+	invalid := domainerrors.Unauthorized("invitation is invalid or expired").WithCode("invalid_invitation")
+
+	invitation, err := s.repo.GetByTokenHash(ctx, domainID, hashSecret(strings.TrimSpace(token)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if invitation.Status != entities.InvitationPending {
+		return nil, invalid
+	}
+
+	username = firstNonEmpty(strings.TrimSpace(username), invitation.Email)
+	firstName = firstNonEmpty(strings.TrimSpace(firstName), invitation.FirstName)
+	lastName = firstNonEmpty(strings.TrimSpace(lastName), invitation.LastName)
+	if firstName == "" || lastName == "" {
+		return nil, domainerrors.Validation("first_name and last_name are required")
+	}
+
+	if err := s.repo.Accept(ctx, domainID, invitation.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	user, err := s.users.CreateUser(ctx, domainID, invitation.RoleID, firstName, lastName, username, invitation.Email, password, nil, nil)
+	if err != nil {
+		// Leave the invitation usable so the invitee can fix the problem and try again
+		if reopenErr := s.repo.Reopen(ctx, domainID, invitation.ID); reopenErr != nil {
+			log.Printf("Failed to reopen invitation %s: %v", invitation.ID, reopenErr)
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// issueToken sets a new link token and expiry on the invitation and returns the plaintext token.
+func (s *invitationService) issueToken(invitation *entities.Invitation) (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	rawToken := hex.EncodeToString(token)
+	invitation.TokenHash = hashSecret(rawToken)
+	invitation.ExpiresAt = time.Now().Add(s.config.TTL)
+	return rawToken, nil
+}
+
+func (s *invitationService) send(ctx context.Context, domain *entities.Domain, invitation *entities.Invitation, rawToken string) error {
+	link := s.config.LinkURL + "?token=" + url.QueryEscape(rawToken) + "&domain_id=" + domain.DomainID.String()
+	data := map[string]any{"Link": link, "ExpiresIn": s.config.TTL.String()}
+	if err := s.emails.Send(ctx, domain.DomainID, invitation.Email, EmailInvitation, data); err != nil {
+		log.Printf("Failed to send invitation: %v", err)
+		return fmt.Errorf("failed to send invitation email")
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
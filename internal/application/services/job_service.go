@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/jobs"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultJobsListed = 50
+	maxJobsListed     = 200
+)
+
+var jobStatuses = []string{entities.JobPending, entities.JobRunning, entities.JobSucceeded, entities.JobFailed}
+
+// JobService shows platform operators the background job queue and lets them retry failed jobs.
+type JobService interface {
+	ListJobs(ctx context.Context, status, kind string, limit int) ([]*entities.Job, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*entities.Job, error)
+	GetStats(ctx context.Context) (*entities.JobStats, error)
+	RetryJob(ctx context.Context, id uuid.UUID) (*entities.Job, error)
+}
+
+type jobService struct {
+	repo  repositories.JobRepository
+	queue *jobs.Queue
+}
+
+func NewJobService(repo repositories.JobRepository, queue *jobs.Queue) JobService {
+	return &jobService{repo: repo, queue: queue}
+}
+
+// ListJobs returns the most recent jobs first, filtered by status and kind when they are set.
+func (s *jobService) ListJobs(ctx context.Context, status, kind string, limit int) ([]*entities.Job, error) {
+	ctx, span := tracer.Start(ctx, "JobService.ListJobs")
+	defer span.End()
+
+	if status != "" && !slices.Contains(jobStatuses, status) {
+		return nil, domainerrors.Validation("status must be pending, running, succeeded or failed")
+	}
+	if limit <= 0 {
+		limit = defaultJobsListed
+	}
+	return s.repo.List(ctx, status, kind, min(limit, maxJobsListed))
+}
+
+func (s *jobService) GetJob(ctx context.Context, id uuid.UUID) (*entities.Job, error) {
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "job not found")
+	}
+	return job, nil
+}
+
+func (s *jobService) GetStats(ctx context.Context) (*entities.JobStats, error) {
+	return s.repo.Stats(ctx)
+}
+
+// RetryJob gives a failed job a fresh set of attempts, due at once.
+func (s *jobService) RetryJob(ctx context.Context, id uuid.UUID) (*entities.Job, error) {
+	ctx, span := tracer.Start(ctx, "JobService.RetryJob")
+	defer span.End()
+
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, notFoundOr(err, "job not found")
+	}
+	job, err := s.queue.Retry(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domainerrors.Conflict("only failed jobs can be retried").WithCode("job_not_failed")
+	}
+	return job, err
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+var emailDomainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+func errCursorSort() error {
+	return domainerrors.Validation("cursor pages are ordered by created_at ascending; use page to sort by another field").WithCode("invalid_sort")
+}
+
+// validateCreatedRange rejects a creation range whose lower bound isn't before its upper bound.
+func validateCreatedRange(after, before *time.Time) error {
+	if after != nil && before != nil && !after.Before(*before) {
+		return domainerrors.Validation("created_after must be before created_before")
+	}
+	return nil
+}
+
+// listDomain returns the domain a user or role listing is scoped to. Without a domainID a domain
+// admin lists their own domain, taken from their token, while system admins and callers of an API
+// without admin authorization must name one. Naming a domain the admin doesn't manage is refused,
+// as are org unit admins, who don't manage domains.
+func listDomain(ctx context.Context, domainID uuid.UUID) (uuid.UUID, error) {
+	principal := AdminPrincipalFrom(ctx)
+	if principal != nil && principal.Delegated() {
+		return uuid.Nil, errOrgUnitAdminScope()
+	}
+	if domainID == uuid.Nil {
+		if principal == nil || principal.System {
+			return uuid.Nil, domainerrors.Validation("domainId is required").WithCode("domain_required")
+		}
+		return principal.DomainID, nil
+	}
+	if !adminCanManage(ctx, domainID) {
+		return uuid.Nil, domainerrors.Forbidden("domain admins can only manage their own domain").WithCode("domain_forbidden")
+	}
+	return domainID, nil
+}
+
+// listUserDomain is listDomain for user listings, which org unit admins may also make: of their own
+// domain, narrowed to the users of their unit's subtree.
+func listUserDomain(ctx context.Context, domainID uuid.UUID, filter *repositories.UserListFilter) (uuid.UUID, error) {
+	principal := AdminPrincipalFrom(ctx)
+	if principal == nil || !principal.Delegated() {
+		return listDomain(ctx, domainID)
+	}
+	if domainID != uuid.Nil && domainID != principal.DomainID {
+		return uuid.Nil, errOrgUnitAdminScope()
+	}
+	filter.DelegatedOrgUnitID = principal.OrgUnitID
+	return principal.DomainID, nil
+}
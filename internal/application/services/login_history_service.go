@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+const maxUserAgentLength = 512
+
+type userAgentKey struct{}
+
+// WithUserAgent attaches the client's User-Agent to ctx, for the login history of the sign-ins
+// made with it.
+func WithUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, userAgentKey{}, userAgent)
+}
+
+func userAgentFrom(ctx context.Context) string {
+	userAgent, _ := ctx.Value(userAgentKey{}).(string)
+	return userAgent
+}
+
+// LoginHistoryService keeps the sign-in attempts on each account and the user's last login.
+type LoginHistoryService interface {
+	Record(ctx context.Context, user *entities.User, method, clientIP, failure string)
+	ListHistory(ctx context.Context, userID uuid.UUID, page, limit int) (*repositories.LoginHistoryListResult, error)
+}
+
+type loginHistoryService struct {
+	repo     repositories.LoginHistoryRepository
+	userRepo repositories.UserRepository
+}
+
+func NewLoginHistoryService(repo repositories.LoginHistoryRepository, userRepo repositories.UserRepository) LoginHistoryService {
+	return &loginHistoryService{repo: repo, userRepo: userRepo}
+}
+
+// Record stores an attempt on the user's account; an empty failure records a success, which also
+// becomes the user's last login. Errors are logged, since the attempt itself already succeeded or
+// failed.
+func (s *loginHistoryService) Record(ctx context.Context, user *entities.User, method, clientIP, failure string) {
+	userAgent := userAgentFrom(ctx)
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+	}
+	entry := &entities.LoginHistoryEntry{
+		DomainID:  user.DomainID,
+		UserID:    user.ID,
+		Success:   failure == "",
+		Method:    method,
+		Reason:    failure,
+		IP:        clientIP,
+		UserAgent: userAgent,
+	}
+	if err := s.repo.Create(ctx, entry); err != nil {
+		log.Printf("Failed to record the login history of user %s: %v", user.ID, err)
+	}
+	if entry.Success {
+		if err := s.userRepo.RecordLogin(ctx, user, clientIP, time.Now().UTC()); err != nil {
+			log.Printf("Failed to record the last login of user %s: %v", user.ID, err)
+		}
+	}
+}
+
+func (s *loginHistoryService) ListHistory(ctx context.Context, userID uuid.UUID, page, limit int) (*repositories.LoginHistoryListResult, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundOr(err, "user not found")
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	return s.repo.ListByUser(ctx, user.DomainID, user.ID, page, limit)
+}
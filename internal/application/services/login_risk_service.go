@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/captcha"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/infrastructure/reputation"
+
+	"github.com/google/uuid"
+)
+
+// Login risk actions, ordered from least to most severe.
+const (
+	RiskActionAllow   = "allow"
+	RiskActionCaptcha = "captcha"
+	RiskActionMFA     = "mfa"
+	RiskActionBlock   = "block"
+)
+
+type LoginRiskService interface {
+	Assess(ctx context.Context, domainID uuid.UUID, ip string) (*RiskAssessment, error)
+	RecordFailure(ip string)
+	RecordSuccess(ip string)
+	VerifyCaptcha(ctx context.Context, token, ip string) (bool, error)
+	CaptchaWidget() *CaptchaWidget
+	GetPolicy(ctx context.Context, domainID uuid.UUID) (*entities.LoginRiskPolicy, error)
+	SetPolicy(ctx context.Context, policy *entities.LoginRiskPolicy) (*entities.LoginRiskPolicy, error)
+}
+
+type RiskAssessment struct {
+	Score  int    `json:"score" minimum:"0" maximum:"100" example:"55"`
+	Action string `json:"action" enums:"allow,captcha,mfa,block" example:"captcha"`
+}
+
+// CaptchaWidget is what a client app needs to render the CAPTCHA widget whose token answers a
+// challenge.
+type CaptchaWidget struct {
+	Provider string `json:"provider" enums:"recaptcha,hcaptcha,turnstile" example:"turnstile"`
+	SiteKey  string `json:"site_key" example:"0x4AAAAAAAB1cD2eF3gH4iJ5"`
+}
+
+type loginRiskService struct {
+	repo       repositories.LoginRiskPolicyRepository
+	domainRepo repositories.DomainRepository
+	failures   *reputation.FailedLoginTracker
+	provider   reputation.Provider
+	captcha    captcha.Verifier // nil when no CAPTCHA provider is configured
+	widget     *CaptchaWidget
+}
+
+func NewLoginRiskService(repo repositories.LoginRiskPolicyRepository, domainRepo repositories.DomainRepository, cfg *config.LoginRiskConfig, captchaConfig *config.CaptchaConfig) LoginRiskService {
+	failures := reputation.NewFailedLoginTracker(cfg.FailureWindow, cfg.FailureWeight)
+	providers := reputation.Combined{failures}
+	if cfg.FeedURL != "" {
+		providers = append(providers, reputation.NewHTTPFeed(cfg.FeedURL, cfg.FeedTimeout, cfg.FeedCacheTTL))
+	}
+
+	return &loginRiskService{
+		repo:       repo,
+		domainRepo: domainRepo,
+		failures:   failures,
+		provider:   providers,
+		captcha:    captcha.New(captchaConfig),
+		widget:     newCaptchaWidget(captchaConfig),
+	}
+}
+
+func newCaptchaWidget(cfg *config.CaptchaConfig) *CaptchaWidget {
+	if cfg.Provider == "" {
+		return nil
+	}
+	return &CaptchaWidget{Provider: cfg.Provider, SiteKey: cfg.SiteKey}
+}
+
+// Assess scores the client IP and picks the most severe action whose threshold the score reaches,
+// or a CAPTCHA when the IP has reached the policy's count of failed logins. Domains without a
+// policy always allow.
+func (s *loginRiskService) Assess(ctx context.Context, domainID uuid.UUID, ip string) (*RiskAssessment, error) {
+	ctx, span := tracer.Start(ctx, "LoginRiskService.Assess")
+	defer span.End()
+
+	// A feed outage degrades to the local score rather than failing the login
+	score, _ := s.provider.Score(ctx, ip)
+	assessment := &RiskAssessment{Score: score, Action: RiskActionAllow}
+
+	policy, err := s.repo.GetByDomainID(ctx, domainID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return assessment, nil
+		}
+		return nil, err
+	}
+
+	switch {
+	case reaches(score, policy.BlockThreshold):
+		assessment.Action = RiskActionBlock
+	case reaches(score, policy.MFAThreshold):
+		assessment.Action = RiskActionMFA
+	case reaches(score, policy.CaptchaThreshold), reaches(s.failures.Failures(ip), policy.CaptchaAfterFailures):
+		assessment.Action = RiskActionCaptcha
+	}
+	return assessment, nil
+}
+
+func (s *loginRiskService) RecordFailure(ip string) {
+	s.failures.RecordFailure(ip)
+}
+
+func (s *loginRiskService) RecordSuccess(ip string) {
+	s.failures.RecordSuccess(ip)
+}
+
+// VerifyCaptcha checks the token of a CAPTCHA widget with the configured provider. Without a
+// provider no token passes.
+func (s *loginRiskService) VerifyCaptcha(ctx context.Context, token, ip string) (bool, error) {
+	if s.captcha == nil || token == "" {
+		return false, nil
+	}
+	ctx, span := tracer.Start(ctx, "LoginRiskService.VerifyCaptcha")
+	defer span.End()
+
+	return s.captcha.Verify(ctx, token, ip)
+}
+
+// CaptchaWidget describes the configured CAPTCHA provider, or is nil when there is none.
+func (s *loginRiskService) CaptchaWidget() *CaptchaWidget {
+	return s.widget
+}
+
+func (s *loginRiskService) GetPolicy(ctx context.Context, domainID uuid.UUID) (*entities.LoginRiskPolicy, error) {
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	policy, err := s.repo.GetByDomainID(ctx, domainID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &entities.LoginRiskPolicy{DomainID: domainID}, nil
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// SetPolicy replaces all thresholds; a nil threshold disables that action.
+func (s *loginRiskService) SetPolicy(ctx context.Context, policy *entities.LoginRiskPolicy) (*entities.LoginRiskPolicy, error) {
+	for _, threshold := range []*int{policy.CaptchaThreshold, policy.MFAThreshold, policy.BlockThreshold} {
+		if threshold != nil && (*threshold < 1 || *threshold > 100) {
+			return nil, domainerrors.Validation("thresholds must be between 1 and 100")
+		}
+	}
+	if failures := policy.CaptchaAfterFailures; failures != nil && (*failures < 1 || *failures > 100) {
+		return nil, domainerrors.Validation("captcha_after_failures must be between 1 and 100")
+	}
+
+	if _, err := s.domainRepo.GetByID(ctx, policy.DomainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	if err := s.repo.Upsert(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func reaches(score int, threshold *int) bool {
+	return threshold != nil && score >= *threshold
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"backend/internal/infrastructure/jobs"
+
+	"github.com/google/uuid"
+)
+
+// JobSendMail is the kind of job sending one email through a DomainMailer.
+const JobSendMail = "mail.send"
+
+type mailJob struct {
+	DomainID uuid.UUID `json:"domain_id"`
+	To       string    `json:"to"`
+	Subject  string    `json:"subject"`
+	Body     string    `json:"body"`
+}
+
+// queuedMailer queues emails on the job queue, which sends them and retries those that fail. Send
+// returns once the email is queued, so it suits notifications no request waits on. Messages
+// carrying secrets, such as login codes, are sent directly instead, since the queue stores them.
+type queuedMailer struct {
+	queue *jobs.Queue
+}
+
+// NewQueuedMailer registers the mail job, sent through mailer, and returns a DomainMailer queueing it.
+func NewQueuedMailer(queue *jobs.Queue, mailer DomainMailer) DomainMailer {
+	queue.Register(JobSendMail, nil, func(ctx context.Context, payload json.RawMessage) error {
+		var job mailJob
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return jobs.Permanent(fmt.Errorf("malformed mail job: %w", err))
+		}
+		return mailer.Send(ctx, job.DomainID, job.To, job.Subject, job.Body)
+	})
+	return &queuedMailer{queue: queue}
+}
+
+func (m *queuedMailer) Send(ctx context.Context, domainID uuid.UUID, to, subject, body string) error {
+	_, err := m.queue.Enqueue(ctx, JobSendMail, mailJob{DomainID: domainID, To: to, Subject: subject, Body: body})
+	return err
+}
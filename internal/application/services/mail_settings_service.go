@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/faults"
+	"backend/internal/infrastructure/mailer"
+	"backend/internal/infrastructure/netguard"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/infrastructure/secrets"
+
+	"github.com/google/uuid"
+)
+
+// DomainMailer sends email on behalf of a domain, through the domain's own sender when one is
+// configured and the platform default otherwise.
+type DomainMailer interface {
+	Send(ctx context.Context, domainID uuid.UUID, to, subject, body string) error
+}
+
+type MailSettingsService interface {
+	DomainMailer
+	GetSettings(ctx context.Context, domainID uuid.UUID) (*entities.DomainMailSettings, error)
+	UpdateSettings(ctx context.Context, settings *entities.DomainMailSettings, password *string) (*entities.DomainMailSettings, error)
+	DeleteSettings(ctx context.Context, domainID uuid.UUID) error
+	TestSettings(ctx context.Context, domainID uuid.UUID, to string) (*entities.DomainMailSettings, error)
+}
+
+type mailSettingsService struct {
+	repo       repositories.DomainMailSettingsRepository
+	domainRepo repositories.DomainRepository
+	platform   mailer.Mailer
+	guard      *netguard.Guard
+}
+
+// NewMailSettingsService connects only to the domain SMTP servers guard accepts, which are also
+// the only hosts that can be configured.
+func NewMailSettingsService(repo repositories.DomainMailSettingsRepository, domainRepo repositories.DomainRepository, platform mailer.Mailer, guard *netguard.Guard) MailSettingsService {
+	return &mailSettingsService{repo: repo, domainRepo: domainRepo, platform: platform, guard: guard}
+}
+
+// Send uses the domain's enabled sender and falls back to the platform default when the domain
+// has none or sending through it fails.
+func (s *mailSettingsService) Send(ctx context.Context, domainID uuid.UUID, to, subject, body string) error {
+	ctx, span := tracer.Start(ctx, "MailSettingsService.Send")
+	defer span.End()
+
+	if faults.FailDelivery() {
+		return faults.ErrInjected
+	}
+
+	settings, err := s.repo.GetByDomainID(ctx, domainID)
+	switch {
+	case err == nil && settings.Enabled:
+		err := mailer.NewSMTP(mailConfigFor(settings), s.guard.Dialer(domainSMTPTimeout)).Send(ctx, to, subject, body)
+		if err == nil {
+			return nil
+		}
+		log.Printf("Failed to send email through the sender of domain %s, using the platform default: %v", domainID, err)
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		log.Printf("Failed to load mail settings of domain %s, using the platform default: %v", domainID, err)
+	}
+	return s.platform.Send(ctx, to, subject, body)
+}
+
+func (s *mailSettingsService) GetSettings(ctx context.Context, domainID uuid.UUID) (*entities.DomainMailSettings, error) {
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	settings, err := s.repo.GetByDomainID(ctx, domainID)
+	if err != nil {
+		return nil, notFoundOr(err, "domain uses the platform mail sender")
+	}
+	return settings, nil
+}
+
+// UpdateSettings replaces the domain's sender. A nil password keeps the stored one, so clients
+// never need to read it back.
+func (s *mailSettingsService) UpdateSettings(ctx context.Context, settings *entities.DomainMailSettings, password *string) (*entities.DomainMailSettings, error) {
+	if _, err := s.domainRepo.GetByID(ctx, settings.DomainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	settings.Provider = strings.ToLower(strings.TrimSpace(settings.Provider))
+	settings.Host = strings.TrimSpace(settings.Host)
+	settings.Region = strings.ToLower(strings.TrimSpace(settings.Region))
+	switch settings.Provider {
+	case "", entities.MailProviderSMTP:
+		settings.Provider = entities.MailProviderSMTP
+		settings.Region = ""
+		if settings.Host == "" {
+			return nil, domainerrors.Validation("host is required for SMTP")
+		}
+	case entities.MailProviderSES:
+		if settings.Region == "" {
+			return nil, domainerrors.Validation("region is required for SES")
+		}
+		settings.Host = fmt.Sprintf("email-smtp.%s.amazonaws.com", settings.Region)
+	default:
+		return nil, domainerrors.Validation("provider must be smtp or ses")
+	}
+	if settings.Port == "" {
+		settings.Port = "587"
+	}
+	if err := s.guard.CheckHost(ctx, settings.Host); errors.Is(err, netguard.ErrForbiddenAddress) {
+		return nil, domainerrors.Validation("host must not be a private, loopback or link-local address")
+	} else if err != nil {
+		return nil, domainerrors.Validation("host %s can't be resolved", settings.Host)
+	}
+	if _, err := mail.ParseAddress(settings.FromAddress); err != nil {
+		return nil, domainerrors.Validation("from_address must be a valid email address")
+	}
+
+	if password != nil {
+		settings.Password = *password
+	} else if existing, err := s.repo.GetByDomainID(ctx, settings.DomainID); err == nil {
+		settings.Password = existing.Password
+	}
+	if settings.Username != "" && settings.Password == "" {
+		return nil, domainerrors.Validation("password is required with a username")
+	}
+	settings.PasswordSet = settings.Password != ""
+
+	if err := s.repo.Upsert(ctx, settings); errors.Is(err, secrets.ErrNoKey) {
+		return nil, domainerrors.Validation("passwords can't be stored until MAIL_CREDENTIALS_KEY is configured").WithCode("mail_credentials_key_missing")
+	} else if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// DeleteSettings returns the domain to the platform sender.
+func (s *mailSettingsService) DeleteSettings(ctx context.Context, domainID uuid.UUID) error {
+	if err := s.repo.Delete(ctx, domainID); err != nil {
+		return notFoundOr(err, "domain uses the platform mail sender")
+	}
+	return nil
+}
+
+// TestSettings connects and authenticates with the domain's sender and, when to is given, sends
+// a test message through it without falling back. The outcome is stored on the settings.
+func (s *mailSettingsService) TestSettings(ctx context.Context, domainID uuid.UUID, to string) (*entities.DomainMailSettings, error) {
+	ctx, span := tracer.Start(ctx, "MailSettingsService.TestSettings")
+	defer span.End()
+
+	settings, err := s.GetSettings(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := mailConfigFor(settings)
+	dialer := s.guard.Dialer(domainSMTPTimeout)
+	testErr := mailer.Verify(ctx, cfg, dialer)
+	if testErr == nil && to != "" {
+		testErr = mailer.NewSMTP(cfg, dialer).Send(ctx, to, "Test email", "This is a test email sent to verify the mail settings of your domain.")
+	}
+
+	now := time.Now()
+	settings.LastTestedAt = &now
+	settings.LastTestError = nil
+	if testErr != nil {
+		message := testErr.Error()
+		settings.LastTestError = &message
+	}
+	if err := s.repo.RecordTest(ctx, domainID, now, settings.LastTestError); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// domainSMTPTimeout bounds connecting to a domain's SMTP server.
+const domainSMTPTimeout = 10 * time.Second
+
+func mailConfigFor(settings *entities.DomainMailSettings) *config.MailConfig {
+	return &config.MailConfig{
+		Driver:       config.MailDriverSMTP,
+		SMTPHost:     settings.Host,
+		SMTPPort:     settings.Port,
+		SMTPUsername: settings.Username,
+		SMTPPassword: settings.Password,
+		From:         settings.FromAddress,
+	}
+}
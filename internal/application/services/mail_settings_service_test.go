@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/netguard"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// fakeDomains finds every domain.
+type fakeDomains struct {
+	repositories.DomainRepository
+}
+
+func (fakeDomains) GetByID(ctx context.Context, id uuid.UUID) (*entities.Domain, error) {
+	return &entities.Domain{DomainID: id}, nil
+}
+
+// fakeMailSettings keeps one domain's settings.
+type fakeMailSettings struct {
+	repositories.DomainMailSettingsRepository
+	settings *entities.DomainMailSettings
+	testErr  *string
+}
+
+func (f *fakeMailSettings) GetByDomainID(ctx context.Context, domainID uuid.UUID) (*entities.DomainMailSettings, error) {
+	if f.settings == nil {
+		return nil, domainerrors.NotFound("no settings")
+	}
+	settings := *f.settings
+	return &settings, nil
+}
+
+func (f *fakeMailSettings) Upsert(ctx context.Context, settings *entities.DomainMailSettings) error {
+	stored := *settings
+	f.settings = &stored
+	return nil
+}
+
+func (f *fakeMailSettings) RecordTest(ctx context.Context, domainID uuid.UUID, at time.Time, testErr *string) error {
+	f.testErr = testErr
+	return nil
+}
+
+func TestUpdateMailSettingsRefusesPrivateHosts(t *testing.T) {
+	service := NewMailSettingsService(&fakeMailSettings{}, fakeDomains{}, nil, netguard.New(nil))
+	for _, host := range []string{"localhost", "127.0.0.1", "10.0.0.5", "169.254.169.254", "[::1]"} {
+		t.Run(host, func(t *testing.T) {
+			settings := &entities.DomainMailSettings{DomainID: uuid.New(), Host: strings.Trim(host, "[]"), FromAddress: "no-reply@acme.example.com"}
+			_, err := service.UpdateSettings(context.Background(), settings, nil)
+			if !errors.Is(err, domainerrors.ErrValidation) || !strings.Contains(err.Error(), "private") {
+				t.Errorf("UpdateSettings(host %s) = %v, want a validation error", host, err)
+			}
+		})
+	}
+}
+
+func TestTestMailSettingsRefusesPrivateHosts(t *testing.T) {
+	// The host was stored before it was checked, or resolves differently since
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	repo := &fakeMailSettings{settings: &entities.DomainMailSettings{Host: host, Port: port, FromAddress: "no-reply@acme.example.com", Enabled: true}}
+	service := NewMailSettingsService(repo, fakeDomains{}, nil, netguard.New(nil))
+	settings, err := service.TestSettings(context.Background(), uuid.New(), "admin@acme.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.LastTestError == nil || !strings.Contains(*settings.LastTestError, netguard.ErrForbiddenAddress.Error()) {
+		t.Errorf("LastTestError = %v, want the address refused", settings.LastTestError)
+	}
+}
+
+func TestMailSettingsPasswordNotSerialized(t *testing.T) {
+	data, err := json.Marshal(&entities.DomainMailSettings{Password: "smtp-secret", PasswordSet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "smtp-secret") {
+		t.Errorf("settings JSON holds the password: %s", data)
+	}
+}
@@ -0,0 +1,5 @@
+// Package mocks holds gomock mocks of the service interfaces the handlers depend on, for handler
+// tests. Regenerate them with go generate after changing one of the interfaces.
+package mocks
+
+//go:generate go tool mockgen -write_package_comment=false -destination=services.go -package=mocks backend/internal/application/services UserService,RoleService,DomainService,AuthService
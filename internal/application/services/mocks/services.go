@@ -0,0 +1,1192 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: backend/internal/application/services (interfaces: UserService,RoleService,DomainService,AuthService)
+//
+// Generated by this command:
+//
+//	mockgen -write_package_comment=false -destination=services.go -package=mocks backend/internal/application/services UserService,RoleService,DomainService,AuthService
+//
+
+package mocks
+
+import (
+	services "backend/internal/application/services"
+	entities "backend/internal/domain/entities"
+	repositories "backend/internal/infrastructure/repositories"
+	signing "backend/internal/infrastructure/signing"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserService is a mock of UserService interface.
+type MockUserService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockUserServiceMockRecorder is the mock recorder for MockUserService.
+type MockUserServiceMockRecorder struct {
+	mock *MockUserService
+}
+
+// NewMockUserService creates a new mock instance.
+func NewMockUserService(ctrl *gomock.Controller) *MockUserService {
+	mock := &MockUserService{ctrl: ctrl}
+	mock.recorder = &MockUserServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateBreakGlassAccount mocks base method.
+func (m *MockUserService) CreateBreakGlassAccount(ctx context.Context, domainID, roleID uuid.UUID, firstName, lastName, username, email, password string) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBreakGlassAccount", ctx, domainID, roleID, firstName, lastName, username, email, password)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBreakGlassAccount indicates an expected call of CreateBreakGlassAccount.
+func (mr *MockUserServiceMockRecorder) CreateBreakGlassAccount(ctx, domainID, roleID, firstName, lastName, username, email, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBreakGlassAccount", reflect.TypeOf((*MockUserService)(nil).CreateBreakGlassAccount), ctx, domainID, roleID, firstName, lastName, username, email, password)
+}
+
+// CreateServiceAccount mocks base method.
+func (m *MockUserService) CreateServiceAccount(ctx context.Context, domainID, roleID uuid.UUID, username, name string) (*services.CreatedServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServiceAccount", ctx, domainID, roleID, username, name)
+	ret0, _ := ret[0].(*services.CreatedServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateServiceAccount indicates an expected call of CreateServiceAccount.
+func (mr *MockUserServiceMockRecorder) CreateServiceAccount(ctx, domainID, roleID, username, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceAccount", reflect.TypeOf((*MockUserService)(nil).CreateServiceAccount), ctx, domainID, roleID, username, name)
+}
+
+// CreateUser mocks base method.
+func (m *MockUserService) CreateUser(ctx context.Context, domainID, roleID uuid.UUID, firstName, lastName, username, email, password string, externalID *string, validUntil *time.Time) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, domainID, roleID, firstName, lastName, username, email, password, externalID, validUntil)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserServiceMockRecorder) CreateUser(ctx, domainID, roleID, firstName, lastName, username, email, password, externalID, validUntil any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserService)(nil).CreateUser), ctx, domainID, roleID, firstName, lastName, username, email, password, externalID, validUntil)
+}
+
+// DeleteBreakGlassAccount mocks base method.
+func (m *MockUserService) DeleteBreakGlassAccount(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBreakGlassAccount", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBreakGlassAccount indicates an expected call of DeleteBreakGlassAccount.
+func (mr *MockUserServiceMockRecorder) DeleteBreakGlassAccount(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBreakGlassAccount", reflect.TypeOf((*MockUserService)(nil).DeleteBreakGlassAccount), ctx, id)
+}
+
+// DeleteUser mocks base method.
+func (m *MockUserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockUserServiceMockRecorder) DeleteUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserService)(nil).DeleteUser), ctx, id)
+}
+
+// DisableExpiredUsers mocks base method.
+func (m *MockUserService) DisableExpiredUsers(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableExpiredUsers", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableExpiredUsers indicates an expected call of DisableExpiredUsers.
+func (mr *MockUserServiceMockRecorder) DisableExpiredUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableExpiredUsers", reflect.TypeOf((*MockUserService)(nil).DisableExpiredUsers), ctx)
+}
+
+// ExportUsers mocks base method.
+func (m *MockUserService) ExportUsers(ctx context.Context, domainID uuid.UUID, fn func(*entities.User) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportUsers", ctx, domainID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportUsers indicates an expected call of ExportUsers.
+func (mr *MockUserServiceMockRecorder) ExportUsers(ctx, domainID, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportUsers", reflect.TypeOf((*MockUserService)(nil).ExportUsers), ctx, domainID, fn)
+}
+
+// GetUserByExternalID mocks base method.
+func (m *MockUserService) GetUserByExternalID(ctx context.Context, domainID uuid.UUID, externalID string) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByExternalID", ctx, domainID, externalID)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByExternalID indicates an expected call of GetUserByExternalID.
+func (mr *MockUserServiceMockRecorder) GetUserByExternalID(ctx, domainID, externalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByExternalID", reflect.TypeOf((*MockUserService)(nil).GetUserByExternalID), ctx, domainID, externalID)
+}
+
+// GetUserByID mocks base method.
+func (m *MockUserService) GetUserByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, id)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUserServiceMockRecorder) GetUserByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserService)(nil).GetUserByID), ctx, id)
+}
+
+// GetUsersByDomainID mocks base method.
+func (m *MockUserService) GetUsersByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByDomainID", ctx, domainID)
+	ret0, _ := ret[0].([]*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByDomainID indicates an expected call of GetUsersByDomainID.
+func (mr *MockUserServiceMockRecorder) GetUsersByDomainID(ctx, domainID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByDomainID", reflect.TypeOf((*MockUserService)(nil).GetUsersByDomainID), ctx, domainID)
+}
+
+// GetUsersByIDs mocks base method.
+func (m *MockUserService) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) (*services.UserBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByIDs", ctx, ids)
+	ret0, _ := ret[0].(*services.UserBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByIDs indicates an expected call of GetUsersByIDs.
+func (mr *MockUserServiceMockRecorder) GetUsersByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockUserService)(nil).GetUsersByIDs), ctx, ids)
+}
+
+// ImportUsers mocks base method.
+func (m *MockUserService) ImportUsers(ctx context.Context, domainID uuid.UUID, defaultRoleID *uuid.UUID, rows []*services.UserImportRow, dryRun bool) (*services.UserImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportUsers", ctx, domainID, defaultRoleID, rows, dryRun)
+	ret0, _ := ret[0].(*services.UserImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportUsers indicates an expected call of ImportUsers.
+func (mr *MockUserServiceMockRecorder) ImportUsers(ctx, domainID, defaultRoleID, rows, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportUsers", reflect.TypeOf((*MockUserService)(nil).ImportUsers), ctx, domainID, defaultRoleID, rows, dryRun)
+}
+
+// ListBreakGlassAccounts mocks base method.
+func (m *MockUserService) ListBreakGlassAccounts(ctx context.Context) ([]*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBreakGlassAccounts", ctx)
+	ret0, _ := ret[0].([]*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBreakGlassAccounts indicates an expected call of ListBreakGlassAccounts.
+func (mr *MockUserServiceMockRecorder) ListBreakGlassAccounts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBreakGlassAccounts", reflect.TypeOf((*MockUserService)(nil).ListBreakGlassAccounts), ctx)
+}
+
+// ListExpiringUsers mocks base method.
+func (m *MockUserService) ListExpiringUsers(ctx context.Context, domainID uuid.UUID, within time.Duration) ([]*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiringUsers", ctx, domainID, within)
+	ret0, _ := ret[0].([]*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiringUsers indicates an expected call of ListExpiringUsers.
+func (mr *MockUserServiceMockRecorder) ListExpiringUsers(ctx, domainID, within any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiringUsers", reflect.TypeOf((*MockUserService)(nil).ListExpiringUsers), ctx, domainID, within)
+}
+
+// ListUsersAfter mocks base method.
+func (m *MockUserService) ListUsersAfter(ctx context.Context, filter repositories.UserListFilter, domainID uuid.UUID, cursor string, limit int) (*repositories.UserCursorPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsersAfter", ctx, filter, domainID, cursor, limit)
+	ret0, _ := ret[0].(*repositories.UserCursorPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsersAfter indicates an expected call of ListUsersAfter.
+func (mr *MockUserServiceMockRecorder) ListUsersAfter(ctx, filter, domainID, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsersAfter", reflect.TypeOf((*MockUserService)(nil).ListUsersAfter), ctx, filter, domainID, cursor, limit)
+}
+
+// ListUsersWithPagination mocks base method.
+func (m *MockUserService) ListUsersWithPagination(ctx context.Context, filter repositories.UserListFilter, domainID uuid.UUID, page, limit int) (*repositories.UserListResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsersWithPagination", ctx, filter, domainID, page, limit)
+	ret0, _ := ret[0].(*repositories.UserListResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsersWithPagination indicates an expected call of ListUsersWithPagination.
+func (mr *MockUserServiceMockRecorder) ListUsersWithPagination(ctx, filter, domainID, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsersWithPagination", reflect.TypeOf((*MockUserService)(nil).ListUsersWithPagination), ctx, filter, domainID, page, limit)
+}
+
+// ResetUserPassword mocks base method.
+func (m *MockUserService) ResetUserPassword(ctx context.Context, id uuid.UUID, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetUserPassword", ctx, id, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetUserPassword indicates an expected call of ResetUserPassword.
+func (mr *MockUserServiceMockRecorder) ResetUserPassword(ctx, id, newPassword any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetUserPassword", reflect.TypeOf((*MockUserService)(nil).ResetUserPassword), ctx, id, newPassword)
+}
+
+// RotateBreakGlassPassword mocks base method.
+func (m *MockUserService) RotateBreakGlassPassword(ctx context.Context, id uuid.UUID, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateBreakGlassPassword", ctx, id, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateBreakGlassPassword indicates an expected call of RotateBreakGlassPassword.
+func (mr *MockUserServiceMockRecorder) RotateBreakGlassPassword(ctx, id, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateBreakGlassPassword", reflect.TypeOf((*MockUserService)(nil).RotateBreakGlassPassword), ctx, id, password)
+}
+
+// RunExpirySweep mocks base method.
+func (m *MockUserService) RunExpirySweep(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunExpirySweep", ctx, interval)
+}
+
+// RunExpirySweep indicates an expected call of RunExpirySweep.
+func (mr *MockUserServiceMockRecorder) RunExpirySweep(ctx, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunExpirySweep", reflect.TypeOf((*MockUserService)(nil).RunExpirySweep), ctx, interval)
+}
+
+// SetUserValidUntil mocks base method.
+func (m *MockUserService) SetUserValidUntil(ctx context.Context, id uuid.UUID, validUntil *time.Time) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserValidUntil", ctx, id, validUntil)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserValidUntil indicates an expected call of SetUserValidUntil.
+func (mr *MockUserServiceMockRecorder) SetUserValidUntil(ctx, id, validUntil any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserValidUntil", reflect.TypeOf((*MockUserService)(nil).SetUserValidUntil), ctx, id, validUntil)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUserService) UpdateUser(ctx context.Context, id uuid.UUID, firstName, lastName, username, email string, roleID uuid.UUID, externalID *string) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, id, firstName, lastName, username, email, roleID, externalID)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserServiceMockRecorder) UpdateUser(ctx, id, firstName, lastName, username, email, roleID, externalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), ctx, id, firstName, lastName, username, email, roleID, externalID)
+}
+
+// UpdateUserByExternalID mocks base method.
+func (m *MockUserService) UpdateUserByExternalID(ctx context.Context, domainID uuid.UUID, externalID, firstName, lastName, username, email string, roleID uuid.UUID) (*entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserByExternalID", ctx, domainID, externalID, firstName, lastName, username, email, roleID)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserByExternalID indicates an expected call of UpdateUserByExternalID.
+func (mr *MockUserServiceMockRecorder) UpdateUserByExternalID(ctx, domainID, externalID, firstName, lastName, username, email, roleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserByExternalID", reflect.TypeOf((*MockUserService)(nil).UpdateUserByExternalID), ctx, domainID, externalID, firstName, lastName, username, email, roleID)
+}
+
+// UpsertUserByExternalID mocks base method.
+func (m *MockUserService) UpsertUserByExternalID(ctx context.Context, domainID uuid.UUID, externalID, firstName, lastName, username, email, password string, roleID uuid.UUID) (*entities.User, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserByExternalID", ctx, domainID, externalID, firstName, lastName, username, email, password, roleID)
+	ret0, _ := ret[0].(*entities.User)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpsertUserByExternalID indicates an expected call of UpsertUserByExternalID.
+func (mr *MockUserServiceMockRecorder) UpsertUserByExternalID(ctx, domainID, externalID, firstName, lastName, username, email, password, roleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserByExternalID", reflect.TypeOf((*MockUserService)(nil).UpsertUserByExternalID), ctx, domainID, externalID, firstName, lastName, username, email, password, roleID)
+}
+
+// VerifyPassword mocks base method.
+func (m *MockUserService) VerifyPassword(hashedPassword, password string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPassword", hashedPassword, password)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// VerifyPassword indicates an expected call of VerifyPassword.
+func (mr *MockUserServiceMockRecorder) VerifyPassword(hashedPassword, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPassword", reflect.TypeOf((*MockUserService)(nil).VerifyPassword), hashedPassword, password)
+}
+
+// MockRoleService is a mock of RoleService interface.
+type MockRoleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRoleServiceMockRecorder is the mock recorder for MockRoleService.
+type MockRoleServiceMockRecorder struct {
+	mock *MockRoleService
+}
+
+// NewMockRoleService creates a new mock instance.
+func NewMockRoleService(ctrl *gomock.Controller) *MockRoleService {
+	mock := &MockRoleService{ctrl: ctrl}
+	mock.recorder = &MockRoleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleService) EXPECT() *MockRoleServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateRole mocks base method.
+func (m *MockRoleService) CreateRole(ctx context.Context, domainID uuid.UUID, roleName string, roleClaims map[string]any) (*entities.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", ctx, domainID, roleName, roleClaims)
+	ret0, _ := ret[0].(*entities.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *MockRoleServiceMockRecorder) CreateRole(ctx, domainID, roleName, roleClaims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockRoleService)(nil).CreateRole), ctx, domainID, roleName, roleClaims)
+}
+
+// DeleteRole mocks base method.
+func (m *MockRoleService) DeleteRole(ctx context.Context, id uuid.UUID, replacementID *uuid.UUID, dryRun bool) (*services.RoleDeletionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", ctx, id, replacementID, dryRun)
+	ret0, _ := ret[0].(*services.RoleDeletionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *MockRoleServiceMockRecorder) DeleteRole(ctx, id, replacementID, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockRoleService)(nil).DeleteRole), ctx, id, replacementID, dryRun)
+}
+
+// ExportRoles mocks base method.
+func (m *MockRoleService) ExportRoles(ctx context.Context, domainID uuid.UUID, fn func(*entities.Role) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportRoles", ctx, domainID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportRoles indicates an expected call of ExportRoles.
+func (mr *MockRoleServiceMockRecorder) ExportRoles(ctx, domainID, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportRoles", reflect.TypeOf((*MockRoleService)(nil).ExportRoles), ctx, domainID, fn)
+}
+
+// GetRoleByID mocks base method.
+func (m *MockRoleService) GetRoleByID(ctx context.Context, id uuid.UUID) (*entities.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleByID", ctx, id)
+	ret0, _ := ret[0].(*entities.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleByID indicates an expected call of GetRoleByID.
+func (mr *MockRoleServiceMockRecorder) GetRoleByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleByID", reflect.TypeOf((*MockRoleService)(nil).GetRoleByID), ctx, id)
+}
+
+// GetRolesByDomainID mocks base method.
+func (m *MockRoleService) GetRolesByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRolesByDomainID", ctx, domainID)
+	ret0, _ := ret[0].([]*entities.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRolesByDomainID indicates an expected call of GetRolesByDomainID.
+func (mr *MockRoleServiceMockRecorder) GetRolesByDomainID(ctx, domainID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRolesByDomainID", reflect.TypeOf((*MockRoleService)(nil).GetRolesByDomainID), ctx, domainID)
+}
+
+// GetRolesByIDs mocks base method.
+func (m *MockRoleService) GetRolesByIDs(ctx context.Context, ids []uuid.UUID) (*services.RoleBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRolesByIDs", ctx, ids)
+	ret0, _ := ret[0].(*services.RoleBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRolesByIDs indicates an expected call of GetRolesByIDs.
+func (mr *MockRoleServiceMockRecorder) GetRolesByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRolesByIDs", reflect.TypeOf((*MockRoleService)(nil).GetRolesByIDs), ctx, ids)
+}
+
+// ListRoleMembers mocks base method.
+func (m *MockRoleService) ListRoleMembers(ctx context.Context, id uuid.UUID, page, limit int) (*repositories.UserListResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoleMembers", ctx, id, page, limit)
+	ret0, _ := ret[0].(*repositories.UserListResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoleMembers indicates an expected call of ListRoleMembers.
+func (mr *MockRoleServiceMockRecorder) ListRoleMembers(ctx, id, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoleMembers", reflect.TypeOf((*MockRoleService)(nil).ListRoleMembers), ctx, id, page, limit)
+}
+
+// ListRoleRevisions mocks base method.
+func (m *MockRoleService) ListRoleRevisions(ctx context.Context, id uuid.UUID, page, limit int) (*repositories.RoleRevisionListResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoleRevisions", ctx, id, page, limit)
+	ret0, _ := ret[0].(*repositories.RoleRevisionListResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoleRevisions indicates an expected call of ListRoleRevisions.
+func (mr *MockRoleServiceMockRecorder) ListRoleRevisions(ctx, id, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoleRevisions", reflect.TypeOf((*MockRoleService)(nil).ListRoleRevisions), ctx, id, page, limit)
+}
+
+// ListRolesAfter mocks base method.
+func (m *MockRoleService) ListRolesAfter(ctx context.Context, filter repositories.RoleListFilter, domainID uuid.UUID, cursor string, limit int) (*repositories.RoleCursorPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRolesAfter", ctx, filter, domainID, cursor, limit)
+	ret0, _ := ret[0].(*repositories.RoleCursorPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRolesAfter indicates an expected call of ListRolesAfter.
+func (mr *MockRoleServiceMockRecorder) ListRolesAfter(ctx, filter, domainID, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRolesAfter", reflect.TypeOf((*MockRoleService)(nil).ListRolesAfter), ctx, filter, domainID, cursor, limit)
+}
+
+// ListRolesWithPagination mocks base method.
+func (m *MockRoleService) ListRolesWithPagination(ctx context.Context, filter repositories.RoleListFilter, domainID uuid.UUID, page, limit int) (*repositories.RoleListResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRolesWithPagination", ctx, filter, domainID, page, limit)
+	ret0, _ := ret[0].(*repositories.RoleListResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRolesWithPagination indicates an expected call of ListRolesWithPagination.
+func (mr *MockRoleServiceMockRecorder) ListRolesWithPagination(ctx, filter, domainID, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRolesWithPagination", reflect.TypeOf((*MockRoleService)(nil).ListRolesWithPagination), ctx, filter, domainID, page, limit)
+}
+
+// RollbackRole mocks base method.
+func (m *MockRoleService) RollbackRole(ctx context.Context, id uuid.UUID, revision int) (*entities.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackRole", ctx, id, revision)
+	ret0, _ := ret[0].(*entities.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackRole indicates an expected call of RollbackRole.
+func (mr *MockRoleServiceMockRecorder) RollbackRole(ctx, id, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackRole", reflect.TypeOf((*MockRoleService)(nil).RollbackRole), ctx, id, revision)
+}
+
+// UpdateRole mocks base method.
+func (m *MockRoleService) UpdateRole(ctx context.Context, id uuid.UUID, roleName string, roleClaims map[string]any, notify *services.RoleChangeNotification) (*entities.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, id, roleName, roleClaims, notify)
+	ret0, _ := ret[0].(*entities.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockRoleServiceMockRecorder) UpdateRole(ctx, id, roleName, roleClaims, notify any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockRoleService)(nil).UpdateRole), ctx, id, roleName, roleClaims, notify)
+}
+
+// MockDomainService is a mock of DomainService interface.
+type MockDomainService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDomainServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockDomainServiceMockRecorder is the mock recorder for MockDomainService.
+type MockDomainServiceMockRecorder struct {
+	mock *MockDomainService
+}
+
+// NewMockDomainService creates a new mock instance.
+func NewMockDomainService(ctrl *gomock.Controller) *MockDomainService {
+	mock := &MockDomainService{ctrl: ctrl}
+	mock.recorder = &MockDomainServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDomainService) EXPECT() *MockDomainServiceMockRecorder {
+	return m.recorder
+}
+
+// AddAlias mocks base method.
+func (m *MockDomainService) AddAlias(ctx context.Context, domainID uuid.UUID, hostname string, isPrimary bool) (*entities.DomainAlias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAlias", ctx, domainID, hostname, isPrimary)
+	ret0, _ := ret[0].(*entities.DomainAlias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAlias indicates an expected call of AddAlias.
+func (mr *MockDomainServiceMockRecorder) AddAlias(ctx, domainID, hostname, isPrimary any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAlias", reflect.TypeOf((*MockDomainService)(nil).AddAlias), ctx, domainID, hostname, isPrimary)
+}
+
+// CreateDomain mocks base method.
+func (m *MockDomainService) CreateDomain(ctx context.Context, name, domainStr, residency, loginMode string, passwordPolicy *entities.PasswordPolicy) (*entities.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDomain", ctx, name, domainStr, residency, loginMode, passwordPolicy)
+	ret0, _ := ret[0].(*entities.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDomain indicates an expected call of CreateDomain.
+func (mr *MockDomainServiceMockRecorder) CreateDomain(ctx, name, domainStr, residency, loginMode, passwordPolicy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDomain", reflect.TypeOf((*MockDomainService)(nil).CreateDomain), ctx, name, domainStr, residency, loginMode, passwordPolicy)
+}
+
+// DeleteDomain mocks base method.
+func (m *MockDomainService) DeleteDomain(ctx context.Context, id uuid.UUID, force, dryRun bool) (*services.DomainDeletionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDomain", ctx, id, force, dryRun)
+	ret0, _ := ret[0].(*services.DomainDeletionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDomain indicates an expected call of DeleteDomain.
+func (mr *MockDomainServiceMockRecorder) DeleteDomain(ctx, id, force, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDomain", reflect.TypeOf((*MockDomainService)(nil).DeleteDomain), ctx, id, force, dryRun)
+}
+
+// GetDataMasking mocks base method.
+func (m *MockDomainService) GetDataMasking(ctx context.Context, id uuid.UUID) (*entities.DataMaskingSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataMasking", ctx, id)
+	ret0, _ := ret[0].(*entities.DataMaskingSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDataMasking indicates an expected call of GetDataMasking.
+func (mr *MockDomainServiceMockRecorder) GetDataMasking(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataMasking", reflect.TypeOf((*MockDomainService)(nil).GetDataMasking), ctx, id)
+}
+
+// GetDomainByID mocks base method.
+func (m *MockDomainService) GetDomainByID(ctx context.Context, id uuid.UUID) (*entities.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDomainByID", ctx, id)
+	ret0, _ := ret[0].(*entities.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDomainByID indicates an expected call of GetDomainByID.
+func (mr *MockDomainServiceMockRecorder) GetDomainByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomainByID", reflect.TypeOf((*MockDomainService)(nil).GetDomainByID), ctx, id)
+}
+
+// GetPasswordPolicy mocks base method.
+func (m *MockDomainService) GetPasswordPolicy(ctx context.Context, id uuid.UUID) (*entities.PasswordPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordPolicy", ctx, id)
+	ret0, _ := ret[0].(*entities.PasswordPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordPolicy indicates an expected call of GetPasswordPolicy.
+func (mr *MockDomainServiceMockRecorder) GetPasswordPolicy(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordPolicy", reflect.TypeOf((*MockDomainService)(nil).GetPasswordPolicy), ctx, id)
+}
+
+// GetTokenSettings mocks base method.
+func (m *MockDomainService) GetTokenSettings(ctx context.Context, id uuid.UUID) (*entities.DomainTokenSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenSettings", ctx, id)
+	ret0, _ := ret[0].(*entities.DomainTokenSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenSettings indicates an expected call of GetTokenSettings.
+func (mr *MockDomainServiceMockRecorder) GetTokenSettings(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenSettings", reflect.TypeOf((*MockDomainService)(nil).GetTokenSettings), ctx, id)
+}
+
+// ListAliases mocks base method.
+func (m *MockDomainService) ListAliases(ctx context.Context, domainID uuid.UUID) ([]*entities.DomainAlias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAliases", ctx, domainID)
+	ret0, _ := ret[0].([]*entities.DomainAlias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAliases indicates an expected call of ListAliases.
+func (mr *MockDomainServiceMockRecorder) ListAliases(ctx, domainID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAliases", reflect.TypeOf((*MockDomainService)(nil).ListAliases), ctx, domainID)
+}
+
+// ListDomains mocks base method.
+func (m *MockDomainService) ListDomains(ctx context.Context) ([]*entities.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDomains", ctx)
+	ret0, _ := ret[0].([]*entities.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDomains indicates an expected call of ListDomains.
+func (mr *MockDomainServiceMockRecorder) ListDomains(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDomains", reflect.TypeOf((*MockDomainService)(nil).ListDomains), ctx)
+}
+
+// ListDomainsAfter mocks base method.
+func (m *MockDomainService) ListDomainsAfter(ctx context.Context, search, cursor string, limit int) (*repositories.DomainCursorPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDomainsAfter", ctx, search, cursor, limit)
+	ret0, _ := ret[0].(*repositories.DomainCursorPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDomainsAfter indicates an expected call of ListDomainsAfter.
+func (mr *MockDomainServiceMockRecorder) ListDomainsAfter(ctx, search, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDomainsAfter", reflect.TypeOf((*MockDomainService)(nil).ListDomainsAfter), ctx, search, cursor, limit)
+}
+
+// ListDomainsWithPagination mocks base method.
+func (m *MockDomainService) ListDomainsWithPagination(ctx context.Context, search string, page, limit int) (*repositories.DomainListResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDomainsWithPagination", ctx, search, page, limit)
+	ret0, _ := ret[0].(*repositories.DomainListResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDomainsWithPagination indicates an expected call of ListDomainsWithPagination.
+func (mr *MockDomainServiceMockRecorder) ListDomainsWithPagination(ctx, search, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDomainsWithPagination", reflect.TypeOf((*MockDomainService)(nil).ListDomainsWithPagination), ctx, search, page, limit)
+}
+
+// RemoveAlias mocks base method.
+func (m *MockDomainService) RemoveAlias(ctx context.Context, domainID, aliasID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveAlias", ctx, domainID, aliasID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveAlias indicates an expected call of RemoveAlias.
+func (mr *MockDomainServiceMockRecorder) RemoveAlias(ctx, domainID, aliasID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveAlias", reflect.TypeOf((*MockDomainService)(nil).RemoveAlias), ctx, domainID, aliasID)
+}
+
+// ResolveDomain mocks base method.
+func (m *MockDomainService) ResolveDomain(ctx context.Context, hostname string) (*entities.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveDomain", ctx, hostname)
+	ret0, _ := ret[0].(*entities.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveDomain indicates an expected call of ResolveDomain.
+func (mr *MockDomainServiceMockRecorder) ResolveDomain(ctx, hostname any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveDomain", reflect.TypeOf((*MockDomainService)(nil).ResolveDomain), ctx, hostname)
+}
+
+// SetPrimaryAlias mocks base method.
+func (m *MockDomainService) SetPrimaryAlias(ctx context.Context, domainID, aliasID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPrimaryAlias", ctx, domainID, aliasID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPrimaryAlias indicates an expected call of SetPrimaryAlias.
+func (mr *MockDomainServiceMockRecorder) SetPrimaryAlias(ctx, domainID, aliasID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrimaryAlias", reflect.TypeOf((*MockDomainService)(nil).SetPrimaryAlias), ctx, domainID, aliasID)
+}
+
+// UpdateDataMasking mocks base method.
+func (m *MockDomainService) UpdateDataMasking(ctx context.Context, id uuid.UUID, settings *entities.DataMaskingSettings) (*entities.DataMaskingSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDataMasking", ctx, id, settings)
+	ret0, _ := ret[0].(*entities.DataMaskingSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDataMasking indicates an expected call of UpdateDataMasking.
+func (mr *MockDomainServiceMockRecorder) UpdateDataMasking(ctx, id, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDataMasking", reflect.TypeOf((*MockDomainService)(nil).UpdateDataMasking), ctx, id, settings)
+}
+
+// UpdateDomain mocks base method.
+func (m *MockDomainService) UpdateDomain(ctx context.Context, id uuid.UUID, name, domainStr, loginMode string, passwordPolicy *entities.PasswordPolicy, registration *entities.RegistrationSettings, branding *entities.DomainBranding, accountDeletion *entities.AccountDeletionSettings) (*entities.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDomain", ctx, id, name, domainStr, loginMode, passwordPolicy, registration, branding, accountDeletion)
+	ret0, _ := ret[0].(*entities.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDomain indicates an expected call of UpdateDomain.
+func (mr *MockDomainServiceMockRecorder) UpdateDomain(ctx, id, name, domainStr, loginMode, passwordPolicy, registration, branding, accountDeletion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDomain", reflect.TypeOf((*MockDomainService)(nil).UpdateDomain), ctx, id, name, domainStr, loginMode, passwordPolicy, registration, branding, accountDeletion)
+}
+
+// UpdateTokenSettings mocks base method.
+func (m *MockDomainService) UpdateTokenSettings(ctx context.Context, id uuid.UUID, settings *entities.DomainTokenSettings) (*entities.DomainTokenSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTokenSettings", ctx, id, settings)
+	ret0, _ := ret[0].(*entities.DomainTokenSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTokenSettings indicates an expected call of UpdateTokenSettings.
+func (mr *MockDomainServiceMockRecorder) UpdateTokenSettings(ctx, id, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTokenSettings", reflect.TypeOf((*MockDomainService)(nil).UpdateTokenSettings), ctx, id, settings)
+}
+
+// MockAuthService is a mock of AuthService interface.
+type MockAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthServiceMockRecorder is the mock recorder for MockAuthService.
+type MockAuthServiceMockRecorder struct {
+	mock *MockAuthService
+}
+
+// NewMockAuthService creates a new mock instance.
+func NewMockAuthService(ctrl *gomock.Controller) *MockAuthService {
+	mock := &MockAuthService{ctrl: ctrl}
+	mock.recorder = &MockAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthService) EXPECT() *MockAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// ChangeExpiredPassword mocks base method.
+func (m *MockAuthService) ChangeExpiredPassword(ctx context.Context, changeToken, newPassword string) (*services.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeExpiredPassword", ctx, changeToken, newPassword)
+	ret0, _ := ret[0].(*services.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeExpiredPassword indicates an expected call of ChangeExpiredPassword.
+func (mr *MockAuthServiceMockRecorder) ChangeExpiredPassword(ctx, changeToken, newPassword any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeExpiredPassword", reflect.TypeOf((*MockAuthService)(nil).ChangeExpiredPassword), ctx, changeToken, newPassword)
+}
+
+// ChangePassword mocks base method.
+func (m *MockAuthService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, userID, currentPassword, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthServiceMockRecorder) ChangePassword(ctx, userID, currentPassword, newPassword any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthService)(nil).ChangePassword), ctx, userID, currentPassword, newPassword)
+}
+
+// ClientCredentials mocks base method.
+func (m *MockAuthService) ClientCredentials(ctx context.Context, clientID, clientSecret, clientIP string, scopes []string) (*services.ClientCredentialsToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientCredentials", ctx, clientID, clientSecret, clientIP, scopes)
+	ret0, _ := ret[0].(*services.ClientCredentialsToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClientCredentials indicates an expected call of ClientCredentials.
+func (mr *MockAuthServiceMockRecorder) ClientCredentials(ctx, clientID, clientSecret, clientIP, scopes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientCredentials", reflect.TypeOf((*MockAuthService)(nil).ClientCredentials), ctx, clientID, clientSecret, clientIP, scopes)
+}
+
+// EndSession mocks base method.
+func (m *MockAuthService) EndSession(ctx context.Context, sessionToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndSession", ctx, sessionToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EndSession indicates an expected call of EndSession.
+func (mr *MockAuthServiceMockRecorder) EndSession(ctx, sessionToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndSession", reflect.TypeOf((*MockAuthService)(nil).EndSession), ctx, sessionToken)
+}
+
+// GetCapabilities mocks base method.
+func (m *MockAuthService) GetCapabilities(ctx context.Context, domainID uuid.UUID) (*services.Capabilities, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCapabilities", ctx, domainID)
+	ret0, _ := ret[0].(*services.Capabilities)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCapabilities indicates an expected call of GetCapabilities.
+func (mr *MockAuthServiceMockRecorder) GetCapabilities(ctx, domainID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapabilities", reflect.TypeOf((*MockAuthService)(nil).GetCapabilities), ctx, domainID)
+}
+
+// GetEffectivePermissions mocks base method.
+func (m *MockAuthService) GetEffectivePermissions(ctx context.Context, userID uuid.UUID) (*services.EffectivePermissions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEffectivePermissions", ctx, userID)
+	ret0, _ := ret[0].(*services.EffectivePermissions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEffectivePermissions indicates an expected call of GetEffectivePermissions.
+func (mr *MockAuthServiceMockRecorder) GetEffectivePermissions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEffectivePermissions", reflect.TypeOf((*MockAuthService)(nil).GetEffectivePermissions), ctx, userID)
+}
+
+// GetProfile mocks base method.
+func (m *MockAuthService) GetProfile(ctx context.Context, userID uuid.UUID) (*services.UserProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx, userID)
+	ret0, _ := ret[0].(*services.UserProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockAuthServiceMockRecorder) GetProfile(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockAuthService)(nil).GetProfile), ctx, userID)
+}
+
+// Impersonate mocks base method.
+func (m *MockAuthService) Impersonate(ctx context.Context, impersonator *services.TokenClaims, userID uuid.UUID, reason string) (*services.ImpersonationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Impersonate", ctx, impersonator, userID, reason)
+	ret0, _ := ret[0].(*services.ImpersonationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Impersonate indicates an expected call of Impersonate.
+func (mr *MockAuthServiceMockRecorder) Impersonate(ctx, impersonator, userID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Impersonate", reflect.TypeOf((*MockAuthService)(nil).Impersonate), ctx, impersonator, userID, reason)
+}
+
+// JWKS mocks base method.
+func (m *MockAuthService) JWKS() signing.JWKSet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(signing.JWKSet)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockAuthServiceMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockAuthService)(nil).JWKS))
+}
+
+// Login mocks base method.
+func (m *MockAuthService) Login(ctx context.Context, domainID uuid.UUID, username, password, clientIP string, opts services.LoginOptions) (*services.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, domainID, username, password, clientIP, opts)
+	ret0, _ := ret[0].(*services.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockAuthServiceMockRecorder) Login(ctx, domainID, username, password, clientIP, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthService)(nil).Login), ctx, domainID, username, password, clientIP, opts)
+}
+
+// RefreshSession mocks base method.
+func (m *MockAuthService) RefreshSession(ctx context.Context, domainID uuid.UUID, sessionToken string) (*services.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSession", ctx, domainID, sessionToken)
+	ret0, _ := ret[0].(*services.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshSession indicates an expected call of RefreshSession.
+func (mr *MockAuthServiceMockRecorder) RefreshSession(ctx, domainID, sessionToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSession", reflect.TypeOf((*MockAuthService)(nil).RefreshSession), ctx, domainID, sessionToken)
+}
+
+// ResolveDomainID mocks base method.
+func (m *MockAuthService) ResolveDomainID(ctx context.Context, hostname string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveDomainID", ctx, hostname)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveDomainID indicates an expected call of ResolveDomainID.
+func (mr *MockAuthServiceMockRecorder) ResolveDomainID(ctx, hostname any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveDomainID", reflect.TypeOf((*MockAuthService)(nil).ResolveDomainID), ctx, hostname)
+}
+
+// RevokeToken mocks base method.
+func (m *MockAuthService) RevokeToken(ctx context.Context, tokenString string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, tokenString)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockAuthServiceMockRecorder) RevokeToken(ctx, tokenString any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockAuthService)(nil).RevokeToken), ctx, tokenString)
+}
+
+// RunRevocationSweep mocks base method.
+func (m *MockAuthService) RunRevocationSweep(ctx context.Context, interval time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunRevocationSweep", ctx, interval)
+}
+
+// RunRevocationSweep indicates an expected call of RunRevocationSweep.
+func (mr *MockAuthServiceMockRecorder) RunRevocationSweep(ctx, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunRevocationSweep", reflect.TypeOf((*MockAuthService)(nil).RunRevocationSweep), ctx, interval)
+}
+
+// SigningAlgorithm mocks base method.
+func (m *MockAuthService) SigningAlgorithm() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SigningAlgorithm")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SigningAlgorithm indicates an expected call of SigningAlgorithm.
+func (mr *MockAuthServiceMockRecorder) SigningAlgorithm() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SigningAlgorithm", reflect.TypeOf((*MockAuthService)(nil).SigningAlgorithm))
+}
+
+// SigningKeyID mocks base method.
+func (m *MockAuthService) SigningKeyID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SigningKeyID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SigningKeyID indicates an expected call of SigningKeyID.
+func (mr *MockAuthServiceMockRecorder) SigningKeyID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SigningKeyID", reflect.TypeOf((*MockAuthService)(nil).SigningKeyID))
+}
+
+// StartPasswordlessLogin mocks base method.
+func (m *MockAuthService) StartPasswordlessLogin(ctx context.Context, domainID uuid.UUID, email, clientIP string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartPasswordlessLogin", ctx, domainID, email, clientIP)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartPasswordlessLogin indicates an expected call of StartPasswordlessLogin.
+func (mr *MockAuthServiceMockRecorder) StartPasswordlessLogin(ctx, domainID, email, clientIP any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartPasswordlessLogin", reflect.TypeOf((*MockAuthService)(nil).StartPasswordlessLogin), ctx, domainID, email, clientIP)
+}
+
+// StartSession mocks base method.
+func (m *MockAuthService) StartSession(ctx context.Context, userID uuid.UUID) (*services.HostedSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSession", ctx, userID)
+	ret0, _ := ret[0].(*services.HostedSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartSession indicates an expected call of StartSession.
+func (mr *MockAuthServiceMockRecorder) StartSession(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSession", reflect.TypeOf((*MockAuthService)(nil).StartSession), ctx, userID)
+}
+
+// ValidateToken mocks base method.
+func (m *MockAuthService) ValidateToken(ctx context.Context, tokenString string) (*services.TokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateToken", ctx, tokenString)
+	ret0, _ := ret[0].(*services.TokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateToken indicates an expected call of ValidateToken.
+func (mr *MockAuthServiceMockRecorder) ValidateToken(ctx, tokenString any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockAuthService)(nil).ValidateToken), ctx, tokenString)
+}
+
+// VerifyPasswordlessLogin mocks base method.
+func (m *MockAuthService) VerifyPasswordlessLogin(ctx context.Context, domainID uuid.UUID, email, code, token, clientIP string) (*services.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPasswordlessLogin", ctx, domainID, email, code, token, clientIP)
+	ret0, _ := ret[0].(*services.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyPasswordlessLogin indicates an expected call of VerifyPasswordlessLogin.
+func (mr *MockAuthServiceMockRecorder) VerifyPasswordlessLogin(ctx, domainID, email, code, token, clientIP any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPasswordlessLogin", reflect.TypeOf((*MockAuthService)(nil).VerifyPasswordlessLogin), ctx, domainID, email, code, token, clientIP)
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// OrgUnitRoleAssignment reports how many users of an org unit's subtree were given a role.
+type OrgUnitRoleAssignment struct {
+	RoleID  uuid.UUID `json:"role_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Updated int       `json:"updated" example:"12"` // users who held another role
+}
+
+type OrgUnitService interface {
+	GetOrgUnit(ctx context.Context, id uuid.UUID) (*entities.OrgUnit, error)
+	ListOrgUnits(ctx context.Context, domainID uuid.UUID) ([]*entities.OrgUnit, error)
+	CreateOrgUnit(ctx context.Context, domainID uuid.UUID, parentID *uuid.UUID, name, description string) (*entities.OrgUnit, error)
+	UpdateOrgUnit(ctx context.Context, id uuid.UUID, parentID *uuid.UUID, name, description string) (*entities.OrgUnit, error)
+	DeleteOrgUnit(ctx context.Context, id uuid.UUID) error
+	SetUserOrgUnit(ctx context.Context, userID uuid.UUID, orgUnitID *uuid.UUID) (*entities.User, error)
+	AssignRole(ctx context.Context, id, roleID uuid.UUID) (*OrgUnitRoleAssignment, error)
+}
+
+type orgUnitService struct {
+	repo       repositories.OrgUnitRepository
+	userRepo   repositories.UserRepository
+	roleRepo   repositories.RoleRepository
+	domainRepo repositories.DomainRepository
+	events     EventService
+}
+
+func NewOrgUnitService(repo repositories.OrgUnitRepository, userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, domainRepo repositories.DomainRepository, events EventService) OrgUnitService {
+	return &orgUnitService{repo: repo, userRepo: userRepo, roleRepo: roleRepo, domainRepo: domainRepo, events: events}
+}
+
+func errOrgUnitNameTaken() error {
+	return domainerrors.Conflict("org unit name is already used in this domain").WithCode("org_unit_name_taken")
+}
+
+func (s *orgUnitService) GetOrgUnit(ctx context.Context, id uuid.UUID) (*entities.OrgUnit, error) {
+	unit, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "org unit not found")
+	}
+	return unit, nil
+}
+
+// ListOrgUnits returns the org units of the domain, or for an org unit admin those of their unit's
+// subtree.
+func (s *orgUnitService) ListOrgUnits(ctx context.Context, domainID uuid.UUID) ([]*entities.OrgUnit, error) {
+	if principal := AdminPrincipalFrom(ctx); principal != nil && principal.Delegated() {
+		if domainID != principal.DomainID {
+			return nil, errOrgUnitAdminScope()
+		}
+		return s.repo.ListSubtree(ctx, domainID, principal.OrgUnitID)
+	}
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	return s.repo.GetByDomainID(ctx, domainID)
+}
+
+func (s *orgUnitService) CreateOrgUnit(ctx context.Context, domainID uuid.UUID, parentID *uuid.UUID, name, description string) (*entities.OrgUnit, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domainerrors.Validation("org unit name is required")
+	}
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	if err := s.ensureUnitInDomain(ctx, domainID, parentID, "parent org unit"); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByName(ctx, domainID, name); err == nil {
+		return nil, errOrgUnitNameTaken()
+	}
+
+	unit := &entities.OrgUnit{
+		DomainID:    domainID,
+		ParentID:    parentID,
+		Name:        name,
+		Description: strings.TrimSpace(description),
+	}
+	if err := s.repo.Create(ctx, unit); err != nil {
+		return nil, err
+	}
+	return unit, nil
+}
+
+// UpdateOrgUnit renames the org unit and moves it, with its subtree, under parentID, or to the top
+// of the tree when parentID is nil. A unit can't be moved under itself or one of its descendants.
+func (s *orgUnitService) UpdateOrgUnit(ctx context.Context, id uuid.UUID, parentID *uuid.UUID, name, description string) (*entities.OrgUnit, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domainerrors.Validation("org unit name is required")
+	}
+
+	unit, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "org unit not found")
+	}
+	if err := s.ensureUnitInDomain(ctx, unit.DomainID, parentID, "parent org unit"); err != nil {
+		return nil, err
+	}
+	if parentID != nil {
+		cycle, err := s.repo.InSubtree(ctx, unit.DomainID, unit.ID, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		if cycle {
+			return nil, domainerrors.Validation("an org unit cannot be moved under itself or its descendants").WithCode("org_unit_cycle")
+		}
+	}
+	if existing, err := s.repo.GetByName(ctx, unit.DomainID, name); err == nil && existing.ID != unit.ID {
+		return nil, errOrgUnitNameTaken()
+	}
+
+	unit.ParentID = parentID
+	unit.Name = name
+	unit.Description = strings.TrimSpace(description)
+	if err := s.repo.Update(ctx, unit); err != nil {
+		return nil, err
+	}
+	return unit, nil
+}
+
+// DeleteOrgUnit deletes an org unit without children. Its users are left without an org unit.
+func (s *orgUnitService) DeleteOrgUnit(ctx context.Context, id uuid.UUID) error {
+	unit, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return notFoundOr(err, "org unit not found")
+	}
+	children, err := s.repo.CountChildren(ctx, unit.DomainID, unit.ID)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return domainerrors.Conflict("org unit has %d child units; move or delete them first", children).WithCode("org_unit_not_empty")
+	}
+	if err := s.repo.Delete(ctx, unit.DomainID, unit.ID); err != nil {
+		return notFoundOr(err, "org unit not found")
+	}
+	return nil
+}
+
+// SetUserOrgUnit moves the user into an org unit of their domain, or out of any when orgUnitID is
+// nil. Org unit admins can only move users between the units of their subtree.
+func (s *orgUnitService) SetUserOrgUnit(ctx context.Context, userID uuid.UUID, orgUnitID *uuid.UUID) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "OrgUnitService.SetUserOrgUnit")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundOr(err, "user not found")
+	}
+	if err := s.ensureUnitInDomain(ctx, user.DomainID, orgUnitID, "org unit"); err != nil {
+		return nil, err
+	}
+	if principal := AdminPrincipalFrom(ctx); principal != nil && principal.Delegated() {
+		if orgUnitID == nil {
+			return nil, errOrgUnitAdminScope()
+		}
+		inScope, err := s.repo.InSubtree(ctx, user.DomainID, principal.OrgUnitID, *orgUnitID)
+		if err != nil {
+			return nil, err
+		}
+		if !inScope {
+			return nil, errOrgUnitAdminScope()
+		}
+	}
+
+	user.OrgUnitID = orgUnitID
+	if err := s.userRepo.SetOrgUnit(ctx, user); err != nil {
+		return nil, err
+	}
+	s.events.Publish(ctx, user.DomainID, EventUserUpdated, user.ID, user)
+	return user, nil
+}
+
+// AssignRole gives the role to every user of the org unit and its descendants. Users whose role
+// changed have their sessions revoked, as tokens carry the role.
+func (s *orgUnitService) AssignRole(ctx context.Context, id, roleID uuid.UUID) (*OrgUnitRoleAssignment, error) {
+	ctx, span := tracer.Start(ctx, "OrgUnitService.AssignRole")
+	defer span.End()
+
+	unit, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "org unit not found")
+	}
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, notFoundOr(err, "role not found")
+	}
+	if role.DomainID != unit.DomainID {
+		return nil, domainerrors.Validation("role belongs to a different domain than the org unit")
+	}
+
+	users, err := s.userRepo.AssignRoleInOrgUnit(ctx, unit.DomainID, unit.ID, role.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if err := revokeSessions(ctx, s.userRepo, user); err != nil {
+			return nil, err
+		}
+		s.events.Publish(ctx, user.DomainID, EventUserUpdated, user.ID, user)
+	}
+	return &OrgUnitRoleAssignment{RoleID: role.ID, Updated: len(users)}, nil
+}
+
+// ensureUnitInDomain checks an optional org unit exists in the domain. what names it in errors.
+func (s *orgUnitService) ensureUnitInDomain(ctx context.Context, domainID uuid.UUID, id *uuid.UUID, what string) error {
+	if id == nil {
+		return nil
+	}
+	unit, err := s.repo.GetByID(ctx, *id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && unit.DomainID != domainID) {
+		return domainerrors.Validation("%s not found in this domain", what)
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/metrics"
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// tokenPurposePasswordChange marks a token that can only be exchanged at /auth/change-expired-password
+	tokenPurposePasswordChange = "password_change"
+	passwordChangeTokenTTL     = 10 * time.Minute
+)
+
+// PasswordExpiredError is returned by Login when the password is older than the domain's
+// max_age_days. The change token lets the client set a new password and finish the login.
+type PasswordExpiredError struct {
+	ChangeToken string
+	ExpiresAt   time.Time
+}
+
+func (e *PasswordExpiredError) Error() string {
+	return "password expired"
+}
+
+// passwordExpired reports whether the user's password is past the domain's maximum age.
+func passwordExpired(domain *entities.Domain, user *entities.User, now time.Time) bool {
+	maxAge := effectivePasswordPolicy(domain).MaxAgeDays
+	if maxAge == 0 {
+		return false
+	}
+	return now.After(user.PasswordChangedAt.AddDate(0, 0, maxAge))
+}
+
+// requirePasswordChange issues the change token for a user who logged in with an expired password.
+func (s *authService) requirePasswordChange(user *entities.User) error {
+	now := time.Now()
+	if accountDisabled(user, now) {
+		return domainerrors.Forbidden("account is disabled")
+	}
+
+	expiresAt := now.Add(passwordChangeTokenTTL)
+	claims := TokenClaims{
+		UserID:   user.ID,
+		DomainID: user.DomainID,
+		Username: user.Username,
+		RoleID:   user.RoleID,
+		Purpose:  tokenPurposePasswordChange,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "nusarithm-iam",
+			Subject:   user.ID.String(),
+		},
+	}
+	token, err := s.keys.Sign(claims)
+	if err != nil {
+		return fmt.Errorf("failed to generate password change token: %w", err)
+	}
+	return &PasswordExpiredError{ChangeToken: token, ExpiresAt: expiresAt}
+}
+
+// ChangeExpiredPassword sets a new password using the change token from an expired-password login
+// and completes the login. Each change token works once.
+func (s *authService) ChangeExpiredPassword(ctx context.Context, changeToken, newPassword string) (resp *LoginResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ChangeExpiredPassword")
+	defer span.End()
+	defer func() { metrics.RecordLogin(err == nil) }()
+
+	claims, err := s.parseToken(changeToken)
+	if err != nil || claims.Purpose != tokenPurposePasswordChange {
+		return nil, domainerrors.Unauthorized("invalid or expired password change token")
+	}
+	ctx = tenancy.WithDomain(ctx, claims.DomainID)
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, domainerrors.Unauthorized("invalid or expired password change token")
+	}
+	// A password changed after the token was issued means the token has been used
+	if claims.IssuedAt == nil || user.PasswordChangedAt.After(claims.IssuedAt.Time) {
+		return nil, domainerrors.Unauthorized("invalid or expired password change token")
+	}
+	domain, err := s.domainRepo.GetByID(ctx, user.DomainID)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	if err := s.passwords.set(ctx, domain, user, newPassword); err != nil {
+		return nil, err
+	}
+	return s.issueLogin(ctx, user, nil, nil)
+}
+
+// ChangePassword lets a signed-in user replace their own password after confirming the current one.
+func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) error {
+	ctx, span := tracer.Start(ctx, "AuthService.ChangePassword")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domainerrors.NotFound("user not found")
+	}
+	if user.BreakGlass {
+		return errBreakGlassManaged()
+	}
+	if user.IsService() {
+		return errServiceAccount()
+	}
+	domain, err := s.domainRepo.GetByID(ctx, user.DomainID)
+	if err != nil {
+		return domainerrors.NotFound("domain not found")
+	}
+	if domain.LoginMode == entities.LoginModePasswordless {
+		return domainerrors.Validation("passwords are disabled for this domain")
+	}
+	if !s.verifyPassword(user.PasswordHash, currentPassword) {
+		return domainerrors.Unauthorized("current password is incorrect").WithCode("invalid_current_password")
+	}
+	return s.passwords.set(ctx, domain, user, newPassword)
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/repositories"
+)
+
+// passwordStore writes user passwords for the user and auth services, enforcing the domain's
+// password policy and history.
+type passwordStore struct {
+	userRepo    repositories.UserRepository
+	historyRepo repositories.PasswordHistoryRepository
+}
+
+// set checks a new password against the domain's policy and the user's recent passwords,
+// then stores it, moves the replaced hash into the password history and revokes the user's tokens.
+func (p *passwordStore) set(ctx context.Context, domain *entities.Domain, user *entities.User, password string) error {
+	if err := checkPasswordPolicy(domain, password); err != nil {
+		return err
+	}
+
+	hashedPassword := hashPassword(password)
+	reused, err := p.reuses(ctx, domain, user, hashedPassword)
+	if err != nil {
+		return err
+	}
+	if reused {
+		return errPasswordReused()
+	}
+
+	// History is kept up to the largest allowed history_count so raising the limit takes effect at once
+	if user.PasswordHash != "" {
+		if err := p.historyRepo.Add(ctx, user.DomainID, user.ID, user.PasswordHash, maxPasswordHistoryCount); err != nil {
+			return err
+		}
+	}
+	if err := p.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return err
+	}
+	user.PasswordHash = hashedPassword
+	user.PasswordChangedAt = time.Now()
+	return revokeSessions(ctx, p.userRepo, user)
+}
+
+// reuses reports whether the hash matches one of the user's last history_count passwords,
+// counting the current one.
+func (p *passwordStore) reuses(ctx context.Context, domain *entities.Domain, user *entities.User, hashedPassword string) (bool, error) {
+	count := effectivePasswordPolicy(domain).HistoryCount
+	if count == 0 {
+		return false, nil
+	}
+	if hashedPassword == user.PasswordHash {
+		return true, nil
+	}
+	if count == 1 {
+		return false, nil
+	}
+
+	previous, err := p.historyRepo.Recent(ctx, user.DomainID, user.ID, count-1)
+	if err != nil {
+		return false, err
+	}
+	for _, hash := range previous {
+		if hash == hashedPassword {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func hashPassword(password string) string {
+	hash := sha256.Sum256([]byte(password))
+	return fmt.Sprintf("%x", hash)
+}
+
+// dummyPasswordHash is checked against the password of sign-ins to accounts that don't exist, so
+// they do the same work as sign-ins with a wrong password.
+var dummyPasswordHash = hashPassword("no account has this password")
+
+// passwordMatches compares in constant time, so response times don't tell how much of the hash
+// a guess got right.
+func passwordMatches(hashedPassword, password string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashPassword(password)), []byte(hashedPassword)) == 1
+}
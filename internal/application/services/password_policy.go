@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxPasswordMinLength    = 128
+	maxPasswordHistoryCount = 24
+	maxPasswordAgeDays      = 3650
+)
+
+// effectivePasswordPolicy fills in the defaults for settings the domain left unset.
+func effectivePasswordPolicy(domain *entities.Domain) *entities.PasswordPolicy {
+	policy := domain.PasswordPolicy
+	if policy.MinLength == 0 {
+		policy.MinLength = entities.DefaultPasswordMinLength
+	}
+	return &policy
+}
+
+// validatePasswordPolicy rejects settings outside the supported ranges.
+func validatePasswordPolicy(policy *entities.PasswordPolicy) error {
+	if policy.MinLength != 0 && (policy.MinLength < entities.DefaultPasswordMinLength || policy.MinLength > maxPasswordMinLength) {
+		return domainerrors.Validation("min_length must be between %d and %d", entities.DefaultPasswordMinLength, maxPasswordMinLength)
+	}
+	if policy.HistoryCount < 0 || policy.HistoryCount > maxPasswordHistoryCount {
+		return domainerrors.Validation("history_count must be between 0 and %d", maxPasswordHistoryCount)
+	}
+	if policy.MaxAgeDays < 0 || policy.MaxAgeDays > maxPasswordAgeDays {
+		return domainerrors.Validation("max_age_days must be between 0 and %d", maxPasswordAgeDays)
+	}
+	return nil
+}
+
+// checkPasswordPolicy reports every rule of the domain's policy the password breaks.
+func checkPasswordPolicy(domain *entities.Domain, password string) error {
+	policy := effectivePasswordPolicy(domain)
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	var broken []string
+	if len([]rune(password)) < policy.MinLength {
+		broken = append(broken, fmt.Sprintf("be at least %d characters", policy.MinLength))
+	}
+	if policy.RequireUppercase && !upper {
+		broken = append(broken, "contain an uppercase letter")
+	}
+	if policy.RequireLowercase && !lower {
+		broken = append(broken, "contain a lowercase letter")
+	}
+	if policy.RequireDigit && !digit {
+		broken = append(broken, "contain a digit")
+	}
+	if policy.RequireSymbol && !symbol {
+		broken = append(broken, "contain a symbol")
+	}
+	if len(broken) > 0 {
+		return domainerrors.Validation("password must %s", strings.Join(broken, ", ")).WithCode("password_policy_violation")
+	}
+	return nil
+}
+
+func errPasswordReused() error {
+	return domainerrors.Validation("password was used recently").WithCode("password_reused")
+}
+
+// GetPasswordPolicy returns the domain's password policy with defaults applied.
+func (s *domainService) GetPasswordPolicy(ctx context.Context, id uuid.UUID) (*entities.PasswordPolicy, error) {
+	domain, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	return effectivePasswordPolicy(domain), nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/metrics"
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/google/uuid"
+)
+
+// StartPasswordlessLogin emails a one-time code and magic link to the user. Unknown emails are
+// accepted silently so the endpoint can't be used to discover accounts.
+func (s *authService) StartPasswordlessLogin(ctx context.Context, domainID uuid.UUID, email, clientIP string) error {
+	ctx, span := tracer.Start(ctx, "AuthService.StartPasswordlessLogin")
+	ctx = tenancy.WithDomain(ctx, domainID)
+	defer span.End()
+	// Storing the code takes longer than finding no account, so every answer is padded; the email
+	// is sent after the response, so the mail server's latency can't tell either
+	defer padLatency(ctx, time.Now())
+
+	if err := s.requirePasswordless(ctx, domainID); err != nil {
+		return err
+	}
+	if _, err := s.assessRisk(ctx, domainID, clientIP, ""); err != nil {
+		s.publishRiskOutcome(ctx, domainID, email, loginMethodPasswordless, clientIP, err)
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmailAndDomain(ctx, strings.TrimSpace(email), domainID)
+	if err != nil {
+		s.riskService.RecordFailure(clientIP)
+		return nil
+	}
+	// Break-glass accounts sign in with their local password only, service accounts with client
+	// credentials
+	if accountDisabled(user, time.Now()) || user.BreakGlass || user.IsService() {
+		return nil
+	}
+
+	code, err := randomDigits(6)
+	if err != nil {
+		return fmt.Errorf("failed to generate code: %w", err)
+	}
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+	rawToken := hex.EncodeToString(token)
+
+	loginCode := &entities.LoginCode{
+		DomainID:  domainID,
+		UserID:    user.ID,
+		CodeHash:  hashSecret(code),
+		TokenHash: hashSecret(rawToken),
+		ExpiresAt: time.Now().Add(s.passwordless.CodeTTL),
+	}
+	if err := s.codeRepo.Create(ctx, loginCode); err != nil {
+		return err
+	}
+
+	link := s.passwordless.LinkURL + "?token=" + url.QueryEscape(rawToken)
+	data := map[string]any{"Code": code, "Link": link, "ExpiresIn": s.passwordless.CodeTTL.String()}
+	attempt := &LoginAttempt{UserID: &user.ID, Username: user.Email, Method: loginMethodPasswordless, ClientIP: clientIP, At: time.Now().UTC()}
+	// The code isn't queued, since the job queue stores its payload; failures are only logged, as
+	// answering with an error would tell the email is registered
+	go func(ctx context.Context) {
+		if err := s.emails.Send(ctx, domainID, user.Email, EmailVerification, data); err != nil {
+			log.Printf("Failed to send login code to user %s: %v", user.ID, err)
+			return
+		}
+		s.events.Publish(ctx, domainID, EventLoginCodeSent, user.ID, attempt)
+	}(context.WithoutCancel(ctx))
+	return nil
+}
+
+// VerifyPasswordlessLogin signs the user in with either the emailed code (with the email) or the
+// magic link token. Codes are single-use and locked after too many wrong guesses.
+func (s *authService) VerifyPasswordlessLogin(ctx context.Context, domainID uuid.UUID, email, code, token, clientIP string) (resp *LoginResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.VerifyPasswordlessLogin")
+	ctx = tenancy.WithDomain(ctx, domainID)
+	defer span.End()
+	defer func() { metrics.RecordLogin(err == nil) }()
+	defer padFailure(ctx, time.Now(), &err)
+
+	if err := s.requirePasswordless(ctx, domainID); err != nil {
+		return nil, err
+	}
+	risk, err := s.assessRisk(ctx, domainID, clientIP, "")
+	if err != nil {
+		s.publishRiskOutcome(ctx, domainID, email, loginMethodPasswordless, clientIP, err)
+		return nil, err
+	}
+
+	loginCode, err := s.findLoginCode(ctx, domainID, email, code, token)
+	if err != nil {
+		s.riskService.RecordFailure(clientIP)
+		s.publishLogin(ctx, domainID, nil, email, loginMethodPasswordless, clientIP, loginFailureInvalidCode)
+		return nil, err
+	}
+	if err := s.codeRepo.Consume(ctx, domainID, loginCode.ID); err != nil {
+		return nil, domainerrors.Unauthorized("invalid or expired code")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, loginCode.UserID)
+	if err != nil {
+		return nil, domainerrors.Unauthorized("invalid or expired code")
+	}
+	s.riskService.RecordSuccess(clientIP)
+
+	resp, err = s.issueLogin(ctx, user, risk, nil)
+	s.publishLoginResult(ctx, user, user.Email, loginMethodPasswordless, clientIP, err)
+	return resp, err
+}
+
+func (s *authService) findLoginCode(ctx context.Context, domainID uuid.UUID, email, code, token string) (*entities.LoginCode, error) {
+	if token != "" {
+		loginCode, err := s.codeRepo.GetByTokenHash(ctx, domainID, hashSecret(token))
+		if err != nil || loginCode.DomainID != domainID || loginCode.ConsumedAt != nil || time.Now().After(loginCode.ExpiresAt) {
+			return nil, domainerrors.Unauthorized("invalid or expired code")
+		}
+		return loginCode, nil
+	}
+
+	if email == "" || code == "" {
+		return nil, domainerrors.Validation("provide email and code, or token")
+	}
+
+	user, err := s.userRepo.GetByEmailAndDomain(ctx, strings.TrimSpace(email), domainID)
+	if err != nil {
+		return nil, domainerrors.Unauthorized("invalid or expired code")
+	}
+
+	loginCode, err := s.codeRepo.GetLatestActive(ctx, domainID, user.ID)
+	if err != nil || loginCode.Attempts >= s.passwordless.MaxAttempts {
+		return nil, domainerrors.Unauthorized("invalid or expired code")
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(strings.TrimSpace(code))), []byte(loginCode.CodeHash)) != 1 {
+		if err := s.codeRepo.IncrementAttempts(ctx, domainID, loginCode.ID); err != nil {
+			log.Printf("Failed to record login code attempt: %v", err)
+		}
+		return nil, domainerrors.Unauthorized("invalid or expired code")
+	}
+	return loginCode, nil
+}
+
+func (s *authService) requirePasswordless(ctx context.Context, domainID uuid.UUID) error {
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return domainerrors.NotFound("domain not found")
+	}
+	if domain.LoginMode != entities.LoginModePasswordless {
+		return domainerrors.Forbidden("passwordless login is not enabled for this domain")
+	}
+	return nil
+}
+
+func randomDigits(n int) (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+	value, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", n, value), nil
+}
+
+func hashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
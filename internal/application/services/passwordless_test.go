@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+type passwordlessDomains struct {
+	repositories.DomainRepository
+}
+
+func (passwordlessDomains) GetByID(ctx context.Context, id uuid.UUID) (*entities.Domain, error) {
+	return &entities.Domain{DomainID: id, LoginMode: entities.LoginModePasswordless}, nil
+}
+
+// knownUsers has the one user jane@example.com.
+type knownUsers struct {
+	repositories.UserRepository
+}
+
+func (knownUsers) GetByEmailAndDomain(ctx context.Context, email string, domainID uuid.UUID) (*entities.User, error) {
+	if email != "jane@example.com" {
+		return nil, sql.ErrNoRows
+	}
+	return &entities.User{ID: uuid.New(), DomainID: domainID, Email: email}, nil
+}
+
+type storedCodes struct {
+	repositories.LoginCodeRepository
+}
+
+func (storedCodes) Create(ctx context.Context, code *entities.LoginCode) error {
+	return nil
+}
+
+type allowAll struct {
+	LoginRiskService
+}
+
+func (allowAll) Assess(ctx context.Context, domainID uuid.UUID, ip string) (*RiskAssessment, error) {
+	return &RiskAssessment{Action: RiskActionAllow}, nil
+}
+
+func (allowAll) RecordFailure(ip string) {}
+
+// slowEmails is a mail server taking a second to answer.
+type slowEmails struct {
+	EmailService
+	sent chan string
+}
+
+func (e *slowEmails) Send(ctx context.Context, domainID uuid.UUID, to, name string, data map[string]any) error {
+	time.Sleep(time.Second)
+	e.sent <- to
+	return nil
+}
+
+type discardedEvents struct {
+	EventService
+}
+
+func (discardedEvents) Publish(ctx context.Context, domainID uuid.UUID, eventType string, subjectID uuid.UUID, payload interface{}) {
+}
+
+func TestStartPasswordlessLoginTiming(t *testing.T) {
+	emails := &slowEmails{sent: make(chan string, 1)}
+	s := &authService{
+		domainRepo:   passwordlessDomains{},
+		userRepo:     knownUsers{},
+		codeRepo:     storedCodes{},
+		riskService:  allowAll{},
+		emails:       emails,
+		events:       discardedEvents{},
+		passwordless: &config.PasswordlessConfig{CodeTTL: 10 * time.Minute, LinkURL: "https://iam.example.com/login"},
+	}
+
+	took := make(map[string]time.Duration)
+	for _, email := range []string{"jane@example.com", "nobody@example.com"} {
+		start := time.Now()
+		if err := s.StartPasswordlessLogin(context.Background(), uuid.New(), email, "192.0.2.10"); err != nil {
+			t.Fatalf("%s: err = %v, want the same nil answer for every email", email, err)
+		}
+		took[email] = time.Since(start)
+	}
+	known, unknown := took["jane@example.com"], took["nobody@example.com"]
+	if known < minLoginResponseTime || unknown < minLoginResponseTime {
+		t.Errorf("answered in %v and %v, want at least %v", known, unknown, minLoginResponseTime)
+	}
+	if diff := (known - unknown).Abs(); diff > 100*time.Millisecond {
+		t.Errorf("known email answered in %v, unknown in %v; want the same time", known, unknown)
+	}
+
+	// The code is still sent once the mail server answers
+	select {
+	case to := <-emails.sent:
+		if to != "jane@example.com" {
+			t.Errorf("code sent to %s", to)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("code was never sent")
+	}
+}
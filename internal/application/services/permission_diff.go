@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// PermissionDiff is the change in one user's effective permissions.
+type PermissionDiff struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+	Gained   []string  `json:"gained"`
+	Lost     []string  `json:"lost"`
+}
+
+// roleChangeDiffs computes how each user's effective permissions change when override is
+// applied. Permissions still granted by another role the user holds are not reported as lost.
+// Users whose access does not change are omitted.
+func (r *permissionResolver) roleChangeDiffs(ctx context.Context, users []*entities.User, override *roleOverride) ([]*PermissionDiff, error) {
+	var diffs []*PermissionDiff
+	for _, user := range users {
+		before, err := r.grants(ctx, user, nil)
+		if err != nil {
+			return nil, err
+		}
+		after, err := r.grants(ctx, user, override)
+		if err != nil {
+			return nil, err
+		}
+
+		gained, lost := diffGrants(before, after)
+		if len(gained) == 0 && len(lost) == 0 {
+			continue
+		}
+		diffs = append(diffs, &PermissionDiff{
+			UserID:   user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+			Gained:   gained,
+			Lost:     lost,
+		})
+	}
+	return diffs, nil
+}
+
+// diffGrants returns the entries only in after (gained) and only in before (lost), in the
+// order they appear in their input.
+func diffGrants(before, after []string) (gained, lost []string) {
+	inBefore := make(map[string]bool, len(before))
+	for _, name := range before {
+		inBefore[name] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, name := range after {
+		inAfter[name] = true
+		if !inBefore[name] {
+			gained = append(gained, name)
+		}
+	}
+	for _, name := range before {
+		if !inAfter[name] {
+			lost = append(lost, name)
+		}
+	}
+	return gained, lost
+}
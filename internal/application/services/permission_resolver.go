@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// permissionResolver computes a user's effective roles and permissions: the direct role
+// plus every role inherited through group membership.
+type permissionResolver struct {
+	roleRepo  repositories.RoleRepository
+	permRepo  repositories.PermissionRepository
+	groupRepo repositories.GroupRepository
+}
+
+// roleOverride substitutes one role's claims and catalog permissions, e.g. for simulation.
+type roleOverride struct {
+	RoleID      uuid.UUID
+	Claims      map[string]interface{}
+	Permissions []*entities.Permission
+}
+
+// effectiveRoles returns the direct role first, followed by group roles without duplicates.
+func (r *permissionResolver) effectiveRoles(ctx context.Context, user *entities.User) ([]*entities.Role, error) {
+	role, err := r.roleRepo.GetByID(ctx, user.RoleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	roles := []*entities.Role{role}
+
+	inherited, err := r.groupRepo.ListInheritedRoleIDs(ctx, user.DomainID, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group roles: %w", err)
+	}
+	for _, roleID := range inherited {
+		if roleID == role.ID {
+			continue
+		}
+		groupRole, err := r.roleRepo.GetByID(ctx, roleID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get group role: %w", err)
+		}
+		roles = append(roles, groupRole)
+	}
+	return roles, nil
+}
+
+// grants returns the sorted union of permissions across the user's effective roles.
+func (r *permissionResolver) grants(ctx context.Context, user *entities.User, override *roleOverride) ([]string, error) {
+	roles, err := r.effectiveRoles(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]struct{})
+	for _, role := range roles {
+		granted, err := r.roleGrants(ctx, user.DomainID, role, override)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range granted {
+			set[name] = struct{}{}
+		}
+	}
+
+	permissions := make([]string, 0, len(set))
+	for name := range set {
+		permissions = append(permissions, name)
+	}
+	sort.Strings(permissions)
+	return permissions, nil
+}
+
+// roleGrants returns the permissions of a single role: its claims and catalog permissions.
+func (r *permissionResolver) roleGrants(ctx context.Context, domainID uuid.UUID, role *entities.Role, override *roleOverride) ([]string, error) {
+	if override != nil && override.RoleID == role.ID {
+		return effectivePermissions(override.Claims, override.Permissions), nil
+	}
+	assigned, err := r.permRepo.GetByRoleID(ctx, domainID, role.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+	return effectivePermissions(role.RoleClaims, assigned), nil
+}
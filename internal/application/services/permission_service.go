@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PermissionService interface {
+	ListPermissions(ctx context.Context, domainID uuid.UUID) ([]*entities.Permission, error)
+	CreatePermission(ctx context.Context, domainID uuid.UUID, resource, action, description string) (*entities.Permission, error)
+	DeletePermission(ctx context.Context, id uuid.UUID) error
+	ListRolePermissions(ctx context.Context, roleID uuid.UUID) ([]*entities.Permission, error)
+	AssignPermission(ctx context.Context, roleID, permissionID uuid.UUID) error
+	RevokePermission(ctx context.Context, roleID, permissionID uuid.UUID) error
+}
+
+type permissionService struct {
+	repo       repositories.PermissionRepository
+	roleRepo   repositories.RoleRepository
+	domainRepo repositories.DomainRepository
+}
+
+func NewPermissionService(repo repositories.PermissionRepository, roleRepo repositories.RoleRepository, domainRepo repositories.DomainRepository) PermissionService {
+	return &permissionService{
+		repo:       repo,
+		roleRepo:   roleRepo,
+		domainRepo: domainRepo,
+	}
+}
+
+func (s *permissionService) ListPermissions(ctx context.Context, domainID uuid.UUID) ([]*entities.Permission, error) {
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	return s.repo.GetByDomainID(ctx, domainID)
+}
+
+func (s *permissionService) CreatePermission(ctx context.Context, domainID uuid.UUID, resource, action, description string) (*entities.Permission, error) {
+	resource = strings.ToLower(strings.TrimSpace(resource))
+	action = strings.ToLower(strings.TrimSpace(action))
+	if resource == "" || action == "" {
+		return nil, domainerrors.Validation("resource and action are required")
+	}
+	if strings.Contains(resource, ":") || strings.Contains(action, ":") {
+		return nil, domainerrors.Validation("resource and action must not contain ':'")
+	}
+
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	name := resource + ":" + action
+	if _, err := s.repo.GetByName(ctx, domainID, name); err == nil {
+		return nil, domainerrors.Conflict("permission already exists in this domain").WithCode("permission_exists")
+	}
+
+	permission := &entities.Permission{
+		DomainID:    domainID,
+		Name:        name,
+		Resource:    resource,
+		Action:      action,
+		Description: strings.TrimSpace(description),
+	}
+	if err := s.repo.Create(ctx, permission); err != nil {
+		return nil, err
+	}
+	return permission, nil
+}
+
+func (s *permissionService) DeletePermission(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *permissionService) ListRolePermissions(ctx context.Context, roleID uuid.UUID) ([]*entities.Permission, error) {
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, domainerrors.NotFound("role not found")
+	}
+	return s.repo.GetByRoleID(ctx, role.DomainID, role.ID)
+}
+
+func (s *permissionService) AssignPermission(ctx context.Context, roleID, permissionID uuid.UUID) error {
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return domainerrors.NotFound("role not found")
+	}
+
+	permission, err := s.repo.GetByID(ctx, permissionID)
+	if err != nil {
+		return domainerrors.NotFound("permission not found")
+	}
+
+	// A role may only draw from its own domain's catalog
+	if permission.DomainID != role.DomainID {
+		return domainerrors.Validation("permission belongs to a different domain than the role")
+	}
+
+	return s.repo.AssignToRole(ctx, role.DomainID, role.ID, permission.ID)
+}
+
+func (s *permissionService) RevokePermission(ctx context.Context, roleID, permissionID uuid.UUID) error {
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return domainerrors.NotFound("role not found")
+	}
+
+	if err := s.repo.RevokeFromRole(ctx, role.DomainID, role.ID, permissionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domainerrors.NotFound("permission is not assigned to this role")
+		}
+		return err
+	}
+	return nil
+}
+
+// effectivePermissions merges catalog permissions with the permission-style entries of free-form
+// role claims: keys shaped like resource:action (or *) and strings in the "permissions" array.
+func effectivePermissions(claims map[string]interface{}, assigned []*entities.Permission) []string {
+	set := make(map[string]struct{})
+	for _, permission := range assigned {
+		set[permission.Name] = struct{}{}
+	}
+	for key := range claims {
+		if key == "*" || strings.Contains(key, ":") {
+			set[key] = struct{}{}
+		}
+	}
+	if list, ok := claims["permissions"].([]interface{}); ok {
+		for _, entry := range list {
+			if name, ok := entry.(string); ok && name != "" {
+				set[name] = struct{}{}
+			}
+		}
+	}
+
+	permissions := make([]string, 0, len(set))
+	for name := range set {
+		permissions = append(permissions, name)
+	}
+	sort.Strings(permissions)
+	return permissions
+}
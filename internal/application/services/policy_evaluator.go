@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"backend/internal/application/policy"
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// policyEvaluator loads a domain's ABAC policies and builds the attributes they are evaluated
+// against.
+type policyEvaluator struct {
+	repo       repositories.PolicyRepository
+	domainRepo repositories.DomainRepository
+	groupRepo  repositories.GroupRepository
+	resolver   *permissionResolver
+}
+
+func newPolicyEvaluator(repo repositories.PolicyRepository, domainRepo repositories.DomainRepository, roleRepo repositories.RoleRepository, permRepo repositories.PermissionRepository, groupRepo repositories.GroupRepository) *policyEvaluator {
+	return &policyEvaluator{
+		repo:       repo,
+		domainRepo: domainRepo,
+		groupRepo:  groupRepo,
+		resolver:   &permissionResolver{roleRepo: roleRepo, permRepo: permRepo, groupRepo: groupRepo},
+	}
+}
+
+// policies returns the parsed policies of the domain.
+func (e *policyEvaluator) policies(ctx context.Context, domainID uuid.UUID) ([]policy.Policy, error) {
+	stored, err := e.repo.GetByDomainID(ctx, domainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policies: %w", err)
+	}
+	policies := make([]policy.Policy, 0, len(stored))
+	for _, p := range stored {
+		doc, err := policy.Parse(p.Document)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %w", p.Name, err)
+		}
+		policies = append(policies, policy.Policy{ID: p.ID.String(), Name: p.Name, Document: doc})
+	}
+	return policies, nil
+}
+
+// userAttributes builds the user, domain and claims namespaces. Claims merge across the user's
+// effective roles; on conflicting keys the direct role wins over group roles.
+func (e *policyEvaluator) userAttributes(ctx context.Context, user *entities.User) (map[string]interface{}, error) {
+	roles, err := e.resolver.effectiveRoles(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	claims := make(map[string]interface{})
+	for i := len(roles) - 1; i >= 0; i-- {
+		for key, value := range roles[i].RoleClaims {
+			claims[key] = value
+		}
+	}
+
+	grants, err := e.resolver.grants(ctx, user, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := e.groupRepo.GetByUserID(ctx, user.DomainID, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups: %w", err)
+	}
+	groupNames := make([]interface{}, 0, len(groups))
+	for _, group := range groups {
+		groupNames = append(groupNames, group.Name)
+	}
+
+	domain, err := e.domainAttributes(ctx, user.DomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"user": map[string]interface{}{
+			"id":          user.ID.String(),
+			"username":    user.Username,
+			"email":       user.Email,
+			"first_name":  user.FirstName,
+			"last_name":   user.LastName,
+			"role_id":     user.RoleID.String(),
+			"role":        roles[0].RoleName,
+			"groups":      groupNames,
+			"permissions": attributeList(grants),
+		},
+		"domain": domain,
+		"claims": claims,
+	}, nil
+}
+
+// roleAttributes builds the attributes of a hypothetical user holding only the role and no
+// groups. User fields other than the role are absent, so conditions on them don't match.
+func (e *policyEvaluator) roleAttributes(ctx context.Context, role *entities.Role) (map[string]interface{}, error) {
+	grants, err := e.resolver.roleGrants(ctx, role.DomainID, role, nil)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := e.domainAttributes(ctx, role.DomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := make(map[string]interface{}, len(role.RoleClaims))
+	for key, value := range role.RoleClaims {
+		claims[key] = value
+	}
+	return map[string]interface{}{
+		"user": map[string]interface{}{
+			"role_id":     role.ID.String(),
+			"role":        role.RoleName,
+			"groups":      []interface{}{},
+			"permissions": attributeList(grants),
+		},
+		"domain": domain,
+		"claims": claims,
+	}, nil
+}
+
+func (e *policyEvaluator) domainAttributes(ctx context.Context, domainID uuid.UUID) (map[string]interface{}, error) {
+	domain, err := e.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain: %w", err)
+	}
+	return map[string]interface{}{
+		"id":        domain.DomainID.String(),
+		"name":      domain.Name,
+		"domain":    domain.Domain,
+		"residency": domain.Residency,
+	}, nil
+}
+
+// evaluatePolicies evaluates the policies for one request, adding the resource, action and context
+// namespaces to a copy of the subject's attributes.
+func evaluatePolicies(policies []policy.Policy, subject map[string]interface{}, resource, action string, resourceAttributes, requestContext map[string]interface{}) *policy.Decision {
+	attributes := make(map[string]interface{}, len(subject)+3)
+	for key, value := range subject {
+		attributes[key] = value
+	}
+	resourceNamespace := map[string]interface{}{"name": resource}
+	for key, value := range resourceAttributes {
+		resourceNamespace[key] = value
+	}
+	attributes["resource"] = resourceNamespace
+	attributes["action"] = action
+	if requestContext != nil {
+		attributes["context"] = requestContext
+	} else {
+		attributes["context"] = map[string]interface{}{}
+	}
+	return policy.Evaluate(policies, policy.Request{Resource: resource, Action: action, Attributes: attributes})
+}
+
+func attributeList(values []string) []interface{} {
+	list := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		list = append(list, value)
+	}
+	return list
+}
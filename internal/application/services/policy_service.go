@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"backend/internal/application/policy"
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+type PolicyService interface {
+	GetPolicyByID(ctx context.Context, id uuid.UUID) (*entities.Policy, error)
+	ListPolicies(ctx context.Context, domainID uuid.UUID) ([]*entities.Policy, error)
+	CreatePolicy(ctx context.Context, domainID uuid.UUID, name, description string, document json.RawMessage) (*entities.Policy, error)
+	UpdatePolicy(ctx context.Context, id uuid.UUID, name, description string, document json.RawMessage) (*entities.Policy, error)
+	DeletePolicy(ctx context.Context, id uuid.UUID) error
+	Authorize(ctx context.Context, req *AuthorizeRequest) (*AuthorizeResult, error)
+}
+
+type AuthorizeRequest struct {
+	UserID             uuid.UUID
+	Resource           string
+	Action             string
+	ResourceAttributes map[string]interface{}
+	Context            map[string]interface{}
+}
+
+type AuthorizeResult struct {
+	UserID   uuid.UUID `json:"user_id"`
+	DomainID uuid.UUID `json:"domain_id"`
+	Resource string    `json:"resource"`
+	Action   string    `json:"action"`
+	*policy.Decision
+}
+
+type policyService struct {
+	repo       repositories.PolicyRepository
+	userRepo   repositories.UserRepository
+	domainRepo repositories.DomainRepository
+	evaluator  *policyEvaluator
+}
+
+func NewPolicyService(repo repositories.PolicyRepository, userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, domainRepo repositories.DomainRepository, permRepo repositories.PermissionRepository, groupRepo repositories.GroupRepository) PolicyService {
+	return &policyService{
+		repo:       repo,
+		userRepo:   userRepo,
+		domainRepo: domainRepo,
+		evaluator:  newPolicyEvaluator(repo, domainRepo, roleRepo, permRepo, groupRepo),
+	}
+}
+
+func (s *policyService) GetPolicyByID(ctx context.Context, id uuid.UUID) (*entities.Policy, error) {
+	p, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "policy not found")
+	}
+	return p, nil
+}
+
+func (s *policyService) ListPolicies(ctx context.Context, domainID uuid.UUID) ([]*entities.Policy, error) {
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	return s.repo.GetByDomainID(ctx, domainID)
+}
+
+func (s *policyService) CreatePolicy(ctx context.Context, domainID uuid.UUID, name, description string, document json.RawMessage) (*entities.Policy, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domainerrors.Validation("policy name is required")
+	}
+	if _, err := policy.Parse(document); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	if _, err := s.repo.GetByName(ctx, domainID, name); err == nil {
+		return nil, domainerrors.Conflict("policy name is already used in this domain").WithCode("policy_name_taken")
+	}
+
+	p := &entities.Policy{
+		DomainID:    domainID,
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		Document:    document,
+	}
+	if err := s.repo.Create(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *policyService) UpdatePolicy(ctx context.Context, id uuid.UUID, name, description string, document json.RawMessage) (*entities.Policy, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domainerrors.Validation("policy name is required")
+	}
+	if _, err := policy.Parse(document); err != nil {
+		return nil, err
+	}
+
+	p, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domainerrors.NotFound("policy not found")
+	}
+
+	if existing, err := s.repo.GetByName(ctx, p.DomainID, name); err == nil && existing.ID != p.ID {
+		return nil, domainerrors.Conflict("policy name is already used in this domain").WithCode("policy_name_taken")
+	}
+
+	p.Name = name
+	p.Description = strings.TrimSpace(description)
+	p.Document = document
+	if err := s.repo.Update(ctx, p); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *policyService) DeletePolicy(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Authorize evaluates the policies of the user's domain against the user's attributes and the
+// attributes supplied by the caller for the resource and request context.
+func (s *policyService) Authorize(ctx context.Context, req *AuthorizeRequest) (*AuthorizeResult, error) {
+	ctx, span := tracer.Start(ctx, "PolicyService.Authorize")
+	defer span.End()
+
+	resource := strings.TrimSpace(req.Resource)
+	action := strings.TrimSpace(req.Action)
+	if resource == "" || action == "" {
+		return nil, domainerrors.Validation("resource and action are required")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+
+	attributes, err := s.evaluator.userAttributes(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := s.evaluator.policies(ctx, user.DomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthorizeResult{
+		UserID:   user.ID,
+		DomainID: user.DomainID,
+		Resource: resource,
+		Action:   action,
+		Decision: evaluatePolicies(policies, attributes, resource, action, req.ResourceAttributes, req.Context),
+	}, nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/google/uuid"
+)
+
+const registrationCodePrefixLen = 8
+
+type RegistrationService interface {
+	Register(ctx context.Context, domainID uuid.UUID, code, firstName, lastName, username, email, password string) (*entities.User, error)
+	CreateCode(ctx context.Context, domainID uuid.UUID, roleID *uuid.UUID, maxUses *int, expiresAt *time.Time) (*CreatedRegistrationCode, error)
+	ListCodes(ctx context.Context, domainID uuid.UUID) ([]*entities.RegistrationCode, error)
+	RevokeCode(ctx context.Context, domainID, id uuid.UUID) error
+}
+
+// CreatedRegistrationCode carries the plaintext code, which is only ever returned at creation.
+type CreatedRegistrationCode struct {
+	*entities.RegistrationCode
+	Code string `json:"code" example:"K7QF2M9XD4TA8BNC"`
+}
+
+type registrationService struct {
+	codeRepo   repositories.RegistrationCodeRepository
+	domainRepo repositories.DomainRepository
+	roleRepo   repositories.RoleRepository
+	users      UserService
+}
+
+func NewRegistrationService(codeRepo repositories.RegistrationCodeRepository, domainRepo repositories.DomainRepository, roleRepo repositories.RoleRepository, users UserService) RegistrationService {
+	return &registrationService{codeRepo: codeRepo, domainRepo: domainRepo, roleRepo: roleRepo, users: users}
+}
+
+// Register signs a user up to the domain. With a code the user gets the code's role, or the
+// domain's default role; without one the domain must be open to registration.
+func (s *registrationService) Register(ctx context.Context, domainID uuid.UUID, code, firstName, lastName, username, email, password string) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "RegistrationService.Register")
+	ctx = tenancy.WithDomain(ctx, domainID)
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	roleID := domain.Registration.DefaultRoleID
+	var consumed *entities.RegistrationCode
+	if code = strings.TrimSpace(code); code != "" {
+		consumed, err = s.consumeCode(ctx, domainID, code)
+		if err != nil {
+			return nil, err
+		}
+		if consumed.RoleID != nil {
+			roleID = consumed.RoleID
+		}
+	} else if !domain.Registration.Open {
+		return nil, domainerrors.Forbidden("registration to this domain requires a registration code").WithCode("registration_closed")
+	}
+
+	user, err := s.createUser(ctx, domainID, roleID, firstName, lastName, username, email, password)
+	if err != nil && consumed != nil {
+		// The sign-up did not happen, so it should not count against the code
+		s.codeRepo.Release(ctx, domainID, consumed.ID)
+	}
+	return user, err
+}
+
+func (s *registrationService) consumeCode(ctx context.Context, domainID uuid.UUID, code string) (*entities.RegistrationCode, error) {
+	invalid := domainerrors.Forbidden("registration code is invalid or expired").WithCode("invalid_registration_code")
+
+	registrationCode, err := s.codeRepo.GetByHash(ctx, domainID, hashRegistrationCode(code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if err := s.codeRepo.Consume(ctx, domainID, registrationCode.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	return registrationCode, nil
+}
+
+func (s *registrationService) createUser(ctx context.Context, domainID uuid.UUID, roleID *uuid.UUID, firstName, lastName, username, email, password string) (*entities.User, error) {
+	if roleID == nil {
+		return nil, domainerrors.Forbidden("registration to this domain has no default role configured").WithCode("registration_closed")
+	}
+	// The role may have been deleted since registration was configured
+	if role, err := s.roleRepo.GetByID(ctx, *roleID); err != nil || role.DomainID != domainID {
+		return nil, domainerrors.Forbidden("registration to this domain has no default role configured").WithCode("registration_closed")
+	}
+	return s.users.CreateUser(ctx, domainID, *roleID, firstName, lastName, username, email, password, nil, nil)
+}
+
+// CreateCode issues a registration code. A nil roleID gives users the domain's default role, a nil
+// maxUses allows unlimited sign-ups and a nil expiresAt never expires.
+func (s *registrationService) CreateCode(ctx context.Context, domainID uuid.UUID, roleID *uuid.UUID, maxUses *int, expiresAt *time.Time) (*CreatedRegistrationCode, error) {
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	if roleID != nil {
+		if role, err := s.roleRepo.GetByID(ctx, *roleID); err != nil || role.DomainID != domainID {
+			return nil, domainerrors.Validation("role does not exist in this domain")
+		}
+	}
+	if maxUses != nil && *maxUses <= 0 {
+		return nil, domainerrors.Validation("max_uses must be positive")
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, domainerrors.Validation("expires_at must be in the future")
+	}
+
+	secret := make([]byte, 10)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate code: %w", err)
+	}
+	rawCode := strings.ToUpper(hex.EncodeToString(secret))
+
+	code := &entities.RegistrationCode{
+		DomainID:  domainID,
+		Prefix:    rawCode[:registrationCodePrefixLen],
+		CodeHash:  hashRegistrationCode(rawCode),
+		RoleID:    roleID,
+		MaxUses:   maxUses,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.codeRepo.Create(ctx, code); err != nil {
+		return nil, err
+	}
+	return &CreatedRegistrationCode{RegistrationCode: code, Code: rawCode}, nil
+}
+
+func (s *registrationService) ListCodes(ctx context.Context, domainID uuid.UUID) ([]*entities.RegistrationCode, error) {
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	return s.codeRepo.ListByDomain(ctx, domainID)
+}
+
+func (s *registrationService) RevokeCode(ctx context.Context, domainID, id uuid.UUID) error {
+	return notFoundOr(s.codeRepo.Revoke(ctx, domainID, id), "registration code not found")
+}
+
+// hashRegistrationCode hashes codes case-insensitively since people may type them by hand.
+func hashRegistrationCode(code string) string {
+	return hashAPIKey(strings.ToUpper(strings.TrimSpace(code)))
+}
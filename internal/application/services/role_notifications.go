@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"backend/internal/domain/entities"
+)
+
+// RoleChangeNotification selects who is told about access changes caused by a role update.
+type RoleChangeNotification struct {
+	Users       bool     // email every affected user the changes to their own access
+	AdminEmails []string // email these addresses a summary covering all affected users
+}
+
+func (n *RoleChangeNotification) enabled() bool {
+	return n != nil && (n.Users || len(n.AdminEmails) > 0)
+}
+
+// sendRoleChangeNotifications queues emails of the diffs, which are sent and retried in the
+// background. It runs after the request has been answered, so failing to queue one is only logged.
+func (s *roleService) sendRoleChangeNotifications(ctx context.Context, role *entities.Role, diffs []*PermissionDiff, notify *RoleChangeNotification) {
+	subject := fmt.Sprintf("Access changed: role %s", role.RoleName)
+
+	if notify.Users {
+		for _, diff := range diffs {
+			body := fmt.Sprintf("Hello %s,\n\nThe role %q was updated and your access changed.\n\n%s",
+				diff.Username, role.RoleName, formatPermissionDiff(diff))
+			if err := s.mailer.Send(ctx, role.DomainID, diff.Email, subject, body); err != nil {
+				log.Printf("Failed to queue role change notification to user %s: %v", diff.UserID, err)
+			}
+		}
+	}
+
+	if len(notify.AdminEmails) > 0 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "The role %q was updated. %d user(s) are affected.\n", role.RoleName, len(diffs))
+		for _, diff := range diffs {
+			fmt.Fprintf(&b, "\n%s <%s>\n%s", diff.Username, diff.Email, formatPermissionDiff(diff))
+		}
+		for _, to := range notify.AdminEmails {
+			if err := s.mailer.Send(ctx, role.DomainID, to, subject, b.String()); err != nil {
+				log.Printf("Failed to queue role change summary to %s: %v", to, err)
+			}
+		}
+	}
+}
+
+func formatPermissionDiff(diff *PermissionDiff) string {
+	var b strings.Builder
+	for _, name := range diff.Gained {
+		fmt.Fprintf(&b, "  + %s\n", name)
+	}
+	for _, name := range diff.Lost {
+		fmt.Fprintf(&b, "  - %s\n", name)
+	}
+	return b.String()
+}
@@ -1,76 +1,287 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
 	"backend/internal/infrastructure/repositories"
 
 	"github.com/google/uuid"
 )
 
 type RoleService interface {
-	GetRoleByID(id uuid.UUID) (*entities.Role, error)
-	GetRolesByDomainID(domainID uuid.UUID) ([]*entities.Role, error)
-	CreateRole(domainID uuid.UUID, roleName string, roleClaims map[string]interface{}) (*entities.Role, error)
-	UpdateRole(id uuid.UUID, roleName string, roleClaims map[string]interface{}) (*entities.Role, error)
-	DeleteRole(id uuid.UUID) error
-	ListRolesWithPagination(search string, domainID uuid.UUID, page, limit int) (*repositories.RoleListResult, error)
+	GetRoleByID(ctx context.Context, id uuid.UUID) (*entities.Role, error)
+	GetRolesByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.Role, error)
+	GetRolesByIDs(ctx context.Context, ids []uuid.UUID) (*RoleBatch, error)
+	CreateRole(ctx context.Context, domainID uuid.UUID, roleName string, roleClaims map[string]interface{}) (*entities.Role, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, roleName string, roleClaims map[string]interface{}, notify *RoleChangeNotification) (*entities.Role, error)
+	DeleteRole(ctx context.Context, id uuid.UUID, replacementID *uuid.UUID, dryRun bool) (*RoleDeletionPlan, error)
+	ListRolesWithPagination(ctx context.Context, filter repositories.RoleListFilter, domainID uuid.UUID, page, limit int) (*repositories.RoleListResult, error)
+	ListRolesAfter(ctx context.Context, filter repositories.RoleListFilter, domainID uuid.UUID, cursor string, limit int) (*repositories.RoleCursorPage, error)
+	ExportRoles(ctx context.Context, domainID uuid.UUID, fn func(*entities.Role) error) error
+	ListRoleMembers(ctx context.Context, id uuid.UUID, page, limit int) (*repositories.UserListResult, error)
+	ListRoleRevisions(ctx context.Context, id uuid.UUID, page, limit int) (*repositories.RoleRevisionListResult, error)
+	RollbackRole(ctx context.Context, id uuid.UUID, revision int) (*entities.Role, error)
 }
 
 type roleService struct {
-	repo repositories.RoleRepository
+	repo       repositories.RoleRepository
+	domainRepo repositories.DomainRepository
+	userRepo   repositories.UserRepository
+	permRepo   repositories.PermissionRepository
+	events     EventService
+	mailer     DomainMailer
+	resolver   *permissionResolver
+	revisions  repositories.RoleRevisionRepository
+	tx         repositories.TxManager
+}
+
+func NewRoleService(repo repositories.RoleRepository, domainRepo repositories.DomainRepository, userRepo repositories.UserRepository, permRepo repositories.PermissionRepository, groupRepo repositories.GroupRepository, revisions repositories.RoleRevisionRepository, events EventService, mailer DomainMailer, tx repositories.TxManager) RoleService {
+	return &roleService{
+		repo:       repo,
+		domainRepo: domainRepo,
+		userRepo:   userRepo,
+		permRepo:   permRepo,
+		events:     events,
+		mailer:     mailer,
+		resolver:   &permissionResolver{roleRepo: repo, permRepo: permRepo, groupRepo: groupRepo},
+		revisions:  revisions,
+		tx:         tx,
+	}
 }
 
-func NewRoleService(repo repositories.RoleRepository) RoleService {
-	return &roleService{repo: repo}
+func (s *roleService) GetRoleByID(ctx context.Context, id uuid.UUID) (*entities.Role, error) {
+	role, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "role not found")
+	}
+	return role, nil
 }
 
-func (s *roleService) GetRoleByID(id uuid.UUID) (*entities.Role, error) {
-	return s.repo.GetByID(id)
+// ListRoleMembers returns a page of the users holding the role, directly or through a group.
+func (s *roleService) ListRoleMembers(ctx context.Context, id uuid.UUID, page, limit int) (*repositories.UserListResult, error) {
+	ctx, span := tracer.Start(ctx, "RoleService.ListRoleMembers")
+	defer span.End()
+
+	role, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "role not found")
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	return s.userRepo.ListRoleMembers(ctx, role.DomainID, role.ID, page, limit)
 }
 
-func (s *roleService) GetRolesByDomainID(domainID uuid.UUID) ([]*entities.Role, error) {
-	return s.repo.GetByDomainID(domainID)
+func (s *roleService) GetRolesByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.Role, error) {
+	return s.repo.GetByDomainID(ctx, domainID)
 }
 
-func (s *roleService) CreateRole(domainID uuid.UUID, roleName string, roleClaims map[string]interface{}) (*entities.Role, error) {
+func (s *roleService) CreateRole(ctx context.Context, domainID uuid.UUID, roleName string, roleClaims map[string]interface{}) (*entities.Role, error) {
 	if roleClaims == nil {
 		roleClaims = make(map[string]interface{})
 	}
+	if err := validateRoleClaims(roleClaims); err != nil {
+		return nil, err
+	}
 
 	role := &entities.Role{
 		DomainID:   domainID,
 		RoleName:   roleName,
 		RoleClaims: roleClaims,
 	}
-	err := s.repo.Create(role)
+	err := s.repo.Create(ctx, role)
 	if err != nil {
 		return nil, err
 	}
+	s.events.Publish(ctx, domainID, EventRoleCreated, role.ID, role)
 	return role, nil
 }
 
-func (s *roleService) UpdateRole(id uuid.UUID, roleName string, roleClaims map[string]interface{}) (*entities.Role, error) {
+// UpdateRole replaces the role's name and claims and records the change as a role revision. When
+// notify is set, affected users and/or admins are emailed what access was gained or lost.
+func (s *roleService) UpdateRole(ctx context.Context, id uuid.UUID, roleName string, roleClaims map[string]interface{}, notify *RoleChangeNotification) (*entities.Role, error) {
 	if roleClaims == nil {
 		roleClaims = make(map[string]interface{})
 	}
+	if err := validateRoleClaims(roleClaims); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domainerrors.NotFound("role not found")
+	}
+
+	// Diffs are computed against the stored claims, so they must be taken before the update
+	var diffs []*PermissionDiff
+	if notify.enabled() {
+		diffs, err = s.roleChangeDiffs(ctx, current, roleClaims)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	role := &entities.Role{
 		ID:         id,
+		DomainID:   current.DomainID,
 		RoleName:   roleName,
 		RoleClaims: roleClaims,
+		CreatedAt:  current.CreatedAt,
 	}
-	err := s.repo.Update(role)
+	role, err = s.updateWithRevision(ctx, current, role, nil)
 	if err != nil {
 		return nil, err
 	}
+
+	if len(diffs) > 0 {
+		go s.sendRoleChangeNotifications(context.WithoutCancel(ctx), role, diffs, notify)
+	}
 	return role, nil
 }
 
-func (s *roleService) DeleteRole(id uuid.UUID) error {
-	return s.repo.Delete(id)
+func (s *roleService) roleChangeDiffs(ctx context.Context, role *entities.Role, roleClaims map[string]interface{}) ([]*PermissionDiff, error) {
+	users, err := s.userRepo.ListByRole(ctx, role.DomainID, role.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role holders: %w", err)
+	}
+	assigned, err := s.permRepo.GetByRoleID(ctx, role.DomainID, role.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+	return s.resolver.roleChangeDiffs(ctx, users, &roleOverride{RoleID: role.ID, Claims: roleClaims, Permissions: assigned})
+}
+
+// RoleDeletionPlan is the effect of deleting a role. With a replacement, the role's users, group
+// grants, invitations and registration codes move to it; without one, the role must have no users,
+// invitations or registration codes, and groups lose the grant. Permission assignments are deleted
+// either way.
+type RoleDeletionPlan struct {
+	Role        *entities.Role              `json:"role"`
+	Replacement *entities.Role              `json:"replacement,omitempty"`
+	Affected    repositories.RoleDependents `json:"affected"`
+	// DefaultRegistrationRole is set when the role is the one self-registered users get; it is
+	// replaced in the registration settings
+	DefaultRegistrationRole bool `json:"default_registration_role"`
+	DryRun                  bool `json:"dry_run"`
+}
+
+// DeleteRole deletes a role, moving what references it to the replacement when one is given. A
+// dry run checks the same conditions and returns the plan without deleting anything.
+func (s *roleService) DeleteRole(ctx context.Context, id uuid.UUID, replacementID *uuid.UUID, dryRun bool) (*RoleDeletionPlan, error) {
+	ctx, span := tracer.Start(ctx, "RoleService.DeleteRole")
+	defer span.End()
+
+	plan, domain, err := s.planRoleDeletion(ctx, id, replacementID)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		plan.DryRun = true
+		return plan, nil
+	}
+
+	role := plan.Role
+	if plan.Replacement == nil {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return nil, err
+		}
+		s.events.Publish(ctx, role.DomainID, EventRoleDeleted, role.ID, role)
+		return plan, nil
+	}
+
+	if plan.DefaultRegistrationRole {
+		domain.Registration.DefaultRoleID = &plan.Replacement.ID
+		if err := s.domainRepo.Update(ctx, domain); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.DeleteReassigning(ctx, role.DomainID, id, plan.Replacement.ID); err != nil {
+		return nil, err
+	}
+	s.events.Publish(ctx, role.DomainID, EventRoleDeleted, role.ID, role)
+	return plan, nil
 }
 
-func (s *roleService) ListRolesWithPagination(search string, domainID uuid.UUID, page, limit int) (*repositories.RoleListResult, error) {
+// planRoleDeletion validates a role deletion and counts what it affects. The role's domain is
+// returned for updating its registration settings.
+func (s *roleService) planRoleDeletion(ctx context.Context, id uuid.UUID, replacementID *uuid.UUID) (*RoleDeletionPlan, *entities.Domain, error) {
+	role, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, notFoundOr(err, "role not found")
+	}
+	domain, err := s.domainRepo.GetByID(ctx, role.DomainID)
+	if err != nil {
+		return nil, nil, notFoundOr(err, "domain not found")
+	}
+
+	plan := &RoleDeletionPlan{Role: role}
+	if replacementID != nil {
+		if *replacementID == id {
+			return nil, nil, domainerrors.Validation("a role can't be replaced by itself")
+		}
+		replacement, err := s.repo.GetByID(ctx, *replacementID)
+		if err != nil || replacement.DomainID != role.DomainID {
+			return nil, nil, domainerrors.Validation("replacement role not found in domain")
+		}
+		plan.Replacement = replacement
+	}
+
+	defaultRole := domain.Registration.DefaultRoleID
+	plan.DefaultRegistrationRole = defaultRole != nil && *defaultRole == id
+	if plan.DefaultRegistrationRole && plan.Replacement == nil {
+		return nil, nil, domainerrors.Conflict("role is the domain's default registration role; pass reassign_to or change the registration settings first").WithCode("role_in_use")
+	}
+
+	dependents, err := s.repo.CountDependents(ctx, role.DomainID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan.Affected = *dependents
+	if plan.Replacement == nil && (dependents.Users > 0 || dependents.Invitations > 0 || dependents.RegistrationCodes > 0) {
+		return nil, nil, &RoleInUseError{
+			Err:        domainerrors.Conflict("role is still held by users or referenced by invitations or registration codes; pass reassign_to to move them").WithCode("role_in_use"),
+			Dependents: *dependents,
+		}
+	}
+	return plan, domain, nil
+}
+
+// RoleInUseError reports a role that can't be deleted without a replacement, with the rows still
+// referencing it. It unwraps to a conflict.
+type RoleInUseError struct {
+	Err        *domainerrors.Error
+	Dependents repositories.RoleDependents
+}
+
+func (e *RoleInUseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RoleInUseError) Unwrap() error {
+	return e.Err
+}
+
+func (s *roleService) ListRolesWithPagination(ctx context.Context, filter repositories.RoleListFilter, domainID uuid.UUID, page, limit int) (*repositories.RoleListResult, error) {
+	ctx, span := tracer.Start(ctx, "RoleService.ListRolesWithPagination")
+	defer span.End()
+
+	domainID, err := listDomain(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Claim = strings.TrimSpace(filter.Claim)
+	if err := validateCreatedRange(filter.CreatedAfter, filter.CreatedBefore); err != nil {
+		return nil, err
+	}
+
 	// Set default values
 	if page <= 0 {
 		page = 1
@@ -79,5 +290,79 @@ func (s *roleService) ListRolesWithPagination(search string, domainID uuid.UUID,
 		limit = 10
 	}
 
-	return s.repo.ListWithPagination(search, domainID, page, limit)
+	return s.repo.ListWithPagination(ctx, filter, domainID, page, limit)
+}
+
+// ListRolesAfter lists roles oldest first, resuming after the cursor of the previous page; an
+// empty cursor starts at the first role. The cursor only works in that order, so another sort is
+// rejected.
+func (s *roleService) ListRolesAfter(ctx context.Context, filter repositories.RoleListFilter, domainID uuid.UUID, cursor string, limit int) (*repositories.RoleCursorPage, error) {
+	ctx, span := tracer.Start(ctx, "RoleService.ListRolesAfter")
+	defer span.End()
+
+	domainID, err := listDomain(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Claim = strings.TrimSpace(filter.Claim)
+	if err := validateCreatedRange(filter.CreatedAfter, filter.CreatedBefore); err != nil {
+		return nil, err
+	}
+	if !filter.Sort.IsDefault() {
+		return nil, errCursorSort()
+	}
+
+	after, err := repositories.ParseListCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	return s.repo.ListAfter(ctx, filter, domainID, after, limit)
+}
+
+// ExportRoles streams every role of the domain to fn.
+func (s *roleService) ExportRoles(ctx context.Context, domainID uuid.UUID, fn func(*entities.Role) error) error {
+	ctx, span := tracer.Start(ctx, "RoleService.ExportRoles")
+	defer span.End()
+
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return domainerrors.NotFound("domain not found")
+	}
+	return s.repo.StreamByDomainID(ctx, domainID, fn)
+}
+
+// maxRoleClaimsDepth is how deeply role claims may nest; {"a": {"b": ["c"]}} is 3 levels deep.
+const maxRoleClaimsDepth = 8
+
+// validateRoleClaims rejects claims nested deeper than maxRoleClaimsDepth. Claims are walked by
+// every policy evaluation and copied into the tokens of the role's users, so deeper documents only
+// serve to exhaust memory.
+func validateRoleClaims(claims map[string]interface{}) error {
+	if jsonDepth(claims) > maxRoleClaimsDepth {
+		return domainerrors.Validation("role_claims may be nested at most %d levels deep", maxRoleClaimsDepth).WithCode("role_claims_too_deep")
+	}
+	return nil
+}
+
+// jsonDepth returns the nesting depth of a decoded JSON value: 0 for a scalar, 1 for an object or
+// array of scalars.
+func jsonDepth(value interface{}) int {
+	depth := 0
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, item := range value {
+			depth = max(depth, jsonDepth(item))
+		}
+	case []interface{}:
+		for _, item := range value {
+			depth = max(depth, jsonDepth(item))
+		}
+	default:
+		return 0
+	}
+	return depth + 1
 }
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// RoleWithPermissions is a role created from a template or copied from another role, with the
+// catalog permissions assigned to it.
+type RoleWithPermissions struct {
+	Role        *entities.Role         `json:"role"`
+	Permissions []*entities.Permission `json:"permissions"`
+}
+
+type RoleTemplateService interface {
+	ListRoleTemplates(ctx context.Context) ([]*entities.RoleTemplate, error)
+	GetRoleTemplate(ctx context.Context, id uuid.UUID) (*entities.RoleTemplate, error)
+	CreateRoleTemplate(ctx context.Context, name, description string, roleClaims map[string]interface{}, permissions []string) (*entities.RoleTemplate, error)
+	UpdateRoleTemplate(ctx context.Context, id uuid.UUID, name, description string, roleClaims map[string]interface{}, permissions []string) (*entities.RoleTemplate, error)
+	DeleteRoleTemplate(ctx context.Context, id uuid.UUID) error
+	CreateRoleFromTemplate(ctx context.Context, domainID, templateID uuid.UUID) (*RoleWithPermissions, error)
+	CloneRole(ctx context.Context, roleID, targetDomainID uuid.UUID, roleName string) (*RoleWithPermissions, error)
+}
+
+type roleTemplateService struct {
+	repo       repositories.RoleTemplateRepository
+	roleRepo   repositories.RoleRepository
+	permRepo   repositories.PermissionRepository
+	domainRepo repositories.DomainRepository
+	events     EventService
+	tx         repositories.TxManager
+}
+
+func NewRoleTemplateService(repo repositories.RoleTemplateRepository, roleRepo repositories.RoleRepository, permRepo repositories.PermissionRepository, domainRepo repositories.DomainRepository, events EventService, tx repositories.TxManager) RoleTemplateService {
+	return &roleTemplateService{
+		repo:       repo,
+		roleRepo:   roleRepo,
+		permRepo:   permRepo,
+		domainRepo: domainRepo,
+		events:     events,
+		tx:         tx,
+	}
+}
+
+func (s *roleTemplateService) ListRoleTemplates(ctx context.Context) ([]*entities.RoleTemplate, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *roleTemplateService) GetRoleTemplate(ctx context.Context, id uuid.UUID) (*entities.RoleTemplate, error) {
+	template, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "role template not found")
+	}
+	return template, nil
+}
+
+func (s *roleTemplateService) CreateRoleTemplate(ctx context.Context, name, description string, roleClaims map[string]interface{}, permissions []string) (*entities.RoleTemplate, error) {
+	template, err := newRoleTemplate(name, description, roleClaims, permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, template); err != nil {
+		return nil, roleTemplateConflict(err)
+	}
+	return template, nil
+}
+
+// UpdateRoleTemplate replaces the template. Roles already created from it are left as they are.
+func (s *roleTemplateService) UpdateRoleTemplate(ctx context.Context, id uuid.UUID, name, description string, roleClaims map[string]interface{}, permissions []string) (*entities.RoleTemplate, error) {
+	template, err := newRoleTemplate(name, description, roleClaims, permissions)
+	if err != nil {
+		return nil, err
+	}
+	template.ID = id
+	if err := s.repo.Update(ctx, template); err != nil {
+		return nil, notFoundOr(roleTemplateConflict(err), "role template not found")
+	}
+	return template, nil
+}
+
+func (s *roleTemplateService) DeleteRoleTemplate(ctx context.Context, id uuid.UUID) error {
+	return notFoundOr(s.repo.Delete(ctx, id), "role template not found")
+}
+
+// newRoleTemplate validates a template and normalizes its permissions to sorted, lowercase
+// resource:action names without duplicates.
+func newRoleTemplate(name, description string, roleClaims map[string]interface{}, permissions []string) (*entities.RoleTemplate, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domainerrors.Validation("name is required")
+	}
+	if roleClaims == nil {
+		roleClaims = make(map[string]interface{})
+	}
+	if err := validateRoleClaims(roleClaims); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		resource, action, err := parsePermissionName(permission)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, resource+":"+action)
+	}
+	slices.Sort(names)
+
+	return &entities.RoleTemplate{
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		RoleClaims:  roleClaims,
+		Permissions: slices.Compact(names),
+	}, nil
+}
+
+// parsePermissionName splits a resource:action permission name into its lowercase parts.
+func parsePermissionName(name string) (string, string, error) {
+	resource, action, ok := strings.Cut(strings.ToLower(strings.TrimSpace(name)), ":")
+	if !ok || resource == "" || action == "" || strings.Contains(action, ":") {
+		return "", "", domainerrors.Validation("permission %q must be a resource:action name", name).WithCode("invalid_permission")
+	}
+	return resource, action, nil
+}
+
+func roleTemplateConflict(err error) error {
+	if repositories.UniqueViolation(err) == "idx_role_templates_name" {
+		return domainerrors.Conflict("a role template with this name already exists").WithCode("role_template_exists")
+	}
+	return err
+}
+
+// CreateRoleFromTemplate creates a role named after the template in the domain, with the template's
+// claims and permissions. Permissions the domain's catalog lacks are added to it.
+func (s *roleTemplateService) CreateRoleFromTemplate(ctx context.Context, domainID, templateID uuid.UUID) (*RoleWithPermissions, error) {
+	ctx, span := tracer.Start(ctx, "RoleTemplateService.CreateRoleFromTemplate")
+	defer span.End()
+
+	template, err := s.repo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, notFoundOr(err, "role template not found")
+	}
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+
+	permissions := make([]*entities.Permission, 0, len(template.Permissions))
+	for _, name := range template.Permissions {
+		resource, action, err := parsePermissionName(name)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, &entities.Permission{Name: resource + ":" + action, Resource: resource, Action: action})
+	}
+	return s.createRole(ctx, domainID, template.Name, template.RoleClaims, permissions)
+}
+
+// CloneRole copies a role into the target domain, or into its own domain when targetDomainID is
+// Nil, under roleName or else its current name. The copy gets the same claims and catalog
+// permissions, matched by name in the target domain and added to its catalog when missing.
+func (s *roleTemplateService) CloneRole(ctx context.Context, roleID, targetDomainID uuid.UUID, roleName string) (*RoleWithPermissions, error) {
+	ctx, span := tracer.Start(ctx, "RoleTemplateService.CloneRole")
+	defer span.End()
+
+	source, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, notFoundOr(err, "role not found")
+	}
+	if targetDomainID == uuid.Nil {
+		targetDomainID = source.DomainID
+	}
+	if !adminCanManage(ctx, targetDomainID) {
+		return nil, domainerrors.Forbidden("domain admins can only manage their own domain").WithCode("domain_forbidden")
+	}
+	if _, err := s.domainRepo.GetByID(ctx, targetDomainID); err != nil {
+		return nil, notFoundOr(err, "target domain not found")
+	}
+	if roleName = strings.TrimSpace(roleName); roleName == "" {
+		roleName = source.RoleName
+	}
+
+	assigned, err := s.permRepo.GetByRoleID(ctx, source.DomainID, source.ID)
+	if err != nil {
+		return nil, err
+	}
+	permissions := make([]*entities.Permission, 0, len(assigned))
+	for _, permission := range assigned {
+		permissions = append(permissions, &entities.Permission{
+			Name:        permission.Name,
+			Resource:    permission.Resource,
+			Action:      permission.Action,
+			Description: permission.Description,
+		})
+	}
+	return s.createRole(ctx, targetDomainID, roleName, source.RoleClaims, permissions)
+}
+
+// createRole creates a role in one transaction with its permission assignments, taking each
+// permission from the domain's catalog by name or adding it there.
+func (s *roleTemplateService) createRole(ctx context.Context, domainID uuid.UUID, roleName string, roleClaims map[string]interface{}, permissions []*entities.Permission) (*RoleWithPermissions, error) {
+	result := &RoleWithPermissions{Permissions: []*entities.Permission{}}
+	err := s.tx.WithinTenantTx(ctx, domainID, func(ctx context.Context) error {
+		role := &entities.Role{DomainID: domainID, RoleName: roleName, RoleClaims: maps.Clone(roleClaims)}
+		if role.RoleClaims == nil {
+			role.RoleClaims = make(map[string]interface{})
+		}
+		if err := s.roleRepo.Create(ctx, role); err != nil {
+			if repositories.UniqueViolation(err) == "roles_domain_id_role_name_key" {
+				return domainerrors.Conflict("role %q already exists in the domain", roleName).WithCode("role_name_taken")
+			}
+			return err
+		}
+		result.Role = role
+
+		for _, wanted := range permissions {
+			permission, err := s.permRepo.GetByName(ctx, domainID, wanted.Name)
+			if errors.Is(err, sql.ErrNoRows) {
+				permission = &entities.Permission{
+					DomainID:    domainID,
+					Name:        wanted.Name,
+					Resource:    wanted.Resource,
+					Action:      wanted.Action,
+					Description: wanted.Description,
+				}
+				err = s.permRepo.Create(ctx, permission)
+			}
+			if err != nil {
+				return err
+			}
+			if err := s.permRepo.AssignToRole(ctx, domainID, role.ID, permission.ID); err != nil {
+				return err
+			}
+			result.Permissions = append(result.Permissions, permission)
+		}
+		return s.events.Record(ctx, domainID, EventRoleCreated, role.ID, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/metrics"
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/google/uuid"
+)
+
+const clientSecretPrefix = "nrs_"
+
+// CreatedServiceAccount carries the client credentials of a new service account. The secret is
+// only ever returned at creation.
+type CreatedServiceAccount struct {
+	*entities.User
+	ClientID     uuid.UUID `json:"client_id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	ClientSecret string    `json:"client_secret" example:"nrs_6f1c2b9e..."`
+}
+
+// ClientCredentialsToken is the token response of the client credentials grant (RFC 6749 4.4.3).
+type ClientCredentialsToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type" example:"Bearer"`
+	ExpiresIn   int    `json:"expires_in" example:"86400"`                       // seconds
+	Scope       string `json:"scope,omitempty" example:"users:read groups:read"` // the scopes granted, when some were requested
+}
+
+func errServiceAccount() error {
+	return domainerrors.Validation("service accounts have no password; they sign in with client credentials").WithCode("service_account")
+}
+
+// CreateServiceAccount creates a service account with the role and returns its client
+// credentials: the user ID as client ID and a generated secret. Service accounts have no password
+// or email.
+func (s *userService) CreateServiceAccount(ctx context.Context, domainID, roleID uuid.UUID, username, name string) (*CreatedServiceAccount, error) {
+	ctx, span := tracer.Start(ctx, "UserService.CreateServiceAccount")
+	defer span.End()
+
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, domainerrors.Validation("username is required")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = username
+	}
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil || role.DomainID != domainID {
+		return nil, domainerrors.Validation("role does not belong to this domain")
+	}
+	if err := s.ensureUnique(ctx, domainID, username, "", uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate client secret: %w", err)
+	}
+	clientSecret := clientSecretPrefix + hex.EncodeToString(secret)
+	secretHash := hashSecret(clientSecret)
+
+	user := &entities.User{
+		DomainID:         domainID,
+		RoleID:           roleID,
+		FirstName:        name,
+		Username:         username,
+		Type:             entities.UserTypeService,
+		ClientSecretHash: &secretHash,
+	}
+	err = s.tx.WithinTenantTx(ctx, domainID, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, user); err != nil {
+			return conflictFromDB(err)
+		}
+		return s.events.Record(ctx, domainID, EventUserCreated, user.ID, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CreatedServiceAccount{User: user, ClientID: user.ID, ClientSecret: clientSecret}, nil
+}
+
+// ClientCredentials issues an access token, narrowed to the requested scopes if any, to a service
+// account that presents its client ID and secret. Every credential failure is reported as
+// invalid_client so callers can't probe for accounts.
+func (s *authService) ClientCredentials(ctx context.Context, clientID, clientSecret, clientIP string, scopes []string) (token *ClientCredentialsToken, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ClientCredentials")
+	defer span.End()
+	defer func() { metrics.RecordLogin(err == nil) }()
+
+	invalid := domainerrors.Unauthorized("invalid client credentials").WithCode("invalid_client")
+	id, err := uuid.Parse(strings.TrimSpace(clientID))
+	if err != nil {
+		return nil, invalid
+	}
+	// The client ID is all that names the account, so it is found among every tenant's users
+	user, err := s.userRepo.GetByID(tenancy.AsSystem(ctx), id)
+	if err != nil || !user.IsService() || user.ClientSecretHash == nil {
+		return nil, invalid
+	}
+	ctx = tenancy.WithDomain(ctx, user.DomainID)
+	if subtle.ConstantTimeCompare([]byte(hashSecret(clientSecret)), []byte(*user.ClientSecretHash)) != 1 {
+		s.publishLogin(ctx, user.DomainID, user, user.Username, loginMethodClientCredentials, clientIP, loginFailureInvalidSecret)
+		return nil, invalid
+	}
+
+	token, err = s.issueClientToken(ctx, user, scopes)
+	s.publishLoginResult(ctx, user, user.Username, loginMethodClientCredentials, clientIP, err)
+	return token, err
+}
+
+func (s *authService) issueClientToken(ctx context.Context, user *entities.User, scopes []string) (*ClientCredentialsToken, error) {
+	if accountDisabled(user, time.Now()) {
+		return nil, domainerrors.Forbidden("account is disabled")
+	}
+	domain, err := s.domainRepo.GetByID(ctx, user.DomainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain: %w", err)
+	}
+	if err := domainAccessError(user, domain); err != nil {
+		return nil, err
+	}
+	profile, err := s.buildUserProfile(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build user profile: %w", err)
+	}
+	scope, err := s.grantScopes(ctx, user, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, expiresAt, err := s.generateToken(ctx, user, domain, profile, nil, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	return &ClientCredentialsToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(time.Until(expiresAt).Round(time.Second).Seconds()),
+		Scope:       scope,
+	}, nil
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/metrics"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/infrastructure/telemetry"
+
+	"github.com/google/uuid"
+)
+
+// loginTelemetryEvents are the sign-in funnel steps exported for opted-in domains.
+var loginTelemetryEvents = []string{EventLoginCodeSent, EventLoginChallenged, EventLoginFailed, EventLoginSucceeded}
+
+// maxTelemetryBatchesPerRun bounds how much of one domain's backlog a run exports, so a domain
+// that just opted in with a busy sign-in page doesn't hold up the others.
+const maxTelemetryBatchesPerRun = 20
+
+// LoginTelemetry is a domain's login telemetry opt-in with the progress of its export.
+type LoginTelemetry struct {
+	entities.TelemetrySettings
+	SinkConfigured bool       `json:"sink_configured" example:"true"` // false while the platform exports nowhere
+	LastSequence   int64      `json:"last_sequence,omitempty" example:"42"`
+	ExportedAt     *time.Time `json:"exported_at,omitempty"`
+	LastError      *string    `json:"last_error,omitempty" example:"s3 returned status 403"`
+}
+
+// TelemetryExportService exports the anonymized sign-in funnel of the domains that opted in.
+type TelemetryExportService interface {
+	GetLoginTelemetry(ctx context.Context, domainID uuid.UUID) (*LoginTelemetry, error)
+	UpdateLoginTelemetry(ctx context.Context, domainID uuid.UUID, settings *entities.TelemetrySettings) (*LoginTelemetry, error)
+	ExportDue(ctx context.Context) (int, error)
+	RunExports(ctx context.Context, interval time.Duration)
+}
+
+type telemetryExportService struct {
+	domainRepo repositories.DomainRepository
+	eventRepo  repositories.EventRepository
+	cursors    repositories.TelemetryExportCursorRepository
+	sink       telemetry.Sink
+	config     *config.TelemetryConfig
+}
+
+func NewTelemetryExportService(domainRepo repositories.DomainRepository, eventRepo repositories.EventRepository, cursors repositories.TelemetryExportCursorRepository, sink telemetry.Sink, cfg *config.TelemetryConfig) TelemetryExportService {
+	return &telemetryExportService{domainRepo: domainRepo, eventRepo: eventRepo, cursors: cursors, sink: sink, config: cfg}
+}
+
+func (s *telemetryExportService) GetLoginTelemetry(ctx context.Context, domainID uuid.UUID) (*LoginTelemetry, error) {
+	ctx, span := tracer.Start(ctx, "TelemetryExportService.GetLoginTelemetry")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	return s.status(ctx, domain)
+}
+
+// UpdateLoginTelemetry opts the domain in to or out of the export. Changing the opt-in restarts
+// the export at the domain's latest event, so nothing recorded while it was opted out is exported.
+func (s *telemetryExportService) UpdateLoginTelemetry(ctx context.Context, domainID uuid.UUID, settings *entities.TelemetrySettings) (*LoginTelemetry, error) {
+	ctx, span := tracer.Start(ctx, "TelemetryExportService.UpdateLoginTelemetry")
+	defer span.End()
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	if domain.Telemetry.LoginExport != settings.LoginExport {
+		domain.Telemetry = *settings
+		if err := s.domainRepo.Update(ctx, domain); err != nil {
+			return nil, err
+		}
+		if err := s.cursors.Delete(ctx, domainID); err != nil {
+			return nil, err
+		}
+	}
+	return s.status(ctx, domain)
+}
+
+func (s *telemetryExportService) status(ctx context.Context, domain *entities.Domain) (*LoginTelemetry, error) {
+	result := &LoginTelemetry{TelemetrySettings: domain.Telemetry, SinkConfigured: s.sink != nil}
+	cursor, err := s.cursors.Get(ctx, domain.DomainID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.LastSequence = cursor.LastSequence
+	result.ExportedAt = cursor.ExportedAt
+	result.LastError = cursor.LastError
+	return result, nil
+}
+
+// ExportDue exports the new sign-in events of every opted-in domain and returns how many records
+// were written. A domain another instance is exporting is skipped.
+func (s *telemetryExportService) ExportDue(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "TelemetryExportService.ExportDue")
+	defer span.End()
+
+	domainIDs, err := s.domainRepo.ListLoginTelemetryExporters(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	exported := 0
+	for _, domainID := range domainIDs {
+		if ctx.Err() != nil {
+			break
+		}
+		exported += s.exportDomain(ctx, domainID)
+	}
+	return exported, nil
+}
+
+// exportDomain writes one domain's new events in batches, advancing its cursor after each batch
+// the sink accepted, and returns how many records were written.
+func (s *telemetryExportService) exportDomain(ctx context.Context, domainID uuid.UUID) int {
+	lease := maxTelemetryBatchesPerRun*s.config.Timeout + time.Minute
+	since, ok, err := s.cursors.Claim(ctx, domainID, lease)
+	if err != nil {
+		log.Printf("Failed to claim login telemetry export of domain %s: %v", domainID, err)
+		return 0
+	}
+	if !ok {
+		return 0
+	}
+
+	exported := 0
+	var exportErr error
+	for range maxTelemetryBatchesPerRun {
+		events, err := s.eventRepo.ListTypesSince(ctx, domainID, loginTelemetryEvents, since, s.config.BatchSize)
+		if err != nil || len(events) == 0 {
+			exportErr = err
+			break
+		}
+
+		batch := telemetry.Batch{
+			DomainID:      domainID.String(),
+			FirstSequence: events[0].Sequence,
+			LastSequence:  events[len(events)-1].Sequence,
+			Records:       make([]telemetry.LoginRecord, 0, len(events)),
+		}
+		for _, event := range events {
+			batch.Records = append(batch.Records, s.anonymize(event))
+		}
+		if exportErr = s.sink.Write(ctx, batch); exportErr != nil {
+			metrics.RecordTelemetryRecords("failed", len(batch.Records))
+			break
+		}
+		metrics.RecordTelemetryRecords("exported", len(batch.Records))
+		exported += len(batch.Records)
+
+		since = batch.LastSequence
+		if exportErr = s.cursors.Advance(ctx, domainID, since); exportErr != nil || len(events) < s.config.BatchSize {
+			break
+		}
+	}
+
+	// Release the cursor even if shutdown cancelled ctx, so the next run needn't wait for the lease
+	reason := ""
+	if exportErr != nil {
+		log.Printf("Failed to export login telemetry of domain %s: %v", domainID, exportErr)
+		reason = exportErr.Error()
+		if len(reason) > maxOutboxErrLen {
+			reason = reason[:maxOutboxErrLen]
+		}
+	}
+	if err := s.cursors.Release(context.WithoutCancel(ctx), domainID, reason); err != nil {
+		log.Printf("Failed to release login telemetry export of domain %s: %v", domainID, err)
+	}
+	return exported
+}
+
+// anonymize keeps what the funnel analysis needs of a sign-in event. The account is replaced by
+// an HMAC of the domain and user ID, so the same account maps to the same actor across exports
+// without being traceable outside the platform.
+func (s *telemetryExportService) anonymize(event *entities.Event) telemetry.LoginRecord {
+	record := telemetry.LoginRecord{
+		EventID:    event.ID.String(),
+		DomainID:   event.DomainID.String(),
+		Sequence:   event.Sequence,
+		Event:      event.Type,
+		OccurredAt: event.CreatedAt.UTC(),
+	}
+	var attempt LoginAttempt
+	if err := json.Unmarshal(event.Payload, &attempt); err != nil {
+		return record
+	}
+	record.Method = attempt.Method
+	record.Reason = attempt.Reason
+	record.Challenge = attempt.Challenge
+	if attempt.UserID != nil {
+		mac := hmac.New(sha256.New, []byte(s.config.AnonymizationKey))
+		mac.Write([]byte(event.DomainID.String() + ":" + attempt.UserID.String()))
+		record.Actor = hex.EncodeToString(mac.Sum(nil))
+	}
+	return record
+}
+
+// RunExports calls ExportDue every interval until ctx is cancelled.
+func (s *telemetryExportService) RunExports(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ExportDue(ctx); err != nil {
+				log.Printf("Login telemetry export failed: %v", err)
+			}
+		}
+	}
+}
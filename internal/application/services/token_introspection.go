@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/cache"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/faults"
+	"backend/internal/infrastructure/metrics"
+)
+
+// TokenIntrospectionService validates tokens for /auth/validate, which API gateways call for every
+// request they forward, caching the result so repeated checks of a token skip the database.
+type TokenIntrospectionService interface {
+	Introspect(ctx context.Context, tokenString string) (*TokenClaims, error)
+	Forget(ctx context.Context, tokenString string)
+}
+
+// introspectionResult is the cached outcome of validating a token; Error is set for rejected tokens.
+type introspectionResult struct {
+	Claims *TokenClaims `json:"claims,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+type tokenIntrospectionService struct {
+	auth   AuthService
+	cache  cache.Cache
+	config *config.IntrospectionConfig
+}
+
+// NewTokenIntrospectionService caches results in c; with a nil cache every token is validated.
+func NewTokenIntrospectionService(auth AuthService, c cache.Cache, cfg *config.IntrospectionConfig) TokenIntrospectionService {
+	return &tokenIntrospectionService{auth: auth, cache: c, config: cfg}
+}
+
+// introspectionCacheKey hashes the token, so the cache never holds a usable credential.
+func introspectionCacheKey(tokenString string) string {
+	sum := sha256.Sum256([]byte(tokenString))
+	return "introspection:" + hex.EncodeToString(sum[:])
+}
+
+// Introspect returns the token's claims, or the reason it was rejected. Valid tokens are cached for
+// at most CacheTTL and never past their expiry. Rejections are cached for NegativeCacheTTL, so a
+// client replaying a bad token doesn't reach the database each time; failures that may clear up,
+// such as an unreachable revocation store, are not cached.
+func (s *tokenIntrospectionService) Introspect(ctx context.Context, tokenString string) (*TokenClaims, error) {
+	ctx, span := tracer.Start(ctx, "TokenIntrospectionService.Introspect")
+	defer span.End()
+
+	if s.cache == nil {
+		return s.auth.ValidateToken(ctx, tokenString)
+	}
+
+	key := introspectionCacheKey(tokenString)
+	if data, found, err := s.cache.Get(ctx, key); err != nil {
+		log.Printf("Failed to read token introspection from cache: %v", err)
+	} else if found {
+		var result introspectionResult
+		if err := json.Unmarshal(data, &result); err == nil {
+			metrics.RecordCacheLookup("introspection", true)
+			if result.Error != "" {
+				return nil, domainerrors.Unauthorized("%s", result.Error)
+			}
+			return result.Claims, nil
+		}
+	}
+	metrics.RecordCacheLookup("introspection", false)
+
+	claims, err := s.auth.ValidateToken(ctx, tokenString)
+	switch {
+	case err == nil:
+		ttl := s.config.CacheTTL
+		if claims.ExpiresAt != nil {
+			ttl = min(ttl, time.Until(claims.ExpiresAt.Time))
+		}
+		s.store(ctx, key, introspectionResult{Claims: claims}, ttl)
+	case errors.Is(err, faults.ErrInjected):
+	case errors.Is(err, domainerrors.ErrUnauthorized), errors.Is(err, domainerrors.ErrForbidden):
+		s.store(ctx, key, introspectionResult{Error: err.Error()}, s.config.NegativeCacheTTL)
+	}
+	return claims, err
+}
+
+func (s *tokenIntrospectionService) store(ctx context.Context, key string, result introspectionResult, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	if err := s.cache.Set(ctx, key, data, ttl); err != nil {
+		log.Printf("Failed to write token introspection to cache: %v", err)
+	}
+}
+
+// Forget drops the cached result of a token, e.g. once it has been revoked.
+func (s *tokenIntrospectionService) Forget(ctx context.Context, tokenString string) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Delete(ctx, introspectionCacheKey(tokenString)); err != nil {
+		log.Printf("Failed to invalidate token introspection in cache: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+)
+
+// revokeSessions revokes every token issued to the user so far. Token iat claims have second
+// precision, so the revocation time is truncated to the second and tokens issued up to and
+// including it are rejected; tokenIssueTime keeps new tokens out of that second.
+func revokeSessions(ctx context.Context, userRepo repositories.UserRepository, user *entities.User) error {
+	at := time.Now().Truncate(time.Second)
+	if err := userRepo.RevokeSessions(ctx, user.ID, at); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	user.SessionsRevokedAt = &at
+	return nil
+}
+
+// tokenIssueTime returns the iat of a token issued to the user at now. A token issued in the
+// second the user's sessions were revoked, such as the one returned when an expired password is
+// changed, would be rejected with the revoked ones, so it is dated the next second instead.
+func tokenIssueTime(user *entities.User, now time.Time) time.Time {
+	if revokedAt := user.SessionsRevokedAt; revokedAt != nil && !now.Truncate(time.Second).After(*revokedAt) {
+		return revokedAt.Truncate(time.Second).Add(time.Second)
+	}
+	return now
+}
+
+// sessionRevoked reports whether the token was issued before the user's sessions were revoked,
+// or in the same second.
+func sessionRevoked(user *entities.User, claims *TokenClaims) bool {
+	if user.SessionsRevokedAt == nil {
+		return false
+	}
+	return claims.IssuedAt == nil || !claims.IssuedAt.Time.After(*user.SessionsRevokedAt)
+}
+
+// checkRevoked rejects tokens on the jti denylist. Tokens issued without a jti can only be
+// revoked with the rest of the user's sessions.
+func (s *authService) checkRevoked(ctx context.Context, claims *TokenClaims) error {
+	if claims.ID == "" {
+		return nil
+	}
+	revoked, err := s.revokedTokens.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	if revoked {
+		return domainerrors.Unauthorized("token revoked")
+	}
+	return nil
+}
+
+// RevokeToken puts an access token on the denylist until it expires. Tokens that are already
+// invalid or expired are ignored, so revoking is safe to repeat.
+func (s *authService) RevokeToken(ctx context.Context, tokenString string) error {
+	ctx, span := tracer.Start(ctx, "AuthService.RevokeToken")
+	defer span.End()
+
+	claims, err := s.parseToken(tokenString)
+	if err != nil || claims.Purpose != "" || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+	return s.revokedTokens.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+}
+
+// RunRevocationSweep removes denylist entries of expired tokens every interval until ctx is
+// cancelled.
+func (s *authService) RunRevocationSweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.revokedTokens.DeleteExpired(ctx, time.Now())
+			if err != nil {
+				log.Printf("Revoked token sweep failed: %v", err)
+			} else if removed > 0 {
+				log.Printf("Revoked token sweep removed %d expired token(s)", removed)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestSessionRevoked(t *testing.T) {
+	revokedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		issuedAt *jwt.NumericDate
+		want     bool
+	}{
+		{"issued before", jwt.NewNumericDate(revokedAt.Add(-time.Second)), true},
+		{"issued in the same second", jwt.NewNumericDate(revokedAt.Add(500 * time.Millisecond)), true},
+		{"issued after", jwt.NewNumericDate(revokedAt.Add(time.Second)), false},
+		{"no iat", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &entities.User{SessionsRevokedAt: &revokedAt}
+			if got := sessionRevoked(user, &TokenClaims{RegisteredClaims: jwt.RegisteredClaims{IssuedAt: tt.issuedAt}}); got != tt.want {
+				t.Errorf("sessionRevoked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if sessionRevoked(&entities.User{}, &TokenClaims{}) {
+		t.Error("token of a user whose sessions were never revoked is revoked")
+	}
+}
+
+func TestTokenIssueTime(t *testing.T) {
+	revokedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"in the second of the revocation", revokedAt.Add(300 * time.Millisecond), revokedAt.Add(time.Second)},
+		{"after the revocation", revokedAt.Add(1500 * time.Millisecond), revokedAt.Add(1500 * time.Millisecond)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &entities.User{SessionsRevokedAt: &revokedAt}
+			got := tokenIssueTime(user, tt.now)
+			if !got.Equal(tt.want) {
+				t.Errorf("tokenIssueTime = %v, want %v", got, tt.want)
+			}
+			if sessionRevoked(user, &TokenClaims{RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(got)}}) {
+				t.Errorf("token issued at %v is revoked by the revocation at %v", got, revokedAt)
+			}
+		})
+	}
+
+	now := time.Now()
+	if got := tokenIssueTime(&entities.User{}, now); !got.Equal(now) {
+		t.Errorf("tokenIssueTime without a revocation = %v, want %v", got, now)
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxAccessTokenTTLMinutes = 7 * 24 * 60
+	maxRefreshTTLMinutes     = 90 * 24 * 60
+	maxTokenAudiences        = 10
+	maxExtraClaimsBytes      = 2048
+	maxClaimNamespaceLen     = 200
+)
+
+// reservedTokenClaims are set by the server or the claim template, so extra claims can't override
+// them.
+var reservedTokenClaims = map[string]bool{
+	"iss": true, "sub": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true,
+	"user_id": true, "domain_id": true, "username": true, "role_id": true, "groups": true,
+	"break_glass": true, "purpose": true, "act": true, "scope": true,
+	"email": true, "first_name": true, "last_name": true, "external_id": true,
+	"role_claims": true, "permissions": true, "group_names": true, claimsOverage: true,
+}
+
+// GetTokenSettings returns the domain's access token settings.
+func (s *domainService) GetTokenSettings(ctx context.Context, id uuid.UUID) (*entities.DomainTokenSettings, error) {
+	domain, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	return &domain.TokenSettings, nil
+}
+
+// UpdateTokenSettings replaces the domain's access token settings. Tokens already issued keep the
+// lifetime and claims they were issued with.
+func (s *domainService) UpdateTokenSettings(ctx context.Context, id uuid.UUID, settings *entities.DomainTokenSettings) (*entities.DomainTokenSettings, error) {
+	domain, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "domain not found")
+	}
+	if err := validateTokenSettings(settings); err != nil {
+		return nil, err
+	}
+	domain.TokenSettings = *settings
+	if namespace := claimNamespace(domain); namespace != "" {
+		overlaps, err := s.repo.ClaimNamespaceOverlaps(ctx, namespace, domain.DomainID)
+		if err != nil {
+			return nil, err
+		}
+		if overlaps {
+			return nil, domainerrors.Conflict("claim namespace %s overlaps the claim namespace of another domain", namespace).WithCode("claim_namespace_taken")
+		}
+	}
+	if err := s.repo.Update(ctx, domain); err != nil {
+		return nil, err
+	}
+	return &domain.TokenSettings, nil
+}
+
+// validateTokenSettings bounds the lifetimes and rejects audiences or extra claims that would make
+// tokens ambiguous or oversized.
+func validateTokenSettings(settings *entities.DomainTokenSettings) error {
+	if settings.AccessTokenTTLMinutes < 0 || settings.AccessTokenTTLMinutes > maxAccessTokenTTLMinutes {
+		return domainerrors.Validation("access_token_ttl_minutes must be between 0 and %d", maxAccessTokenTTLMinutes)
+	}
+	if settings.RefreshTTLMinutes < 0 || settings.RefreshTTLMinutes > maxRefreshTTLMinutes {
+		return domainerrors.Validation("refresh_ttl_minutes must be between 0 and %d", maxRefreshTTLMinutes)
+	}
+	if len(settings.Audience) > maxTokenAudiences {
+		return domainerrors.Validation("at most %d audiences are allowed", maxTokenAudiences)
+	}
+	for i, audience := range settings.Audience {
+		settings.Audience[i] = strings.TrimSpace(audience)
+		if settings.Audience[i] == "" {
+			return domainerrors.Validation("audience entries must not be empty")
+		}
+	}
+	for name := range settings.ExtraClaims {
+		if strings.TrimSpace(name) == "" || reservedTokenClaims[name] {
+			return domainerrors.Validation("extra claim %q is reserved", name)
+		}
+	}
+	if encoded, err := json.Marshal(settings.ExtraClaims); err != nil || len(encoded) > maxExtraClaimsBytes {
+		return domainerrors.Validation("extra_claims must be JSON of at most %d bytes", maxExtraClaimsBytes)
+	}
+	if err := validateClaimNamespace(settings); err != nil {
+		return err
+	}
+	return validateClaimTemplate(&settings.ClaimTemplate)
+}
+
+// validateClaimNamespace requires a custom claim namespace to be an absolute http(s) URL, which is
+// normalized to end with a slash.
+func validateClaimNamespace(settings *entities.DomainTokenSettings) error {
+	namespace := strings.TrimSpace(settings.ClaimNamespace)
+	if namespace == "" {
+		settings.ClaimNamespace = ""
+		return nil
+	}
+	parsed, err := url.Parse(namespace)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" ||
+		parsed.RawQuery != "" || parsed.Fragment != "" || len(namespace) > maxClaimNamespaceLen {
+		return domainerrors.Validation("claim_namespace must be an http or https URL of at most %d characters without query or fragment", maxClaimNamespaceLen)
+	}
+	if !strings.HasSuffix(namespace, "/") {
+		namespace += "/"
+	}
+	settings.ClaimNamespace = namespace
+	return nil
+}
+
+// claimNamespace is the prefix of the domain's template and extra claims, or empty when they are
+// issued under their own names.
+func claimNamespace(domain *entities.Domain) string {
+	if !domain.TokenSettings.NamespaceClaims {
+		return ""
+	}
+	if domain.TokenSettings.ClaimNamespace != "" {
+		return domain.TokenSettings.ClaimNamespace
+	}
+	return "https://" + domain.Domain + "/claims/"
+}
+
+// accessTokenTTL is the domain's access token lifetime, or fallback when it has none.
+func accessTokenTTL(domain *entities.Domain, fallback time.Duration) time.Duration {
+	if domain.TokenSettings.AccessTokenTTLMinutes > 0 {
+		return time.Duration(domain.TokenSettings.AccessTokenTTLMinutes) * time.Minute
+	}
+	return fallback
+}
+
+// refreshTTL is the domain's hosted session lifetime, or fallback when it has none.
+func refreshTTL(domain *entities.Domain, fallback time.Duration) time.Duration {
+	if domain.TokenSettings.RefreshTTLMinutes > 0 {
+		return time.Duration(domain.TokenSettings.RefreshTTLMinutes) * time.Minute
+	}
+	return fallback
+}
+
+// accessTokenClaims adds a domain's template and extra claims to the standard claims of an access
+// token, prefixed with namespace when it is set.
+type accessTokenClaims struct {
+	TokenClaims
+	template  map[string]interface{}
+	extra     map[string]interface{}
+	namespace string
+}
+
+func (c accessTokenClaims) MarshalJSON() ([]byte, error) {
+	standard, err := json.Marshal(c.TokenClaims)
+	if err != nil || len(c.template)+len(c.extra) == 0 {
+		return standard, err
+	}
+	var claims map[string]json.RawMessage
+	if err := json.Unmarshal(standard, &claims); err != nil {
+		return nil, err
+	}
+	// Standard claims win over template claims, and both over extra claims stored before a name
+	// became reserved
+	for _, added := range []map[string]interface{}{c.template, c.extra} {
+		for name, value := range added {
+			name = c.namespace + name
+			// act and scope are only ever set by the server, so an extra claim can't fake them
+			if _, taken := claims[name]; taken || name == "act" || name == "scope" {
+				continue
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			claims[name] = encoded
+		}
+	}
+	return json.Marshal(claims)
+}
@@ -0,0 +1,5 @@
+package services
+
+import "go.opentelemetry.io/otel"
+
+var tracer = otel.Tracer("backend/internal/application/services")
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+const maxDeviceNameLength = 255
+
+// RememberedDevice is returned by a login that asked to remember the device. The token is shown
+// only here; later logins send it back to skip MFA challenges.
+type RememberedDevice struct {
+	ID        uuid.UUID `json:"id" format:"uuid" example:"5d2c8a1e-3b4f-4c6d-9e7f-8a9b0c1d2e3f"`
+	Token     string    `json:"token" example:"4f9c2a7e1b3d5f6a8c0e2b4d6f8a1c3e5b7d9f0a2c4e6b8d0f1a3c5e7b9d2f4a"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TrustedDeviceService remembers the devices users sign in from, so logins from them skip MFA
+// challenges, and lets users list and revoke them.
+type TrustedDeviceService interface {
+	IsTrusted(ctx context.Context, user *entities.User, token, ip string) bool
+	Remember(ctx context.Context, user *entities.User, token, name, ip string) (*RememberedDevice, error)
+	ListDevices(ctx context.Context, userID uuid.UUID) ([]*entities.TrustedDevice, error)
+	RevokeDevice(ctx context.Context, userID, deviceID uuid.UUID) error
+	RevokeAllDevices(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+type trustedDeviceService struct {
+	repo     repositories.TrustedDeviceRepository
+	userRepo repositories.UserRepository
+	config   *config.TrustedDeviceConfig
+}
+
+func NewTrustedDeviceService(repo repositories.TrustedDeviceRepository, userRepo repositories.UserRepository, cfg *config.TrustedDeviceConfig) TrustedDeviceService {
+	return &trustedDeviceService{repo: repo, userRepo: userRepo, config: cfg}
+}
+
+// IsTrusted reports whether token belongs to an unexpired device of the user, and records the
+// login from it. A failed lookup counts as untrusted.
+func (s *trustedDeviceService) IsTrusted(ctx context.Context, user *entities.User, token, ip string) bool {
+	device := s.lookup(ctx, user, token)
+	if device == nil {
+		return false
+	}
+	if err := s.repo.RecordUse(ctx, device, ip, time.Now().Add(s.config.TTL)); err != nil {
+		log.Printf("Failed to record the use of trusted device %s: %v", device.ID, err)
+	}
+	return true
+}
+
+// Remember trusts the device the user signed in from. A device that already sent a valid token
+// keeps it, with its expiry renewed; otherwise a new device and token are created.
+func (s *trustedDeviceService) Remember(ctx context.Context, user *entities.User, token, name, ip string) (*RememberedDevice, error) {
+	ctx, span := tracer.Start(ctx, "TrustedDeviceService.Remember")
+	defer span.End()
+
+	expiresAt := time.Now().Add(s.config.TTL)
+	if device := s.lookup(ctx, user, token); device != nil {
+		if err := s.repo.RecordUse(ctx, device, ip, expiresAt); err != nil {
+			return nil, err
+		}
+		return &RememberedDevice{ID: device.ID, Token: token, ExpiresAt: device.ExpiresAt}, nil
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate device token: %w", err)
+	}
+	token = hex.EncodeToString(raw)
+	if len(name) > maxDeviceNameLength {
+		// Cutting a user agent may split a character, which the database would reject
+		name = strings.ToValidUTF8(name[:maxDeviceNameLength], "")
+	}
+	device := &entities.TrustedDevice{
+		DomainID:  user.DomainID,
+		UserID:    user.ID,
+		TokenHash: hashSecret(token),
+		Name:      name,
+		LastIP:    ip,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.Create(ctx, device); err != nil {
+		return nil, err
+	}
+	return &RememberedDevice{ID: device.ID, Token: token, ExpiresAt: device.ExpiresAt}, nil
+}
+
+func (s *trustedDeviceService) lookup(ctx context.Context, user *entities.User, token string) *entities.TrustedDevice {
+	if token == "" {
+		return nil
+	}
+	device, err := s.repo.GetByTokenHash(ctx, user.DomainID, hashSecret(token))
+	if err != nil || device.UserID != user.ID {
+		return nil
+	}
+	return device
+}
+
+func (s *trustedDeviceService) ListDevices(ctx context.Context, userID uuid.UUID) ([]*entities.TrustedDevice, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+	return s.repo.ListByUser(ctx, user.DomainID, user.ID)
+}
+
+func (s *trustedDeviceService) RevokeDevice(ctx context.Context, userID, deviceID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domainerrors.NotFound("user not found")
+	}
+	return notFoundOr(s.repo.Delete(ctx, user.DomainID, user.ID, deviceID), "device not found")
+}
+
+// RevokeAllDevices forgets every device of the user and returns how many there were.
+func (s *trustedDeviceService) RevokeAllDevices(ctx context.Context, userID uuid.UUID) (int64, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return 0, domainerrors.NotFound("user not found")
+	}
+	return s.repo.DeleteByUser(ctx, user.DomainID, user.ID)
+}
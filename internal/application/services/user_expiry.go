@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultExpiryWindow = 30 * 24 * time.Hour
+	maxExpiryWindow     = 365 * 24 * time.Hour
+)
+
+// accountDisabled reports whether the user has been disabled or has passed its end date; the
+// end date is checked directly so access stops on time even before the sweep runs.
+func accountDisabled(user *entities.User, now time.Time) bool {
+	return user.DisabledAt != nil || (user.ValidUntil != nil && !now.Before(*user.ValidUntil))
+}
+
+// SetUserValidUntil sets or clears (nil) the account end date. Moving the end date of a disabled
+// user into the future, or clearing it, re-enables the account; tokens revoked when it was
+// disabled stay revoked.
+func (s *userService) SetUserValidUntil(ctx context.Context, id uuid.UUID, validUntil *time.Time) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.SetUserValidUntil")
+	defer span.End()
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+	if user.BreakGlass {
+		return nil, errBreakGlassManaged()
+	}
+
+	if user.DisabledAt != nil && (validUntil == nil || validUntil.After(time.Now())) {
+		user.DisabledAt = nil
+	}
+	user.ValidUntil = validUntil
+	if err := s.repo.SetValidity(ctx, user.ID, user.ValidUntil, user.DisabledAt); err != nil {
+		return nil, err
+	}
+	// Suspending the account by ending it now revokes its tokens, so they stay revoked if it is re-enabled
+	if validUntil != nil && !validUntil.After(time.Now()) {
+		if err := revokeSessions(ctx, s.repo, user); err != nil {
+			return nil, err
+		}
+	}
+	s.events.Publish(ctx, user.DomainID, EventUserUpdated, user.ID, user)
+	return user, nil
+}
+
+// ListExpiringUsers returns the active users of the domain whose end date falls within the
+// window (default 30 days, at most a year), soonest first.
+func (s *userService) ListExpiringUsers(ctx context.Context, domainID uuid.UUID, within time.Duration) ([]*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.ListExpiringUsers")
+	defer span.End()
+
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	if within <= 0 {
+		within = defaultExpiryWindow
+	}
+	if within > maxExpiryWindow {
+		within = maxExpiryWindow
+	}
+	return s.repo.ListExpiring(ctx, domainID, time.Now().Add(within))
+}
+
+// DisableExpiredUsers disables every account past its end date and revokes its sessions,
+// returning how many were disabled. A failure on one user doesn't stop the others.
+func (s *userService) DisableExpiredUsers(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "UserService.DisableExpiredUsers")
+	defer span.End()
+
+	now := time.Now()
+	users, err := s.repo.ListExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	disabled := 0
+	for _, user := range users {
+		if err := s.repo.Disable(ctx, user.ID, now); err != nil {
+			log.Printf("Failed to disable expired user %s: %v", user.ID, err)
+			continue
+		}
+		user.DisabledAt = &now
+		user.SessionsRevokedAt = &now
+		s.events.Publish(ctx, user.DomainID, EventUserDisabled, user.ID, user)
+		disabled++
+	}
+	return disabled, nil
+}
+
+// RunExpirySweep calls DisableExpiredUsers every interval until ctx is cancelled.
+func (s *userService) RunExpirySweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			disabled, err := s.DisableExpiredUsers(ctx)
+			if err != nil {
+				log.Printf("User expiry sweep failed: %v", err)
+			} else if disabled > 0 {
+				log.Printf("User expiry sweep disabled %d account(s)", disabled)
+			}
+		}
+	}
+}
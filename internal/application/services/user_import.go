@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// MaxUserImportRows bounds a single import so one request can't hold a transaction open indefinitely.
+const MaxUserImportRows = 5000
+
+// Import row outcomes.
+const (
+	ImportStatusCreated = "created"
+	ImportStatusSkipped = "skipped"
+	ImportStatusError   = "error"
+)
+
+// UserImportRow is one user from an uploaded CSV or JSON file. RoleID falls back to the import's
+// default role when empty.
+type UserImportRow struct {
+	Username   string `json:"username"`
+	Email      string `json:"email"`
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	RoleID     string `json:"role_id"`
+	Password   string `json:"password"`
+	ExternalID string `json:"external_id"`
+}
+
+type UserImportReport struct {
+	Total   int                    `json:"total"`
+	Created int                    `json:"created"`
+	Skipped int                    `json:"skipped"`
+	Failed  int                    `json:"failed"`
+	DryRun  bool                   `json:"dry_run"`
+	Rows    []*UserImportRowResult `json:"rows"`
+}
+
+type UserImportRowResult struct {
+	Row      int        `json:"row" minimum:"1" example:"1"`
+	Username string     `json:"username" example:"jdoe"`
+	Email    string     `json:"email" example:"jane.doe@example.com"`
+	Status   string     `json:"status" enums:"created,skipped,error" example:"created"`
+	UserID   *uuid.UUID `json:"user_id,omitempty" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// ImportUsers validates every row, skips rows whose username, email or external ID is already
+// taken (in the database or earlier in the file) and inserts the remaining rows in one transaction.
+// Row numbers are 1-based positions in the uploaded data. A dry run validates the rows the same
+// way and reports the rows that would be created as created, without inserting them.
+func (s *userService) ImportUsers(ctx context.Context, domainID uuid.UUID, defaultRoleID *uuid.UUID, rows []*UserImportRow, dryRun bool) (*UserImportReport, error) {
+	ctx, span := tracer.Start(ctx, "UserService.ImportUsers")
+	defer span.End()
+
+	if len(rows) == 0 {
+		return nil, domainerrors.Validation("no rows to import")
+	}
+	if len(rows) > MaxUserImportRows {
+		return nil, domainerrors.Validation("too many rows: limit is %d", MaxUserImportRows)
+	}
+
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+
+	roles, err := s.roleRepo.GetByDomainID(ctx, domainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+	domainRoles := make(map[uuid.UUID]bool, len(roles))
+	for _, role := range roles {
+		domainRoles[role.ID] = true
+	}
+	if defaultRoleID != nil && !domainRoles[*defaultRoleID] {
+		return nil, domainerrors.Validation("default role not found in domain")
+	}
+
+	report := &UserImportReport{Total: len(rows), DryRun: dryRun, Rows: make([]*UserImportRowResult, len(rows))}
+	var usernames, emails, externalIDs []string
+	for i, row := range rows {
+		normalizeImportRow(row)
+		report.Rows[i] = &UserImportRowResult{Row: i + 1, Username: row.Username, Email: row.Email}
+		usernames = append(usernames, row.Username)
+		emails = append(emails, row.Email)
+		if row.ExternalID != "" {
+			externalIDs = append(externalIDs, row.ExternalID)
+		}
+	}
+
+	conflicts, err := s.repo.FindConflicts(ctx, domainID, usernames, emails, externalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing users: %w", err)
+	}
+
+	seenUsernames := make(map[string]int)
+	seenEmails := make(map[string]int)
+	seenExternalIDs := make(map[string]int)
+	var pending []*entities.User
+	var pendingResults []*UserImportRowResult
+
+	for i, row := range rows {
+		result := report.Rows[i]
+
+		user, err := s.importRowUser(domain, domainRoles, defaultRoleID, row)
+		if err != nil {
+			result.Status, result.Error = ImportStatusError, err.Error()
+			continue
+		}
+
+		if reason := duplicateReason(row, conflicts, seenUsernames, seenEmails, seenExternalIDs); reason != "" {
+			result.Status, result.Error = ImportStatusSkipped, reason
+			continue
+		}
+		seenUsernames[row.Username] = result.Row
+		seenEmails[row.Email] = result.Row
+		if row.ExternalID != "" {
+			seenExternalIDs[row.ExternalID] = result.Row
+		}
+
+		pending = append(pending, user)
+		pendingResults = append(pendingResults, result)
+	}
+
+	if dryRun {
+		for _, result := range pendingResults {
+			result.Status = ImportStatusCreated
+		}
+	} else if len(pending) > 0 {
+		if err := s.repo.CreateBatch(ctx, domainID, pending); err != nil {
+			// The batch is atomic, so no row was created
+			for _, result := range pendingResults {
+				result.Status, result.Error = ImportStatusError, "batch insert failed: "+err.Error()
+			}
+		} else {
+			for i, result := range pendingResults {
+				result.Status = ImportStatusCreated
+				result.UserID = &pending[i].ID
+				s.events.Publish(ctx, domainID, EventUserCreated, pending[i].ID, pending[i])
+			}
+		}
+	}
+
+	for _, result := range report.Rows {
+		switch result.Status {
+		case ImportStatusCreated:
+			report.Created++
+		case ImportStatusSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+func (s *userService) importRowUser(domain *entities.Domain, domainRoles map[uuid.UUID]bool, defaultRoleID *uuid.UUID, row *UserImportRow) (*entities.User, error) {
+	var missing []string
+	for _, field := range []struct{ name, value string }{
+		{"username", row.Username}, {"email", row.Email}, {"first_name", row.FirstName}, {"last_name", row.LastName},
+	} {
+		if field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required fields: %s", strings.Join(missing, ", "))
+	}
+	if _, err := mail.ParseAddress(row.Email); err != nil {
+		return nil, fmt.Errorf("invalid email")
+	}
+
+	var roleID uuid.UUID
+	switch {
+	case row.RoleID != "":
+		parsed, err := uuid.Parse(row.RoleID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid role_id")
+		}
+		roleID = parsed
+	case defaultRoleID != nil:
+		roleID = *defaultRoleID
+	default:
+		return nil, fmt.Errorf("role_id is required")
+	}
+	if !domainRoles[roleID] {
+		return nil, fmt.Errorf("role not found in domain")
+	}
+
+	hashedPassword, err := s.passwordHashFor(domain, row.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	var externalID *string
+	if row.ExternalID != "" {
+		externalID = &row.ExternalID
+	}
+	return &entities.User{
+		DomainID:     domain.DomainID,
+		RoleID:       roleID,
+		ExternalID:   externalID,
+		FirstName:    row.FirstName,
+		LastName:     row.LastName,
+		Username:     row.Username,
+		Email:        row.Email,
+		PasswordHash: hashedPassword,
+	}, nil
+}
+
+func duplicateReason(row *UserImportRow, conflicts *repositories.UserConflicts, seenUsernames, seenEmails, seenExternalIDs map[string]int) string {
+	switch {
+	case conflicts.Usernames[row.Username]:
+		return "username already exists"
+	case conflicts.Emails[row.Email]:
+		return "email already exists"
+	case row.ExternalID != "" && conflicts.ExternalIDs[row.ExternalID]:
+		return "external_id already exists"
+	}
+	if first, ok := seenUsernames[row.Username]; ok {
+		return fmt.Sprintf("duplicate username of row %d", first)
+	}
+	if first, ok := seenEmails[row.Email]; ok {
+		return fmt.Sprintf("duplicate email of row %d", first)
+	}
+	if first, ok := seenExternalIDs[row.ExternalID]; ok && row.ExternalID != "" {
+		return fmt.Sprintf("duplicate external_id of row %d", first)
+	}
+	return ""
+}
+
+func normalizeImportRow(row *UserImportRow) {
+	row.Username = strings.TrimSpace(row.Username)
+	row.Email = strings.TrimSpace(row.Email)
+	row.FirstName = strings.TrimSpace(row.FirstName)
+	row.LastName = strings.TrimSpace(row.LastName)
+	row.RoleID = strings.TrimSpace(row.RoleID)
+	row.ExternalID = strings.TrimSpace(row.ExternalID)
+}
@@ -1,101 +1,373 @@
 package services
 
 import (
-	"crypto/sha256"
-	"fmt"
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
 
 	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
 	"backend/internal/infrastructure/repositories"
 
 	"github.com/google/uuid"
 )
 
 type UserService interface {
-	GetUserByID(id uuid.UUID) (*entities.User, error)
-	GetUserByUsername(username string) (*entities.User, error)
-	GetUserByEmail(email string) (*entities.User, error)
-	GetUsersByDomainID(domainID uuid.UUID) ([]*entities.User, error)
-	CreateUser(domainID, roleID uuid.UUID, firstName, lastName, username, email, password string) (*entities.User, error)
-	UpdateUser(id uuid.UUID, firstName, lastName, username, email string, roleID uuid.UUID) (*entities.User, error)
-	ResetUserPassword(id uuid.UUID, newPassword string) error
-	DeleteUser(id uuid.UUID) error
-	ListUsersWithPagination(search string, domainID uuid.UUID, page, limit int) (*repositories.UserListResult, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
+	GetUserByExternalID(ctx context.Context, domainID uuid.UUID, externalID string) (*entities.User, error)
+	GetUsersByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.User, error)
+	CreateUser(ctx context.Context, domainID, roleID uuid.UUID, firstName, lastName, username, email, password string, externalID *string, validUntil *time.Time) (*entities.User, error)
+	UpdateUser(ctx context.Context, id uuid.UUID, firstName, lastName, username, email string, roleID uuid.UUID, externalID *string) (*entities.User, error)
+	UpdateUserByExternalID(ctx context.Context, domainID uuid.UUID, externalID, firstName, lastName, username, email string, roleID uuid.UUID) (*entities.User, error)
+	UpsertUserByExternalID(ctx context.Context, domainID uuid.UUID, externalID, firstName, lastName, username, email, password string, roleID uuid.UUID) (*entities.User, bool, error)
+	ResetUserPassword(ctx context.Context, id uuid.UUID, newPassword string) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) (*UserBatch, error)
+	ListUsersWithPagination(ctx context.Context, filter repositories.UserListFilter, domainID uuid.UUID, page, limit int) (*repositories.UserListResult, error)
+	ListUsersAfter(ctx context.Context, filter repositories.UserListFilter, domainID uuid.UUID, cursor string, limit int) (*repositories.UserCursorPage, error)
+	ImportUsers(ctx context.Context, domainID uuid.UUID, defaultRoleID *uuid.UUID, rows []*UserImportRow, dryRun bool) (*UserImportReport, error)
+	ExportUsers(ctx context.Context, domainID uuid.UUID, fn func(*entities.User) error) error
+	SetUserValidUntil(ctx context.Context, id uuid.UUID, validUntil *time.Time) (*entities.User, error)
+	ListExpiringUsers(ctx context.Context, domainID uuid.UUID, within time.Duration) ([]*entities.User, error)
+	DisableExpiredUsers(ctx context.Context) (int, error)
+	RunExpirySweep(ctx context.Context, interval time.Duration)
+	ListBreakGlassAccounts(ctx context.Context) ([]*entities.User, error)
+	CreateBreakGlassAccount(ctx context.Context, domainID, roleID uuid.UUID, firstName, lastName, username, email, password string) (*entities.User, error)
+	RotateBreakGlassPassword(ctx context.Context, id uuid.UUID, password string) error
+	DeleteBreakGlassAccount(ctx context.Context, id uuid.UUID) error
+	CreateServiceAccount(ctx context.Context, domainID, roleID uuid.UUID, username, name string) (*CreatedServiceAccount, error)
 	VerifyPassword(hashedPassword, password string) bool
 }
 
 type userService struct {
-	repo repositories.UserRepository
+	repo       repositories.UserRepository
+	roleRepo   repositories.RoleRepository
+	domainRepo repositories.DomainRepository
+	passwords  *passwordStore
+	events     EventService
+	emails     EmailService
+	tx         repositories.TxManager
 }
 
-func NewUserService(repo repositories.UserRepository) UserService {
-	return &userService{repo: repo}
+func NewUserService(repo repositories.UserRepository, roleRepo repositories.RoleRepository, domainRepo repositories.DomainRepository, historyRepo repositories.PasswordHistoryRepository, events EventService, emails EmailService, tx repositories.TxManager) UserService {
+	return &userService{repo: repo, roleRepo: roleRepo, domainRepo: domainRepo, passwords: &passwordStore{userRepo: repo, historyRepo: historyRepo}, events: events, emails: emails, tx: tx}
 }
 
-func (s *userService) GetUserByID(id uuid.UUID) (*entities.User, error) {
-	return s.repo.GetByID(id)
+func (s *userService) GetUserByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, "user not found")
+	}
+	return user, nil
 }
 
-func (s *userService) GetUserByUsername(username string) (*entities.User, error) {
-	return s.repo.GetByUsername(username)
+func (s *userService) GetUserByExternalID(ctx context.Context, domainID uuid.UUID, externalID string) (*entities.User, error) {
+	user, err := s.repo.GetByExternalID(ctx, domainID, externalID)
+	if err != nil {
+		return nil, notFoundOr(err, "user not found")
+	}
+	return user, nil
 }
 
-func (s *userService) GetUserByEmail(email string) (*entities.User, error) {
-	return s.repo.GetByEmail(email)
+func (s *userService) GetUsersByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.User, error) {
+	return s.repo.GetByDomainID(ctx, domainID)
 }
 
-func (s *userService) GetUsersByDomainID(domainID uuid.UUID) ([]*entities.User, error) {
-	return s.repo.GetByDomainID(domainID)
-}
+// CreateUser creates a user with the role. An admin caller must manage the domain; org unit admins,
+// who don't assign roles, can't create users.
+func (s *userService) CreateUser(ctx context.Context, domainID, roleID uuid.UUID, firstName, lastName, username, email, password string, externalID *string, validUntil *time.Time) (*entities.User, error) {
+	if principal := AdminPrincipalFrom(ctx); principal != nil && principal.Delegated() {
+		return nil, errOrgUnitAdminScope()
+	}
+	if !adminCanManage(ctx, domainID) {
+		return nil, domainerrors.Forbidden("domain admins can only manage their own domain").WithCode("domain_forbidden")
+	}
+	domain, err := s.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	if err := s.ensureRoleInDomain(ctx, domainID, roleID); err != nil {
+		return nil, err
+	}
 
-func (s *userService) CreateUser(domainID, roleID uuid.UUID, firstName, lastName, username, email, password string) (*entities.User, error) {
-	// Hash the password
-	hashedPassword := s.hashPassword(password)
+	externalID = normalizeExternalID(externalID)
+	if err := s.ensureExternalIDFree(ctx, domainID, externalID, uuid.Nil); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUnique(ctx, domainID, username, email, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.passwordHashFor(domain, password)
+	if err != nil {
+		return nil, err
+	}
 
 	user := &entities.User{
 		DomainID:     domainID,
 		RoleID:       roleID,
+		ExternalID:   externalID,
 		FirstName:    firstName,
 		LastName:     lastName,
 		Username:     username,
 		Email:        email,
 		PasswordHash: hashedPassword,
+		ValidUntil:   validUntil,
 	}
-	err := s.repo.Create(user)
+	// The user and its user.created event are stored together or not at all
+	err = s.tx.WithinTenantTx(ctx, domainID, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, user); err != nil {
+			return conflictFromDB(err)
+		}
+		return s.events.Record(ctx, domainID, EventUserCreated, user.ID, user)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (s *userService) UpdateUser(id uuid.UUID, firstName, lastName, username, email string, roleID uuid.UUID) (*entities.User, error) {
-	user := &entities.User{
-		ID:        id,
-		FirstName: firstName,
-		LastName:  lastName,
-		Username:  username,
-		Email:     email,
-		RoleID:    roleID,
+// UpdateUser replaces the user's profile. A nil externalID keeps the current one; an empty
+// string clears it.
+func (s *userService) UpdateUser(ctx context.Context, id uuid.UUID, firstName, lastName, username, email string, roleID uuid.UUID, externalID *string) (*entities.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+	if user.BreakGlass {
+		return nil, errBreakGlassManaged()
 	}
-	err := s.repo.Update(user)
+
+	if externalID != nil {
+		user.ExternalID = normalizeExternalID(externalID)
+		if err := s.ensureExternalIDFree(ctx, user.DomainID, user.ExternalID, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return s.applyUpdate(ctx, user, firstName, lastName, username, email, roleID)
+}
+
+func (s *userService) UpdateUserByExternalID(ctx context.Context, domainID uuid.UUID, externalID, firstName, lastName, username, email string, roleID uuid.UUID) (*entities.User, error) {
+	user, err := s.repo.GetByExternalID(ctx, domainID, strings.TrimSpace(externalID))
 	if err != nil {
+		return nil, domainerrors.NotFound("user not found")
+	}
+	return s.applyUpdate(ctx, user, firstName, lastName, username, email, roleID)
+}
+
+// UpsertUserByExternalID creates the user when the external ID is unknown in the domain and
+// updates it otherwise, reporting whether it was created. The password is only used on create,
+// and an update that changes nothing is not written, so repeated syncs of the same record are no-ops.
+func (s *userService) UpsertUserByExternalID(ctx context.Context, domainID uuid.UUID, externalID, firstName, lastName, username, email, password string, roleID uuid.UUID) (*entities.User, bool, error) {
+	ctx, span := tracer.Start(ctx, "UserService.UpsertUserByExternalID")
+	defer span.End()
+
+	externalID = strings.TrimSpace(externalID)
+	if externalID == "" {
+		return nil, false, domainerrors.Validation("external ID is required")
+	}
+
+	user, err := s.repo.GetByExternalID(ctx, domainID, externalID)
+	if errors.Is(err, sql.ErrNoRows) {
+		user, err = s.CreateUser(ctx, domainID, roleID, firstName, lastName, username, email, password, &externalID, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		return user, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if user.FirstName == firstName && user.LastName == lastName && user.Username == username && user.Email == email && user.RoleID == roleID {
+		return user, false, nil
+	}
+	user, err = s.applyUpdate(ctx, user, firstName, lastName, username, email, roleID)
+	if err != nil {
+		return nil, false, err
+	}
+	return user, false, nil
+}
+
+func (s *userService) applyUpdate(ctx context.Context, user *entities.User, firstName, lastName, username, email string, roleID uuid.UUID) (*entities.User, error) {
+	if err := s.ensureUnique(ctx, user.DomainID, username, email, user.ID); err != nil {
 		return nil, err
 	}
+
+	roleChanged := user.RoleID != roleID
+	if roleChanged {
+		// Roles carry permissions beyond the unit, so only domain admins assign them
+		if principal := AdminPrincipalFrom(ctx); principal != nil && principal.Delegated() {
+			return nil, domainerrors.Forbidden("org unit admins cannot change roles").WithCode("org_unit_forbidden")
+		}
+		if err := s.ensureRoleInDomain(ctx, user.DomainID, roleID); err != nil {
+			return nil, err
+		}
+	}
+	user.FirstName = firstName
+	user.LastName = lastName
+	user.Username = username
+	user.Email = email
+	user.RoleID = roleID
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, conflictFromDB(err)
+	}
+	// Tokens carry the role, so those issued under the previous one are revoked
+	if roleChanged {
+		if err := revokeSessions(ctx, s.repo, user); err != nil {
+			return nil, err
+		}
+	}
+	s.events.Publish(ctx, user.DomainID, EventUserUpdated, user.ID, user)
 	return user, nil
 }
 
-func (s *userService) ResetUserPassword(id uuid.UUID, newPassword string) error {
-	// Hash the new password
-	hashedPassword := s.hashPassword(newPassword)
+func errUsernameTaken() error {
+	return domainerrors.Conflict("username is already used in this domain").WithCode("username_taken")
+}
+
+func errEmailTaken() error {
+	return domainerrors.Conflict("email is already used in this domain").WithCode("email_taken")
+}
+
+func errExternalIDTaken() error {
+	return domainerrors.Conflict("external ID is already used in this domain").WithCode("external_id_taken")
+}
+
+// ensureRoleInDomain keeps users from being given a role of another domain, which would let the
+// admins of one domain grant the permissions of another.
+func (s *userService) ensureRoleInDomain(ctx context.Context, domainID, roleID uuid.UUID) error {
+	role, err := s.roleRepo.GetByID(ctx, roleID)
+	if err != nil || role.DomainID != domainID {
+		return domainerrors.Validation("role does not belong to this domain")
+	}
+	return nil
+}
+
+// ensureUnique rejects a username or email already held by another user of the domain.
+func (s *userService) ensureUnique(ctx context.Context, domainID uuid.UUID, username, email string, userID uuid.UUID) error {
+	existing, err := s.repo.FindDuplicate(ctx, domainID, username, email, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Username == username {
+		return errUsernameTaken()
+	}
+	return errEmailTaken()
+}
+
+// conflictFromDB turns a unique violation from a concurrent write into the error the
+// service-level checks would have returned.
+func conflictFromDB(err error) error {
+	switch repositories.UniqueViolation(err) {
+	case "idx_users_domain_username":
+		return errUsernameTaken()
+	case "idx_users_domain_email":
+		return errEmailTaken()
+	case "idx_users_domain_external_id":
+		return errExternalIDTaken()
+	}
+	return err
+}
+
+// ensureExternalIDFree rejects an external ID already held by another user of the domain.
+func (s *userService) ensureExternalIDFree(ctx context.Context, domainID uuid.UUID, externalID *string, userID uuid.UUID) error {
+	if externalID == nil {
+		return nil
+	}
+	if existing, err := s.repo.GetByExternalID(ctx, domainID, *externalID); err == nil && existing.ID != userID {
+		return errExternalIDTaken()
+	}
+	return nil
+}
 
-	// Update the user's password hash
-	return s.repo.UpdatePassword(id, hashedPassword)
+// normalizeExternalID trims the value and treats blank as unset.
+func normalizeExternalID(externalID *string) *string {
+	if externalID == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*externalID)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
 }
 
-func (s *userService) DeleteUser(id uuid.UUID) error {
-	return s.repo.Delete(id)
+// ExportUsers streams every user of the domain to fn.
+func (s *userService) ExportUsers(ctx context.Context, domainID uuid.UUID, fn func(*entities.User) error) error {
+	ctx, span := tracer.Start(ctx, "UserService.ExportUsers")
+	defer span.End()
+
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return domainerrors.NotFound("domain not found")
+	}
+	return s.repo.StreamByDomainID(ctx, domainID, fn)
 }
 
-func (s *userService) ListUsersWithPagination(search string, domainID uuid.UUID, page, limit int) (*repositories.UserListResult, error) {
+func (s *userService) ResetUserPassword(ctx context.Context, id uuid.UUID, newPassword string) error {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domainerrors.NotFound("user not found")
+	}
+	if user.BreakGlass {
+		return errBreakGlassManaged()
+	}
+	if user.IsService() {
+		return errServiceAccount()
+	}
+	domain, err := s.domainRepo.GetByID(ctx, user.DomainID)
+	if err != nil {
+		return domainerrors.NotFound("domain not found")
+	}
+	if domain.LoginMode == entities.LoginModePasswordless {
+		return domainerrors.Validation("passwords are disabled for this domain")
+	}
+	if err := s.passwords.set(ctx, domain, user, newPassword); err != nil {
+		return err
+	}
+	// The password is reset either way; the notice is only a heads-up
+	if user.Email != "" {
+		if err := s.emails.Send(ctx, domain.DomainID, user.Email, EmailPasswordReset, map[string]any{"Username": user.Username}); err != nil {
+			log.Printf("Failed to queue the password reset notice for user %s: %v", user.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *userService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.BreakGlass {
+		return errBreakGlassManaged()
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.events.Publish(ctx, user.DomainID, EventUserDeleted, user.ID, user)
+	return nil
+}
+
+func (s *userService) ListUsersWithPagination(ctx context.Context, filter repositories.UserListFilter, domainID uuid.UUID, page, limit int) (*repositories.UserListResult, error) {
+	ctx, span := tracer.Start(ctx, "UserService.ListUsersWithPagination")
+	defer span.End()
+
+	domainID, err := listUserDomain(ctx, domainID, &filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := normalizeUserListFilter(&filter); err != nil {
+		return nil, err
+	}
+
 	// Set default values
 	if page <= 0 {
 		page = 1
@@ -104,14 +376,73 @@ func (s *userService) ListUsersWithPagination(search string, domainID uuid.UUID,
 		limit = 10
 	}
 
-	return s.repo.ListWithPagination(search, domainID, page, limit)
+	return s.repo.ListWithPagination(ctx, filter, domainID, page, limit)
+}
+
+// ListUsersAfter lists users oldest first, resuming after the cursor of the previous page; an
+// empty cursor starts at the first user. The cursor only works in that order, so another sort is
+// rejected.
+func (s *userService) ListUsersAfter(ctx context.Context, filter repositories.UserListFilter, domainID uuid.UUID, cursor string, limit int) (*repositories.UserCursorPage, error) {
+	ctx, span := tracer.Start(ctx, "UserService.ListUsersAfter")
+	defer span.End()
+
+	domainID, err := listUserDomain(ctx, domainID, &filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := normalizeUserListFilter(&filter); err != nil {
+		return nil, err
+	}
+	if !filter.Sort.IsDefault() {
+		return nil, errCursorSort()
+	}
+	after, err := repositories.ParseListCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	return s.repo.ListAfter(ctx, filter, domainID, after, limit)
+}
+
+// normalizeUserListFilter checks the status and creation range of a listing filter and reduces
+// the email domain to its lowercase host name.
+func normalizeUserListFilter(filter *repositories.UserListFilter) error {
+	switch filter.Status {
+	case "", repositories.UserStatusActive, repositories.UserStatusDisabled,
+		repositories.UserStatusExpired, repositories.UserStatusPendingDeletion:
+	default:
+		return domainerrors.Validation("status must be one of active, disabled, expired, pending_deletion").WithCode("invalid_status")
+	}
+	filter.EmailDomain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(filter.EmailDomain), "@"))
+	if filter.EmailDomain != "" && !emailDomainPattern.MatchString(filter.EmailDomain) {
+		return domainerrors.Validation("email_domain must be a host name such as example.com")
+	}
+	return validateCreatedRange(filter.CreatedAfter, filter.CreatedBefore)
+}
+
+// passwordHashFor applies the domain's login mode and password policy: passwordless users keep
+// an empty hash, which no password can match.
+func (s *userService) passwordHashFor(domain *entities.Domain, password string) (string, error) {
+	if domain.LoginMode == entities.LoginModePasswordless {
+		if password != "" {
+			return "", domainerrors.Validation("passwords are disabled for this domain")
+		}
+		return "", nil
+	}
+	if err := checkPasswordPolicy(domain, password); err != nil {
+		return "", err
+	}
+	return s.hashPassword(password), nil
 }
 
 func (s *userService) hashPassword(password string) string {
-	hash := sha256.Sum256([]byte(password))
-	return fmt.Sprintf("%x", hash)
+	return hashPassword(password)
 }
 
 func (s *userService) VerifyPassword(hashedPassword, password string) bool {
-	return s.hashPassword(password) == hashedPassword
+	return passwordMatches(hashedPassword, password)
 }
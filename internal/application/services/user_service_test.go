@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateUserAdminScope(t *testing.T) {
+	domainID, unitID := uuid.New(), uuid.New()
+	tests := []struct {
+		name      string
+		principal *AdminPrincipal
+		domainID  uuid.UUID
+	}{
+		{"domain admin of another domain", &AdminPrincipal{UserID: uuid.New(), DomainID: uuid.New()}, domainID},
+		{"org unit admin of the domain", &AdminPrincipal{UserID: uuid.New(), DomainID: domainID, OrgUnitID: unitID}, domainID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithAdminPrincipal(context.Background(), tt.principal)
+			// The caller is refused before any repository is used
+			_, err := (&userService{}).CreateUser(ctx, tt.domainID, uuid.New(), "Ada", "Lovelace", "ada", "ada@example.com", "Secret123!", nil, nil)
+			if !errors.Is(err, domainerrors.ErrForbidden) {
+				t.Errorf("CreateUser error = %v, want forbidden", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/metrics"
+	"backend/internal/infrastructure/netguard"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/infrastructure/webhook"
+
+	"github.com/google/uuid"
+)
+
+const (
+	webhookSecretPrefix      = "whsec_"
+	maxWebhooksPerDomain     = 10
+	defaultDeliveryPageSize  = 50
+	maxDeliveryPageSize      = 500
+	maxWebhookDeliveryErrLen = 500
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, domainID uuid.UUID, rawURL string, events []string, enabled *bool) (*CreatedWebhook, error)
+	ListWebhooks(ctx context.Context, domainID uuid.UUID) ([]*entities.Webhook, error)
+	GetWebhook(ctx context.Context, domainID, id uuid.UUID) (*entities.Webhook, error)
+	UpdateWebhook(ctx context.Context, domainID, id uuid.UUID, rawURL string, events []string, enabled *bool) (*entities.Webhook, error)
+	RotateSecret(ctx context.Context, domainID, id uuid.UUID) (*CreatedWebhook, error)
+	DeleteWebhook(ctx context.Context, domainID, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, domainID, webhookID uuid.UUID, status string, limit int) ([]*entities.WebhookDelivery, error)
+	Redeliver(ctx context.Context, domainID, webhookID, id uuid.UUID) error
+	DeliverDue(ctx context.Context) (int, error)
+	RunDeliveries(ctx context.Context, interval time.Duration)
+}
+
+// CreatedWebhook carries the signing secret, which is only returned at creation and rotation.
+type CreatedWebhook struct {
+	*entities.Webhook
+	Secret string `json:"secret" example:"whsec_5f2b8c0e9a7d4e3f8b1c6a2d9e0f7b3c5f2b8c0e9a7d4e3f8b1c6a2d9e0f7b3c"`
+}
+
+type webhookService struct {
+	repo       repositories.WebhookRepository
+	domainRepo repositories.DomainRepository
+	client     *webhook.Client
+	config     *config.WebhookConfig
+}
+
+// NewWebhookService delivers to the endpoints guard accepts; deliveries to the others fail.
+func NewWebhookService(repo repositories.WebhookRepository, domainRepo repositories.DomainRepository, cfg *config.WebhookConfig, guard *netguard.Guard) WebhookService {
+	return &webhookService{repo: repo, domainRepo: domainRepo, client: webhook.NewClient(cfg.Timeout, guard), config: cfg}
+}
+
+// CreateWebhook subscribes the URL to the domain's events of the given types, or to every type
+// when none are given. Webhooks are enabled unless enabled is false.
+func (s *webhookService) CreateWebhook(ctx context.Context, domainID uuid.UUID, rawURL string, events []string, enabled *bool) (*CreatedWebhook, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.CreateWebhook")
+	defer span.End()
+
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	hook := &entities.Webhook{DomainID: domainID, Enabled: enabled == nil || *enabled}
+	if err := s.apply(hook, rawURL, events); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListByDomain(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWebhooksPerDomain {
+		return nil, domainerrors.Conflict("a domain can have at most %d webhooks", maxWebhooksPerDomain).WithCode("webhook_limit")
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	hook.Secret = secret
+	if err := s.repo.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+	return &CreatedWebhook{Webhook: hook, Secret: secret}, nil
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context, domainID uuid.UUID) ([]*entities.Webhook, error) {
+	if _, err := s.domainRepo.GetByID(ctx, domainID); err != nil {
+		return nil, domainerrors.NotFound("domain not found")
+	}
+	return s.repo.ListByDomain(ctx, domainID)
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, domainID, id uuid.UUID) (*entities.Webhook, error) {
+	hook, err := s.repo.GetByID(ctx, domainID, id)
+	if err != nil {
+		return nil, notFoundOr(err, "webhook not found")
+	}
+	return hook, nil
+}
+
+// UpdateWebhook replaces the URL and event filter; a nil enabled keeps the current state.
+// Disabling a webhook holds its pending deliveries until it is enabled again.
+func (s *webhookService) UpdateWebhook(ctx context.Context, domainID, id uuid.UUID, rawURL string, events []string, enabled *bool) (*entities.Webhook, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.UpdateWebhook")
+	defer span.End()
+
+	hook, err := s.GetWebhook(ctx, domainID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(hook, rawURL, events); err != nil {
+		return nil, err
+	}
+	if enabled != nil {
+		hook.Enabled = *enabled
+	}
+	if err := s.repo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// RotateSecret replaces the signing secret; deliveries sent from then on use the new one.
+func (s *webhookService) RotateSecret(ctx context.Context, domainID, id uuid.UUID) (*CreatedWebhook, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.RotateSecret")
+	defer span.End()
+
+	hook, err := s.GetWebhook(ctx, domainID, id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetSecret(ctx, domainID, id, secret); err != nil {
+		return nil, notFoundOr(err, "webhook not found")
+	}
+	hook.Secret = secret
+	return &CreatedWebhook{Webhook: hook, Secret: secret}, nil
+}
+
+// DeleteWebhook removes the webhook and its delivery log; pending deliveries are dropped.
+func (s *webhookService) DeleteWebhook(ctx context.Context, domainID, id uuid.UUID) error {
+	return notFoundOr(s.repo.Delete(ctx, domainID, id), "webhook not found")
+}
+
+// ListDeliveries returns the webhook's most recent deliveries, newest first, optionally only those
+// with the given status.
+func (s *webhookService) ListDeliveries(ctx context.Context, domainID, webhookID uuid.UUID, status string, limit int) ([]*entities.WebhookDelivery, error) {
+	switch status {
+	case "", entities.WebhookDeliveryPending, entities.WebhookDeliverySucceeded, entities.WebhookDeliveryFailed:
+	default:
+		return nil, domainerrors.Validation("status must be one of pending, succeeded or failed")
+	}
+	if _, err := s.GetWebhook(ctx, domainID, webhookID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryPageSize
+	}
+	if limit > maxDeliveryPageSize {
+		limit = maxDeliveryPageSize
+	}
+	return s.repo.ListDeliveries(ctx, domainID, webhookID, status, limit)
+}
+
+// Redeliver queues a delivery again with a fresh set of attempts, e.g. after the endpoint has
+// been fixed.
+func (s *webhookService) Redeliver(ctx context.Context, domainID, webhookID, id uuid.UUID) error {
+	return notFoundOr(s.repo.Redeliver(ctx, domainID, webhookID, id), "delivery not found")
+}
+
+// DeliverDue sends the deliveries that are due, in parallel, and returns how many were attempted.
+func (s *webhookService) DeliverDue(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.DeliverDue")
+	defer span.End()
+
+	// The lease outlasts an attempt, so a delivery is not picked up again while it is in flight
+	due, err := s.repo.ClaimDueDeliveries(ctx, 2*s.config.Timeout+time.Minute, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, item := range due {
+		wg.Add(1)
+		go func(item *repositories.DueWebhookDelivery) {
+			defer wg.Done()
+			s.attempt(ctx, item)
+		}(item)
+	}
+	wg.Wait()
+	return len(due), nil
+}
+
+// attempt sends one delivery and records the outcome, scheduling a retry with exponential backoff
+// until the attempts run out. Any response outside 2xx counts as a failure.
+func (s *webhookService) attempt(ctx context.Context, item *repositories.DueWebhookDelivery) {
+	delivery := item.Delivery
+	body, err := json.Marshal(item.Event)
+	if err != nil {
+		log.Printf("Failed to encode webhook delivery %s: %v", delivery.ID, err)
+		return
+	}
+
+	status, err := s.client.Deliver(ctx, webhook.Request{
+		URL:        item.URL,
+		Secret:     item.Secret,
+		DeliveryID: delivery.ID.String(),
+		EventType:  delivery.EventType,
+		Body:       body,
+	})
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = nil
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+	switch {
+	case err == nil:
+		delivery.Status, delivery.NextAttemptAt, delivery.Error = entities.WebhookDeliverySucceeded, nil, nil
+		metrics.RecordWebhookDelivery("success")
+	case delivery.Attempts >= s.config.MaxAttempts:
+		delivery.Status, delivery.NextAttemptAt, delivery.Error = entities.WebhookDeliveryFailed, nil, deliveryError(err)
+		metrics.RecordWebhookDelivery("failed")
+	default:
+		next := now.Add(s.backoff(delivery.Attempts))
+		delivery.NextAttemptAt, delivery.Error = &next, deliveryError(err)
+		metrics.RecordWebhookDelivery("retry")
+	}
+
+	// Record the outcome even if shutdown cancelled ctx mid-attempt, so the lease isn't left to expire
+	if err := s.repo.RecordAttempt(context.WithoutCancel(ctx), delivery); err != nil {
+		log.Printf("Failed to record webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (s *webhookService) backoff(attempts int) time.Duration {
+	wait := s.config.RetryBackoff
+	for i := 1; i < attempts && wait < s.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, s.config.MaxBackoff)
+}
+
+// RunDeliveries calls DeliverDue every interval until ctx is cancelled.
+func (s *webhookService) RunDeliveries(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DeliverDue(ctx); err != nil {
+				log.Printf("Webhook delivery failed: %v", err)
+			}
+		}
+	}
+}
+
+// apply validates and sets the URL and event filter of the webhook.
+func (s *webhookService) apply(hook *entities.Webhook, rawURL string, events []string) error {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && !(u.Scheme == "http" && s.config.AllowHTTP)) {
+		if s.config.AllowHTTP {
+			return domainerrors.Validation("url must be an absolute http or https URL")
+		}
+		return domainerrors.Validation("url must be an absolute https URL")
+	}
+	if u.User != nil || u.Fragment != "" {
+		return domainerrors.Validation("url must not contain credentials or a fragment")
+	}
+
+	filter := []string{}
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if !slices.Contains(EventTypes, event) {
+			return domainerrors.Validation("unknown event type %q", event)
+		}
+		if !slices.Contains(filter, event) {
+			filter = append(filter, event)
+		}
+	}
+	hook.URL, hook.Events = rawURL, filter
+	return nil
+}
+
+// deliveryError shortens an attempt's error to what the delivery log keeps.
+func deliveryError(err error) *string {
+	message := err.Error()
+	if len(message) > maxWebhookDeliveryErrLen {
+		message = message[:maxWebhookDeliveryErrLen]
+	}
+	return &message
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return webhookSecretPrefix + hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,8 @@
+package entities
+
+// AccountDeletionSettings controls self-service account deletion through DELETE /auth/me. A
+// requested deletion takes effect after the grace period and can be cancelled until then.
+type AccountDeletionSettings struct {
+	Enabled   bool `json:"enabled" example:"true"`
+	GraceDays int  `json:"grace_days" minimum:"0" maximum:"90" example:"14"` // 0 uses the server default
+}
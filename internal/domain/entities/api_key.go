@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKey struct {
+	ID                 uuid.UUID  `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID           uuid.UUID  `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Name               string     `json:"name" db:"name" example:"ci-pipeline"`
+	Prefix             string     `json:"prefix" db:"key_prefix" example:"nrm_3f9a2b"`
+	KeyHash            string     `json:"-" db:"key_hash"`                                                            // Don't expose in JSON
+	RateLimitPerMinute *int       `json:"rate_limit_per_minute" db:"rate_limit_per_minute" minimum:"1" example:"120"` // nil uses the server default
+	DailyQuota         *int       `json:"daily_quota" db:"daily_quota" minimum:"1" example:"50000"`                   // nil uses the server default
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
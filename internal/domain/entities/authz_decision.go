@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuthzDecision struct {
+	ID          uuid.UUID `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID    uuid.UUID `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	RoleID      uuid.UUID `json:"role_id" db:"role_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Resource    string    `json:"resource" db:"resource" example:"documents"`
+	Action      string    `json:"action" db:"action" example:"read"`
+	Allowed     bool      `json:"allowed" db:"allowed" example:"true"`
+	MatchedRule string    `json:"matched_rule" db:"matched_rule" example:"documents:read"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
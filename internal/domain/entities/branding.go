@@ -0,0 +1,11 @@
+package entities
+
+// DomainBranding themes the hosted login page at /login. RedirectURIs are the pages of the
+// domain's apps the page may send users back to; the page refuses any other redirect_uri.
+type DomainBranding struct {
+	DisplayName     string   `json:"display_name,omitempty" example:"Acme"` // defaults to the domain name
+	LogoURL         string   `json:"logo_url,omitempty" example:"https://acme.example.com/logo.png"`
+	PrimaryColor    string   `json:"primary_color,omitempty" example:"#1a73e8"`
+	BackgroundColor string   `json:"background_color,omitempty" example:"#f5f7fb"`
+	RedirectURIs    []string `json:"redirect_uris,omitempty" example:"https://app.acme.example.com/callback"`
+}
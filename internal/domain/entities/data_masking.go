@@ -0,0 +1,10 @@
+package entities
+
+// PermissionReadPII lets admins see user fields their domain masks.
+const PermissionReadPII = "pii:read"
+
+// DataMaskingSettings lists the user fields masked in admin responses for viewers without the
+// pii:read permission.
+type DataMaskingSettings struct {
+	Fields []string `json:"fields,omitempty" enums:"email,first_name,last_name,external_id" example:"email"`
+}
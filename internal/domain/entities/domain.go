@@ -1,9 +1,36 @@
 package entities
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Domain struct {
-	DomainID uuid.UUID `json:"domain_id" db:"domain_id"`
-	Name     string    `json:"name" db:"name"`
-	Domain   string    `json:"domain" db:"domain"`
+	DomainID        uuid.UUID               `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Name            string                  `json:"name" db:"name" example:"Acme Corp"`
+	Domain          string                  `json:"domain" db:"domain" example:"acme.example.com"`
+	Residency       string                  `json:"residency" db:"residency" example:"eu"`
+	LoginMode       string                  `json:"login_mode" db:"login_mode" enums:"password,passwordless" example:"password"`
+	PasswordPolicy  PasswordPolicy          `json:"password_policy" db:"password_policy"`
+	Registration    RegistrationSettings    `json:"registration" db:"registration"`
+	Branding        DomainBranding          `json:"branding" db:"branding"`
+	AccountDeletion AccountDeletionSettings `json:"account_deletion" db:"account_deletion"`
+	TokenSettings   DomainTokenSettings     `json:"token_settings" db:"token_settings"`
+	DataMasking     DataMaskingSettings     `json:"data_masking" db:"data_masking"`
+	Telemetry       TelemetrySettings       `json:"telemetry" db:"telemetry"`
+	// Plan and Tags group domains for platform operators, e.g. to target bulk operations
+	Plan string   `json:"plan" db:"plan" example:"enterprise"`
+	Tags []string `json:"tags" db:"tags" example:"eu-pilot"`
+	// SuspendedAt is set while platform operators have suspended the domain; its users cannot sign in
+	SuspendedAt *time.Time `json:"suspended_at" db:"suspended_at"`
+	// DeletionRequestedAt is set once the domain is queued for deletion; from then on nobody can
+	// sign in to it
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty" db:"deletion_requested_at"`
 }
+
+// Domain login modes. Passwordless domains sign users in with emailed one-time codes or magic links.
+const (
+	LoginModePassword     = "password"
+	LoginModePasswordless = "passwordless"
+)
@@ -0,0 +1,15 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DomainAlias struct {
+	ID        uuid.UUID `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID  uuid.UUID `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Hostname  string    `json:"hostname" db:"hostname" example:"login.acme.example.com"`
+	IsPrimary bool      `json:"is_primary" db:"is_primary" example:"true"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
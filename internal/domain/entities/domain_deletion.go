@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain deletion statuses. A failed deletion is resumed by requesting it again.
+const (
+	DomainDeletionPending   = "pending"
+	DomainDeletionRunning   = "running"
+	DomainDeletionCompleted = "completed"
+	DomainDeletionFailed    = "failed"
+)
+
+// DomainDeletion is the background deletion of a domain: its data is deleted in batches, one kind
+// at a time, and then the domain itself. It outlives the domain so its outcome can still be read.
+type DomainDeletion struct {
+	ID         uuid.UUID        `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID   uuid.UUID        `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	DomainName string           `json:"domain_name" db:"domain_name" example:"Acme Corp"`
+	Status     string           `json:"status" db:"status" enums:"pending,running,completed,failed" example:"running"`
+	Step       string           `json:"step,omitempty" db:"step" example:"users"` // the kind of data being deleted
+	Deleted    map[string]int64 `json:"deleted" db:"deleted"`                     // rows deleted so far, by kind
+	Error      string           `json:"error,omitempty" db:"error"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	StartedAt  *time.Time       `json:"started_at" db:"started_at"`
+	FinishedAt *time.Time       `json:"finished_at" db:"finished_at"`
+}
@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain job actions.
+const (
+	DomainJobSuspend        = "suspend"
+	DomainJobUnsuspend      = "unsuspend"
+	DomainJobMessage        = "message"
+	DomainJobUpdateSettings = "update_settings"
+)
+
+// Domain job statuses. Jobs interrupted by a restart are marked failed.
+const (
+	DomainJobPending   = "pending"
+	DomainJobRunning   = "running"
+	DomainJobCompleted = "completed"
+	DomainJobFailed    = "failed"
+)
+
+// DomainSelector picks the domains of a bulk operation. Set criteria must all match; an empty
+// selector matches nothing unless All is set.
+type DomainSelector struct {
+	All       bool        `json:"all,omitempty" example:"false"`
+	Plan      string      `json:"plan,omitempty" example:"enterprise"`
+	Tags      []string    `json:"tags,omitempty" example:"eu-pilot"` // domains carrying any of the tags
+	DomainIDs []uuid.UUID `json:"domain_ids,omitempty" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+}
+
+// DomainMessage is an email sent to every active user of the selected domains.
+type DomainMessage struct {
+	Subject string `json:"subject" example:"Scheduled maintenance"`
+	Body    string `json:"body" example:"Sign-in will be unavailable on Saturday from 02:00 to 03:00 UTC."`
+}
+
+// DomainSettingsChange lists the settings a bulk operation applies; nil settings are left as they are.
+type DomainSettingsChange struct {
+	LoginMode       *string                  `json:"login_mode,omitempty" enums:"password,passwordless" example:"password"`
+	PasswordPolicy  *PasswordPolicy          `json:"password_policy,omitempty"`
+	AccountDeletion *AccountDeletionSettings `json:"account_deletion,omitempty"`
+	TokenSettings   *DomainTokenSettings     `json:"token_settings,omitempty"`
+	DataMasking     *DataMaskingSettings     `json:"data_masking,omitempty"`
+}
+
+// DomainJobResult is the outcome of a bulk operation on one domain.
+type DomainJobResult struct {
+	DomainID uuid.UUID `json:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Name     string    `json:"name" example:"Acme Corp"`
+	Status   string    `json:"status" enums:"succeeded,failed" example:"succeeded"`
+	Detail   string    `json:"detail,omitempty" example:"emailed 42 users"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// DomainJob is a platform operator's bulk operation, run in the background one domain at a time.
+type DomainJob struct {
+	ID         uuid.UUID             `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Action     string                `json:"action" db:"action" enums:"suspend,unsuspend,message,update_settings" example:"suspend"`
+	Selector   DomainSelector        `json:"selector" db:"selector"`
+	Message    *DomainMessage        `json:"message,omitempty" db:"message"`
+	Settings   *DomainSettingsChange `json:"settings,omitempty" db:"settings"`
+	Status     string                `json:"status" db:"status" enums:"pending,running,completed,failed" example:"running"`
+	Total      int                   `json:"total" db:"total" example:"12"`
+	Succeeded  int                   `json:"succeeded" db:"succeeded" example:"7"`
+	Failed     int                   `json:"failed" db:"failed" example:"1"`
+	Error      string                `json:"error,omitempty" db:"error"` // why the job as a whole failed
+	Results    []DomainJobResult     `json:"results" db:"results"`
+	CreatedAt  time.Time             `json:"created_at" db:"created_at"`
+	StartedAt  *time.Time            `json:"started_at" db:"started_at"`
+	FinishedAt *time.Time            `json:"finished_at" db:"finished_at"`
+}
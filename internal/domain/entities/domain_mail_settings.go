@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Mail providers a domain can send through. SES is used through its SMTP interface.
+const (
+	MailProviderSMTP = "smtp"
+	MailProviderSES  = "ses"
+)
+
+// DomainMailSettings is a domain's own outgoing mail sender, used instead of the platform default.
+type DomainMailSettings struct {
+	DomainID      uuid.UUID  `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Provider      string     `json:"provider" db:"provider" enums:"smtp,ses" example:"smtp"`
+	Host          string     `json:"host" db:"host" example:"smtp.acme.example.com"`
+	Port          string     `json:"port" db:"port" example:"587"`
+	Region        string     `json:"region,omitempty" db:"region" example:"eu-west-1"` // SES only; the host is derived from it
+	Username      string     `json:"username" db:"username" example:"mailer@acme.example.com"`
+	Password      string     `json:"-" db:"password"`
+	PasswordSet   bool       `json:"password_set" db:"-"`
+	FromAddress   string     `json:"from_address" db:"from_address" example:"Acme <no-reply@acme.example.com>"`
+	Enabled       bool       `json:"enabled" db:"enabled" example:"true"`
+	LastTestedAt  *time.Time `json:"last_tested_at" db:"last_tested_at"`
+	LastTestError *string    `json:"last_test_error" db:"last_test_error"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailTemplate is the subject and plain-text body of an email, written as Go text/template
+// templates.
+type EmailTemplate struct {
+	Subject string `json:"subject" example:"Join {{.Product}}"`
+	Body    string `json:"body" example:"You have been invited to {{.Product}}.\n\nAccept with this link:\n{{.Link}}"`
+}
+
+// EmailBranding is how a domain's emails look: the name they use, a support contact, a footer and
+// overrides of the built-in templates by name.
+type EmailBranding struct {
+	DomainID     uuid.UUID                `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	ProductName  string                   `json:"product_name" db:"product_name" example:"Acme Portal"` // empty uses the domain name
+	SupportEmail string                   `json:"support_email" db:"support_email" example:"support@acme.example.com"`
+	Footer       string                   `json:"footer" db:"footer" example:"Acme Corp, 1 Main Street"`
+	Templates    map[string]EmailTemplate `json:"templates" db:"templates"`
+	UpdatedAt    time.Time                `json:"updated_at" db:"updated_at"`
+}
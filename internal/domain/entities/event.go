@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event records a change to a domain's data. Sequence numbers increase by one per domain, so
+// consumers can resume from the last sequence they processed.
+type Event struct {
+	ID        uuid.UUID       `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID  uuid.UUID       `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Sequence  int64           `json:"sequence" db:"sequence" minimum:"1" example:"42"`
+	Type      string          `json:"type" db:"type" enums:"user.created,user.updated,user.deleted,role.created,role.updated,role.deleted,login.succeeded,login.failed,login.challenged,login.code_sent" example:"user.created"`
+	SubjectID *uuid.UUID      `json:"subject_id" db:"subject_id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Payload   json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	// ImpersonatorID is the support engineer who caused the event while impersonating a user
+	ImpersonatorID *uuid.UUID `json:"impersonator_id,omitempty" db:"impersonator_id" format:"uuid" example:"5b1c9d2e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Integration kinds with health checks.
+const (
+	IntegrationSMTP     = "smtp"
+	IntegrationWebhooks = "webhooks"
+)
+
+// Integration health statuses. Integrations not checked yet are reported as unknown.
+const (
+	IntegrationHealthy   = "healthy"
+	IntegrationUnhealthy = "unhealthy"
+	IntegrationUnknown   = "unknown"
+)
+
+// IntegrationHealth is the latest health check result of one tenant integration.
+type IntegrationHealth struct {
+	DomainID            uuid.UUID  `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Kind                string     `json:"kind" db:"kind" enums:"smtp,webhooks" example:"smtp"`
+	Status              string     `json:"status" db:"status" enums:"healthy,unhealthy,unknown" example:"healthy"`
+	ConsecutiveFailures int        `json:"consecutive_failures" db:"consecutive_failures" example:"0"`
+	LastError           *string    `json:"last_error" db:"last_error"`
+	LastCheckedAt       *time.Time `json:"last_checked_at" db:"last_checked_at"`
+	LastSuccessAt       *time.Time `json:"last_success_at" db:"last_success_at"`
+}
@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired"
+)
+
+// Invitation asks someone to join a domain by email. The invitee accepts through the emailed link,
+// whose token is only stored hashed.
+type Invitation struct {
+	ID         uuid.UUID  `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID   uuid.UUID  `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Email      string     `json:"email" db:"email" example:"jane.doe@example.com"`
+	RoleID     uuid.UUID  `json:"role_id" db:"role_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	FirstName  string     `json:"first_name" db:"first_name" example:"Jane"`
+	LastName   string     `json:"last_name" db:"last_name" example:"Doe"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	Status     string     `json:"status" enums:"pending,accepted,revoked,expired" example:"pending"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	SentAt     time.Time  `json:"sent_at" db:"sent_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// StatusAt reports the invitation's state at the given time.
+func (i *Invitation) StatusAt(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case !now.Before(i.ExpiresAt):
+		return InvitationExpired
+	default:
+		return InvitationPending
+	}
+}
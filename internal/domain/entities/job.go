@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job statuses. A failed run puts the job back to pending until its attempts run out; then it
+// stays failed until an operator retries it.
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Job is a unit of background work, run by the handler registered for its kind.
+type Job struct {
+	ID          uuid.UUID       `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Kind        string          `json:"kind" db:"kind" example:"mail.send"`
+	Payload     json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	Status      string          `json:"status" db:"status" enums:"pending,running,succeeded,failed" example:"pending"`
+	Attempts    int             `json:"attempts" db:"attempts" example:"1"`
+	MaxAttempts int             `json:"max_attempts" db:"max_attempts" example:"5"`
+	RunAt       time.Time       `json:"run_at" db:"run_at"` // when a pending job is next due
+	LastError   string          `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	StartedAt   *time.Time      `json:"started_at" db:"started_at"`
+	FinishedAt  *time.Time      `json:"finished_at" db:"finished_at"`
+}
+
+// JobStats counts jobs by status.
+type JobStats struct {
+	Pending   int64 `json:"pending" example:"3"`
+	Running   int64 `json:"running" example:"1"`
+	Succeeded int64 `json:"succeeded" example:"120"`
+	Failed    int64 `json:"failed" example:"2"`
+}
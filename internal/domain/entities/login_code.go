@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginCode is a one-time email code and magic link token for passwordless login.
+// Only hashes of the code and token are stored.
+type LoginCode struct {
+	ID         uuid.UUID  `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID   uuid.UUID  `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	CodeHash   string     `json:"-" db:"code_hash"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	Attempts   int        `json:"attempts" db:"attempts" minimum:"0" example:"1"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	ConsumedAt *time.Time `json:"consumed_at" db:"consumed_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
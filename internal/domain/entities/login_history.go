@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginHistoryEntry is a sign-in attempt on a user's account, kept for security reviews.
+type LoginHistoryEntry struct {
+	ID        uuid.UUID `json:"id" db:"id" format:"uuid" example:"8e1f2a3b-4c5d-4e6f-8a9b-0c1d2e3f4a5b"`
+	DomainID  uuid.UUID `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Success   bool      `json:"success" db:"success" example:"false"`
+	Method    string    `json:"method" db:"method" example:"password"`
+	Reason    string    `json:"reason,omitempty" db:"reason" example:"invalid_password"` // why a failed attempt was refused
+	IP        string    `json:"ip" db:"ip" example:"203.0.113.7"`
+	UserAgent string    `json:"user_agent" db:"user_agent" example:"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) Safari/605.1.15"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
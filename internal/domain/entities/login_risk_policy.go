@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginRiskPolicy maps login risk scores (0-100) to actions; a nil threshold disables that action.
+// CaptchaAfterFailures also challenges an IP for a CAPTCHA once it has that many recent failed
+// logins, whatever its score.
+type LoginRiskPolicy struct {
+	DomainID         uuid.UUID `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	CaptchaThreshold *int      `json:"captcha_threshold" db:"captcha_threshold" minimum:"1" maximum:"100" example:"40"`
+	MFAThreshold     *int      `json:"mfa_threshold" db:"mfa_threshold" minimum:"1" maximum:"100" example:"70"`
+	BlockThreshold   *int      `json:"block_threshold" db:"block_threshold" minimum:"1" maximum:"100" example:"90"`
+	// CaptchaAfterFailures counts failures within LOGIN_RISK_FAILURE_WINDOW
+	CaptchaAfterFailures *int      `json:"captcha_after_failures" db:"captcha_after_failures" minimum:"1" maximum:"100" example:"3"`
+	UpdatedAt            time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrgUnit is a node of a domain's organization tree, such as a department. Units without a parent
+// are at the top of the tree.
+type OrgUnit struct {
+	ID          uuid.UUID  `json:"id" db:"id" format:"uuid" example:"c4d5e6f7-8a9b-4c0d-9e1f-2a3b4c5d6e7f"`
+	DomainID    uuid.UUID  `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	ParentID    *uuid.UUID `json:"parent_id" db:"parent_id" format:"uuid" example:"b3c4d5e6-7f8a-4b9c-8d0e-1f2a3b4c5d6e"`
+	Name        string     `json:"name" db:"name" example:"Platform Engineering"`
+	Description string     `json:"description" db:"description" example:"Infrastructure and developer tooling"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
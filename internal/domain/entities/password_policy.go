@@ -0,0 +1,15 @@
+package entities
+
+// DefaultPasswordMinLength applies when a domain's policy doesn't set a minimum length.
+const DefaultPasswordMinLength = 6
+
+// PasswordPolicy sets the rules new passwords of a domain must follow.
+type PasswordPolicy struct {
+	MinLength        int  `json:"min_length" minimum:"6" maximum:"128" example:"12"`
+	RequireUppercase bool `json:"require_uppercase" example:"true"`
+	RequireLowercase bool `json:"require_lowercase" example:"true"`
+	RequireDigit     bool `json:"require_digit" example:"true"`
+	RequireSymbol    bool `json:"require_symbol" example:"false"`
+	HistoryCount     int  `json:"history_count" minimum:"0" maximum:"24" example:"5"`   // reject reuse of the last N passwords; 0 allows reuse
+	MaxAgeDays       int  `json:"max_age_days" minimum:"0" maximum:"3650" example:"90"` // 0 never expires
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permissions of the admin API, checked when admin authorization is enforced. A domain admin
+// manages the users, roles and settings of their own domain; a system admin, who must belong to
+// the configured system domain, also manages the domains themselves.
+const (
+	PermissionDomainAdmin = "domain:admin"
+	PermissionSystemAdmin = "system:admin"
+)
+
+// PermissionOrgUnitAdmin delegates the administration of the users of an org unit: its holders
+// manage the users of their own unit and its descendants, but not their roles or anything else of
+// the domain.
+const PermissionOrgUnitAdmin = "org_unit:admin"
+
+// PermissionImpersonate lets support engineers get short-lived tokens acting as other users of
+// their domain.
+const PermissionImpersonate = "impersonate"
+
+// Scopes of the user routes of the admin API. Tokens narrowed to other scopes can't use them;
+// unscoped tokens can. Narrowed tokens reach the other admin routes only with the admin
+// permission itself among their scopes.
+const (
+	ScopeUsersRead  = "users:read"
+	ScopeUsersWrite = "users:write"
+)
+
+type Permission struct {
+	ID          uuid.UUID `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID    uuid.UUID `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Name        string    `json:"name" db:"name" example:"documents:read"` // resource:action
+	Resource    string    `json:"resource" db:"resource" example:"documents"`
+	Action      string    `json:"action" db:"action" example:"read"`
+	Description string    `json:"description" db:"description" example:"Read documents"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type RolePermission struct {
+	RoleID       uuid.UUID `json:"role_id" db:"role_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	PermissionID uuid.UUID `json:"permission_id" db:"permission_id" format:"uuid" example:"a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Policy is an ABAC policy document scoped to a domain.
+type Policy struct {
+	ID          uuid.UUID       `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID    uuid.UUID       `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Name        string          `json:"name" db:"name" example:"deny-outside-office-hours"`
+	Description string          `json:"description" db:"description" example:"Deny writes outside office hours"`
+	Document    json.RawMessage `json:"document" db:"document" swaggertype:"object"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Profile fields a user can share with a client app through /oauth/userinfo. The user's ID is
+// always returned as sub.
+const (
+	ProfileFieldUsername  = "username"
+	ProfileFieldEmail     = "email"
+	ProfileFieldFirstName = "first_name"
+	ProfileFieldLastName  = "last_name"
+	ProfileFieldRole      = "role"
+	ProfileFieldGroups    = "groups"
+)
+
+var ProfileFields = []string{ProfileFieldUsername, ProfileFieldEmail, ProfileFieldFirstName, ProfileFieldLastName, ProfileFieldRole, ProfileFieldGroups}
+
+// ProfileConsent records the profile fields a user agreed to share with one client app. Client
+// apps are identified by their API key.
+type ProfileConsent struct {
+	DomainID   uuid.UUID `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	UserID     uuid.UUID `json:"user_id" db:"user_id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	ClientID   uuid.UUID `json:"client_id" db:"client_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	ClientName string    `json:"client_name" db:"-" example:"mobile-app"`
+	Fields     []string  `json:"fields" db:"fields" enums:"username,email,first_name,last_name,role,groups" example:"email"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RegistrationSettings controls self-signup through /auth/register. Closed domains still accept
+// sign-ups carrying a registration code.
+type RegistrationSettings struct {
+	Open          bool       `json:"open" example:"false"`
+	DefaultRoleID *uuid.UUID `json:"default_role_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"` // required for open registration
+}
+
+// RegistrationCode is an invitation code that lets people sign up to a domain. Only the code's
+// prefix is kept readable; the full code is shown once when it is created.
+type RegistrationCode struct {
+	ID        uuid.UUID  `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID  uuid.UUID  `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	Prefix    string     `json:"prefix" db:"code_prefix" example:"K7QF"`
+	CodeHash  string     `json:"-" db:"code_hash"`
+	RoleID    *uuid.UUID `json:"role_id" db:"role_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"` // nil uses the domain's default role
+	MaxUses   *int       `json:"max_uses" db:"max_uses" minimum:"1" example:"50"`                                   // nil is unlimited
+	Uses      int        `json:"uses" db:"uses" example:"3"`
+	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
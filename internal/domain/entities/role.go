@@ -7,9 +7,9 @@ import (
 )
 
 type Role struct {
-	ID         uuid.UUID              `json:"id" db:"id"`
-	DomainID   uuid.UUID              `json:"domain_id" db:"domain_id"`
-	RoleName   string                 `json:"role_name" db:"role_name"`
+	ID         uuid.UUID              `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID   uuid.UUID              `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	RoleName   string                 `json:"role_name" db:"role_name" example:"editor"`
 	RoleClaims map[string]interface{} `json:"role_claims" db:"role_claims"`
 	CreatedAt  time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt  time.Time              `json:"updated_at" db:"updated_at"`
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoleRevision records one update of a role: its name and claims before and after, and who made it.
+type RoleRevision struct {
+	ID          uuid.UUID              `json:"id" db:"id" format:"uuid" example:"e5f6a7b8-9c0d-4e1f-8a2b-3c4d5e6f7a8b"`
+	DomainID    uuid.UUID              `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	RoleID      uuid.UUID              `json:"role_id" db:"role_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Revision    int                    `json:"revision" db:"revision" example:"3"`
+	ActorID     *uuid.UUID             `json:"actor_id" db:"actor_id" format:"uuid" example:"5b1c9d2e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"` // nil for the platform operator
+	OldRoleName string                 `json:"old_role_name" db:"old_role_name" example:"editor"`
+	NewRoleName string                 `json:"new_role_name" db:"new_role_name" example:"editor"`
+	OldClaims   map[string]interface{} `json:"old_claims" db:"old_claims"`
+	NewClaims   map[string]interface{} `json:"new_claims" db:"new_claims"`
+	Diff        RoleClaimsDiff         `json:"diff" db:"diff"`
+	RollbackOf  *int                   `json:"rollback_of" db:"rollback_of" example:"2"` // the revision a rollback undid
+	CreatedAt   time.Time              `json:"created_at" db:"created_at"`
+}
+
+// RoleClaimsDiff is the change in a role's top-level claims.
+type RoleClaimsDiff struct {
+	Added   map[string]interface{} `json:"added,omitempty"`
+	Removed map[string]interface{} `json:"removed,omitempty"`
+	Changed map[string]ClaimChange `json:"changed,omitempty"`
+}
+
+// ClaimChange is a claim whose value changed.
+type ClaimChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
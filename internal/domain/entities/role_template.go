@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoleTemplate is a role kept in a platform-wide catalog rather than in a domain, so operators can
+// create the same roles in every new tenant.
+type RoleTemplate struct {
+	ID          uuid.UUID              `json:"id" db:"id" format:"uuid" example:"5d7e2f1a-8b3c-4e6d-9f0a-1b2c3d4e5f6a"`
+	Name        string                 `json:"name" db:"name" example:"editor"` // also the name of roles created from the template
+	Description string                 `json:"description" db:"description" example:"Edits documents but cannot publish them"`
+	RoleClaims  map[string]interface{} `json:"role_claims" db:"role_claims"`
+	// Permissions are resource:action names assigned to roles created from the template; those
+	// missing from the domain's catalog are added to it
+	Permissions []string  `json:"permissions" db:"permissions" example:"documents:read,documents:write"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TelemetrySettings opts a domain in to exporting its anonymized sign-in funnel to the platform's
+// analytics warehouse. Usernames, emails and client IPs are never exported.
+type TelemetrySettings struct {
+	LoginExport bool `json:"login_export" example:"true"`
+}
+
+// TelemetryExportCursor records how far a domain's login telemetry has been exported.
+type TelemetryExportCursor struct {
+	DomainID     uuid.UUID  `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	LastSequence int64      `json:"last_sequence" db:"last_sequence" example:"42"` // last event exported
+	ExportedAt   *time.Time `json:"exported_at,omitempty" db:"exported_at"`
+	LastError    *string    `json:"last_error,omitempty" db:"last_error" example:"s3 returned status 403"`
+}
@@ -0,0 +1,27 @@
+package entities
+
+// DomainTokenSettings customizes the access tokens issued to the domain's users. Zero TTLs use the
+// server defaults. ExtraClaims are added to every access token as static values and may not
+// override the standard claims.
+type DomainTokenSettings struct {
+	AccessTokenTTLMinutes int `json:"access_token_ttl_minutes" minimum:"0" maximum:"10080" example:"60"` // 0 uses 24 hours
+	// RefreshTTLMinutes is how long the hosted login session keeps renewing access tokens at /auth/session/refresh
+	RefreshTTLMinutes int                    `json:"refresh_ttl_minutes" minimum:"0" maximum:"129600" example:"10080"` // 0 uses HOSTED_SESSION_TTL
+	Audience          []string               `json:"audience,omitempty" example:"https://api.acme.example.com"`
+	ExtraClaims       map[string]interface{} `json:"extra_claims,omitempty" swaggertype:"object"`
+	ClaimTemplate     TokenClaimTemplate     `json:"claim_template"`
+	// NamespaceClaims prefixes the template and extra claims with ClaimNamespace, or with
+	// https://<domain>/claims/ when it is empty, so they can't collide with registered claims or
+	// with the claims of another domain's tokens
+	NamespaceClaims bool   `json:"namespace_claims" example:"true"`
+	ClaimNamespace  string `json:"claim_namespace,omitempty" example:"https://acme.example.com/claims/"`
+}
+
+// TokenClaimTemplate picks profile data to embed in access tokens so consumers don't need to call
+// /auth/profile. Claims that would make the token too large are left out and claims_overage is set.
+type TokenClaimTemplate struct {
+	RoleClaims     bool     `json:"role_claims" example:"true"`  // the direct role's claims, as "role_claims"
+	Permissions    bool     `json:"permissions" example:"true"`  // effective permission names, as "permissions"
+	GroupNames     bool     `json:"group_names" example:"false"` // names of the user's groups, as "group_names"
+	UserAttributes []string `json:"user_attributes,omitempty" enums:"email,first_name,last_name,external_id" example:"email"`
+}
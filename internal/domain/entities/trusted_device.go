@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrustedDevice is a device a user chose to remember at login. Logins presenting its token skip MFA
+// challenges until it expires or is revoked.
+type TrustedDevice struct {
+	ID         uuid.UUID `json:"id" db:"id" format:"uuid" example:"5d2c8a1e-3b4f-4c6d-9e7f-8a9b0c1d2e3f"`
+	DomainID   uuid.UUID `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	UserID     uuid.UUID `json:"user_id" db:"user_id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	TokenHash  string    `json:"-" db:"token_hash"`
+	Name       string    `json:"name" db:"name" example:"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) Safari/605.1.15"`
+	LastIP     string    `json:"last_ip" db:"last_ip" example:"203.0.113.7"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at" db:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
+}
@@ -6,15 +6,48 @@ import (
 	"github.com/google/uuid"
 )
 
+// User types. Service accounts are used by backend services, which sign in with a client ID and
+// secret instead of a password.
+const (
+	UserTypeHuman   = "human"
+	UserTypeService = "service"
+)
+
 type User struct {
-	ID           uuid.UUID `json:"id" db:"id"`
-	DomainID     uuid.UUID `json:"domain_id" db:"domain_id"`
-	RoleID       uuid.UUID `json:"role_id" db:"role_id"`
-	FirstName    string    `json:"first_name" db:"first_name"`
-	LastName     string    `json:"last_name" db:"last_name"`
-	Username     string    `json:"username" db:"username"`
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"` // Don't expose in JSON
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID       uuid.UUID `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID uuid.UUID `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	RoleID   uuid.UUID `json:"role_id" db:"role_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	// OrgUnitID is the org unit the user belongs to; nil outside any
+	OrgUnitID    *uuid.UUID `json:"org_unit_id" db:"org_unit_id" format:"uuid" example:"c4d5e6f7-8a9b-4c0d-9e1f-2a3b4c5d6e7f"`
+	ExternalID   *string    `json:"external_id" db:"external_id" example:"EMP-00123"` // ID in an upstream system (e.g. HR), unique per domain
+	FirstName    string     `json:"first_name" db:"first_name" example:"Jane"`
+	LastName     string     `json:"last_name" db:"last_name" example:"Doe"`
+	Username     string     `json:"username" db:"username" example:"jdoe"`
+	Email        string     `json:"email" db:"email" example:"jane.doe@example.com"`
+	PasswordHash string     `json:"-" db:"password_hash"` // Don't expose in JSON
+	// ValidUntil ends a time-limited account; once passed the account is disabled and its sessions revoked
+	ValidUntil        *time.Time `json:"valid_until" db:"valid_until"`
+	DisabledAt        *time.Time `json:"disabled_at" db:"disabled_at"`
+	SessionsRevokedAt *time.Time `json:"-" db:"sessions_revoked_at"`
+	Type              string     `json:"type" db:"type" enums:"human,service" example:"human"`
+	ClientSecretHash  *string    `json:"-" db:"client_secret_hash"` // Set for service accounts only
+	// BreakGlass marks an emergency access account, which only platform operators can manage
+	BreakGlass bool `json:"break_glass" db:"break_glass"`
+	// PasswordChangedAt starts the password age checked against the domain's max_age_days
+	PasswordChangedAt time.Time `json:"password_changed_at" db:"password_changed_at"`
+	// DeletionScheduledAt is when a deletion the user requested takes effect; nil when none is pending
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" db:"deletion_scheduled_at"`
+	// LastLoginAt and LastLoginIP describe the last successful login; nil before the first
+	LastLoginAt *time.Time `json:"last_login_at" db:"last_login_at"`
+	LastLoginIP *string    `json:"last_login_ip" db:"last_login_ip" example:"203.0.113.7"`
+	// AvatarURL is where clients fetch the uploaded avatar; AvatarKey names it in file storage
+	AvatarURL *string   `json:"avatar_url" db:"avatar_url" example:"https://cdn.example.com/avatars/jdoe.png"`
+	AvatarKey *string   `json:"-" db:"avatar_key"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// IsService reports whether the user is a service account.
+func (u *User) IsService() bool {
+	return u.Type == UserTypeService
 }
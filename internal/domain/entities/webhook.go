@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook subscribes a URL to a domain's events. Every delivery is signed with the secret, which
+// is only returned when the webhook is created or its secret rotated.
+type Webhook struct {
+	ID        uuid.UUID `json:"id" db:"id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID  uuid.UUID `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	URL       string    `json:"url" db:"url" example:"https://hooks.acme.example.com/iam"`
+	Secret    string    `json:"-" db:"secret"`
+	Events    []string  `json:"events" db:"events" example:"user.created,login.failed"` // empty delivers every event type
+	Enabled   bool      `json:"enabled" db:"enabled" example:"true"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDelivery is one event queued for one webhook, with the outcome of its latest attempt.
+type WebhookDelivery struct {
+	ID             uuid.UUID  `json:"id" db:"id" format:"uuid" example:"1d4e5f6a-7b8c-4d9e-8f0a-1b2c3d4e5f6a"`
+	WebhookID      uuid.UUID  `json:"webhook_id" db:"webhook_id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	DomainID       uuid.UUID  `json:"domain_id" db:"domain_id" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	EventID        uuid.UUID  `json:"event_id" db:"event_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	EventType      string     `json:"event_type" db:"event_type" example:"user.created"`
+	Status         string     `json:"status" db:"status" enums:"pending,succeeded,failed" example:"pending"`
+	Attempts       int        `json:"attempts" db:"attempts" example:"1"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	ResponseStatus *int       `json:"response_status,omitempty" db:"response_status" example:"503"`
+	Error          *string    `json:"error,omitempty" db:"error" example:"endpoint returned status 503"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
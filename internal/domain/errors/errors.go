@@ -0,0 +1,80 @@
+// Package errors defines the kinds of failure services report, so the presentation layer can map
+// them to HTTP statuses without matching on messages. Import it as domainerrors.
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds. Match them with errors.Is.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+// Error is a failure of a given kind. Message is safe to show to clients; Code optionally
+// identifies the failure for clients that handle it programmatically (e.g. username_taken).
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Err     error // underlying cause, never shown to clients
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports whether target is the error's kind.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithCode sets the machine-readable code.
+func (e *Error) WithCode(code string) *Error {
+	e.Code = code
+	return e
+}
+
+// Wrap records the underlying cause.
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func newError(kind error, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// NotFound reports a missing resource.
+func NotFound(format string, args ...interface{}) *Error {
+	return newError(ErrNotFound, format, args...)
+}
+
+// Conflict reports a request that clashes with existing state, such as a duplicate name.
+func Conflict(format string, args ...interface{}) *Error {
+	return newError(ErrConflict, format, args...)
+}
+
+// Validation reports invalid input.
+func Validation(format string, args ...interface{}) *Error {
+	return newError(ErrValidation, format, args...)
+}
+
+// Unauthorized reports missing or invalid credentials.
+func Unauthorized(format string, args ...interface{}) *Error {
+	return newError(ErrUnauthorized, format, args...)
+}
+
+// Forbidden reports an authenticated request that is not allowed.
+func Forbidden(format string, args ...interface{}) *Error {
+	return newError(ErrForbidden, format, args...)
+}
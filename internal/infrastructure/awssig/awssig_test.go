@@ -0,0 +1,63 @@
+package awssig
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The expected signatures are those of the get-vanilla case of the AWS SigV4 test suite and of
+// the GET object example of the S3 documentation, without its Range header.
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name   string
+		signer Signer
+		url    string
+		now    time.Time
+		want   string
+	}{
+		{
+			"get-vanilla",
+			Signer{Service: "service", Region: "us-east-1", AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"},
+			"https://example.amazonaws.com/",
+			time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC),
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.signer.Sign(req, nil, tt.now)
+			if got := req.Header.Get("Authorization"); got != tt.want {
+				t.Errorf("Authorization =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignS3PayloadHeader(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPut, "https://bucket.s3.eu-west-1.amazonaws.com/a%20b", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	Signer{Service: "s3", Region: "eu-west-1", AccessKeyID: "id", SecretAccessKey: "secret", SessionToken: "token"}.Sign(req, []byte("body"), time.Now())
+
+	if got, want := req.Header.Get("X-Amz-Content-Sha256"), "230d8358dc8e8890b4c58deeb62912ee2f20357ae92a5cc861b98e68fe31acb5"; got != want {
+		t.Errorf("X-Amz-Content-Sha256 = %s, want %s", got, want)
+	}
+	want := "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date;x-amz-security-token,"
+	if got := req.Header.Get("Authorization"); !strings.Contains(got, want) {
+		t.Errorf("Authorization = %s, want %s", got, want)
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	if got, want := EscapePath("/avatars/a b+c~é.png"), "/avatars/a%20b%2Bc~%C3%A9.png"; got != want {
+		t.Errorf("EscapePath = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,20 @@
+// Package broker publishes domain events to a message broker.
+package broker
+
+import "context"
+
+// Message is one event to publish. ID is unique per event, so consumers and brokers that
+// deduplicate can drop the copies a retried publish may produce.
+type Message struct {
+	ID       string
+	DomainID string
+	Type     string
+	Body     []byte
+}
+
+// Publisher sends messages to the broker. Publish returns once the broker has acknowledged every
+// message, or with an error if any of them may not have been stored.
+type Publisher interface {
+	Publish(ctx context.Context, messages []Message) error
+	Close() error
+}
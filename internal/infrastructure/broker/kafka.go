@@ -0,0 +1,46 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher writes messages to one topic, keyed by domain so a domain's events stay in order
+// on one partition.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaPublisher(brokers []string, topic string) *KafkaPublisher {
+	return &KafkaPublisher{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		// Publish already hands over whole batches; don't hold them back waiting for more
+		BatchTimeout: time.Millisecond,
+		MaxAttempts:  1,
+	}}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, messages []Message) error {
+	records := make([]kafka.Message, len(messages))
+	for i, message := range messages {
+		records[i] = kafka.Message{
+			Key:   []byte(message.DomainID),
+			Value: message.Body,
+			Headers: []kafka.Header{
+				{Key: "event_id", Value: []byte(message.ID)},
+				{Key: "event_type", Value: []byte(message.Type)},
+				{Key: "content-type", Value: []byte("application/json")},
+			},
+		}
+	}
+	return p.writer.WriteMessages(ctx, records...)
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
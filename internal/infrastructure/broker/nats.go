@@ -0,0 +1,52 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes to JetStream on <prefix>.<domain id>.<event type>, so a stream must
+// capture <prefix>.>. The event ID is sent as Nats-Msg-Id for the stream's duplicate window.
+type NATSPublisher struct {
+	conn   *nats.Conn
+	js     nats.JetStreamContext
+	prefix string
+}
+
+// NewNATSPublisher connects to the server, retrying in the background if it is unreachable, so
+// the service starts while the broker is down.
+func NewNATSPublisher(url, prefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("nusarithm-iam"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open JetStream: %w", err)
+	}
+	return &NATSPublisher{conn: conn, js: js, prefix: prefix}, nil
+}
+
+// Publish sends the messages one at a time, in order, stopping at the first failure.
+func (p *NATSPublisher) Publish(ctx context.Context, messages []Message) error {
+	for _, message := range messages {
+		msg := nats.NewMsg(p.prefix + "." + message.DomainID + "." + message.Type)
+		msg.Data = message.Body
+		msg.Header.Set(nats.MsgIdHdr, message.ID)
+		msg.Header.Set("Content-Type", "application/json")
+		if _, err := p.js.PublishMsg(msg, nats.Context(ctx)); err != nil {
+			return fmt.Errorf("failed to publish event %s: %w", message.ID, err)
+		}
+	}
+	return nil
+}
+
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,74 @@
+// Package cache holds serialized values for a limited time, in process memory or in Redis.
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Cache stores values under string keys until their TTL passes or they are deleted. Get reports
+// a missing or expired key as found == false, not as an error.
+type Cache interface {
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache keeps entries in process memory. Deletes only reach this instance, so other
+// instances serve their copy until it expires.
+type MemoryCache struct {
+	mu        sync.Mutex
+	entries   map[string]entry
+	lastSweep time.Time
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]entry)}
+}
+
+// memorySweepInterval bounds how often Set scans for expired entries.
+const memorySweepInterval = time.Minute
+
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !time.Now().Before(e.expiresAt) {
+		return nil, false, nil
+	}
+	return e.value, true, nil
+}
+
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.lastSweep) >= memorySweepInterval {
+		c.lastSweep = now
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = entry{value: value, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (c *MemoryCache) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Package captcha verifies the tokens CAPTCHA widgets hand to client apps. reCAPTCHA, hCaptcha and
+// Cloudflare Turnstile share the siteverify API: the token and secret are posted as a form and the
+// answer says whether the challenge was passed.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"backend/internal/infrastructure/config"
+)
+
+// Verifier checks a widget token. It reports false for a token the provider rejects, and an error
+// when the provider couldn't be asked.
+type Verifier interface {
+	Verify(ctx context.Context, token, remoteIP string) (bool, error)
+}
+
+var verifyURLs = map[string]string{
+	config.CaptchaRecaptcha: "https://www.google.com/recaptcha/api/siteverify",
+	config.CaptchaHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	config.CaptchaTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+// New returns the verifier of the configured provider, or nil when none is configured.
+func New(cfg *config.CaptchaConfig) Verifier {
+	if cfg.Provider == "" {
+		return nil
+	}
+	verifyURL := cfg.VerifyURL
+	if verifyURL == "" {
+		verifyURL = verifyURLs[cfg.Provider]
+	}
+	v := &siteVerifier{url: verifyURL, secret: cfg.SecretKey, client: &http.Client{Timeout: cfg.Timeout}}
+	// hCaptcha's enterprise score grows with risk, so the minimum only applies to reCAPTCHA
+	if cfg.Provider == config.CaptchaRecaptcha {
+		v.minScore = cfg.MinScore
+	}
+	return v
+}
+
+type siteVerifier struct {
+	url      string
+	secret   string
+	minScore float64
+	client   *http.Client
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score"` // reCAPTCHA v3 only
+	ErrorCodes []string `json:"error-codes"`
+}
+
+func (v *siteVerifier) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("CAPTCHA provider unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("CAPTCHA provider returned status %d", resp.StatusCode)
+	}
+
+	var body siteVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, fmt.Errorf("invalid CAPTCHA provider response: %w", err)
+	}
+	if !body.Success {
+		// A rejected secret is a misconfiguration, not a failed challenge
+		for _, code := range body.ErrorCodes {
+			if strings.HasSuffix(code, "-secret") || strings.HasSuffix(code, "-secret-key") {
+				return false, fmt.Errorf("CAPTCHA provider rejected the secret key: %s", code)
+			}
+		}
+		return false, nil
+	}
+	if body.Score != nil && *body.Score < v.minScore {
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+// AccountDeletionConfig configures self-service account deletion. Domains may override the grace
+// period in their account_deletion settings.
+type AccountDeletionConfig struct {
+	GracePeriod   time.Duration
+	SweepInterval time.Duration // 0 disables the sweep that deletes accounts once their grace period ends
+}
+
+func NewAccountDeletionConfig() (*AccountDeletionConfig, error) {
+	var env envReader
+	cfg := &AccountDeletionConfig{
+		GracePeriod:   env.Duration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+		SweepInterval: env.Duration("ACCOUNT_DELETION_SWEEP_INTERVAL", time.Hour),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
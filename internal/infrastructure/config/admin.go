@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// AdminAuthConfig controls who may call the admin API. When enforced, every admin route needs the
+// bearer token of a domain admin, who manages only their own domain, or of a system admin, who
+// manages every domain; the operator token counts as a system admin. It is on unless
+// ADMIN_AUTHORIZATION=false, which opens the admin API to every caller.
+type AdminAuthConfig struct {
+	Enforced bool
+	// SystemDomainID is the domain whose holders of iam:system-admin are system admins; Nil leaves
+	// only the operator token
+	SystemDomainID uuid.UUID
+}
+
+func NewAdminAuthConfig() (*AdminAuthConfig, error) {
+	cfg := &AdminAuthConfig{Enforced: getEnv("ADMIN_AUTHORIZATION", "true") != "false"}
+	if raw := getEnv("ADMIN_SYSTEM_DOMAIN_ID", ""); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("ADMIN_SYSTEM_DOMAIN_ID must be a UUID: %w", err)
+		}
+		cfg.SystemDomainID = id
+	}
+	return cfg, nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// APIConfig configures the unversioned paths the API was served at before /api/v1. They keep
+// working, with Deprecation and Sunset headers, until LegacySunset and answer 410 Gone after it.
+type APIConfig struct {
+	LegacyRoutes       bool // false stops serving the unversioned paths at all
+	LegacyDeprecatedAt time.Time
+	LegacySunset       time.Time
+}
+
+func NewAPIConfig() (*APIConfig, error) {
+	cfg := &APIConfig{LegacyRoutes: getEnv("API_LEGACY_ROUTES", "true") == "true"}
+	dates := []struct {
+		target *time.Time
+		key    string
+		def    string
+	}{
+		{&cfg.LegacyDeprecatedAt, "API_LEGACY_DEPRECATED_AT", "2026-10-16"},
+		{&cfg.LegacySunset, "API_LEGACY_SUNSET", "2027-04-30"},
+	}
+	for _, date := range dates {
+		parsed, err := time.Parse(time.DateOnly, getEnv(date.key, date.def))
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD): %w", date.key, err)
+		}
+		*date.target = parsed
+	}
+	if cfg.LegacySunset.Before(cfg.LegacyDeprecatedAt) {
+		return nil, fmt.Errorf("API_LEGACY_SUNSET must not be before API_LEGACY_DEPRECATED_AT")
+	}
+	return cfg, nil
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// AppConfig is every setting of the server, loaded once at startup. Each field is read from the
+// environment variables documented in .env.example; CONFIG_FILE may name a YAML file setting the
+// same variables, e.g. "JWT_SECRET: ..." or "DB_SHARDS: [eu, us]", for those the environment
+// doesn't set.
+type AppConfig struct {
+	Server            *ServerConfig
+	CORS              *CORSConfig
+	Startup           *StartupConfig
+	Database          *DatabaseConfig
+	ShardDSNs         map[string]string
+	ReplicaDSNs       map[string]string
+	Tracing           *TracingConfig
+	JWT               *JWTConfig
+	API               *APIConfig
+	AdminAuth         *AdminAuthConfig
+	Operator          *OperatorConfig
+	Captcha           *CaptchaConfig
+	RequestRateLimits *RequestRateLimitConfig
+	APIKeyRateLimits  *RateLimitConfig
+	Cache             *CacheConfig
+	TokenRevocation   *TokenRevocationConfig
+	Introspection     *IntrospectionConfig
+	Mail              *MailConfig
+	Passwordless      *PasswordlessConfig
+	Invitations       *InvitationConfig
+	HostedSession     *HostedSessionConfig
+	TrustedDevices    *TrustedDeviceConfig
+	BreakGlass        *BreakGlassConfig
+	Impersonation     *ImpersonationConfig
+	LoginRisk         *LoginRiskConfig
+	DecisionLog       *DecisionLogConfig
+	Broker            *BrokerConfig
+	Telemetry         *TelemetryConfig
+	Storage           *StorageConfig
+	Avatars           *AvatarConfig
+	Jobs              *JobsConfig
+	UserExpiry        *UserExpiryConfig
+	AccountDeletion   *AccountDeletionConfig
+	DomainDeletion    *DomainDeletionConfig
+	IntegrationHealth *IntegrationHealthConfig
+	Health            *HealthConfig
+	Webhooks          *WebhookConfig
+	Outbound          *OutboundConfig
+	Idempotency       *IdempotencyConfig
+	FaultInjection    *FaultInjectionConfig
+}
+
+// LoadAppConfig reads the CONFIG_FILE, if any, and every setting. Invalid settings are all
+// reported in one error, one per line, rather than one per restart.
+func LoadAppConfig() (*AppConfig, error) {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadConfigFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	check := func(section string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", section, err))
+		}
+	}
+
+	cfg := &AppConfig{
+		Tracing:     NewTracingConfig(),
+		JWT:         NewJWTConfig(),
+		Operator:    NewOperatorConfig(),
+		DecisionLog: NewDecisionLogConfig(),
+	}
+	var err error
+	cfg.Server, err = NewServerConfig()
+	check("server", err)
+	cfg.Startup, err = NewStartupConfig()
+	check("startup", err)
+	cfg.Database, err = NewDatabaseConfig()
+	check("database", err)
+	cfg.ShardDSNs, err = NewShardDSNs()
+	check("shards", err)
+	cfg.ReplicaDSNs = NewReplicaDSNs(cfg.ShardDSNs)
+	check("JWT", cfg.JWT.Validate())
+	cfg.CORS, err = NewCORSConfig()
+	check("CORS", err)
+	cfg.API, err = NewAPIConfig()
+	check("API", err)
+	cfg.AdminAuth, err = NewAdminAuthConfig()
+	check("admin authorization", err)
+	cfg.Captcha, err = NewCaptchaConfig()
+	check("CAPTCHA", err)
+	cfg.RequestRateLimits, err = NewRequestRateLimitConfig()
+	check("rate limits", err)
+	cfg.Cache, err = NewCacheConfig()
+	check("cache", err)
+	cfg.TokenRevocation, err = NewTokenRevocationConfig()
+	check("token revocation", err)
+	cfg.Mail, err = NewMailConfig()
+	check("mail", err)
+	cfg.Broker, err = NewBrokerConfig()
+	check("broker", err)
+	cfg.Telemetry, err = NewTelemetryConfig()
+	check("telemetry", err)
+	cfg.Storage, err = NewStorageConfig()
+	check("storage", err)
+	cfg.Outbound, err = NewOutboundConfig()
+	check("outbound connections", err)
+	cfg.Jobs, err = NewJobsConfig()
+	check("jobs", err)
+	cfg.APIKeyRateLimits, err = NewRateLimitConfig()
+	check("API key rate limits", err)
+	cfg.Introspection, err = NewIntrospectionConfig()
+	check("introspection", err)
+	cfg.Passwordless, err = NewPasswordlessConfig()
+	check("passwordless login", err)
+	cfg.Invitations, err = NewInvitationConfig()
+	check("invitations", err)
+	cfg.HostedSession, err = NewHostedSessionConfig()
+	check("hosted sessions", err)
+	cfg.TrustedDevices, err = NewTrustedDeviceConfig()
+	check("trusted devices", err)
+	cfg.BreakGlass, err = NewBreakGlassConfig()
+	check("break-glass access", err)
+	cfg.Impersonation, err = NewImpersonationConfig()
+	check("impersonation", err)
+	cfg.LoginRisk, err = NewLoginRiskConfig()
+	check("login risk", err)
+	cfg.Avatars, err = NewAvatarConfig()
+	check("avatars", err)
+	cfg.UserExpiry, err = NewUserExpiryConfig()
+	check("user expiry", err)
+	cfg.AccountDeletion, err = NewAccountDeletionConfig()
+	check("account deletion", err)
+	cfg.DomainDeletion, err = NewDomainDeletionConfig()
+	check("domain deletion", err)
+	cfg.IntegrationHealth, err = NewIntegrationHealthConfig()
+	check("integration health", err)
+	cfg.Health, err = NewHealthConfig()
+	check("health probes", err)
+	cfg.Webhooks, err = NewWebhookConfig()
+	check("webhooks", err)
+	cfg.Idempotency, err = NewIdempotencyConfig()
+	check("idempotency", err)
+	cfg.FaultInjection, err = NewFaultInjectionConfig()
+	check("fault injection", err)
+
+	if cfg.AdminAuth != nil && cfg.AdminAuth.Enforced && cfg.AdminAuth.SystemDomainID == uuid.Nil && cfg.Operator.Token == "" {
+		errs = append(errs, errors.New("admin authorization: set ADMIN_SYSTEM_DOMAIN_ID or PLATFORM_OPERATOR_TOKEN, or no one can manage the domains themselves; ADMIN_AUTHORIZATION=false turns admin authorization off"))
+	}
+	if cfg.Database != nil && cfg.Database.Driver == DriverSQLite && (len(cfg.ShardDSNs) > 0 || len(cfg.ReplicaDSNs) > 0) {
+		errs = append(errs, errors.New("database: DB_DRIVER=sqlite has no residency shards or read replicas; unset DB_SHARDS and the replica DSNs"))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return cfg, nil
+}
+
+// loadConfigFile sets the variables of a YAML file that aren't set in the environment, which
+// takes precedence. Lists are joined with commas, as the list variables expect.
+func loadConfigFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("CONFIG_FILE: %w", err)
+	}
+	var values map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, err := configFileValue(values[key])
+		if err != nil {
+			return fmt.Errorf("CONFIG_FILE %s: %s %w", path, key, err)
+		}
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("CONFIG_FILE %s: %s: %w", path, key, err)
+		}
+	}
+	return nil
+}
+
+// configFileValue returns a scalar as written, so dates and durations reach the parsers of the
+// settings unchanged, and a list of scalars joined with commas.
+func configFileValue(node yaml.Node) (string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return "", nil
+		}
+		return node.Value, nil
+	case yaml.SequenceNode:
+		items := make([]string, len(node.Content))
+		for i, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return "", errors.New("must be a value or a list of values")
+			}
+			items[i] = item.Value
+		}
+		return strings.Join(items, ","), nil
+	}
+	return "", errors.New("must be a value or a list of values")
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadAppConfigMalformedNumbers(t *testing.T) {
+	malformed := map[string]string{
+		"SERVER_MAX_BODY_BYTES": "1MB",
+		"JOB_LEASE":             "5",
+		"WEBHOOK_MAX_ATTEMPTS":  "-3",
+		"HOSTED_SESSION_TTL":    "-1h",
+	}
+	for key, value := range malformed {
+		t.Setenv(key, value)
+	}
+	// Settings that may be 0 still are
+	t.Setenv("JOB_WORKERS", "0")
+	t.Setenv("WEBHOOK_DELIVERY_INTERVAL", "0")
+
+	_, err := LoadAppConfig()
+	if err == nil {
+		t.Fatal("LoadAppConfig() succeeded with malformed settings")
+	}
+	for key, value := range malformed {
+		if !strings.Contains(err.Error(), key+` must be`) || !strings.Contains(err.Error(), `"`+value+`"`) {
+			t.Errorf("error doesn't report %s=%s:\n%v", key, value, err)
+		}
+	}
+	for _, key := range []string{"JOB_WORKERS", "WEBHOOK_DELIVERY_INTERVAL"} {
+		if strings.Contains(err.Error(), key) {
+			t.Errorf("error reports %s=0:\n%v", key, err)
+		}
+	}
+}
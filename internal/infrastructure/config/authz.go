@@ -0,0 +1,38 @@
+package config
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+// DecisionLogConfig controls which /authz/check decisions are persisted for simulation and audit.
+type DecisionLogConfig struct {
+	Enabled         bool
+	SampleRate      float64
+	AlwaysLogDenied bool
+}
+
+func NewDecisionLogConfig() *DecisionLogConfig {
+	sampleRate, err := strconv.ParseFloat(getEnv("AUTHZ_DECISION_LOG_SAMPLE_RATE", "1"), 64)
+	if err != nil || sampleRate < 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+
+	return &DecisionLogConfig{
+		Enabled:         getEnv("AUTHZ_DECISION_LOG_ENABLED", "true") == "true",
+		SampleRate:      sampleRate,
+		AlwaysLogDenied: getEnv("AUTHZ_DECISION_LOG_ALWAYS_DENIED", "true") == "true",
+	}
+}
+
+// ShouldLog decides whether a single decision is recorded. Denials bypass sampling when
+// AlwaysLogDenied is set so audits never miss a refused request.
+func (c *DecisionLogConfig) ShouldLog(allowed bool) bool {
+	if !c.Enabled {
+		return false
+	}
+	if !allowed && c.AlwaysLogDenied {
+		return true
+	}
+	return c.SampleRate >= 1 || rand.Float64() < c.SampleRate
+}
@@ -0,0 +1,51 @@
+package config
+
+import "time"
+
+// OperatorConfig holds the credential for platform operator endpoints. Without a token those
+// endpoints reject every request.
+type OperatorConfig struct {
+	Token string
+}
+
+func NewOperatorConfig() *OperatorConfig {
+	return &OperatorConfig{
+		Token: getEnv("PLATFORM_OPERATOR_TOKEN", ""),
+	}
+}
+
+// BreakGlassConfig controls emergency access accounts.
+type BreakGlassConfig struct {
+	SessionTTL  time.Duration
+	AlertEmails []string // notified of every sign-in attempt, in addition to the account's own email
+}
+
+func NewBreakGlassConfig() (*BreakGlassConfig, error) {
+	var env envReader
+	cfg := &BreakGlassConfig{
+		SessionTTL:  env.Duration("BREAK_GLASS_SESSION_TTL", time.Hour),
+		AlertEmails: getEnvList("BREAK_GLASS_ALERT_EMAILS"),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ImpersonationConfig controls the tokens support engineers get to act as a user.
+type ImpersonationConfig struct {
+	// TokenTTL is the lifetime of an impersonation token; the domain's shorter access token
+	// lifetime wins
+	TokenTTL time.Duration
+}
+
+func NewImpersonationConfig() (*ImpersonationConfig, error) {
+	var env envReader
+	cfg := &ImpersonationConfig{
+		TokenTTL: env.Duration("IMPERSONATION_TOKEN_TTL", 15*time.Minute),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
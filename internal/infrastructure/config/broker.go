@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"backend/internal/infrastructure/broker"
+)
+
+// BrokerConfig configures publishing domain events to a message broker. Events are queued in the
+// event outbox with the change that caused them and relayed every RelayInterval; failed publishes
+// are retried after RetryBackoff, doubled after each further failure up to MaxBackoff.
+type BrokerConfig struct {
+	Broker            string // "none", "kafka" or "nats"
+	KafkaBrokers      []string
+	KafkaTopic        string
+	NATSURL           string
+	NATSSubjectPrefix string
+	RelayInterval     time.Duration
+	BatchSize         int // outbox entries claimed per database and interval
+	PublishTimeout    time.Duration
+	RetryBackoff      time.Duration
+	MaxBackoff        time.Duration
+}
+
+func NewBrokerConfig() (*BrokerConfig, error) {
+	var env envReader
+	cfg := &BrokerConfig{
+		Broker:            getEnv("BROKER", "none"),
+		KafkaBrokers:      getEnvList("KAFKA_BROKERS"),
+		KafkaTopic:        getEnv("KAFKA_TOPIC", "iam.events"),
+		NATSURL:           getEnv("NATS_URL", "nats://localhost:4222"),
+		NATSSubjectPrefix: getEnv("NATS_SUBJECT_PREFIX", "iam.events"),
+		RelayInterval:     env.Duration("BROKER_RELAY_INTERVAL", time.Second),
+		BatchSize:         max(env.Int("BROKER_BATCH_SIZE", 100), 1),
+		PublishTimeout:    env.Duration("BROKER_PUBLISH_TIMEOUT", 10*time.Second),
+		RetryBackoff:      env.Duration("BROKER_RETRY_BACKOFF", 5*time.Second),
+		MaxBackoff:        env.Duration("BROKER_MAX_BACKOFF", 5*time.Minute),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	switch cfg.Broker {
+	case "none", "nats":
+	case "kafka":
+		if len(cfg.KafkaBrokers) == 0 {
+			return nil, fmt.Errorf("KAFKA_BROKERS is required when BROKER is kafka")
+		}
+	default:
+		return nil, fmt.Errorf("BROKER must be none, kafka or nats, got %q", cfg.Broker)
+	}
+	return cfg, nil
+}
+
+// Enabled reports whether events are queued in the outbox for a broker.
+func (c *BrokerConfig) Enabled() bool {
+	return c.Broker != "none"
+}
+
+// OpenPublisher returns the configured publisher, or nil when no broker is configured. Neither
+// publisher needs the broker to be reachable yet; events wait in the outbox until it is.
+func (c *BrokerConfig) OpenPublisher() (broker.Publisher, error) {
+	switch c.Broker {
+	case "kafka":
+		return broker.NewKafkaPublisher(c.KafkaBrokers, c.KafkaTopic), nil
+	case "nats":
+		publisher, err := broker.NewNATSPublisher(c.NATSURL, c.NATSSubjectPrefix)
+		if err != nil {
+			return nil, err
+		}
+		return publisher, nil
+	}
+	return nil, nil
+}
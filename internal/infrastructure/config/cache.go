@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"backend/internal/infrastructure/cache"
+)
+
+// CacheConfig configures the cache in front of role and domain lookups.
+type CacheConfig struct {
+	Store    string // "memory", "redis" or "off"
+	RedisURL string
+	TTL      time.Duration
+}
+
+func NewCacheConfig() (*CacheConfig, error) {
+	var env envReader
+	cfg := &CacheConfig{
+		Store:    getEnv("CACHE_STORE", "memory"),
+		RedisURL: getEnv("REDIS_URL", "redis://localhost:6379/0"),
+		TTL:      env.Duration("CACHE_TTL", 30*time.Second),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	switch cfg.Store {
+	case "memory", "redis", "off":
+	default:
+		return nil, fmt.Errorf("CACHE_STORE must be memory, redis or off, got %q", cfg.Store)
+	}
+	return cfg, nil
+}
+
+// OpenCache returns the configured cache, connecting to and pinging Redis when selected, or nil
+// when caching is off.
+func (c *CacheConfig) OpenCache() (cache.Cache, error) {
+	switch c.Store {
+	case "off":
+		return nil, nil
+	case "redis":
+		client, err := openRedis(c.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		return cache.NewRedisCache(client, "cache:"), nil
+	}
+	return cache.NewMemoryCache(), nil
+}
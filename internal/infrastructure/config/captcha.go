@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CAPTCHA providers. All three verify a widget's token through the same siteverify API.
+const (
+	CaptchaRecaptcha = "recaptcha"
+	CaptchaHCaptcha  = "hcaptcha"
+	CaptchaTurnstile = "turnstile"
+)
+
+// CaptchaConfig configures the provider that verifies the captcha_token of a login the domain's
+// risk policy challenged. Without a provider a CAPTCHA challenge can't be answered.
+type CaptchaConfig struct {
+	Provider  string // empty disables CAPTCHA verification
+	SiteKey   string // public key client apps render the widget with
+	SecretKey string
+	VerifyURL string // overrides the provider's siteverify endpoint
+	// MinScore is the lowest reCAPTCHA v3 score accepted; 0 accepts any passed token
+	MinScore float64
+	Timeout  time.Duration
+}
+
+func NewCaptchaConfig() (*CaptchaConfig, error) {
+	var env envReader
+	cfg := &CaptchaConfig{
+		Provider:  getEnv("CAPTCHA_PROVIDER", ""),
+		SiteKey:   getEnv("CAPTCHA_SITE_KEY", ""),
+		SecretKey: getEnv("CAPTCHA_SECRET_KEY", ""),
+		VerifyURL: getEnv("CAPTCHA_VERIFY_URL", ""),
+		Timeout:   env.Duration("CAPTCHA_TIMEOUT", 5*time.Second),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	if value := getEnv("CAPTCHA_MIN_SCORE", ""); value != "" {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil || score < 0 || score > 1 {
+			return nil, fmt.Errorf("CAPTCHA_MIN_SCORE must be between 0 and 1, got %q", value)
+		}
+		cfg.MinScore = score
+	}
+
+	switch cfg.Provider {
+	case "":
+		return cfg, nil
+	case CaptchaRecaptcha, CaptchaHCaptcha, CaptchaTurnstile:
+	default:
+		return nil, fmt.Errorf("CAPTCHA_PROVIDER must be recaptcha, hcaptcha or turnstile, got %q", cfg.Provider)
+	}
+	if cfg.SecretKey == "" {
+		return nil, fmt.Errorf("CAPTCHA_PROVIDER=%s requires CAPTCHA_SECRET_KEY", cfg.Provider)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,20 @@
+package config
+
+import "strings"
+
+// CLIConfig configures how iamctl reaches the HTTP API. Admin routes are called with the operator
+// token when it is set, otherwise with Token, an admin's bearer token; with neither they only
+// answer while ADMIN_AUTHORIZATION=false.
+type CLIConfig struct {
+	APIURL        string
+	Token         string
+	OperatorToken string
+}
+
+func NewCLIConfig() *CLIConfig {
+	return &CLIConfig{
+		APIURL:        strings.TrimRight(getEnv("IAMCTL_API_URL", "http://localhost:8080"), "/"),
+		Token:         getEnv("IAMCTL_TOKEN", ""),
+		OperatorToken: NewOperatorConfig().Token,
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CORSConfig configures which browser origins may call the API.
+type CORSConfig struct {
+	// AllowedOrigins are origins such as "https://app.example.com"; "*" allows any origin and
+	// "https://*.example.com" any subdomain
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool // lets browsers send cookies and read responses of credentialed requests
+	MaxAge           time.Duration
+	// DomainOrigins also allows https://<hostname> for the hostname of every registered domain
+	DomainOrigins bool
+}
+
+func NewCORSConfig() (*CORSConfig, error) {
+	domainOrigins := getEnv("CORS_DOMAIN_ORIGINS", "false") == "true"
+	// Any origin is allowed by default, unless the domains' hostnames are
+	defaultOrigins := []string{"*"}
+	if domainOrigins {
+		defaultOrigins = nil
+	}
+	var env envReader
+	cfg := &CORSConfig{
+		AllowedOrigins:   getEnvListDefault("CORS_ALLOWED_ORIGINS", defaultOrigins),
+		AllowedMethods:   getEnvListDefault("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+		AllowedHeaders:   getEnvListDefault("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Content-Length", "Accept", "Accept-Encoding", "Authorization", "Cache-Control", "X-CSRF-Token", "X-Requested-With", "X-NRM-DID", "X-NRM-Domain", "X-API-Key", "X-Operator-Token", "X-Consistency-Token", "Idempotency-Key"}),
+		AllowCredentials: getEnv("CORS_ALLOW_CREDENTIALS", "false") == "true",
+		MaxAge:           env.Duration("CORS_MAX_AGE", 12*time.Hour),
+		DomainOrigins:    domainOrigins,
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			if len(cfg.AllowedOrigins) > 1 {
+				return nil, errors.New("CORS_ALLOWED_ORIGINS can't list other origins next to *")
+			}
+			// Browsers ignore credentials for a wildcard origin, and reflecting any origin
+			// instead would let every site act with the user's cookies
+			if cfg.AllowCredentials {
+				return nil, errors.New("CORS_ALLOW_CREDENTIALS=true requires CORS_ALLOWED_ORIGINS to list the origins instead of *")
+			}
+			if cfg.DomainOrigins {
+				return nil, errors.New("CORS_DOMAIN_ORIGINS=true has no effect while CORS_ALLOWED_ORIGINS is *")
+			}
+			continue
+		}
+		if !strings.HasPrefix(origin, "https://") && !strings.HasPrefix(origin, "http://") {
+			return nil, fmt.Errorf("CORS_ALLOWED_ORIGINS entry %q must start with https:// or http://", origin)
+		}
+		if strings.TrimRight(origin, "/") != origin {
+			return nil, fmt.Errorf("CORS_ALLOWED_ORIGINS entry %q must not end with /", origin)
+		}
+	}
+	if len(cfg.AllowedOrigins) == 0 && !cfg.DomainOrigins {
+		return nil, errors.New("CORS_ALLOWED_ORIGINS lists no origin and CORS_DOMAIN_ORIGINS is false, so no browser could call the API")
+	}
+	return cfg, nil
+}
+
+// AllowsAnyOrigin reports whether every origin is allowed.
+func (c *CORSConfig) AllowsAnyOrigin() bool {
+	return len(c.AllowedOrigins) == 1 && c.AllowedOrigins[0] == "*"
+}
+
+// getEnvListDefault is getEnvList with a default for an unset or blank variable.
+func getEnvListDefault(key string, defaultVal []string) []string {
+	if values := getEnvList(key); len(values) > 0 {
+		return values
+	}
+	return defaultVal
+}
@@ -2,30 +2,92 @@ package config
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
+
+	"backend/internal/infrastructure/sqlitedb"
 
 	_ "github.com/lib/pq"
 )
 
+// The drivers DB_DRIVER selects. SQLite runs the same repositories (see repositories.Dialect), for
+// local development and tests without a Postgres server.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type DatabaseConfig struct {
+	// Driver is DriverPostgres or DriverSQLite
+	Driver   string
 	Host     string
 	Port     string
 	User     string
 	Password string
 	DBName   string
 	SSLMode  string
+	// RowLevelSecurity sets app.domain_id on every tenant-scoped statement and runs the others as
+	// the bypass role, for databases set up with migrations/optional/row_level_security.sql
+	RowLevelSecurity bool
+	// SQLitePath is the SQLite database file, by default sqlitedb.Memory, a new in-memory database.
+	// SQLiteSchema creates the tables of a new one; Postgres databases are migrated.
+	SQLitePath   string
+	SQLiteSchema string
+	// Pool applies to the primary database and to every shard and replica
+	Pool PoolConfig
+}
+
+// PoolConfig limits each connection pool; database/sql leaves open connections unbounded.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration // 0 keeps connections open indefinitely
+	ConnMaxIdleTime time.Duration
+}
+
+func (p PoolConfig) apply(db *sql.DB) {
+	db.SetMaxOpenConns(p.MaxOpenConns)
+	db.SetMaxIdleConns(p.MaxIdleConns)
+	db.SetConnMaxLifetime(p.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(p.ConnMaxIdleTime)
 }
 
-func NewDatabaseConfig() *DatabaseConfig {
-	return &DatabaseConfig{
+func NewDatabaseConfig() (*DatabaseConfig, error) {
+	driver := getEnv("DB_DRIVER", DriverPostgres)
+	if driver != DriverPostgres && driver != DriverSQLite {
+		return nil, fmt.Errorf("DB_DRIVER must be %s or %s, got %q", DriverPostgres, DriverSQLite, driver)
+	}
+
+	var env envReader
+	cfg := &DatabaseConfig{
+		Driver:   driver,
 		Host:     getEnv("DB_HOST", "localhost"),
 		Port:     getEnv("DB_PORT", "5432"),
 		User:     getEnv("DB_USER", "postgres"),
 		Password: getEnv("DB_PASSWORD", ""),
 		DBName:   getEnv("DB_NAME", "mydb"),
 		SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+		RowLevelSecurity: getEnv("DB_ROW_LEVEL_SECURITY", "false") == "true",
+		SQLitePath:       getEnv("DB_SQLITE_PATH", sqlitedb.Memory),
+		SQLiteSchema:     getEnv("DB_SQLITE_SCHEMA", "migrations/sqlite/schema.sql"),
+		Pool: PoolConfig{
+			MaxOpenConns:    env.Int("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    env.Int("DB_MAX_IDLE_CONNS", 10),
+			ConnMaxLifetime: env.Duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+			ConnMaxIdleTime: env.Duration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+		},
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
 	}
+	if cfg.Driver == DriverSQLite && cfg.RowLevelSecurity {
+		return nil, errors.New("DB_ROW_LEVEL_SECURITY needs Postgres; SQLite has no row-level security policies")
+	}
+	return cfg, nil
 }
 
 func (c *DatabaseConfig) ConnectionString() string {
@@ -34,19 +96,109 @@ func (c *DatabaseConfig) ConnectionString() string {
 }
 
 func (c *DatabaseConfig) OpenDB() (*sql.DB, error) {
-	db, err := sql.Open("postgres", c.ConnectionString())
+	var db *sql.DB
+	var err error
+	if c.Driver == DriverSQLite {
+		db, err = sqlitedb.Open(c.SQLitePath)
+	} else {
+		db, err = openPostgres(c.ConnectionString(), c.RowLevelSecurity)
+	}
 	if err != nil {
 		return nil, err
 	}
+	c.Pool.apply(db)
 	if err = db.Ping(); err != nil {
+		db.Close()
 		return nil, err
 	}
+	if c.Driver == DriverSQLite {
+		if err = sqlitedb.CreateSchema(db, c.SQLiteSchema); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
 	return db, nil
 }
 
+// NewShardDSNs reads the residency shards listed in DB_SHARDS (e.g. "eu,us") and their
+// connection strings from DB_SHARD_<REGION>_DSN. Tenants without a matching shard stay on the primary database.
+func NewShardDSNs() (map[string]string, error) {
+	dsns := make(map[string]string)
+	for _, region := range strings.Split(getEnv("DB_SHARDS", ""), ",") {
+		region = strings.ToLower(strings.TrimSpace(region))
+		if region == "" {
+			continue
+		}
+		key := "DB_SHARD_" + strings.ToUpper(region) + "_DSN"
+		dsn := getEnv(key, "")
+		if dsn == "" {
+			return nil, fmt.Errorf("%s is required for residency shard %q", key, region)
+		}
+		dsns[region] = dsn
+	}
+	return dsns, nil
+}
+
+// NewReplicaDSNs reads optional read replicas, keyed by residency: DB_REPLICA_DSN for the
+// primary database ("default") and DB_SHARD_<REGION>_REPLICA_DSN for each residency shard.
+func NewReplicaDSNs(shardDSNs map[string]string) map[string]string {
+	dsns := make(map[string]string)
+	if dsn := getEnv("DB_REPLICA_DSN", ""); dsn != "" {
+		dsns["default"] = dsn
+	}
+	for region := range shardDSNs {
+		if dsn := getEnv("DB_SHARD_"+strings.ToUpper(region)+"_REPLICA_DSN", ""); dsn != "" {
+			dsns[region] = dsn
+		}
+	}
+	return dsns
+}
+
+// OpenShards opens and pings one connection pool per residency shard.
+func (c *DatabaseConfig) OpenShards(dsns map[string]string) (map[string]*sql.DB, error) {
+	return c.openPools("shard", dsns)
+}
+
+// OpenReplicas opens and pings one connection pool per read replica.
+func (c *DatabaseConfig) OpenReplicas(dsns map[string]string) (map[string]*sql.DB, error) {
+	return c.openPools("replica", dsns)
+}
+
+func (c *DatabaseConfig) openPools(kind string, dsns map[string]string) (map[string]*sql.DB, error) {
+	pools := make(map[string]*sql.DB, len(dsns))
+	for region, dsn := range dsns {
+		db, err := openPostgres(dsn, c.RowLevelSecurity)
+		if err == nil {
+			c.Pool.apply(db)
+			if err = db.Ping(); err != nil {
+				db.Close()
+			}
+		}
+		if err != nil {
+			for _, opened := range pools {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("%s %s: %w", kind, region, err)
+		}
+		pools[region] = db
+	}
+	return pools, nil
+}
+
 func getEnv(key, defaultVal string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultVal
 }
+
+// getEnvList splits a comma-separated variable, dropping blank entries.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
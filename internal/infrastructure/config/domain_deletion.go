@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+// DomainDeletionConfig configures the worker deleting domains queued for background deletion.
+// Every instance may run it; a deletion is held by one worker at a time.
+type DomainDeletionConfig struct {
+	WorkerInterval time.Duration // 0 disables the worker on this instance
+	BatchSize      int           // rows deleted per statement
+	// BatchPause is waited between batches so a large deletion doesn't starve the shard
+	BatchPause time.Duration
+}
+
+func NewDomainDeletionConfig() (*DomainDeletionConfig, error) {
+	var env envReader
+	cfg := &DomainDeletionConfig{
+		WorkerInterval: env.Duration("DOMAIN_DELETION_INTERVAL", 10*time.Second),
+		BatchSize:      max(env.Int("DOMAIN_DELETION_BATCH_SIZE", 500), 1),
+		BatchPause:     env.Duration("DOMAIN_DELETION_BATCH_PAUSE", 100*time.Millisecond),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
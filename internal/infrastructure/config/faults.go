@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+// FaultInjectionConfig gates the admin endpoints that inject artificial failures. Never enable it
+// in production.
+type FaultInjectionConfig struct {
+	Enabled     bool
+	MaxDuration time.Duration // longest time faults may stay active; also the default
+}
+
+func NewFaultInjectionConfig() (*FaultInjectionConfig, error) {
+	var env envReader
+	cfg := &FaultInjectionConfig{
+		Enabled:     getEnv("FAULT_INJECTION_ENABLED", "false") == "true",
+		MaxDuration: env.Duration("FAULT_INJECTION_MAX_DURATION", time.Hour),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+// HealthConfig configures the dependency probes behind /readyz and the Degradation header.
+type HealthConfig struct {
+	ProbeInterval time.Duration // 0 disables background probes; /readyz still probes on request
+	ProbeTimeout  time.Duration
+}
+
+func NewHealthConfig() (*HealthConfig, error) {
+	var env envReader
+	cfg := &HealthConfig{
+		ProbeInterval: env.Duration("HEALTH_PROBE_INTERVAL", 15*time.Second),
+		ProbeTimeout:  env.Duration("HEALTH_PROBE_TIMEOUT", 2*time.Second),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
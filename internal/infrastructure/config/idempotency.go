@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+// IdempotencyConfig configures the Idempotency-Key support of create endpoints.
+type IdempotencyConfig struct {
+	TTL time.Duration // how long a key's response is replayed
+	// LockTimeout is how long a request holds its key before a retry may take it over, in case
+	// the instance handling it died
+	LockTimeout   time.Duration
+	SweepInterval time.Duration // how often expired keys are removed; 0 disables
+}
+
+func NewIdempotencyConfig() (*IdempotencyConfig, error) {
+	var env envReader
+	cfg := &IdempotencyConfig{
+		TTL:           env.Duration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		LockTimeout:   env.Duration("IDEMPOTENCY_LOCK_TIMEOUT", time.Minute),
+		SweepInterval: env.Duration("IDEMPOTENCY_SWEEP_INTERVAL", time.Hour),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
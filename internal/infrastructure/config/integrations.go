@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+// IntegrationHealthConfig configures the scheduled health checks of tenant integrations.
+type IntegrationHealthConfig struct {
+	CheckInterval  time.Duration // 0 disables the checks
+	AlertThreshold int           // consecutive failures before alerting
+	AlertEmails    []string
+}
+
+func NewIntegrationHealthConfig() (*IntegrationHealthConfig, error) {
+	var env envReader
+	cfg := &IntegrationHealthConfig{
+		CheckInterval:  env.Duration("INTEGRATION_HEALTH_CHECK_INTERVAL", 5*time.Minute),
+		AlertThreshold: env.Int("INTEGRATION_HEALTH_ALERT_THRESHOLD", 3),
+		AlertEmails:    getEnvList("INTEGRATION_HEALTH_ALERT_EMAILS"),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+// IntrospectionConfig configures caching of /auth/validate results in the lookup cache. Valid
+// results are served for up to CacheTTL, so a disabled account or revoked session may still pass
+// for that long; tokens revoked through /auth/revoke are dropped from the cache immediately.
+type IntrospectionConfig struct {
+	CacheTTL         time.Duration // 0 disables caching of valid tokens
+	NegativeCacheTTL time.Duration // 0 disables caching of rejected tokens
+}
+
+func NewIntrospectionConfig() (*IntrospectionConfig, error) {
+	var env envReader
+	cfg := &IntrospectionConfig{
+		CacheTTL:         env.Duration("INTROSPECTION_CACHE_TTL", 5*time.Second),
+		NegativeCacheTTL: env.Duration("INTROSPECTION_NEGATIVE_CACHE_TTL", 30*time.Second),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,31 @@
+package config
+
+import "time"
+
+// JobsConfig configures the background job queue and the worker pool of this instance. Every
+// instance may run workers; a job is held by one worker at a time. Job kinds register their own
+// retry policy; these are the defaults.
+type JobsConfig struct {
+	Workers      int           // 0 disables the workers on this instance; jobs still queue up
+	PollInterval time.Duration // how often idle workers check for due jobs
+	Lease        time.Duration // how long a run may take before another worker takes the job over
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+}
+
+func NewJobsConfig() (*JobsConfig, error) {
+	var env envReader
+	cfg := &JobsConfig{
+		Workers:      env.Count("JOB_WORKERS", 4),
+		PollInterval: max(env.Duration("JOB_POLL_INTERVAL", time.Second), 10*time.Millisecond),
+		Lease:        max(env.Duration("JOB_LEASE", 5*time.Minute), time.Second),
+		MaxAttempts:  max(env.Int("JOB_MAX_ATTEMPTS", 5), 1),
+		RetryBackoff: env.Duration("JOB_RETRY_BACKOFF", 30*time.Second),
+		MaxBackoff:   env.Duration("JOB_MAX_BACKOFF", time.Hour),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
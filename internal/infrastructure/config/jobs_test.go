@@ -0,0 +1,29 @@
+package config
+
+import "testing"
+
+func TestJobsConfigWorkers(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"", 4, false},
+		{"8", 8, false},
+		{"0", 0, false},
+		{"-1", 0, true},
+		{"four", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run("JOB_WORKERS="+tt.value, func(t *testing.T) {
+			t.Setenv("JOB_WORKERS", tt.value)
+			cfg, err := NewJobsConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewJobsConfig() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.Workers != tt.want {
+				t.Errorf("Workers = %d, want %d", cfg.Workers, tt.want)
+			}
+		})
+	}
+}
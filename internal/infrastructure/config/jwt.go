@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"backend/internal/infrastructure/signing"
+)
+
+// defaultJWTSecret is the public development secret JWT_ALLOW_DEFAULT_SECRET signs with, and the
+// value .env.example used to ship.
+const defaultJWTSecret = "your-secret-key"
+
+// JWTConfig selects the key access tokens are signed with. With a private key, tokens are signed
+// with RS256 or ES256 by key type and the public key is published at /.well-known/jwks.json;
+// without one they are signed with HS256 and Secret, which must then be set.
+type JWTConfig struct {
+	Secret string
+	// AllowDefaultSecret signs with the public development secret when JWT_SECRET is unset, for
+	// local development only
+	AllowDefaultSecret bool
+	PrivateKey         string // PEM; takes precedence over PrivateKeyFile
+	PrivateKeyFile     string
+	// PreviousKeyFiles hold replaced keys (private or public PEM) whose tokens are still accepted
+	// and published until they expire
+	PreviousKeyFiles []string
+}
+
+func NewJWTConfig() *JWTConfig {
+	cfg := &JWTConfig{
+		Secret:             getEnv("JWT_SECRET", ""),
+		AllowDefaultSecret: getEnv("JWT_ALLOW_DEFAULT_SECRET", "false") == "true",
+		// Literal \n sequences allow a PEM key on a single env line
+		PrivateKey:       strings.ReplaceAll(getEnv("JWT_PRIVATE_KEY", ""), `\n`, "\n"),
+		PrivateKeyFile:   getEnv("JWT_PRIVATE_KEY_FILE", ""),
+		PreviousKeyFiles: getEnvList("JWT_PREVIOUS_KEY_FILES"),
+	}
+	if cfg.Secret == "" && cfg.AllowDefaultSecret {
+		cfg.Secret = defaultJWTSecret
+	}
+	return cfg
+}
+
+// Validate reports a missing signing key: without a private key, JWT_SECRET must be set to a value
+// other than the public development secret unless JWT_ALLOW_DEFAULT_SECRET is.
+func (c *JWTConfig) Validate() error {
+	if c.KeySource() != "secret" {
+		return nil
+	}
+	switch {
+	case c.Secret == "":
+		return errors.New("JWT_SECRET is required unless JWT_PRIVATE_KEY or JWT_PRIVATE_KEY_FILE is set; JWT_ALLOW_DEFAULT_SECRET=true signs with a public development secret")
+	case c.Secret == defaultJWTSecret && !c.AllowDefaultSecret:
+		return fmt.Errorf("JWT_SECRET is the public development secret %q; set a secret of your own, or JWT_ALLOW_DEFAULT_SECRET=true for local development", defaultJWTSecret)
+	}
+	return nil
+}
+
+// KeySource reports where the signing key comes from: "env", "file" or "secret".
+func (c *JWTConfig) KeySource() string {
+	switch {
+	case c.PrivateKey != "":
+		return "env"
+	case c.PrivateKeyFile != "":
+		return "file"
+	}
+	return "secret"
+}
+
+// DefaultSecret reports whether tokens are signed with the built-in development secret.
+func (c *JWTConfig) DefaultSecret() bool {
+	return c.KeySource() == "secret" && c.Secret == defaultJWTSecret
+}
+
+// LoadKeys reads the signing key and the previous keys.
+func (c *JWTConfig) LoadKeys() (*signing.KeySet, error) {
+	var current *signing.Key
+	switch c.KeySource() {
+	case "env":
+		key, err := signing.ParsePrivateKey([]byte(c.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY: %w", err)
+		}
+		current = key
+	case "file":
+		key, err := readKeyFile(c.PrivateKeyFile, signing.ParsePrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		current = key
+	default:
+		current = signing.NewHMACKey([]byte(c.Secret))
+	}
+
+	previous := make([]*signing.Key, 0, len(c.PreviousKeyFiles))
+	for _, path := range c.PreviousKeyFiles {
+		key, err := readKeyFile(path, signing.ParseVerificationKey)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, key)
+	}
+	return signing.NewKeySet(current, previous...), nil
+}
+
+func readKeyFile(path string, parse func([]byte) (*signing.Key, error)) (*signing.Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
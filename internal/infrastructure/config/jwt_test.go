@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestJWTConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{"no secret", nil, true},
+		{"public development secret", map[string]string{"JWT_SECRET": defaultJWTSecret}, true},
+		{"secret of its own", map[string]string{"JWT_SECRET": "8f0c2b7e4d1a"}, false},
+		{"development secret allowed", map[string]string{"JWT_ALLOW_DEFAULT_SECRET": "true"}, false},
+		{"private key without a secret", map[string]string{"JWT_PRIVATE_KEY_FILE": "/etc/iam/jwt-signing.pem"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"JWT_SECRET", "JWT_ALLOW_DEFAULT_SECRET", "JWT_PRIVATE_KEY", "JWT_PRIVATE_KEY_FILE"} {
+				t.Setenv(key, tt.env[key])
+			}
+			err := NewJWTConfig().Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
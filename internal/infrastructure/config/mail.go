@@ -0,0 +1,133 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"backend/internal/infrastructure/secrets"
+)
+
+// Mail drivers. The log driver writes messages to the log instead of sending them, for local
+// development.
+const (
+	MailDriverSMTP     = "smtp"
+	MailDriverSendGrid = "sendgrid"
+	MailDriverSES      = "ses"
+	MailDriverLog      = "log"
+)
+
+// MailConfig configures outgoing email. MAIL_DRIVER picks how it is sent; it defaults to smtp when
+// SMTP_HOST is set and to log otherwise.
+type MailConfig struct {
+	Driver       string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+
+	SendGridAPIKey string
+	// SES is called through its v2 HTTP API, signed with these credentials
+	SESRegion          string
+	SESAccessKeyID     string
+	SESSecretAccessKey string
+	SESSessionToken    string
+	// APITimeout bounds each request to the SendGrid or SES API
+	APITimeout time.Duration
+	// Credentials encrypts the SMTP passwords of domains' own senders; nil without
+	// MAIL_CREDENTIALS_KEY, which leaves domains unable to store one
+	Credentials *secrets.Box
+}
+
+func NewMailConfig() (*MailConfig, error) {
+	var env envReader
+	cfg := &MailConfig{
+		SMTPHost:           getEnv("SMTP_HOST", ""),
+		SMTPPort:           getEnv("SMTP_PORT", "587"),
+		SMTPUsername:       getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:       getEnv("SMTP_PASSWORD", ""),
+		From:               getEnv("SMTP_FROM", "no-reply@nusarithm.local"),
+		SendGridAPIKey:     getEnv("SENDGRID_API_KEY", ""),
+		SESRegion:          getEnv("SES_REGION", ""),
+		SESAccessKeyID:     getEnv("SES_ACCESS_KEY_ID", ""),
+		SESSecretAccessKey: getEnv("SES_SECRET_ACCESS_KEY", ""),
+		SESSessionToken:    getEnv("SES_SESSION_TOKEN", ""),
+		APITimeout:         env.Duration("MAIL_API_TIMEOUT", 10*time.Second),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	if key := getEnv("MAIL_CREDENTIALS_KEY", ""); key != "" {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("MAIL_CREDENTIALS_KEY must be base64: %w", err)
+		}
+		if cfg.Credentials, err = secrets.NewBox(decoded); err != nil {
+			return nil, fmt.Errorf("MAIL_CREDENTIALS_KEY: %w", err)
+		}
+	}
+
+	cfg.Driver = MailDriverLog
+	if cfg.SMTPHost != "" {
+		cfg.Driver = MailDriverSMTP
+	}
+	cfg.Driver = getEnv("MAIL_DRIVER", cfg.Driver)
+
+	switch cfg.Driver {
+	case MailDriverLog:
+	case MailDriverSMTP:
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("MAIL_DRIVER=smtp requires SMTP_HOST")
+		}
+	case MailDriverSendGrid:
+		if cfg.SendGridAPIKey == "" {
+			return nil, fmt.Errorf("MAIL_DRIVER=sendgrid requires SENDGRID_API_KEY")
+		}
+	case MailDriverSES:
+		if cfg.SESRegion == "" || cfg.SESAccessKeyID == "" || cfg.SESSecretAccessKey == "" {
+			return nil, fmt.Errorf("MAIL_DRIVER=ses requires SES_REGION, SES_ACCESS_KEY_ID and SES_SECRET_ACCESS_KEY")
+		}
+	default:
+		return nil, fmt.Errorf("MAIL_DRIVER must be smtp, sendgrid, ses or log, got %q", cfg.Driver)
+	}
+	return cfg, nil
+}
+
+// PasswordlessConfig controls the one-time codes and magic links used by passwordless domains.
+type PasswordlessConfig struct {
+	CodeTTL     time.Duration
+	MaxAttempts int
+	LinkURL     string
+}
+
+func NewPasswordlessConfig() (*PasswordlessConfig, error) {
+	var env envReader
+	cfg := &PasswordlessConfig{
+		CodeTTL:     env.Duration("PASSWORDLESS_CODE_TTL", 10*time.Minute),
+		MaxAttempts: env.Int("PASSWORDLESS_MAX_ATTEMPTS", 5),
+		LinkURL:     getEnv("PASSWORDLESS_LINK_URL", "http://localhost:3000/auth/magic-link"),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// InvitationConfig controls the emailed links that let invited users join a domain.
+type InvitationConfig struct {
+	TTL     time.Duration
+	LinkURL string
+}
+
+func NewInvitationConfig() (*InvitationConfig, error) {
+	var env envReader
+	cfg := &InvitationConfig{
+		TTL:     env.Duration("INVITATION_TTL", 7*24*time.Hour),
+		LinkURL: getEnv("INVITATION_LINK_URL", "http://localhost:3000/auth/accept-invitation"),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
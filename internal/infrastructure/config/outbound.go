@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// OutboundConfig limits the addresses the server connects to on behalf of domains, such as
+// webhook endpoints and domain SMTP servers.
+type OutboundConfig struct {
+	// AllowedNetworks are the private, loopback or link-local ranges that may still be reached,
+	// such as an internal mail relay; every other such address is refused
+	AllowedNetworks []netip.Prefix
+}
+
+func NewOutboundConfig() (*OutboundConfig, error) {
+	cfg := &OutboundConfig{}
+	for _, network := range getEnvList("OUTBOUND_ALLOWED_NETWORKS") {
+		if !strings.Contains(network, "/") {
+			addr, err := netip.ParseAddr(network)
+			if err != nil {
+				return nil, fmt.Errorf("OUTBOUND_ALLOWED_NETWORKS entry %q must be an IP address or CIDR range", network)
+			}
+			cfg.AllowedNetworks = append(cfg.AllowedNetworks, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			return nil, fmt.Errorf("OUTBOUND_ALLOWED_NETWORKS entry %q must be an IP address or CIDR range", network)
+		}
+		cfg.AllowedNetworks = append(cfg.AllowedNetworks, prefix.Masked())
+	}
+	return cfg, nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"backend/internal/infrastructure/ratelimit"
+)
+
+// RateLimitConfig holds the defaults applied to API keys without an override.
+type RateLimitConfig struct {
+	DefaultPerMinute  int
+	DefaultDailyQuota int
+}
+
+func NewRateLimitConfig() (*RateLimitConfig, error) {
+	var env envReader
+	cfg := &RateLimitConfig{
+		DefaultPerMinute:  env.Int("API_KEY_RATE_LIMIT_PER_MINUTE", 60),
+		DefaultDailyQuota: env.Int("API_KEY_DAILY_QUOTA", 10000),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// RequestRateLimitConfig holds the token bucket limits applied to every request by client IP and
+// by authenticated user, and the stricter limits for credential and email-sending endpoints.
+type RequestRateLimitConfig struct {
+	Store    string // "memory" or "redis"
+	RedisURL string
+	// MemoryMaxKeys bounds the buckets held in memory, by the memory store or while Redis is down
+	MemoryMaxKeys int
+	PerIP         ratelimit.Limit
+	PerUser       ratelimit.Limit
+	Login         ratelimit.Limit // per IP on endpoints that check credentials
+	EmailSend     ratelimit.Limit // per IP on endpoints that email a code or link
+	// Introspection applies per API key, or per IP without one, on /auth/validate instead of the
+	// per-IP and per-user limits
+	Introspection ratelimit.Limit
+}
+
+func NewRequestRateLimitConfig() (*RequestRateLimitConfig, error) {
+	var env envReader
+	cfg := &RequestRateLimitConfig{
+		Store:    getEnv("RATE_LIMIT_STORE", "memory"),
+		RedisURL: getEnv("REDIS_URL", "redis://localhost:6379/0"),
+
+		MemoryMaxKeys: env.Int("RATE_LIMIT_MEMORY_MAX_KEYS", 100000),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	limits := []struct {
+		target *ratelimit.Limit
+		key    string
+		def    string
+	}{
+		{&cfg.PerIP, "RATE_LIMIT_PER_IP", "300/1m"},
+		{&cfg.PerUser, "RATE_LIMIT_PER_USER", "600/1m"},
+		{&cfg.Login, "RATE_LIMIT_LOGIN", "10/1m"},
+		{&cfg.EmailSend, "RATE_LIMIT_EMAIL_SEND", "5/15m"},
+		{&cfg.Introspection, "RATE_LIMIT_INTROSPECTION", "6000/1m"},
+	}
+	for _, limit := range limits {
+		parsed, err := ratelimit.ParseLimit(getEnv(limit.key, limit.def))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", limit.key, err)
+		}
+		*limit.target = parsed
+	}
+	if cfg.Store != "memory" && cfg.Store != "redis" {
+		return nil, fmt.Errorf("RATE_LIMIT_STORE must be memory or redis, got %q", cfg.Store)
+	}
+	return cfg, nil
+}
+
+// OpenStore returns the configured bucket store. The Redis store falls back to a memory store while
+// Redis is unreachable, including at startup, so only an invalid REDIS_URL is an error.
+func (c *RequestRateLimitConfig) OpenStore() (ratelimit.Store, error) {
+	memory := ratelimit.NewMemoryStore(c.MemoryMaxKeys)
+	if c.Store != "redis" {
+		return memory, nil
+	}
+	client, err := newRedisClient(c.RedisURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := pingRedis(client); err != nil {
+		log.Printf("Warning: rate limit store unavailable, limiting per instance until it recovers: %v", err)
+	}
+	return ratelimit.NewFallbackStore(ratelimit.NewRedisStore(client, "ratelimit:"), memory), nil
+}
+
+// getEnvInt parses a positive integer. Malformed values are an error rather than the default, so
+// a typo stops startup instead of running with a setting nobody chose.
+func getEnvInt(key string, defaultVal int) (int, error) {
+	raw := getEnv(key, "")
+	if raw == "" {
+		return defaultVal, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("%s must be a whole number above 0, got %q", key, raw)
+	}
+	return value, nil
+}
+
+// getEnvCount parses a count that may be 0, such as a number of workers.
+func getEnvCount(key string, defaultVal int) (int, error) {
+	raw := getEnv(key, "")
+	if raw == "" {
+		return defaultVal, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%s must be a whole number of 0 or more, got %q", key, raw)
+	}
+	return value, nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// openRedis connects to the Redis server at url and pings it.
+func openRedis(url string) (*redis.Client, error) {
+	client, err := newRedisClient(url)
+	if err != nil {
+		return nil, err
+	}
+	if err := pingRedis(client); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// newRedisClient returns a client for url without connecting; it connects on first use.
+func newRedisClient(url string) (*redis.Client, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	return redis.NewClient(options), nil
+}
+
+func pingRedis(client *redis.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return client.Ping(ctx).Err()
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend/internal/infrastructure/repositories"
+)
+
+// TokenRevocationConfig configures the denylist of revoked access tokens.
+type TokenRevocationConfig struct {
+	Store         string // "db" or "redis"
+	RedisURL      string
+	SweepInterval time.Duration // how often expired entries are removed from the db store; 0 disables
+}
+
+func NewTokenRevocationConfig() (*TokenRevocationConfig, error) {
+	var env envReader
+	cfg := &TokenRevocationConfig{
+		Store:         getEnv("TOKEN_REVOCATION_STORE", "db"),
+		RedisURL:      getEnv("REDIS_URL", "redis://localhost:6379/0"),
+		SweepInterval: env.Duration("TOKEN_REVOCATION_SWEEP_INTERVAL", time.Hour),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	switch cfg.Store {
+	case "db", "redis":
+	default:
+		return nil, fmt.Errorf("TOKEN_REVOCATION_STORE must be db or redis, got %q", cfg.Store)
+	}
+	return cfg, nil
+}
+
+// OpenStore returns the configured denylist, connecting to and pinging Redis when selected. The
+// db store uses the revoked_tokens table of db.
+func (c *TokenRevocationConfig) OpenStore(db *sql.DB) (repositories.RevokedTokenRepository, error) {
+	if c.Store == "redis" {
+		client, err := openRedis(c.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		return repositories.NewRedisRevokedTokenRepository(client), nil
+	}
+	return repositories.NewRevokedTokenRepository(db), nil
+}
@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+// LoginRiskConfig configures the sources feeding the login risk score.
+type LoginRiskConfig struct {
+	FeedURL       string
+	FeedTimeout   time.Duration
+	FeedCacheTTL  time.Duration
+	FailureWindow time.Duration
+	FailureWeight int
+}
+
+func NewLoginRiskConfig() (*LoginRiskConfig, error) {
+	var env envReader
+	cfg := &LoginRiskConfig{
+		FeedURL:       getEnv("IP_REPUTATION_FEED_URL", ""),
+		FeedTimeout:   env.Duration("IP_REPUTATION_FEED_TIMEOUT", 2*time.Second),
+		FeedCacheTTL:  env.Duration("IP_REPUTATION_CACHE_TTL", 10*time.Minute),
+		FailureWindow: env.Duration("LOGIN_RISK_FAILURE_WINDOW", 15*time.Minute),
+		FailureWeight: env.Int("LOGIN_RISK_FAILURE_WEIGHT", 10),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
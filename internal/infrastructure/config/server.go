@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+type ServerConfig struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	// MaxBodyBytes caps request bodies; MaxImportBodyBytes replaces it for user imports and uploads
+	MaxBodyBytes       int
+	MaxImportBodyBytes int
+	// TrustedProxies are the addresses or CIDR ranges of the proxies whose X-Forwarded-For header
+	// gives the client IP. Without any, the client IP is the connection's peer.
+	TrustedProxies []string
+}
+
+func NewServerConfig() (*ServerConfig, error) {
+	var env envReader
+	cfg := &ServerConfig{
+		Addr:              getEnv("SERVER_ADDR", ":8080"),
+		ReadTimeout:       env.Duration("SERVER_READ_TIMEOUT", 15*time.Second),
+		ReadHeaderTimeout: env.Duration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+		WriteTimeout:      env.Duration("SERVER_WRITE_TIMEOUT", 15*time.Second),
+		IdleTimeout:       env.Duration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+		ShutdownTimeout:   env.Duration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second),
+		// The import file may be 10MB, plus the other form fields
+		MaxBodyBytes:       env.Int("SERVER_MAX_BODY_BYTES", 1<<20),
+		MaxImportBodyBytes: env.Int("SERVER_MAX_IMPORT_BODY_BYTES", 12<<20),
+		TrustedProxies:     getEnvList("TRUSTED_PROXIES"),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	for _, proxy := range cfg.TrustedProxies {
+		var err error
+		if strings.Contains(proxy, "/") {
+			_, err = netip.ParsePrefix(proxy)
+		} else {
+			_, err = netip.ParseAddr(proxy)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES entry %q must be an IP address or CIDR range", proxy)
+		}
+	}
+	return cfg, nil
+}
+
+// getEnvDuration parses values such as "15s" or "1m". A number without a unit is an error, as
+// are negative durations.
+func getEnvDuration(key string, defaultVal time.Duration) (time.Duration, error) {
+	raw := getEnv(key, "")
+	if raw == "" {
+		return defaultVal, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s must be a duration such as 30s or 5m, got %q", key, raw)
+	}
+	return d, nil
+}
+
+// envReader reads the numeric settings of one section and collects the malformed ones, so they
+// are all reported together and the fields can still be set in one struct literal.
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) Int(key string, defaultVal int) int {
+	value, err := getEnvInt(key, defaultVal)
+	r.errs = append(r.errs, err)
+	return value
+}
+
+func (r *envReader) Count(key string, defaultVal int) int {
+	value, err := getEnvCount(key, defaultVal)
+	r.errs = append(r.errs, err)
+	return value
+}
+
+func (r *envReader) Duration(key string, defaultVal time.Duration) time.Duration {
+	value, err := getEnvDuration(key, defaultVal)
+	r.errs = append(r.errs, err)
+	return value
+}
+
+// Err joins the errors of the malformed settings read, or is nil.
+func (r *envReader) Err() error {
+	return errors.Join(r.errs...)
+}
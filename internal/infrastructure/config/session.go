@@ -0,0 +1,45 @@
+package config
+
+import "time"
+
+// HostedSessionConfig configures the session cookie the hosted login page sets, which SPAs use to
+// renew access tokens silently at /auth/session/refresh.
+type HostedSessionConfig struct {
+	TTL time.Duration
+	// CookieSecure marks the cookie Secure and SameSite=None so it is sent to the refresh iframe
+	// embedded by apps on other sites; turn it off only for local development over plain HTTP
+	CookieSecure bool
+}
+
+func NewHostedSessionConfig() (*HostedSessionConfig, error) {
+	var env envReader
+	cfg := &HostedSessionConfig{
+		TTL:          env.Duration("HOSTED_SESSION_TTL", 12*time.Hour),
+		CookieSecure: getEnv("HOSTED_SESSION_COOKIE_SECURE", "true") == "true",
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// TrustedDeviceConfig configures the devices users remember at login, which skip MFA challenges.
+type TrustedDeviceConfig struct {
+	// TTL is how long a device stays trusted after its last login
+	TTL time.Duration
+	// CookieSecure marks the device cookie set by /auth/login Secure; turn it off only for local
+	// development over plain HTTP
+	CookieSecure bool
+}
+
+func NewTrustedDeviceConfig() (*TrustedDeviceConfig, error) {
+	var env envReader
+	cfg := &TrustedDeviceConfig{
+		TTL:          env.Duration("TRUSTED_DEVICE_TTL", 30*24*time.Hour),
+		CookieSecure: getEnv("TRUSTED_DEVICE_COOKIE_SECURE", "true") == "true",
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,371 @@
+package config
+
+import "sort"
+
+// Snapshot is the effective configuration with every secret left out: passwords, tokens and
+// connection strings are reduced to whether they are set. Durations are rendered as strings.
+type Snapshot struct {
+	Server            ServerSnapshot            `json:"server"`
+	CORS              CORSSnapshot              `json:"cors"`
+	Database          DatabaseSnapshot          `json:"database"`
+	Mail              MailSnapshot              `json:"mail"`
+	Tracing           TracingSnapshot           `json:"tracing"`
+	RateLimit         RateLimitSnapshot         `json:"rate_limit"`
+	RequestRateLimit  RequestRateLimitSnapshot  `json:"request_rate_limit"`
+	Cache             CacheSnapshot             `json:"cache"`
+	TokenRevocation   TokenRevocationSnapshot   `json:"token_revocation"`
+	LoginRisk         LoginRiskSnapshot         `json:"login_risk"`
+	Passwordless      PasswordlessSnapshot      `json:"passwordless"`
+	Invitations       InvitationSnapshot        `json:"invitations"`
+	HostedSession     HostedSessionSnapshot     `json:"hosted_session"`
+	TrustedDevices    TrustedDeviceSnapshot     `json:"trusted_devices"`
+	BreakGlass        BreakGlassSnapshot        `json:"break_glass"`
+	Impersonation     ImpersonationSnapshot     `json:"impersonation"`
+	Storage           StorageSnapshot           `json:"storage"`
+	Avatars           AvatarSnapshot            `json:"avatars"`
+	DecisionLog       DecisionLogSnapshot       `json:"decision_log"`
+	UserExpiry        UserExpirySnapshot        `json:"user_expiry"`
+	AccountDeletion   AccountDeletionSnapshot   `json:"account_deletion"`
+	IntegrationHealth IntegrationHealthSnapshot `json:"integration_health"`
+	Health            HealthSnapshot            `json:"health"`
+	Operator          OperatorSnapshot          `json:"operator"`
+	JWT               JWTSnapshot               `json:"jwt"`
+	FaultInjection    FaultInjectionSnapshot    `json:"fault_injection"`
+	MigrationsDir     string                    `json:"migrations_dir" example:"migrations"`
+}
+
+type ServerSnapshot struct {
+	Addr               string   `json:"addr" example:":8080"`
+	ReadTimeout        string   `json:"read_timeout" example:"15s"`
+	ReadHeaderTimeout  string   `json:"read_header_timeout" example:"5s"`
+	WriteTimeout       string   `json:"write_timeout" example:"15s"`
+	IdleTimeout        string   `json:"idle_timeout" example:"1m0s"`
+	ShutdownTimeout    string   `json:"shutdown_timeout" example:"30s"`
+	MaxBodyBytes       int      `json:"max_body_bytes" example:"1048576"`
+	MaxImportBodyBytes int      `json:"max_import_body_bytes" example:"12582912"`
+	TrustedProxies     []string `json:"trusted_proxies" example:"10.0.0.0/8"`
+}
+
+type CORSSnapshot struct {
+	AllowedOrigins   []string `json:"allowed_origins" example:"https://app.example.com"`
+	AllowedMethods   []string `json:"allowed_methods" example:"GET,POST"`
+	AllowedHeaders   []string `json:"allowed_headers" example:"Authorization,Content-Type"`
+	AllowCredentials bool     `json:"allow_credentials"`
+	MaxAge           string   `json:"max_age" example:"12h0m0s"`
+	DomainOrigins    bool     `json:"domain_origins"`
+}
+
+type DatabaseSnapshot struct {
+	Driver           string   `json:"driver" enums:"postgres,sqlite" example:"postgres"`
+	Host             string   `json:"host" example:"localhost"`
+	Port             string   `json:"port" example:"5432"`
+	Name             string   `json:"name" example:"nusarithm_iam"`
+	User             string   `json:"user" example:"postgres"`
+	SSLMode          string   `json:"ssl_mode" example:"disable"`
+	RowLevelSecurity bool     `json:"row_level_security" example:"false"`
+	MaxOpenConns     int      `json:"max_open_conns" example:"25"`
+	MaxIdleConns     int      `json:"max_idle_conns" example:"10"`
+	ConnMaxLifetime  string   `json:"conn_max_lifetime" example:"30m0s"`
+	ConnMaxIdleTime  string   `json:"conn_max_idle_time" example:"5m0s"`
+	Shards           []string `json:"shards" example:"eu"`        // residencies with their own database
+	Replicas         []string `json:"replicas" example:"default"` // residencies with a read replica
+}
+
+type MailSnapshot struct {
+	Driver                string `json:"driver" enums:"smtp,sendgrid,ses,log" example:"smtp"`
+	SMTPHost              string `json:"smtp_host" example:"smtp.example.com"`
+	SMTPPort              string `json:"smtp_port" example:"587"`
+	From                  string `json:"from" example:"no-reply@nusarithm.local"`
+	SendGridKeyConfigured bool   `json:"sendgrid_key_configured" example:"false"`
+	SESRegion             string `json:"ses_region" example:"eu-west-1"`
+	CredentialsKeySet     bool   `json:"credentials_key_set" example:"true"`
+}
+
+type TracingSnapshot struct {
+	Enabled     bool    `json:"enabled" example:"false"`
+	Endpoint    string  `json:"endpoint" example:"localhost:4318"`
+	ServiceName string  `json:"service_name" example:"nusarithm-iam"`
+	SampleRatio float64 `json:"sample_ratio" example:"1"`
+}
+
+type RateLimitSnapshot struct {
+	DefaultPerMinute  int `json:"default_per_minute" example:"60"`
+	DefaultDailyQuota int `json:"default_daily_quota" example:"10000"`
+}
+
+// RequestRateLimitSnapshot renders limits as "<requests>/<period>", or "0" when disabled.
+type RequestRateLimitSnapshot struct {
+	Store         string `json:"store" enums:"memory,redis" example:"memory"`
+	MemoryMaxKeys int    `json:"memory_max_keys" example:"100000"`
+	PerIP         string `json:"per_ip" example:"300/1m0s"`
+	PerUser       string `json:"per_user" example:"600/1m0s"`
+	Login         string `json:"login" example:"10/1m0s"`
+	EmailSend     string `json:"email_send" example:"5/15m0s"`
+	Introspection string `json:"introspection" example:"6000/1m0s"`
+}
+
+type CacheSnapshot struct {
+	Store                    string `json:"store" enums:"memory,redis,off" example:"memory"`
+	TTL                      string `json:"ttl" example:"30s"`
+	IntrospectionTTL         string `json:"introspection_ttl" example:"5s"`
+	IntrospectionNegativeTTL string `json:"introspection_negative_ttl" example:"30s"`
+}
+
+type TokenRevocationSnapshot struct {
+	Store         string `json:"store" enums:"db,redis" example:"db"`
+	SweepInterval string `json:"sweep_interval" example:"1h0m0s"`
+}
+
+type LoginRiskSnapshot struct {
+	ReputationFeed bool   `json:"reputation_feed" example:"false"` // the feed URL may carry credentials
+	FeedTimeout    string `json:"feed_timeout" example:"2s"`
+	FeedCacheTTL   string `json:"feed_cache_ttl" example:"10m0s"`
+	FailureWindow  string `json:"failure_window" example:"15m0s"`
+	FailureWeight  int    `json:"failure_weight" example:"10"`
+	// CaptchaProvider verifies answers to CAPTCHA challenges; empty when none is configured
+	CaptchaProvider string `json:"captcha_provider" enums:"recaptcha,hcaptcha,turnstile" example:"turnstile"`
+	CaptchaTimeout  string `json:"captcha_timeout" example:"5s"`
+}
+
+type PasswordlessSnapshot struct {
+	CodeTTL     string `json:"code_ttl" example:"10m0s"`
+	MaxAttempts int    `json:"max_attempts" example:"5"`
+	LinkURL     string `json:"link_url" example:"http://localhost:3000/auth/magic-link"`
+}
+
+type InvitationSnapshot struct {
+	TTL     string `json:"ttl" example:"168h0m0s"`
+	LinkURL string `json:"link_url" example:"http://localhost:3000/auth/accept-invitation"`
+}
+
+type TrustedDeviceSnapshot struct {
+	TTL          string `json:"ttl" example:"720h0m0s"`
+	CookieSecure bool   `json:"cookie_secure" example:"true"`
+}
+
+type HostedSessionSnapshot struct {
+	TTL          string `json:"ttl" example:"12h0m0s"`
+	CookieSecure bool   `json:"cookie_secure" example:"true"`
+}
+
+type BreakGlassSnapshot struct {
+	SessionTTL      string `json:"session_ttl" example:"1h0m0s"`
+	AlertRecipients int    `json:"alert_recipients" example:"2"`
+}
+
+type ImpersonationSnapshot struct {
+	TokenTTL string `json:"token_ttl" example:"15m0s"`
+}
+
+type StorageSnapshot struct {
+	Driver    string `json:"driver" enums:"local,s3" example:"local"`
+	LocalDir  string `json:"local_dir,omitempty" example:"uploads"`
+	PublicURL string `json:"public_url" example:"/uploads"`
+	S3Bucket  string `json:"s3_bucket,omitempty" example:"iam-uploads"`
+	S3Region  string `json:"s3_region,omitempty" example:"us-east-1"`
+	Timeout   string `json:"timeout" example:"30s"`
+}
+
+type AvatarSnapshot struct {
+	MaxBytes int `json:"max_bytes" example:"5242880"`
+	Size     int `json:"size" example:"256"`
+}
+
+type DecisionLogSnapshot struct {
+	Enabled         bool    `json:"enabled" example:"true"`
+	SampleRate      float64 `json:"sample_rate" example:"1"`
+	AlwaysLogDenied bool    `json:"always_log_denied" example:"true"`
+}
+
+type UserExpirySnapshot struct {
+	SweepInterval string `json:"sweep_interval" example:"5m0s"` // 0s when the sweep is disabled
+}
+
+type AccountDeletionSnapshot struct {
+	GracePeriod   string `json:"grace_period" example:"720h0m0s"`
+	SweepInterval string `json:"sweep_interval" example:"1h0m0s"` // 0s when the sweep is disabled
+}
+
+type HealthSnapshot struct {
+	ProbeInterval string `json:"probe_interval" example:"15s"` // 0s when background probes are disabled
+	ProbeTimeout  string `json:"probe_timeout" example:"2s"`
+}
+
+type IntegrationHealthSnapshot struct {
+	CheckInterval   string `json:"check_interval" example:"5m0s"` // 0s when checks are disabled
+	AlertThreshold  int    `json:"alert_threshold" example:"3"`
+	AlertRecipients int    `json:"alert_recipients" example:"1"`
+}
+
+type OperatorSnapshot struct {
+	TokenConfigured bool `json:"token_configured" example:"true"`
+}
+
+type JWTSnapshot struct {
+	KeySource     string `json:"key_source" enums:"secret,env,file" example:"file"`
+	DefaultSecret bool   `json:"default_secret" example:"false"` // signed with the built-in development secret
+	PreviousKeys  int    `json:"previous_keys" example:"1"`
+}
+
+type FaultInjectionSnapshot struct {
+	Enabled     bool   `json:"enabled" example:"false"`
+	MaxDuration string `json:"max_duration" example:"1h0m0s"`
+}
+
+// Snapshot reports the configuration the server was started with.
+func (c *AppConfig) Snapshot() *Snapshot {
+	server := c.Server
+	cors := c.CORS
+	db := c.Database
+	tracing := c.Tracing
+	rateLimit := c.APIKeyRateLimits
+	risk := c.LoginRisk
+	passwordless := c.Passwordless
+	invitations := c.Invitations
+	breakGlass := c.BreakGlass
+	hostedSession := c.HostedSession
+	trustedDevices := c.TrustedDevices
+	jwt := c.JWT
+	decisionLog := c.DecisionLog
+	integrations := c.IntegrationHealth
+	accountDeletion := c.AccountDeletion
+	health := c.Health
+	faultInjection := c.FaultInjection
+	introspection := c.Introspection
+	requestLimits := c.RequestRateLimits
+	cacheConfig := c.Cache
+	captcha := c.Captcha
+	mail := c.Mail
+	revocation := c.TokenRevocation
+	storageConfig := c.Storage
+	avatars := c.Avatars
+
+	storageSnapshot := StorageSnapshot{Driver: storageConfig.Driver, PublicURL: storageConfig.PublicURL, Timeout: storageConfig.Timeout.String()}
+	if storageConfig.Driver == "s3" {
+		storageSnapshot.S3Bucket, storageSnapshot.S3Region = storageConfig.S3.Bucket, storageConfig.S3.Region
+	} else {
+		storageSnapshot.LocalDir = storageConfig.LocalDir
+	}
+
+	return &Snapshot{
+		Server: ServerSnapshot{
+			Addr:               server.Addr,
+			ReadTimeout:        server.ReadTimeout.String(),
+			ReadHeaderTimeout:  server.ReadHeaderTimeout.String(),
+			WriteTimeout:       server.WriteTimeout.String(),
+			IdleTimeout:        server.IdleTimeout.String(),
+			ShutdownTimeout:    server.ShutdownTimeout.String(),
+			MaxBodyBytes:       server.MaxBodyBytes,
+			MaxImportBodyBytes: server.MaxImportBodyBytes,
+			TrustedProxies:     server.TrustedProxies,
+		},
+		CORS: CORSSnapshot{
+			AllowedOrigins:   cors.AllowedOrigins,
+			AllowedMethods:   cors.AllowedMethods,
+			AllowedHeaders:   cors.AllowedHeaders,
+			AllowCredentials: cors.AllowCredentials,
+			MaxAge:           cors.MaxAge.String(),
+			DomainOrigins:    cors.DomainOrigins,
+		},
+		Database: DatabaseSnapshot{
+			Driver:           db.Driver,
+			Host:             db.Host,
+			Port:             db.Port,
+			Name:             db.DBName,
+			User:             db.User,
+			SSLMode:          db.SSLMode,
+			RowLevelSecurity: db.RowLevelSecurity,
+			MaxOpenConns:     db.Pool.MaxOpenConns,
+			MaxIdleConns:     db.Pool.MaxIdleConns,
+			ConnMaxLifetime:  db.Pool.ConnMaxLifetime.String(),
+			ConnMaxIdleTime:  db.Pool.ConnMaxIdleTime.String(),
+			Shards:           sortedKeys(c.ShardDSNs),
+			Replicas:         sortedKeys(c.ReplicaDSNs),
+		},
+		Mail: MailSnapshot{
+			Driver:                mail.Driver,
+			SMTPHost:              mail.SMTPHost,
+			SMTPPort:              mail.SMTPPort,
+			From:                  mail.From,
+			SendGridKeyConfigured: mail.SendGridAPIKey != "",
+			SESRegion:             mail.SESRegion,
+			CredentialsKeySet:     mail.Credentials != nil,
+		},
+		Tracing: TracingSnapshot{
+			Enabled:     tracing.Enabled,
+			Endpoint:    tracing.Endpoint,
+			ServiceName: tracing.ServiceName,
+			SampleRatio: tracing.SampleRatio,
+		},
+		RateLimit: RateLimitSnapshot{DefaultPerMinute: rateLimit.DefaultPerMinute, DefaultDailyQuota: rateLimit.DefaultDailyQuota},
+		RequestRateLimit: RequestRateLimitSnapshot{
+			Store:         requestLimits.Store,
+			MemoryMaxKeys: requestLimits.MemoryMaxKeys,
+			PerIP:         requestLimits.PerIP.String(),
+			PerUser:       requestLimits.PerUser.String(),
+			Login:         requestLimits.Login.String(),
+			EmailSend:     requestLimits.EmailSend.String(),
+			Introspection: requestLimits.Introspection.String(),
+		},
+		Cache: CacheSnapshot{
+			Store:                    cacheConfig.Store,
+			TTL:                      cacheConfig.TTL.String(),
+			IntrospectionTTL:         introspection.CacheTTL.String(),
+			IntrospectionNegativeTTL: introspection.NegativeCacheTTL.String(),
+		},
+		TokenRevocation: TokenRevocationSnapshot{Store: revocation.Store, SweepInterval: revocation.SweepInterval.String()},
+		LoginRisk: LoginRiskSnapshot{
+			ReputationFeed:  risk.FeedURL != "",
+			FeedTimeout:     risk.FeedTimeout.String(),
+			FeedCacheTTL:    risk.FeedCacheTTL.String(),
+			FailureWindow:   risk.FailureWindow.String(),
+			FailureWeight:   risk.FailureWeight,
+			CaptchaProvider: captcha.Provider,
+			CaptchaTimeout:  captcha.Timeout.String(),
+		},
+		Passwordless: PasswordlessSnapshot{
+			CodeTTL:     passwordless.CodeTTL.String(),
+			MaxAttempts: passwordless.MaxAttempts,
+			LinkURL:     passwordless.LinkURL,
+		},
+		Invitations:    InvitationSnapshot{TTL: invitations.TTL.String(), LinkURL: invitations.LinkURL},
+		HostedSession:  HostedSessionSnapshot{TTL: hostedSession.TTL.String(), CookieSecure: hostedSession.CookieSecure},
+		TrustedDevices: TrustedDeviceSnapshot{TTL: trustedDevices.TTL.String(), CookieSecure: trustedDevices.CookieSecure},
+		BreakGlass:     BreakGlassSnapshot{SessionTTL: breakGlass.SessionTTL.String(), AlertRecipients: len(breakGlass.AlertEmails)},
+		Impersonation:  ImpersonationSnapshot{TokenTTL: c.Impersonation.TokenTTL.String()},
+		Storage:        storageSnapshot,
+		Avatars:        AvatarSnapshot{MaxBytes: avatars.MaxBytes, Size: avatars.Size},
+		DecisionLog: DecisionLogSnapshot{
+			Enabled:         decisionLog.Enabled,
+			SampleRate:      decisionLog.SampleRate,
+			AlwaysLogDenied: decisionLog.AlwaysLogDenied,
+		},
+		UserExpiry: UserExpirySnapshot{SweepInterval: c.UserExpiry.SweepInterval.String()},
+		AccountDeletion: AccountDeletionSnapshot{
+			GracePeriod:   accountDeletion.GracePeriod.String(),
+			SweepInterval: accountDeletion.SweepInterval.String(),
+		},
+		IntegrationHealth: IntegrationHealthSnapshot{
+			CheckInterval:   integrations.CheckInterval.String(),
+			AlertThreshold:  integrations.AlertThreshold,
+			AlertRecipients: len(integrations.AlertEmails),
+		},
+		Health: HealthSnapshot{
+			ProbeInterval: health.ProbeInterval.String(),
+			ProbeTimeout:  health.ProbeTimeout.String(),
+		},
+		Operator:       OperatorSnapshot{TokenConfigured: c.Operator.Token != ""},
+		JWT:            JWTSnapshot{KeySource: jwt.KeySource(), DefaultSecret: jwt.DefaultSecret(), PreviousKeys: len(jwt.PreviousKeyFiles)},
+		FaultInjection: FaultInjectionSnapshot{Enabled: faultInjection.Enabled, MaxDuration: faultInjection.MaxDuration.String()},
+		MigrationsDir:  c.Startup.MigrationsDir,
+	}
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StartupConfig configures the dependency checks run before the server binds its port. Required
+// dependencies are retried with exponential backoff so a transient outage doesn't stop the boot.
+type StartupConfig struct {
+	RetryAttempts     int
+	RetryBackoff      time.Duration // wait after the first failure, doubled after each further one
+	RetryMaxBackoff   time.Duration
+	RequireMigrations bool // refuse to start when a database is behind the shipped migrations
+	VerifyMail        bool // connect to the SMTP server once; failures only warn
+	MigrationsDir     string
+}
+
+func NewStartupConfig() (*StartupConfig, error) {
+	var env envReader
+	cfg := &StartupConfig{
+		RetryAttempts:     max(env.Int("STARTUP_RETRY_ATTEMPTS", 10), 1),
+		RetryBackoff:      env.Duration("STARTUP_RETRY_BACKOFF", time.Second),
+		RetryMaxBackoff:   env.Duration("STARTUP_RETRY_MAX_BACKOFF", 30*time.Second),
+		RequireMigrations: getEnv("STARTUP_REQUIRE_MIGRATIONS", "false") == "true",
+		VerifyMail:        getEnv("STARTUP_VERIFY_MAIL", "true") == "true",
+		MigrationsDir:     getEnv("MIGRATIONS_DIR", "migrations"),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LatestMigration returns the newest numbered migration in dir, or "" if it cannot be read.
+func LatestMigration(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[len(names)-1]
+}
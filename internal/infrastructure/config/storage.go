@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"backend/internal/infrastructure/storage"
+)
+
+// LocalStoragePath is where the server serves the files of the local storage driver.
+const LocalStoragePath = "/uploads"
+
+// StorageConfig selects where uploaded files such as avatars are kept: on local disk, served by
+// this server at LocalStoragePath, or in an S3 bucket. PublicURL is the base of the URLs clients
+// fetch them from; set it to this server's public URL plus /uploads for the local driver, or to a
+// CDN in front of the bucket.
+type StorageConfig struct {
+	Driver    string // "local" or "s3"
+	LocalDir  string
+	PublicURL string
+	Timeout   time.Duration // per S3 request
+	S3        storage.S3Config
+}
+
+func NewStorageConfig() (*StorageConfig, error) {
+	var env envReader
+	cfg := &StorageConfig{
+		Driver:    getEnv("STORAGE_DRIVER", "local"),
+		LocalDir:  getEnv("STORAGE_LOCAL_DIR", "uploads"),
+		PublicURL: getEnv("STORAGE_PUBLIC_URL", ""),
+		Timeout:   env.Duration("STORAGE_TIMEOUT", 30*time.Second),
+		S3: storage.S3Config{
+			Bucket:          getEnv("STORAGE_S3_BUCKET", ""),
+			Region:          getEnv("STORAGE_S3_REGION", "us-east-1"),
+			Endpoint:        getEnv("STORAGE_S3_ENDPOINT", ""),
+			AccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			SessionToken:    getEnv("AWS_SESSION_TOKEN", ""),
+		},
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Driver {
+	case "local":
+		if cfg.PublicURL == "" {
+			cfg.PublicURL = LocalStoragePath
+		}
+	case "s3":
+		if cfg.S3.Bucket == "" || cfg.S3.AccessKeyID == "" || cfg.S3.SecretAccessKey == "" {
+			return nil, fmt.Errorf("STORAGE_S3_BUCKET, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required when STORAGE_DRIVER is s3")
+		}
+		cfg.S3.PublicURL = cfg.PublicURL
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER must be local or s3, got %q", cfg.Driver)
+	}
+	return cfg, nil
+}
+
+// Open returns the configured storage. The local driver creates its directory here; S3 is first
+// contacted by the first upload.
+func (c *StorageConfig) Open() (storage.ObjectStorage, error) {
+	if c.Driver == "s3" {
+		return storage.NewS3Storage(c.S3, c.Timeout), nil
+	}
+	return storage.NewLocalStorage(c.LocalDir, c.PublicURL)
+}
+
+// AvatarConfig limits avatar uploads. Avatars are cropped to a square of at most Size pixels a side.
+type AvatarConfig struct {
+	MaxBytes int
+	Size     int
+}
+
+func NewAvatarConfig() (*AvatarConfig, error) {
+	var env envReader
+	cfg := &AvatarConfig{
+		MaxBytes: env.Int("AVATAR_MAX_BYTES", 5<<20),
+		Size:     env.Int("AVATAR_SIZE", 256),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
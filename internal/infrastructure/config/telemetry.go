@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"backend/internal/infrastructure/telemetry"
+)
+
+// TelemetryConfig configures exporting the anonymized sign-in funnel of the domains that opted
+// in. Every ExportInterval each such domain's new login events are written to the sink in batches
+// of up to BatchSize records. Accounts are pseudonymized with an HMAC keyed by AnonymizationKey,
+// so the key must stay the same for records to be joined across exports.
+type TelemetryConfig struct {
+	Sink             string // "none", "s3" or "bigquery"
+	Format           string // object format of the s3 sink: "ndjson" or "parquet"
+	ExportInterval   time.Duration
+	BatchSize        int
+	Timeout          time.Duration // per batch written
+	AnonymizationKey string
+	S3               telemetry.S3Config
+	BigQuery         telemetry.BigQueryConfig
+}
+
+func NewTelemetryConfig() (*TelemetryConfig, error) {
+	var env envReader
+	cfg := &TelemetryConfig{
+		Sink:             getEnv("TELEMETRY_EXPORT_SINK", "none"),
+		Format:           getEnv("TELEMETRY_EXPORT_FORMAT", telemetry.FormatNDJSON),
+		ExportInterval:   env.Duration("TELEMETRY_EXPORT_INTERVAL", 15*time.Minute),
+		BatchSize:        max(env.Int("TELEMETRY_EXPORT_BATCH_SIZE", 500), 1),
+		Timeout:          env.Duration("TELEMETRY_EXPORT_TIMEOUT", 30*time.Second),
+		AnonymizationKey: getEnv("TELEMETRY_ANONYMIZATION_KEY", ""),
+		S3: telemetry.S3Config{
+			Bucket:          getEnv("TELEMETRY_S3_BUCKET", ""),
+			Prefix:          getEnv("TELEMETRY_S3_PREFIX", "login-telemetry"),
+			Region:          getEnv("TELEMETRY_S3_REGION", "us-east-1"),
+			Endpoint:        getEnv("TELEMETRY_S3_ENDPOINT", ""),
+			AccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			SessionToken:    getEnv("AWS_SESSION_TOKEN", ""),
+		},
+		BigQuery: telemetry.BigQueryConfig{
+			ProjectID:       getEnv("TELEMETRY_BIGQUERY_PROJECT", ""),
+			Dataset:         getEnv("TELEMETRY_BIGQUERY_DATASET", ""),
+			Table:           getEnv("TELEMETRY_BIGQUERY_TABLE", "login_events"),
+			CredentialsFile: getEnv("GOOGLE_APPLICATION_CREDENTIALS", ""),
+		},
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+
+	switch cfg.Sink {
+	case "none":
+		return cfg, nil
+	case "s3":
+		if cfg.S3.Bucket == "" || cfg.S3.AccessKeyID == "" || cfg.S3.SecretAccessKey == "" {
+			return nil, fmt.Errorf("TELEMETRY_S3_BUCKET, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required when TELEMETRY_EXPORT_SINK is s3")
+		}
+		if cfg.Format != telemetry.FormatNDJSON && cfg.Format != telemetry.FormatParquet {
+			return nil, fmt.Errorf("TELEMETRY_EXPORT_FORMAT must be ndjson or parquet, got %q", cfg.Format)
+		}
+	case "bigquery":
+		if cfg.BigQuery.ProjectID == "" || cfg.BigQuery.Dataset == "" || cfg.BigQuery.CredentialsFile == "" {
+			return nil, fmt.Errorf("TELEMETRY_BIGQUERY_PROJECT, TELEMETRY_BIGQUERY_DATASET and GOOGLE_APPLICATION_CREDENTIALS are required when TELEMETRY_EXPORT_SINK is bigquery")
+		}
+	default:
+		return nil, fmt.Errorf("TELEMETRY_EXPORT_SINK must be none, s3 or bigquery, got %q", cfg.Sink)
+	}
+	if len(cfg.AnonymizationKey) < 32 {
+		return nil, fmt.Errorf("TELEMETRY_ANONYMIZATION_KEY must be at least 32 characters when TELEMETRY_EXPORT_SINK is set")
+	}
+	return cfg, nil
+}
+
+// Enabled reports whether login telemetry is exported.
+func (c *TelemetryConfig) Enabled() bool {
+	return c.Sink != "none"
+}
+
+// OpenSink returns the configured sink, or nil when the export is disabled. The BigQuery sink
+// reads its credentials file here; neither sink contacts the warehouse until the first export.
+func (c *TelemetryConfig) OpenSink() (telemetry.Sink, error) {
+	switch c.Sink {
+	case "s3":
+		sink, err := telemetry.NewS3Sink(c.S3, c.Format, c.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		return sink, nil
+	case "bigquery":
+		sink, err := telemetry.NewBigQuerySink(c.BigQuery, c.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		return sink, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,260 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracedDriverName = "postgres-traced"
+	// tenantDriverName also sets app.domain_id on each connection for row-level security
+	tenantDriverName = "postgres-traced-tenant"
+)
+
+var registerTracedDriver sync.Once
+
+var dbTracer = otel.Tracer("backend/internal/infrastructure/config")
+
+// rlsBypassRole is the role the statements of system paths (see tenancy.AsSystem) run as under
+// row-level security. It has BYPASSRLS; see migrations/optional/row_level_security.sql.
+const rlsBypassRole = "iam_rls_bypass"
+
+// tracedDriver wraps lib/pq so every statement runs inside a client span carrying its SQL text.
+// With rowSecurity set, each statement also runs with app.domain_id set to the tenant from its
+// context (see package tenancy), as rlsBypassRole on system paths, and otherwise without a tenant,
+// which the policies give no rows.
+type tracedDriver struct {
+	driver.Driver
+	rowSecurity bool
+}
+
+func (d tracedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn, rowSecurity: d.rowSecurity}, nil
+}
+
+type tracedConn struct {
+	driver.Conn
+	rowSecurity bool
+
+	// tenant and role are the app.domain_id and role last set on the session; tenantKnown is false
+	// until they have been set and again after a rollback, which may have undone the setting, or a
+	// checkout
+	tenant      string
+	role        string
+	tenantKnown bool
+}
+
+// scopeTenant points app.domain_id at the context's tenant and returns to the session's own role,
+// which the policies limit to that tenant. Without a tenant it clears app.domain_id, so the
+// statement fails closed and sees no tenant rows, and only switches to rlsBypassRole when the
+// context is a system path. The round trip is skipped when the session already has the settings.
+// They are session-wide so they also cover transactions started with the same context, and they
+// are set again after every checkout from the pool, so a connection never runs a statement with
+// the settings of its previous user.
+func (c *tracedConn) scopeTenant(ctx context.Context) error {
+	if !c.rowSecurity {
+		return nil
+	}
+	tenant, role := "", "none"
+	if domainID, ok := tenancy.DomainFrom(ctx); ok {
+		tenant = domainID.String()
+	} else if tenancy.IsSystem(ctx) {
+		role = rlsBypassRole
+	}
+	if c.tenantKnown && c.tenant == tenant && c.role == role {
+		return nil
+	}
+
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil
+	}
+	_, err := execer.ExecContext(ctx, "SELECT set_config('role', $1, false), set_config('app.domain_id', $2, false)",
+		[]driver.NamedValue{{Ordinal: 1, Value: role}, {Ordinal: 2, Value: tenant}})
+	if err != nil {
+		c.tenantKnown = false
+		return err
+	}
+	c.tenant, c.role, c.tenantKnown = tenant, role, true
+	return nil
+}
+
+func startStatementSpan(ctx context.Context, operation, statement string) (context.Context, trace.Span) {
+	return dbTracer.Start(ctx, "sql."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			attribute.String("db.statement", statement),
+		))
+}
+
+func endStatementSpan(span trace.Span, err error) {
+	if err != nil && err != driver.ErrSkip {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.scopeTenant(ctx); err != nil {
+		return nil, err
+	}
+	ctx, span := startStatementSpan(ctx, "query", query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	endStatementSpan(span, err)
+	return rows, err
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.scopeTenant(ctx); err != nil {
+		return nil, err
+	}
+	ctx, span := startStatementSpan(ctx, "exec", query)
+	result, err := execer.ExecContext(ctx, query, args)
+	endStatementSpan(span, err)
+	return result, err
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.scopeTenant(ctx); err != nil {
+		return nil, err
+	}
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil || !c.rowSecurity {
+		return stmt, err
+	}
+	return &tenantStmt{Stmt: stmt, conn: c}, nil
+}
+
+// tenantStmt scopes each execution of a prepared statement to the tenant of its own context,
+// which may differ from the one it was prepared with.
+type tenantStmt struct {
+	driver.Stmt
+	conn *tracedConn
+}
+
+func (s *tenantStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := s.conn.scopeTenant(ctx); err != nil {
+		return nil, err
+	}
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, args)
+	}
+	return s.Stmt.Exec(namedValues(args)) //nolint:staticcheck // fallback for drivers without ExecContext
+}
+
+func (s *tenantStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := s.conn.scopeTenant(ctx); err != nil {
+		return nil, err
+	}
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		return queryer.QueryContext(ctx, args)
+	}
+	return s.Stmt.Query(namedValues(args)) //nolint:staticcheck // fallback for drivers without QueryContext
+}
+
+func namedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	// Set the tenant before BEGIN so a rollback can't undo it
+	if err := c.scopeTenant(ctx); err != nil {
+		return nil, err
+	}
+
+	var tx driver.Tx
+	var err error
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.Conn.Begin() //nolint:staticcheck // fallback for drivers without BeginTx
+	}
+	if err != nil || !c.rowSecurity {
+		return tx, err
+	}
+	return &tenantTx{Tx: tx, conn: c}, nil
+}
+
+// tenantTx forgets the session's tenant on rollback, since a tenant switched inside the
+// transaction is rolled back with it.
+type tenantTx struct {
+	driver.Tx
+	conn *tracedConn
+}
+
+func (t *tenantTx) Rollback() error {
+	t.conn.tenantKnown = false
+	return t.Tx.Rollback()
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession runs before the pool hands the connection out again. The tenant settings are
+// forgotten so the next statement sets them for its own context.
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	c.tenantKnown = false
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// openPostgres opens a connection pool whose statements are traced with their SQL text. With
+// rowSecurity, statements also set app.domain_id and the role for the row-level security policies.
+func openPostgres(dsn string, rowSecurity bool) (*sql.DB, error) {
+	registerTracedDriver.Do(func() {
+		sql.Register(tracedDriverName, tracedDriver{Driver: &pq.Driver{}})
+		sql.Register(tenantDriverName, tracedDriver{Driver: &pq.Driver{}, rowSecurity: true})
+	})
+	if rowSecurity {
+		return sql.Open(tenantDriverName, dsn)
+	}
+	return sql.Open(tracedDriverName, dsn)
+}
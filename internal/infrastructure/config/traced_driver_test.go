@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/google/uuid"
+)
+
+// recordingConn records the statements run on it and the arguments of the tenant settings.
+type recordingConn struct {
+	statements []string
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{conn: c, query: query}, nil
+}
+func (c *recordingConn) Close() error              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) { return nil, fmt.Errorf("not supported") }
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if len(args) == 2 {
+		query = fmt.Sprintf("set role=%v domain=%v", args[0].Value, args[1].Value)
+	}
+	c.statements = append(c.statements, query)
+	return driver.RowsAffected(0), nil
+}
+
+type recordingStmt struct {
+	conn  *recordingConn
+	query string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.conn.statements = append(s.conn.statements, s.query)
+	return driver.RowsAffected(0), nil
+}
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func TestScopeTenant(t *testing.T) {
+	domainA, domainB := uuid.New(), uuid.New()
+	ctxA := tenancy.WithDomain(context.Background(), domainA)
+	ctxB := tenancy.WithDomain(context.Background(), domainB)
+	system := tenancy.AsSystem(context.Background())
+	untenanted := context.Background()
+
+	inner := &recordingConn{}
+	conn := &tracedConn{Conn: inner, rowSecurity: true}
+	exec := func(ctx context.Context, query string) {
+		if _, err := conn.ExecContext(ctx, query, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exec(ctxA, "a1")
+	exec(ctxA, "a2")
+	exec(system, "sweep")
+	exec(untenanted, "by-id")
+	exec(tenancy.WithDomain(system, domainA), "a-in-sweep")
+	exec(tenancy.AsSystem(ctxA), "system-admin")
+	stmt, err := conn.PrepareContext(ctxB, "b1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec(ctxA, "a3")
+	if _, err := stmt.(driver.StmtExecContext).ExecContext(ctxB, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.ResetSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	exec(ctxB, "b2")
+
+	want := []string{
+		"set role=none domain=" + domainA.String(), "a1", "a2",
+		"set role=iam_rls_bypass domain=", "sweep",
+		"set role=none domain=", "by-id",
+		"set role=none domain=" + domainA.String(), "a-in-sweep",
+		"set role=iam_rls_bypass domain=", "system-admin",
+		"set role=none domain=" + domainB.String(),
+		"set role=none domain=" + domainA.String(), "a3",
+		"set role=none domain=" + domainB.String(), "b1",
+		"set role=none domain=" + domainB.String(), "b2",
+	}
+	if !reflect.DeepEqual(inner.statements, want) {
+		t.Errorf("statements =\n%q\nwant\n%q", inner.statements, want)
+	}
+}
+
+func TestScopeTenantDisabled(t *testing.T) {
+	inner := &recordingConn{}
+	conn := &tracedConn{Conn: inner}
+	if _, err := conn.ExecContext(tenancy.WithDomain(context.Background(), uuid.New()), "a1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a1"}; !reflect.DeepEqual(inner.statements, want) {
+		t.Errorf("statements = %q, want %q", inner.statements, want)
+	}
+}
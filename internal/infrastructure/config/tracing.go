@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+type TracingConfig struct {
+	Enabled     bool
+	Endpoint    string
+	Insecure    bool
+	ServiceName string
+	SampleRatio float64
+}
+
+func NewTracingConfig() *TracingConfig {
+	sampleRatio, err := strconv.ParseFloat(getEnv("OTEL_TRACES_SAMPLE_RATIO", "1"), 64)
+	if err != nil || sampleRatio < 0 || sampleRatio > 1 {
+		sampleRatio = 1
+	}
+
+	return &TracingConfig{
+		Enabled:     getEnv("OTEL_ENABLED", "false") == "true",
+		Endpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4318"),
+		Insecure:    getEnv("OTEL_EXPORTER_OTLP_INSECURE", "true") == "true",
+		ServiceName: getEnv("OTEL_SERVICE_NAME", "nusarithm-iam"),
+		SampleRatio: sampleRatio,
+	}
+}
+
+// InitTracer installs the global tracer provider with an OTLP/HTTP exporter. When tracing
+// is disabled the global no-op provider is left in place and the returned shutdown is a no-op.
+func (c *TracingConfig) InitTracer(ctx context.Context) (func(context.Context) error, error) {
+	if !c.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(c.Endpoint)}
+	if c.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(c.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+// UserExpiryConfig configures the background sweep that disables accounts past their end date.
+type UserExpiryConfig struct {
+	SweepInterval time.Duration // 0 disables the sweep
+}
+
+func NewUserExpiryConfig() (*UserExpiryConfig, error) {
+	var env envReader
+	cfg := &UserExpiryConfig{
+		SweepInterval: env.Duration("USER_EXPIRY_SWEEP_INTERVAL", 5*time.Minute),
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
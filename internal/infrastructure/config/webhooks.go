@@ -0,0 +1,32 @@
+package config
+
+import "time"
+
+// WebhookConfig configures the background delivery of domain events to webhooks. Failed
+// deliveries are retried after RetryBackoff, doubled after each further failure up to MaxBackoff.
+type WebhookConfig struct {
+	DeliveryInterval time.Duration // 0 disables delivery; events still queue up
+	BatchSize        int           // deliveries claimed per database and interval
+	Timeout          time.Duration
+	MaxAttempts      int
+	RetryBackoff     time.Duration
+	MaxBackoff       time.Duration
+	AllowHTTP        bool // accept plain http:// webhook URLs, for local development
+}
+
+func NewWebhookConfig() (*WebhookConfig, error) {
+	var env envReader
+	cfg := &WebhookConfig{
+		DeliveryInterval: env.Duration("WEBHOOK_DELIVERY_INTERVAL", 5*time.Second),
+		BatchSize:        max(env.Int("WEBHOOK_BATCH_SIZE", 50), 1),
+		Timeout:          env.Duration("WEBHOOK_TIMEOUT", 10*time.Second),
+		MaxAttempts:      max(env.Int("WEBHOOK_MAX_ATTEMPTS", 8), 1),
+		RetryBackoff:     env.Duration("WEBHOOK_RETRY_BACKOFF", 30*time.Second),
+		MaxBackoff:       env.Duration("WEBHOOK_MAX_BACKOFF", 6*time.Hour),
+		AllowHTTP:        getEnv("WEBHOOK_ALLOW_HTTP", "false") == "true",
+	}
+	if err := env.Err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,75 @@
+// Package faults injects artificial failures so integrators can test how their apps cope with a
+// slow or failing IAM. Nothing is injected until faults are set, which the admin API only allows
+// when FAULT_INJECTION_ENABLED is true. Faults apply to this instance only.
+package faults
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInjected is returned by operations failed on purpose.
+var ErrInjected = errors.New("injected fault")
+
+// Settings are the faults to inject. Rates are the share of calls affected, from 0 to 1.
+type Settings struct {
+	DBLatency                  time.Duration
+	DBLatencyRate              float64
+	TokenValidationFailureRate float64
+	DeliveryFailureRate        float64
+	ExpiresAt                  time.Time
+}
+
+var current atomic.Pointer[Settings]
+
+// Set replaces the injected faults; they stop at settings.ExpiresAt.
+func Set(settings Settings) {
+	current.Store(&settings)
+}
+
+// Clear stops injecting faults.
+func Clear() {
+	current.Store(nil)
+}
+
+// Active returns the faults being injected, or nil when there are none.
+func Active() *Settings {
+	settings := current.Load()
+	if settings == nil || !time.Now().Before(settings.ExpiresAt) {
+		return nil
+	}
+	return settings
+}
+
+// DelayDB sleeps for the injected database latency, or until ctx is done.
+func DelayDB(ctx context.Context) {
+	settings := Active()
+	if settings == nil || settings.DBLatency <= 0 || !hit(settings.DBLatencyRate) {
+		return
+	}
+	timer := time.NewTimer(settings.DBLatency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// FailTokenValidation reports whether this token validation should fail.
+func FailTokenValidation() bool {
+	settings := Active()
+	return settings != nil && hit(settings.TokenValidationFailureRate)
+}
+
+// FailDelivery reports whether this outbound delivery should fail.
+func FailDelivery() bool {
+	settings := Active()
+	return settings != nil && hit(settings.DeliveryFailureRate)
+}
+
+func hit(rate float64) bool {
+	return rate >= 1 || rate > 0 && rand.Float64() < rate
+}
@@ -0,0 +1,264 @@
+// Package jobs queues background work in the database and runs it on a pool of workers. Each kind
+// of job registers a handler and a retry policy; a failed run is retried with exponential backoff
+// until its attempts run out. Workers of every instance share the queue, and a job is held by one
+// worker at a time.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/metrics"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Handler runs one job. The context is cancelled when the job's lease runs out or the server
+// shuts down; a job cut off by shutdown is run again later.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// RetryPolicy sets how often a kind of job is run and how long to wait between failed runs: the
+// first retry waits Backoff, and each further one twice as long up to MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Delay returns the wait after the given number of failed attempts.
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	wait := p.Backoff
+	for i := 1; i < attempts && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, p.MaxBackoff)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that running the job again won't fix, such as a malformed payload, so
+// the job fails at once.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+type registration struct {
+	handler Handler
+	policy  RetryPolicy
+}
+
+// Queue stores jobs and runs those of the kinds registered on this instance.
+type Queue struct {
+	repo   repositories.JobRepository
+	config *config.JobsConfig
+
+	mu    sync.RWMutex
+	kinds map[string]registration
+
+	// wake starts an idle worker of this instance before its next poll
+	wake    chan struct{}
+	running sync.WaitGroup
+}
+
+func NewQueue(repo repositories.JobRepository, cfg *config.JobsConfig) *Queue {
+	return &Queue{repo: repo, config: cfg, kinds: make(map[string]registration), wake: make(chan struct{}, 1)}
+}
+
+// DefaultPolicy is the retry policy of the configuration.
+func (q *Queue) DefaultPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: q.config.MaxAttempts, Backoff: q.config.RetryBackoff, MaxBackoff: q.config.MaxBackoff}
+}
+
+// Register sets the handler of a kind of job, retried as policy says or by DefaultPolicy when it
+// is nil. Kinds are registered before Start.
+func (q *Queue) Register(kind string, policy *RetryPolicy, handler Handler) {
+	reg := registration{handler: handler, policy: q.DefaultPolicy()}
+	if policy != nil {
+		reg.policy = *policy
+		reg.policy.MaxAttempts = max(reg.policy.MaxAttempts, 1)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.kinds[kind] = reg
+}
+
+// Enqueue queues a job of a registered kind, due at once. The payload is stored as JSON.
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any) (*entities.Job, error) {
+	return q.EnqueueAt(ctx, kind, payload, time.Time{})
+}
+
+// EnqueueAt queues a job of a registered kind, due at runAt.
+func (q *Queue) EnqueueAt(ctx context.Context, kind string, payload any, runAt time.Time) (*entities.Job, error) {
+	reg, ok := q.registration(kind)
+	if !ok {
+		return nil, fmt.Errorf("unknown job kind %q", kind)
+	}
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s job: %w", kind, err)
+	}
+
+	job := &entities.Job{Kind: kind, Payload: payloadJSON, MaxAttempts: reg.policy.MaxAttempts, RunAt: runAt}
+	if err := q.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	if runAt.IsZero() {
+		q.notify()
+	}
+	return job, nil
+}
+
+// Retry gives a failed job a fresh set of attempts, due at once. It returns sql.ErrNoRows when
+// the job doesn't exist or hasn't failed.
+func (q *Queue) Retry(ctx context.Context, id uuid.UUID) (*entities.Job, error) {
+	job, err := q.repo.Requeue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	q.notify()
+	return job, nil
+}
+
+// notify wakes an idle worker of this instance for a job due now.
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the configured number of workers until ctx is cancelled; Wait blocks until they
+// have finished their current jobs.
+func (q *Queue) Start(ctx context.Context) {
+	for i := 0; i < q.config.Workers; i++ {
+		q.running.Add(1)
+		go q.work(ctx)
+	}
+}
+
+// Wait blocks until the workers started by Start have stopped.
+func (q *Queue) Wait() {
+	q.running.Wait()
+}
+
+func (q *Queue) work(ctx context.Context) {
+	defer q.running.Done()
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil && q.runNext(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// runNext claims and runs one due job, and reports whether there was one.
+func (q *Queue) runNext(ctx context.Context) bool {
+	kinds := q.registeredKinds()
+	if len(kinds) == 0 {
+		return false
+	}
+	job, err := q.repo.Claim(ctx, kinds, q.config.Lease)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to claim a job: %v", err)
+		}
+		return false
+	}
+	q.run(ctx, job)
+	return true
+}
+
+func (q *Queue) run(ctx context.Context, job *entities.Job) {
+	reg, _ := q.registration(job.Kind)
+	// Record the outcome even if shutdown cancelled ctx mid-run, so the lease isn't left to expire
+	record := context.WithoutCancel(ctx)
+
+	if job.Attempts > job.MaxAttempts {
+		// The worker of the last attempt stopped before recording its outcome
+		q.fail(record, job, errors.New("the worker running the job stopped"))
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, q.config.Lease)
+	err := call(runCtx, reg.handler, job.Payload)
+	cancel()
+
+	var permanent *permanentError
+	switch {
+	case err == nil:
+		if err := q.repo.Complete(record, job.ID); err != nil {
+			log.Printf("Failed to record job %s as succeeded: %v", job.ID, err)
+		}
+		metrics.RecordJobRun(job.Kind, "success")
+	case ctx.Err() != nil:
+		// Shutdown: run it again later without waiting out a backoff
+		if err := q.repo.Retry(record, job.ID, time.Now(), "interrupted by shutdown"); err != nil {
+			log.Printf("Failed to requeue job %s: %v", job.ID, err)
+		}
+	case errors.As(err, &permanent), job.Attempts >= job.MaxAttempts:
+		q.fail(record, job, err)
+	default:
+		log.Printf("Job %s (%s) failed on attempt %d of %d: %v", job.ID, job.Kind, job.Attempts, job.MaxAttempts, err)
+		if err := q.repo.Retry(record, job.ID, time.Now().Add(reg.policy.Delay(job.Attempts)), err.Error()); err != nil {
+			log.Printf("Failed to schedule the retry of job %s: %v", job.ID, err)
+		}
+		metrics.RecordJobRun(job.Kind, "retry")
+	}
+}
+
+func (q *Queue) fail(ctx context.Context, job *entities.Job, err error) {
+	log.Printf("Job %s (%s) failed after %d attempt(s): %v", job.ID, job.Kind, job.Attempts, err)
+	if err := q.repo.Fail(ctx, job.ID, err.Error()); err != nil {
+		log.Printf("Failed to record job %s as failed: %v", job.ID, err)
+	}
+	metrics.RecordJobRun(job.Kind, "failed")
+}
+
+// call runs the handler, turning a panic into an error so it doesn't take the worker down.
+func call(ctx context.Context, handler Handler, payload json.RawMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, payload)
+}
+
+func (q *Queue) registration(kind string) (registration, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	reg, ok := q.kinds[kind]
+	return reg, ok
+}
+
+func (q *Queue) registeredKinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return slices.Sorted(maps.Keys(q.kinds))
+}
@@ -0,0 +1,138 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"backend/internal/infrastructure/awssig"
+	"backend/internal/infrastructure/config"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// New returns the mailer of the configured driver. The log driver, and an SMTP configuration
+// without a host, write messages to the log for local development.
+func New(cfg *config.MailConfig) Mailer {
+	switch cfg.Driver {
+	case config.MailDriverSendGrid:
+		return &sendGridMailer{cfg: cfg, http: &http.Client{Timeout: cfg.APITimeout}}
+	case config.MailDriverSES:
+		signer := awssig.Signer{Service: "ses", Region: cfg.SESRegion, AccessKeyID: cfg.SESAccessKeyID, SecretAccessKey: cfg.SESSecretAccessKey, SessionToken: cfg.SESSessionToken}
+		return &sesMailer{cfg: cfg, signer: signer, http: &http.Client{Timeout: cfg.APITimeout}}
+	case config.MailDriverSMTP:
+		if cfg.SMTPHost != "" {
+			return &smtpMailer{cfg: cfg}
+		}
+	}
+	return &logMailer{}
+}
+
+// NewSMTP returns an SMTP mailer connecting through dialer, such as one refusing private
+// addresses for the servers of domains.
+func NewSMTP(cfg *config.MailConfig, dialer *net.Dialer) Mailer {
+	return &smtpMailer{cfg: cfg, dialer: dialer}
+}
+
+// Verify connects to the SMTP server through dialer, or a plain one if nil, upgrades to TLS when
+// offered and authenticates, without sending a message.
+func Verify(ctx context.Context, cfg *config.MailConfig, dialer *net.Dialer) error {
+	client, err := openSMTP(ctx, cfg, dialer)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Quit()
+}
+
+// openSMTP connects to the SMTP server, upgrades to TLS when offered and authenticates.
+func openSMTP(ctx context.Context, cfg *config.MailConfig, dialer *net.Dialer) (*smtp.Client, error) {
+	if dialer == nil {
+		dialer = &net.Dialer{Timeout: 10 * time.Second}
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	client, err := smtp.NewClient(conn, cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: cfg.SMTPHost}); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if cfg.SMTPUsername != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+	return client, nil
+}
+
+type smtpMailer struct {
+	cfg    *config.MailConfig
+	dialer *net.Dialer
+}
+
+func (m *smtpMailer) Send(ctx context.Context, to, subject, body string) error {
+	msg := strings.Join([]string{
+		"From: " + m.cfg.From,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	// From may carry a display name ("Acme <no-reply@acme.com>"); the envelope needs the bare address
+	sender := m.cfg.From
+	if addr, err := mail.ParseAddress(m.cfg.From); err == nil {
+		sender = addr.Address
+	}
+
+	client, err := openSMTP(ctx, m.cfg, m.dialer)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	defer client.Close()
+	if err := client.Mail(sender); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := w.Write([]byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return client.Quit()
+}
+
+type logMailer struct{}
+
+func (m *logMailer) Send(_ context.Context, to, subject, body string) error {
+	log.Printf("Email to %s: %s\n%s", to, subject, body)
+	return nil
+}
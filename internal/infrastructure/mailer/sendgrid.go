@@ -0,0 +1,79 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"backend/internal/infrastructure/config"
+)
+
+const sendGridURL = "https://api.sendgrid.com/v3/mail/send"
+
+// sendGridMailer sends through the SendGrid v3 mail send API.
+type sendGridMailer struct {
+	cfg  *config.MailConfig
+	http *http.Client
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridRequest struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+}
+
+func (m *sendGridMailer) Send(ctx context.Context, to, subject, body string) error {
+	payload := sendGridRequest{
+		Personalizations: []sendGridPersonalization{{To: []sendGridAddress{{Email: to}}}},
+		From:             sendGridAddress{Email: m.cfg.From},
+		Subject:          subject,
+		Content:          []sendGridContent{{Type: "text/plain", Value: body}},
+	}
+	// From may carry a display name ("Acme <no-reply@acme.com>"), which SendGrid takes separately
+	if addr, err := mail.ParseAddress(m.cfg.From); err == nil {
+		payload.From = sendGridAddress{Email: addr.Address, Name: addr.Name}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sendGridURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.cfg.SendGridAPIKey)
+
+	resp, err := m.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("sendgrid unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sendgrid returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/infrastructure/awssig"
+	"backend/internal/infrastructure/config"
+)
+
+// sesMailer sends through the Amazon SES v2 SendEmail API.
+type sesMailer struct {
+	cfg    *config.MailConfig
+	signer awssig.Signer
+	http   *http.Client
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesRequest struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				Text sesContent `json:"Text"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+}
+
+func (m *sesMailer) Send(ctx context.Context, to, subject, body string) error {
+	var payload sesRequest
+	payload.FromEmailAddress = m.cfg.From
+	payload.Destination.ToAddresses = []string{to}
+	payload.Content.Simple.Subject = sesContent{Data: subject, Charset: "UTF-8"}
+	payload.Content.Simple.Body.Text = sesContent{Data: body, Charset: "UTF-8"}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	endpoint := "https://email." + m.cfg.SESRegion + ".amazonaws.com/v2/email/outbound-emails"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	m.signer.Sign(req, data, time.Now())
+
+	resp, err := m.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("ses unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("ses returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "nusarithm_iam"
+
+var (
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	LoginAttemptsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_attempts_total",
+		Help:      "Total number of login attempts by result (success, failure).",
+	}, []string{"result"})
+
+	TokenValidationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_validations_total",
+		Help:      "Total number of token validations by result (valid, invalid).",
+	}, []string{"result"})
+
+	AuthzDecisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "authz_decisions_total",
+		Help:      "Total number of authorization checks by result (allowed, denied) and whether the decision was logged.",
+	}, []string{"result", "logged"})
+
+	CacheLookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Total number of cached lookups by cache and result (hit, miss).",
+	}, []string{"cache", "result"})
+
+	WebhookDeliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Total number of webhook delivery attempts by result (success, retry, failed).",
+	}, []string{"result"})
+
+	JobRunsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_runs_total",
+		Help:      "Total number of background job runs by kind and result (success, retry, failed).",
+	}, []string{"kind", "result"})
+
+	BrokerEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "broker_events_total",
+		Help:      "Total number of events relayed from the outbox to the message broker by result (published, retry).",
+	}, []string{"result"})
+
+	TelemetryRecordsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telemetry_records_total",
+		Help:      "Total number of login telemetry records written to the analytics sink by result (exported, failed).",
+	}, []string{"result"})
+
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by table and operation.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"table", "operation"})
+)
+
+// ObserveDBQuery records the elapsed time since start; use as `defer metrics.ObserveDBQuery("users", "get_by_id", time.Now())`.
+func ObserveDBQuery(table, operation string, start time.Time) {
+	DBQueryDuration.WithLabelValues(table, operation).Observe(time.Since(start).Seconds())
+}
+
+func RecordLogin(success bool) {
+	if success {
+		LoginAttemptsTotal.WithLabelValues("success").Inc()
+		return
+	}
+	LoginAttemptsTotal.WithLabelValues("failure").Inc()
+}
+
+func RecordTokenValidation(valid bool) {
+	if valid {
+		TokenValidationsTotal.WithLabelValues("valid").Inc()
+		return
+	}
+	TokenValidationsTotal.WithLabelValues("invalid").Inc()
+}
+
+func RecordCacheLookup(cache string, hit bool) {
+	if hit {
+		CacheLookupsTotal.WithLabelValues(cache, "hit").Inc()
+		return
+	}
+	CacheLookupsTotal.WithLabelValues(cache, "miss").Inc()
+}
+
+// RecordWebhookDelivery counts a delivery attempt; result is success, retry or failed (out of attempts).
+func RecordWebhookDelivery(result string) {
+	WebhookDeliveriesTotal.WithLabelValues(result).Inc()
+}
+
+// RecordJobRun counts a background job run; result is success, retry or failed (out of attempts).
+func RecordJobRun(kind, result string) {
+	JobRunsTotal.WithLabelValues(kind, result).Inc()
+}
+
+// RecordBrokerEvents counts events relayed to the broker; result is published or retry.
+func RecordBrokerEvents(result string, count int) {
+	BrokerEventsTotal.WithLabelValues(result).Add(float64(count))
+}
+
+// RecordTelemetryRecords counts login telemetry records; result is exported or failed.
+func RecordTelemetryRecords(result string, count int) {
+	TelemetryRecordsTotal.WithLabelValues(result).Add(float64(count))
+}
+
+func RecordAuthzDecision(allowed, logged bool) {
+	result := "denied"
+	if allowed {
+		result = "allowed"
+	}
+	AuthzDecisionsTotal.WithLabelValues(result, strconv.FormatBool(logged)).Inc()
+}
@@ -0,0 +1,82 @@
+// Package netguard keeps connections made on behalf of domains, such as webhook deliveries and
+// SMTP sessions with a domain's own server, from reaching the server's private network.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for addresses the guard refuses.
+var ErrForbiddenAddress = errors.New("address is private, loopback or link-local")
+
+// sharedAddressSpace is the carrier-grade NAT range, private in all but name.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// thisNetwork reaches the local host on most systems, as 0.0.0.0 does.
+var thisNetwork = netip.MustParsePrefix("0.0.0.0/8")
+
+// Guard refuses loopback, private, link-local, multicast and unspecified addresses, unless one
+// of its allowed networks holds them.
+type Guard struct {
+	allowed []netip.Prefix
+}
+
+func New(allowed []netip.Prefix) *Guard {
+	return &Guard{allowed: allowed}
+}
+
+// Check returns ErrForbiddenAddress when the guard refuses addr.
+func (g *Guard) Check(addr netip.Addr) error {
+	addr = addr.Unmap()
+	for _, prefix := range g.allowed {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsMulticast() || addr.IsUnspecified() ||
+		sharedAddressSpace.Contains(addr) || thisNetwork.Contains(addr) {
+		return fmt.Errorf("%s: %w", addr, ErrForbiddenAddress)
+	}
+	return nil
+}
+
+// Control is a net.Dialer Control function. It runs after DNS resolution on the address about to
+// be dialled, so a hostname that resolves to a public address when checked and to a private one
+// when connecting is still refused.
+func (g *Guard) Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%s: %w", address, ErrForbiddenAddress)
+	}
+	return g.Check(addrPort.Addr())
+}
+
+// Dialer returns a dialer refusing the addresses the guard refuses.
+func (g *Guard) Dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{Timeout: timeout, Control: g.Control}
+}
+
+// CheckHost resolves host, a hostname or IP address, and returns ErrForbiddenAddress when the
+// guard refuses any of its addresses. It's meant for validating a host when it's configured;
+// connections still need Dialer, as the host may resolve differently later.
+func (g *Guard) CheckHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return g.Check(addr)
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := g.Check(addr); err != nil {
+			return fmt.Errorf("%s resolves to %w", host, err)
+		}
+	}
+	return nil
+}
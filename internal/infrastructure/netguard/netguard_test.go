@@ -0,0 +1,63 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	guard := New([]netip.Prefix{netip.MustParsePrefix("10.1.0.0/16")})
+	tests := []struct {
+		addr    string
+		refused bool
+	}{
+		{"93.184.216.34", false},
+		{"2606:2800:220:1:248:1893:25c8:1946", false},
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.0.0.1", true},
+		{"172.16.5.4", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"0.1.2.3", true},
+		{"::", true},
+		{"224.0.0.1", true},
+		{"::ffff:127.0.0.1", true},
+		{"10.1.2.3", false},
+		{"::ffff:10.1.2.3", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			err := guard.Check(netip.MustParseAddr(tt.addr))
+			if refused := errors.Is(err, ErrForbiddenAddress); refused != tt.refused {
+				t.Errorf("Check(%s) = %v, want refused %v", tt.addr, err, tt.refused)
+			}
+		})
+	}
+}
+
+func TestControl(t *testing.T) {
+	guard := New(nil)
+	if err := guard.Control("tcp4", "127.0.0.1:443", nil); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Control(127.0.0.1:443) = %v, want ErrForbiddenAddress", err)
+	}
+	if err := guard.Control("tcp6", "[2606:2800:220:1:248:1893:25c8:1946]:443", nil); err != nil {
+		t.Errorf("Control of a public address = %v", err)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	guard := New(nil)
+	if err := guard.CheckHost(context.Background(), "localhost"); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("CheckHost(localhost) = %v, want ErrForbiddenAddress", err)
+	}
+	if err := guard.CheckHost(context.Background(), "192.168.0.10"); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("CheckHost(192.168.0.10) = %v, want ErrForbiddenAddress", err)
+	}
+}
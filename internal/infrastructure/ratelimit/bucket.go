@@ -0,0 +1,168 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit allows bursts of up to Requests and refills at Requests per Period. A zero Limit is
+// disabled.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit reads limits written as "<requests>/<period>", e.g. "10/1m"; "0" or an empty
+// string disables the limit.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return Limit{}, nil
+	}
+	requests, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q must look like 10/1m", value)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 0 {
+		return Limit{}, fmt.Errorf("limit %q has an invalid request count", value)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("limit %q has an invalid period", value)
+	}
+	return Limit{Requests: n, Period: d}, nil
+}
+
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "0"
+	}
+	return strconv.Itoa(l.Requests) + "/" + l.Period.String()
+}
+
+// rate is the refill speed in tokens per nanosecond.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / float64(l.Period)
+}
+
+// Decision is the outcome of taking a token from a bucket.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the bucket is full again
+	ResetAfter time.Duration
+	// RetryAfter is the time until the next request would be allowed; zero when allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets. Stores shared between instances, such as Redis, enforce a limit
+// across the whole deployment; the in-memory store enforces it per instance.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error)
+}
+
+// decide turns the tokens left after a take into a Decision.
+func decide(limit Limit, tokens float64, allowed bool) Decision {
+	rate := limit.rate()
+	decision := Decision{
+		Allowed:    allowed,
+		Limit:      limit.Requests,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: time.Duration(math.Ceil((float64(limit.Requests) - tokens) / rate)),
+	}
+	if !allowed {
+		decision.RetryAfter = time.Duration(math.Ceil((1 - tokens) / rate))
+	}
+	return decision
+}
+
+type bucket struct {
+	key     string
+	tokens  float64
+	updated time.Time
+	period  time.Duration // time to refill completely from empty
+}
+
+// MemoryStore keeps buckets in process memory, at most maxKeys of them. Buckets that have refilled
+// completely are dropped periodically, since a missing bucket is treated as full; when the store is
+// full anyway, the least recently used bucket is evicted, so a flood of new keys costs bounded
+// memory and only resets buckets that were idle the longest.
+type MemoryStore struct {
+	mu        sync.Mutex
+	maxKeys   int
+	buckets   map[string]*list.Element
+	recent    *list.List // most recently used first
+	lastSweep time.Time
+}
+
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	return &MemoryStore{maxKeys: maxKeys, buckets: make(map[string]*list.Element), recent: list.New()}
+}
+
+// memorySweepInterval bounds how often Take scans for refilled buckets.
+const memorySweepInterval = time.Minute
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= memorySweepInterval {
+		s.sweep(now)
+	}
+
+	var b *bucket
+	if element, ok := s.buckets[key]; ok {
+		s.recent.MoveToFront(element)
+		b = element.Value.(*bucket)
+	} else {
+		if s.maxKeys > 0 && len(s.buckets) >= s.maxKeys {
+			s.remove(s.recent.Back())
+		}
+		b = &bucket{key: key, tokens: float64(limit.Requests), updated: now, period: limit.Period}
+		s.buckets[key] = s.recent.PushFront(b)
+	}
+	b.tokens = math.Min(float64(limit.Requests), b.tokens+float64(now.Sub(b.updated))*limit.rate())
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return decide(limit, b.tokens, allowed), nil
+}
+
+// Len returns the number of buckets held.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
+
+// sweep drops buckets that have been idle long enough to refill completely.
+func (s *MemoryStore) sweep(now time.Time) {
+	s.lastSweep = now
+	for element := s.recent.Back(); element != nil; {
+		prev := element.Prev()
+		if b := element.Value.(*bucket); now.Sub(b.updated) >= b.period {
+			s.remove(element)
+		}
+		element = prev
+	}
+}
+
+func (s *MemoryStore) remove(element *list.Element) {
+	s.recent.Remove(element)
+	delete(s.buckets, element.Value.(*bucket).key)
+}
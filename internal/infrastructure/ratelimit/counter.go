@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Usage describes consumption of a single fixed window.
+type Usage struct {
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// Counter tracks hits in fixed, wall-clock aligned windows (e.g. per minute, per UTC day).
+// Counts live in process memory, so each instance enforces its own share of a limit.
+type Counter struct {
+	mu      sync.Mutex
+	period  time.Duration
+	windows map[string]*window
+}
+
+func NewCounter(period time.Duration) *Counter {
+	return &Counter{period: period, windows: make(map[string]*window)}
+}
+
+// Take records one hit for key unless it would exceed limit, and returns the resulting usage.
+func (c *Counter) Take(key string, limit int, now time.Time) (Usage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := c.current(key, now)
+	allowed := w.count < limit
+	if allowed {
+		w.count++
+	}
+	return c.usage(w, limit), allowed
+}
+
+// Peek returns the usage for key without recording a hit.
+func (c *Counter) Peek(key string, limit int, now time.Time) Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.usage(c.current(key, now), limit)
+}
+
+// Reset forgets all hits recorded for key.
+func (c *Counter) Reset(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.windows, key)
+}
+
+func (c *Counter) current(key string, now time.Time) *window {
+	start := now.UTC().Truncate(c.period)
+	w, ok := c.windows[key]
+	if !ok || !w.start.Equal(start) {
+		w = &window{start: start}
+		c.windows[key] = w
+	}
+	return w
+}
+
+func (c *Counter) usage(w *window, limit int) Usage {
+	remaining := limit - w.count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Usage{
+		Limit:     limit,
+		Used:      w.count,
+		Remaining: remaining,
+		ResetAt:   w.start.Add(c.period),
+	}
+}
@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// FallbackStore takes from primary, such as Redis, and from fallback while primary is failing, so
+// limits stay enforced per instance during an outage instead of being lifted.
+type FallbackStore struct {
+	primary  Store
+	fallback Store
+
+	mu        sync.Mutex
+	lastError time.Time // when a primary failure was last logged
+}
+
+func NewFallbackStore(primary, fallback Store) *FallbackStore {
+	return &FallbackStore{primary: primary, fallback: fallback}
+}
+
+// fallbackLogInterval bounds how often primary failures are logged during an outage.
+const fallbackLogInterval = time.Minute
+
+func (s *FallbackStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	decision, err := s.primary.Take(ctx, key, limit, now)
+	if err == nil {
+		return decision, nil
+	}
+
+	s.mu.Lock()
+	if now.Sub(s.lastError) >= fallbackLogInterval {
+		s.lastError = now
+		log.Printf("Rate limit store unavailable, limiting per instance: %v", err)
+	}
+	s.mu.Unlock()
+	return s.fallback.Take(ctx, key, limit, now)
+}
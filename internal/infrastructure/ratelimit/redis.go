@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills and takes from a bucket stored as a hash of tokens and last update time in
+// milliseconds. Buckets expire once they would be full again, since a missing bucket is full.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or capacity
+local updated = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - updated) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore keeps buckets in Redis so every instance enforces the same limits.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Decision, error) {
+	perMillisecond := limit.rate() * float64(time.Millisecond)
+	result, err := takeScript.Run(ctx, s.client, []string{s.prefix + key},
+		limit.Requests, strconv.FormatFloat(perMillisecond, 'g', -1, 64), now.UnixMilli()).Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+
+	allowed, _ := result[0].(int64)
+	tokensStr, _ := result[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return Decision{}, err
+	}
+	return decide(limit, tokens, allowed == 1), nil
+}
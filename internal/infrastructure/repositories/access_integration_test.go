@@ -0,0 +1,693 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+func TestRoleRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, member := createTestDomain(t, router)
+	roles := NewRoleRepository(router)
+
+	admin := &entities.Role{DomainID: domain.DomainID, RoleName: "admin", RoleClaims: map[string]interface{}{"admin": true}}
+	if err := roles.Create(ctx, admin); err != nil {
+		t.Fatal(err)
+	}
+	if err := roles.Create(ctx, &entities.Role{DomainID: domain.DomainID, RoleName: "admin", RoleClaims: map[string]interface{}{}}); UniqueViolation(err) != "roles_domain_id_role_name_key" {
+		t.Errorf("duplicate name: err = %v, want a violation of roles_domain_id_role_name_key", err)
+	}
+
+	if got, err := roles.GetByID(ctx, admin.ID); err != nil || got.RoleName != "admin" || got.DomainID != domain.DomainID {
+		t.Errorf("by ID = %+v, %v", got, err)
+	}
+	if _, err := roles.GetByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+	byIDs, err := roles.GetByIDs(ctx, []uuid.UUID{admin.ID, member.ID, uuid.New()})
+	if err != nil || len(byIDs) != 2 {
+		t.Errorf("by IDs = %d roles, %v; want the 2 that exist", len(byIDs), err)
+	}
+	if none, err := roles.GetByIDs(ctx, nil); err != nil || len(none) != 0 {
+		t.Errorf("by no IDs = %+v, %v", none, err)
+	}
+	if listed, err := roles.GetByDomainID(ctx, domain.DomainID); err != nil || len(listed) != 2 {
+		t.Errorf("by domain = %d roles, %v; want 2", len(listed), err)
+	}
+	var streamed []string
+	if err := roles.StreamByDomainID(ctx, domain.DomainID, func(role *entities.Role) error {
+		streamed = append(streamed, role.RoleName)
+		return nil
+	}); err != nil || len(streamed) != 2 {
+		t.Errorf("streamed = %v, %v; want 2 roles", streamed, err)
+	}
+	stop := errors.New("stop")
+	if err := roles.StreamByDomainID(ctx, domain.DomainID, func(*entities.Role) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("stopped stream: err = %v, want the callback's error", err)
+	}
+
+	admin.RoleName = "administrator"
+	if err := roles.Update(ctx, admin); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := roles.GetByID(ctx, admin.ID); err != nil || got.RoleName != "administrator" {
+		t.Errorf("renamed = %+v, %v", got, err)
+	}
+	if err := roles.Update(ctx, &entities.Role{ID: uuid.New(), DomainID: domain.DomainID, RoleName: "ghost"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("update unknown role: err = %v, want sql.ErrNoRows", err)
+	}
+	admin.RoleName = member.RoleName
+	if err := roles.Update(ctx, admin); UniqueViolation(err) != "roles_domain_id_role_name_key" {
+		t.Errorf("rename onto another role: err = %v, want a violation of roles_domain_id_role_name_key", err)
+	}
+
+	// Reassigning moves the holders of a role to its replacement
+	user := createTestUser(t, router, domain, admin)
+	group := &entities.Group{DomainID: domain.DomainID, Name: "ops"}
+	groups := NewGroupRepository(router)
+	if err := groups.Create(ctx, group); err != nil {
+		t.Fatal(err)
+	}
+	if err := groups.AddRole(ctx, domain.DomainID, group.ID, admin.ID); err != nil {
+		t.Fatal(err)
+	}
+	dependents, err := roles.CountDependents(ctx, domain.DomainID, admin.ID)
+	if err != nil || dependents.Users != 1 || dependents.Groups != 1 {
+		t.Errorf("dependents = %+v, %v; want 1 user, 1 group", dependents, err)
+	}
+	if err := roles.DeleteReassigning(ctx, domain.DomainID, admin.ID, member.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := roles.GetByID(ctx, admin.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("reassigned role: err = %v, want it deleted", err)
+	}
+	if got, err := NewUserRepository(router).GetByID(ctx, user.ID); err != nil || got.RoleID != member.ID {
+		t.Errorf("user after reassigning = %+v, %v; want role %s", got, err, member.ID)
+	}
+	if roleIDs, err := groups.ListRoleIDs(ctx, domain.DomainID, group.ID); err != nil || !reflect.DeepEqual(roleIDs, []uuid.UUID{member.ID}) {
+		t.Errorf("group roles after reassigning = %v, %v; want [%s]", roleIDs, err, member.ID)
+	}
+
+	spare := &entities.Role{DomainID: domain.DomainID, RoleName: "spare", RoleClaims: map[string]interface{}{}}
+	if err := roles.Create(ctx, spare); err != nil {
+		t.Fatal(err)
+	}
+	if err := roles.Delete(ctx, spare.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := roles.GetByID(ctx, spare.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestRolePagination(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, router)
+	roles := NewRoleRepository(router)
+	for _, name := range []string{"editor-a", "editor-b", "editor-c", "viewer"} {
+		if err := roles.Create(ctx, &entities.Role{DomainID: domain.DomainID, RoleName: name, RoleClaims: map[string]interface{}{}}); err != nil {
+			t.Fatal(err)
+		}
+		// Creation times order the cursor listing
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	tests := []struct {
+		name                      string
+		filter                    RoleListFilter
+		page, limit               int
+		wantNames                 []string
+		wantTotal, wantTotalPages int
+	}{
+		{"first page", RoleListFilter{}, 1, 2, []string{"editor-a", "editor-b"}, 5, 3},
+		{"last page", RoleListFilter{}, 3, 2, []string{"viewer"}, 5, 3},
+		{"past the end", RoleListFilter{}, 4, 2, []string{}, 5, 3},
+		{"search", RoleListFilter{Search: "EDITOR"}, 1, 10, []string{"editor-a", "editor-b", "editor-c"}, 3, 1},
+		{"descending", RoleListFilter{Sort: ListSort{Field: "role_name", Descending: true}}, 1, 2, []string{"viewer", "member"}, 5, 3},
+		{"no match", RoleListFilter{Search: "nobody"}, 1, 10, []string{}, 0, 0},
+	}
+	for _, tt := range tests {
+		result, err := roles.ListWithPagination(ctx, tt.filter, domain.DomainID, tt.page, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, role := range result.Roles {
+			names = append(names, role.RoleName)
+		}
+		if !reflect.DeepEqual(names, tt.wantNames) || result.Total != tt.wantTotal || result.TotalPages != tt.wantTotalPages {
+			t.Errorf("%s: %v, total %d, %d pages; want %v, total %d, %d pages",
+				tt.name, names, result.Total, result.TotalPages, tt.wantNames, tt.wantTotal, tt.wantTotalPages)
+		}
+	}
+
+	var walked []string
+	var after *ListCursor
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("the cursor never reached the last page")
+		}
+		page, err := roles.ListAfter(ctx, RoleListFilter{Search: "editor"}, domain.DomainID, after, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, role := range page.Roles {
+			walked = append(walked, role.RoleName)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if after, err = ParseListCursor(page.NextCursor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"editor-a", "editor-b", "editor-c"}; !reflect.DeepEqual(walked, want) {
+		t.Errorf("walked %v, want %v", walked, want)
+	}
+}
+
+func TestPermissionRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	permissions := NewPermissionRepository(router)
+
+	read := &entities.Permission{DomainID: domain.DomainID, Name: "documents:read", Resource: "documents", Action: "read"}
+	write := &entities.Permission{DomainID: domain.DomainID, Name: "documents:write", Resource: "documents", Action: "write"}
+	for _, permission := range []*entities.Permission{read, write} {
+		if err := permissions.Create(ctx, permission); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := permissions.Create(ctx, &entities.Permission{DomainID: domain.DomainID, Name: "documents:read", Resource: "documents", Action: "read"}); UniqueViolation(err) != "permissions_domain_id_name_key" {
+		t.Errorf("duplicate name: err = %v, want a violation of permissions_domain_id_name_key", err)
+	}
+
+	if got, err := permissions.GetByID(ctx, read.ID); err != nil || got.Name != "documents:read" {
+		t.Errorf("by ID = %+v, %v", got, err)
+	}
+	if _, err := permissions.GetByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+	if got, err := permissions.GetByName(ctx, domain.DomainID, "documents:write"); err != nil || got.ID != write.ID {
+		t.Errorf("by name = %+v, %v", got, err)
+	}
+	if _, err := permissions.GetByName(ctx, domain.DomainID, "documents:delete"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown name: err = %v, want sql.ErrNoRows", err)
+	}
+	if listed, err := permissions.GetByDomainID(ctx, domain.DomainID); err != nil || len(listed) != 2 {
+		t.Errorf("by domain = %d permissions, %v; want 2", len(listed), err)
+	}
+
+	// Assigning twice is a no-op
+	for i := 0; i < 2; i++ {
+		if err := permissions.AssignToRole(ctx, domain.DomainID, role.ID, read.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assigned, err := permissions.GetByRoleID(ctx, domain.DomainID, role.ID)
+	if err != nil || len(assigned) != 1 || assigned[0].ID != read.ID {
+		t.Errorf("assigned = %+v, %v; want documents:read", assigned, err)
+	}
+	if err := permissions.RevokeFromRole(ctx, domain.DomainID, role.ID, read.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := permissions.RevokeFromRole(ctx, domain.DomainID, role.ID, read.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("revoked twice: err = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := permissions.Delete(ctx, write.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := permissions.GetByID(ctx, write.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestGroupRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	user := createTestUser(t, router, domain, role)
+	groups := NewGroupRepository(router)
+
+	group := &entities.Group{DomainID: domain.DomainID, Name: "engineering", Description: "Engineers"}
+	if err := groups.Create(ctx, group); err != nil {
+		t.Fatal(err)
+	}
+	if err := groups.Create(ctx, &entities.Group{DomainID: domain.DomainID, Name: "engineering"}); UniqueViolation(err) != "groups_domain_id_name_key" {
+		t.Errorf("duplicate name: err = %v, want a violation of groups_domain_id_name_key", err)
+	}
+	if got, err := groups.GetByID(ctx, group.ID); err != nil || got.Description != "Engineers" {
+		t.Errorf("by ID = %+v, %v", got, err)
+	}
+	if _, err := groups.GetByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+	if got, err := groups.GetByName(ctx, domain.DomainID, "engineering"); err != nil || got.ID != group.ID {
+		t.Errorf("by name = %+v, %v", got, err)
+	}
+	if _, err := groups.GetByName(ctx, domain.DomainID, "sales"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown name: err = %v, want sql.ErrNoRows", err)
+	}
+
+	group.Description = "Platform and product engineers"
+	if err := groups.Update(ctx, group); err != nil {
+		t.Fatal(err)
+	}
+	if listed, err := groups.GetByDomainID(ctx, domain.DomainID); err != nil || len(listed) != 1 || listed[0].Description != group.Description {
+		t.Errorf("by domain = %+v, %v", listed, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := groups.AddMember(ctx, domain.DomainID, group.ID, user.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if members, err := groups.ListMembers(ctx, domain.DomainID, group.ID); err != nil || len(members) != 1 || members[0].ID != user.ID {
+		t.Errorf("members = %+v, %v; want the user once", members, err)
+	}
+	if userGroups, err := groups.GetByUserID(ctx, domain.DomainID, user.ID); err != nil || len(userGroups) != 1 {
+		t.Errorf("user's groups = %+v, %v; want 1", userGroups, err)
+	}
+
+	granted := &entities.Role{DomainID: domain.DomainID, RoleName: "deployer", RoleClaims: map[string]interface{}{}}
+	if err := NewRoleRepository(router).Create(ctx, granted); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := groups.AddRole(ctx, domain.DomainID, group.ID, granted.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inherited, err := groups.ListInheritedRoleIDs(ctx, domain.DomainID, user.ID); err != nil || !reflect.DeepEqual(inherited, []uuid.UUID{granted.ID}) {
+		t.Errorf("inherited roles = %v, %v; want [%s]", inherited, err, granted.ID)
+	}
+	if err := groups.RemoveRole(ctx, domain.DomainID, group.ID, granted.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := groups.RemoveRole(ctx, domain.DomainID, group.ID, granted.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("role removed twice: err = %v, want sql.ErrNoRows", err)
+	}
+	if err := groups.RemoveMember(ctx, domain.DomainID, group.ID, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := groups.RemoveMember(ctx, domain.DomainID, group.ID, user.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("member removed twice: err = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := groups.Delete(ctx, group.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := groups.GetByID(ctx, group.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestOrgUnitRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, router)
+	units := NewOrgUnitRepository(router)
+
+	root := &entities.OrgUnit{DomainID: domain.DomainID, Name: "Company"}
+	if err := units.Create(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	engineering := &entities.OrgUnit{DomainID: domain.DomainID, ParentID: &root.ID, Name: "Engineering"}
+	if err := units.Create(ctx, engineering); err != nil {
+		t.Fatal(err)
+	}
+	platform := &entities.OrgUnit{DomainID: domain.DomainID, ParentID: &engineering.ID, Name: "Platform"}
+	sales := &entities.OrgUnit{DomainID: domain.DomainID, ParentID: &root.ID, Name: "Sales"}
+	for _, unit := range []*entities.OrgUnit{platform, sales} {
+		if err := units.Create(ctx, unit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := units.Create(ctx, &entities.OrgUnit{DomainID: domain.DomainID, Name: "Sales"}); UniqueViolation(err) != "org_units_domain_id_name_key" {
+		t.Errorf("duplicate name: err = %v, want a violation of org_units_domain_id_name_key", err)
+	}
+
+	if got, err := units.GetByID(ctx, platform.ID); err != nil || got.ParentID == nil || *got.ParentID != engineering.ID {
+		t.Errorf("by ID = %+v, %v", got, err)
+	}
+	if _, err := units.GetByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+	if got, err := units.GetByName(ctx, domain.DomainID, "Company"); err != nil || got.ParentID != nil {
+		t.Errorf("root by name = %+v, %v", got, err)
+	}
+	if listed, err := units.GetByDomainID(ctx, domain.DomainID); err != nil || len(listed) != 4 {
+		t.Errorf("by domain = %d units, %v; want 4", len(listed), err)
+	}
+	subtree, err := units.ListSubtree(ctx, domain.DomainID, engineering.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, unit := range subtree {
+		names = append(names, unit.Name)
+	}
+	if want := []string{"Engineering", "Platform"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("subtree = %v, want %v", names, want)
+	}
+	for id, want := range map[uuid.UUID]bool{engineering.ID: true, platform.ID: true, sales.ID: false, root.ID: false} {
+		if in, err := units.InSubtree(ctx, domain.DomainID, engineering.ID, id); err != nil || in != want {
+			t.Errorf("%s in the engineering subtree = %v, %v; want %v", id, in, err, want)
+		}
+	}
+	if children, err := units.CountChildren(ctx, domain.DomainID, root.ID); err != nil || children != 2 {
+		t.Errorf("children = %d, %v; want 2", children, err)
+	}
+
+	// Moving a unit takes its subtree along
+	platform.ParentID = &sales.ID
+	if err := units.Update(ctx, platform); err != nil {
+		t.Fatal(err)
+	}
+	if in, err := units.InSubtree(ctx, domain.DomainID, sales.ID, platform.ID); err != nil || !in {
+		t.Errorf("moved unit under sales = %v, %v", in, err)
+	}
+	if err := units.Update(ctx, &entities.OrgUnit{ID: uuid.New(), DomainID: domain.DomainID, Name: "Ghost"}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("update unknown unit: err = %v, want sql.ErrNoRows", err)
+	}
+
+	// A unit with children can't be deleted
+	if err := units.Delete(ctx, domain.DomainID, sales.ID); err == nil {
+		t.Error("deleted a unit that has children")
+	}
+	if err := units.Delete(ctx, domain.DomainID, platform.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := units.Delete(ctx, domain.DomainID, platform.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted twice: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestPolicyRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, router)
+	policies := NewPolicyRepository(router)
+
+	policy := &entities.Policy{DomainID: domain.DomainID, Name: "office-hours",
+		Document: json.RawMessage(`{"effect": "deny", "actions": ["documents:write"], "conditions": {"hours": [9, 17]}}`)}
+	if err := policies.Create(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	if err := policies.Create(ctx, &entities.Policy{DomainID: domain.DomainID, Name: "office-hours", Document: json.RawMessage(`{}`)}); UniqueViolation(err) != "policies_domain_id_name_key" {
+		t.Errorf("duplicate name: err = %v, want a violation of policies_domain_id_name_key", err)
+	}
+
+	got, err := policies.GetByID(ctx, policy.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotDocument, wantDocument interface{}
+	if err := json.Unmarshal(got.Document, &gotDocument); err != nil {
+		t.Fatal(err)
+	}
+	_ = json.Unmarshal(policy.Document, &wantDocument)
+	if !reflect.DeepEqual(gotDocument, wantDocument) {
+		t.Errorf("document = %s, want %s", got.Document, policy.Document)
+	}
+	if _, err := policies.GetByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+	if got, err := policies.GetByName(ctx, domain.DomainID, "office-hours"); err != nil || got.ID != policy.ID {
+		t.Errorf("by name = %+v, %v", got, err)
+	}
+	if _, err := policies.GetByName(ctx, domain.DomainID, "weekends"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown name: err = %v, want sql.ErrNoRows", err)
+	}
+
+	policy.Description = "No writes after hours"
+	if err := policies.Update(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	if listed, err := policies.GetByDomainID(ctx, domain.DomainID); err != nil || len(listed) != 1 || listed[0].Description != policy.Description {
+		t.Errorf("by domain = %+v, %v", listed, err)
+	}
+
+	if err := policies.Delete(ctx, policy.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := policies.GetByID(ctx, policy.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestRoleRevisionRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	revisions := NewRoleRevisionRepository(router)
+
+	empty, err := revisions.ListByRole(ctx, domain.DomainID, role.ID, 1, 10)
+	if err != nil || empty.Total != 0 || empty.Revisions == nil || len(empty.Revisions) != 0 {
+		t.Errorf("no revisions = %+v, %v; want an empty page", empty, err)
+	}
+	for i := 1; i <= 3; i++ {
+		revision := &entities.RoleRevision{DomainID: domain.DomainID, RoleID: role.ID, OldRoleName: "member", NewRoleName: "member",
+			OldClaims: map[string]interface{}{"level": float64(i - 1)}, NewClaims: map[string]interface{}{"level": float64(i)},
+			Diff: entities.RoleClaimsDiff{Changed: map[string]entities.ClaimChange{"level": {Old: float64(i - 1), New: float64(i)}}}}
+		if i == 3 {
+			rollbackOf := 2
+			revision.RollbackOf = &rollbackOf
+		}
+		if err := revisions.Create(ctx, revision); err != nil {
+			t.Fatal(err)
+		}
+		if revision.Revision != i {
+			t.Errorf("revision number = %d, want %d", revision.Revision, i)
+		}
+	}
+
+	got, err := revisions.GetByRevision(ctx, domain.DomainID, role.ID, 3)
+	if err != nil || got.RollbackOf == nil || *got.RollbackOf != 2 || got.NewClaims["level"] != float64(3) || got.Diff.Changed["level"].New != float64(3) {
+		t.Errorf("revision 3 = %+v, %v", got, err)
+	}
+	if _, err := revisions.GetByRevision(ctx, domain.DomainID, role.ID, 4); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown revision: err = %v, want sql.ErrNoRows", err)
+	}
+
+	tests := []struct {
+		page, limit   int
+		wantRevisions []int
+	}{
+		{1, 2, []int{3, 2}},
+		{2, 2, []int{1}},
+		{3, 2, []int{}},
+	}
+	for _, tt := range tests {
+		result, err := revisions.ListByRole(ctx, domain.DomainID, role.ID, tt.page, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		numbers := []int{}
+		for _, revision := range result.Revisions {
+			numbers = append(numbers, revision.Revision)
+		}
+		if !reflect.DeepEqual(numbers, tt.wantRevisions) || result.Total != 3 || result.TotalPages != 2 {
+			t.Errorf("page %d: %v, total %d, %d pages; want %v, total 3, 2 pages", tt.page, numbers, result.Total, result.TotalPages, tt.wantRevisions)
+		}
+	}
+}
+
+func TestRoleTemplateRepository(t *testing.T) {
+	templates := NewRoleTemplateRepository(integrationDB(t))
+	ctx := context.Background()
+
+	template := &entities.RoleTemplate{Name: "editor-" + uuid.NewString()[:8], Description: "Edits documents",
+		RoleClaims: map[string]interface{}{"editor": true}, Permissions: []string{"documents:read", "documents:write"}}
+	if err := templates.Create(ctx, template); err != nil {
+		t.Fatal(err)
+	}
+	if err := templates.Create(ctx, &entities.RoleTemplate{Name: template.Name, RoleClaims: map[string]interface{}{}, Permissions: []string{}}); UniqueViolation(err) != "idx_role_templates_name" {
+		t.Errorf("duplicate name: err = %v, want a violation of idx_role_templates_name", err)
+	}
+
+	got, err := templates.GetByID(ctx, template.ID)
+	if err != nil || !reflect.DeepEqual(got.Permissions, template.Permissions) || !reflect.DeepEqual(got.RoleClaims, template.RoleClaims) {
+		t.Fatalf("by ID = %+v, %v; want %+v", got, err, template)
+	}
+	if _, err := templates.GetByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+	listed, err := templates.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, candidate := range listed {
+		found = found || candidate.ID == template.ID
+	}
+	if !found {
+		t.Errorf("list = %d templates without %s", len(listed), template.Name)
+	}
+
+	template.Permissions = []string{"documents:read"}
+	if err := templates.Update(ctx, template); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := templates.GetByID(ctx, template.ID); err != nil || !reflect.DeepEqual(got.Permissions, []string{"documents:read"}) {
+		t.Errorf("updated = %+v, %v", got, err)
+	}
+	if err := templates.Update(ctx, &entities.RoleTemplate{ID: uuid.New(), Name: "ghost-" + uuid.NewString()[:8], RoleClaims: map[string]interface{}{}}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("update unknown template: err = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := templates.Delete(ctx, template.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := templates.Delete(ctx, template.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted twice: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestAPIKeyRepository(t *testing.T) {
+	db := integrationDB(t)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, NewShardRouter(db, nil, nil))
+	keys := NewAPIKeyRepository(db)
+
+	key := &entities.APIKey{DomainID: domain.DomainID, Name: "ci", Prefix: "nrm_abc", KeyHash: testHash()}
+	if err := keys.Create(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.Create(ctx, &entities.APIKey{DomainID: domain.DomainID, Name: "copy", Prefix: "nrm_abc", KeyHash: key.KeyHash}); UniqueViolation(err) != "api_keys_key_hash_key" {
+		t.Errorf("duplicate hash: err = %v, want a violation of api_keys_key_hash_key", err)
+	}
+
+	if got, err := keys.GetByID(ctx, key.ID); err != nil || got.Name != "ci" || got.RateLimitPerMinute != nil || got.RevokedAt != nil {
+		t.Errorf("by ID = %+v, %v", got, err)
+	}
+	if _, err := keys.GetByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+	if got, err := keys.GetByHash(ctx, key.KeyHash); err != nil || got.ID != key.ID {
+		t.Errorf("by hash = %+v, %v", got, err)
+	}
+	if _, err := keys.GetByHash(ctx, testHash()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown hash: err = %v, want sql.ErrNoRows", err)
+	}
+
+	rate, quota := 120, 50000
+	if err := keys.UpdateLimits(ctx, key.ID, &rate, &quota); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := keys.GetByID(ctx, key.ID); err != nil || got.RateLimitPerMinute == nil || *got.RateLimitPerMinute != 120 || *got.DailyQuota != 50000 {
+		t.Errorf("limited = %+v, %v", got, err)
+	}
+	if err := keys.UpdateLimits(ctx, key.ID, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := keys.GetByID(ctx, key.ID); err != nil || got.RateLimitPerMinute != nil || got.DailyQuota != nil {
+		t.Errorf("back to the defaults = %+v, %v", got, err)
+	}
+
+	if err := keys.Revoke(ctx, key.ID); err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := keys.GetByID(ctx, key.ID)
+	if err != nil || revoked.RevokedAt == nil {
+		t.Fatalf("revoked = %+v, %v", revoked, err)
+	}
+	// Revoking again keeps the first revocation time
+	if err := keys.Revoke(ctx, key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := keys.GetByID(ctx, key.ID); err != nil || !again.RevokedAt.Equal(*revoked.RevokedAt) {
+		t.Errorf("revoked again = %+v, %v", again, err)
+	}
+	if listed, err := keys.GetByDomainID(ctx, domain.DomainID); err != nil || len(listed) != 1 {
+		t.Errorf("by domain = %d keys, %v; want 1", len(listed), err)
+	}
+}
+
+func TestAuthzDecisionRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	direct := createTestUser(t, router, domain, role)
+	other := &entities.Role{DomainID: domain.DomainID, RoleName: "other", RoleClaims: map[string]interface{}{}}
+	if err := NewRoleRepository(router).Create(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	inheriting := createTestUser(t, router, domain, other)
+	outsider := createTestUser(t, router, domain, other)
+
+	// inheriting holds role through a group
+	groups := NewGroupRepository(router)
+	group := &entities.Group{DomainID: domain.DomainID, Name: "granting"}
+	if err := groups.Create(ctx, group); err != nil {
+		t.Fatal(err)
+	}
+	if err := groups.AddMember(ctx, domain.DomainID, group.ID, inheriting.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := groups.AddRole(ctx, domain.DomainID, group.ID, role.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	decisions := NewAuthzDecisionRepository(router)
+	since := time.Now().Add(-time.Minute)
+	for _, decision := range []*entities.AuthzDecision{
+		{UserID: direct.ID, RoleID: role.ID, Resource: "documents", Action: "read", Allowed: true},
+		{UserID: direct.ID, RoleID: role.ID, Resource: "documents", Action: "delete", Allowed: false},
+		{UserID: inheriting.ID, RoleID: other.ID, Resource: "reports", Action: "read", Allowed: true},
+		{UserID: outsider.ID, RoleID: other.ID, Resource: "documents", Action: "read", Allowed: true},
+	} {
+		decision.DomainID = domain.DomainID
+		if err := decisions.Create(ctx, decision); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	allowed, err := decisions.ListAllowedByRole(ctx, domain.DomainID, role.ID, since, 10)
+	if err != nil || len(allowed) != 2 {
+		t.Errorf("allowed by role = %d decisions, %v; want the direct and inherited ones", len(allowed), err)
+	}
+	if later, err := decisions.ListAllowedByRole(ctx, domain.DomainID, role.ID, time.Now().Add(time.Minute), 10); err != nil || len(later) != 0 {
+		t.Errorf("allowed since later = %d decisions, %v; want none", len(later), err)
+	}
+
+	denied := false
+	tests := []struct {
+		name        string
+		filter      AuthzDecisionFilter
+		page, limit int
+		wantCount   int
+		wantTotal   int
+	}{
+		{"all", AuthzDecisionFilter{}, 1, 10, 4, 4},
+		{"paged", AuthzDecisionFilter{}, 2, 3, 1, 4},
+		{"user", AuthzDecisionFilter{UserID: direct.ID}, 1, 10, 2, 2},
+		{"resource and action", AuthzDecisionFilter{Resource: "documents", Action: "read"}, 1, 10, 2, 2},
+		{"denied", AuthzDecisionFilter{Allowed: &denied}, 1, 10, 1, 1},
+	}
+	for _, tt := range tests {
+		tt.filter.DomainID = domain.DomainID
+		result, err := decisions.ListWithPagination(ctx, tt.filter, tt.page, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Decisions) != tt.wantCount || result.Total != tt.wantTotal {
+			t.Errorf("%s: %d decisions, total %d; want %d, total %d", tt.name, len(result.Decisions), result.Total, tt.wantCount, tt.wantTotal)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error)
+	GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.APIKey, error)
+	Create(ctx context.Context, key *entities.APIKey) error
+	UpdateLimits(ctx context.Context, id uuid.UUID, ratePerMinute, dailyQuota *int) error
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+const apiKeyColumns = "id, domain_id, name, key_prefix, key_hash, rate_limit_per_minute, daily_quota, created_at, updated_at, revoked_at"
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.APIKey, error) {
+	ctx, end := observe(ctx, "api_keys", "get_by_id")
+	defer end()
+
+	return scanAPIKey(r.db.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE id = $1", id))
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	ctx, end := observe(ctx, "api_keys", "get_by_hash")
+	defer end()
+
+	return scanAPIKey(r.db.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = $1", keyHash))
+}
+
+func (r *apiKeyRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.APIKey, error) {
+	ctx, end := observe(ctx, "api_keys", "get_by_domain_id")
+	defer end()
+
+	rows, err := r.db.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE domain_id = $1 ORDER BY created_at DESC", domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*entities.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	ctx, end := observe(ctx, "api_keys", "create")
+	defer end()
+
+	key.ID = uuid.New()
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO api_keys (id, domain_id, name, key_prefix, key_hash, rate_limit_per_minute, daily_quota)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING created_at, updated_at`,
+		key.ID, key.DomainID, key.Name, key.Prefix, key.KeyHash, key.RateLimitPerMinute, key.DailyQuota).Scan(
+		&key.CreatedAt, &key.UpdatedAt)
+}
+
+func (r *apiKeyRepository) UpdateLimits(ctx context.Context, id uuid.UUID, ratePerMinute, dailyQuota *int) error {
+	ctx, end := observe(ctx, "api_keys", "update_limits")
+	defer end()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE api_keys SET rate_limit_per_minute = $1, daily_quota = $2, updated_at = NOW()
+		WHERE id = $3`, ratePerMinute, dailyQuota, id)
+	return err
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "api_keys", "revoke")
+	defer end()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE api_keys SET revoked_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL`, id)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner) (*entities.APIKey, error) {
+	var key entities.APIKey
+	var ratePerMinute, dailyQuota sql.NullInt64
+	var revokedAt sql.NullTime
+
+	err := row.Scan(&key.ID, &key.DomainID, &key.Name, &key.Prefix, &key.KeyHash,
+		&ratePerMinute, &dailyQuota, &key.CreatedAt, &key.UpdatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if ratePerMinute.Valid {
+		value := int(ratePerMinute.Int64)
+		key.RateLimitPerMinute = &value
+	}
+	if dailyQuota.Valid {
+		value := int(dailyQuota.Int64)
+		key.DailyQuota = &value
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return &key, nil
+}
@@ -0,0 +1,417 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// createTestUser creates a user of the domain with the role.
+func createTestUser(t *testing.T, router *ShardRouter, domain *entities.Domain, role *entities.Role) *entities.User {
+	t.Helper()
+	name := "user-" + uuid.NewString()[:8]
+	user := &entities.User{DomainID: domain.DomainID, RoleID: role.ID, FirstName: "Test", LastName: "User",
+		Username: name, Email: name + "@example.com", PasswordHash: "hash"}
+	if err := NewUserRepository(router).Create(context.Background(), user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// testHash returns a value for a unique hash column.
+func testHash() string {
+	return uuid.NewString()
+}
+
+func TestLoginCodeRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	user := createTestUser(t, router, domain, role)
+	codes := NewLoginCodeRepository(router)
+
+	if _, err := codes.GetLatestActive(ctx, domain.DomainID, user.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("no codes: err = %v, want sql.ErrNoRows", err)
+	}
+	expired := &entities.LoginCode{DomainID: domain.DomainID, UserID: user.ID, CodeHash: "old", TokenHash: testHash(),
+		ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := codes.Create(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+	code := &entities.LoginCode{DomainID: domain.DomainID, UserID: user.ID, CodeHash: "new", TokenHash: testHash(),
+		ExpiresAt: time.Now().Add(10 * time.Minute)}
+	if err := codes.Create(ctx, code); err != nil {
+		t.Fatal(err)
+	}
+	if err := codes.Create(ctx, &entities.LoginCode{DomainID: domain.DomainID, UserID: user.ID, CodeHash: "dup",
+		TokenHash: code.TokenHash, ExpiresAt: code.ExpiresAt}); UniqueViolation(err) != "login_codes_token_hash_key" {
+		t.Errorf("duplicate token: err = %v, want a violation of login_codes_token_hash_key", err)
+	}
+
+	active, err := codes.GetLatestActive(ctx, domain.DomainID, user.ID)
+	if err != nil || active.ID != code.ID {
+		t.Fatalf("latest active = %+v, %v; want %s", active, err, code.ID)
+	}
+	if err := codes.IncrementAttempts(ctx, domain.DomainID, code.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, err := codes.GetByTokenHash(ctx, domain.DomainID, code.TokenHash)
+	if err != nil || got.Attempts != 1 || got.ConsumedAt != nil {
+		t.Errorf("by token = %+v, %v; want 1 attempt, unconsumed", got, err)
+	}
+	if _, err := codes.GetByTokenHash(ctx, domain.DomainID, testHash()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown token: err = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := codes.Consume(ctx, domain.DomainID, code.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := codes.Consume(ctx, domain.DomainID, code.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("consumed twice: err = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := codes.GetLatestActive(ctx, domain.DomainID, user.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("after consuming: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestLoginHistoryRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	user := createTestUser(t, router, domain, role)
+	history := NewLoginHistoryRepository(router)
+
+	empty, err := history.ListByUser(ctx, domain.DomainID, user.ID, 1, 10)
+	if err != nil || empty.Total != 0 || empty.Logins == nil || len(empty.Logins) != 0 {
+		t.Errorf("no logins = %+v, %v; want an empty page", empty, err)
+	}
+	for i, success := range []bool{true, false, true} {
+		entry := &entities.LoginHistoryEntry{DomainID: domain.DomainID, UserID: user.ID, Success: success,
+			Method: "password", IP: "203.0.113.1", UserAgent: "test", Reason: map[bool]string{false: "invalid_credentials"}[success]}
+		if err := history.Create(ctx, entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry.CreatedAt.IsZero() {
+			t.Errorf("entry %d has no created_at", i)
+		}
+	}
+
+	tests := []struct {
+		page, limit, wantLogins, wantPages int
+	}{
+		{1, 2, 2, 2},
+		{2, 2, 1, 2},
+		{3, 2, 0, 2},
+		{1, 10, 3, 1},
+	}
+	for _, tt := range tests {
+		result, err := history.ListByUser(ctx, domain.DomainID, user.ID, tt.page, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Logins) != tt.wantLogins || result.Total != 3 || result.TotalPages != tt.wantPages {
+			t.Errorf("page %d of %d: %d logins, total %d, %d pages; want %d logins, total 3, %d pages",
+				tt.page, tt.limit, len(result.Logins), result.Total, result.TotalPages, tt.wantLogins, tt.wantPages)
+		}
+	}
+}
+
+func TestPasswordHistoryRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	user := createTestUser(t, router, domain, role)
+	history := NewPasswordHistoryRepository(router)
+
+	if hashes, err := history.Recent(ctx, domain.DomainID, user.ID, 5); err != nil || len(hashes) != 0 {
+		t.Errorf("no history = %v, %v", hashes, err)
+	}
+	for _, hash := range []string{"h1", "h2", "h3"} {
+		if err := history.Add(ctx, domain.DomainID, user.ID, hash, 2); err != nil {
+			t.Fatal(err)
+		}
+		// Entries are ordered by when they were added
+		time.Sleep(2 * time.Millisecond)
+	}
+	hashes, err := history.Recent(ctx, domain.DomainID, user.ID, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"h3", "h2"}; !reflect.DeepEqual(hashes, want) {
+		t.Errorf("recent = %v, want %v", hashes, want)
+	}
+	if hashes, err := history.Recent(ctx, domain.DomainID, user.ID, 1); err != nil || !reflect.DeepEqual(hashes, []string{"h3"}) {
+		t.Errorf("recent 1 = %v, %v; want [h3]", hashes, err)
+	}
+}
+
+func TestTrustedDeviceRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	user := createTestUser(t, router, domain, role)
+	devices := NewTrustedDeviceRepository(router)
+
+	expired := &entities.TrustedDevice{DomainID: domain.DomainID, UserID: user.ID, TokenHash: testHash(), Name: "old",
+		ExpiresAt: time.Now().Add(-time.Hour)}
+	if err := devices.Create(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+	device := &entities.TrustedDevice{DomainID: domain.DomainID, UserID: user.ID, TokenHash: testHash(), Name: "laptop",
+		LastIP: "203.0.113.1", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := devices.Create(ctx, device); err != nil {
+		t.Fatal(err)
+	}
+	if err := devices.Create(ctx, &entities.TrustedDevice{DomainID: domain.DomainID, UserID: user.ID, TokenHash: device.TokenHash,
+		ExpiresAt: device.ExpiresAt}); UniqueViolation(err) != "trusted_devices_token_hash_key" {
+		t.Errorf("duplicate token: err = %v, want a violation of trusted_devices_token_hash_key", err)
+	}
+
+	got, err := devices.GetByTokenHash(ctx, domain.DomainID, device.TokenHash)
+	if err != nil || got.ID != device.ID || got.Name != "laptop" {
+		t.Fatalf("by token = %+v, %v", got, err)
+	}
+	if _, err := devices.GetByTokenHash(ctx, domain.DomainID, expired.TokenHash); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expired device: err = %v, want sql.ErrNoRows", err)
+	}
+	other, _ := createTestDomain(t, router)
+	if _, err := devices.GetByTokenHash(ctx, other.DomainID, device.TokenHash); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("another domain's device: err = %v, want sql.ErrNoRows", err)
+	}
+
+	expiresAt := time.Now().Add(2 * time.Hour).Truncate(time.Microsecond)
+	if err := devices.RecordUse(ctx, device, "198.51.100.7", expiresAt); err != nil {
+		t.Fatal(err)
+	}
+	listed, err := devices.ListByUser(ctx, domain.DomainID, user.ID)
+	if err != nil || len(listed) != 1 || listed[0].LastIP != "198.51.100.7" || !listed[0].ExpiresAt.Equal(expiresAt) {
+		t.Errorf("listed = %+v, %v; want the used device", listed, err)
+	}
+
+	if err := devices.Delete(ctx, domain.DomainID, user.ID, device.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := devices.Delete(ctx, domain.DomainID, user.ID, device.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted twice: err = %v, want sql.ErrNoRows", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := devices.Create(ctx, &entities.TrustedDevice{DomainID: domain.DomainID, UserID: user.ID, TokenHash: testHash(),
+			ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Creating a device dropped the expired one
+	if deleted, err := devices.DeleteByUser(ctx, domain.DomainID, user.ID); err != nil || deleted != 2 {
+		t.Errorf("deleted by user = %d, %v; want 2", deleted, err)
+	}
+}
+
+func TestRevokedTokenRepository(t *testing.T) {
+	tokens := NewRevokedTokenRepository(integrationDB(t))
+	ctx := context.Background()
+	now := time.Now()
+	expired, live := uuid.NewString(), uuid.NewString()
+
+	if revoked, err := tokens.IsRevoked(ctx, live); err != nil || revoked {
+		t.Errorf("before revoking: %v, %v; want false", revoked, err)
+	}
+	if err := tokens.Revoke(ctx, expired, now.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tokens.Revoke(ctx, live, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	// Revoking twice is not a conflict
+	if err := tokens.Revoke(ctx, live, now.Add(time.Hour)); err != nil {
+		t.Errorf("revoked twice: %v", err)
+	}
+	if revoked, err := tokens.IsRevoked(ctx, live); err != nil || !revoked {
+		t.Errorf("after revoking: %v, %v; want true", revoked, err)
+	}
+
+	if deleted, err := tokens.DeleteExpired(ctx, now); err != nil || deleted < 1 {
+		t.Errorf("deleted expired = %d, %v; want at least 1", deleted, err)
+	}
+	if revoked, err := tokens.IsRevoked(ctx, expired); err != nil || revoked {
+		t.Errorf("expired entry: %v, %v; want it deleted", revoked, err)
+	}
+	if revoked, err := tokens.IsRevoked(ctx, live); err != nil || !revoked {
+		t.Errorf("live entry: %v, %v; want it kept", revoked, err)
+	}
+}
+
+func TestRegistrationCodeRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	codes := NewRegistrationCodeRepository(router)
+
+	maxUses := 1
+	code := &entities.RegistrationCode{DomainID: domain.DomainID, Prefix: "abcd", CodeHash: testHash(), RoleID: &role.ID, MaxUses: &maxUses}
+	if err := codes.Create(ctx, code); err != nil {
+		t.Fatal(err)
+	}
+	if err := codes.Create(ctx, &entities.RegistrationCode{DomainID: domain.DomainID, Prefix: "abcd", CodeHash: code.CodeHash}); UniqueViolation(err) != "registration_codes_code_hash_key" {
+		t.Errorf("duplicate code: err = %v, want a violation of registration_codes_code_hash_key", err)
+	}
+	expiresAt := time.Now().Add(-time.Minute)
+	expired := &entities.RegistrationCode{DomainID: domain.DomainID, Prefix: "efgh", CodeHash: testHash(), ExpiresAt: &expiresAt}
+	if err := codes.Create(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := codes.GetByHash(ctx, domain.DomainID, code.CodeHash)
+	if err != nil || got.ID != code.ID || got.RoleID == nil || *got.RoleID != role.ID || got.MaxUses == nil || *got.MaxUses != 1 {
+		t.Fatalf("by hash = %+v, %v", got, err)
+	}
+	if _, err := codes.GetByHash(ctx, domain.DomainID, testHash()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown code: err = %v, want sql.ErrNoRows", err)
+	}
+	if listed, err := codes.ListByDomain(ctx, domain.DomainID); err != nil || len(listed) != 2 || listed[0].ID != expired.ID {
+		t.Errorf("listed = %+v, %v; want both codes, newest first", listed, err)
+	}
+
+	if err := codes.Consume(ctx, domain.DomainID, code.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := codes.Consume(ctx, domain.DomainID, code.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("used up: err = %v, want sql.ErrNoRows", err)
+	}
+	if err := codes.Release(ctx, domain.DomainID, code.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := codes.Consume(ctx, domain.DomainID, code.ID); err != nil {
+		t.Errorf("after release: %v", err)
+	}
+	if err := codes.Consume(ctx, domain.DomainID, expired.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expired: err = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := codes.Revoke(ctx, domain.DomainID, expired.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := codes.Revoke(ctx, domain.DomainID, expired.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("revoked twice: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestInvitationRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	invitations := NewInvitationRepository(router)
+
+	invitation := &entities.Invitation{DomainID: domain.DomainID, Email: "New.Hire@example.com", RoleID: role.ID,
+		FirstName: "New", LastName: "Hire", TokenHash: testHash(), ExpiresAt: time.Now().Add(time.Hour)}
+	if err := invitations.Create(ctx, invitation); err != nil {
+		t.Fatal(err)
+	}
+	if invitation.Status != entities.InvitationPending || invitation.SentAt.IsZero() {
+		t.Errorf("created = %+v; want pending and sent", invitation)
+	}
+	if err := invitations.Create(ctx, &entities.Invitation{DomainID: domain.DomainID, Email: "other@example.com", RoleID: role.ID,
+		TokenHash: invitation.TokenHash, ExpiresAt: invitation.ExpiresAt}); UniqueViolation(err) != "invitations_token_hash_key" {
+		t.Errorf("duplicate token: err = %v, want a violation of invitations_token_hash_key", err)
+	}
+	expired := &entities.Invitation{DomainID: domain.DomainID, Email: "late@example.com", RoleID: role.ID,
+		TokenHash: testHash(), ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := invitations.Create(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := invitations.GetByID(ctx, domain.DomainID, invitation.ID); err != nil || got.Email != invitation.Email {
+		t.Errorf("by ID = %+v, %v", got, err)
+	}
+	if _, err := invitations.GetByID(ctx, domain.DomainID, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+	if got, err := invitations.GetByTokenHash(ctx, domain.DomainID, invitation.TokenHash); err != nil || got.ID != invitation.ID {
+		t.Errorf("by token = %+v, %v", got, err)
+	}
+	if got, err := invitations.GetPendingByEmail(ctx, domain.DomainID, "new.hire@EXAMPLE.com"); err != nil || got.ID != invitation.ID {
+		t.Errorf("pending by email = %+v, %v", got, err)
+	}
+	if _, err := invitations.GetPendingByEmail(ctx, domain.DomainID, "late@example.com"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expired by email: err = %v, want sql.ErrNoRows", err)
+	}
+	for status, want := range map[string]int{"": 2, entities.InvitationPending: 1, entities.InvitationExpired: 1, entities.InvitationAccepted: 0} {
+		if listed, err := invitations.ListByDomain(ctx, domain.DomainID, status); err != nil || len(listed) != want {
+			t.Errorf("status %q: %d invitations, %v; want %d", status, len(listed), err, want)
+		}
+	}
+
+	if err := invitations.Accept(ctx, domain.DomainID, expired.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("accept expired: err = %v, want sql.ErrNoRows", err)
+	}
+	expired.TokenHash, expired.ExpiresAt = testHash(), time.Now().Add(time.Hour)
+	if err := invitations.Renew(ctx, expired); err != nil || expired.Status != entities.InvitationPending {
+		t.Errorf("renew = %v, status %q; want pending", err, expired.Status)
+	}
+
+	if err := invitations.Accept(ctx, domain.DomainID, invitation.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := invitations.Accept(ctx, domain.DomainID, invitation.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("accepted twice: err = %v, want sql.ErrNoRows", err)
+	}
+	if err := invitations.Renew(ctx, invitation); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("renew accepted: err = %v, want sql.ErrNoRows", err)
+	}
+	if err := invitations.Revoke(ctx, domain.DomainID, invitation.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("revoke accepted: err = %v, want sql.ErrNoRows", err)
+	}
+	if err := invitations.Reopen(ctx, domain.DomainID, invitation.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := invitations.Revoke(ctx, domain.DomainID, invitation.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := invitations.GetByID(ctx, domain.DomainID, invitation.ID); err != nil || got.Status != entities.InvitationRevoked {
+		t.Errorf("revoked = %+v, %v", got, err)
+	}
+}
+
+func TestProfileConsentRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	user := createTestUser(t, router, domain, role)
+	consents := NewProfileConsentRepository(router)
+	clientID := uuid.New()
+
+	if _, err := consents.Get(ctx, domain.DomainID, user.ID, clientID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("no consent: err = %v, want sql.ErrNoRows", err)
+	}
+	consent := &entities.ProfileConsent{DomainID: domain.DomainID, UserID: user.ID, ClientID: clientID, Fields: []string{"email"}}
+	if err := consents.Upsert(ctx, consent); err != nil {
+		t.Fatal(err)
+	}
+	// Consenting again replaces the fields
+	consent.Fields = []string{"email", "first_name"}
+	if err := consents.Upsert(ctx, consent); err != nil {
+		t.Fatal(err)
+	}
+	got, err := consents.Get(ctx, domain.DomainID, user.ID, clientID)
+	if err != nil || !reflect.DeepEqual(got.Fields, consent.Fields) {
+		t.Errorf("consent = %+v, %v; want fields %v", got, err, consent.Fields)
+	}
+	if listed, err := consents.ListByUser(ctx, domain.DomainID, user.ID); err != nil || len(listed) != 1 {
+		t.Errorf("listed = %+v, %v; want one consent", listed, err)
+	}
+
+	if err := consents.Delete(ctx, domain.DomainID, user.ID, clientID); err != nil {
+		t.Fatal(err)
+	}
+	if err := consents.Delete(ctx, domain.DomainID, user.ID, clientID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted twice: err = %v, want sql.ErrNoRows", err)
+	}
+}
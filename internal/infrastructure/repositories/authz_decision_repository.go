@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type AuthzDecisionRepository interface {
+	Create(ctx context.Context, decision *entities.AuthzDecision) error
+	ListAllowedByRole(ctx context.Context, domainID, roleID uuid.UUID, since time.Time, limit int) ([]*entities.AuthzDecision, error)
+	ListWithPagination(ctx context.Context, filter AuthzDecisionFilter, page, limit int) (*AuthzDecisionListResult, error)
+}
+
+// AuthzDecisionFilter narrows an audit listing; zero values are ignored except DomainID.
+type AuthzDecisionFilter struct {
+	DomainID uuid.UUID
+	UserID   uuid.UUID
+	Resource string
+	Action   string
+	Allowed  *bool
+}
+
+type AuthzDecisionListResult struct {
+	Decisions  []*entities.AuthzDecision `json:"decisions"`
+	Total      int                       `json:"total"`
+	Page       int                       `json:"page"`
+	Limit      int                       `json:"limit"`
+	TotalPages int                       `json:"total_pages"`
+}
+
+type authzDecisionRepository struct {
+	router *ShardRouter
+}
+
+func NewAuthzDecisionRepository(router *ShardRouter) AuthzDecisionRepository {
+	return &authzDecisionRepository{router: router}
+}
+
+func (r *authzDecisionRepository) Create(ctx context.Context, decision *entities.AuthzDecision) error {
+	ctx, end := observe(ctx, "authz_decisions", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, decision.DomainID)
+	if err != nil {
+		return err
+	}
+
+	decision.ID = uuid.New()
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO authz_decisions (id, domain_id, user_id, role_id, resource, action, allowed, matched_rule)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING created_at`,
+		decision.ID, decision.DomainID, decision.UserID, decision.RoleID, decision.Resource,
+		decision.Action, decision.Allowed, decision.MatchedRule).Scan(&decision.CreatedAt)
+	return err
+}
+
+// ListAllowedByRole returns the most recent allowed decisions taken for holders of a role,
+// including users who inherit it through a group.
+func (r *authzDecisionRepository) ListAllowedByRole(ctx context.Context, domainID, roleID uuid.UUID, since time.Time, limit int) ([]*entities.AuthzDecision, error) {
+	ctx, end := observe(ctx, "authz_decisions", "list_allowed_by_role")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, domain_id, user_id, role_id, resource, action, allowed, matched_rule, created_at
+		FROM authz_decisions
+		WHERE domain_id = $1 AND allowed AND created_at >= $3
+			AND (role_id = $2 OR user_id IN (
+				SELECT gm.user_id FROM group_members gm JOIN group_roles gr ON gr.group_id = gm.group_id
+				WHERE gr.role_id = $2))
+		ORDER BY created_at DESC LIMIT $4`, domainID, roleID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAuthzDecisions(rows)
+}
+
+func (r *authzDecisionRepository) ListWithPagination(ctx context.Context, filter AuthzDecisionFilter, page, limit int) (*AuthzDecisionListResult, error) {
+	ctx, end := observe(ctx, "authz_decisions", "list_with_pagination")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, filter.DomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate offset
+	offset := (page - 1) * limit
+
+	// Build the query with filter conditions
+	baseQuery := "SELECT id, domain_id, user_id, role_id, resource, action, allowed, matched_rule, created_at FROM authz_decisions WHERE domain_id = $1"
+	countQuery := "SELECT COUNT(*) FROM authz_decisions WHERE domain_id = $1"
+	args := []interface{}{filter.DomainID}
+	var whereClause string
+
+	if filter.UserID != uuid.Nil {
+		whereClause += " AND user_id = $" + fmt.Sprintf("%d", len(args)+1)
+		args = append(args, filter.UserID)
+	}
+	if filter.Resource != "" {
+		whereClause += " AND resource = $" + fmt.Sprintf("%d", len(args)+1)
+		args = append(args, filter.Resource)
+	}
+	if filter.Action != "" {
+		whereClause += " AND action = $" + fmt.Sprintf("%d", len(args)+1)
+		args = append(args, filter.Action)
+	}
+	if filter.Allowed != nil {
+		whereClause += " AND allowed = $" + fmt.Sprintf("%d", len(args)+1)
+		args = append(args, *filter.Allowed)
+	}
+
+	// Get total count
+	var total int
+	err = db.QueryRowContext(ctx, countQuery+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get paginated results
+	query := baseQuery + whereClause + " ORDER BY created_at DESC LIMIT $" + fmt.Sprintf("%d", len(args)+1) + " OFFSET $" + fmt.Sprintf("%d", len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	decisions, err := scanAuthzDecisions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate total pages
+	totalPages := (total + limit - 1) / limit
+
+	return &AuthzDecisionListResult{
+		Decisions:  decisions,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func scanAuthzDecisions(rows *sql.Rows) ([]*entities.AuthzDecision, error) {
+	var decisions []*entities.AuthzDecision
+	for rows.Next() {
+		var decision entities.AuthzDecision
+		err := rows.Scan(&decision.ID, &decision.DomainID, &decision.UserID, &decision.RoleID, &decision.Resource,
+			&decision.Action, &decision.Allowed, &decision.MatchedRule, &decision.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, &decision)
+	}
+	return decisions, nil
+}
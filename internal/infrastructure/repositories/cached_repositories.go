@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/cache"
+	"backend/internal/infrastructure/metrics"
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/google/uuid"
+)
+
+// cachedGet returns the value cached under key, or loads and caches it. Cache errors are logged
+// and fall through to load, so an unavailable cache only costs the lookups it would have saved.
+func cachedGet[T any](ctx context.Context, c cache.Cache, name, key string, ttl time.Duration, load func() (*T, error)) (*T, error) {
+	if data, found, err := c.Get(ctx, key); err != nil {
+		log.Printf("Failed to read %s from cache: %v", key, err)
+	} else if found {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			metrics.RecordCacheLookup(name, true)
+			return &value, nil
+		}
+	}
+	metrics.RecordCacheLookup(name, false)
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(value); err == nil {
+		if err := c.Set(ctx, key, data, ttl); err != nil {
+			log.Printf("Failed to write %s to cache: %v", key, err)
+		}
+	}
+	return value, nil
+}
+
+func invalidate(ctx context.Context, c cache.Cache, key string) {
+	if err := c.Delete(ctx, key); err != nil {
+		log.Printf("Failed to invalidate %s in cache: %v", key, err)
+	}
+}
+
+type cachedRoleRepository struct {
+	RoleRepository
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedRoleRepository caches roles looked up by ID, which every login, token and profile
+// request does, and drops them when they are updated or deleted.
+func NewCachedRoleRepository(repo RoleRepository, c cache.Cache, ttl time.Duration) RoleRepository {
+	return &cachedRoleRepository{RoleRepository: repo, cache: c, ttl: ttl}
+}
+
+func roleCacheKey(id uuid.UUID) string {
+	return "role:" + id.String()
+}
+
+// GetByID reports a cached role of another tenant than ctx's as not found, as row-level security
+// would have.
+func (r *cachedRoleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Role, error) {
+	role, err := cachedGet(ctx, r.cache, "roles", roleCacheKey(id), r.ttl, func() (*entities.Role, error) {
+		return r.RoleRepository.GetByID(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if domainID, ok := tenancy.DomainFrom(ctx); ok && role.DomainID != domainID {
+		return nil, sql.ErrNoRows
+	}
+	return role, nil
+}
+
+func (r *cachedRoleRepository) Update(ctx context.Context, role *entities.Role) error {
+	if err := r.RoleRepository.Update(ctx, role); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, roleCacheKey(role.ID))
+	return nil
+}
+
+func (r *cachedRoleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.RoleRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, roleCacheKey(id))
+	return nil
+}
+
+func (r *cachedRoleRepository) DeleteReassigning(ctx context.Context, domainID, id, replacementID uuid.UUID) error {
+	if err := r.RoleRepository.DeleteReassigning(ctx, domainID, id, replacementID); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, roleCacheKey(id))
+	return nil
+}
+
+type cachedDomainRepository struct {
+	DomainRepository
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewCachedDomainRepository caches domains looked up by ID and drops them when they are updated
+// or deleted. Lookups by hostname are not cached, since alias changes would also have to
+// invalidate them.
+func NewCachedDomainRepository(repo DomainRepository, c cache.Cache, ttl time.Duration) DomainRepository {
+	return &cachedDomainRepository{DomainRepository: repo, cache: c, ttl: ttl}
+}
+
+func domainCacheKey(id uuid.UUID) string {
+	return "domain:" + id.String()
+}
+
+func (r *cachedDomainRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Domain, error) {
+	return cachedGet(ctx, r.cache, "domains", domainCacheKey(id), r.ttl, func() (*entities.Domain, error) {
+		return r.DomainRepository.GetByID(ctx, id)
+	})
+}
+
+func (r *cachedDomainRepository) Update(ctx context.Context, domain *entities.Domain) error {
+	if err := r.DomainRepository.Update(ctx, domain); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, domainCacheKey(domain.DomainID))
+	return nil
+}
+
+func (r *cachedDomainRepository) MarkForDeletion(ctx context.Context, id uuid.UUID) error {
+	if err := r.DomainRepository.MarkForDeletion(ctx, id); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, domainCacheKey(id))
+	return nil
+}
+
+func (r *cachedDomainRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.DomainRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, domainCacheKey(id))
+	return nil
+}
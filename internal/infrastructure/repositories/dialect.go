@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"database/sql"
+	"strings"
+
+	"backend/internal/infrastructure/sqlitedb"
+)
+
+// Dialect writes the constructs of statements the databases the repositories run on spell
+// differently: Postgres, and SQLite for local development and tests (DB_DRIVER=sqlite). Arguments
+// and results are SQL expressions, such as columns and $n placeholders. Everything else the
+// repositories write is SQL both understand; the current time is NOW().
+type Dialect interface {
+	// Cast gives expr a Postgres type, such as text[], for placeholders whose type Postgres can't
+	// infer.
+	Cast(expr, pgType string) string
+	// SecondsFromNow is the current time plus a number of seconds.
+	SecondsFromNow(seconds string) string
+	// ILike matches expr against a LIKE pattern, with \ escaping, ignoring case.
+	ILike(expr, pattern string) string
+	// AnyOf is whether value is an element of array (value = ANY(array)).
+	AnyOf(value, array string) string
+	// Overlaps is whether two arrays share an element (a && b).
+	Overlaps(a, b string) string
+	// JSONHasKey is whether a JSONB object has the key, or a JSONB array the string (doc ? key).
+	JSONHasKey(doc, key string) string
+	// JSONHasAnyKey is JSONHasKey for any of an array of keys (doc ?| keys).
+	JSONHasAnyKey(doc, keys string) string
+	// JSONContains is whether a JSONB document contains another (doc @> value).
+	JSONContains(doc, value string) string
+	// RowID is the column locating a row in its table, which RowsIn selects rows by.
+	RowID() string
+	// RowsIn is the condition selecting the rows whose RowID the subquery returns, for deleting in
+	// batches.
+	RowsIn(subquery string) string
+	// SkipLocked ends a SELECT to lock the rows it returns, of the tables named if given, skipping
+	// rows other transactions hold.
+	SkipLocked(of ...string) string
+	// TableExists is whether the table named by a string expression exists.
+	TableExists(name string) string
+	// ReadOnly is whether the database refuses writes, as a standby does.
+	ReadOnly() string
+}
+
+// DialectOf returns the dialect of a database opened with DatabaseConfig.OpenDB.
+func DialectOf(db *sql.DB) Dialect {
+	if sqlitedb.Is(db) {
+		return sqlitedb.Dialect{}
+	}
+	return postgresDialect{}
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Cast(expr, pgType string) string {
+	return expr + "::" + pgType
+}
+
+func (postgresDialect) SecondsFromNow(seconds string) string {
+	return "NOW() + make_interval(secs => " + seconds + ")"
+}
+
+func (postgresDialect) ILike(expr, pattern string) string {
+	return expr + " ILIKE " + pattern
+}
+
+func (postgresDialect) AnyOf(value, array string) string {
+	return value + " = ANY(" + array + ")"
+}
+
+func (postgresDialect) Overlaps(a, b string) string {
+	return a + " && " + b
+}
+
+func (postgresDialect) JSONHasKey(doc, key string) string {
+	return doc + " ? " + key
+}
+
+func (postgresDialect) JSONHasAnyKey(doc, keys string) string {
+	return doc + " ?| " + keys
+}
+
+func (postgresDialect) JSONContains(doc, value string) string {
+	return doc + " @> " + value
+}
+
+func (postgresDialect) RowID() string {
+	return "ctid"
+}
+
+// RowsIn compares with ANY of an array rather than IN, which Postgres plans as a TID scan.
+func (postgresDialect) RowsIn(subquery string) string {
+	return "ctid = ANY(ARRAY(" + subquery + "))"
+}
+
+func (postgresDialect) SkipLocked(of ...string) string {
+	if len(of) == 0 {
+		return " FOR UPDATE SKIP LOCKED"
+	}
+	return " FOR UPDATE OF " + strings.Join(of, ", ") + " SKIP LOCKED"
+}
+
+func (postgresDialect) TableExists(name string) string {
+	return "to_regclass(" + name + ") IS NOT NULL"
+}
+
+func (postgresDialect) ReadOnly() string {
+	return "pg_is_in_recovery() OR current_setting('transaction_read_only') = 'on'"
+}
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type DomainAliasRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.DomainAlias, error)
+	GetByHostname(ctx context.Context, hostname string) (*entities.DomainAlias, error)
+	GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.DomainAlias, error)
+	Create(ctx context.Context, alias *entities.DomainAlias) error
+	SetPrimary(ctx context.Context, domainID, aliasID uuid.UUID) error
+	Delete(ctx context.Context, domainID, aliasID uuid.UUID) error
+}
+
+type domainAliasRepository struct {
+	db *sql.DB
+}
+
+func NewDomainAliasRepository(db *sql.DB) DomainAliasRepository {
+	return &domainAliasRepository{db: db}
+}
+
+func (r *domainAliasRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DomainAlias, error) {
+	ctx, end := observe(ctx, "domain_aliases", "get_by_id")
+	defer end()
+
+	var alias entities.DomainAlias
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, domain_id, hostname, is_primary, created_at
+		FROM domain_aliases WHERE id = $1`, id).Scan(
+		&alias.ID, &alias.DomainID, &alias.Hostname, &alias.IsPrimary, &alias.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+func (r *domainAliasRepository) GetByHostname(ctx context.Context, hostname string) (*entities.DomainAlias, error) {
+	ctx, end := observe(ctx, "domain_aliases", "get_by_hostname")
+	defer end()
+
+	var alias entities.DomainAlias
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, domain_id, hostname, is_primary, created_at
+		FROM domain_aliases WHERE hostname = $1`, hostname).Scan(
+		&alias.ID, &alias.DomainID, &alias.Hostname, &alias.IsPrimary, &alias.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+func (r *domainAliasRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.DomainAlias, error) {
+	ctx, end := observe(ctx, "domain_aliases", "get_by_domain_id")
+	defer end()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, domain_id, hostname, is_primary, created_at
+		FROM domain_aliases WHERE domain_id = $1 ORDER BY is_primary DESC, hostname`, domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var aliases []*entities.DomainAlias
+	for rows.Next() {
+		var alias entities.DomainAlias
+		err := rows.Scan(&alias.ID, &alias.DomainID, &alias.Hostname, &alias.IsPrimary, &alias.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, &alias)
+	}
+	return aliases, nil
+}
+
+func (r *domainAliasRepository) Create(ctx context.Context, alias *entities.DomainAlias) error {
+	ctx, end := observe(ctx, "domain_aliases", "create")
+	defer end()
+
+	alias.ID = uuid.New()
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO domain_aliases (id, domain_id, hostname, is_primary)
+		VALUES ($1, $2, $3, $4) RETURNING created_at`,
+		alias.ID, alias.DomainID, alias.Hostname, alias.IsPrimary).Scan(&alias.CreatedAt)
+	return err
+}
+
+func (r *domainAliasRepository) SetPrimary(ctx context.Context, domainID, aliasID uuid.UUID) error {
+	ctx, end := observe(ctx, "domain_aliases", "set_primary")
+	defer end()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Clear the current primary first so the partial unique index is never violated
+	if _, err := tx.ExecContext(ctx, "UPDATE domain_aliases SET is_primary = FALSE WHERE domain_id = $1 AND is_primary", domainID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, "UPDATE domain_aliases SET is_primary = TRUE WHERE id = $1 AND domain_id = $2", aliasID, domainID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+func (r *domainAliasRepository) Delete(ctx context.Context, domainID, aliasID uuid.UUID) error {
+	ctx, end := observe(ctx, "domain_aliases", "delete")
+	defer end()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM domain_aliases WHERE id = $1 AND domain_id = $2", aliasID, domainID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
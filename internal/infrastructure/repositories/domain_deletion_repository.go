@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// DomainPurgeSteps are the tables a domain's data is deleted from, in order, before the domain
+// itself. Users go before the roles they hold and take their password history, consents and group
+// memberships with them; the event log goes last so it records the deletion's earlier changes. The
+// domain's rows on the primary, such as its aliases and API keys, are deleted with the domain.
+var DomainPurgeSteps = []string{
+	"login_codes", "invitations", "registration_codes", "users", "groups", "policies", "roles",
+	"permissions", "webhook_deliveries", "webhooks", "event_outbox", "authz_decisions", "events",
+}
+
+type DomainDeletionRepository interface {
+	Create(ctx context.Context, deletion *entities.DomainDeletion) error
+	Save(ctx context.Context, deletion *entities.DomainDeletion, lease time.Duration) error
+	GetLatest(ctx context.Context, domainID uuid.UUID) (*entities.DomainDeletion, error)
+	ClaimNext(ctx context.Context, lease time.Duration) (*entities.DomainDeletion, error)
+	PurgeBatch(ctx context.Context, domainID uuid.UUID, step string, limit int) (int64, error)
+}
+
+const domainDeletionColumns = "id, domain_id, domain_name, status, step, deleted, error, created_at, started_at, finished_at"
+
+// domainDeletionRepository keeps deletions on the primary and purges the domain's data on its shard.
+type domainDeletionRepository struct {
+	db     *sql.DB
+	router *ShardRouter
+}
+
+func NewDomainDeletionRepository(router *ShardRouter) DomainDeletionRepository {
+	return &domainDeletionRepository{db: router.Primary(), router: router}
+}
+
+func (r *domainDeletionRepository) Create(ctx context.Context, deletion *entities.DomainDeletion) error {
+	ctx, end := observe(ctx, "domain_deletions", "create")
+	defer end()
+
+	deletedJSON, err := json.Marshal(deletion.Deleted)
+	if err != nil {
+		return err
+	}
+	deletion.ID = uuid.New()
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO domain_deletions (id, domain_id, domain_name, status, step, deleted)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING created_at`,
+		deletion.ID, deletion.DomainID, deletion.DomainName, deletion.Status, deletion.Step, deletedJSON).Scan(&deletion.CreatedAt)
+}
+
+// Save stores the deletion's progress and extends the caller's lease on it; a finished deletion
+// releases the lease.
+func (r *domainDeletionRepository) Save(ctx context.Context, deletion *entities.DomainDeletion, lease time.Duration) error {
+	ctx, end := observe(ctx, "domain_deletions", "save")
+	defer end()
+
+	deletedJSON, err := json.Marshal(deletion.Deleted)
+	if err != nil {
+		return err
+	}
+	d := r.router.Dialect()
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE domain_deletions SET status = $1, step = $2, deleted = $3, error = $4, started_at = $5, finished_at = $6,
+			lease_until = CASE WHEN `+d.Cast("$6", "timestamptz")+` IS NULL THEN `+d.SecondsFromNow("$7")+` END
+		WHERE id = $8`,
+		deletion.Status, deletion.Step, deletedJSON, deletion.Error, deletion.StartedAt, deletion.FinishedAt, lease.Seconds(), deletion.ID)
+	return err
+}
+
+// GetLatest returns the domain's most recent deletion.
+func (r *domainDeletionRepository) GetLatest(ctx context.Context, domainID uuid.UUID) (*entities.DomainDeletion, error) {
+	ctx, end := observe(ctx, "domain_deletions", "get_latest")
+	defer end()
+
+	return scanDomainDeletion(r.db.QueryRowContext(ctx, "SELECT "+domainDeletionColumns+`
+		FROM domain_deletions WHERE domain_id = $1 ORDER BY created_at DESC LIMIT 1`, domainID))
+}
+
+// ClaimNext takes the oldest pending deletion, or a running one whose worker let its lease expire,
+// and holds it for lease. It returns sql.ErrNoRows when there is none.
+func (r *domainDeletionRepository) ClaimNext(ctx context.Context, lease time.Duration) (*entities.DomainDeletion, error) {
+	ctx, end := observe(ctx, "domain_deletions", "claim_next")
+	defer end()
+
+	d := r.router.Dialect()
+	return scanDomainDeletion(r.db.QueryRowContext(ctx, `
+		UPDATE domain_deletions SET status = $1, started_at = COALESCE(started_at, NOW()),
+			lease_until = `+d.SecondsFromNow("$2")+`
+		WHERE id = (
+			SELECT id FROM domain_deletions
+			WHERE status = $3 OR (status = $1 AND lease_until < NOW())
+			ORDER BY created_at LIMIT 1`+d.SkipLocked()+`)
+		RETURNING `+domainDeletionColumns,
+		entities.DomainDeletionRunning, lease.Seconds(), entities.DomainDeletionPending))
+}
+
+// PurgeBatch deletes up to limit rows of the domain from the table of a DomainPurgeSteps step and
+// returns how many were deleted.
+func (r *domainDeletionRepository) PurgeBatch(ctx context.Context, domainID uuid.UUID, step string, limit int) (int64, error) {
+	ctx, end := observe(ctx, step, "purge_batch")
+	defer end()
+
+	if !slices.Contains(DomainPurgeSteps, step) {
+		return 0, fmt.Errorf("unknown purge step %q", step)
+	}
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return 0, err
+	}
+	// step is one of DomainPurgeSteps, never client input
+	d := r.router.Dialect()
+	result, err := db.ExecContext(ctx, `
+		DELETE FROM `+step+` WHERE `+d.RowsIn(`
+			SELECT `+d.RowID()+` FROM `+step+` WHERE domain_id = $1 LIMIT $2`), domainID, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanDomainDeletion(row rowScanner) (*entities.DomainDeletion, error) {
+	var deletion entities.DomainDeletion
+	var deletedJSON []byte
+	var startedAt, finishedAt sql.NullTime
+	err := row.Scan(&deletion.ID, &deletion.DomainID, &deletion.DomainName, &deletion.Status, &deletion.Step,
+		&deletedJSON, &deletion.Error, &deletion.CreatedAt, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	if startedAt.Valid {
+		deletion.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		deletion.FinishedAt = &finishedAt.Time
+	}
+	if err := json.Unmarshal(deletedJSON, &deletion.Deleted); err != nil {
+		return nil, err
+	}
+	return &deletion, nil
+}
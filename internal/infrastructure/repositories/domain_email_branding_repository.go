@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type DomainEmailBrandingRepository interface {
+	GetByDomainID(ctx context.Context, domainID uuid.UUID) (*entities.EmailBranding, error)
+	Upsert(ctx context.Context, branding *entities.EmailBranding) error
+	Delete(ctx context.Context, domainID uuid.UUID) error
+}
+
+type domainEmailBrandingRepository struct {
+	db *sql.DB
+}
+
+func NewDomainEmailBrandingRepository(db *sql.DB) DomainEmailBrandingRepository {
+	return &domainEmailBrandingRepository{db: db}
+}
+
+func (r *domainEmailBrandingRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) (*entities.EmailBranding, error) {
+	ctx, end := observe(ctx, "domain_email_branding", "get_by_domain_id")
+	defer end()
+
+	var branding entities.EmailBranding
+	var templatesJSON []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT domain_id, product_name, support_email, footer, templates, updated_at
+		FROM domain_email_branding WHERE domain_id = $1`, domainID).Scan(
+		&branding.DomainID, &branding.ProductName, &branding.SupportEmail, &branding.Footer, &templatesJSON, &branding.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(templatesJSON, &branding.Templates); err != nil {
+		return nil, err
+	}
+	return &branding, nil
+}
+
+// Upsert replaces the domain's branding.
+func (r *domainEmailBrandingRepository) Upsert(ctx context.Context, branding *entities.EmailBranding) error {
+	ctx, end := observe(ctx, "domain_email_branding", "upsert")
+	defer end()
+
+	templatesJSON, err := json.Marshal(branding.Templates)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO domain_email_branding (domain_id, product_name, support_email, footer, templates)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (domain_id) DO UPDATE SET
+			product_name = EXCLUDED.product_name,
+			support_email = EXCLUDED.support_email,
+			footer = EXCLUDED.footer,
+			templates = EXCLUDED.templates,
+			updated_at = NOW()
+		RETURNING updated_at`,
+		branding.DomainID, branding.ProductName, branding.SupportEmail, branding.Footer, templatesJSON).Scan(&branding.UpdatedAt)
+}
+
+func (r *domainEmailBrandingRepository) Delete(ctx context.Context, domainID uuid.UUID) error {
+	ctx, end := observe(ctx, "domain_email_branding", "delete")
+	defer end()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM domain_email_branding WHERE domain_id = $1", domainID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
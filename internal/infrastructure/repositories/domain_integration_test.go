@@ -0,0 +1,507 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+func TestDomainRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domains := NewDomainRepository(router)
+
+	domain := &entities.Domain{Name: "Acme " + uuid.NewString()[:8], Domain: uuid.NewString() + ".example.com", Plan: "enterprise",
+		Tags: []string{"eu-pilot"}, Telemetry: entities.TelemetrySettings{LoginExport: true}}
+	if err := domains.Create(ctx, domain); err != nil {
+		t.Fatal(err)
+	}
+	if domain.Residency != DefaultResidency || domain.LoginMode != entities.LoginModePassword {
+		t.Errorf("created = %+v; want the default residency and login mode", domain)
+	}
+	if err := domains.Create(ctx, &entities.Domain{Name: "Copy", Domain: domain.Domain}); UniqueViolation(err) != "domains_domain_key" {
+		t.Errorf("duplicate hostname: err = %v, want a violation of domains_domain_key", err)
+	}
+	if err := domains.Create(ctx, &entities.Domain{Name: "Elsewhere", Domain: uuid.NewString() + ".example.com", Residency: "mars"}); err == nil {
+		t.Error("created a domain in an unknown residency")
+	}
+
+	got, err := domains.GetByID(ctx, domain.DomainID)
+	if err != nil || got.Name != domain.Name || got.Plan != "enterprise" || !reflect.DeepEqual(got.Tags, []string{"eu-pilot"}) || !got.Telemetry.LoginExport {
+		t.Fatalf("by ID = %+v, %v", got, err)
+	}
+	if _, err := domains.GetByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+	if got, err := domains.GetByHostname(ctx, domain.Domain); err != nil || got.DomainID != domain.DomainID {
+		t.Errorf("by hostname = %+v, %v", got, err)
+	}
+	if _, err := domains.GetByHostname(ctx, "missing.example.com"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown hostname: err = %v, want sql.ErrNoRows", err)
+	}
+	if exporters, err := domains.ListLoginTelemetryExporters(ctx); err != nil || !containsID(exporters, domain.DomainID) {
+		t.Errorf("telemetry exporters = %v, %v; want %s among them", exporters, err, domain.DomainID)
+	}
+	if all, err := domains.List(ctx); err != nil || !containsDomain(all, domain.DomainID) {
+		t.Errorf("list = %d domains, %v; want the domain among them", len(all), err)
+	}
+
+	domain.Name = "Renamed " + uuid.NewString()[:8]
+	domain.Tags = nil
+	domain.TokenSettings.NamespaceClaims = true
+	domain.TokenSettings.ClaimNamespace = "https://" + domain.Domain + "/claims/"
+	if err := domains.Update(ctx, domain); err != nil {
+		t.Fatal(err)
+	}
+	got, err = domains.GetByID(ctx, domain.DomainID)
+	if err != nil || got.Name != domain.Name || len(got.Tags) != 0 || !got.TokenSettings.NamespaceClaims {
+		t.Errorf("updated = %+v, %v", got, err)
+	}
+
+	namespaceTests := []struct {
+		namespace string
+		except    uuid.UUID
+		want      bool
+	}{
+		{domain.TokenSettings.ClaimNamespace + "sub/", uuid.Nil, true},
+		{"https://" + domain.Domain + "/", uuid.Nil, true},
+		{domain.TokenSettings.ClaimNamespace, domain.DomainID, false},
+		{"https://unrelated.example.com/claims/", uuid.Nil, false},
+	}
+	for _, tt := range namespaceTests {
+		if overlaps, err := domains.ClaimNamespaceOverlaps(ctx, tt.namespace, tt.except); err != nil || overlaps != tt.want {
+			t.Errorf("namespace %q overlaps = %v, %v; want %v", tt.namespace, overlaps, err, tt.want)
+		}
+	}
+
+	role := &entities.Role{DomainID: domain.DomainID, RoleName: "member", RoleClaims: map[string]interface{}{}}
+	if err := NewRoleRepository(router).Create(ctx, role); err != nil {
+		t.Fatal(err)
+	}
+	createTestUser(t, router, domain, role)
+	alias := &entities.DomainAlias{DomainID: domain.DomainID, Hostname: uuid.NewString() + ".example.com"}
+	if err := NewDomainAliasRepository(router.Primary()).Create(ctx, alias); err != nil {
+		t.Fatal(err)
+	}
+	dependents, err := domains.CountDependents(ctx, domain.DomainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dependents.Users != 1 || dependents.Roles != 1 || dependents.Aliases != 1 || dependents.Webhooks != 0 {
+		t.Errorf("dependents = %+v; want 1 user, 1 role, 1 alias", dependents)
+	}
+
+	if err := domains.MarkForDeletion(ctx, domain.DomainID); err != nil {
+		t.Fatal(err)
+	}
+	marked, err := domains.GetByID(ctx, domain.DomainID)
+	if err != nil || marked.DeletionRequestedAt == nil {
+		t.Fatalf("marked = %+v, %v; want deletion_requested_at set", marked, err)
+	}
+	// Marking again keeps the first request, and Update can't clear it
+	if err := domains.MarkForDeletion(ctx, domain.DomainID); err != nil {
+		t.Fatal(err)
+	}
+	if err := domains.Update(ctx, domain); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := domains.GetByID(ctx, domain.DomainID); err != nil || again.DeletionRequestedAt == nil || !again.DeletionRequestedAt.Equal(*marked.DeletionRequestedAt) {
+		t.Errorf("marked again = %+v, %v; want deletion_requested_at unchanged", again, err)
+	}
+
+	if err := domains.Delete(ctx, domain.DomainID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := domains.GetByID(ctx, domain.DomainID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestDomainPagination(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domains := NewDomainRepository(router)
+
+	// The search matches only this test's domains
+	marker := uuid.NewString()[:8]
+	var created []uuid.UUID
+	for i := 0; i < 5; i++ {
+		domain := &entities.Domain{Name: "Paged " + marker + " " + string(rune('a'+i)), Domain: uuid.NewString() + ".example.com"}
+		if err := domains.Create(ctx, domain); err != nil {
+			t.Fatal(err)
+		}
+		created = append(created, domain.DomainID)
+	}
+
+	tests := []struct {
+		page, limit, wantDomains, wantPages int
+	}{
+		{1, 2, 2, 3},
+		{3, 2, 1, 3},
+		{4, 2, 0, 3},
+		{1, 5, 5, 1},
+		{1, 10, 5, 1},
+	}
+	for _, tt := range tests {
+		result, err := domains.ListWithPagination(ctx, marker, tt.page, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Domains) != tt.wantDomains || result.Total != 5 || result.TotalPages != tt.wantPages {
+			t.Errorf("page %d of %d: %d domains, total %d, %d pages; want %d domains, total 5, %d pages",
+				tt.page, tt.limit, len(result.Domains), result.Total, result.TotalPages, tt.wantDomains, tt.wantPages)
+		}
+	}
+	if first, err := domains.ListWithPagination(ctx, marker, 1, 1); err != nil || first.Domains[0].Name != "Paged "+marker+" a" {
+		t.Errorf("first by name = %+v, %v", first, err)
+	}
+	if upper, err := domains.ListWithPagination(ctx, "PAGED "+marker, 1, 10); err != nil || upper.Total != 5 {
+		t.Errorf("search ignoring case: %+v, %v; want 5 domains", upper, err)
+	}
+
+	// Walking the cursor visits every domain once, in creation order
+	var walked []uuid.UUID
+	var after *ListCursor
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("the cursor never reached the last page")
+		}
+		page, err := domains.ListAfter(ctx, marker, after, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, domain := range page.Domains {
+			walked = append(walked, domain.DomainID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		if after, err = ParseListCursor(page.NextCursor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(walked, created) {
+		t.Errorf("walked %v, want %v", walked, created)
+	}
+}
+
+func TestDomainAliasRepository(t *testing.T) {
+	db := integrationDB(t)
+	router := NewShardRouter(db, nil, nil)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, router)
+	aliases := NewDomainAliasRepository(db)
+
+	first := &entities.DomainAlias{DomainID: domain.DomainID, Hostname: uuid.NewString() + ".example.com", IsPrimary: true}
+	second := &entities.DomainAlias{DomainID: domain.DomainID, Hostname: uuid.NewString() + ".example.com"}
+	for _, alias := range []*entities.DomainAlias{first, second} {
+		if err := aliases.Create(ctx, alias); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := aliases.Create(ctx, &entities.DomainAlias{DomainID: domain.DomainID, Hostname: first.Hostname}); UniqueViolation(err) != "domain_aliases_hostname_key" {
+		t.Errorf("duplicate hostname: err = %v, want a violation of domain_aliases_hostname_key", err)
+	}
+	if err := aliases.Create(ctx, &entities.DomainAlias{DomainID: domain.DomainID, Hostname: uuid.NewString() + ".example.com", IsPrimary: true}); UniqueViolation(err) != "idx_domain_aliases_primary" {
+		t.Errorf("second primary: err = %v, want a violation of idx_domain_aliases_primary", err)
+	}
+
+	if got, err := aliases.GetByID(ctx, first.ID); err != nil || got.Hostname != first.Hostname || !got.IsPrimary {
+		t.Errorf("by ID = %+v, %v", got, err)
+	}
+	if _, err := aliases.GetByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+	if got, err := aliases.GetByHostname(ctx, second.Hostname); err != nil || got.ID != second.ID {
+		t.Errorf("by hostname = %+v, %v", got, err)
+	}
+	if got, err := NewDomainRepository(router).GetByHostname(ctx, second.Hostname); err != nil || got.DomainID != domain.DomainID {
+		t.Errorf("domain by alias = %+v, %v", got, err)
+	}
+
+	if err := aliases.SetPrimary(ctx, domain.DomainID, second.ID); err != nil {
+		t.Fatal(err)
+	}
+	listed, err := aliases.GetByDomainID(ctx, domain.DomainID)
+	if err != nil || len(listed) != 2 {
+		t.Fatalf("listed = %+v, %v", listed, err)
+	}
+	for _, alias := range listed {
+		if alias.IsPrimary != (alias.ID == second.ID) {
+			t.Errorf("alias %s primary = %v after moving the primary", alias.Hostname, alias.IsPrimary)
+		}
+	}
+	other, _ := createTestDomain(t, router)
+	if err := aliases.SetPrimary(ctx, other.DomainID, first.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("another domain's alias: err = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := aliases.Delete(ctx, domain.DomainID, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliases.Delete(ctx, domain.DomainID, first.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted twice: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestDomainEmailBrandingRepository(t *testing.T) {
+	db := integrationDB(t)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, NewShardRouter(db, nil, nil))
+	brandings := NewDomainEmailBrandingRepository(db)
+
+	if _, err := brandings.GetByDomainID(ctx, domain.DomainID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("no branding: err = %v, want sql.ErrNoRows", err)
+	}
+	branding := &entities.EmailBranding{DomainID: domain.DomainID, ProductName: "Acme Portal", SupportEmail: "support@example.com",
+		Templates: map[string]entities.EmailTemplate{"invitation": {Subject: "Join {{.Product}}", Body: "{{.Link}}"}}}
+	if err := brandings.Upsert(ctx, branding); err != nil {
+		t.Fatal(err)
+	}
+	branding.Footer = "Acme Corp"
+	if err := brandings.Upsert(ctx, branding); err != nil {
+		t.Fatal(err)
+	}
+	got, err := brandings.GetByDomainID(ctx, domain.DomainID)
+	if err != nil || got.Footer != "Acme Corp" || !reflect.DeepEqual(got.Templates, branding.Templates) {
+		t.Errorf("branding = %+v, %v; want %+v", got, err, branding)
+	}
+
+	if err := brandings.Delete(ctx, domain.DomainID); err != nil {
+		t.Fatal(err)
+	}
+	if err := brandings.Delete(ctx, domain.DomainID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted twice: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestLoginRiskPolicyRepository(t *testing.T) {
+	db := integrationDB(t)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, NewShardRouter(db, nil, nil))
+	policies := NewLoginRiskPolicyRepository(db)
+
+	if _, err := policies.GetByDomainID(ctx, domain.DomainID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("no policy: err = %v, want sql.ErrNoRows", err)
+	}
+	captcha, block := 40, 90
+	policy := &entities.LoginRiskPolicy{DomainID: domain.DomainID, CaptchaThreshold: &captcha, BlockThreshold: &block}
+	if err := policies.Upsert(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	policy.BlockThreshold = nil
+	if err := policies.Upsert(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	got, err := policies.GetByDomainID(ctx, domain.DomainID)
+	if err != nil || got.CaptchaThreshold == nil || *got.CaptchaThreshold != 40 || got.BlockThreshold != nil || got.MFAThreshold != nil {
+		t.Errorf("policy = %+v, %v; want only the captcha threshold", got, err)
+	}
+}
+
+func TestIntegrationHealthRepository(t *testing.T) {
+	db := integrationDB(t)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, NewShardRouter(db, nil, nil))
+	healths := NewIntegrationHealthRepository(db)
+
+	if _, err := healths.Get(ctx, domain.DomainID, "smtp"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("never checked: err = %v, want sql.ErrNoRows", err)
+	}
+	checkedAt := time.Now().Truncate(time.Microsecond)
+	failure := "connection refused"
+	health := &entities.IntegrationHealth{DomainID: domain.DomainID, Kind: "smtp", Status: "unhealthy",
+		ConsecutiveFailures: 2, LastError: &failure, LastCheckedAt: &checkedAt}
+	if err := healths.Save(ctx, health); err != nil {
+		t.Fatal(err)
+	}
+	got, err := healths.Get(ctx, domain.DomainID, "smtp")
+	if err != nil || got.Status != "unhealthy" || got.LastError == nil || *got.LastError != failure || got.LastSuccessAt != nil {
+		t.Fatalf("unhealthy = %+v, %v", got, err)
+	}
+
+	health.Status, health.ConsecutiveFailures, health.LastError, health.LastSuccessAt = "healthy", 0, nil, &checkedAt
+	if err := healths.Save(ctx, health); err != nil {
+		t.Fatal(err)
+	}
+	got, err = healths.Get(ctx, domain.DomainID, "smtp")
+	if err != nil || got.Status != "healthy" || got.LastError != nil || got.LastSuccessAt == nil || !got.LastSuccessAt.Equal(checkedAt) {
+		t.Errorf("healthy = %+v, %v", got, err)
+	}
+}
+
+func TestDomainJobRepository(t *testing.T) {
+	db := integrationDB(t)
+	ctx := context.Background()
+	jobs := NewDomainJobRepository(db)
+
+	job := &entities.DomainJob{Action: entities.DomainJobSuspend, Status: entities.DomainJobPending, Total: 2,
+		Selector: entities.DomainSelector{Plan: "trial", Tags: []string{"eu-pilot"}}, Results: []entities.DomainJobResult{}}
+	if err := jobs.Create(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jobs.GetByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+
+	startedAt := time.Now()
+	job.Status, job.StartedAt, job.Succeeded = entities.DomainJobRunning, &startedAt, 1
+	job.Results = []entities.DomainJobResult{{DomainID: uuid.New(), Name: "Acme", Status: "succeeded"}}
+	if err := jobs.Update(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	got, err := jobs.GetByID(ctx, job.ID)
+	if err != nil || got.Status != entities.DomainJobRunning || got.Succeeded != 1 || !reflect.DeepEqual(got.Selector, job.Selector) || !reflect.DeepEqual(got.Results, job.Results) {
+		t.Fatalf("running = %+v, %v; want %+v", got, err, job)
+	}
+
+	finished := &entities.DomainJob{Action: entities.DomainJobUnsuspend, Status: entities.DomainJobCompleted, Results: []entities.DomainJobResult{}}
+	if err := jobs.Create(ctx, finished); err != nil {
+		t.Fatal(err)
+	}
+	if listed, err := jobs.List(ctx, 1); err != nil || len(listed) != 1 || listed[0].ID != finished.ID {
+		t.Errorf("latest job = %+v, %v; want %s", listed, err, finished.ID)
+	}
+
+	if failed, err := jobs.FailUnfinished(ctx, "restarted"); err != nil || failed < 1 {
+		t.Errorf("failed unfinished = %d, %v; want at least 1", failed, err)
+	}
+	if got, err := jobs.GetByID(ctx, job.ID); err != nil || got.Status != entities.DomainJobFailed || got.Error != "restarted" || got.FinishedAt == nil {
+		t.Errorf("cut off = %+v, %v; want it failed", got, err)
+	}
+	if got, err := jobs.GetByID(ctx, finished.ID); err != nil || got.Status != entities.DomainJobCompleted {
+		t.Errorf("completed = %+v, %v; want it left alone", got, err)
+	}
+}
+
+func TestDomainDeletionRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, role := createTestDomain(t, router)
+	for i := 0; i < 3; i++ {
+		createTestUser(t, router, domain, role)
+	}
+	deletions := NewDomainDeletionRepository(router)
+
+	if _, err := deletions.GetLatest(ctx, domain.DomainID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("no deletion: err = %v, want sql.ErrNoRows", err)
+	}
+	deletion := &entities.DomainDeletion{DomainID: domain.DomainID, DomainName: domain.Name, Status: entities.DomainDeletionPending,
+		Deleted: map[string]int64{}}
+	if err := deletions.Create(ctx, deletion); err != nil {
+		t.Fatal(err)
+	}
+	if err := deletions.Create(ctx, &entities.DomainDeletion{DomainID: domain.DomainID, Status: entities.DomainDeletionPending,
+		Deleted: map[string]int64{}}); UniqueViolation(err) != "idx_domain_deletions_active" {
+		t.Errorf("second active deletion: err = %v, want a violation of idx_domain_deletions_active", err)
+	}
+
+	claimed, err := deletions.ClaimNext(ctx, time.Minute)
+	if err != nil || claimed.ID != deletion.ID || claimed.Status != entities.DomainDeletionRunning || claimed.StartedAt == nil {
+		t.Fatalf("claimed = %+v, %v; want the deletion running", claimed, err)
+	}
+	if again, err := deletions.ClaimNext(ctx, time.Minute); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("claimed while leased = %+v, %v; want sql.ErrNoRows", again, err)
+	}
+
+	if _, err := deletions.PurgeBatch(ctx, domain.DomainID, "domains", 10); err == nil {
+		t.Error("purged a table that is not a purge step")
+	}
+	for _, want := range []int64{2, 1, 0} {
+		if purged, err := deletions.PurgeBatch(ctx, domain.DomainID, "users", 2); err != nil || purged != want {
+			t.Errorf("purged = %d, %v; want %d", purged, err, want)
+		}
+	}
+
+	claimed.Step, claimed.Deleted["users"] = "users", 3
+	if err := deletions.Save(ctx, claimed, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	finishedAt := time.Now()
+	claimed.Status, claimed.FinishedAt = entities.DomainDeletionCompleted, &finishedAt
+	if err := deletions.Save(ctx, claimed, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	latest, err := deletions.GetLatest(ctx, domain.DomainID)
+	if err != nil || latest.Status != entities.DomainDeletionCompleted || latest.Deleted["users"] != 3 || latest.FinishedAt == nil {
+		t.Errorf("latest = %+v, %v; want it completed", latest, err)
+	}
+	// A finished deletion no longer blocks another
+	if err := deletions.Create(ctx, &entities.DomainDeletion{DomainID: domain.DomainID, Status: entities.DomainDeletionPending,
+		Deleted: map[string]int64{}}); err != nil {
+		t.Errorf("deletion after a finished one: %v", err)
+	}
+}
+
+func TestTelemetryExportCursorRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, router)
+	events := NewEventRepository(router, false)
+	for i := 0; i < 2; i++ {
+		if err := events.Append(ctx, &entities.Event{DomainID: domain.DomainID, Type: "user.login", Payload: []byte("{}")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cursors := NewTelemetryExportCursorRepository(router)
+
+	if _, err := cursors.Get(ctx, domain.DomainID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("never exported: err = %v, want sql.ErrNoRows", err)
+	}
+	// The first export starts after the latest event
+	since, ok, err := cursors.Claim(ctx, domain.DomainID, time.Minute)
+	if err != nil || !ok || since != 2 {
+		t.Fatalf("first claim = %d, %v, %v; want 2, true", since, ok, err)
+	}
+	if _, ok, err := cursors.Claim(ctx, domain.DomainID, time.Minute); err != nil || ok {
+		t.Errorf("claimed while held = %v, %v; want false", ok, err)
+	}
+
+	if err := cursors.Advance(ctx, domain.DomainID, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := cursors.Release(ctx, domain.DomainID, "collector unavailable"); err != nil {
+		t.Fatal(err)
+	}
+	cursor, err := cursors.Get(ctx, domain.DomainID)
+	if err != nil || cursor.LastSequence != 5 || cursor.ExportedAt == nil || cursor.LastError == nil || *cursor.LastError != "collector unavailable" {
+		t.Errorf("cursor = %+v, %v", cursor, err)
+	}
+	if since, ok, err := cursors.Claim(ctx, domain.DomainID, time.Minute); err != nil || !ok || since != 5 {
+		t.Errorf("claim after release = %d, %v, %v; want 5, true", since, ok, err)
+	}
+
+	if err := cursors.Delete(ctx, domain.DomainID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cursors.Get(ctx, domain.DomainID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsDomain(domains []*entities.Domain, id uuid.UUID) bool {
+	for _, domain := range domains {
+		if domain.DomainID == id {
+			return true
+		}
+	}
+	return false
+}
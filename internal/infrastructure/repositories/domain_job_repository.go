@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type DomainJobRepository interface {
+	Create(ctx context.Context, job *entities.DomainJob) error
+	Update(ctx context.Context, job *entities.DomainJob) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.DomainJob, error)
+	List(ctx context.Context, limit int) ([]*entities.DomainJob, error)
+	FailUnfinished(ctx context.Context, reason string) (int64, error)
+}
+
+type domainJobRepository struct {
+	db *sql.DB
+}
+
+// NewDomainJobRepository stores bulk domain jobs on the primary database, since they span shards.
+func NewDomainJobRepository(db *sql.DB) DomainJobRepository {
+	return &domainJobRepository{db: db}
+}
+
+const domainJobColumns = "id, action, selector, message, settings, status, total, succeeded, failed, error, results, created_at, started_at, finished_at"
+
+func (r *domainJobRepository) Create(ctx context.Context, job *entities.DomainJob) error {
+	ctx, end := observe(ctx, "domain_jobs", "create")
+	defer end()
+
+	job.ID = uuid.New()
+	selectorJSON, messageJSON, settingsJSON, resultsJSON, err := marshalDomainJob(job)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO domain_jobs (id, action, selector, message, settings, status, total, results)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING created_at`,
+		job.ID, job.Action, selectorJSON, messageJSON, settingsJSON, job.Status, job.Total, resultsJSON).Scan(&job.CreatedAt)
+}
+
+// Update saves the job's progress, results and status.
+func (r *domainJobRepository) Update(ctx context.Context, job *entities.DomainJob) error {
+	ctx, end := observe(ctx, "domain_jobs", "update")
+	defer end()
+
+	resultsJSON, err := json.Marshal(job.Results)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE domain_jobs SET status = $1, total = $2, succeeded = $3, failed = $4, error = $5, results = $6,
+			started_at = $7, finished_at = $8
+		WHERE id = $9`,
+		job.Status, job.Total, job.Succeeded, job.Failed, job.Error, resultsJSON, job.StartedAt, job.FinishedAt, job.ID)
+	return err
+}
+
+func (r *domainJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DomainJob, error) {
+	ctx, end := observe(ctx, "domain_jobs", "get_by_id")
+	defer end()
+
+	return scanDomainJob(r.db.QueryRowContext(ctx, "SELECT "+domainJobColumns+" FROM domain_jobs WHERE id = $1", id))
+}
+
+// List returns the most recent jobs first.
+func (r *domainJobRepository) List(ctx context.Context, limit int) ([]*entities.DomainJob, error) {
+	ctx, end := observe(ctx, "domain_jobs", "list")
+	defer end()
+
+	rows, err := r.db.QueryContext(ctx, "SELECT "+domainJobColumns+" FROM domain_jobs ORDER BY created_at DESC LIMIT $1", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*entities.DomainJob
+	for rows.Next() {
+		job, err := scanDomainJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// FailUnfinished marks pending and running jobs failed, e.g. those cut off by a restart, and
+// returns how many there were.
+func (r *domainJobRepository) FailUnfinished(ctx context.Context, reason string) (int64, error) {
+	ctx, end := observe(ctx, "domain_jobs", "fail_unfinished")
+	defer end()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE domain_jobs SET status = $1, error = $2, finished_at = NOW()
+		WHERE status IN ($3, $4)`,
+		entities.DomainJobFailed, reason, entities.DomainJobPending, entities.DomainJobRunning)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func marshalDomainJob(job *entities.DomainJob) (selectorJSON, messageJSON, settingsJSON, resultsJSON []byte, err error) {
+	if selectorJSON, err = json.Marshal(job.Selector); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if job.Message != nil {
+		if messageJSON, err = json.Marshal(job.Message); err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+	if job.Settings != nil {
+		if settingsJSON, err = json.Marshal(job.Settings); err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+	if resultsJSON, err = json.Marshal(job.Results); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return selectorJSON, messageJSON, settingsJSON, resultsJSON, nil
+}
+
+func scanDomainJob(row rowScanner) (*entities.DomainJob, error) {
+	var job entities.DomainJob
+	var selectorJSON, messageJSON, settingsJSON, resultsJSON []byte
+	var startedAt, finishedAt sql.NullTime
+	err := row.Scan(&job.ID, &job.Action, &selectorJSON, &messageJSON, &settingsJSON, &job.Status, &job.Total,
+		&job.Succeeded, &job.Failed, &job.Error, &resultsJSON, &job.CreatedAt, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(selectorJSON, &job.Selector); err != nil {
+		return nil, err
+	}
+	if messageJSON != nil {
+		if err := json.Unmarshal(messageJSON, &job.Message); err != nil {
+			return nil, err
+		}
+	}
+	if settingsJSON != nil {
+		if err := json.Unmarshal(settingsJSON, &job.Settings); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(resultsJSON, &job.Results); err != nil {
+		return nil, err
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return &job, nil
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/secrets"
+
+	"github.com/google/uuid"
+)
+
+type DomainMailSettingsRepository interface {
+	GetByDomainID(ctx context.Context, domainID uuid.UUID) (*entities.DomainMailSettings, error)
+	ListEnabledDomainIDs(ctx context.Context) ([]uuid.UUID, error)
+	Upsert(ctx context.Context, settings *entities.DomainMailSettings) error
+	RecordTest(ctx context.Context, domainID uuid.UUID, at time.Time, testErr *string) error
+	Delete(ctx context.Context, domainID uuid.UUID) error
+	SealPlaintextPasswords(ctx context.Context) (int, error)
+}
+
+type domainMailSettingsRepository struct {
+	db          *sql.DB
+	credentials *secrets.Box
+}
+
+// NewDomainMailSettingsRepository stores passwords sealed with credentials, keyed to their
+// domain. Without credentials, passwords can't be stored or read back.
+func NewDomainMailSettingsRepository(db *sql.DB, credentials *secrets.Box) DomainMailSettingsRepository {
+	return &domainMailSettingsRepository{db: db, credentials: credentials}
+}
+
+func (r *domainMailSettingsRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) (*entities.DomainMailSettings, error) {
+	ctx, end := observe(ctx, "domain_mail_settings", "get_by_domain_id")
+	defer end()
+
+	var settings entities.DomainMailSettings
+	var region, username, password, testErr sql.NullString
+	var testedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT domain_id, provider, host, port, region, username, password, from_address, enabled,
+		       last_tested_at, last_test_error, updated_at
+		FROM domain_mail_settings WHERE domain_id = $1`, domainID).Scan(
+		&settings.DomainID, &settings.Provider, &settings.Host, &settings.Port, &region, &username, &password,
+		&settings.FromAddress, &settings.Enabled, &testedAt, &testErr, &settings.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.Region = region.String
+	settings.Username = username.String
+	if settings.Password, err = r.credentials.Open(password.String, settings.DomainID[:]); err != nil {
+		return nil, fmt.Errorf("mail password of domain %s: %w", settings.DomainID, err)
+	}
+	settings.PasswordSet = password.String != ""
+	if testedAt.Valid {
+		settings.LastTestedAt = &testedAt.Time
+	}
+	if testErr.Valid {
+		settings.LastTestError = &testErr.String
+	}
+	return &settings, nil
+}
+
+// ListEnabledDomainIDs returns the domains sending through their own enabled sender.
+func (r *domainMailSettingsRepository) ListEnabledDomainIDs(ctx context.Context) ([]uuid.UUID, error) {
+	ctx, end := observe(ctx, "domain_mail_settings", "list_enabled_domain_ids")
+	defer end()
+
+	rows, err := r.db.QueryContext(ctx, "SELECT domain_id FROM domain_mail_settings WHERE enabled")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var domainIDs []uuid.UUID
+	for rows.Next() {
+		var domainID uuid.UUID
+		if err := rows.Scan(&domainID); err != nil {
+			return nil, err
+		}
+		domainIDs = append(domainIDs, domainID)
+	}
+	return domainIDs, rows.Err()
+}
+
+// Upsert replaces the domain's settings and clears the previous test result.
+func (r *domainMailSettingsRepository) Upsert(ctx context.Context, settings *entities.DomainMailSettings) error {
+	ctx, end := observe(ctx, "domain_mail_settings", "upsert")
+	defer end()
+
+	password := settings.Password
+	if password != "" {
+		var err error
+		if password, err = r.credentials.Seal(password, settings.DomainID[:]); err != nil {
+			return err
+		}
+	}
+	settings.LastTestedAt = nil
+	settings.LastTestError = nil
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO domain_mail_settings (domain_id, provider, host, port, region, username, password, from_address, enabled)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), $8, $9)
+		ON CONFLICT (domain_id) DO UPDATE SET
+			provider = EXCLUDED.provider,
+			host = EXCLUDED.host,
+			port = EXCLUDED.port,
+			region = EXCLUDED.region,
+			username = EXCLUDED.username,
+			password = EXCLUDED.password,
+			from_address = EXCLUDED.from_address,
+			enabled = EXCLUDED.enabled,
+			last_tested_at = NULL,
+			last_test_error = NULL,
+			updated_at = NOW()
+		RETURNING updated_at`,
+		settings.DomainID, settings.Provider, settings.Host, settings.Port, settings.Region, settings.Username,
+		password, settings.FromAddress, settings.Enabled).Scan(&settings.UpdatedAt)
+}
+
+// RecordTest stores the outcome of a connection test; a nil testErr means it passed.
+func (r *domainMailSettingsRepository) RecordTest(ctx context.Context, domainID uuid.UUID, at time.Time, testErr *string) error {
+	ctx, end := observe(ctx, "domain_mail_settings", "record_test")
+	defer end()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE domain_mail_settings SET last_tested_at = $1, last_test_error = $2
+		WHERE domain_id = $3`, at, testErr, domainID)
+	return err
+}
+
+func (r *domainMailSettingsRepository) Delete(ctx context.Context, domainID uuid.UUID) error {
+	ctx, end := observe(ctx, "domain_mail_settings", "delete")
+	defer end()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM domain_mail_settings WHERE domain_id = $1", domainID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SealPlaintextPasswords seals the passwords stored before they were encrypted and returns how
+// many it sealed. A password changed meanwhile is left to its new value.
+func (r *domainMailSettingsRepository) SealPlaintextPasswords(ctx context.Context) (int, error) {
+	ctx, end := observe(ctx, "domain_mail_settings", "seal_plaintext_passwords")
+	defer end()
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT domain_id, password FROM domain_mail_settings
+		WHERE password IS NOT NULL AND password NOT LIKE 'aesgcm:v1:%'`)
+	if err != nil {
+		return 0, err
+	}
+	plaintext := map[uuid.UUID]string{}
+	for rows.Next() {
+		var domainID uuid.UUID
+		var password string
+		if err := rows.Scan(&domainID, &password); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		plaintext[domainID] = password
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	sealed := 0
+	for domainID, password := range plaintext {
+		value, err := r.credentials.Seal(password, domainID[:])
+		if err != nil {
+			return sealed, err
+		}
+		result, err := r.db.ExecContext(ctx, `
+			UPDATE domain_mail_settings SET password = $1 WHERE domain_id = $2 AND password = $3`, value, domainID, password)
+		if err != nil {
+			return sealed, err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+			sealed++
+		}
+	}
+	return sealed, nil
+}
@@ -1,21 +1,49 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type DomainRepository interface {
-	GetByID(id uuid.UUID) (*entities.Domain, error)
-	Create(domain *entities.Domain) error
-	List() ([]*entities.Domain, error)
-	ListWithPagination(search string, page, limit int) (*DomainListResult, error)
-	Update(domain *entities.Domain) error
-	Delete(id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Domain, error)
+	GetByHostname(ctx context.Context, hostname string) (*entities.Domain, error)
+	Create(ctx context.Context, domain *entities.Domain) error
+	List(ctx context.Context) ([]*entities.Domain, error)
+	ListWithPagination(ctx context.Context, search string, page, limit int) (*DomainListResult, error)
+	ListAfter(ctx context.Context, search string, after *ListCursor, limit int) (*DomainCursorPage, error)
+	ListBySelector(ctx context.Context, plan string, tags []string, ids []uuid.UUID) ([]*entities.Domain, error)
+	ListLoginTelemetryExporters(ctx context.Context) ([]uuid.UUID, error)
+	ClaimNamespaceOverlaps(ctx context.Context, namespace string, exceptID uuid.UUID) (bool, error)
+	Update(ctx context.Context, domain *entities.Domain) error
+	MarkForDeletion(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CountDependents(ctx context.Context, id uuid.UUID) (*DomainDependents, error)
+}
+
+// DomainDependents counts the rows deleted together with a domain.
+type DomainDependents struct {
+	Users             int `json:"users" example:"120"`
+	Roles             int `json:"roles" example:"4"`
+	Permissions       int `json:"permissions" example:"18"`
+	Groups            int `json:"groups" example:"3"`
+	Policies          int `json:"policies" example:"2"`
+	Invitations       int `json:"invitations" example:"5"`
+	RegistrationCodes int `json:"registration_codes" example:"1"`
+	Webhooks          int `json:"webhooks" example:"1"`
+	Events            int `json:"events" example:"5400"`
+	Aliases           int `json:"aliases" example:"2"`
+	APIKeys           int `json:"api_keys" example:"3"`
 }
 
 type DomainListResult struct {
@@ -26,31 +54,90 @@ type DomainListResult struct {
 	TotalPages int                `json:"total_pages"`
 }
 
+// DomainCursorPage is a page of a cursor-paginated domain listing. NextCursor is empty on the last page.
+type DomainCursorPage struct {
+	Domains    []*entities.Domain `json:"domains"`
+	Limit      int                `json:"limit"`
+	NextCursor string             `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNi0xMC0xNlQwODowMDowMFoiLCJpZCI6IjNmYTg1ZjY0LTU3MTctNDU2Mi1iM2ZjLTJjOTYzZjY2YWZhNiJ9"`
+}
+
+const domainColumns = "domain_id, name, domain, residency, login_mode, password_policy, registration, branding, account_deletion, token_settings, data_masking, telemetry, plan, tags, suspended_at, deletion_requested_at"
+
 type domainRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	router *ShardRouter
 }
 
-func NewDomainRepository(db *sql.DB) DomainRepository {
-	return &domainRepository{db: db}
+func NewDomainRepository(router *ShardRouter) DomainRepository {
+	return &domainRepository{db: router.Primary(), router: router}
 }
 
-func (r *domainRepository) GetByID(id uuid.UUID) (*entities.Domain, error) {
-	var domain entities.Domain
-	err := r.db.QueryRow("SELECT domain_id, name, domain FROM domains WHERE domain_id = $1", id).Scan(&domain.DomainID, &domain.Name, &domain.Domain)
-	if err != nil {
-		return nil, err
-	}
-	return &domain, nil
+func (r *domainRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Domain, error) {
+	ctx, end := observe(ctx, "domains", "get_by_id")
+	defer end()
+
+	return scanDomain(r.db.QueryRowContext(ctx, "SELECT "+domainColumns+" FROM domains WHERE domain_id = $1", id))
 }
 
-func (r *domainRepository) Create(domain *entities.Domain) error {
+// GetByHostname resolves a domain by its canonical hostname or any of its registered aliases.
+func (r *domainRepository) GetByHostname(ctx context.Context, hostname string) (*entities.Domain, error) {
+	ctx, end := observe(ctx, "domains", "get_by_hostname")
+	defer end()
+
+	return scanDomain(r.db.QueryRowContext(ctx, "SELECT "+domainColumns+` FROM domains d
+		WHERE d.domain = $1
+		   OR EXISTS (SELECT 1 FROM domain_aliases a WHERE a.domain_id = d.domain_id AND a.hostname = $1)
+		LIMIT 1`, hostname))
+}
+
+func (r *domainRepository) Create(ctx context.Context, domain *entities.Domain) error {
+	ctx, end := observe(ctx, "domains", "create")
+	defer end()
+
 	domain.DomainID = uuid.New()
-	err := r.db.QueryRow("INSERT INTO domains (domain_id, name, domain) VALUES ($1, $2, $3) RETURNING domain_id", domain.DomainID, domain.Name, domain.Domain).Scan(&domain.DomainID)
-	return err
+	if domain.Residency == "" {
+		domain.Residency = DefaultResidency
+	}
+	if domain.LoginMode == "" {
+		domain.LoginMode = entities.LoginModePassword
+	}
+	if !r.router.HasResidency(domain.Residency) {
+		return domainerrors.Validation("unknown data residency region %q", domain.Residency)
+	}
+
+	if domain.Tags == nil {
+		domain.Tags = []string{}
+	}
+	policyJSON, registrationJSON, brandingJSON, deletionJSON, tokenJSON, maskingJSON, telemetryJSON, err := marshalDomainSettings(domain)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRowContext(ctx, "INSERT INTO domains (domain_id, name, domain, residency, login_mode, password_policy, registration, branding, account_deletion, token_settings, data_masking, telemetry, plan, tags, suspended_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING domain_id",
+		domain.DomainID, domain.Name, domain.Domain, domain.Residency, domain.LoginMode, policyJSON, registrationJSON, brandingJSON, deletionJSON, tokenJSON, maskingJSON, telemetryJSON, domain.Plan, pq.Array(domain.Tags), domain.SuspendedAt).Scan(&domain.DomainID)
+	if err != nil {
+		return err
+	}
+
+	// Mirror the domain row into its residency shard so tenant tables can reference it
+	if shard := r.router.ForResidency(domain.Residency); shard != r.db {
+		_, err = shard.ExecContext(ctx, "INSERT INTO domains (domain_id, name, domain, residency, login_mode, password_policy, registration, branding, account_deletion, token_settings, data_masking, telemetry, plan, tags, suspended_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)",
+			domain.DomainID, domain.Name, domain.Domain, domain.Residency, domain.LoginMode, policyJSON, registrationJSON, brandingJSON, deletionJSON, tokenJSON, maskingJSON, telemetryJSON, domain.Plan, pq.Array(domain.Tags), domain.SuspendedAt)
+		if err != nil {
+			r.db.ExecContext(ctx, "DELETE FROM domains WHERE domain_id = $1", domain.DomainID)
+			return err
+		}
+	}
+
+	r.router.Remember(domain.DomainID, domain.Residency)
+	return nil
 }
 
-func (r *domainRepository) List() ([]*entities.Domain, error) {
-	rows, err := r.db.Query("SELECT domain_id, name, domain FROM domains ORDER BY name")
+func (r *domainRepository) List(ctx context.Context) ([]*entities.Domain, error) {
+	ctx, end := observe(ctx, "domains", "list")
+	defer end()
+
+	rows, err := r.db.QueryContext(ctx, "SELECT "+domainColumns+" FROM domains ORDER BY name")
 	if err != nil {
 		return nil, err
 	}
@@ -58,34 +145,116 @@ func (r *domainRepository) List() ([]*entities.Domain, error) {
 
 	var domains []*entities.Domain
 	for rows.Next() {
-		var domain entities.Domain
-		err := rows.Scan(&domain.DomainID, &domain.Name, &domain.Domain)
+		domain, err := scanDomain(rows)
 		if err != nil {
 			return nil, err
 		}
-		domains = append(domains, &domain)
+		domains = append(domains, domain)
 	}
 	return domains, nil
 }
 
-func (r *domainRepository) ListWithPagination(search string, page, limit int) (*DomainListResult, error) {
+// ListBySelector returns the domains on the plan (when set), carrying any of the tags (when set)
+// and among the IDs (when set). pq.Array binds a nil slice as NULL, which is not set either.
+func (r *domainRepository) ListBySelector(ctx context.Context, plan string, tags []string, ids []uuid.UUID) ([]*entities.Domain, error) {
+	ctx, end := observe(ctx, "domains", "list_by_selector")
+	defer end()
+
+	d := r.router.Dialect()
+	tagsParam, idsParam := d.Cast("$2", "text[]"), d.Cast("$3", "uuid[]")
+	rows, err := r.db.QueryContext(ctx, "SELECT "+domainColumns+` FROM domains
+		WHERE ($1 = '' OR plan = $1)
+		  AND (COALESCE(cardinality(`+tagsParam+`), 0) = 0 OR `+d.Overlaps("tags", tagsParam)+`)
+		  AND (COALESCE(cardinality(`+idsParam+`), 0) = 0 OR `+d.AnyOf("domain_id", idsParam)+`)
+		ORDER BY name`, plan, pq.Array(tags), pq.Array(uuidStrings(ids)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var domains []*entities.Domain
+	for rows.Next() {
+		domain, err := scanDomain(rows)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, domain)
+	}
+	return domains, rows.Err()
+}
+
+// ListLoginTelemetryExporters returns the IDs of the domains opted in to the login telemetry export.
+func (r *domainRepository) ListLoginTelemetryExporters(ctx context.Context) ([]uuid.UUID, error) {
+	ctx, end := observe(ctx, "domains", "list_login_telemetry_exporters")
+	defer end()
+
+	d := r.router.Dialect()
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT domain_id FROM domains
+		WHERE `+d.JSONContains("telemetry", d.Cast(`'{"login_export": true}'`, "jsonb"))+`
+		ORDER BY domain_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ClaimNamespaceOverlaps reports whether another domain namespaces its token claims under a prefix
+// of namespace or under a namespace that namespace is a prefix of. Domains without a custom
+// namespace use https://<domain>/claims/.
+func (r *domainRepository) ClaimNamespaceOverlaps(ctx context.Context, namespace string, exceptID uuid.UUID) (bool, error) {
+	ctx, end := observe(ctx, "domains", "claim_namespace_overlaps")
+	defer end()
+
+	d := r.router.Dialect()
+	var overlaps bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM (
+				SELECT COALESCE(NULLIF(token_settings->>'claim_namespace', ''), 'https://' || domain || '/claims/') AS namespace
+				FROM domains
+				WHERE domain_id <> $2 AND `+d.JSONContains("token_settings", d.Cast(`'{"namespace_claims": true}'`, "jsonb"))+`
+			) namespaced
+			WHERE starts_with($1, namespaced.namespace) OR starts_with(namespaced.namespace, $1))`,
+		namespace, exceptID).Scan(&overlaps)
+	return overlaps, err
+}
+
+func (r *domainRepository) ListWithPagination(ctx context.Context, search string, page, limit int) (*DomainListResult, error) {
+	ctx, end := observe(ctx, "domains", "list_with_pagination")
+	defer end()
+
+	// Listings tolerate replica lag up to the client's consistency token
+	db := r.router.ForRead(ctx, r.db)
+
 	// Calculate offset
 	offset := (page - 1) * limit
 
 	// Build the query with search condition
-	baseQuery := "SELECT domain_id, name, domain FROM domains"
+	baseQuery := "SELECT " + domainColumns + " FROM domains"
 	countQuery := "SELECT COUNT(*) FROM domains"
 	var args []interface{}
 	var whereClause string
 
 	if search != "" {
-		whereClause = " WHERE name ILIKE $1 OR domain ILIKE $1"
+		d := r.router.Dialect()
+		whereClause = " WHERE " + d.ILike("name", "$1") + " OR " + d.ILike("domain", "$1")
 		args = append(args, "%"+search+"%")
 	}
 
 	// Get total count
 	var total int
-	err := r.db.QueryRow(countQuery+whereClause, args...).Scan(&total)
+	err := db.QueryRowContext(ctx, countQuery+whereClause, args...).Scan(&total)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +263,7 @@ func (r *domainRepository) ListWithPagination(search string, page, limit int) (*
 	query := baseQuery + whereClause + " ORDER BY name LIMIT $" + fmt.Sprintf("%d", len(args)+1) + " OFFSET $" + fmt.Sprintf("%d", len(args)+2)
 	args = append(args, limit, offset)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -102,12 +271,11 @@ func (r *domainRepository) ListWithPagination(search string, page, limit int) (*
 
 	var domains []*entities.Domain
 	for rows.Next() {
-		var domain entities.Domain
-		err := rows.Scan(&domain.DomainID, &domain.Name, &domain.Domain)
+		domain, err := scanDomain(rows)
 		if err != nil {
 			return nil, err
 		}
-		domains = append(domains, &domain)
+		domains = append(domains, domain)
 	}
 
 	// Calculate total pages
@@ -122,12 +290,200 @@ func (r *domainRepository) ListWithPagination(search string, page, limit int) (*
 	}, nil
 }
 
-func (r *domainRepository) Update(domain *entities.Domain) error {
-	_, err := r.db.Exec("UPDATE domains SET name = $1, domain = $2 WHERE domain_id = $3", domain.Name, domain.Domain, domain.DomainID)
-	return err
+// ListAfter returns up to limit domains created after the cursor (from the first when after is
+// nil), oldest first.
+func (r *domainRepository) ListAfter(ctx context.Context, search string, after *ListCursor, limit int) (*DomainCursorPage, error) {
+	ctx, end := observe(ctx, "domains", "list_after")
+	defer end()
+
+	// Listings tolerate replica lag up to the client's consistency token
+	db := r.router.ForRead(ctx, r.db)
+
+	var conditions []string
+	var args []interface{}
+	if search != "" {
+		d := r.router.Dialect()
+		conditions = append(conditions, "("+d.ILike("name", "$1")+" OR "+d.ILike("domain", "$1")+")")
+		args = append(args, "%"+search+"%")
+	}
+	if after != nil {
+		var condition string
+		condition, args = cursorCondition(after, "domain_id", args)
+		conditions = append(conditions, condition)
+	}
+	var whereClause string
+	if len(conditions) > 0 {
+		whereClause = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Fetch one extra domain to learn whether another page follows
+	query := "SELECT " + domainColumns + ", created_at FROM domains" + whereClause +
+		" ORDER BY created_at, domain_id LIMIT $" + fmt.Sprintf("%d", len(args)+1)
+	rows, err := db.QueryContext(ctx, query, append(args, limit+1)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var domains []*entities.Domain
+	var createdAt []time.Time
+	for rows.Next() {
+		var created time.Time
+		domain, err := scanDomain(withExtraColumns(rows, &created))
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, domain)
+		createdAt = append(createdAt, created)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &DomainCursorPage{Domains: domains, Limit: limit}
+	if len(domains) > limit {
+		page.Domains = domains[:limit]
+		page.NextCursor = ListCursor{CreatedAt: createdAt[limit-1], ID: domains[limit-1].DomainID}.Encode()
+	}
+	return page, nil
 }
 
-func (r *domainRepository) Delete(id uuid.UUID) error {
-	_, err := r.db.Exec("DELETE FROM domains WHERE domain_id = $1", id)
-	return err
+func (r *domainRepository) Update(ctx context.Context, domain *entities.Domain) error {
+	ctx, end := observe(ctx, "domains", "update")
+	defer end()
+
+	if domain.Tags == nil {
+		domain.Tags = []string{}
+	}
+	policyJSON, registrationJSON, brandingJSON, deletionJSON, tokenJSON, maskingJSON, telemetryJSON, err := marshalDomainSettings(domain)
+	if err != nil {
+		return err
+	}
+
+	// Residency is fixed at creation; moving a tenant between shards is a data migration
+	return r.router.ExecAcross(ctx, "UPDATE domains SET name = $1, domain = $2, login_mode = $3, password_policy = $4, registration = $5, branding = $6, account_deletion = $7, token_settings = $8, data_masking = $9, telemetry = $10, plan = $11, tags = $12, suspended_at = $13 WHERE domain_id = $14",
+		domain.Name, domain.Domain, domain.LoginMode, policyJSON, registrationJSON, brandingJSON, deletionJSON, tokenJSON, maskingJSON, telemetryJSON, domain.Plan, pq.Array(domain.Tags), domain.SuspendedAt, domain.DomainID)
+}
+
+// MarkForDeletion sets deletion_requested_at on the domain and its shard mirror, unless it is
+// already set. Update leaves the column alone, so the mark can't be undone by a settings change.
+func (r *domainRepository) MarkForDeletion(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "domains", "mark_for_deletion")
+	defer end()
+
+	return r.router.ExecAcross(ctx, "UPDATE domains SET deletion_requested_at = COALESCE(deletion_requested_at, NOW()) WHERE domain_id = $1", id)
+}
+
+func (r *domainRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "domains", "delete")
+	defer end()
+
+	if err := r.router.ExecAcross(ctx, "DELETE FROM domains WHERE domain_id = $1", id); err != nil {
+		return err
+	}
+	r.router.Forget(id)
+	return nil
+}
+
+// CountDependents counts the domain's tenant rows on its shard and its aliases and API keys on the
+// primary.
+func (r *domainRepository) CountDependents(ctx context.Context, id uuid.UUID) (*DomainDependents, error) {
+	ctx, end := observe(ctx, "domains", "count_dependents")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependents DomainDependents
+	err = db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE domain_id = $1),
+			(SELECT COUNT(*) FROM roles WHERE domain_id = $1),
+			(SELECT COUNT(*) FROM permissions WHERE domain_id = $1),
+			(SELECT COUNT(*) FROM groups WHERE domain_id = $1),
+			(SELECT COUNT(*) FROM policies WHERE domain_id = $1),
+			(SELECT COUNT(*) FROM invitations WHERE domain_id = $1),
+			(SELECT COUNT(*) FROM registration_codes WHERE domain_id = $1),
+			(SELECT COUNT(*) FROM webhooks WHERE domain_id = $1),
+			(SELECT COUNT(*) FROM events WHERE domain_id = $1)`, id).Scan(
+		&dependents.Users, &dependents.Roles, &dependents.Permissions, &dependents.Groups, &dependents.Policies,
+		&dependents.Invitations, &dependents.RegistrationCodes, &dependents.Webhooks, &dependents.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM domain_aliases WHERE domain_id = $1),
+			(SELECT COUNT(*) FROM api_keys WHERE domain_id = $1)`, id).Scan(&dependents.Aliases, &dependents.APIKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &dependents, nil
+}
+
+func marshalDomainSettings(domain *entities.Domain) (policyJSON, registrationJSON, brandingJSON, deletionJSON, tokenJSON, maskingJSON, telemetryJSON []byte, err error) {
+	if policyJSON, err = json.Marshal(domain.PasswordPolicy); err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
+	if registrationJSON, err = json.Marshal(domain.Registration); err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
+	if brandingJSON, err = json.Marshal(domain.Branding); err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
+	if deletionJSON, err = json.Marshal(domain.AccountDeletion); err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
+	if tokenJSON, err = json.Marshal(domain.TokenSettings); err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
+	if maskingJSON, err = json.Marshal(domain.DataMasking); err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
+	if telemetryJSON, err = json.Marshal(domain.Telemetry); err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
+	return policyJSON, registrationJSON, brandingJSON, deletionJSON, tokenJSON, maskingJSON, telemetryJSON, nil
+}
+
+func scanDomain(row rowScanner) (*entities.Domain, error) {
+	var domain entities.Domain
+	var policyJSON, registrationJSON, brandingJSON, deletionJSON, tokenJSON, maskingJSON, telemetryJSON []byte
+	var suspendedAt, deletionRequestedAt sql.NullTime
+	err := row.Scan(&domain.DomainID, &domain.Name, &domain.Domain, &domain.Residency, &domain.LoginMode, &policyJSON, &registrationJSON, &brandingJSON, &deletionJSON, &tokenJSON, &maskingJSON,
+		&telemetryJSON, &domain.Plan, pq.Array(&domain.Tags), &suspendedAt, &deletionRequestedAt)
+	if err != nil {
+		return nil, err
+	}
+	if suspendedAt.Valid {
+		domain.SuspendedAt = &suspendedAt.Time
+	}
+	if deletionRequestedAt.Valid {
+		domain.DeletionRequestedAt = &deletionRequestedAt.Time
+	}
+	if err := json.Unmarshal(policyJSON, &domain.PasswordPolicy); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(registrationJSON, &domain.Registration); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(brandingJSON, &domain.Branding); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(deletionJSON, &domain.AccountDeletion); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tokenJSON, &domain.TokenSettings); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(maskingJSON, &domain.DataMasking); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(telemetryJSON, &domain.Telemetry); err != nil {
+		return nil, err
+	}
+	return &domain, nil
 }
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"errors"
+
+	"backend/internal/infrastructure/sqlitedb"
+
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the Postgres error code raised when a unique constraint or index rejects a write.
+const uniqueViolation = "23505"
+
+// UniqueViolation returns the name of the unique constraint or index that rejected the write,
+// or "" when err is not a unique violation. SQLite's constraints are named as Postgres names them.
+func UniqueViolation(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return pqErr.Constraint
+	}
+	var sqliteErr *sqlitedb.UniqueViolation
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Constraint
+	}
+	return ""
+}
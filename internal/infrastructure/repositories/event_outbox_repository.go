@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// EventOutboxRepository holds the events waiting to be published to the message broker.
+type EventOutboxRepository interface {
+	ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]*OutboxEntry, error)
+	Delete(ctx context.Context, domainID uuid.UUID, ids []int64) error
+	RecordFailure(ctx context.Context, domainID uuid.UUID, ids []int64, retryAfter time.Duration, reason string) error
+}
+
+// OutboxEntry is a claimed outbox row with its event.
+type OutboxEntry struct {
+	ID       int64
+	Attempts int
+	Event    *entities.Event
+}
+
+type eventOutboxRepository struct {
+	router *ShardRouter
+}
+
+func NewEventOutboxRepository(router *ShardRouter) EventOutboxRepository {
+	return &eventOutboxRepository{router: router}
+}
+
+// ClaimDue picks up to limit due entries from each database, in the order they were queued, and
+// holds them for lease so other instances skip them while they are being published. An entry
+// queued behind one of its domain that is in flight or waiting for a retry is not due yet.
+func (r *eventOutboxRepository) ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]*OutboxEntry, error) {
+	ctx, end := observe(ctx, "event_outbox", "claim_due")
+	defer end()
+
+	d := r.router.Dialect()
+	var due []*OutboxEntry
+	for _, db := range r.router.All() {
+		claimed, err := claimOutboxEntries(ctx, db, `
+			UPDATE event_outbox SET next_attempt_at = `+d.SecondsFromNow("$1")+`
+			WHERE id IN (
+				SELECT o.id FROM event_outbox o
+				WHERE o.next_attempt_at <= NOW() AND NOT EXISTS (
+					SELECT 1 FROM event_outbox earlier
+					WHERE earlier.domain_id = o.domain_id AND earlier.id < o.id
+						AND earlier.next_attempt_at > NOW())
+				ORDER BY o.id LIMIT $2`+d.SkipLocked()+`)
+			RETURNING id`,
+			lease.Seconds(), limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(claimed) == 0 {
+			continue
+		}
+		// The entries are held for lease, so no other instance changes them before they are read
+		rows, err := db.QueryContext(ctx, `
+			SELECT o.id, o.attempts, e.id, e.domain_id, e.sequence, e.type, e.subject_id, e.payload, e.impersonator_id, e.created_at
+			FROM event_outbox o JOIN events e ON e.id = o.event_id
+			WHERE `+d.AnyOf("o.id", "$1")+`
+			ORDER BY o.id`,
+			pq.Array(claimed))
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var entry OutboxEntry
+			var event entities.Event
+			var subjectID, impersonatorID uuid.NullUUID
+			var payload []byte
+			err := rows.Scan(&entry.ID, &entry.Attempts, &event.ID, &event.DomainID, &event.Sequence, &event.Type,
+				&subjectID, &payload, &impersonatorID, &event.CreatedAt)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if subjectID.Valid {
+				event.SubjectID = &subjectID.UUID
+			}
+			if impersonatorID.Valid {
+				event.ImpersonatorID = &impersonatorID.UUID
+			}
+			event.Payload = payload
+			entry.Event = &event
+			due = append(due, &entry)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return due, nil
+}
+
+// claimOutboxEntries runs the UPDATE claiming outbox entries and returns their IDs.
+func claimOutboxEntries(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]int64, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Delete removes published entries of the domain.
+func (r *eventOutboxRepository) Delete(ctx context.Context, domainID uuid.UUID, ids []int64) error {
+	ctx, end := observe(ctx, "event_outbox", "delete")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, "DELETE FROM event_outbox WHERE domain_id = $1 AND "+r.router.Dialect().AnyOf("id", "$2"), domainID, pq.Array(ids))
+	return err
+}
+
+// RecordFailure counts a failed publish attempt on the entries and holds them back for retryAfter.
+func (r *eventOutboxRepository) RecordFailure(ctx context.Context, domainID uuid.UUID, ids []int64, retryAfter time.Duration, reason string) error {
+	ctx, end := observe(ctx, "event_outbox", "record_failure")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	d := r.router.Dialect()
+	_, err = db.ExecContext(ctx, `
+		UPDATE event_outbox SET attempts = attempts + 1, last_error = $1,
+			next_attempt_at = `+d.SecondsFromNow("$2")+`
+		WHERE domain_id = $3 AND `+d.AnyOf("id", "$4"),
+		reason, retryAfter.Seconds(), domainID, pq.Array(ids))
+	return err
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type EventRepository interface {
+	Append(ctx context.Context, event *entities.Event) error
+	ListSince(ctx context.Context, domainID uuid.UUID, since int64, limit int) ([]*entities.Event, error)
+	ListTypesSince(ctx context.Context, domainID uuid.UUID, types []string, since int64, limit int) ([]*entities.Event, error)
+}
+
+type eventRepository struct {
+	router *ShardRouter
+	outbox bool
+}
+
+// NewEventRepository stores events on the router's databases. With outbox set every event is also
+// queued in event_outbox for the broker relay.
+func NewEventRepository(router *ShardRouter, outbox bool) EventRepository {
+	return &eventRepository{router: router, outbox: outbox}
+}
+
+// Append assigns the next sequence number of the event's domain and stores the event, queueing a
+// delivery for each enabled webhook of the domain subscribed to its type and, with the outbox
+// enabled, the event for the broker.
+func (r *eventRepository) Append(ctx context.Context, event *entities.Event) error {
+	ctx, end := observe(ctx, "events", "append")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, event.DomainID)
+	if err != nil {
+		return err
+	}
+
+	return inTx(ctx, db, func(tx DBTX) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO event_sequences (domain_id, last_sequence) VALUES ($1, 1)
+			ON CONFLICT (domain_id) DO UPDATE SET last_sequence = event_sequences.last_sequence + 1
+			RETURNING last_sequence`, event.DomainID).Scan(&event.Sequence)
+		if err != nil {
+			return err
+		}
+
+		event.ID = uuid.New()
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO events (id, domain_id, sequence, type, subject_id, payload, impersonator_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING created_at`,
+			event.ID, event.DomainID, event.Sequence, event.Type, event.SubjectID, []byte(event.Payload),
+			event.ImpersonatorID).Scan(&event.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries (webhook_id, domain_id, event_id, event_type, next_attempt_at)
+			SELECT id, domain_id, $2, $3, NOW() FROM webhooks
+			WHERE domain_id = $1 AND enabled AND (cardinality(events) = 0 OR `+r.router.Dialect().AnyOf("$3", "events")+`)`,
+			event.DomainID, event.ID, event.Type)
+		if err != nil || !r.outbox {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO event_outbox (event_id, domain_id) VALUES ($1, $2)", event.ID, event.DomainID)
+		return err
+	})
+}
+
+// ListSince returns up to limit events of the domain with a sequence greater than since, oldest first.
+func (r *eventRepository) ListSince(ctx context.Context, domainID uuid.UUID, since int64, limit int) ([]*entities.Event, error) {
+	ctx, end := observe(ctx, "events", "list_since")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, domain_id, sequence, type, subject_id, payload, impersonator_id, created_at
+		FROM events WHERE domain_id = $1 AND sequence > $2
+		ORDER BY sequence LIMIT $3`, domainID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanEvents(rows)
+}
+
+// ListTypesSince returns up to limit events of the domain of one of the types with a sequence
+// greater than since, oldest first.
+func (r *eventRepository) ListTypesSince(ctx context.Context, domainID uuid.UUID, types []string, since int64, limit int) ([]*entities.Event, error) {
+	ctx, end := observe(ctx, "events", "list_types_since")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, domain_id, sequence, type, subject_id, payload, impersonator_id, created_at
+		FROM events WHERE domain_id = $1 AND sequence > $2 AND `+r.router.Dialect().AnyOf("type", "$3")+`
+		ORDER BY sequence LIMIT $4`, domainID, since, pq.Array(types), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanEvents(rows)
+}
+
+func scanEvents(rows *sql.Rows) ([]*entities.Event, error) {
+	defer rows.Close()
+
+	var events []*entities.Event
+	for rows.Next() {
+		var event entities.Event
+		var subjectID, impersonatorID uuid.NullUUID
+		var payload []byte
+		if err := rows.Scan(&event.ID, &event.DomainID, &event.Sequence, &event.Type, &subjectID, &payload, &impersonatorID, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		if subjectID.Valid {
+			event.SubjectID = &subjectID.UUID
+		}
+		if impersonatorID.Valid {
+			event.ImpersonatorID = &impersonatorID.UUID
+		}
+		event.Payload = payload
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
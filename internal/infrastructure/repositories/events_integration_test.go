@@ -0,0 +1,319 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+func TestEventRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, router)
+	other, _ := createTestDomain(t, router)
+	events := NewEventRepository(router, false)
+
+	subject := uuid.New()
+	for _, eventType := range []string{"user.created", "user.updated", "role.created", "user.deleted"} {
+		event := &entities.Event{DomainID: domain.DomainID, Type: eventType, SubjectID: &subject, Payload: []byte(`{"id": "x"}`)}
+		if err := events.Append(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Sequences are numbered per domain
+	first := &entities.Event{DomainID: other.DomainID, Type: "user.created", Payload: []byte("{}")}
+	if err := events.Append(ctx, first); err != nil || first.Sequence != 1 {
+		t.Errorf("other domain's first event = %d, %v; want sequence 1", first.Sequence, err)
+	}
+
+	listed, err := events.ListSince(ctx, domain.DomainID, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sequences []int64
+	for _, event := range listed {
+		sequences = append(sequences, event.Sequence)
+	}
+	if !reflect.DeepEqual(sequences, []int64{2, 3}) || listed[0].SubjectID == nil || *listed[0].SubjectID != subject {
+		t.Errorf("since 1 = %v, %+v; want sequences [2 3] about the subject", sequences, listed)
+	}
+	if rest, err := events.ListSince(ctx, domain.DomainID, 4, 10); err != nil || len(rest) != 0 {
+		t.Errorf("since the last = %d events, %v; want none", len(rest), err)
+	}
+
+	typed, err := events.ListTypesSince(ctx, domain.DomainID, []string{"user.created", "user.deleted"}, 0, 10)
+	if err != nil || len(typed) != 2 || typed[0].Type != "user.created" || typed[1].Type != "user.deleted" {
+		t.Errorf("typed = %+v, %v; want user.created and user.deleted", typed, err)
+	}
+}
+
+func TestEventOutboxRecordFailure(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, router)
+	if err := NewEventRepository(router, true).Append(ctx, &entities.Event{DomainID: domain.DomainID, Type: "user.created", Payload: []byte("{}")}); err != nil {
+		t.Fatal(err)
+	}
+	outbox := NewEventOutboxRepository(router)
+
+	claim := func() []*OutboxEntry {
+		t.Helper()
+		entries, err := outbox.ClaimDue(ctx, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ours []*OutboxEntry
+		for _, entry := range entries {
+			if entry.Event.DomainID == domain.DomainID {
+				ours = append(ours, entry)
+			}
+		}
+		return ours
+	}
+	entries := claim()
+	if len(entries) != 1 || entries[0].Attempts != 0 {
+		t.Fatalf("claimed = %+v; want the event on its first attempt", entries)
+	}
+
+	// A failure is retried after the delay, not before
+	if err := outbox.RecordFailure(ctx, domain.DomainID, []int64{entries[0].ID}, time.Hour, "broker unavailable"); err != nil {
+		t.Fatal(err)
+	}
+	if again := claim(); len(again) != 0 {
+		t.Errorf("claimed before the retry = %+v; want nothing", again)
+	}
+	if err := outbox.RecordFailure(ctx, domain.DomainID, []int64{entries[0].ID}, -time.Second, "broker unavailable"); err != nil {
+		t.Fatal(err)
+	}
+	retried := claim()
+	if len(retried) != 1 || retried[0].Attempts != 2 {
+		t.Fatalf("retried = %+v; want the event after 2 attempts", retried)
+	}
+
+	if err := outbox.Delete(ctx, domain.DomainID, []int64{retried[0].ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.RecordFailure(ctx, domain.DomainID, []int64{retried[0].ID}, -time.Second, "gone"); err != nil {
+		t.Fatal(err)
+	}
+	if deleted := claim(); len(deleted) != 0 {
+		t.Errorf("claimed after deleting = %+v; want nothing", deleted)
+	}
+}
+
+func TestWebhookRepository(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, router)
+	other, _ := createTestDomain(t, router)
+	webhooks := NewWebhookRepository(router)
+
+	webhook := &entities.Webhook{DomainID: domain.DomainID, URL: "https://hooks.example.com/iam", Secret: "secret",
+		Events: []string{"user.created"}, Enabled: true}
+	everything := &entities.Webhook{DomainID: domain.DomainID, URL: "https://hooks.example.com/all", Secret: "secret",
+		Events: []string{}, Enabled: true}
+	for _, w := range []*entities.Webhook{webhook, everything} {
+		if err := webhooks.Create(ctx, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := webhooks.GetByID(ctx, domain.DomainID, webhook.ID)
+	if err != nil || got.Secret != "secret" || !reflect.DeepEqual(got.Events, []string{"user.created"}) {
+		t.Fatalf("by ID = %+v, %v", got, err)
+	}
+	if _, err := webhooks.GetByID(ctx, other.DomainID, webhook.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("another domain's webhook: err = %v, want sql.ErrNoRows", err)
+	}
+	if listed, err := webhooks.ListByDomain(ctx, domain.DomainID); err != nil || len(listed) != 2 {
+		t.Errorf("by domain = %d webhooks, %v; want 2", len(listed), err)
+	}
+	enabled, err := webhooks.ListEnabledDomainIDs(ctx)
+	if err != nil || !slices.Contains(enabled, domain.DomainID) || slices.Contains(enabled, other.DomainID) {
+		t.Errorf("domains with enabled webhooks = %v, %v; want %s and not %s", enabled, err, domain.DomainID, other.DomainID)
+	}
+
+	webhook.URL = "https://hooks.example.com/v2"
+	if err := webhooks.Update(ctx, webhook); err != nil {
+		t.Fatal(err)
+	}
+	if err := webhooks.Update(ctx, &entities.Webhook{ID: uuid.New(), DomainID: domain.DomainID, Events: []string{}}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("update unknown webhook: err = %v, want sql.ErrNoRows", err)
+	}
+	if err := webhooks.SetSecret(ctx, domain.DomainID, webhook.ID, "rotated"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := webhooks.GetByID(ctx, domain.DomainID, webhook.ID); err != nil || got.Secret != "rotated" || got.URL != webhook.URL {
+		t.Errorf("updated = %+v, %v", got, err)
+	}
+	if err := webhooks.SetSecret(ctx, other.DomainID, webhook.ID, "stolen"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("rotate another domain's secret: err = %v, want sql.ErrNoRows", err)
+	}
+
+	// Events queue a delivery for each subscribed webhook
+	events := NewEventRepository(router, false)
+	for _, eventType := range []string{"user.created", "role.created"} {
+		if err := events.Append(ctx, &entities.Event{DomainID: domain.DomainID, Type: eventType, Payload: []byte("{}")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if deliveries, err := webhooks.ListDeliveries(ctx, domain.DomainID, everything.ID, "", 10); err != nil || len(deliveries) != 2 {
+		t.Errorf("deliveries to the catch-all = %d, %v; want 2", len(deliveries), err)
+	}
+	deliveries, err := webhooks.ListDeliveries(ctx, domain.DomainID, webhook.ID, entities.WebhookDeliveryPending, 10)
+	if err != nil || len(deliveries) != 1 || deliveries[0].EventType != "user.created" {
+		t.Fatalf("pending deliveries = %+v, %v; want user.created", deliveries, err)
+	}
+
+	delivery := deliveries[0]
+	now := time.Now()
+	status, failure := 503, "endpoint returned status 503"
+	delivery.Status, delivery.Attempts, delivery.LastAttemptAt, delivery.ResponseStatus, delivery.Error, delivery.NextAttemptAt =
+		entities.WebhookDeliveryFailed, 5, &now, &status, &failure, nil
+	if err := webhooks.RecordAttempt(ctx, delivery); err != nil {
+		t.Fatal(err)
+	}
+	failed, err := webhooks.ListDeliveries(ctx, domain.DomainID, webhook.ID, entities.WebhookDeliveryFailed, 10)
+	if err != nil || len(failed) != 1 || failed[0].ResponseStatus == nil || *failed[0].ResponseStatus != 503 || failed[0].Attempts != 5 {
+		t.Errorf("failed deliveries = %+v, %v", failed, err)
+	}
+	if err := webhooks.Redeliver(ctx, domain.DomainID, webhook.ID, delivery.ID); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := webhooks.ListDeliveries(ctx, domain.DomainID, webhook.ID, entities.WebhookDeliveryPending, 10); err != nil || len(pending) != 1 || pending[0].Attempts != 0 {
+		t.Errorf("redelivered = %+v, %v; want it pending again", pending, err)
+	}
+	if err := webhooks.Redeliver(ctx, domain.DomainID, everything.ID, delivery.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("redeliver through another webhook: err = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := webhooks.Delete(ctx, domain.DomainID, webhook.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := webhooks.Delete(ctx, domain.DomainID, webhook.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted twice: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestJobRepository(t *testing.T) {
+	jobs := NewJobRepository(integrationDB(t))
+	ctx := context.Background()
+	kind := "test." + uuid.NewString()
+
+	before, err := jobs.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := &entities.Job{Kind: kind, Payload: []byte(`{"n": 2}`), MaxAttempts: 3, RunAt: time.Now().Add(time.Hour)}
+	due := &entities.Job{Kind: kind, Payload: []byte(`{"n": 1}`), MaxAttempts: 3}
+	for _, job := range []*entities.Job{later, due} {
+		if err := jobs.Create(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if after, err := jobs.Stats(ctx); err != nil || after.Pending != before.Pending+2 {
+		t.Errorf("stats = %+v, %v; want 2 more pending than %+v", after, err, before)
+	}
+	if _, err := jobs.GetByID(ctx, uuid.New()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown ID: err = %v, want sql.ErrNoRows", err)
+	}
+
+	// Only the due job is claimed
+	claimed, err := jobs.Claim(ctx, []string{kind}, time.Minute)
+	if err != nil || claimed.ID != due.ID || claimed.Status != entities.JobRunning {
+		t.Fatalf("claimed = %+v, %v; want the due job running", claimed, err)
+	}
+	if _, err := jobs.Claim(ctx, []string{"test." + uuid.NewString()}, time.Minute); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("claim another kind: err = %v, want sql.ErrNoRows", err)
+	}
+
+	if err := jobs.Retry(ctx, due.ID, time.Now().Add(-time.Second), "timeout"); err != nil {
+		t.Fatal(err)
+	}
+	retried, err := jobs.Claim(ctx, []string{kind}, time.Minute)
+	if err != nil || retried.ID != due.ID || retried.Attempts != 2 || retried.LastError != "timeout" {
+		t.Fatalf("retried = %+v, %v; want the job's second attempt", retried, err)
+	}
+	if err := jobs.Fail(ctx, due.ID, "gave up"); err != nil {
+		t.Fatal(err)
+	}
+	if listed, err := jobs.List(ctx, entities.JobFailed, kind, 10); err != nil || len(listed) != 1 || listed[0].LastError != "gave up" {
+		t.Errorf("failed jobs = %+v, %v", listed, err)
+	}
+
+	requeued, err := jobs.Requeue(ctx, due.ID)
+	if err != nil || requeued.Status != entities.JobPending || requeued.Attempts != 0 {
+		t.Fatalf("requeued = %+v, %v; want it pending with fresh attempts", requeued, err)
+	}
+	if _, err := jobs.Requeue(ctx, due.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("requeue a pending job: err = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := jobs.Claim(ctx, []string{kind}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.Complete(ctx, due.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, err := jobs.GetByID(ctx, due.ID)
+	if err != nil || got.Status != entities.JobSucceeded || got.FinishedAt == nil || string(got.Payload) == "" {
+		t.Errorf("completed = %+v, %v", got, err)
+	}
+	if listed, err := jobs.List(ctx, "", kind, 10); err != nil || len(listed) != 2 {
+		t.Errorf("jobs of the kind = %d, %v; want 2", len(listed), err)
+	}
+}
+
+func TestHealthRepository(t *testing.T) {
+	statuses := NewHealthRepository(NewShardRouter(integrationDB(t), nil, nil)).ProbeDatabases(context.Background())
+	if len(statuses) != 1 {
+		t.Fatalf("statuses = %+v; want the primary alone", statuses)
+	}
+	if status := statuses[0]; !status.Primary || !status.Reachable || status.ReadOnly || status.Err != nil {
+		t.Errorf("primary = %+v; want it reachable and writable", status)
+	}
+}
+
+func TestTxManager(t *testing.T) {
+	router := NewShardRouter(integrationDB(t), nil, nil)
+	ctx := context.Background()
+	domain, _ := createTestDomain(t, router)
+	roles := NewRoleRepository(router)
+	txs := NewTxManager(router)
+
+	committed := &entities.Role{DomainID: domain.DomainID, RoleName: "committed", RoleClaims: map[string]interface{}{}}
+	rolledBack := &entities.Role{DomainID: domain.DomainID, RoleName: "rolled-back", RoleClaims: map[string]interface{}{}}
+	if err := txs.WithinTenantTx(ctx, domain.DomainID, func(ctx context.Context) error {
+		// Nested calls join the open transaction
+		return txs.WithinTenantTx(ctx, domain.DomainID, func(ctx context.Context) error {
+			return roles.Create(ctx, committed)
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	failure := errors.New("abort")
+	if err := txs.WithinTenantTx(ctx, domain.DomainID, func(ctx context.Context) error {
+		if err := roles.Create(ctx, rolledBack); err != nil {
+			return err
+		}
+		return failure
+	}); !errors.Is(err, failure) {
+		t.Errorf("aborted transaction: err = %v, want fn's error", err)
+	}
+
+	if _, err := roles.GetByID(ctx, committed.ID); err != nil {
+		t.Errorf("committed role: %v", err)
+	}
+	if _, err := roles.GetByID(ctx, rolledBack.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("rolled back role: err = %v, want sql.ErrNoRows", err)
+	}
+}
@@ -0,0 +1,311 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type GroupRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Group, error)
+	GetByName(ctx context.Context, domainID uuid.UUID, name string) (*entities.Group, error)
+	GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.Group, error)
+	GetByUserID(ctx context.Context, domainID, userID uuid.UUID) ([]*entities.Group, error)
+	Create(ctx context.Context, group *entities.Group) error
+	Update(ctx context.Context, group *entities.Group) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListMembers(ctx context.Context, domainID, groupID uuid.UUID) ([]*entities.User, error)
+	AddMember(ctx context.Context, domainID, groupID, userID uuid.UUID) error
+	RemoveMember(ctx context.Context, domainID, groupID, userID uuid.UUID) error
+	ListRoleIDs(ctx context.Context, domainID, groupID uuid.UUID) ([]uuid.UUID, error)
+	ListInheritedRoleIDs(ctx context.Context, domainID, userID uuid.UUID) ([]uuid.UUID, error)
+	AddRole(ctx context.Context, domainID, groupID, roleID uuid.UUID) error
+	RemoveRole(ctx context.Context, domainID, groupID, roleID uuid.UUID) error
+}
+
+type groupRepository struct {
+	router *ShardRouter
+}
+
+func NewGroupRepository(router *ShardRouter) GroupRepository {
+	return &groupRepository{router: router}
+}
+
+func (r *groupRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Group, error) {
+	ctx, end := observe(ctx, "groups", "get_by_id")
+	defer end()
+
+	var group entities.Group
+	err := r.router.QueryRowAcross(ctx, func(db *sql.DB) error {
+		return db.QueryRowContext(ctx, `
+			SELECT id, domain_id, name, description, created_at, updated_at
+			FROM groups WHERE id = $1`, id).Scan(
+			&group.ID, &group.DomainID, &group.Name, &group.Description, &group.CreatedAt, &group.UpdatedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) GetByName(ctx context.Context, domainID uuid.UUID, name string) (*entities.Group, error) {
+	ctx, end := observe(ctx, "groups", "get_by_name")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var group entities.Group
+	err = db.QueryRowContext(ctx, `
+		SELECT id, domain_id, name, description, created_at, updated_at
+		FROM groups WHERE domain_id = $1 AND name = $2`, domainID, name).Scan(
+		&group.ID, &group.DomainID, &group.Name, &group.Description, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.Group, error) {
+	ctx, end := observe(ctx, "groups", "get_by_domain_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, domain_id, name, description, created_at, updated_at
+		FROM groups WHERE domain_id = $1 ORDER BY name`, domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanGroups(rows)
+}
+
+func (r *groupRepository) GetByUserID(ctx context.Context, domainID, userID uuid.UUID) ([]*entities.Group, error) {
+	ctx, end := observe(ctx, "groups", "get_by_user_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT g.id, g.domain_id, g.name, g.description, g.created_at, g.updated_at
+		FROM groups g JOIN group_members gm ON gm.group_id = g.id
+		WHERE gm.user_id = $1 ORDER BY g.name`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanGroups(rows)
+}
+
+func (r *groupRepository) Create(ctx context.Context, group *entities.Group) error {
+	ctx, end := observe(ctx, "groups", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, group.DomainID)
+	if err != nil {
+		return err
+	}
+
+	group.ID = uuid.New()
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO groups (id, domain_id, name, description)
+		VALUES ($1, $2, $3, $4) RETURNING created_at, updated_at`,
+		group.ID, group.DomainID, group.Name, group.Description).Scan(&group.CreatedAt, &group.UpdatedAt)
+	return err
+}
+
+func (r *groupRepository) Update(ctx context.Context, group *entities.Group) error {
+	ctx, end := observe(ctx, "groups", "update")
+	defer end()
+
+	return r.router.ExecAcross(ctx, `
+		UPDATE groups SET name = $1, description = $2, updated_at = NOW()
+		WHERE id = $3`, group.Name, group.Description, group.ID)
+}
+
+func (r *groupRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "groups", "delete")
+	defer end()
+
+	return r.router.ExecAcross(ctx, "DELETE FROM groups WHERE id = $1", id)
+}
+
+func (r *groupRepository) ListMembers(ctx context.Context, domainID, groupID uuid.UUID) ([]*entities.User, error) {
+	ctx, end := observe(ctx, "group_members", "list")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+userColumnsAs("u")+`
+		FROM users u JOIN group_members gm ON gm.user_id = u.id
+		WHERE gm.group_id = $1 ORDER BY u.username`, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (r *groupRepository) AddMember(ctx context.Context, domainID, groupID, userID uuid.UUID) error {
+	ctx, end := observe(ctx, "group_members", "add")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	// Adding twice is a no-op
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO group_members (group_id, user_id) VALUES ($1, $2)
+		ON CONFLICT (group_id, user_id) DO NOTHING`, groupID, userID)
+	return err
+}
+
+func (r *groupRepository) RemoveMember(ctx context.Context, domainID, groupID, userID uuid.UUID) error {
+	ctx, end := observe(ctx, "group_members", "remove")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	return execExpectingRow(ctx, db, "DELETE FROM group_members WHERE group_id = $1 AND user_id = $2", groupID, userID)
+}
+
+func (r *groupRepository) ListRoleIDs(ctx context.Context, domainID, groupID uuid.UUID) ([]uuid.UUID, error) {
+	ctx, end := observe(ctx, "group_roles", "list")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT role_id FROM group_roles WHERE group_id = $1 ORDER BY created_at", groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanIDs(rows)
+}
+
+// ListInheritedRoleIDs returns the distinct roles a user receives through group membership.
+func (r *groupRepository) ListInheritedRoleIDs(ctx context.Context, domainID, userID uuid.UUID) ([]uuid.UUID, error) {
+	ctx, end := observe(ctx, "group_roles", "list_inherited")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT DISTINCT gr.role_id
+		FROM group_roles gr JOIN group_members gm ON gm.group_id = gr.group_id
+		WHERE gm.user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanIDs(rows)
+}
+
+func (r *groupRepository) AddRole(ctx context.Context, domainID, groupID, roleID uuid.UUID) error {
+	ctx, end := observe(ctx, "group_roles", "add")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	// Adding twice is a no-op
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO group_roles (group_id, role_id) VALUES ($1, $2)
+		ON CONFLICT (group_id, role_id) DO NOTHING`, groupID, roleID)
+	return err
+}
+
+func (r *groupRepository) RemoveRole(ctx context.Context, domainID, groupID, roleID uuid.UUID) error {
+	ctx, end := observe(ctx, "group_roles", "remove")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	return execExpectingRow(ctx, db, "DELETE FROM group_roles WHERE group_id = $1 AND role_id = $2", groupID, roleID)
+}
+
+func scanGroups(rows *sql.Rows) ([]*entities.Group, error) {
+	var groups []*entities.Group
+	for rows.Next() {
+		var group entities.Group
+		err := rows.Scan(&group.ID, &group.DomainID, &group.Name, &group.Description, &group.CreatedAt, &group.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, &group)
+	}
+	return groups, nil
+}
+
+func scanIDs(rows *sql.Rows) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// execExpectingRow runs a statement and reports sql.ErrNoRows when it affected nothing.
+func execExpectingRow(ctx context.Context, db DBTX, query string, args ...interface{}) error {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
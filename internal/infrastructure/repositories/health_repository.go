@@ -0,0 +1,40 @@
+package repositories
+
+import "context"
+
+// DatabaseStatus is the result of probing the primary database or a residency shard.
+type DatabaseStatus struct {
+	Residency string
+	Primary   bool
+	Reachable bool
+	ReadOnly  bool // a standby in recovery, or default_transaction_read_only is on
+	Err       error
+}
+
+type HealthRepository interface {
+	ProbeDatabases(ctx context.Context) []DatabaseStatus
+}
+
+type healthRepository struct {
+	router *ShardRouter
+}
+
+func NewHealthRepository(router *ShardRouter) HealthRepository {
+	return &healthRepository{router: router}
+}
+
+// ProbeDatabases checks that every database answers and accepts writes, primary first.
+func (r *healthRepository) ProbeDatabases(ctx context.Context) []DatabaseStatus {
+	ctx, end := observe(ctx, "health", "probe")
+	defer end()
+
+	residencies := r.router.Residencies()
+	statuses := make([]DatabaseStatus, 0, len(residencies))
+	for i, db := range r.router.All() {
+		status := DatabaseStatus{Residency: residencies[i], Primary: i == 0}
+		status.Err = db.QueryRowContext(ctx, "SELECT "+r.router.Dialect().ReadOnly()).Scan(&status.ReadOnly)
+		status.Reachable = status.Err == nil
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
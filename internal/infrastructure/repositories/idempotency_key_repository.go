@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// IdempotencyRecord is a stored response to a request sent with an Idempotency-Key. StatusCode is 0
+// while the first request with the key is still in flight.
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+type IdempotencyKeyRepository interface {
+	// Reserve claims the record's key for a new request, returning nil, or returns the record
+	// already holding it. Expired keys, and reservations made before staleBefore that never got a
+	// response, are taken over.
+	Reserve(ctx context.Context, record *IdempotencyRecord, staleBefore time.Time) (*IdempotencyRecord, error)
+	Complete(ctx context.Context, record *IdempotencyRecord) error
+	Release(ctx context.Context, scope, key string) error
+	// DeleteExpired removes keys expired before now, returning how many were removed
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type idempotencyKeyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyKeyRepository keeps idempotency keys in the idempotency_keys table of the primary
+// database.
+func NewIdempotencyKeyRepository(db *sql.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+func (r *idempotencyKeyRepository) Reserve(ctx context.Context, record *IdempotencyRecord, staleBefore time.Time) (*IdempotencyRecord, error) {
+	ctx, end := observe(ctx, "idempotency_keys", "reserve")
+	defer end()
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (scope, idempotency_key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL,
+			response_body = NULL, created_at = NOW(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < NOW()
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < $5)
+		RETURNING created_at`,
+		record.Scope, record.Key, record.RequestHash, record.ExpiresAt, staleBefore,
+	).Scan(&record.CreatedAt)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	// The key is held by a live request or response
+	var existing IdempotencyRecord
+	var status sql.NullInt64
+	var contentType sql.NullString
+	err = r.db.QueryRowContext(ctx, `
+		SELECT scope, idempotency_key, request_hash, status_code, content_type, response_body, created_at, expires_at
+		FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`, record.Scope, record.Key).Scan(
+		&existing.Scope, &existing.Key, &existing.RequestHash, &status, &contentType, &existing.Body,
+		&existing.CreatedAt, &existing.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	existing.StatusCode = int(status.Int64)
+	existing.ContentType = contentType.String
+	return &existing, nil
+}
+
+func (r *idempotencyKeyRepository) Complete(ctx context.Context, record *IdempotencyRecord) error {
+	ctx, end := observe(ctx, "idempotency_keys", "complete")
+	defer end()
+
+	return execExpectingRow(ctx, r.db, `
+		UPDATE idempotency_keys SET status_code = $1, content_type = $2, response_body = $3
+		WHERE scope = $4 AND idempotency_key = $5`,
+		record.StatusCode, record.ContentType, record.Body, record.Scope, record.Key)
+}
+
+func (r *idempotencyKeyRepository) Release(ctx context.Context, scope, key string) error {
+	ctx, end := observe(ctx, "idempotency_keys", "release")
+	defer end()
+
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2 AND status_code IS NULL`, scope, key)
+	return err
+}
+
+func (r *idempotencyKeyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	ctx, end := observe(ctx, "idempotency_keys", "delete_expired")
+	defer end()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at < $1", now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import "github.com/google/uuid"
+
+// uuidStrings formats IDs for a $n::uuid[] parameter; pq.Array can't encode uuid.UUID itself.
+func uuidStrings(ids []uuid.UUID) []string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	return strs
+}
+
+// withoutIDs returns the IDs not in found, keeping their order.
+func withoutIDs(ids []uuid.UUID, found map[uuid.UUID]bool) []uuid.UUID {
+	var rest []uuid.UUID
+	for _, id := range ids {
+		if !found[id] {
+			rest = append(rest, id)
+		}
+	}
+	return rest
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"backend/internal/infrastructure/faults"
+	"backend/internal/infrastructure/metrics"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("backend/internal/infrastructure/repositories")
+
+// observe starts a span for a repository call and records its query duration when the
+// returned func runs. The SQL statements themselves are traced as child spans by otelsql.
+// Injected database latency is spent here, so it shows up in both.
+func observe(ctx context.Context, table, operation string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, table+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.sql.table", table),
+			attribute.String("db.operation", operation),
+		))
+	faults.DelayDB(ctx)
+	return ctx, func() {
+		span.End()
+		metrics.ObserveDBQuery(table, operation, start)
+	}
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type IntegrationHealthRepository interface {
+	Get(ctx context.Context, domainID uuid.UUID, kind string) (*entities.IntegrationHealth, error)
+	Save(ctx context.Context, health *entities.IntegrationHealth) error
+}
+
+type integrationHealthRepository struct {
+	db *sql.DB
+}
+
+func NewIntegrationHealthRepository(db *sql.DB) IntegrationHealthRepository {
+	return &integrationHealthRepository{db: db}
+}
+
+func (r *integrationHealthRepository) Get(ctx context.Context, domainID uuid.UUID, kind string) (*entities.IntegrationHealth, error) {
+	ctx, end := observe(ctx, "integration_health", "get")
+	defer end()
+
+	var health entities.IntegrationHealth
+	var lastError sql.NullString
+	var checkedAt, successAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT domain_id, kind, status, consecutive_failures, last_error, last_checked_at, last_success_at
+		FROM integration_health WHERE domain_id = $1 AND kind = $2`, domainID, kind).Scan(
+		&health.DomainID, &health.Kind, &health.Status, &health.ConsecutiveFailures, &lastError, &checkedAt, &successAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastError.Valid {
+		health.LastError = &lastError.String
+	}
+	if checkedAt.Valid {
+		health.LastCheckedAt = &checkedAt.Time
+	}
+	if successAt.Valid {
+		health.LastSuccessAt = &successAt.Time
+	}
+	return &health, nil
+}
+
+func (r *integrationHealthRepository) Save(ctx context.Context, health *entities.IntegrationHealth) error {
+	ctx, end := observe(ctx, "integration_health", "save")
+	defer end()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO integration_health (domain_id, kind, status, consecutive_failures, last_error, last_checked_at, last_success_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (domain_id, kind) DO UPDATE SET
+			status = EXCLUDED.status,
+			consecutive_failures = EXCLUDED.consecutive_failures,
+			last_error = EXCLUDED.last_error,
+			last_checked_at = EXCLUDED.last_checked_at,
+			last_success_at = EXCLUDED.last_success_at`,
+		health.DomainID, health.Kind, health.Status, health.ConsecutiveFailures, health.LastError,
+		health.LastCheckedAt, health.LastSuccessAt)
+	return err
+}
@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *entities.Invitation) error
+	GetByID(ctx context.Context, domainID, id uuid.UUID) (*entities.Invitation, error)
+	GetByTokenHash(ctx context.Context, domainID uuid.UUID, tokenHash string) (*entities.Invitation, error)
+	GetPendingByEmail(ctx context.Context, domainID uuid.UUID, email string) (*entities.Invitation, error)
+	ListByDomain(ctx context.Context, domainID uuid.UUID, status string) ([]*entities.Invitation, error)
+	Renew(ctx context.Context, invitation *entities.Invitation) error
+	Accept(ctx context.Context, domainID, id uuid.UUID) error
+	Reopen(ctx context.Context, domainID, id uuid.UUID) error
+	Revoke(ctx context.Context, domainID, id uuid.UUID) error
+}
+
+type invitationRepository struct {
+	router *ShardRouter
+}
+
+func NewInvitationRepository(router *ShardRouter) InvitationRepository {
+	return &invitationRepository{router: router}
+}
+
+const invitationColumns = "id, domain_id, email, role_id, first_name, last_name, token_hash, expires_at, sent_at, accepted_at, revoked_at, created_at"
+
+// invitationStatusFilters holds the WHERE condition selecting each invitation status.
+var invitationStatusFilters = map[string]string{
+	entities.InvitationPending:  " AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()",
+	entities.InvitationAccepted: " AND accepted_at IS NOT NULL",
+	entities.InvitationRevoked:  " AND accepted_at IS NULL AND revoked_at IS NOT NULL",
+	entities.InvitationExpired:  " AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at <= NOW()",
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *entities.Invitation) error {
+	ctx, end := observe(ctx, "invitations", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, invitation.DomainID)
+	if err != nil {
+		return err
+	}
+
+	invitation.ID = uuid.New()
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO invitations (id, domain_id, email, role_id, first_name, last_name, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING sent_at, created_at`,
+		invitation.ID, invitation.DomainID, invitation.Email, invitation.RoleID, invitation.FirstName, invitation.LastName,
+		invitation.TokenHash, invitation.ExpiresAt).Scan(&invitation.SentAt, &invitation.CreatedAt)
+	if err != nil {
+		return err
+	}
+	invitation.Status = invitation.StatusAt(time.Now())
+	return nil
+}
+
+func (r *invitationRepository) GetByID(ctx context.Context, domainID, id uuid.UUID) (*entities.Invitation, error) {
+	ctx, end := observe(ctx, "invitations", "get_by_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanInvitation(db.QueryRowContext(ctx, "SELECT "+invitationColumns+" FROM invitations WHERE id = $1 AND domain_id = $2", id, domainID))
+}
+
+func (r *invitationRepository) GetByTokenHash(ctx context.Context, domainID uuid.UUID, tokenHash string) (*entities.Invitation, error) {
+	ctx, end := observe(ctx, "invitations", "get_by_token_hash")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanInvitation(db.QueryRowContext(ctx, "SELECT "+invitationColumns+" FROM invitations WHERE domain_id = $1 AND token_hash = $2", domainID, tokenHash))
+}
+
+// GetPendingByEmail returns the unexpired, unanswered invitation for the email, if any.
+func (r *invitationRepository) GetPendingByEmail(ctx context.Context, domainID uuid.UUID, email string) (*entities.Invitation, error) {
+	ctx, end := observe(ctx, "invitations", "get_pending_by_email")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanInvitation(db.QueryRowContext(ctx, "SELECT "+invitationColumns+" FROM invitations WHERE domain_id = $1 AND LOWER(email) = LOWER($2)"+
+		invitationStatusFilters[entities.InvitationPending]+" LIMIT 1", domainID, email))
+}
+
+// ListByDomain returns the domain's invitations, newest first; an empty status lists all of them.
+func (r *invitationRepository) ListByDomain(ctx context.Context, domainID uuid.UUID, status string) ([]*entities.Invitation, error) {
+	ctx, end := observe(ctx, "invitations", "list_by_domain")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, "SELECT "+invitationColumns+" FROM invitations WHERE domain_id = $1"+
+		invitationStatusFilters[status]+" ORDER BY created_at DESC", domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []*entities.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+	return invitations, rows.Err()
+}
+
+// Renew stores a new token and expiry for an unanswered invitation; it fails with sql.ErrNoRows
+// once the invitation has been accepted or revoked.
+func (r *invitationRepository) Renew(ctx context.Context, invitation *entities.Invitation) error {
+	ctx, end := observe(ctx, "invitations", "renew")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, invitation.DomainID)
+	if err != nil {
+		return err
+	}
+	err = db.QueryRowContext(ctx, `UPDATE invitations SET token_hash = $1, expires_at = $2, sent_at = NOW()
+		WHERE id = $3 AND domain_id = $4 AND accepted_at IS NULL AND revoked_at IS NULL RETURNING sent_at`,
+		invitation.TokenHash, invitation.ExpiresAt, invitation.ID, invitation.DomainID).Scan(&invitation.SentAt)
+	if err != nil {
+		return err
+	}
+	invitation.Status = invitation.StatusAt(time.Now())
+	return nil
+}
+
+// Accept marks a pending invitation accepted; it fails with sql.ErrNoRows if the invitation was
+// revoked, has expired or was accepted by a concurrent request.
+func (r *invitationRepository) Accept(ctx context.Context, domainID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "invitations", "accept")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	return execExpectingRow(ctx, db, "UPDATE invitations SET accepted_at = NOW() WHERE id = $1 AND domain_id = $2"+
+		invitationStatusFilters[entities.InvitationPending], id, domainID)
+}
+
+// Reopen undoes Accept when creating the invited user fails.
+func (r *invitationRepository) Reopen(ctx context.Context, domainID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "invitations", "reopen")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, "UPDATE invitations SET accepted_at = NULL WHERE id = $1 AND domain_id = $2", id, domainID)
+	return err
+}
+
+func (r *invitationRepository) Revoke(ctx context.Context, domainID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "invitations", "revoke")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	return execExpectingRow(ctx, db, "UPDATE invitations SET revoked_at = NOW() WHERE id = $1 AND domain_id = $2 AND accepted_at IS NULL AND revoked_at IS NULL", id, domainID)
+}
+
+func scanInvitation(row rowScanner) (*entities.Invitation, error) {
+	var invitation entities.Invitation
+	var acceptedAt, revokedAt sql.NullTime
+	err := row.Scan(&invitation.ID, &invitation.DomainID, &invitation.Email, &invitation.RoleID, &invitation.FirstName,
+		&invitation.LastName, &invitation.TokenHash, &invitation.ExpiresAt, &invitation.SentAt, &acceptedAt, &revokedAt,
+		&invitation.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if acceptedAt.Valid {
+		invitation.AcceptedAt = &acceptedAt.Time
+	}
+	if revokedAt.Valid {
+		invitation.RevokedAt = &revokedAt.Time
+	}
+	invitation.Status = invitation.StatusAt(time.Now())
+	return &invitation, nil
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type JobRepository interface {
+	Create(ctx context.Context, job *entities.Job) error
+	Claim(ctx context.Context, kinds []string, lease time.Duration) (*entities.Job, error)
+	Complete(ctx context.Context, id uuid.UUID) error
+	Retry(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error
+	Fail(ctx context.Context, id uuid.UUID, lastError string) error
+	Requeue(ctx context.Context, id uuid.UUID) (*entities.Job, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Job, error)
+	List(ctx context.Context, status, kind string, limit int) ([]*entities.Job, error)
+	Stats(ctx context.Context) (*entities.JobStats, error)
+}
+
+type jobRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewJobRepository keeps the job queue on the primary database, so every instance's workers share it.
+func NewJobRepository(db *sql.DB) JobRepository {
+	return &jobRepository{db: db, dialect: DialectOf(db)}
+}
+
+const jobColumns = "id, kind, payload, status, attempts, max_attempts, run_at, last_error, created_at, started_at, finished_at"
+
+// Create queues the job as pending, due at job.RunAt or at once when it is zero.
+func (r *jobRepository) Create(ctx context.Context, job *entities.Job) error {
+	ctx, end := observe(ctx, "jobs", "create")
+	defer end()
+
+	job.ID = uuid.New()
+	job.Status = entities.JobPending
+	var runAt *time.Time
+	if !job.RunAt.IsZero() {
+		runAt = &job.RunAt
+	}
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO jobs (id, kind, payload, status, max_attempts, run_at)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, NOW())) RETURNING run_at, created_at`,
+		job.ID, job.Kind, []byte(job.Payload), job.Status, job.MaxAttempts, runAt).Scan(&job.RunAt, &job.CreatedAt)
+}
+
+// Claim takes the oldest due pending job of one of the kinds, or a running one whose worker let
+// its lease expire, counts the attempt and holds the job for lease. It returns sql.ErrNoRows when
+// there is none.
+func (r *jobRepository) Claim(ctx context.Context, kinds []string, lease time.Duration) (*entities.Job, error) {
+	ctx, end := observe(ctx, "jobs", "claim")
+	defer end()
+
+	return scanJob(r.db.QueryRowContext(ctx, `
+		UPDATE jobs SET status = $1, attempts = attempts + 1, started_at = NOW(),
+			lease_until = `+r.dialect.SecondsFromNow("$2")+`
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE `+r.dialect.AnyOf("kind", "$3")+` AND run_at <= NOW()
+				AND (status = $4 OR (status = $1 AND lease_until < NOW()))
+			ORDER BY run_at LIMIT 1`+r.dialect.SkipLocked()+`)
+		RETURNING `+jobColumns,
+		entities.JobRunning, lease.Seconds(), pq.Array(kinds), entities.JobPending))
+}
+
+func (r *jobRepository) Complete(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "jobs", "complete")
+	defer end()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET status = $1, last_error = '', lease_until = NULL, finished_at = NOW()
+		WHERE id = $2`, entities.JobSucceeded, id)
+	return err
+}
+
+// Retry puts a job whose run failed back to pending, due at runAt.
+func (r *jobRepository) Retry(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error {
+	ctx, end := observe(ctx, "jobs", "retry")
+	defer end()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET status = $1, run_at = $2, last_error = $3, lease_until = NULL
+		WHERE id = $4`, entities.JobPending, runAt, lastError, id)
+	return err
+}
+
+// Fail marks the job failed for good.
+func (r *jobRepository) Fail(ctx context.Context, id uuid.UUID, lastError string) error {
+	ctx, end := observe(ctx, "jobs", "fail")
+	defer end()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET status = $1, last_error = $2, lease_until = NULL, finished_at = NOW()
+		WHERE id = $3`, entities.JobFailed, lastError, id)
+	return err
+}
+
+// Requeue gives a failed job a fresh set of attempts, due at once. It returns sql.ErrNoRows when
+// the job doesn't exist or hasn't failed.
+func (r *jobRepository) Requeue(ctx context.Context, id uuid.UUID) (*entities.Job, error) {
+	ctx, end := observe(ctx, "jobs", "requeue")
+	defer end()
+
+	return scanJob(r.db.QueryRowContext(ctx, `
+		UPDATE jobs SET status = $1, attempts = 0, run_at = NOW(), finished_at = NULL
+		WHERE id = $2 AND status = $3
+		RETURNING `+jobColumns, entities.JobPending, id, entities.JobFailed))
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Job, error) {
+	ctx, end := observe(ctx, "jobs", "get_by_id")
+	defer end()
+
+	return scanJob(r.db.QueryRowContext(ctx, "SELECT "+jobColumns+" FROM jobs WHERE id = $1", id))
+}
+
+// List returns the most recent jobs first, filtered by status and kind when they are set.
+func (r *jobRepository) List(ctx context.Context, status, kind string, limit int) ([]*entities.Job, error) {
+	ctx, end := observe(ctx, "jobs", "list")
+	defer end()
+
+	rows, err := r.db.QueryContext(ctx, "SELECT "+jobColumns+`
+		FROM jobs WHERE ($1 = '' OR status = $1) AND ($2 = '' OR kind = $2)
+		ORDER BY created_at DESC LIMIT $3`, status, kind, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*entities.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *jobRepository) Stats(ctx context.Context) (*entities.JobStats, error) {
+	ctx, end := observe(ctx, "jobs", "stats")
+	defer end()
+
+	var stats entities.JobStats
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FILTER (WHERE status = $1), COUNT(*) FILTER (WHERE status = $2),
+			COUNT(*) FILTER (WHERE status = $3), COUNT(*) FILTER (WHERE status = $4)
+		FROM jobs`,
+		entities.JobPending, entities.JobRunning, entities.JobSucceeded, entities.JobFailed).
+		Scan(&stats.Pending, &stats.Running, &stats.Succeeded, &stats.Failed)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func scanJob(row rowScanner) (*entities.Job, error) {
+	var job entities.Job
+	var payload []byte
+	var startedAt, finishedAt sql.NullTime
+	err := row.Scan(&job.ID, &job.Kind, &payload, &job.Status, &job.Attempts, &job.MaxAttempts, &job.RunAt,
+		&job.LastError, &job.CreatedAt, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	job.Payload = payload
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return &job, nil
+}
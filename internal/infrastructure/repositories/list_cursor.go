@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// ListCursor marks the last row of a page of a cursor-paginated listing, which is ordered by
+// creation time and then ID. Unlike an offset, it keeps its place when rows are added or removed
+// ahead of it, and the next page is read from an index instead of skipping the rows before it.
+type ListCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// Encode returns the cursor as the opaque string handed to clients.
+func (c ListCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ParseListCursor decodes a cursor returned by Encode. An empty string is the start of the
+// listing and returns nil.
+func ParseListCursor(s string) (*ListCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, domainerrors.Validation("invalid cursor").WithCode("invalid_cursor")
+	}
+	var cursor ListCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == uuid.Nil || cursor.CreatedAt.IsZero() {
+		return nil, domainerrors.Validation("invalid cursor").WithCode("invalid_cursor")
+	}
+	return &cursor, nil
+}
+
+// cursorCondition returns the condition selecting the rows after the cursor, with placeholders
+// numbered after args, and appends its arguments to args.
+func cursorCondition(after *ListCursor, idColumn string, args []interface{}) (string, []interface{}) {
+	condition := fmt.Sprintf("(created_at, %s) > ($%d, $%d)", idColumn, len(args)+1, len(args)+2)
+	return condition, append(args, after.CreatedAt, after.ID)
+}
+
+// extraColumnScanner scans columns selected after those its wrapped scanner expects into extra.
+type extraColumnScanner struct {
+	row   rowScanner
+	extra []interface{}
+}
+
+func withExtraColumns(row rowScanner, extra ...interface{}) rowScanner {
+	return extraColumnScanner{row: row, extra: extra}
+}
+
+func (s extraColumnScanner) Scan(dest ...interface{}) error {
+	return s.row.Scan(append(dest, s.extra...)...)
+}
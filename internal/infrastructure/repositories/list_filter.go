@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	domainerrors "backend/internal/domain/errors"
+)
+
+// Fields a user or role listing can be sorted by. They map to columns through userSortColumns and
+// roleSortColumns, so a sort never puts client input into the SQL.
+var (
+	UserSortFields = []string{"username", "email", "first_name", "last_name", "created_at", "updated_at"}
+	RoleSortFields = []string{"role_name", "created_at", "updated_at"}
+)
+
+var (
+	userSortColumns = map[string]string{
+		"username": "username", "email": "email", "first_name": "first_name", "last_name": "last_name",
+		"created_at": "created_at", "updated_at": "updated_at",
+	}
+	roleSortColumns = map[string]string{"role_name": "role_name", "created_at": "created_at", "updated_at": "updated_at"}
+)
+
+// ListSort orders a listing by one field; the zero value keeps the listing's default order.
+type ListSort struct {
+	Field      string
+	Descending bool
+}
+
+// ParseListSort parses "field", "field:asc" or "field:desc", where field is one of allowed. An
+// empty string returns the zero ListSort.
+func ParseListSort(raw string, allowed []string) (ListSort, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ListSort{}, nil
+	}
+	field, direction, _ := strings.Cut(strings.ToLower(raw), ":")
+	sort := ListSort{Field: field}
+	switch direction {
+	case "", "asc":
+	case "desc":
+		sort.Descending = true
+	default:
+		return ListSort{}, domainerrors.Validation("sort direction must be asc or desc").WithCode("invalid_sort")
+	}
+	for _, name := range allowed {
+		if name == field {
+			return sort, nil
+		}
+	}
+	return ListSort{}, domainerrors.Validation("sort field must be one of %s", strings.Join(allowed, ", ")).WithCode("invalid_sort")
+}
+
+// IsDefault reports whether the sort keeps the order of cursor pagination, oldest first.
+func (s ListSort) IsDefault() bool {
+	return s.Field == "" || (s.Field == "created_at" && !s.Descending)
+}
+
+// orderBy returns the ORDER BY clause for the sort, or for fallback when no field is set. The ID
+// breaks ties so pages don't overlap when the sorted column repeats.
+func (s ListSort) orderBy(columns map[string]string, fallback string) string {
+	column, ok := columns[s.Field]
+	if !ok {
+		return " ORDER BY " + fallback + ", id"
+	}
+	if s.Descending {
+		return " ORDER BY " + column + " DESC, id DESC"
+	}
+	return " ORDER BY " + column + ", id"
+}
+
+// createdRangeClause returns the conditions bounding created_at, and appends their arguments to
+// args.
+func createdRangeClause(after, before *time.Time, args []interface{}) (string, []interface{}) {
+	var clause string
+	if after != nil {
+		clause += " AND created_at >= $" + fmt.Sprintf("%d", len(args)+1)
+		args = append(args, *after)
+	}
+	if before != nil {
+		clause += " AND created_at < $" + fmt.Sprintf("%d", len(args)+1)
+		args = append(args, *before)
+	}
+	return clause, args
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type LoginCodeRepository interface {
+	Create(ctx context.Context, code *entities.LoginCode) error
+	GetLatestActive(ctx context.Context, domainID, userID uuid.UUID) (*entities.LoginCode, error)
+	GetByTokenHash(ctx context.Context, domainID uuid.UUID, tokenHash string) (*entities.LoginCode, error)
+	IncrementAttempts(ctx context.Context, domainID, id uuid.UUID) error
+	Consume(ctx context.Context, domainID, id uuid.UUID) error
+}
+
+type loginCodeRepository struct {
+	router *ShardRouter
+}
+
+func NewLoginCodeRepository(router *ShardRouter) LoginCodeRepository {
+	return &loginCodeRepository{router: router}
+}
+
+const loginCodeColumns = "id, domain_id, user_id, code_hash, token_hash, attempts, expires_at, consumed_at, created_at"
+
+func (r *loginCodeRepository) Create(ctx context.Context, code *entities.LoginCode) error {
+	ctx, end := observe(ctx, "login_codes", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, code.DomainID)
+	if err != nil {
+		return err
+	}
+
+	code.ID = uuid.New()
+	return db.QueryRowContext(ctx, `
+		INSERT INTO login_codes (id, domain_id, user_id, code_hash, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING created_at`,
+		code.ID, code.DomainID, code.UserID, code.CodeHash, code.TokenHash, code.ExpiresAt).Scan(&code.CreatedAt)
+}
+
+// GetLatestActive returns the newest unconsumed, unexpired code; issuing a new code supersedes older ones.
+func (r *loginCodeRepository) GetLatestActive(ctx context.Context, domainID, userID uuid.UUID) (*entities.LoginCode, error) {
+	ctx, end := observe(ctx, "login_codes", "get_latest_active")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanLoginCode(db.QueryRowContext(ctx, "SELECT "+loginCodeColumns+` FROM login_codes
+		WHERE user_id = $1 AND consumed_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC LIMIT 1`, userID))
+}
+
+func (r *loginCodeRepository) GetByTokenHash(ctx context.Context, domainID uuid.UUID, tokenHash string) (*entities.LoginCode, error) {
+	ctx, end := observe(ctx, "login_codes", "get_by_token_hash")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanLoginCode(db.QueryRowContext(ctx, "SELECT "+loginCodeColumns+" FROM login_codes WHERE token_hash = $1", tokenHash))
+}
+
+func (r *loginCodeRepository) IncrementAttempts(ctx context.Context, domainID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "login_codes", "increment_attempts")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, "UPDATE login_codes SET attempts = attempts + 1 WHERE id = $1", id)
+	return err
+}
+
+// Consume marks the code used; it fails with sql.ErrNoRows if another request consumed it first.
+func (r *loginCodeRepository) Consume(ctx context.Context, domainID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "login_codes", "consume")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	return execExpectingRow(ctx, db, "UPDATE login_codes SET consumed_at = NOW() WHERE id = $1 AND consumed_at IS NULL", id)
+}
+
+func scanLoginCode(row rowScanner) (*entities.LoginCode, error) {
+	var code entities.LoginCode
+	var consumedAt sql.NullTime
+	err := row.Scan(&code.ID, &code.DomainID, &code.UserID, &code.CodeHash, &code.TokenHash,
+		&code.Attempts, &code.ExpiresAt, &consumedAt, &code.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if consumedAt.Valid {
+		code.ConsumedAt = &consumedAt.Time
+	}
+	return &code, nil
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type LoginHistoryRepository interface {
+	Create(ctx context.Context, entry *entities.LoginHistoryEntry) error
+	ListByUser(ctx context.Context, domainID, userID uuid.UUID, page, limit int) (*LoginHistoryListResult, error)
+}
+
+type LoginHistoryListResult struct {
+	Logins     []*entities.LoginHistoryEntry `json:"logins"`
+	Total      int                           `json:"total"`
+	Page       int                           `json:"page"`
+	Limit      int                           `json:"limit"`
+	TotalPages int                           `json:"total_pages"`
+}
+
+type loginHistoryRepository struct {
+	router *ShardRouter
+}
+
+func NewLoginHistoryRepository(router *ShardRouter) LoginHistoryRepository {
+	return &loginHistoryRepository{router: router}
+}
+
+const loginHistoryColumns = "id, domain_id, user_id, success, method, reason, ip, user_agent, created_at"
+
+func (r *loginHistoryRepository) Create(ctx context.Context, entry *entities.LoginHistoryEntry) error {
+	ctx, end := observe(ctx, "login_history", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, entry.DomainID)
+	if err != nil {
+		return err
+	}
+
+	entry.ID = uuid.New()
+	return db.QueryRowContext(ctx, `
+		INSERT INTO login_history (id, domain_id, user_id, success, method, reason, ip, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING created_at`,
+		entry.ID, entry.DomainID, entry.UserID, entry.Success, entry.Method, entry.Reason, entry.IP, entry.UserAgent).
+		Scan(&entry.CreatedAt)
+}
+
+// ListByUser returns a page of the user's sign-in attempts, newest first.
+func (r *loginHistoryRepository) ListByUser(ctx context.Context, domainID, userID uuid.UUID, page, limit int) (*LoginHistoryListResult, error) {
+	ctx, end := observe(ctx, "login_history", "list_by_user")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM login_history WHERE user_id = $1", userID).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+loginHistoryColumns+`
+		FROM login_history WHERE user_id = $1
+		ORDER BY created_at DESC, id LIMIT $2 OFFSET $3`, userID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logins := []*entities.LoginHistoryEntry{}
+	for rows.Next() {
+		var entry entities.LoginHistoryEntry
+		err := rows.Scan(&entry.ID, &entry.DomainID, &entry.UserID, &entry.Success, &entry.Method, &entry.Reason,
+			&entry.IP, &entry.UserAgent, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		logins = append(logins, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &LoginHistoryListResult{
+		Logins:     logins,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
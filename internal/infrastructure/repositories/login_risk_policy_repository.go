@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type LoginRiskPolicyRepository interface {
+	GetByDomainID(ctx context.Context, domainID uuid.UUID) (*entities.LoginRiskPolicy, error)
+	Upsert(ctx context.Context, policy *entities.LoginRiskPolicy) error
+}
+
+type loginRiskPolicyRepository struct {
+	db *sql.DB
+}
+
+func NewLoginRiskPolicyRepository(db *sql.DB) LoginRiskPolicyRepository {
+	return &loginRiskPolicyRepository{db: db}
+}
+
+func (r *loginRiskPolicyRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) (*entities.LoginRiskPolicy, error) {
+	ctx, end := observe(ctx, "login_risk_policies", "get_by_domain_id")
+	defer end()
+
+	var policy entities.LoginRiskPolicy
+	var captcha, mfa, block, captchaFailures sql.NullInt64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT domain_id, captcha_threshold, mfa_threshold, block_threshold, captcha_after_failures, updated_at
+		FROM login_risk_policies WHERE domain_id = $1`, domainID).Scan(
+		&policy.DomainID, &captcha, &mfa, &block, &captchaFailures, &policy.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.CaptchaThreshold = nullableInt(captcha)
+	policy.MFAThreshold = nullableInt(mfa)
+	policy.BlockThreshold = nullableInt(block)
+	policy.CaptchaAfterFailures = nullableInt(captchaFailures)
+	return &policy, nil
+}
+
+func (r *loginRiskPolicyRepository) Upsert(ctx context.Context, policy *entities.LoginRiskPolicy) error {
+	ctx, end := observe(ctx, "login_risk_policies", "upsert")
+	defer end()
+
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO login_risk_policies (domain_id, captcha_threshold, mfa_threshold, block_threshold, captcha_after_failures)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (domain_id) DO UPDATE SET
+			captcha_threshold = EXCLUDED.captcha_threshold,
+			mfa_threshold = EXCLUDED.mfa_threshold,
+			block_threshold = EXCLUDED.block_threshold,
+			captcha_after_failures = EXCLUDED.captcha_after_failures,
+			updated_at = NOW()
+		RETURNING updated_at`,
+		policy.DomainID, policy.CaptchaThreshold, policy.MFAThreshold, policy.BlockThreshold, policy.CaptchaAfterFailures).Scan(&policy.UpdatedAt)
+}
+
+func nullableInt(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SplitStatements splits a migration file into its statements the way psql does, so each runs
+// on its own: at semicolons outside quotes, comments and dollar-quoted bodies. Statements holding
+// nothing but whitespace and comments are dropped.
+func SplitStatements(script string) []string {
+	var statements []string
+	start, hasCode := 0, false
+	for i := 0; i < len(script); i++ {
+		switch {
+		case strings.HasPrefix(script[i:], "--"):
+			i += skipUntil(script[i:], "\n") - 1
+		case strings.HasPrefix(script[i:], "/*"):
+			i += skipUntil(script[i+2:], "*/") + 1
+		case script[i] == '\'' || script[i] == '"':
+			i += skipUntil(script[i+1:], script[i:i+1])
+			hasCode = true
+		case script[i] == '$':
+			if end := strings.IndexByte(script[i+1:], '$'); end >= 0 && isDollarTag(script[i+1:i+1+end]) {
+				tag := script[i : i+end+2]
+				i += len(tag) + skipUntil(script[i+len(tag):], tag) - 1
+			}
+			hasCode = true
+		case script[i] == ';':
+			if hasCode {
+				statements = append(statements, strings.TrimSpace(script[start:i]))
+			}
+			start, hasCode = i+1, false
+		case !unicode.IsSpace(rune(script[i])):
+			hasCode = true
+		}
+	}
+	if hasCode {
+		statements = append(statements, strings.TrimSpace(script[start:]))
+	}
+	return statements
+}
+
+// skipUntil returns the offset in s just past the first occurrence of end, or len(s). The loop of
+// SplitStatements advances past the last skipped byte itself.
+func skipUntil(s, end string) int {
+	if i := strings.Index(s, end); i >= 0 {
+		return i + len(end)
+	}
+	return len(s)
+}
+
+func isDollarTag(tag string) bool {
+	for i, r := range tag {
+		if r != '_' && !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"statements", "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n", []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"}},
+		{"last statement without semicolon", "SELECT 1;\nSELECT 2", []string{"SELECT 1", "SELECT 2"}},
+		{"semicolon in a string", "INSERT INTO a VALUES ('x;y');", []string{"INSERT INTO a VALUES ('x;y')"}},
+		{"escaped quote in a string", "INSERT INTO a VALUES ('it''s; fine');", []string{"INSERT INTO a VALUES ('it''s; fine')"}},
+		{"semicolon in a quoted identifier", `SELECT 1 AS "a;b";`, []string{`SELECT 1 AS "a;b"`}},
+		{"semicolon in a line comment", "SELECT 1 -- one; two\n;", []string{"SELECT 1 -- one; two"}},
+		{"semicolon in a block comment", "SELECT /* one; two */ 1;", []string{"SELECT /* one; two */ 1"}},
+		{"comment only statements", "-- header;\n/* note; */\nSELECT 1;\n-- trailer\n", []string{"-- header;\n/* note; */\nSELECT 1"}},
+		{"blank statements", "SELECT 1;;\n  ;\n", []string{"SELECT 1"}},
+		{
+			"dollar quoted body",
+			"CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n  NEW.a := 1;\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;\nSELECT 1;",
+			[]string{"CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n  NEW.a := 1;\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql", "SELECT 1"},
+		},
+		{
+			"tagged dollar quote holding $$",
+			"DO $body$ BEGIN PERFORM '$$;'; END $body$;\nSELECT 2;",
+			[]string{"DO $body$ BEGIN PERFORM '$$;'; END $body$", "SELECT 2"},
+		},
+		{"positional parameter", "PREPARE p AS SELECT $1; EXECUTE p(1);", []string{"PREPARE p AS SELECT $1", "EXECUTE p(1)"}},
+		{"unterminated string", "SELECT 'a;b", []string{"SELECT 'a;b"}},
+		{"empty script", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitStatements(%q)\n got %q\nwant %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSplitMigrations checks that the repository's migrations split into statements that each
+// keep their dollar-quoted bodies whole.
+func TestSplitMigrations(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "..", "migrations", "*.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no migrations found")
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statements := SplitStatements(string(data))
+		if len(statements) == 0 {
+			t.Errorf("%s: no statements", filepath.Base(file))
+		}
+		for _, statement := range statements {
+			if strings.Count(statement, "$$")%2 != 0 {
+				t.Errorf("%s: statement splits a $$ body: %q", filepath.Base(file), statement)
+			}
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type OrgUnitRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.OrgUnit, error)
+	GetByName(ctx context.Context, domainID uuid.UUID, name string) (*entities.OrgUnit, error)
+	GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.OrgUnit, error)
+	ListSubtree(ctx context.Context, domainID, rootID uuid.UUID) ([]*entities.OrgUnit, error)
+	InSubtree(ctx context.Context, domainID, rootID, id uuid.UUID) (bool, error)
+	CountChildren(ctx context.Context, domainID, id uuid.UUID) (int, error)
+	Create(ctx context.Context, unit *entities.OrgUnit) error
+	Update(ctx context.Context, unit *entities.OrgUnit) error
+	Delete(ctx context.Context, domainID, id uuid.UUID) error
+}
+
+type orgUnitRepository struct {
+	router *ShardRouter
+}
+
+func NewOrgUnitRepository(router *ShardRouter) OrgUnitRepository {
+	return &orgUnitRepository{router: router}
+}
+
+const orgUnitColumns = "id, domain_id, parent_id, name, description, created_at, updated_at"
+
+// orgUnitSubtree is a query selecting the ID of the org unit whose ID is bound to placeholder and
+// the IDs of all its descendants.
+func orgUnitSubtree(placeholder string) string {
+	return `WITH RECURSIVE subtree AS (
+			SELECT id FROM org_units WHERE id = ` + placeholder + `
+			UNION ALL
+			SELECT ou.id FROM org_units ou JOIN subtree s ON ou.parent_id = s.id)
+		SELECT id FROM subtree`
+}
+
+func (r *orgUnitRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.OrgUnit, error) {
+	ctx, end := observe(ctx, "org_units", "get_by_id")
+	defer end()
+
+	var unit *entities.OrgUnit
+	err := r.router.QueryRowAcross(ctx, func(db *sql.DB) error {
+		var err error
+		unit, err = scanOrgUnit(db.QueryRowContext(ctx, "SELECT "+orgUnitColumns+" FROM org_units WHERE id = $1", id))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unit, nil
+}
+
+func (r *orgUnitRepository) GetByName(ctx context.Context, domainID uuid.UUID, name string) (*entities.OrgUnit, error) {
+	ctx, end := observe(ctx, "org_units", "get_by_name")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanOrgUnit(db.QueryRowContext(ctx, "SELECT "+orgUnitColumns+" FROM org_units WHERE domain_id = $1 AND name = $2", domainID, name))
+}
+
+func (r *orgUnitRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.OrgUnit, error) {
+	ctx, end := observe(ctx, "org_units", "get_by_domain_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+orgUnitColumns+" FROM org_units WHERE domain_id = $1 ORDER BY name", domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrgUnits(rows)
+}
+
+// ListSubtree returns the org unit and all its descendants, ordered by name.
+func (r *orgUnitRepository) ListSubtree(ctx context.Context, domainID, rootID uuid.UUID) ([]*entities.OrgUnit, error) {
+	ctx, end := observe(ctx, "org_units", "list_subtree")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+orgUnitColumns+` FROM org_units
+		WHERE domain_id = $1 AND id IN (`+orgUnitSubtree("$2")+`)
+		ORDER BY name`, domainID, rootID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrgUnits(rows)
+}
+
+// InSubtree reports whether the org unit id is rootID or one of its descendants.
+func (r *orgUnitRepository) InSubtree(ctx context.Context, domainID, rootID, id uuid.UUID) (bool, error) {
+	ctx, end := observe(ctx, "org_units", "in_subtree")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return false, err
+	}
+
+	var found bool
+	err = db.QueryRowContext(ctx, "SELECT $2 IN ("+orgUnitSubtree("$1")+")", rootID, id).Scan(&found)
+	return found, err
+}
+
+func (r *orgUnitRepository) CountChildren(ctx context.Context, domainID, id uuid.UUID) (int, error) {
+	ctx, end := observe(ctx, "org_units", "count_children")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM org_units WHERE parent_id = $1", id).Scan(&count)
+	return count, err
+}
+
+func (r *orgUnitRepository) Create(ctx context.Context, unit *entities.OrgUnit) error {
+	ctx, end := observe(ctx, "org_units", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, unit.DomainID)
+	if err != nil {
+		return err
+	}
+
+	unit.ID = uuid.New()
+	return db.QueryRowContext(ctx, `
+		INSERT INTO org_units (id, domain_id, parent_id, name, description)
+		VALUES ($1, $2, $3, $4, $5) RETURNING created_at, updated_at`,
+		unit.ID, unit.DomainID, unit.ParentID, unit.Name, unit.Description).Scan(&unit.CreatedAt, &unit.UpdatedAt)
+}
+
+func (r *orgUnitRepository) Update(ctx context.Context, unit *entities.OrgUnit) error {
+	ctx, end := observe(ctx, "org_units", "update")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, unit.DomainID)
+	if err != nil {
+		return err
+	}
+	return db.QueryRowContext(ctx, `
+		UPDATE org_units SET parent_id = $1, name = $2, description = $3, updated_at = NOW()
+		WHERE id = $4 RETURNING updated_at`, unit.ParentID, unit.Name, unit.Description, unit.ID).Scan(&unit.UpdatedAt)
+}
+
+func (r *orgUnitRepository) Delete(ctx context.Context, domainID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "org_units", "delete")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	return execExpectingRow(ctx, db, "DELETE FROM org_units WHERE id = $1", id)
+}
+
+func scanOrgUnit(row rowScanner) (*entities.OrgUnit, error) {
+	var unit entities.OrgUnit
+	var parentID uuid.NullUUID
+	err := row.Scan(&unit.ID, &unit.DomainID, &parentID, &unit.Name, &unit.Description, &unit.CreatedAt, &unit.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		unit.ParentID = &parentID.UUID
+	}
+	return &unit, nil
+}
+
+func scanOrgUnits(rows *sql.Rows) ([]*entities.OrgUnit, error) {
+	var units []*entities.OrgUnit
+	for rows.Next() {
+		unit, err := scanOrgUnit(rows)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, unit)
+	}
+	return units, rows.Err()
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type PasswordHistoryRepository interface {
+	Recent(ctx context.Context, domainID, userID uuid.UUID, limit int) ([]string, error)
+	Add(ctx context.Context, domainID, userID uuid.UUID, passwordHash string, keep int) error
+}
+
+type passwordHistoryRepository struct {
+	router *ShardRouter
+}
+
+func NewPasswordHistoryRepository(router *ShardRouter) PasswordHistoryRepository {
+	return &passwordHistoryRepository{router: router}
+}
+
+// Recent returns up to limit previous password hashes of the user, newest first.
+func (r *passwordHistoryRepository) Recent(ctx context.Context, domainID, userID uuid.UUID, limit int) ([]string, error) {
+	ctx, end := observe(ctx, "password_history", "recent")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT password_hash FROM password_history
+		WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
+
+// Add records a replaced password hash and drops all but the newest keep entries of the user.
+func (r *passwordHistoryRepository) Add(ctx context.Context, domainID, userID uuid.UUID, passwordHash string, keep int) error {
+	ctx, end := observe(ctx, "password_history", "add")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	return inTx(ctx, db, func(tx DBTX) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO password_history (id, domain_id, user_id, password_hash)
+			VALUES ($1, $2, $3, $4)`, uuid.New(), domainID, userID, passwordHash)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM password_history WHERE user_id = $1 AND id NOT IN (
+				SELECT id FROM password_history WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2)`, userID, keep)
+		return err
+	})
+}
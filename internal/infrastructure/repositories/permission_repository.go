@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type PermissionRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Permission, error)
+	GetByName(ctx context.Context, domainID uuid.UUID, name string) (*entities.Permission, error)
+	GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.Permission, error)
+	GetByRoleID(ctx context.Context, domainID, roleID uuid.UUID) ([]*entities.Permission, error)
+	Create(ctx context.Context, permission *entities.Permission) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	AssignToRole(ctx context.Context, domainID, roleID, permissionID uuid.UUID) error
+	RevokeFromRole(ctx context.Context, domainID, roleID, permissionID uuid.UUID) error
+}
+
+type permissionRepository struct {
+	router *ShardRouter
+}
+
+func NewPermissionRepository(router *ShardRouter) PermissionRepository {
+	return &permissionRepository{router: router}
+}
+
+func (r *permissionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Permission, error) {
+	ctx, end := observe(ctx, "permissions", "get_by_id")
+	defer end()
+
+	var permission entities.Permission
+	err := r.router.QueryRowAcross(ctx, func(db *sql.DB) error {
+		return db.QueryRowContext(ctx, `
+			SELECT id, domain_id, name, resource, action, description, created_at
+			FROM permissions WHERE id = $1`, id).Scan(
+			&permission.ID, &permission.DomainID, &permission.Name, &permission.Resource,
+			&permission.Action, &permission.Description, &permission.CreatedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func (r *permissionRepository) GetByName(ctx context.Context, domainID uuid.UUID, name string) (*entities.Permission, error) {
+	ctx, end := observe(ctx, "permissions", "get_by_name")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var permission entities.Permission
+	err = db.QueryRowContext(ctx, `
+		SELECT id, domain_id, name, resource, action, description, created_at
+		FROM permissions WHERE domain_id = $1 AND name = $2`, domainID, name).Scan(
+		&permission.ID, &permission.DomainID, &permission.Name, &permission.Resource,
+		&permission.Action, &permission.Description, &permission.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func (r *permissionRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.Permission, error) {
+	ctx, end := observe(ctx, "permissions", "get_by_domain_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, domain_id, name, resource, action, description, created_at
+		FROM permissions WHERE domain_id = $1 ORDER BY name`, domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPermissions(rows)
+}
+
+func (r *permissionRepository) GetByRoleID(ctx context.Context, domainID, roleID uuid.UUID) ([]*entities.Permission, error) {
+	ctx, end := observe(ctx, "role_permissions", "get_by_role_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT p.id, p.domain_id, p.name, p.resource, p.action, p.description, p.created_at
+		FROM permissions p JOIN role_permissions rp ON rp.permission_id = p.id
+		WHERE rp.role_id = $1 ORDER BY p.name`, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPermissions(rows)
+}
+
+func (r *permissionRepository) Create(ctx context.Context, permission *entities.Permission) error {
+	ctx, end := observe(ctx, "permissions", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, permission.DomainID)
+	if err != nil {
+		return err
+	}
+
+	permission.ID = uuid.New()
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO permissions (id, domain_id, name, resource, action, description)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING created_at`,
+		permission.ID, permission.DomainID, permission.Name, permission.Resource,
+		permission.Action, permission.Description).Scan(&permission.CreatedAt)
+	return err
+}
+
+func (r *permissionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "permissions", "delete")
+	defer end()
+
+	return r.router.ExecAcross(ctx, "DELETE FROM permissions WHERE id = $1", id)
+}
+
+func (r *permissionRepository) AssignToRole(ctx context.Context, domainID, roleID, permissionID uuid.UUID) error {
+	ctx, end := observe(ctx, "role_permissions", "assign")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	// Assigning twice is a no-op
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO role_permissions (role_id, permission_id) VALUES ($1, $2)
+		ON CONFLICT (role_id, permission_id) DO NOTHING`, roleID, permissionID)
+	return err
+}
+
+func (r *permissionRepository) RevokeFromRole(ctx context.Context, domainID, roleID, permissionID uuid.UUID) error {
+	ctx, end := observe(ctx, "role_permissions", "revoke")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.ExecContext(ctx, "DELETE FROM role_permissions WHERE role_id = $1 AND permission_id = $2", roleID, permissionID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanPermissions(rows *sql.Rows) ([]*entities.Permission, error) {
+	var permissions []*entities.Permission
+	for rows.Next() {
+		var permission entities.Permission
+		err := rows.Scan(&permission.ID, &permission.DomainID, &permission.Name, &permission.Resource,
+			&permission.Action, &permission.Description, &permission.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, &permission)
+	}
+	return permissions, nil
+}
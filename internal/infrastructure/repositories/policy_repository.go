@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type PolicyRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Policy, error)
+	GetByName(ctx context.Context, domainID uuid.UUID, name string) (*entities.Policy, error)
+	GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.Policy, error)
+	Create(ctx context.Context, policy *entities.Policy) error
+	Update(ctx context.Context, policy *entities.Policy) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type policyRepository struct {
+	router *ShardRouter
+}
+
+func NewPolicyRepository(router *ShardRouter) PolicyRepository {
+	return &policyRepository{router: router}
+}
+
+const policyColumns = "id, domain_id, name, description, document, created_at, updated_at"
+
+func (r *policyRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Policy, error) {
+	ctx, end := observe(ctx, "policies", "get_by_id")
+	defer end()
+
+	var policy *entities.Policy
+	err := r.router.QueryRowAcross(ctx, func(db *sql.DB) error {
+		var err error
+		policy, err = scanPolicy(db.QueryRowContext(ctx, "SELECT "+policyColumns+" FROM policies WHERE id = $1", id))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (r *policyRepository) GetByName(ctx context.Context, domainID uuid.UUID, name string) (*entities.Policy, error) {
+	ctx, end := observe(ctx, "policies", "get_by_name")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanPolicy(db.QueryRowContext(ctx, "SELECT "+policyColumns+" FROM policies WHERE domain_id = $1 AND name = $2", domainID, name))
+}
+
+func (r *policyRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.Policy, error) {
+	ctx, end := observe(ctx, "policies", "get_by_domain_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+policyColumns+" FROM policies WHERE domain_id = $1 ORDER BY name", domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*entities.Policy
+	for rows.Next() {
+		policy, err := scanPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func (r *policyRepository) Create(ctx context.Context, policy *entities.Policy) error {
+	ctx, end := observe(ctx, "policies", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, policy.DomainID)
+	if err != nil {
+		return err
+	}
+
+	policy.ID = uuid.New()
+	return db.QueryRowContext(ctx, `
+		INSERT INTO policies (id, domain_id, name, description, document)
+		VALUES ($1, $2, $3, $4, $5) RETURNING created_at, updated_at`,
+		policy.ID, policy.DomainID, policy.Name, policy.Description, []byte(policy.Document)).Scan(
+		&policy.CreatedAt, &policy.UpdatedAt)
+}
+
+func (r *policyRepository) Update(ctx context.Context, policy *entities.Policy) error {
+	ctx, end := observe(ctx, "policies", "update")
+	defer end()
+
+	return r.router.ExecAcross(ctx, `
+		UPDATE policies SET name = $1, description = $2, document = $3, updated_at = NOW()
+		WHERE id = $4`, policy.Name, policy.Description, []byte(policy.Document), policy.ID)
+}
+
+func (r *policyRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "policies", "delete")
+	defer end()
+
+	return r.router.ExecAcross(ctx, "DELETE FROM policies WHERE id = $1", id)
+}
+
+func scanPolicy(row rowScanner) (*entities.Policy, error) {
+	var policy entities.Policy
+	var document []byte
+	err := row.Scan(&policy.ID, &policy.DomainID, &policy.Name, &policy.Description, &document, &policy.CreatedAt, &policy.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	policy.Document = document
+	return &policy, nil
+}
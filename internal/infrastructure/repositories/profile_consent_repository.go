@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ProfileConsentRepository interface {
+	Get(ctx context.Context, domainID, userID, clientID uuid.UUID) (*entities.ProfileConsent, error)
+	ListByUser(ctx context.Context, domainID, userID uuid.UUID) ([]*entities.ProfileConsent, error)
+	Upsert(ctx context.Context, consent *entities.ProfileConsent) error
+	Delete(ctx context.Context, domainID, userID, clientID uuid.UUID) error
+}
+
+type profileConsentRepository struct {
+	router *ShardRouter
+}
+
+func NewProfileConsentRepository(router *ShardRouter) ProfileConsentRepository {
+	return &profileConsentRepository{router: router}
+}
+
+const profileConsentColumns = "domain_id, user_id, client_id, fields, created_at, updated_at"
+
+func (r *profileConsentRepository) Get(ctx context.Context, domainID, userID, clientID uuid.UUID) (*entities.ProfileConsent, error) {
+	ctx, end := observe(ctx, "profile_consents", "get")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanProfileConsent(db.QueryRowContext(ctx, "SELECT "+profileConsentColumns+`
+		FROM profile_consents WHERE user_id = $1 AND client_id = $2`, userID, clientID))
+}
+
+func (r *profileConsentRepository) ListByUser(ctx context.Context, domainID, userID uuid.UUID) ([]*entities.ProfileConsent, error) {
+	ctx, end := observe(ctx, "profile_consents", "list_by_user")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+profileConsentColumns+`
+		FROM profile_consents WHERE user_id = $1 ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var consents []*entities.ProfileConsent
+	for rows.Next() {
+		consent, err := scanProfileConsent(rows)
+		if err != nil {
+			return nil, err
+		}
+		consents = append(consents, consent)
+	}
+	return consents, rows.Err()
+}
+
+func (r *profileConsentRepository) Upsert(ctx context.Context, consent *entities.ProfileConsent) error {
+	ctx, end := observe(ctx, "profile_consents", "upsert")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, consent.DomainID)
+	if err != nil {
+		return err
+	}
+	return db.QueryRowContext(ctx, `
+		INSERT INTO profile_consents (domain_id, user_id, client_id, fields)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, client_id) DO UPDATE SET fields = EXCLUDED.fields, updated_at = NOW()
+		RETURNING created_at, updated_at`,
+		consent.DomainID, consent.UserID, consent.ClientID, pq.Array(consent.Fields)).Scan(&consent.CreatedAt, &consent.UpdatedAt)
+}
+
+func (r *profileConsentRepository) Delete(ctx context.Context, domainID, userID, clientID uuid.UUID) error {
+	ctx, end := observe(ctx, "profile_consents", "delete")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	result, err := db.ExecContext(ctx, "DELETE FROM profile_consents WHERE user_id = $1 AND client_id = $2", userID, clientID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanProfileConsent(row rowScanner) (*entities.ProfileConsent, error) {
+	var consent entities.ProfileConsent
+	err := row.Scan(&consent.DomainID, &consent.UserID, &consent.ClientID, pq.Array(&consent.Fields),
+		&consent.CreatedAt, &consent.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type RegistrationCodeRepository interface {
+	Create(ctx context.Context, code *entities.RegistrationCode) error
+	ListByDomain(ctx context.Context, domainID uuid.UUID) ([]*entities.RegistrationCode, error)
+	GetByHash(ctx context.Context, domainID uuid.UUID, codeHash string) (*entities.RegistrationCode, error)
+	Consume(ctx context.Context, domainID, id uuid.UUID) error
+	Release(ctx context.Context, domainID, id uuid.UUID) error
+	Revoke(ctx context.Context, domainID, id uuid.UUID) error
+}
+
+type registrationCodeRepository struct {
+	router *ShardRouter
+}
+
+func NewRegistrationCodeRepository(router *ShardRouter) RegistrationCodeRepository {
+	return &registrationCodeRepository{router: router}
+}
+
+const registrationCodeColumns = "id, domain_id, code_prefix, code_hash, role_id, max_uses, uses, expires_at, revoked_at, created_at"
+
+func (r *registrationCodeRepository) Create(ctx context.Context, code *entities.RegistrationCode) error {
+	ctx, end := observe(ctx, "registration_codes", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, code.DomainID)
+	if err != nil {
+		return err
+	}
+
+	code.ID = uuid.New()
+	return db.QueryRowContext(ctx, `
+		INSERT INTO registration_codes (id, domain_id, code_prefix, code_hash, role_id, max_uses, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING created_at`,
+		code.ID, code.DomainID, code.Prefix, code.CodeHash, code.RoleID, code.MaxUses, code.ExpiresAt).Scan(&code.CreatedAt)
+}
+
+func (r *registrationCodeRepository) ListByDomain(ctx context.Context, domainID uuid.UUID) ([]*entities.RegistrationCode, error) {
+	ctx, end := observe(ctx, "registration_codes", "list_by_domain")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, "SELECT "+registrationCodeColumns+" FROM registration_codes WHERE domain_id = $1 ORDER BY created_at DESC", domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes := []*entities.RegistrationCode{}
+	for rows.Next() {
+		code, err := scanRegistrationCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, rows.Err()
+}
+
+func (r *registrationCodeRepository) GetByHash(ctx context.Context, domainID uuid.UUID, codeHash string) (*entities.RegistrationCode, error) {
+	ctx, end := observe(ctx, "registration_codes", "get_by_hash")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanRegistrationCode(db.QueryRowContext(ctx, "SELECT "+registrationCodeColumns+" FROM registration_codes WHERE domain_id = $1 AND code_hash = $2", domainID, codeHash))
+}
+
+// Consume takes one use of the code; it fails with sql.ErrNoRows if the code was revoked, has
+// expired or ran out of uses, including to a concurrent sign-up.
+func (r *registrationCodeRepository) Consume(ctx context.Context, domainID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "registration_codes", "consume")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	return execExpectingRow(ctx, db, `UPDATE registration_codes SET uses = uses + 1
+		WHERE id = $1 AND domain_id = $2 AND revoked_at IS NULL
+		  AND (expires_at IS NULL OR expires_at > NOW())
+		  AND (max_uses IS NULL OR uses < max_uses)`, id, domainID)
+}
+
+// Release gives back a use taken by Consume when the sign-up it was taken for fails.
+func (r *registrationCodeRepository) Release(ctx context.Context, domainID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "registration_codes", "release")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, "UPDATE registration_codes SET uses = uses - 1 WHERE id = $1 AND domain_id = $2 AND uses > 0", id, domainID)
+	return err
+}
+
+func (r *registrationCodeRepository) Revoke(ctx context.Context, domainID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "registration_codes", "revoke")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	return execExpectingRow(ctx, db, "UPDATE registration_codes SET revoked_at = NOW() WHERE id = $1 AND domain_id = $2 AND revoked_at IS NULL", id, domainID)
+}
+
+func scanRegistrationCode(row rowScanner) (*entities.RegistrationCode, error) {
+	var code entities.RegistrationCode
+	var roleID uuid.NullUUID
+	var maxUses sql.NullInt64
+	var expiresAt, revokedAt sql.NullTime
+	err := row.Scan(&code.ID, &code.DomainID, &code.Prefix, &code.CodeHash, &roleID, &maxUses,
+		&code.Uses, &expiresAt, &revokedAt, &code.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if roleID.Valid {
+		code.RoleID = &roleID.UUID
+	}
+	code.MaxUses = nullableInt(maxUses)
+	if expiresAt.Valid {
+		code.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		code.RevokedAt = &revokedAt.Time
+	}
+	return &code, nil
+}
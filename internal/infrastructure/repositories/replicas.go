@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+)
+
+type consistencyTokenKey struct{}
+
+// WithConsistencyToken attaches a token from ConsistencyToken to ctx; reads made with it skip
+// replicas that have not replayed up to the token's write positions.
+func WithConsistencyToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, consistencyTokenKey{}, token)
+}
+
+func (r *ShardRouter) HasReplicas() bool {
+	return len(r.replicas) > 0
+}
+
+// ConsistencyToken reports the current WAL position of every database that has a replica, as
+// comma-separated residency:lsn pairs.
+func (r *ShardRouter) ConsistencyToken(ctx context.Context) (string, error) {
+	residencies := make([]string, 0, len(r.replicas))
+	for residency := range r.replicas {
+		residencies = append(residencies, residency)
+	}
+	sort.Strings(residencies)
+
+	positions := make([]string, 0, len(residencies))
+	for _, residency := range residencies {
+		var lsn string
+		if err := r.ForResidency(residency).QueryRowContext(ctx, "SELECT pg_current_wal_lsn()::text").Scan(&lsn); err != nil {
+			return "", err
+		}
+		positions = append(positions, residency+":"+lsn)
+	}
+	return strings.Join(positions, ","), nil
+}
+
+// ForRead returns the read replica of db for queries that tolerate replication lag, or db itself
+// when it has no replica, the replica is behind the consistency token in ctx or db is a
+// transaction, whose own writes the replica can't have seen.
+func (r *ShardRouter) ForRead(ctx context.Context, db DBTX) DBTX {
+	pool, ok := db.(*sql.DB)
+	if !ok {
+		return db
+	}
+	residency := r.residencyOf(pool)
+	replica, ok := r.replicas[residency]
+	if !ok {
+		return db
+	}
+
+	lsn := tokenPosition(ctx, residency)
+	if lsn == "" {
+		return replica
+	}
+	// A malformed token or a replica that isn't replaying falls back to db
+	var caughtUp bool
+	err := replica.QueryRowContext(ctx, "SELECT COALESCE(pg_last_wal_replay_lsn() >= $1::pg_lsn, false)", lsn).Scan(&caughtUp)
+	if err != nil || !caughtUp {
+		return db
+	}
+	return replica
+}
+
+func (r *ShardRouter) residencyOf(db *sql.DB) string {
+	for region, shard := range r.shards {
+		if shard == db {
+			return region
+		}
+	}
+	return DefaultResidency
+}
+
+// tokenPosition returns the WAL position the ctx token requires for the residency, if any.
+func tokenPosition(ctx context.Context, residency string) string {
+	token, _ := ctx.Value(consistencyTokenKey{}).(string)
+	for _, position := range strings.Split(token, ",") {
+		if region, lsn, ok := strings.Cut(strings.TrimSpace(position), ":"); ok && region == residency {
+			return lsn
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RevokedTokenRepository is the denylist of revoked access tokens, keyed by their jti claim.
+// Entries are only kept until the token expires, after which it is rejected anyway.
+type RevokedTokenRepository interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	// DeleteExpired removes entries of tokens expired before now, returning how many were removed
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type revokedTokenRepository struct {
+	db *sql.DB
+}
+
+// NewRevokedTokenRepository keeps the denylist in the revoked_tokens table of the primary database.
+func NewRevokedTokenRepository(db *sql.DB) RevokedTokenRepository {
+	return &revokedTokenRepository{db: db}
+}
+
+func (r *revokedTokenRepository) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ctx, end := observe(ctx, "revoked_tokens", "revoke")
+	defer end()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2)
+		ON CONFLICT (jti) DO NOTHING`, jti, expiresAt)
+	return err
+}
+
+func (r *revokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	ctx, end := observe(ctx, "revoked_tokens", "is_revoked")
+	defer end()
+
+	var revoked bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)", jti).Scan(&revoked)
+	return revoked, err
+}
+
+func (r *revokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	ctx, end := observe(ctx, "revoked_tokens", "delete_expired")
+	defer end()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM revoked_tokens WHERE expires_at < $1", now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+type redisRevokedTokenRepository struct {
+	client *redis.Client
+}
+
+// NewRedisRevokedTokenRepository keeps the denylist in Redis, each entry expiring with its token.
+func NewRedisRevokedTokenRepository(client *redis.Client) RevokedTokenRepository {
+	return &redisRevokedTokenRepository{client: client}
+}
+
+func revokedTokenKey(jti string) string {
+	return "revoked_token:" + jti
+}
+
+func (r *redisRevokedTokenRepository) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return r.client.Set(ctx, revokedTokenKey(jti), 1, ttl).Err()
+}
+
+func (r *redisRevokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	err := r.client.Get(ctx, revokedTokenKey(jti)).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DeleteExpired is a no-op; Redis expires entries itself.
+func (r *redisRevokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
@@ -1,22 +1,50 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"backend/internal/domain/entities"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type RoleRepository interface {
-	GetByID(id uuid.UUID) (*entities.Role, error)
-	GetByDomainID(domainID uuid.UUID) ([]*entities.Role, error)
-	Create(role *entities.Role) error
-	Update(role *entities.Role) error
-	Delete(id uuid.UUID) error
-	ListWithPagination(search string, domainID uuid.UUID, page, limit int) (*RoleListResult, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Role, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Role, error)
+	GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.Role, error)
+	Create(ctx context.Context, role *entities.Role) error
+	Update(ctx context.Context, role *entities.Role) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteReassigning(ctx context.Context, domainID, id, replacementID uuid.UUID) error
+	CountDependents(ctx context.Context, domainID, id uuid.UUID) (*RoleDependents, error)
+	ListWithPagination(ctx context.Context, filter RoleListFilter, domainID uuid.UUID, page, limit int) (*RoleListResult, error)
+	ListAfter(ctx context.Context, filter RoleListFilter, domainID uuid.UUID, after *ListCursor, limit int) (*RoleCursorPage, error)
+	StreamByDomainID(ctx context.Context, domainID uuid.UUID, fn func(*entities.Role) error) error
+}
+
+// RoleDependents counts the rows that reference a role. Users only counts users holding the role
+// directly; members of the groups granting it keep their accounts either way.
+type RoleDependents struct {
+	Users             int `json:"users" example:"12"`
+	Groups            int `json:"groups" example:"2"`
+	Permissions       int `json:"permissions" example:"5"`
+	Invitations       int `json:"invitations" example:"1"`
+	RegistrationCodes int `json:"registration_codes" example:"0"`
+}
+
+// RoleListFilter narrows a role listing; zero values are ignored. Claim matches a top-level claim
+// key or an entry in the role's "permissions" array.
+type RoleListFilter struct {
+	Search        string
+	Claim         string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          ListSort
 }
 
 type RoleListResult struct {
@@ -27,22 +55,34 @@ type RoleListResult struct {
 	TotalPages int              `json:"total_pages"`
 }
 
+// RoleCursorPage is a page of a cursor-paginated role listing. NextCursor is empty on the last page.
+type RoleCursorPage struct {
+	Roles      []*entities.Role `json:"roles"`
+	Limit      int              `json:"limit"`
+	NextCursor string           `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNi0xMC0xNlQwODowMDowMFoiLCJpZCI6IjNmYTg1ZjY0LTU3MTctNDU2Mi1iM2ZjLTJjOTYzZjY2YWZhNiJ9"`
+}
+
 type roleRepository struct {
-	db *sql.DB
+	router *ShardRouter
 }
 
-func NewRoleRepository(db *sql.DB) RoleRepository {
-	return &roleRepository{db: db}
+func NewRoleRepository(router *ShardRouter) RoleRepository {
+	return &roleRepository{router: router}
 }
 
-func (r *roleRepository) GetByID(id uuid.UUID) (*entities.Role, error) {
+func (r *roleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Role, error) {
+	ctx, end := observe(ctx, "roles", "get_by_id")
+	defer end()
+
 	var role entities.Role
 	var claimsJSON []byte
 
-	err := r.db.QueryRow(`
-		SELECT id, domain_id, role_name, role_claims, created_at, updated_at
-		FROM roles WHERE id = $1`, id).Scan(
-		&role.ID, &role.DomainID, &role.RoleName, &claimsJSON, &role.CreatedAt, &role.UpdatedAt)
+	err := r.router.QueryRowAcross(ctx, func(db *sql.DB) error {
+		return db.QueryRowContext(ctx, `
+			SELECT id, domain_id, role_name, role_claims, created_at, updated_at
+			FROM roles WHERE id = $1`, id).Scan(
+			&role.ID, &role.DomainID, &role.RoleName, &claimsJSON, &role.CreatedAt, &role.UpdatedAt)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -55,8 +95,59 @@ func (r *roleRepository) GetByID(id uuid.UUID) (*entities.Role, error) {
 	return &role, nil
 }
 
-func (r *roleRepository) GetByDomainID(domainID uuid.UUID) ([]*entities.Role, error) {
-	rows, err := r.db.Query(`
+// GetByIDs returns the roles with the given IDs in no particular order, asking each database only
+// for the IDs not found yet. IDs without a role are left out.
+func (r *roleRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Role, error) {
+	ctx, end := observe(ctx, "roles", "get_by_ids")
+	defer end()
+
+	var roles []*entities.Role
+	remaining := ids
+	for _, db := range r.router.All() {
+		if len(remaining) == 0 {
+			break
+		}
+		rows, err := db.QueryContext(ctx, `
+			SELECT id, domain_id, role_name, role_claims, created_at, updated_at
+			FROM roles WHERE `+r.router.Dialect().AnyOf("id", r.router.Dialect().Cast("$1", "uuid[]")), pq.Array(uuidStrings(remaining)))
+		if err != nil {
+			return nil, err
+		}
+		found := make(map[uuid.UUID]bool)
+		for rows.Next() {
+			var role entities.Role
+			var claimsJSON []byte
+			if err := rows.Scan(&role.ID, &role.DomainID, &role.RoleName, &claimsJSON, &role.CreatedAt, &role.UpdatedAt); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if err := json.Unmarshal(claimsJSON, &role.RoleClaims); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			roles = append(roles, &role)
+			found[role.ID] = true
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		remaining = withoutIDs(remaining, found)
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.Role, error) {
+	ctx, end := observe(ctx, "roles", "get_by_domain_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
 		SELECT id, domain_id, role_name, role_claims, created_at, updated_at
 		FROM roles WHERE domain_id = $1 ORDER BY role_name`, domainID)
 	if err != nil {
@@ -66,25 +157,71 @@ func (r *roleRepository) GetByDomainID(domainID uuid.UUID) ([]*entities.Role, er
 
 	var roles []*entities.Role
 	for rows.Next() {
-		var role entities.Role
-		var claimsJSON []byte
-
-		err := rows.Scan(&role.ID, &role.DomainID, &role.RoleName, &claimsJSON, &role.CreatedAt, &role.UpdatedAt)
+		role, err := scanRole(rows)
 		if err != nil {
 			return nil, err
 		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
 
-		// Parse JSONB claims
-		if err := json.Unmarshal(claimsJSON, &role.RoleClaims); err != nil {
-			return nil, err
+// StreamByDomainID calls fn for every role of the domain while iterating the result set.
+// An error from fn stops the iteration.
+func (r *roleRepository) StreamByDomainID(ctx context.Context, domainID uuid.UUID, fn func(*entities.Role) error) error {
+	ctx, end := observe(ctx, "roles", "stream_by_domain_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, domain_id, role_name, role_claims, created_at, updated_at
+		FROM roles WHERE domain_id = $1 ORDER BY role_name`, domainID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(role); err != nil {
+			return err
 		}
+	}
+	return rows.Err()
+}
 
-		roles = append(roles, &role)
+func scanRole(row rowScanner) (*entities.Role, error) {
+	var role entities.Role
+	var claimsJSON []byte
+
+	err := row.Scan(&role.ID, &role.DomainID, &role.RoleName, &claimsJSON, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		return nil, err
 	}
-	return roles, nil
+
+	// Parse JSONB claims
+	if err := json.Unmarshal(claimsJSON, &role.RoleClaims); err != nil {
+		return nil, err
+	}
+	return &role, nil
 }
 
-func (r *roleRepository) Create(role *entities.Role) error {
+func (r *roleRepository) Create(ctx context.Context, role *entities.Role) error {
+	ctx, end := observe(ctx, "roles", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, role.DomainID)
+	if err != nil {
+		return err
+	}
+
 	role.ID = uuid.New()
 
 	// Convert claims to JSON
@@ -93,58 +230,126 @@ func (r *roleRepository) Create(role *entities.Role) error {
 		return err
 	}
 
-	err = r.db.QueryRow(`
+	err = db.QueryRowContext(ctx, `
 		INSERT INTO roles (id, domain_id, role_name, role_claims)
 		VALUES ($1, $2, $3, $4) RETURNING id`,
 		role.ID, role.DomainID, role.RoleName, claimsJSON).Scan(&role.ID)
 	return err
 }
 
-func (r *roleRepository) Update(role *entities.Role) error {
+func (r *roleRepository) Update(ctx context.Context, role *entities.Role) error {
+	ctx, end := observe(ctx, "roles", "update")
+	defer end()
+
 	// Convert claims to JSON
 	claimsJSON, err := json.Marshal(role.RoleClaims)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.Exec(`
-		UPDATE roles SET role_name = $1, role_claims = $2, updated_at = CURRENT_TIMESTAMP
+	// Through the tenant, so the update joins a transaction recording its revision
+	ctx, db, err := r.router.ForTenant(ctx, role.DomainID)
+	if err != nil {
+		return err
+	}
+	return execExpectingRow(ctx, db, `
+		UPDATE roles SET role_name = $1, role_claims = $2, updated_at = NOW()
 		WHERE id = $3`, role.RoleName, claimsJSON, role.ID)
-	return err
 }
 
-func (r *roleRepository) Delete(id uuid.UUID) error {
-	_, err := r.db.Exec("DELETE FROM roles WHERE id = $1", id)
-	return err
+func (r *roleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "roles", "delete")
+	defer end()
+
+	return r.router.ExecAcross(ctx, "DELETE FROM roles WHERE id = $1", id)
+}
+
+// DeleteReassigning moves the users, group grants, invitations and registration codes of a role
+// to its replacement and deletes the role, in one transaction. Permission assignments are not
+// moved; they are deleted with the role.
+func (r *roleRepository) DeleteReassigning(ctx context.Context, domainID, id, replacementID uuid.UUID) error {
+	ctx, end := observe(ctx, "roles", "delete_reassigning")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	return inTx(ctx, db, func(tx DBTX) error {
+		for _, query := range []string{
+			"UPDATE users SET role_id = $3, updated_at = NOW() WHERE domain_id = $1 AND role_id = $2",
+			"UPDATE invitations SET role_id = $3 WHERE domain_id = $1 AND role_id = $2",
+			"UPDATE registration_codes SET role_id = $3 WHERE domain_id = $1 AND role_id = $2",
+			`INSERT INTO group_roles (group_id, role_id)
+				SELECT gr.group_id, $3 FROM group_roles gr JOIN groups g ON g.id = gr.group_id
+				WHERE g.domain_id = $1 AND gr.role_id = $2
+				ON CONFLICT DO NOTHING`,
+			"DELETE FROM roles WHERE domain_id = $1 AND id = $2",
+		} {
+			if _, err := tx.ExecContext(ctx, query, domainID, id, replacementID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-func (r *roleRepository) ListWithPagination(search string, domainID uuid.UUID, page, limit int) (*RoleListResult, error) {
+func (r *roleRepository) CountDependents(ctx context.Context, domainID, id uuid.UUID) (*RoleDependents, error) {
+	ctx, end := observe(ctx, "roles", "count_dependents")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependents RoleDependents
+	err = db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE domain_id = $1 AND role_id = $2),
+			(SELECT COUNT(*) FROM group_roles WHERE role_id = $2),
+			(SELECT COUNT(*) FROM role_permissions WHERE role_id = $2),
+			(SELECT COUNT(*) FROM invitations WHERE domain_id = $1 AND role_id = $2),
+			(SELECT COUNT(*) FROM registration_codes WHERE domain_id = $1 AND role_id = $2)`,
+		domainID, id).Scan(&dependents.Users, &dependents.Groups, &dependents.Permissions,
+		&dependents.Invitations, &dependents.RegistrationCodes)
+	if err != nil {
+		return nil, err
+	}
+	return &dependents, nil
+}
+
+func (r *roleRepository) ListWithPagination(ctx context.Context, filter RoleListFilter, domainID uuid.UUID, page, limit int) (*RoleListResult, error) {
+	ctx, end := observe(ctx, "roles", "list_with_pagination")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	// Listings tolerate replica lag up to the client's consistency token
+	db = r.router.ForRead(ctx, db)
+
 	// Calculate offset
 	offset := (page - 1) * limit
 
-	// Build the query with search condition
+	// Build the query with filter conditions
 	baseQuery := "SELECT id, domain_id, role_name, role_claims, created_at, updated_at FROM roles WHERE domain_id = $1"
 	countQuery := "SELECT COUNT(*) FROM roles WHERE domain_id = $1"
-	args := []interface{}{domainID}
-	var whereClause string
-
-	if search != "" {
-		whereClause = " AND role_name ILIKE $" + fmt.Sprintf("%d", len(args)+1)
-		args = append(args, "%"+search+"%")
-	}
+	whereClause, args := roleFilterClause(r.router.Dialect(), filter, []interface{}{domainID})
 
 	// Get total count
 	var total int
-	err := r.db.QueryRow(countQuery+whereClause, args...).Scan(&total)
+	err = db.QueryRowContext(ctx, countQuery+whereClause, args...).Scan(&total)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get paginated results
-	query := baseQuery + whereClause + " ORDER BY role_name LIMIT $" + fmt.Sprintf("%d", len(args)+1) + " OFFSET $" + fmt.Sprintf("%d", len(args)+2)
+	query := baseQuery + whereClause + filter.Sort.orderBy(roleSortColumns, "role_name") + " LIMIT $" + fmt.Sprintf("%d", len(args)+1) + " OFFSET $" + fmt.Sprintf("%d", len(args)+2)
 	args = append(args, limit, offset)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -179,3 +384,77 @@ func (r *roleRepository) ListWithPagination(search string, domainID uuid.UUID, p
 		TotalPages: totalPages,
 	}, nil
 }
+
+// ListAfter returns up to limit roles of the domain created after the cursor (from the first when
+// after is nil), oldest first.
+func (r *roleRepository) ListAfter(ctx context.Context, filter RoleListFilter, domainID uuid.UUID, after *ListCursor, limit int) (*RoleCursorPage, error) {
+	ctx, end := observe(ctx, "roles", "list_after")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	// Listings tolerate replica lag up to the client's consistency token
+	db = r.router.ForRead(ctx, db)
+
+	whereClause, args := roleFilterClause(r.router.Dialect(), filter, []interface{}{domainID})
+	if after != nil {
+		var condition string
+		condition, args = cursorCondition(after, "id", args)
+		whereClause += " AND " + condition
+	}
+
+	// Fetch one extra role to learn whether another page follows
+	query := "SELECT id, domain_id, role_name, role_claims, created_at, updated_at FROM roles WHERE domain_id = $1" + whereClause +
+		" ORDER BY created_at, id LIMIT $" + fmt.Sprintf("%d", len(args)+1)
+	rows, err := db.QueryContext(ctx, query, append(args, limit+1)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []*entities.Role
+	for rows.Next() {
+		var role entities.Role
+		var claimsJSON []byte
+		if err := rows.Scan(&role.ID, &role.DomainID, &role.RoleName, &claimsJSON, &role.CreatedAt, &role.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(claimsJSON, &role.RoleClaims); err != nil {
+			return nil, err
+		}
+		roles = append(roles, &role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &RoleCursorPage{Roles: roles, Limit: limit}
+	if len(roles) > limit {
+		page.Roles = roles[:limit]
+		last := page.Roles[limit-1]
+		page.NextCursor = ListCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return page, nil
+}
+
+// roleFilterClause returns the conditions of the filter, and appends their arguments to args.
+func roleFilterClause(d Dialect, filter RoleListFilter, args []interface{}) (string, []interface{}) {
+	var clause string
+	if filter.Search != "" {
+		clause = " AND " + d.ILike("role_name", "$"+fmt.Sprintf("%d", len(args)+1))
+		args = append(args, "%"+filter.Search+"%")
+	}
+	if filter.Claim != "" {
+		// Match a top-level claim key or an entry in the "permissions" array; both use the GIN index.
+		// Strings always marshal.
+		permissions, _ := json.Marshal(map[string][]string{"permissions": {filter.Claim}})
+		clause += " AND (" + d.JSONHasKey("role_claims", d.Cast("$"+fmt.Sprintf("%d", len(args)+1), "text")) + " OR " +
+			d.JSONContains("role_claims", d.Cast("$"+fmt.Sprintf("%d", len(args)+2), "jsonb")) + ")"
+		args = append(args, filter.Claim, string(permissions))
+	}
+	var rangeClause string
+	rangeClause, args = createdRangeClause(filter.CreatedAfter, filter.CreatedBefore, args)
+	return clause + rangeClause, args
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type RoleRevisionRepository interface {
+	Create(ctx context.Context, revision *entities.RoleRevision) error
+	GetByRevision(ctx context.Context, domainID, roleID uuid.UUID, revision int) (*entities.RoleRevision, error)
+	ListByRole(ctx context.Context, domainID, roleID uuid.UUID, page, limit int) (*RoleRevisionListResult, error)
+}
+
+type RoleRevisionListResult struct {
+	Revisions  []*entities.RoleRevision `json:"revisions"`
+	Total      int                      `json:"total"`
+	Page       int                      `json:"page"`
+	Limit      int                      `json:"limit"`
+	TotalPages int                      `json:"total_pages"`
+}
+
+const roleRevisionColumns = `id, domain_id, role_id, revision, actor_id, old_role_name, new_role_name,
+	old_claims, new_claims, diff, rollback_of, created_at`
+
+type roleRevisionRepository struct {
+	router *ShardRouter
+}
+
+func NewRoleRevisionRepository(router *ShardRouter) RoleRevisionRepository {
+	return &roleRevisionRepository{router: router}
+}
+
+// Create records the revision as the role's next one, setting its ID, number and creation time.
+// Run it in the transaction of the role update, which locks the role row, so concurrent updates
+// can't take the same number.
+func (r *roleRevisionRepository) Create(ctx context.Context, revision *entities.RoleRevision) error {
+	ctx, end := observe(ctx, "role_revisions", "create")
+	defer end()
+
+	oldClaims, err := json.Marshal(revision.OldClaims)
+	if err != nil {
+		return err
+	}
+	newClaims, err := json.Marshal(revision.NewClaims)
+	if err != nil {
+		return err
+	}
+	diff, err := json.Marshal(revision.Diff)
+	if err != nil {
+		return err
+	}
+
+	ctx, db, err := r.router.ForTenant(ctx, revision.DomainID)
+	if err != nil {
+		return err
+	}
+
+	revision.ID = uuid.New()
+	return db.QueryRowContext(ctx, `
+		INSERT INTO role_revisions (id, domain_id, role_id, revision, actor_id, old_role_name, new_role_name,
+			old_claims, new_claims, diff, rollback_of)
+		SELECT $1, $2, $3, COALESCE(MAX(revision), 0) + 1, $4, $5, $6, $7, $8, $9, $10
+		FROM role_revisions WHERE role_id = $3
+		RETURNING revision, created_at`,
+		revision.ID, revision.DomainID, revision.RoleID, revision.ActorID, revision.OldRoleName, revision.NewRoleName,
+		oldClaims, newClaims, diff, revision.RollbackOf,
+	).Scan(&revision.Revision, &revision.CreatedAt)
+}
+
+func (r *roleRevisionRepository) GetByRevision(ctx context.Context, domainID, roleID uuid.UUID, revision int) (*entities.RoleRevision, error) {
+	ctx, end := observe(ctx, "role_revisions", "get_by_revision")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRoleRevision(db.QueryRowContext(ctx, `
+		SELECT `+roleRevisionColumns+` FROM role_revisions
+		WHERE role_id = $1 AND revision = $2`, roleID, revision))
+}
+
+// ListByRole returns a page of the role's revisions, newest first.
+func (r *roleRevisionRepository) ListByRole(ctx context.Context, domainID, roleID uuid.UUID, page, limit int) (*RoleRevisionListResult, error) {
+	ctx, end := observe(ctx, "role_revisions", "list_by_role")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	// Listings tolerate replica lag up to the client's consistency token
+	db = r.router.ForRead(ctx, db)
+
+	var total int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM role_revisions WHERE role_id = $1", roleID).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+roleRevisionColumns+` FROM role_revisions
+		WHERE role_id = $1 ORDER BY revision DESC LIMIT $2 OFFSET $3`, roleID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []*entities.RoleRevision{}
+	for rows.Next() {
+		revision, err := scanRoleRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &RoleRevisionListResult{
+		Revisions:  revisions,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+func scanRoleRevision(row rowScanner) (*entities.RoleRevision, error) {
+	var revision entities.RoleRevision
+	var actorID uuid.NullUUID
+	var oldClaims, newClaims, diff []byte
+
+	err := row.Scan(&revision.ID, &revision.DomainID, &revision.RoleID, &revision.Revision, &actorID,
+		&revision.OldRoleName, &revision.NewRoleName, &oldClaims, &newClaims, &diff, &revision.RollbackOf, &revision.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if actorID.Valid {
+		revision.ActorID = &actorID.UUID
+	}
+	if err := json.Unmarshal(oldClaims, &revision.OldClaims); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(newClaims, &revision.NewClaims); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(diff, &revision.Diff); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
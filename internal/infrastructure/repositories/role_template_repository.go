@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type RoleTemplateRepository interface {
+	List(ctx context.Context) ([]*entities.RoleTemplate, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.RoleTemplate, error)
+	Create(ctx context.Context, template *entities.RoleTemplate) error
+	Update(ctx context.Context, template *entities.RoleTemplate) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+const roleTemplateColumns = "id, name, description, role_claims, permissions, created_at, updated_at"
+
+// roleTemplateRepository keeps the catalog on the primary, next to the domains, since templates
+// belong to no domain.
+type roleTemplateRepository struct {
+	db *sql.DB
+}
+
+func NewRoleTemplateRepository(db *sql.DB) RoleTemplateRepository {
+	return &roleTemplateRepository{db: db}
+}
+
+// List returns the templates ordered by name.
+func (r *roleTemplateRepository) List(ctx context.Context) ([]*entities.RoleTemplate, error) {
+	ctx, end := observe(ctx, "role_templates", "list")
+	defer end()
+
+	rows, err := r.db.QueryContext(ctx, "SELECT "+roleTemplateColumns+" FROM role_templates ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []*entities.RoleTemplate{}
+	for rows.Next() {
+		template, err := scanRoleTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+func (r *roleTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.RoleTemplate, error) {
+	ctx, end := observe(ctx, "role_templates", "get_by_id")
+	defer end()
+
+	return scanRoleTemplate(r.db.QueryRowContext(ctx, "SELECT "+roleTemplateColumns+" FROM role_templates WHERE id = $1", id))
+}
+
+func (r *roleTemplateRepository) Create(ctx context.Context, template *entities.RoleTemplate) error {
+	ctx, end := observe(ctx, "role_templates", "create")
+	defer end()
+
+	claimsJSON, err := json.Marshal(template.RoleClaims)
+	if err != nil {
+		return err
+	}
+
+	template.ID = uuid.New()
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO role_templates (id, name, description, role_claims, permissions)
+		VALUES ($1, $2, $3, $4, $5) RETURNING created_at, updated_at`,
+		template.ID, template.Name, template.Description, claimsJSON, pq.Array(template.Permissions)).Scan(&template.CreatedAt, &template.UpdatedAt)
+}
+
+func (r *roleTemplateRepository) Update(ctx context.Context, template *entities.RoleTemplate) error {
+	ctx, end := observe(ctx, "role_templates", "update")
+	defer end()
+
+	claimsJSON, err := json.Marshal(template.RoleClaims)
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRowContext(ctx, `
+		UPDATE role_templates SET name = $1, description = $2, role_claims = $3, permissions = $4,
+			updated_at = NOW()
+		WHERE id = $5 RETURNING created_at, updated_at`,
+		template.Name, template.Description, claimsJSON, pq.Array(template.Permissions), template.ID).Scan(&template.CreatedAt, &template.UpdatedAt)
+}
+
+func (r *roleTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "role_templates", "delete")
+	defer end()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM role_templates WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanRoleTemplate(row rowScanner) (*entities.RoleTemplate, error) {
+	var template entities.RoleTemplate
+	var claimsJSON []byte
+	err := row.Scan(&template.ID, &template.Name, &template.Description, &claimsJSON,
+		pq.Array(&template.Permissions), &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(claimsJSON, &template.RoleClaims); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+)
+
+// SchemaRepository reads and writes the migration bookkeeping kept by migrations/run_migrations.sh
+// and iamctl migrate.
+type SchemaRepository interface {
+	// AppliedMigrations returns the newest applied migration of each database by residency; it is
+	// empty for databases migrated without the bookkeeping table.
+	AppliedMigrations(ctx context.Context) (map[string]string, error)
+	// RecordedMigrations returns every migration recorded in the database of the residency,
+	// creating the bookkeeping table if it is missing.
+	RecordedMigrations(ctx context.Context, residency string) (map[string]bool, error)
+	// ApplyMigration runs the statements of a migration, as split by SplitStatements, one by one
+	// outside a transaction as psql does so CREATE INDEX CONCURRENTLY works, and records it once
+	// they all succeeded.
+	ApplyMigration(ctx context.Context, residency, version string, statements []string) error
+}
+
+type schemaRepository struct {
+	router *ShardRouter
+}
+
+func NewSchemaRepository(router *ShardRouter) SchemaRepository {
+	return &schemaRepository{router: router}
+}
+
+func (r *schemaRepository) AppliedMigrations(ctx context.Context) (map[string]string, error) {
+	ctx, end := observe(ctx, "schema_migrations", "latest")
+	defer end()
+
+	applied := make(map[string]string)
+	for _, residency := range r.router.Residencies() {
+		var version sql.NullString
+		err := r.router.ForResidency(residency).QueryRowContext(ctx, `
+			SELECT CASE WHEN NOT `+r.router.Dialect().TableExists("'schema_migrations'")+` THEN NULL
+			            ELSE (SELECT MAX(version) FROM schema_migrations) END`).Scan(&version)
+		if err != nil {
+			return nil, err
+		}
+		applied[residency] = version.String
+	}
+	return applied, nil
+}
+
+func (r *schemaRepository) RecordedMigrations(ctx context.Context, residency string) (map[string]bool, error) {
+	ctx, end := observe(ctx, "schema_migrations", "list")
+	defer end()
+
+	db := r.router.ForResidency(residency)
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMP WITH TIME ZONE DEFAULT (NOW())
+		)`); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recorded := make(map[string]bool)
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		recorded[version] = true
+	}
+	return recorded, rows.Err()
+}
+
+func (r *schemaRepository) ApplyMigration(ctx context.Context, residency, version string, statements []string) error {
+	ctx, end := observe(ctx, "schema_migrations", "apply")
+	defer end()
+
+	db := r.router.ForResidency(residency)
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	_, err := db.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1) ON CONFLICT DO NOTHING`, version)
+	return err
+}
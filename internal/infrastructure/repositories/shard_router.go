@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"sync"
+
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/google/uuid"
+)
+
+// DefaultResidency marks tenants whose data lives in the primary database.
+const DefaultResidency = "default"
+
+// ShardRouter picks the database that holds a tenant's users and roles based on the
+// domain's residency. The domains table is authoritative on the primary database and
+// mirrored into each shard so tenant tables keep their foreign keys.
+type ShardRouter struct {
+	primary  *sql.DB
+	shards   map[string]*sql.DB
+	replicas map[string]*sql.DB // read replicas by residency
+	dialect  Dialect            // shards and replicas are of the primary's database system
+
+	mu        sync.RWMutex
+	residency map[uuid.UUID]string
+}
+
+func NewShardRouter(primary *sql.DB, shards, replicas map[string]*sql.DB) *ShardRouter {
+	if shards == nil {
+		shards = make(map[string]*sql.DB)
+	}
+	if replicas == nil {
+		replicas = make(map[string]*sql.DB)
+	}
+	return &ShardRouter{
+		primary:   primary,
+		shards:    shards,
+		replicas:  replicas,
+		dialect:   DialectOf(primary),
+		residency: make(map[uuid.UUID]string),
+	}
+}
+
+func (r *ShardRouter) Primary() *sql.DB {
+	return r.primary
+}
+
+// Dialect returns how the databases spell the constructs that differ between database systems.
+func (r *ShardRouter) Dialect() Dialect {
+	return r.dialect
+}
+
+// Residencies lists every residency a domain may be assigned to.
+func (r *ShardRouter) Residencies() []string {
+	residencies := []string{DefaultResidency}
+	for region := range r.shards {
+		residencies = append(residencies, region)
+	}
+	sort.Strings(residencies[1:])
+	return residencies
+}
+
+func (r *ShardRouter) HasResidency(residency string) bool {
+	if residency == DefaultResidency {
+		return true
+	}
+	_, ok := r.shards[residency]
+	return ok
+}
+
+func (r *ShardRouter) ForResidency(residency string) *sql.DB {
+	if db, ok := r.shards[residency]; ok {
+		return db
+	}
+	return r.primary
+}
+
+// ForDomain returns the database holding the domain's tenant data, caching the residency lookup.
+func (r *ShardRouter) ForDomain(ctx context.Context, domainID uuid.UUID) (*sql.DB, error) {
+	if len(r.shards) == 0 {
+		return r.primary, nil
+	}
+
+	r.mu.RLock()
+	residency, ok := r.residency[domainID]
+	r.mu.RUnlock()
+	if ok {
+		return r.ForResidency(residency), nil
+	}
+
+	ctx, end := observe(ctx, "domains", "lookup_residency")
+	defer end()
+
+	err := r.primary.QueryRowContext(ctx, "SELECT residency FROM domains WHERE domain_id = $1", domainID).Scan(&residency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.primary, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.Remember(domainID, residency)
+	return r.ForResidency(residency), nil
+}
+
+// ForTenant is ForDomain for statements on the domain's tenant data. The returned context carries
+// the domain so that, with row-level security enabled, the session's app.domain_id matches it and
+// a query missing its domain filter can't read or write another tenant's rows. Within a TxManager
+// transaction on that database, the transaction is returned instead.
+func (r *ShardRouter) ForTenant(ctx context.Context, domainID uuid.UUID) (context.Context, DBTX, error) {
+	db, err := r.ForDomain(ctx, domainID)
+	if err != nil {
+		return ctx, nil, err
+	}
+	return tenancy.WithDomain(ctx, domainID), withinTx(ctx, db), nil
+}
+
+func (r *ShardRouter) Remember(domainID uuid.UUID, residency string) {
+	r.mu.Lock()
+	r.residency[domainID] = residency
+	r.mu.Unlock()
+}
+
+func (r *ShardRouter) Forget(domainID uuid.UUID) {
+	r.mu.Lock()
+	delete(r.residency, domainID)
+	r.mu.Unlock()
+}
+
+// All returns the primary database followed by every shard.
+func (r *ShardRouter) All() []*sql.DB {
+	dbs := []*sql.DB{r.primary}
+	for _, region := range r.Residencies()[1:] {
+		dbs = append(dbs, r.shards[region])
+	}
+	return dbs
+}
+
+// QueryRowAcross runs query against each database until one yields a row; used for
+// lookups by record ID or globally unique columns where the owning domain is unknown.
+func (r *ShardRouter) QueryRowAcross(ctx context.Context, query func(db *sql.DB) error) error {
+	for _, db := range r.All() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := query(db)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// ExecAcross applies a statement keyed by record ID on every database; shards that
+// don't hold the record are unaffected.
+func (r *ShardRouter) ExecAcross(ctx context.Context, query string, args ...interface{}) error {
+	for _, db := range r.All() {
+		if _, err := db.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
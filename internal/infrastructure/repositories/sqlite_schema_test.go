@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"backend/internal/infrastructure/sqlitedb"
+)
+
+// migratedTable is a table as the migrations leave it: its columns, and its unique constraints
+// and indexes by name, each with its comma-separated columns.
+type migratedTable struct {
+	columns map[string]bool
+	unique  map[string]string
+}
+
+var (
+	migrationComment     = regexp.MustCompile(`--[^\n]*`)
+	migrationCreateTable = regexp.MustCompile(`(?is)^CREATE TABLE (?:IF NOT EXISTS )?(\w+) \((.*)\)$`)
+	migrationAddColumn   = regexp.MustCompile(`(?i)^ALTER TABLE (\w+) ADD COLUMN (?:IF NOT EXISTS )?(\w+)`)
+	migrationDropColumn  = regexp.MustCompile(`(?i)^ALTER TABLE (\w+) DROP COLUMN (?:IF EXISTS )?(\w+)`)
+	migrationDropUnique  = regexp.MustCompile(`(?i)^ALTER TABLE (\w+) DROP CONSTRAINT (?:IF EXISTS )?(\w+)`)
+	migrationUniqueIndex = regexp.MustCompile(`(?i)^CREATE UNIQUE INDEX (?:CONCURRENTLY )?(?:IF NOT EXISTS )?(\w+) ON (\w+) ?\(([^)]*)\)`)
+	migrationDropIndex   = regexp.MustCompile(`(?i)^DROP INDEX (?:CONCURRENTLY )?(?:IF EXISTS )?(\w+)`)
+	migrationKey         = regexp.MustCompile(`(?i)^(PRIMARY KEY|UNIQUE) ?\(([^)]*)\)`)
+)
+
+// migratedSchema replays the DDL of the numbered migrations, in order, on a description of the
+// tables. It knows the statements the migrations use; other statements change no table.
+func migratedSchema(t *testing.T) map[string]*migratedTable {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join("..", "..", "..", "migrations", "[0-9]*.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("migrations: %v", err)
+	}
+	sort.Strings(files)
+
+	tables := make(map[string]*migratedTable)
+	for _, file := range files {
+		script, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, statement := range SplitStatements(string(script)) {
+			statement = strings.Join(strings.Fields(migrationComment.ReplaceAllString(statement, "")), " ")
+			if m := migrationCreateTable.FindStringSubmatch(statement); m != nil {
+				if tables[m[1]] == nil {
+					tables[m[1]] = createdTable(m[1], m[2])
+				}
+			} else if m := migrationAddColumn.FindStringSubmatch(statement); m != nil {
+				tables[m[1]].columns[m[2]] = true
+			} else if m := migrationDropColumn.FindStringSubmatch(statement); m != nil {
+				delete(tables[m[1]].columns, m[2])
+			} else if m := migrationDropUnique.FindStringSubmatch(statement); m != nil {
+				delete(tables[m[1]].unique, m[2])
+			} else if m := migrationUniqueIndex.FindStringSubmatch(statement); m != nil {
+				tables[m[2]].unique[m[1]] = columnList(m[3])
+			} else if m := migrationDropIndex.FindStringSubmatch(statement); m != nil {
+				for _, table := range tables {
+					delete(table.unique, m[1])
+				}
+			}
+		}
+	}
+	return tables
+}
+
+// createdTable describes a table from the body of its CREATE TABLE, naming its constraints as
+// Postgres does.
+func createdTable(name, body string) *migratedTable {
+	table := &migratedTable{columns: make(map[string]bool), unique: make(map[string]string)}
+	for _, item := range splitTopLevel(body) {
+		if m := migrationKey.FindStringSubmatch(item); m != nil {
+			columns := columnList(m[2])
+			if strings.EqualFold(m[1], "PRIMARY KEY") {
+				table.unique[name+"_pkey"] = columns
+			} else {
+				table.unique[name+"_"+strings.ReplaceAll(columns, ",", "_")+"_key"] = columns
+			}
+			continue
+		}
+		fields := strings.Fields(item)
+		switch strings.ToUpper(fields[0]) {
+		case "CONSTRAINT", "FOREIGN", "CHECK", "EXCLUDE":
+			continue
+		}
+		column := fields[0]
+		table.columns[column] = true
+		definition := " " + strings.ToUpper(strings.Join(fields[1:], " ")) + " "
+		if check := strings.Index(definition, " CHECK "); check >= 0 {
+			definition = definition[:check]
+		}
+		if strings.Contains(definition, " PRIMARY KEY ") {
+			table.unique[name+"_pkey"] = column
+		}
+		if strings.Contains(definition, " UNIQUE ") {
+			table.unique[name+"_"+column+"_key"] = column
+		}
+	}
+	return table
+}
+
+// splitTopLevel splits s at the commas outside parentheses.
+func splitTopLevel(s string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(items, strings.TrimSpace(s[start:]))
+}
+
+func columnList(columns string) string {
+	var names []string
+	for _, column := range strings.Split(columns, ",") {
+		names = append(names, strings.TrimSpace(column))
+	}
+	return strings.Join(names, ",")
+}
+
+// sqliteSchema describes the tables of migrations/sqlite/schema.sql, naming unique constraints
+// the way the sqlitedb driver reports their violations.
+func sqliteSchema(t *testing.T) map[string]*migratedTable {
+	t.Helper()
+	db, err := sqlitedb.Open(sqlitedb.Memory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := sqlitedb.CreateSchema(db, filepath.Join("..", "..", "..", "migrations", "sqlite", "schema.sql")); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := queryStrings(db, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name <> 'schema_migrations'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables := make(map[string]*migratedTable)
+	for _, name := range names {
+		table := &migratedTable{columns: make(map[string]bool), unique: make(map[string]string)}
+		columns, err := queryStrings(db, "SELECT name FROM pragma_table_info($1)", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, column := range columns {
+			table.columns[column] = true
+		}
+		// The primary key has no index of its own when it is the rowid
+		primaryKey, err := queryStrings(db, "SELECT name FROM pragma_table_info($1) WHERE pk > 0 ORDER BY pk", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(primaryKey) > 0 {
+			table.unique[name+"_pkey"] = strings.Join(primaryKey, ",")
+		}
+		rows, err := db.Query(`
+			SELECT l.name, l.origin, (SELECT group_concat(name, ',') FROM (SELECT name FROM pragma_index_info(l.name) ORDER BY seqno))
+			FROM pragma_index_list($1) l WHERE l."unique" AND l.origin <> 'pk'`, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for rows.Next() {
+			var index, origin, columns string
+			if err := rows.Scan(&index, &origin, &columns); err != nil {
+				t.Fatal(err)
+			}
+			if origin == "u" {
+				index = name + "_" + strings.ReplaceAll(columns, ",", "_") + "_key"
+			}
+			table.unique[index] = columns
+		}
+		rows.Close()
+		tables[name] = table
+	}
+	return tables
+}
+
+func queryStrings(db *sql.DB, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// The SQLite schema is the final state of the migrations: the same tables, columns and unique
+// constraints, so a migration not mirrored in it fails here.
+func TestSQLiteSchemaMatchesMigrations(t *testing.T) {
+	migrated, sqlite := migratedSchema(t), sqliteSchema(t)
+	for name, want := range migrated {
+		got, ok := sqlite[name]
+		if !ok {
+			t.Errorf("table %s is missing from the SQLite schema", name)
+			continue
+		}
+		if !reflect.DeepEqual(got.columns, want.columns) {
+			t.Errorf("%s columns = %v, want %v", name, sortedKeys(got.columns), sortedKeys(want.columns))
+		}
+		if !reflect.DeepEqual(got.unique, want.unique) {
+			t.Errorf("%s unique constraints = %v, want %v", name, got.unique, want.unique)
+		}
+	}
+	for name := range sqlite {
+		if migrated[name] == nil {
+			t.Errorf("table %s of the SQLite schema is not created by a migration", name)
+		}
+	}
+}
+
+func sortedKeys(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return fmt.Sprint(keys)
+}
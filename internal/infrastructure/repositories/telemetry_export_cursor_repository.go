@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// TelemetryExportCursorRepository tracks how far each domain's login telemetry has been exported.
+type TelemetryExportCursorRepository interface {
+	Get(ctx context.Context, domainID uuid.UUID) (*entities.TelemetryExportCursor, error)
+	Claim(ctx context.Context, domainID uuid.UUID, lease time.Duration) (int64, bool, error)
+	Advance(ctx context.Context, domainID uuid.UUID, sequence int64) error
+	Release(ctx context.Context, domainID uuid.UUID, reason string) error
+	Delete(ctx context.Context, domainID uuid.UUID) error
+}
+
+type telemetryExportCursorRepository struct {
+	router *ShardRouter
+}
+
+func NewTelemetryExportCursorRepository(router *ShardRouter) TelemetryExportCursorRepository {
+	return &telemetryExportCursorRepository{router: router}
+}
+
+// Get returns the domain's cursor, or sql.ErrNoRows before its first export.
+func (r *telemetryExportCursorRepository) Get(ctx context.Context, domainID uuid.UUID) (*entities.TelemetryExportCursor, error) {
+	ctx, end := observe(ctx, "telemetry_export_cursors", "get")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := &entities.TelemetryExportCursor{DomainID: domainID}
+	var lastError sql.NullString
+	err = db.QueryRowContext(ctx, `
+		SELECT last_sequence, exported_at, last_error FROM telemetry_export_cursors WHERE domain_id = $1`,
+		domainID).Scan(&cursor.LastSequence, &cursor.ExportedAt, &lastError)
+	if err != nil {
+		return nil, err
+	}
+	if lastError.Valid {
+		cursor.LastError = &lastError.String
+	}
+	return cursor, nil
+}
+
+// Claim holds the domain's cursor for lease and returns the sequence its export resumes after. A
+// domain exported for the first time starts after its latest event. ok is false while another
+// instance holds the cursor.
+func (r *telemetryExportCursorRepository) Claim(ctx context.Context, domainID uuid.UUID, lease time.Duration) (int64, bool, error) {
+	ctx, end := observe(ctx, "telemetry_export_cursors", "claim")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var since int64
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO telemetry_export_cursors (domain_id, last_sequence, locked_until)
+		VALUES ($1, COALESCE((SELECT last_sequence FROM event_sequences WHERE domain_id = $1), 0),
+			`+r.router.Dialect().SecondsFromNow("$2")+`)
+		ON CONFLICT (domain_id) DO UPDATE SET locked_until = EXCLUDED.locked_until
+		WHERE telemetry_export_cursors.locked_until IS NULL OR telemetry_export_cursors.locked_until <= NOW()
+		RETURNING last_sequence`, domainID, lease.Seconds()).Scan(&since)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return since, true, nil
+}
+
+// Advance records that the events up to sequence have been exported. The cursor stays held.
+func (r *telemetryExportCursorRepository) Advance(ctx context.Context, domainID uuid.UUID, sequence int64) error {
+	ctx, end := observe(ctx, "telemetry_export_cursors", "advance")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		UPDATE telemetry_export_cursors
+		SET last_sequence = $2, exported_at = NOW(), last_error = NULL, updated_at = NOW()
+		WHERE domain_id = $1`, domainID, sequence)
+	return err
+}
+
+// Release lets the next run claim the cursor again, recording reason as the export's failure when
+// it is not empty.
+func (r *telemetryExportCursorRepository) Release(ctx context.Context, domainID uuid.UUID, reason string) error {
+	ctx, end := observe(ctx, "telemetry_export_cursors", "release")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		UPDATE telemetry_export_cursors
+		SET locked_until = NULL, last_error = NULLIF($2, ''), updated_at = NOW()
+		WHERE domain_id = $1`, domainID, reason)
+	return err
+}
+
+// Delete drops the domain's cursor, so its next export starts after its latest event.
+func (r *telemetryExportCursorRepository) Delete(ctx context.Context, domainID uuid.UUID) error {
+	ctx, end := observe(ctx, "telemetry_export_cursors", "delete")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, "DELETE FROM telemetry_export_cursors WHERE domain_id = $1", domainID)
+	return err
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+type TrustedDeviceRepository interface {
+	Create(ctx context.Context, device *entities.TrustedDevice) error
+	GetByTokenHash(ctx context.Context, domainID uuid.UUID, tokenHash string) (*entities.TrustedDevice, error)
+	ListByUser(ctx context.Context, domainID, userID uuid.UUID) ([]*entities.TrustedDevice, error)
+	RecordUse(ctx context.Context, device *entities.TrustedDevice, ip string, expiresAt time.Time) error
+	Delete(ctx context.Context, domainID, userID, id uuid.UUID) error
+	DeleteByUser(ctx context.Context, domainID, userID uuid.UUID) (int64, error)
+}
+
+type trustedDeviceRepository struct {
+	router *ShardRouter
+}
+
+func NewTrustedDeviceRepository(router *ShardRouter) TrustedDeviceRepository {
+	return &trustedDeviceRepository{router: router}
+}
+
+const trustedDeviceColumns = "id, domain_id, user_id, token_hash, name, last_ip, created_at, last_used_at, expires_at"
+
+// Create stores the device and drops the user's expired ones.
+func (r *trustedDeviceRepository) Create(ctx context.Context, device *entities.TrustedDevice) error {
+	ctx, end := observe(ctx, "trusted_devices", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, device.DomainID)
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM trusted_devices WHERE user_id = $1 AND expires_at < NOW()", device.UserID); err != nil {
+		return err
+	}
+
+	device.ID = uuid.New()
+	return db.QueryRowContext(ctx, `
+		INSERT INTO trusted_devices (id, domain_id, user_id, token_hash, name, last_ip, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING created_at, last_used_at`,
+		device.ID, device.DomainID, device.UserID, device.TokenHash, device.Name, device.LastIP, device.ExpiresAt).
+		Scan(&device.CreatedAt, &device.LastUsedAt)
+}
+
+// GetByTokenHash returns the unexpired device of the domain with the token.
+func (r *trustedDeviceRepository) GetByTokenHash(ctx context.Context, domainID uuid.UUID, tokenHash string) (*entities.TrustedDevice, error) {
+	ctx, end := observe(ctx, "trusted_devices", "get_by_token_hash")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanTrustedDevice(db.QueryRowContext(ctx, "SELECT "+trustedDeviceColumns+`
+		FROM trusted_devices WHERE token_hash = $1 AND domain_id = $2 AND expires_at > NOW()`,
+		tokenHash, domainID))
+}
+
+// ListByUser returns the user's unexpired devices, most recently used first.
+func (r *trustedDeviceRepository) ListByUser(ctx context.Context, domainID, userID uuid.UUID) ([]*entities.TrustedDevice, error) {
+	ctx, end := observe(ctx, "trusted_devices", "list_by_user")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, "SELECT "+trustedDeviceColumns+`
+		FROM trusted_devices WHERE user_id = $1 AND expires_at > NOW()
+		ORDER BY last_used_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := []*entities.TrustedDevice{}
+	for rows.Next() {
+		device, err := scanTrustedDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, rows.Err()
+}
+
+// RecordUse notes a login from the device and moves its expiry to expiresAt.
+func (r *trustedDeviceRepository) RecordUse(ctx context.Context, device *entities.TrustedDevice, ip string, expiresAt time.Time) error {
+	ctx, end := observe(ctx, "trusted_devices", "record_use")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, device.DomainID)
+	if err != nil {
+		return err
+	}
+	err = db.QueryRowContext(ctx, `
+		UPDATE trusted_devices SET last_ip = $1, last_used_at = NOW(), expires_at = $2
+		WHERE id = $3 RETURNING last_used_at`, ip, expiresAt, device.ID).Scan(&device.LastUsedAt)
+	if err != nil {
+		return err
+	}
+	device.LastIP = ip
+	device.ExpiresAt = expiresAt
+	return nil
+}
+
+func (r *trustedDeviceRepository) Delete(ctx context.Context, domainID, userID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "trusted_devices", "delete")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	result, err := db.ExecContext(ctx, "DELETE FROM trusted_devices WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *trustedDeviceRepository) DeleteByUser(ctx context.Context, domainID, userID uuid.UUID) (int64, error) {
+	ctx, end := observe(ctx, "trusted_devices", "delete_by_user")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return 0, err
+	}
+	result, err := db.ExecContext(ctx, "DELETE FROM trusted_devices WHERE user_id = $1", userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanTrustedDevice(row rowScanner) (*entities.TrustedDevice, error) {
+	var device entities.TrustedDevice
+	err := row.Scan(&device.ID, &device.DomainID, &device.UserID, &device.TokenHash, &device.Name, &device.LastIP,
+		&device.CreatedAt, &device.LastUsedAt, &device.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
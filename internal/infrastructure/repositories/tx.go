@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/google/uuid"
+)
+
+// DBTX is what tenant repositories run statements on: a database, or the transaction a
+// TxManager opened on it.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// TxManager runs several repository calls atomically.
+type TxManager interface {
+	// WithinTenantTx runs fn in a transaction on the database holding the domain's tenant data.
+	// Tenant repository calls made with fn's context on that database join the transaction, which
+	// commits when fn returns nil and rolls back otherwise. Nested calls join the open transaction.
+	WithinTenantTx(ctx context.Context, domainID uuid.UUID, fn func(ctx context.Context) error) error
+}
+
+type txManager struct {
+	router *ShardRouter
+}
+
+func NewTxManager(router *ShardRouter) TxManager {
+	return &txManager{router: router}
+}
+
+type txKey struct{}
+
+// openTx is the transaction carried by a context, with the database it was opened on.
+type openTx struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+var errTxAcrossDatabases = errors.New("transaction already open on another database")
+
+func (m *txManager) WithinTenantTx(ctx context.Context, domainID uuid.UUID, fn func(ctx context.Context) error) error {
+	db, err := m.router.ForDomain(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	if open, ok := ctx.Value(txKey{}).(*openTx); ok {
+		if open.db != db {
+			return errTxAcrossDatabases
+		}
+		return fn(ctx)
+	}
+
+	// The tenant is set before BEGIN so row-level security covers the whole transaction
+	tx, err := db.BeginTx(tenancy.WithDomain(ctx, domainID), nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, &openTx{db: db, tx: tx})); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// withinTx returns the transaction ctx carries for db, or db itself when there is none.
+func withinTx(ctx context.Context, db *sql.DB) DBTX {
+	if open, ok := ctx.Value(txKey{}).(*openTx); ok && open.db == db {
+		return open.tx
+	}
+	return db
+}
+
+// inTx runs fn in a transaction of its own on db, or as part of the transaction db already is.
+func inTx(ctx context.Context, db DBTX, fn func(tx DBTX) error) error {
+	pool, ok := db.(*sql.DB)
+	if !ok {
+		return fn(db)
+	}
+	tx, err := pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
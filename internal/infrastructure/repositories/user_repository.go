@@ -1,24 +1,81 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"backend/internal/domain/entities"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type UserRepository interface {
-	GetByID(id uuid.UUID) (*entities.User, error)
-	GetByUsername(username string) (*entities.User, error)
-	GetByEmail(email string) (*entities.User, error)
-	GetByDomainID(domainID uuid.UUID) ([]*entities.User, error)
-	Create(user *entities.User) error
-	Update(user *entities.User) error
-	UpdatePassword(id uuid.UUID, hashedPassword string) error
-	Delete(id uuid.UUID) error
-	ListWithPagination(search string, domainID uuid.UUID, page, limit int) (*UserListResult, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.User, error)
+	GetByUsernameAndDomain(ctx context.Context, username string, domainID uuid.UUID) (*entities.User, error)
+	GetByEmailAndDomain(ctx context.Context, email string, domainID uuid.UUID) (*entities.User, error)
+	GetByExternalID(ctx context.Context, domainID uuid.UUID, externalID string) (*entities.User, error)
+	GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.User, error)
+	ListByRole(ctx context.Context, domainID, roleID uuid.UUID) ([]*entities.User, error)
+	ListRoleMembers(ctx context.Context, domainID, roleID uuid.UUID, page, limit int) (*UserListResult, error)
+	Create(ctx context.Context, user *entities.User) error
+	Update(ctx context.Context, user *entities.User) error
+	UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListWithPagination(ctx context.Context, filter UserListFilter, domainID uuid.UUID, page, limit int) (*UserListResult, error)
+	ListAfter(ctx context.Context, filter UserListFilter, domainID uuid.UUID, after *ListCursor, limit int) (*UserCursorPage, error)
+	ListByRoleClaims(ctx context.Context, domainID uuid.UUID, claims []string, page, limit int) (*UserListResult, error)
+	FindConflicts(ctx context.Context, domainID uuid.UUID, usernames, emails, externalIDs []string) (*UserConflicts, error)
+	FindDuplicate(ctx context.Context, domainID uuid.UUID, username, email string, excludeID uuid.UUID) (*entities.User, error)
+	CreateBatch(ctx context.Context, domainID uuid.UUID, users []*entities.User) error
+	StreamByDomainID(ctx context.Context, domainID uuid.UUID, fn func(*entities.User) error) error
+	ListExpiring(ctx context.Context, domainID uuid.UUID, before time.Time) ([]*entities.User, error)
+	ListExpired(ctx context.Context, at time.Time) ([]*entities.User, error)
+	SetValidity(ctx context.Context, id uuid.UUID, validUntil, disabledAt *time.Time) error
+	Disable(ctx context.Context, id uuid.UUID, at time.Time) error
+	RevokeSessions(ctx context.Context, id uuid.UUID, at time.Time) error
+	RecordLogin(ctx context.Context, user *entities.User, ip string, at time.Time) error
+	UpdateAvatar(ctx context.Context, user *entities.User) error
+	SetOrgUnit(ctx context.Context, user *entities.User) error
+	AssignRoleInOrgUnit(ctx context.Context, domainID, orgUnitID, roleID uuid.UUID) ([]*entities.User, error)
+	ListBreakGlass(ctx context.Context) ([]*entities.User, error)
+	ScheduleDeletion(ctx context.Context, id uuid.UUID, at *time.Time) error
+	ListDueForDeletion(ctx context.Context, at time.Time) ([]*entities.User, error)
+}
+
+// UserConflicts lists identifiers that are already taken in a domain.
+type UserConflicts struct {
+	Usernames   map[string]bool
+	Emails      map[string]bool
+	ExternalIDs map[string]bool
+}
+
+// Lifecycle states a user listing can be filtered by.
+const (
+	UserStatusActive          = "active"
+	UserStatusDisabled        = "disabled"
+	UserStatusExpired         = "expired"
+	UserStatusPendingDeletion = "pending_deletion"
+)
+
+// UserListFilter narrows a user listing; zero values are ignored. RoleID matches direct and
+// group-inherited assignments, OrgUnitID the users of the unit and its descendants, and
+// EmailDomain the part of the email after the @. DelegatedOrgUnitID is the unit of an org unit
+// admin, whose listings leave out users outside its subtree whatever else the filter asks for.
+type UserListFilter struct {
+	Search             string
+	RoleID             uuid.UUID
+	OrgUnitID          uuid.UUID
+	DelegatedOrgUnitID uuid.UUID
+	Status             string
+	CreatedAfter       *time.Time
+	CreatedBefore      *time.Time
+	EmailDomain        string
+	Sort               ListSort
 }
 
 type UserListResult struct {
@@ -29,57 +86,131 @@ type UserListResult struct {
 	TotalPages int              `json:"total_pages"`
 }
 
+// UserCursorPage is a page of a cursor-paginated user listing. NextCursor is empty on the last page.
+type UserCursorPage struct {
+	Users      []*entities.User `json:"users"`
+	Limit      int              `json:"limit"`
+	NextCursor string           `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyNi0xMC0xNlQwODowMDowMFoiLCJpZCI6IjNmYTg1ZjY0LTU3MTctNDU2Mi1iM2ZjLTJjOTYzZjY2YWZhNiJ9"`
+}
+
 type userRepository struct {
-	db *sql.DB
+	router *ShardRouter
 }
 
-func NewUserRepository(db *sql.DB) UserRepository {
-	return &userRepository{db: db}
+func NewUserRepository(router *ShardRouter) UserRepository {
+	return &userRepository{router: router}
 }
 
-func (r *userRepository) GetByID(id uuid.UUID) (*entities.User, error) {
-	var user entities.User
-	err := r.db.QueryRow(`
-		SELECT id, domain_id, role_id, first_name, last_name, username, email, password_hash, created_at, updated_at
-		FROM users WHERE id = $1`, id).Scan(
-		&user.ID, &user.DomainID, &user.RoleID, &user.FirstName, &user.LastName,
-		&user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+var userColumnNames = []string{"id", "domain_id", "role_id", "external_id", "first_name", "last_name", "username", "email", "password_hash", "valid_until", "disabled_at", "sessions_revoked_at", "break_glass", "password_changed_at", "deletion_scheduled_at", "last_login_at", "last_login_ip", "type", "client_secret_hash", "avatar_key", "avatar_url", "org_unit_id", "created_at", "updated_at"}
+
+var userColumns = strings.Join(userColumnNames, ", ")
+
+// userColumnsAs qualifies the user columns with a table alias for joins.
+func userColumnsAs(alias string) string {
+	return alias + "." + strings.Join(userColumnNames, ", "+alias+".")
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	ctx, end := observe(ctx, "users", "get_by_id")
+	defer end()
+
+	var user *entities.User
+	err := r.router.QueryRowAcross(ctx, func(db *sql.DB) error {
+		var err error
+		user, err = scanUser(db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", id))
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	return user, nil
 }
 
-func (r *userRepository) GetByUsername(username string) (*entities.User, error) {
-	var user entities.User
-	err := r.db.QueryRow(`
-		SELECT id, domain_id, role_id, first_name, last_name, username, email, password_hash, created_at, updated_at
-		FROM users WHERE username = $1`, username).Scan(
-		&user.ID, &user.DomainID, &user.RoleID, &user.FirstName, &user.LastName,
-		&user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+// GetByIDs returns the users with the given IDs in no particular order, asking each database only
+// for the IDs not found yet. IDs without a user are left out.
+func (r *userRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.User, error) {
+	ctx, end := observe(ctx, "users", "get_by_ids")
+	defer end()
+
+	var users []*entities.User
+	remaining := ids
+	for _, db := range r.router.All() {
+		if len(remaining) == 0 {
+			break
+		}
+		rows, err := db.QueryContext(ctx, "SELECT "+userColumns+" FROM users WHERE "+
+			r.router.Dialect().AnyOf("id", r.router.Dialect().Cast("$1", "uuid[]")), pq.Array(uuidStrings(remaining)))
+		if err != nil {
+			return nil, err
+		}
+		found := make(map[uuid.UUID]bool)
+		for rows.Next() {
+			user, err := scanUser(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			users = append(users, user)
+			found[user.ID] = true
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		remaining = withoutIDs(remaining, found)
+	}
+	return users, nil
+}
+
+// GetByUsernameAndDomain looks up a username within one domain; the same username may exist
+// in other domains.
+func (r *userRepository) GetByUsernameAndDomain(ctx context.Context, username string, domainID uuid.UUID) (*entities.User, error) {
+	ctx, end := observe(ctx, "users", "get_by_username_and_domain")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	return scanUser(db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE domain_id = $1 AND username = $2", domainID, username))
 }
 
-func (r *userRepository) GetByEmail(email string) (*entities.User, error) {
-	var user entities.User
-	err := r.db.QueryRow(`
-		SELECT id, domain_id, role_id, first_name, last_name, username, email, password_hash, created_at, updated_at
-		FROM users WHERE email = $1`, email).Scan(
-		&user.ID, &user.DomainID, &user.RoleID, &user.FirstName, &user.LastName,
-		&user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+// GetByEmailAndDomain looks up an email within one domain; the same email may exist in other domains.
+func (r *userRepository) GetByEmailAndDomain(ctx context.Context, email string, domainID uuid.UUID) (*entities.User, error) {
+	ctx, end := observe(ctx, "users", "get_by_email_and_domain")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	return scanUser(db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE domain_id = $1 AND email = $2", domainID, email))
 }
 
-func (r *userRepository) GetByDomainID(domainID uuid.UUID) ([]*entities.User, error) {
-	rows, err := r.db.Query(`
-		SELECT id, domain_id, role_id, first_name, last_name, username, email, password_hash, created_at, updated_at
-		FROM users WHERE domain_id = $1 ORDER BY username`, domainID)
+// GetByExternalID looks up a user by the identifier assigned by an external system such as an HR
+// platform; external IDs are unique per domain.
+func (r *userRepository) GetByExternalID(ctx context.Context, domainID uuid.UUID, externalID string) (*entities.User, error) {
+	ctx, end := observe(ctx, "users", "get_by_external_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanUser(db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE domain_id = $1 AND external_id = $2", domainID, externalID))
+}
+
+func (r *userRepository) GetByDomainID(ctx context.Context, domainID uuid.UUID) ([]*entities.User, error) {
+	ctx, end := observe(ctx, "users", "get_by_domain_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+userColumns+" FROM users WHERE domain_id = $1 ORDER BY username", domainID)
 	if err != nil {
 		return nil, err
 	}
@@ -87,76 +218,533 @@ func (r *userRepository) GetByDomainID(domainID uuid.UUID) ([]*entities.User, er
 
 	var users []*entities.User
 	for rows.Next() {
-		var user entities.User
-		err := rows.Scan(&user.ID, &user.DomainID, &user.RoleID, &user.FirstName, &user.LastName,
-			&user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, err
 		}
-		users = append(users, &user)
+		users = append(users, user)
 	}
 	return users, nil
 }
 
-func (r *userRepository) Create(user *entities.User) error {
+// ListByRole returns the users holding the role, directly or through a group.
+func (r *userRepository) ListByRole(ctx context.Context, domainID, roleID uuid.UUID) ([]*entities.User, error) {
+	ctx, end := observe(ctx, "users", "list_by_role")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+userColumns+` FROM users
+		WHERE domain_id = $1 AND (role_id = $2 OR id IN (
+			SELECT gm.user_id FROM group_members gm JOIN group_roles gr ON gr.group_id = gm.group_id
+			WHERE gr.role_id = $2))
+		ORDER BY username`, domainID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// ListRoleMembers returns a page of the users holding the role, directly or through a group, by
+// username. Each half of the union is looked up by role in an index (idx_users_role_id and
+// idx_group_roles_role_id) rather than by filtering the domain's users.
+func (r *userRepository) ListRoleMembers(ctx context.Context, domainID, roleID uuid.UUID, page, limit int) (*UserListResult, error) {
+	ctx, end := observe(ctx, "users", "list_role_members")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	// Listings tolerate replica lag up to the client's consistency token
+	db = r.router.ForRead(ctx, db)
+
+	members := `
+		SELECT ` + userColumns + ` FROM users WHERE domain_id = $1 AND role_id = $2
+		UNION
+		SELECT ` + userColumnsAs("u") + `
+		FROM users u
+			JOIN group_members gm ON gm.user_id = u.id
+			JOIN group_roles gr ON gr.group_id = gm.group_id
+		WHERE u.domain_id = $1 AND gr.role_id = $2`
+
+	var total int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+members+") AS members", domainID, roleID).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, members+" ORDER BY username, id LIMIT $3 OFFSET $4", domainID, roleID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &UserListResult{
+		Users:      users,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + limit - 1) / limit,
+	}, nil
+}
+
+// StreamByDomainID calls fn for every user of the domain while iterating the result set, so
+// exports don't hold the whole domain in memory. An error from fn stops the iteration.
+func (r *userRepository) StreamByDomainID(ctx context.Context, domainID uuid.UUID, fn func(*entities.User) error) error {
+	ctx, end := observe(ctx, "users", "stream_by_domain_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+userColumns+" FROM users WHERE domain_id = $1 ORDER BY username", domainID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *userRepository) Create(ctx context.Context, user *entities.User) error {
+	ctx, end := observe(ctx, "users", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, user.DomainID)
+	if err != nil {
+		return err
+	}
+
 	user.ID = uuid.New()
-	err := r.db.QueryRow(`
-		INSERT INTO users (id, domain_id, role_id, first_name, last_name, username, email, password_hash)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
-		user.ID, user.DomainID, user.RoleID, user.FirstName, user.LastName,
-		user.Username, user.Email, user.PasswordHash).Scan(&user.ID)
+	if user.Type == "" {
+		user.Type = entities.UserTypeHuman
+	}
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO users (id, domain_id, role_id, external_id, first_name, last_name, username, email, password_hash, valid_until, break_glass, type, client_secret_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`,
+		user.ID, user.DomainID, user.RoleID, user.ExternalID, user.FirstName, user.LastName,
+		user.Username, user.Email, user.PasswordHash, user.ValidUntil, user.BreakGlass, user.Type, user.ClientSecretHash).Scan(&user.ID)
 	return err
 }
 
-func (r *userRepository) Update(user *entities.User) error {
-	_, err := r.db.Exec(`
-		UPDATE users SET first_name = $1, last_name = $2, username = $3, email = $4, role_id = $5, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $6`, user.FirstName, user.LastName, user.Username, user.Email, user.RoleID, user.ID)
-	return err
+// CreateBatch inserts all users in a single transaction; either every user is created or none is.
+func (r *userRepository) CreateBatch(ctx context.Context, domainID uuid.UUID, users []*entities.User) error {
+	ctx, end := observe(ctx, "users", "create_batch")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+
+	return inTx(ctx, db, func(tx DBTX) error {
+		stmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO users (id, domain_id, role_id, external_id, first_name, last_name, username, email, password_hash, valid_until)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING created_at, updated_at`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, user := range users {
+			user.ID = uuid.New()
+			user.DomainID = domainID
+			user.Type = entities.UserTypeHuman
+			err := stmt.QueryRowContext(ctx, user.ID, user.DomainID, user.RoleID, user.ExternalID, user.FirstName, user.LastName,
+				user.Username, user.Email, user.PasswordHash, user.ValidUntil).Scan(&user.CreatedAt, &user.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to insert %s: %w", user.Username, err)
+			}
+		}
+		return nil
+	})
 }
 
-func (r *userRepository) UpdatePassword(id uuid.UUID, hashedPassword string) error {
-	_, err := r.db.Exec(`
-		UPDATE users SET password_hash = $1, updated_at = CURRENT_TIMESTAMP
+// FindConflicts reports which of the given identifiers are already in use in the domain.
+func (r *userRepository) FindConflicts(ctx context.Context, domainID uuid.UUID, usernames, emails, externalIDs []string) (*UserConflicts, error) {
+	ctx, end := observe(ctx, "users", "find_conflicts")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	d := r.router.Dialect()
+	rows, err := db.QueryContext(ctx, `
+		SELECT username, email, COALESCE(external_id, '') FROM users
+		WHERE domain_id = $1 AND (`+d.AnyOf("username", "$2")+` OR `+d.AnyOf("email", "$3")+` OR `+d.AnyOf("external_id", "$4")+`)`,
+		domainID, pq.Array(usernames), pq.Array(emails), pq.Array(externalIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conflicts := &UserConflicts{
+		Usernames:   make(map[string]bool),
+		Emails:      make(map[string]bool),
+		ExternalIDs: make(map[string]bool),
+	}
+	for rows.Next() {
+		var username, email, externalID string
+		if err := rows.Scan(&username, &email, &externalID); err != nil {
+			return nil, err
+		}
+		conflicts.Usernames[username] = true
+		conflicts.Emails[email] = true
+		if externalID != "" {
+			conflicts.ExternalIDs[externalID] = true
+		}
+	}
+	return conflicts, rows.Err()
+}
+
+// FindDuplicate returns a user of the domain, other than excludeID, that already holds the
+// username or email; sql.ErrNoRows means both are free.
+func (r *userRepository) FindDuplicate(ctx context.Context, domainID uuid.UUID, username, email string, excludeID uuid.UUID) (*entities.User, error) {
+	ctx, end := observe(ctx, "users", "find_duplicate")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanUser(db.QueryRowContext(ctx, "SELECT "+userColumns+` FROM users
+		WHERE domain_id = $1 AND (username = $2 OR (email = $3 AND email <> '')) AND id <> $4
+		ORDER BY username = $2 DESC LIMIT 1`, domainID, username, email, excludeID))
+}
+
+func (r *userRepository) Update(ctx context.Context, user *entities.User) error {
+	ctx, end := observe(ctx, "users", "update")
+	defer end()
+
+	return r.router.ExecAcross(ctx, `
+		UPDATE users SET first_name = $1, last_name = $2, username = $3, email = $4, role_id = $5, external_id = $6, updated_at = NOW()
+		WHERE id = $7`, user.FirstName, user.LastName, user.Username, user.Email, user.RoleID, user.ExternalID, user.ID)
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hashedPassword string) error {
+	ctx, end := observe(ctx, "users", "update_password")
+	defer end()
+
+	return r.router.ExecAcross(ctx, `
+		UPDATE users SET password_hash = $1, password_changed_at = NOW(), updated_at = NOW()
 		WHERE id = $2`, hashedPassword, id)
-	return err
 }
 
-func (r *userRepository) Delete(id uuid.UUID) error {
-	_, err := r.db.Exec("DELETE FROM users WHERE id = $1", id)
-	return err
+// ListExpiring returns the active users of the domain whose end date falls before the given
+// time, soonest first; users already past their end date but not yet swept are included.
+func (r *userRepository) ListExpiring(ctx context.Context, domainID uuid.UUID, before time.Time) ([]*entities.User, error) {
+	ctx, end := observe(ctx, "users", "list_expiring")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+userColumns+` FROM users
+		WHERE domain_id = $1 AND disabled_at IS NULL AND valid_until IS NOT NULL AND valid_until <= $2
+		ORDER BY valid_until, username`, domainID, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// ListExpired returns active users of every domain whose end date is at or before the given
+// time. It reads every database since the sweep is not scoped to a domain.
+func (r *userRepository) ListExpired(ctx context.Context, at time.Time) ([]*entities.User, error) {
+	ctx, end := observe(ctx, "users", "list_expired")
+	defer end()
+
+	var users []*entities.User
+	for _, db := range r.router.All() {
+		rows, err := db.QueryContext(ctx, "SELECT "+userColumns+` FROM users
+			WHERE disabled_at IS NULL AND valid_until IS NOT NULL AND valid_until <= $1
+			ORDER BY valid_until`, at)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			user, err := scanUser(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			users = append(users, user)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+// SetValidity stores the account end date and disabled state.
+func (r *userRepository) SetValidity(ctx context.Context, id uuid.UUID, validUntil, disabledAt *time.Time) error {
+	ctx, end := observe(ctx, "users", "set_validity")
+	defer end()
+
+	return r.router.ExecAcross(ctx, `
+		UPDATE users SET valid_until = $1, disabled_at = $2, updated_at = NOW()
+		WHERE id = $3`, validUntil, disabledAt, id)
 }
 
-func (r *userRepository) ListWithPagination(search string, domainID uuid.UUID, page, limit int) (*UserListResult, error) {
+// Disable marks the user disabled and revokes every token issued before at. Already disabled
+// users are left untouched.
+func (r *userRepository) Disable(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ctx, end := observe(ctx, "users", "disable")
+	defer end()
+
+	return r.router.ExecAcross(ctx, `
+		UPDATE users SET disabled_at = $1, sessions_revoked_at = $1, updated_at = NOW()
+		WHERE id = $2 AND disabled_at IS NULL`, at, id)
+}
+
+// RevokeSessions revokes every token of the user issued before at.
+func (r *userRepository) RevokeSessions(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ctx, end := observe(ctx, "users", "revoke_sessions")
+	defer end()
+
+	return r.router.ExecAcross(ctx, `
+		UPDATE users SET sessions_revoked_at = $1, updated_at = NOW()
+		WHERE id = $2`, at, id)
+}
+
+// RecordLogin stores the user's last successful login. It is not a profile change, so updated_at
+// is left alone.
+func (r *userRepository) RecordLogin(ctx context.Context, user *entities.User, ip string, at time.Time) error {
+	ctx, end := observe(ctx, "users", "record_login")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, user.DomainID)
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, "UPDATE users SET last_login_at = $1, last_login_ip = $2 WHERE id = $3", at, ip, user.ID); err != nil {
+		return err
+	}
+	user.LastLoginAt = &at
+	user.LastLoginIP = &ip
+	return nil
+}
+
+// UpdateAvatar stores the user's avatar key and URL.
+func (r *userRepository) UpdateAvatar(ctx context.Context, user *entities.User) error {
+	ctx, end := observe(ctx, "users", "update_avatar")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, user.DomainID)
+	if err != nil {
+		return err
+	}
+	return db.QueryRowContext(ctx, `
+		UPDATE users SET avatar_key = $1, avatar_url = $2, updated_at = NOW()
+		WHERE id = $3 RETURNING updated_at`, user.AvatarKey, user.AvatarURL, user.ID).Scan(&user.UpdatedAt)
+}
+
+// SetOrgUnit stores the user's org unit.
+func (r *userRepository) SetOrgUnit(ctx context.Context, user *entities.User) error {
+	ctx, end := observe(ctx, "users", "set_org_unit")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, user.DomainID)
+	if err != nil {
+		return err
+	}
+	return db.QueryRowContext(ctx, `
+		UPDATE users SET org_unit_id = $1, updated_at = NOW()
+		WHERE id = $2 RETURNING updated_at`, user.OrgUnitID, user.ID).Scan(&user.UpdatedAt)
+}
+
+// AssignRoleInOrgUnit gives the role to every user of the org unit and its descendants, and returns
+// the users whose role changed.
+func (r *userRepository) AssignRoleInOrgUnit(ctx context.Context, domainID, orgUnitID, roleID uuid.UUID) ([]*entities.User, error) {
+	ctx, end := observe(ctx, "users", "assign_role_in_org_unit")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		UPDATE users SET role_id = $3, updated_at = NOW()
+		WHERE domain_id = $1 AND role_id <> $3 AND org_unit_id IN (`+orgUnitSubtree("$2")+`)
+		RETURNING `+userColumns, domainID, orgUnitID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// ListBreakGlass returns the break-glass accounts of every domain, reading every database.
+func (r *userRepository) ListBreakGlass(ctx context.Context) ([]*entities.User, error) {
+	ctx, end := observe(ctx, "users", "list_break_glass")
+	defer end()
+
+	var users []*entities.User
+	for _, db := range r.router.All() {
+		rows, err := db.QueryContext(ctx, "SELECT "+userColumns+" FROM users WHERE break_glass ORDER BY domain_id, username")
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			user, err := scanUser(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			users = append(users, user)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+// ScheduleDeletion stores when the user's requested deletion takes effect; nil cancels it.
+func (r *userRepository) ScheduleDeletion(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	ctx, end := observe(ctx, "users", "schedule_deletion")
+	defer end()
+
+	return r.router.ExecAcross(ctx, `
+		UPDATE users SET deletion_scheduled_at = $1, updated_at = NOW()
+		WHERE id = $2`, at, id)
+}
+
+// ListDueForDeletion returns the users of every domain whose requested deletion takes effect at
+// or before the given time, reading every database.
+func (r *userRepository) ListDueForDeletion(ctx context.Context, at time.Time) ([]*entities.User, error) {
+	ctx, end := observe(ctx, "users", "list_due_for_deletion")
+	defer end()
+
+	var users []*entities.User
+	for _, db := range r.router.All() {
+		rows, err := db.QueryContext(ctx, "SELECT "+userColumns+` FROM users
+			WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $1
+			ORDER BY deletion_scheduled_at`, at)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			user, err := scanUser(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			users = append(users, user)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, end := observe(ctx, "users", "delete")
+	defer end()
+
+	return r.router.ExecAcross(ctx, "DELETE FROM users WHERE id = $1", id)
+}
+
+func (r *userRepository) ListWithPagination(ctx context.Context, filter UserListFilter, domainID uuid.UUID, page, limit int) (*UserListResult, error) {
+	ctx, end := observe(ctx, "users", "list_with_pagination")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	// Listings tolerate replica lag up to the client's consistency token
+	db = r.router.ForRead(ctx, db)
+
 	// Calculate offset
 	offset := (page - 1) * limit
 
-	// Build the query with search condition
-	baseQuery := "SELECT id, domain_id, role_id, first_name, last_name, username, email, password_hash, created_at, updated_at FROM users WHERE domain_id = $1"
+	// Build the query with filter conditions
+	baseQuery := "SELECT " + userColumns + " FROM users WHERE domain_id = $1"
 	countQuery := "SELECT COUNT(*) FROM users WHERE domain_id = $1"
-	args := []interface{}{domainID}
-	var whereClause string
-
-	if search != "" {
-		whereClause = " AND (username ILIKE $" + fmt.Sprintf("%d", len(args)+1) +
-			" OR email ILIKE $" + fmt.Sprintf("%d", len(args)+1) +
-			" OR first_name ILIKE $" + fmt.Sprintf("%d", len(args)+1) +
-			" OR last_name ILIKE $" + fmt.Sprintf("%d", len(args)+1) + ")"
-		args = append(args, "%"+search+"%")
-	}
+	whereClause, args := userFilterClause(r.router.Dialect(), filter, []interface{}{domainID})
 
 	// Get total count
 	var total int
-	err := r.db.QueryRow(countQuery+whereClause, args...).Scan(&total)
+	err = db.QueryRowContext(ctx, countQuery+whereClause, args...).Scan(&total)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get paginated results
-	query := baseQuery + whereClause + " ORDER BY username LIMIT $" + fmt.Sprintf("%d", len(args)+1) + " OFFSET $" + fmt.Sprintf("%d", len(args)+2)
+	query := baseQuery + whereClause + filter.Sort.orderBy(userSortColumns, "username") + " LIMIT $" + fmt.Sprintf("%d", len(args)+1) + " OFFSET $" + fmt.Sprintf("%d", len(args)+2)
 	args = append(args, limit, offset)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -164,13 +752,11 @@ func (r *userRepository) ListWithPagination(search string, domainID uuid.UUID, p
 
 	var users []*entities.User
 	for rows.Next() {
-		var user entities.User
-		err := rows.Scan(&user.ID, &user.DomainID, &user.RoleID, &user.FirstName, &user.LastName,
-			&user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, err
 		}
-		users = append(users, &user)
+		users = append(users, user)
 	}
 
 	// Calculate total pages
@@ -184,3 +770,206 @@ func (r *userRepository) ListWithPagination(search string, domainID uuid.UUID, p
 		TotalPages: totalPages,
 	}, nil
 }
+
+// ListAfter returns up to limit users of the domain created after the cursor (from the first when
+// after is nil), oldest first.
+func (r *userRepository) ListAfter(ctx context.Context, filter UserListFilter, domainID uuid.UUID, after *ListCursor, limit int) (*UserCursorPage, error) {
+	ctx, end := observe(ctx, "users", "list_after")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	// Listings tolerate replica lag up to the client's consistency token
+	db = r.router.ForRead(ctx, db)
+
+	whereClause, args := userFilterClause(r.router.Dialect(), filter, []interface{}{domainID})
+	if after != nil {
+		var condition string
+		condition, args = cursorCondition(after, "id", args)
+		whereClause += " AND " + condition
+	}
+
+	// Fetch one extra user to learn whether another page follows
+	query := "SELECT " + userColumns + " FROM users WHERE domain_id = $1" + whereClause +
+		" ORDER BY created_at, id LIMIT $" + fmt.Sprintf("%d", len(args)+1)
+	rows, err := db.QueryContext(ctx, query, append(args, limit+1)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &UserCursorPage{Users: users, Limit: limit}
+	if len(users) > limit {
+		page.Users = users[:limit]
+		last := page.Users[limit-1]
+		page.NextCursor = ListCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return page, nil
+}
+
+// userSearchText is what a user search matches against. It is the expression of the trigram index
+// idx_users_search_trgm, so a search term anywhere in it is looked up in the index instead of
+// scanning the domain's users; the two must stay identical.
+const userSearchText = "(username || ' ' || email || ' ' || first_name || ' ' || last_name)"
+
+// userFilterClause returns the conditions of the filter, and appends their arguments to args.
+func userFilterClause(d Dialect, filter UserListFilter, args []interface{}) (string, []interface{}) {
+	var clause string
+	if filter.Search != "" {
+		clause += " AND " + d.ILike(userSearchText, "$"+fmt.Sprintf("%d", len(args)+1))
+		args = append(args, "%"+escapeLike(filter.Search)+"%")
+	}
+	if filter.RoleID != uuid.Nil {
+		placeholder := "$" + fmt.Sprintf("%d", len(args)+1)
+		clause += " AND (role_id = " + placeholder + ` OR id IN (
+			SELECT gm.user_id FROM group_members gm JOIN group_roles gr ON gr.group_id = gm.group_id
+			WHERE gr.role_id = ` + placeholder + "))"
+		args = append(args, filter.RoleID)
+	}
+	for _, orgUnitID := range []uuid.UUID{filter.OrgUnitID, filter.DelegatedOrgUnitID} {
+		if orgUnitID != uuid.Nil {
+			clause += " AND org_unit_id IN (" + orgUnitSubtree("$"+fmt.Sprintf("%d", len(args)+1)) + ")"
+			args = append(args, orgUnitID)
+		}
+	}
+	switch filter.Status {
+	case UserStatusActive:
+		clause += " AND disabled_at IS NULL AND (valid_until IS NULL OR valid_until > NOW())"
+	case UserStatusDisabled:
+		clause += " AND disabled_at IS NOT NULL"
+	case UserStatusExpired:
+		clause += " AND disabled_at IS NULL AND valid_until <= NOW()"
+	case UserStatusPendingDeletion:
+		clause += " AND deletion_scheduled_at IS NOT NULL"
+	}
+	if filter.EmailDomain != "" {
+		clause += " AND " + d.ILike("email", "$"+fmt.Sprintf("%d", len(args)+1))
+		args = append(args, "%@"+escapeLike(filter.EmailDomain))
+	}
+	var rangeClause string
+	rangeClause, args = createdRangeClause(filter.CreatedAfter, filter.CreatedBefore, args)
+	return clause + rangeClause, args
+}
+
+// ListByRoleClaims returns users whose direct or group-inherited roles grant any of the given
+// claims, either as a top-level claim key, an entry in the role's "permissions" array, or an
+// assigned catalog permission.
+func (r *userRepository) ListByRoleClaims(ctx context.Context, domainID uuid.UUID, claims []string, page, limit int) (*UserListResult, error) {
+	ctx, end := observe(ctx, "users", "list_by_role_claims")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * limit
+
+	d := r.router.Dialect()
+	fromClause := `
+		FROM users u
+		WHERE u.domain_id = $1 AND EXISTS (
+			SELECT 1 FROM roles r
+			WHERE (r.id = u.role_id OR r.id IN (
+					SELECT gr.role_id FROM group_members gm JOIN group_roles gr ON gr.group_id = gm.group_id
+					WHERE gm.user_id = u.id))
+				AND (` + d.JSONHasAnyKey("r.role_claims", "$2") + ` OR ` + d.JSONHasAnyKey("r.role_claims->'permissions'", "$2") + `
+					OR EXISTS (
+						SELECT 1 FROM role_permissions rp JOIN permissions p ON p.id = rp.permission_id
+						WHERE rp.role_id = r.id AND ` + d.AnyOf("p.name", "$2") + `)))`
+
+	var total int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*)"+fromClause, domainID, pq.Array(claims)).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+userColumnsAs("u")+
+		fromClause+" ORDER BY u.username LIMIT $3 OFFSET $4", domainID, pq.Array(claims), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	totalPages := (total + limit - 1) / limit
+
+	return &UserListResult{
+		Users:      users,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func scanUser(row rowScanner) (*entities.User, error) {
+	var user entities.User
+	var externalID, lastLoginIP, clientSecretHash, avatarKey, avatarURL sql.NullString
+	var validUntil, disabledAt, sessionsRevokedAt, deletionScheduledAt, lastLoginAt sql.NullTime
+	var orgUnitID uuid.NullUUID
+	err := row.Scan(&user.ID, &user.DomainID, &user.RoleID, &externalID, &user.FirstName, &user.LastName,
+		&user.Username, &user.Email, &user.PasswordHash, &validUntil, &disabledAt, &sessionsRevokedAt,
+		&user.BreakGlass, &user.PasswordChangedAt, &deletionScheduledAt, &lastLoginAt, &lastLoginIP,
+		&user.Type, &clientSecretHash, &avatarKey, &avatarURL, &orgUnitID, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if externalID.Valid {
+		user.ExternalID = &externalID.String
+	}
+	if validUntil.Valid {
+		user.ValidUntil = &validUntil.Time
+	}
+	if disabledAt.Valid {
+		user.DisabledAt = &disabledAt.Time
+	}
+	if sessionsRevokedAt.Valid {
+		user.SessionsRevokedAt = &sessionsRevokedAt.Time
+	}
+	if deletionScheduledAt.Valid {
+		user.DeletionScheduledAt = &deletionScheduledAt.Time
+	}
+	if lastLoginAt.Valid {
+		user.LastLoginAt = &lastLoginAt.Time
+	}
+	if lastLoginIP.Valid {
+		user.LastLoginIP = &lastLoginIP.String
+	}
+	if clientSecretHash.Valid {
+		user.ClientSecretHash = &clientSecretHash.String
+	}
+	if avatarKey.Valid {
+		user.AvatarKey = &avatarKey.String
+	}
+	if avatarURL.Valid {
+		user.AvatarURL = &avatarURL.String
+	}
+	if orgUnitID.Valid {
+		user.OrgUnitID = &orgUnitID.UUID
+	}
+	return &user, nil
+}
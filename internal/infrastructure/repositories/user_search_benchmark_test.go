@@ -0,0 +1,106 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"backend/internal/domain/entities"
+)
+
+// ilikeUserSearch is the user search before the trigram index: ILIKE on each of the four columns,
+// which no index serves, so every user of the domain is scanned.
+const ilikeUserSearch = " AND (username ILIKE $2 OR email ILIKE $2 OR first_name ILIKE $2 OR last_name ILIKE $2)"
+
+// BenchmarkSearchUsers compares a user search through the trigram index of
+// 037_add_user_search_index.sql with the ILIKE search it replaced, in a domain of
+// TEST_SEARCH_USERS users (100000 by default):
+//
+//	TEST_DATABASE_URL=... go test -tags integration -run '^$' -bench SearchUsers ./internal/infrastructure/repositories/
+func BenchmarkSearchUsers(b *testing.B) {
+	if testDB == nil {
+		b.Skip("TEST_DATABASE_URL is not set")
+	}
+	if os.Getenv("TEST_DATABASE_URL") == "sqlite" {
+		b.Skip("the trigram index needs Postgres")
+	}
+	count := 100000
+	if value := os.Getenv("TEST_SEARCH_USERS"); value != "" {
+		var err error
+		if count, err = strconv.Atoi(value); err != nil {
+			b.Fatalf("TEST_SEARCH_USERS: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	router := NewShardRouter(testDB, nil, nil)
+	domain := &entities.Domain{Name: "Search benchmark", Domain: "search-benchmark.example.com"}
+	if err := NewDomainRepository(router).Create(ctx, domain); err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		_, _ = testDB.Exec("DELETE FROM users WHERE domain_id = $1", domain.DomainID)
+		_, _ = testDB.Exec("DELETE FROM roles WHERE domain_id = $1", domain.DomainID)
+		_, _ = testDB.Exec("DELETE FROM domains WHERE domain_id = $1", domain.DomainID)
+	})
+	role := &entities.Role{DomainID: domain.DomainID, RoleName: "member", RoleClaims: map[string]interface{}{}}
+	if err := NewRoleRepository(router).Create(ctx, role); err != nil {
+		b.Fatal(err)
+	}
+	if _, err := testDB.ExecContext(ctx, `
+		INSERT INTO users (id, domain_id, role_id, username, email, first_name, last_name, password_hash)
+		SELECT gen_random_uuid(), $1, $2,
+		       'user' || n || 'x' || substr(md5(n::text), 1, 6),
+		       'user' || n || '@' || (ARRAY['acme.com', 'example.org', 'corp.example.net'])[1 + n % 3],
+		       (ARRAY['Jane', 'John', 'Ayu', 'Budi', 'Maria', 'Wei', 'Omar', 'Sofia'])[1 + n % 8] || substr(md5(n::text), 7, 3),
+		       (ARRAY['Doe', 'Santoso', 'Garcia', 'Chen', 'Haddad', 'Rossi'])[1 + n % 6] || substr(md5(n::text), 10, 3),
+		       'hash'
+		FROM generate_series(1, $3::int) AS n`, domain.DomainID, role.ID, count); err != nil {
+		b.Fatal(err)
+	}
+	if _, err := testDB.ExecContext(ctx, "ANALYZE users"); err != nil {
+		b.Fatal(err)
+	}
+
+	term := "user" + strconv.Itoa(count/2) + "x"
+	users := NewUserRepository(router)
+	b.Run("trigram", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			result, err := users.ListWithPagination(ctx, UserListFilter{Search: term}, domain.DomainID, 1, 10)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if result.Total != 1 {
+				b.Fatalf("found %d users, want 1", result.Total)
+			}
+		}
+	})
+	b.Run("ilike", func(b *testing.B) {
+		pattern := "%" + term + "%"
+		for i := 0; i < b.N; i++ {
+			// The count and page queries of ListWithPagination, with the old condition
+			var total int
+			if err := testDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE domain_id = $1"+ilikeUserSearch,
+				domain.DomainID, pattern).Scan(&total); err != nil {
+				b.Fatal(err)
+			}
+			if total != 1 {
+				b.Fatalf("found %d users, want 1", total)
+			}
+			rows, err := testDB.QueryContext(ctx, "SELECT "+userColumns+" FROM users WHERE domain_id = $1"+ilikeUserSearch+
+				" ORDER BY username, id LIMIT 10 OFFSET 0", domain.DomainID, pattern)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for rows.Next() {
+				if _, err := scanUser(rows); err != nil {
+					b.Fatal(err)
+				}
+			}
+			rows.Close()
+		}
+	})
+}
@@ -0,0 +1,338 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"backend/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *entities.Webhook) error
+	GetByID(ctx context.Context, domainID, id uuid.UUID) (*entities.Webhook, error)
+	ListByDomain(ctx context.Context, domainID uuid.UUID) ([]*entities.Webhook, error)
+	ListEnabledDomainIDs(ctx context.Context) ([]uuid.UUID, error)
+	Update(ctx context.Context, webhook *entities.Webhook) error
+	SetSecret(ctx context.Context, domainID, id uuid.UUID, secret string) error
+	Delete(ctx context.Context, domainID, id uuid.UUID) error
+
+	ListDeliveries(ctx context.Context, domainID, webhookID uuid.UUID, status string, limit int) ([]*entities.WebhookDelivery, error)
+	Redeliver(ctx context.Context, domainID, webhookID, id uuid.UUID) error
+	ClaimDueDeliveries(ctx context.Context, lease time.Duration, limit int) ([]*DueWebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *entities.WebhookDelivery) error
+}
+
+// DueWebhookDelivery is a claimed delivery with the event and the endpoint it goes to.
+type DueWebhookDelivery struct {
+	Delivery *entities.WebhookDelivery
+	Event    *entities.Event
+	URL      string
+	Secret   string
+}
+
+type webhookRepository struct {
+	router *ShardRouter
+}
+
+func NewWebhookRepository(router *ShardRouter) WebhookRepository {
+	return &webhookRepository{router: router}
+}
+
+const (
+	webhookColumns         = "id, domain_id, url, secret, events, enabled, created_at, updated_at"
+	webhookDeliveryColumns = "id, webhook_id, domain_id, event_id, event_type, status, attempts, next_attempt_at, last_attempt_at, response_status, error, created_at"
+)
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *entities.Webhook) error {
+	ctx, end := observe(ctx, "webhooks", "create")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, webhook.DomainID)
+	if err != nil {
+		return err
+	}
+
+	webhook.ID = uuid.New()
+	return db.QueryRowContext(ctx, `
+		INSERT INTO webhooks (id, domain_id, url, secret, events, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING created_at, updated_at`,
+		webhook.ID, webhook.DomainID, webhook.URL, webhook.Secret, pq.Array(webhook.Events), webhook.Enabled).Scan(&webhook.CreatedAt, &webhook.UpdatedAt)
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, domainID, id uuid.UUID) (*entities.Webhook, error) {
+	ctx, end := observe(ctx, "webhooks", "get_by_id")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhook(db.QueryRowContext(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE id = $1 AND domain_id = $2", id, domainID))
+}
+
+// ListByDomain returns the domain's webhooks, oldest first.
+func (r *webhookRepository) ListByDomain(ctx context.Context, domainID uuid.UUID) ([]*entities.Webhook, error) {
+	ctx, end := observe(ctx, "webhooks", "list_by_domain")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE domain_id = $1 ORDER BY created_at", domainID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*entities.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// ListEnabledDomainIDs returns the domains with at least one enabled webhook, from every database.
+func (r *webhookRepository) ListEnabledDomainIDs(ctx context.Context) ([]uuid.UUID, error) {
+	ctx, end := observe(ctx, "webhooks", "list_enabled_domain_ids")
+	defer end()
+
+	var domainIDs []uuid.UUID
+	for _, db := range r.router.All() {
+		rows, err := db.QueryContext(ctx, "SELECT DISTINCT domain_id FROM webhooks WHERE enabled")
+		if err != nil {
+			return nil, err
+		}
+		ids, err := scanIDs(rows)
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		domainIDs = append(domainIDs, ids...)
+	}
+	return domainIDs, nil
+}
+
+// Update saves the URL, event filter and enabled flag; the secret only changes through SetSecret.
+func (r *webhookRepository) Update(ctx context.Context, webhook *entities.Webhook) error {
+	ctx, end := observe(ctx, "webhooks", "update")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, webhook.DomainID)
+	if err != nil {
+		return err
+	}
+	return db.QueryRowContext(ctx, `
+		UPDATE webhooks SET url = $1, events = $2, enabled = $3, updated_at = NOW()
+		WHERE id = $4 AND domain_id = $5 RETURNING updated_at`,
+		webhook.URL, pq.Array(webhook.Events), webhook.Enabled, webhook.ID, webhook.DomainID).Scan(&webhook.UpdatedAt)
+}
+
+func (r *webhookRepository) SetSecret(ctx context.Context, domainID, id uuid.UUID, secret string) error {
+	ctx, end := observe(ctx, "webhooks", "set_secret")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	return execExpectingRow(ctx, db, "UPDATE webhooks SET secret = $1, updated_at = NOW() WHERE id = $2 AND domain_id = $3", secret, id, domainID)
+}
+
+// Delete removes the webhook together with its delivery log.
+func (r *webhookRepository) Delete(ctx context.Context, domainID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "webhooks", "delete")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	return execExpectingRow(ctx, db, "DELETE FROM webhooks WHERE id = $1 AND domain_id = $2", id, domainID)
+}
+
+// ListDeliveries returns up to limit deliveries of the webhook, newest first; an empty status
+// lists all of them.
+func (r *webhookRepository) ListDeliveries(ctx context.Context, domainID, webhookID uuid.UUID, status string, limit int) ([]*entities.WebhookDelivery, error) {
+	ctx, end := observe(ctx, "webhook_deliveries", "list")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, "SELECT "+webhookDeliveryColumns+` FROM webhook_deliveries
+		WHERE domain_id = $1 AND webhook_id = $2 AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC LIMIT $4`, domainID, webhookID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*entities.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// Redeliver queues the delivery again with a fresh set of attempts.
+func (r *webhookRepository) Redeliver(ctx context.Context, domainID, webhookID, id uuid.UUID) error {
+	ctx, end := observe(ctx, "webhook_deliveries", "redeliver")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, domainID)
+	if err != nil {
+		return err
+	}
+	return execExpectingRow(ctx, db, `
+		UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1 AND webhook_id = $2 AND domain_id = $3`, id, webhookID, domainID)
+}
+
+// ClaimDueDeliveries picks up to limit pending deliveries of enabled webhooks from each database
+// and holds them for lease, so other instances skip them while they are being sent.
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, lease time.Duration, limit int) ([]*DueWebhookDelivery, error) {
+	ctx, end := observe(ctx, "webhook_deliveries", "claim_due")
+	defer end()
+
+	d := r.router.Dialect()
+	var due []*DueWebhookDelivery
+	for _, db := range r.router.All() {
+		rows, err := db.QueryContext(ctx, `
+			UPDATE webhook_deliveries SET next_attempt_at = `+d.SecondsFromNow("$1")+`
+			WHERE id IN (
+				SELECT d.id FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+				WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.enabled
+				ORDER BY d.next_attempt_at LIMIT $2`+d.SkipLocked("d")+`)
+			RETURNING id`,
+			lease.Seconds(), limit)
+		if err != nil {
+			return nil, err
+		}
+		claimed, err := scanIDs(rows)
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(claimed) == 0 {
+			continue
+		}
+		// The deliveries are held for lease, so no other instance changes them before they are read
+		rows, err = db.QueryContext(ctx, `
+			SELECT c.id, c.webhook_id, c.domain_id, c.event_id, c.event_type, c.status, c.attempts, c.next_attempt_at,
+				c.last_attempt_at, c.response_status, c.error, c.created_at,
+				e.sequence, e.subject_id, e.payload, e.impersonator_id, e.created_at, w.url, w.secret
+			FROM webhook_deliveries c JOIN webhooks w ON w.id = c.webhook_id JOIN events e ON e.id = c.event_id
+			WHERE `+d.AnyOf("c.id", d.Cast("$1", "uuid[]")),
+			pq.Array(uuidStrings(claimed)))
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			item, err := scanDueWebhookDelivery(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+			due = append(due, item)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return due, nil
+}
+
+// RecordAttempt stores the outcome of a delivery attempt and when, if at all, to try again.
+func (r *webhookRepository) RecordAttempt(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	ctx, end := observe(ctx, "webhook_deliveries", "record_attempt")
+	defer end()
+
+	ctx, db, err := r.router.ForTenant(ctx, delivery.DomainID)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3, last_attempt_at = $4,
+			response_status = $5, error = $6
+		WHERE id = $7 AND domain_id = $8`,
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastAttemptAt, delivery.ResponseStatus,
+		delivery.Error, delivery.ID, delivery.DomainID)
+	return err
+}
+
+func scanWebhook(row rowScanner) (*entities.Webhook, error) {
+	var webhook entities.Webhook
+	err := row.Scan(&webhook.ID, &webhook.DomainID, &webhook.URL, &webhook.Secret, pq.Array(&webhook.Events),
+		&webhook.Enabled, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+	return &webhook, nil
+}
+
+func scanWebhookDelivery(row rowScanner, extra ...interface{}) (*entities.WebhookDelivery, error) {
+	var delivery entities.WebhookDelivery
+	var nextAttemptAt, lastAttemptAt sql.NullTime
+	var responseStatus sql.NullInt64
+	var deliveryErr sql.NullString
+	dest := append([]interface{}{&delivery.ID, &delivery.WebhookID, &delivery.DomainID, &delivery.EventID, &delivery.EventType,
+		&delivery.Status, &delivery.Attempts, &nextAttemptAt, &lastAttemptAt, &responseStatus, &deliveryErr,
+		&delivery.CreatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if nextAttemptAt.Valid {
+		delivery.NextAttemptAt = &nextAttemptAt.Time
+	}
+	if lastAttemptAt.Valid {
+		delivery.LastAttemptAt = &lastAttemptAt.Time
+	}
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		delivery.ResponseStatus = &status
+	}
+	if deliveryErr.Valid {
+		delivery.Error = &deliveryErr.String
+	}
+	return &delivery, nil
+}
+
+func scanDueWebhookDelivery(row rowScanner) (*DueWebhookDelivery, error) {
+	var event entities.Event
+	var subjectID, impersonatorID uuid.NullUUID
+	var payload []byte
+	item := &DueWebhookDelivery{Event: &event}
+	delivery, err := scanWebhookDelivery(row, &event.Sequence, &subjectID, &payload, &impersonatorID, &event.CreatedAt, &item.URL, &item.Secret)
+	if err != nil {
+		return nil, err
+	}
+	event.ID, event.DomainID, event.Type = delivery.EventID, delivery.DomainID, delivery.EventType
+	if subjectID.Valid {
+		event.SubjectID = &subjectID.UUID
+	}
+	if impersonatorID.Valid {
+		event.ImpersonatorID = &impersonatorID.UUID
+	}
+	event.Payload = payload
+	item.Delivery = delivery
+	return item, nil
+}
@@ -0,0 +1,69 @@
+package reputation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FailedLoginTracker scores IPs from their recent failed logins. State is kept in process
+// memory, so each instance only sees the failures it handled.
+type FailedLoginTracker struct {
+	mu       sync.Mutex
+	window   time.Duration
+	weight   int
+	failures map[string][]time.Time
+}
+
+func NewFailedLoginTracker(window time.Duration, weight int) *FailedLoginTracker {
+	return &FailedLoginTracker{
+		window:   window,
+		weight:   weight,
+		failures: make(map[string][]time.Time),
+	}
+}
+
+func (t *FailedLoginTracker) RecordFailure(ip string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.failures[ip] = append(t.prune(ip, now), now)
+}
+
+// RecordSuccess forgets earlier failures so a user who eventually types the right password
+// isn't penalised on the next attempt.
+func (t *FailedLoginTracker) RecordSuccess(ip string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.failures, ip)
+}
+
+func (t *FailedLoginTracker) Score(_ context.Context, ip string) (int, error) {
+	return clamp(t.Failures(ip) * t.weight), nil
+}
+
+// Failures counts the IP's failed logins within the window.
+func (t *FailedLoginTracker) Failures(ip string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	recent := t.prune(ip, time.Now())
+	if len(recent) == 0 {
+		delete(t.failures, ip)
+	} else {
+		t.failures[ip] = recent
+	}
+	return len(recent)
+}
+
+func (t *FailedLoginTracker) prune(ip string, now time.Time) []time.Time {
+	cutoff := now.Add(-t.window)
+	attempts := t.failures[ip]
+	i := 0
+	for i < len(attempts) && attempts[i].Before(cutoff) {
+		i++
+	}
+	return attempts[i:]
+}
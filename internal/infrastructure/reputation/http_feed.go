@@ -0,0 +1,72 @@
+package reputation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// HTTPFeed queries an external reputation service with GET <url>?ip=<addr> and expects a
+// JSON body of the form {"score": 0-100}. Results are cached to keep login latency low.
+type HTTPFeed struct {
+	url      string
+	client   *http.Client
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedScore
+}
+
+type cachedScore struct {
+	score     int
+	expiresAt time.Time
+}
+
+func NewHTTPFeed(feedURL string, timeout, cacheTTL time.Duration) *HTTPFeed {
+	return &HTTPFeed{
+		url:      feedURL,
+		client:   &http.Client{Timeout: timeout},
+		cacheTTL: cacheTTL,
+		cache:    make(map[string]cachedScore),
+	}
+}
+
+func (f *HTTPFeed) Score(ctx context.Context, ip string) (int, error) {
+	f.mu.Lock()
+	cached, ok := f.cache[ip]
+	f.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.score, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url+"?ip="+url.QueryEscape(ip), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("reputation feed unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("reputation feed returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Score int `json:"score"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("invalid reputation feed response: %w", err)
+	}
+
+	score := clamp(body.Score)
+	f.mu.Lock()
+	f.cache[ip] = cachedScore{score: score, expiresAt: time.Now().Add(f.cacheTTL)}
+	f.mu.Unlock()
+	return score, nil
+}
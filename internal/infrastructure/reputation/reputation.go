@@ -0,0 +1,40 @@
+package reputation
+
+import "context"
+
+// Provider scores how risky an IP address is, from 0 (clean) to 100 (known bad).
+type Provider interface {
+	Score(ctx context.Context, ip string) (int, error)
+}
+
+// Combined reports the highest score among its providers. Providers that fail are skipped
+// so an unavailable feed never blocks logins on its own.
+type Combined []Provider
+
+func (c Combined) Score(ctx context.Context, ip string) (int, error) {
+	highest := 0
+	var firstErr error
+	for _, provider := range c {
+		score, err := provider.Score(ctx, ip)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if score > highest {
+			highest = score
+		}
+	}
+	return highest, firstErr
+}
+
+func clamp(score int) int {
+	if score < 0 {
+		return 0
+	}
+	if score > 100 {
+		return 100
+	}
+	return score
+}
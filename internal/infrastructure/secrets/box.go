@@ -0,0 +1,82 @@
+// Package secrets encrypts credentials stored in the database, such as the SMTP passwords of
+// domains' own mail senders, with AES-256-GCM under a key from the configuration.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// sealedPrefix marks sealed values, so values stored before encryption was set up can be told
+// apart and sealed later.
+const sealedPrefix = "aesgcm:v1:"
+
+// ErrNoKey is returned when a secret must be sealed or opened without a key configured.
+var ErrNoKey = errors.New("no encryption key configured")
+
+// Box seals and opens secrets with one key.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// NewBox returns a box for a 32-byte key.
+func NewBox(key []byte) (*Box, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Box{aead: aead}, nil
+}
+
+// Seal encrypts the secret. The same context, such as the ID of the row holding the secret, must
+// be given to Open, so a sealed value copied to another row doesn't open. A nil box returns
+// ErrNoKey.
+func (b *Box) Seal(secret string, context []byte) (string, error) {
+	if b == nil {
+		return "", ErrNoKey
+	}
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(secret), context)
+	return sealedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value returned by Seal. Values stored before encryption was set up aren't
+// sealed and are returned as they are; a nil box returns ErrNoKey for sealed values.
+func (b *Box) Open(value string, context []byte) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+	if b == nil {
+		return "", ErrNoKey
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, sealedPrefix))
+	if err != nil || len(sealed) < b.aead.NonceSize() {
+		return "", errors.New("sealed secret is malformed")
+	}
+	nonce, ciphertext := sealed[:b.aead.NonceSize()], sealed[b.aead.NonceSize():]
+	secret, err := b.aead.Open(nil, nonce, ciphertext, context)
+	if err != nil {
+		return "", errors.New("sealed secret doesn't open with the configured key")
+	}
+	return string(secret), nil
+}
+
+// IsSealed reports whether the value was returned by Seal.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
@@ -0,0 +1,51 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBox(t *testing.T) {
+	box, err := NewBox(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := box.Seal("smtp-password", []byte("domain-a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sealed, "smtp-password") || !IsSealed(sealed) {
+		t.Fatalf("Seal = %q", sealed)
+	}
+	if again, _ := box.Seal("smtp-password", []byte("domain-a")); again == sealed {
+		t.Error("sealing twice gives the same value")
+	}
+	if secret, err := box.Open(sealed, []byte("domain-a")); err != nil || secret != "smtp-password" {
+		t.Errorf("Open = %q, %v", secret, err)
+	}
+	if _, err := box.Open(sealed, []byte("domain-b")); err == nil {
+		t.Error("value sealed for one context opens for another")
+	}
+
+	other, _ := NewBox(bytes.Repeat([]byte{2}, 32))
+	if _, err := other.Open(sealed, []byte("domain-a")); err == nil {
+		t.Error("value opens with another key")
+	}
+	if secret, err := box.Open("legacy", nil); err != nil || secret != "legacy" {
+		t.Errorf("Open of an unsealed value = %q, %v", secret, err)
+	}
+
+	var none *Box
+	if _, err := none.Seal("x", nil); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Seal without a key = %v, want ErrNoKey", err)
+	}
+	if _, err := none.Open(sealed, nil); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Open without a key = %v, want ErrNoKey", err)
+	}
+	if _, err := NewBox([]byte("short")); err == nil {
+		t.Error("NewBox accepted a short key")
+	}
+}
@@ -0,0 +1,208 @@
+// Package signing holds the key access tokens are signed with: a shared HS256 secret, or an
+// RS256 or ES256 private key whose public half is published as a JWKS.
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key signs and verifies tokens with one algorithm.
+type Key struct {
+	// ID is the kid header of signed tokens: the RFC 7638 thumbprint of the public key, or a
+	// fingerprint of the secret for HS256
+	ID     string
+	method jwt.SigningMethod
+	sign   interface{}
+	verify interface{}
+}
+
+// NewHMACKey returns an HS256 key. Every service validating tokens needs the secret.
+func NewHMACKey(secret []byte) *Key {
+	sum := sha256.Sum256(secret)
+	return &Key{ID: hex.EncodeToString(sum[:8]), method: jwt.SigningMethodHS256, sign: secret, verify: secret}
+}
+
+// ParsePrivateKey reads a PEM encoded RSA key (PKCS#1 or PKCS#8) for RS256 or a P-256 EC key
+// (SEC 1 or PKCS#8) for ES256.
+func ParsePrivateKey(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in private key")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q in private key", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		if private.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits")
+		}
+		return newAsymmetricKey(jwt.SigningMethodRS256, private, &private.PublicKey)
+	case *ecdsa.PrivateKey:
+		if private.Curve != elliptic.P256() {
+			return nil, errors.New("EC keys must use the P-256 curve for ES256")
+		}
+		return newAsymmetricKey(jwt.SigningMethodES256, private, &private.PublicKey)
+	}
+	return nil, fmt.Errorf("unsupported private key type %T", parsed)
+}
+
+// ParseVerificationKey reads a PEM encoded private key, or a public key ("PUBLIC KEY" block), that
+// tokens are verified but no longer signed with.
+func ParseVerificationKey(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return ParsePrivateKey(data)
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	switch public := parsed.(type) {
+	case *rsa.PublicKey:
+		return newAsymmetricKey(jwt.SigningMethodRS256, nil, public)
+	case *ecdsa.PublicKey:
+		if public.Curve != elliptic.P256() {
+			return nil, errors.New("EC keys must use the P-256 curve for ES256")
+		}
+		return newAsymmetricKey(jwt.SigningMethodES256, nil, public)
+	}
+	return nil, fmt.Errorf("unsupported public key type %T", parsed)
+}
+
+func newAsymmetricKey(method jwt.SigningMethod, private crypto.Signer, public crypto.PublicKey) (*Key, error) {
+	key := &Key{method: method, verify: public}
+	if private != nil {
+		key.sign = private
+	}
+	thumbprint, err := key.thumbprint()
+	if err != nil {
+		return nil, err
+	}
+	key.ID = thumbprint
+	return key, nil
+}
+
+// Algorithm is the JWS alg of tokens signed with the key.
+func (k *Key) Algorithm() string {
+	return k.method.Alg()
+}
+
+// Sign returns the signed token with the key ID in its kid header.
+func (k *Key) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = k.ID
+	return token.SignedString(k.sign)
+}
+
+// Keyfunc verifies tokens for jwt.Parse, rejecting any algorithm but the key's own.
+func (k *Key) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return k.verify, nil
+}
+
+// JWK is a public key in JSON Web Key form (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty" enums:"RSA,EC" example:"RSA"`
+	Use string `json:"use" example:"sig"`
+	Alg string `json:"alg" enums:"RS256,ES256" example:"RS256"`
+	Kid string `json:"kid" example:"NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"`
+	// RSA modulus and exponent
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty" example:"AQAB"`
+	// EC curve and coordinates
+	Crv string `json:"crv,omitempty" example:"P-256"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicJWK returns the public key, or false for HS256 keys, which have none to publish.
+func (k *Key) PublicJWK() (JWK, bool) {
+	switch public := k.verify.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA", Use: "sig", Alg: k.Algorithm(), Kid: k.ID,
+			N: encode(public.N.Bytes()),
+			E: encode(big.NewInt(int64(public.E)).Bytes()),
+		}, true
+	case *ecdsa.PublicKey:
+		size := (public.Curve.Params().BitSize + 7) / 8
+		return JWK{
+			Kty: "EC", Use: "sig", Alg: k.Algorithm(), Kid: k.ID, Crv: public.Curve.Params().Name,
+			X: encode(public.X.FillBytes(make([]byte, size))),
+			Y: encode(public.Y.FillBytes(make([]byte, size))),
+		}, true
+	}
+	return JWK{}, false
+}
+
+// thumbprint is the RFC 7638 thumbprint of the public key: the SHA-256 of its required members
+// in lexicographic order.
+func (k *Key) thumbprint() (string, error) {
+	jwk, ok := k.PublicJWK()
+	if !ok {
+		return "", errors.New("key has no public part")
+	}
+
+	var members interface{}
+	if jwk.Kty == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return encode(sum[:]), nil
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,62 @@
+package signing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeySet is the key new tokens are signed with, plus earlier keys whose tokens are still
+// accepted, so the signing key can be replaced without invalidating tokens already issued.
+type KeySet struct {
+	Signing  *Key
+	previous []*Key
+}
+
+func NewKeySet(signing *Key, previous ...*Key) *KeySet {
+	set := &KeySet{Signing: signing}
+	for _, key := range previous {
+		if key.ID != signing.ID {
+			set.previous = append(set.previous, key)
+		}
+	}
+	return set
+}
+
+// Sign signs claims with the signing key.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if s.Signing.sign == nil {
+		return "", errors.New("signing key has no private part")
+	}
+	return s.Signing.Sign(claims)
+}
+
+// Keyfunc picks the key by the token's kid header for jwt.Parse. Tokens without a kid, issued
+// before keys had IDs, are checked against the signing key.
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" || kid == s.Signing.ID {
+		return s.Signing.Keyfunc(token)
+	}
+	for _, key := range s.previous {
+		if key.ID == kid {
+			return key.Keyfunc(token)
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// JWKS returns the public keys of the set, signing key first. HS256 keys are never published.
+func (s *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	if jwk, ok := s.Signing.PublicJWK(); ok {
+		set.Keys = append(set.Keys, jwk)
+	}
+	for _, key := range s.previous {
+		if jwk, ok := key.PublicJWK(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
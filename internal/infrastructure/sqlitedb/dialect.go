@@ -0,0 +1,64 @@
+package sqlitedb
+
+// Dialect writes the constructs of statements SQLite spells differently from Postgres, for the
+// repositories (see repositories.Dialect). Arguments and results are SQL expressions. Operators
+// on arrays and JSONB documents become the functions registerSQLiteFunctions adds.
+type Dialect struct{}
+
+// Cast leaves expr as it is: SQLite values carry their own type.
+func (Dialect) Cast(expr, pgType string) string {
+	return expr
+}
+
+func (Dialect) SecondsFromNow(seconds string) string {
+	return "seconds_from_now(" + seconds + ")"
+}
+
+// ILike calls ilike, since SQLite's LIKE only ignores the case of ASCII letters and has no escape
+// character unless told.
+func (Dialect) ILike(expr, pattern string) string {
+	return "ilike(" + expr + ", " + pattern + ")"
+}
+
+func (Dialect) AnyOf(value, array string) string {
+	return "array_contains(" + array + ", " + value + ")"
+}
+
+func (Dialect) Overlaps(a, b string) string {
+	return "array_overlaps(" + a + ", " + b + ")"
+}
+
+func (Dialect) JSONHasKey(doc, key string) string {
+	return "jsonb_exists(" + doc + ", " + key + ")"
+}
+
+func (Dialect) JSONHasAnyKey(doc, keys string) string {
+	return "jsonb_exists_any(" + doc + ", " + keys + ")"
+}
+
+func (Dialect) JSONContains(doc, value string) string {
+	return "jsonb_contains(" + doc + ", " + value + ")"
+}
+
+func (Dialect) RowID() string {
+	return "rowid"
+}
+
+func (Dialect) RowsIn(subquery string) string {
+	return "rowid IN (" + subquery + ")"
+}
+
+// SkipLocked locks nothing: SQLite runs one write transaction at a time, and Open begins every
+// transaction as a writer, so there are no rows locked by others to skip.
+func (Dialect) SkipLocked(of ...string) string {
+	return ""
+}
+
+func (Dialect) TableExists(name string) string {
+	return "EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = " + name + ")"
+}
+
+// ReadOnly is false: a SQLite database is never a standby.
+func (Dialect) ReadOnly() string {
+	return "false"
+}
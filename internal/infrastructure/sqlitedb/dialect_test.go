@@ -0,0 +1,86 @@
+package sqlitedb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestDialect(t *testing.T) {
+	db := openTestDB(t)
+	var d Dialect
+	tests := []struct {
+		name, condition string
+		args            []any
+		want            bool
+	}{
+		{"ilike", d.ILike("$1", "$2"), []any{"Jane Doe", "%doe"}, true},
+		{"ilike without a match", d.ILike("$1", "$2"), []any{"Jane Doe", "doe"}, false},
+		{"any of", d.AnyOf("$1", d.Cast("$2", "text[]")), []any{"b", pq.Array([]string{"a", "b"})}, true},
+		{"any of without a match", d.AnyOf("$1", "$2"), []any{"c", pq.Array([]string{"a", "b"})}, false},
+		{"overlaps", d.Overlaps("$1", "$2"), []any{pq.Array([]string{"a", "b"}), pq.Array([]string{"b", "c"})}, true},
+		{"disjoint", d.Overlaps("$1", "$2"), []any{pq.Array([]string{"a"}), pq.Array([]string{"c"})}, false},
+		{"key", d.JSONHasKey("$1", "$2"), []any{`{"admin": true}`, "admin"}, true},
+		{"string of an array", d.JSONHasKey("$1", "$2"), []any{`["users:read"]`, "users:read"}, true},
+		{"any key", d.JSONHasAnyKey("$1", "$2"), []any{`{"admin": true}`, pq.Array([]string{"owner", "admin"})}, true},
+		{"no key", d.JSONHasAnyKey("$1", "$2"), []any{`{"admin": true}`, pq.Array([]string{"owner"})}, false},
+		{"contains", d.JSONContains("$1", d.Cast("$2", "jsonb")), []any{`{"permissions": ["a", "b"], "level": 2}`, `{"permissions": ["b"]}`}, true},
+		{"seconds from now", d.SecondsFromNow("$1") + " > $2", []any{60, time.Now().Add(59 * time.Second)}, true},
+		{"table exists", d.TableExists("'users'"), nil, true},
+		{"table is missing", d.TableExists("'missing'"), nil, false},
+		{"read only", d.ReadOnly(), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			if err := db.QueryRow("SELECT "+tt.condition, tt.args...).Scan(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("%s = %v, want %v", tt.condition, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialectRowsIn(t *testing.T) {
+	db := openTestDB(t)
+	var d Dialect
+	for _, kind := range []string{"a", "b", "c"} {
+		if _, err := db.Exec("INSERT INTO jobs (kind, status, max_attempts) VALUES ($1, 'pending', 3)", kind); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := db.Exec("DELETE FROM jobs WHERE "+d.RowsIn("SELECT "+d.RowID()+" FROM jobs WHERE kind <> $1 LIMIT $2"+d.SkipLocked()), "a", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted, _ := result.RowsAffected(); deleted != 1 {
+		t.Errorf("deleted %d jobs, want 1", deleted)
+	}
+}
+
+func TestILike(t *testing.T) {
+	tests := []struct {
+		value, pattern string
+		want           bool
+	}{
+		{"Jane Doe", "jane doe", true},
+		{"Jane Doe", "%DOE", true},
+		{"Jane Doe", "j_ne%", true},
+		{"Jane Doe", "jane", false},
+		{"ÉLODIE", "élodie", true},
+		{"100% sure", `100\%%`, true},
+		{"1000 sure", `100\%%`, false},
+		{"a_b", `a\_b`, true},
+		{"axb", `a\_b`, false},
+		{"", "%", true},
+		{"", "_", false},
+	}
+	for _, tt := range tests {
+		if got := ilike(tt.value, tt.pattern); got != tt.want {
+			t.Errorf("ilike(%q, %q) = %v, want %v", tt.value, tt.pattern, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,598 @@
+// Package sqlitedb opens SQLite databases for local development and tests without a Postgres
+// server (DB_DRIVER=sqlite). The repositories write the few constructs the two databases spell
+// differently through Dialect; everything else they run is SQL both understand.
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Memory as the path opens a new in-memory database.
+const Memory = ":memory:"
+
+// sqliteTimeFormat is how timestamps are stored and bound: in UTC with microseconds, as Postgres
+// keeps them, and of fixed width so comparing them as text compares them in time order.
+const sqliteTimeFormat = "2006-01-02 15:04:05.000000-07:00"
+
+var (
+	registerSQLiteFunctionsOnce sync.Once
+	// sqliteBaseDriver is the driver the sqlite package registers, which has the functions
+	sqliteBaseDriver driver.Driver
+	// sqliteMemoryDBs numbers the in-memory databases so every pool gets its own
+	sqliteMemoryDBs atomic.Int64
+)
+
+// sqliteDriver wraps the pure-Go SQLite driver so arguments are bound the way the schema stores
+// them, timestamps come back as time.Time and unique violations as *UniqueViolation.
+type sqliteDriver struct {
+	driver.Driver
+}
+
+func (d *sqliteDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteConn{Conn: conn}, nil
+}
+
+// sqliteConnector opens the connections of one pool. For an in-memory database it holds a
+// connection of its own until the pool is closed, since SQLite drops the database with its last
+// connection and database/sql closes idle ones.
+type sqliteConnector struct {
+	driver *sqliteDriver
+	dsn    string
+	keep   driver.Conn
+}
+
+func (c *sqliteConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *sqliteConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// Close is called by sql.DB.Close.
+func (c *sqliteConnector) Close() error {
+	if c.keep == nil {
+		return nil
+	}
+	return c.keep.Close()
+}
+
+type sqliteConn struct {
+	driver.Conn
+}
+
+// CheckNamedValue binds time.Time in sqliteTimeFormat, and []byte, which the repositories pass
+// for JSONB, as text: SQLite's JSON functions read a blob as its binary JSONB format. A nil []byte
+// is NULL, as lib/pq binds it.
+func (c *sqliteConn) CheckNamedValue(nv *driver.NamedValue) error {
+	value, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	nv.Value = sqliteValue(value)
+	return nil
+}
+
+func sqliteValue(value driver.Value) driver.Value {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(sqliteTimeFormat)
+	case []byte:
+		if v == nil {
+			return nil
+		}
+		return string(v)
+	}
+	return value
+}
+
+func (c *sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, c.uniqueViolation(err)
+	}
+	return sqliteRows{Rows: rows, conn: c}, nil
+}
+
+func (c *sqliteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result, err := c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+	return result, c.uniqueViolation(err)
+}
+
+func (c *sqliteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteStmt{Stmt: stmt, conn: c}, nil
+}
+
+// UniqueViolation is the error of a write a unique constraint or index rejected. Constraint is
+// named as Postgres names it: the name of a CREATE UNIQUE INDEX, or table_pkey and
+// table_columns_key for primary keys and UNIQUE constraints, which the schema leaves unnamed.
+type UniqueViolation struct {
+	Table      string
+	Constraint string
+	err        error
+}
+
+func (e *UniqueViolation) Error() string { return e.err.Error() }
+
+func (e *UniqueViolation) Unwrap() error { return e.err }
+
+// uniqueViolation returns SQLite's unique constraint errors as *UniqueViolation and other errors
+// unchanged.
+func (c *sqliteConn) uniqueViolation(err error) error {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) ||
+		sqliteErr.Code() != sqlite3.SQLITE_CONSTRAINT_UNIQUE && sqliteErr.Code() != sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY {
+		return err
+	}
+	// e.g. "constraint failed: UNIQUE constraint failed: users.domain_id, users.username (2067)"
+	message := sqliteErr.Error()
+	failed, _, _ := strings.Cut(message[strings.LastIndex(message, "failed: ")+len("failed: "):], " (")
+	var table string
+	var columns []string
+	for _, column := range strings.Split(failed, ", ") {
+		var name string
+		table, name, _ = strings.Cut(column, ".")
+		columns = append(columns, name)
+	}
+	constraint := table + "_" + strings.Join(columns, "_") + "_key"
+	if indexes, err := uniqueIndexes(context.Background(), c.Conn.(driver.QueryerContext), table); err == nil {
+		if name, ok := indexes[strings.Join(columns, ",")]; ok {
+			constraint = name
+		}
+	}
+	return &UniqueViolation{Table: table, Constraint: constraint, err: err}
+}
+
+// uniqueIndexes returns the Postgres names of the unique indexes of table by their
+// comma-separated columns: that of CREATE UNIQUE INDEX, or the one Postgres gives a primary key
+// or UNIQUE constraint.
+func uniqueIndexes(ctx context.Context, conn driver.QueryerContext, table string) (map[string]string, error) {
+	indexes, err := readSQLiteRows(conn.QueryContext(ctx, `
+		SELECT l.name, l.origin, (SELECT group_concat(name, ',') FROM (SELECT name FROM pragma_index_info(l.name) ORDER BY seqno))
+		FROM pragma_index_list($1) l WHERE l."unique"`, []driver.NamedValue{{Ordinal: 1, Value: table}}))
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(indexes.values))
+	for _, index := range indexes.values {
+		columns := fmt.Sprint(index[2])
+		switch index[1] {
+		case "pk":
+			names[columns] = table + "_pkey"
+		case "u":
+			names[columns] = table + "_" + strings.ReplaceAll(columns, ",", "_") + "_key"
+		default:
+			names[columns] = fmt.Sprint(index[0])
+		}
+	}
+	return names, nil
+}
+
+func (c *sqliteConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (c *sqliteConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *sqliteConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *sqliteConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// sqliteStmt returns the rows of a prepared statement as sqliteRows.
+type sqliteStmt struct {
+	driver.Stmt
+	conn *sqliteConn
+}
+
+func (s *sqliteStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	result, err := s.Stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+	return result, s.conn.uniqueViolation(err)
+}
+
+func (s *sqliteStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := s.Stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+	if err != nil {
+		return nil, s.conn.uniqueViolation(err)
+	}
+	return sqliteRows{Rows: rows, conn: s.conn}, nil
+}
+
+// sqliteRows returns the timestamps SQLite computes, such as MAX(created_at), as time.Time, as
+// the driver already does for columns declared TIMESTAMP.
+type sqliteRows struct {
+	driver.Rows
+	conn *sqliteConn
+}
+
+func (r sqliteRows) Next(dest []driver.Value) error {
+	if err := r.Rows.Next(dest); err != nil {
+		return r.conn.uniqueViolation(err)
+	}
+	for i, value := range dest {
+		if s, ok := value.(string); ok && len(s) == len(sqliteTimeFormat) {
+			if t, err := time.Parse(sqliteTimeFormat, s); err == nil {
+				dest[i] = t
+			}
+		}
+	}
+	return nil
+}
+
+// sqliteResultRows are rows read into memory by readSQLiteRows.
+type sqliteResultRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *sqliteResultRows) Columns() []string { return r.columns }
+
+func (r *sqliteResultRows) Close() error { return nil }
+
+func (r *sqliteResultRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// readSQLiteRows reads and closes rows.
+func readSQLiteRows(rows driver.Rows, err error) (*sqliteResultRows, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := &sqliteResultRows{columns: rows.Columns()}
+	for {
+		row := make([]driver.Value, len(result.columns))
+		if err := rows.Next(row); err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return nil, err
+		}
+		for i, value := range row {
+			if b, ok := value.([]byte); ok {
+				row[i] = append([]byte(nil), b...)
+			}
+		}
+		result.values = append(result.values, row)
+	}
+}
+
+// registerSQLiteFunctions registers the Postgres functions the repositories and the schema call,
+// such as now() and gen_random_uuid(), and those Dialect writes in place of Postgres operators.
+// Arrays are bound and stored as Postgres array literals ({a,b}), which is how pq.Array and
+// pq.StringArray bind them.
+func registerSQLiteFunctions() {
+	sqlite.MustRegisterScalarFunction("now", 0, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+		return sqliteValue(time.Now()), nil
+	})
+	sqlite.MustRegisterScalarFunction("seconds_from_now", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		var seconds float64
+		switch v := args[0].(type) {
+		case int64:
+			seconds = float64(v)
+		case float64:
+			seconds = v
+		default:
+			return nil, fmt.Errorf("seconds_from_now: %v is not a number", v)
+		}
+		return sqliteValue(time.Now().Add(time.Duration(seconds * float64(time.Second)))), nil
+	})
+	sqlite.MustRegisterScalarFunction("gen_random_uuid", 0, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+		return uuid.NewString(), nil
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("starts_with", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+		return strings.HasPrefix(fmt.Sprint(args[0]), fmt.Sprint(args[1])), nil
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("ilike", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+		return ilike(fmt.Sprint(args[0]), fmt.Sprint(args[1])), nil
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("cardinality", 1, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil {
+			return nil, nil
+		}
+		array, err := sqliteArray(args[0])
+		return int64(len(array)), err
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("array_contains", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+		array, err := sqliteArray(args[0])
+		if err != nil {
+			return nil, err
+		}
+		for _, element := range array {
+			if element == fmt.Sprint(args[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("array_overlaps", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+		a, err := sqliteArray(args[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := sqliteArray(args[1])
+		if err != nil {
+			return nil, err
+		}
+		for _, x := range a {
+			for _, y := range b {
+				if x == y {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("jsonb_exists", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+		doc, err := sqliteJSON(args[0])
+		return jsonbExists(doc, fmt.Sprint(args[1])), err
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("jsonb_exists_any", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+		doc, err := sqliteJSON(args[0])
+		if err != nil {
+			return nil, err
+		}
+		keys, err := sqliteArray(args[1])
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			if jsonbExists(doc, key) {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	sqlite.MustRegisterDeterministicScalarFunction("jsonb_contains", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+		a, err := sqliteJSON(args[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := sqliteJSON(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return jsonbContains(a, b), nil
+	})
+}
+
+// ilike is Postgres's value ILIKE pattern: % matches any run of characters and _ any one, \
+// escapes the character after it, and letters match those of either case, beyond ASCII too.
+func ilike(value, pattern string) bool {
+	v, p := []rune(value), []rune(pattern)
+	// matched[i] reports whether the pattern read so far matches v[:i]
+	matched := make([]bool, len(v)+1)
+	matched[0] = true
+	for i := 0; i < len(p); i++ {
+		next := make([]bool, len(v)+1)
+		switch p[i] {
+		case '%':
+			for j := range next {
+				next[j] = matched[j] || j > 0 && next[j-1]
+			}
+		case '_':
+			for j := 1; j < len(next); j++ {
+				next[j] = matched[j-1]
+			}
+		default:
+			literal := p[i]
+			if literal == '\\' && i+1 < len(p) {
+				i++
+				literal = p[i]
+			}
+			for j := 1; j < len(next); j++ {
+				next[j] = matched[j-1] && equalFold(v[j-1], literal)
+			}
+		}
+		matched = next
+	}
+	return matched[len(v)]
+}
+
+func equalFold(a, b rune) bool {
+	return a == b || unicode.ToLower(a) == unicode.ToLower(b) || unicode.ToUpper(a) == unicode.ToUpper(b)
+}
+
+// sqliteArray parses a Postgres array literal.
+func sqliteArray(value driver.Value) ([]string, error) {
+	var array pq.StringArray
+	if err := array.Scan(value); err != nil {
+		return nil, err
+	}
+	return array, nil
+}
+
+func sqliteJSON(value driver.Value) (any, error) {
+	var text []byte
+	switch v := value.(type) {
+	case string:
+		text = []byte(v)
+	case []byte:
+		text = v
+	default:
+		return v, nil
+	}
+	var doc any
+	if err := json.Unmarshal(text, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// jsonbExists is Postgres's doc ? key: key is a key of the object, a string in the array or the
+// string itself.
+func jsonbExists(doc any, key string) bool {
+	switch doc := doc.(type) {
+	case map[string]any:
+		_, ok := doc[key]
+		return ok
+	case []any:
+		for _, element := range doc {
+			if element == any(key) {
+				return true
+			}
+		}
+		return false
+	case string:
+		return doc == key
+	}
+	return false
+}
+
+// jsonbContains is Postgres's a @> b: every key of an object in b is in a with a value that
+// contains b's, and every element of an array in b is contained in some element of a's.
+func jsonbContains(a, b any) bool {
+	switch b := b.(type) {
+	case map[string]any:
+		a, ok := a.(map[string]any)
+		if !ok {
+			return false
+		}
+		for key, value := range b {
+			if got, ok := a[key]; !ok || !jsonbContains(got, value) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		a, ok := a.([]any)
+		if !ok {
+			return false
+		}
+		for _, want := range b {
+			found := false
+			for _, got := range a {
+				if jsonbContains(got, want) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// Open opens the SQLite database at path, or a new in-memory one for Memory.
+func Open(path string) (*sql.DB, error) {
+	registerSQLiteFunctionsOnce.Do(func() {
+		registerSQLiteFunctions()
+		db, _ := sql.Open("sqlite", "")
+		sqliteBaseDriver = db.Driver()
+		db.Close()
+	})
+
+	params := url.Values{"_pragma": {"foreign_keys(1)", "busy_timeout(10000)"}, "_txlock": {"immediate"}}
+	name := path
+	if path == Memory {
+		// memdb shares the database between the connections of the pool, unlike :memory:
+		name = fmt.Sprintf("/iam-%d", sqliteMemoryDBs.Add(1))
+		params.Set("vfs", "memdb")
+	}
+	connector := &sqliteConnector{driver: &sqliteDriver{Driver: sqliteBaseDriver}, dsn: "file:" + name + "?" + params.Encode()}
+	if path == Memory {
+		keep, err := connector.Connect(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		connector.keep = keep
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// Is reports whether db is a SQLite database opened by Open.
+func Is(db *sql.DB) bool {
+	if db == nil {
+		return false
+	}
+	_, ok := db.Driver().(*sqliteDriver)
+	return ok
+}
+
+// CreateSchema runs the schema file in a database without tables; databases opened before
+// keep theirs.
+func CreateSchema(db *sql.DB, schemaFile string) error {
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'").Scan(&tables); err != nil {
+		return err
+	}
+	if tables > 0 {
+		return nil
+	}
+	schema, err := os.ReadFile(schemaFile)
+	if err != nil {
+		return fmt.Errorf("sqlite schema: %w", err)
+	}
+	if _, err := db.Exec(string(schema)); err != nil {
+		return fmt.Errorf("sqlite schema %s: %w", schemaFile, err)
+	}
+	return nil
+}
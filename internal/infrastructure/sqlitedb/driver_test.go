@@ -0,0 +1,155 @@
+package sqlitedb
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var schemaFile = filepath.Join("..", "..", "..", "migrations", "sqlite", "schema.sql")
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(Memory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := CreateSchema(db, schemaFile); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// The schema mirrors the migrations; a migration it doesn't record was not mirrored.
+func TestSchemaRecordsEveryMigration(t *testing.T) {
+	db := openTestDB(t)
+	files, err := filepath.Glob(filepath.Join("..", "..", "..", "migrations", "*.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("migrations: %v", err)
+	}
+	for _, file := range files {
+		var recorded bool
+		if err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", filepath.Base(file)).Scan(&recorded); err != nil {
+			t.Fatal(err)
+		}
+		if !recorded {
+			t.Errorf("%s is not in %s", filepath.Base(file), schemaFile)
+		}
+	}
+}
+
+func TestInMemoryDatabasesArePerPool(t *testing.T) {
+	a, b := openTestDB(t), openTestDB(t)
+	if _, err := a.Exec("INSERT INTO domains (name, domain) VALUES ($1, $2)", "Acme", "acme.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	// Every connection of a pool sees the database
+	a.SetMaxIdleConns(0)
+	var count int
+	if err := a.QueryRow("SELECT COUNT(*) FROM domains").Scan(&count); err != nil || count != 1 {
+		t.Errorf("pool a: %d domains, %v; want 1", count, err)
+	}
+	if err := b.QueryRow("SELECT COUNT(*) FROM domains").Scan(&count); err != nil || count != 0 {
+		t.Errorf("pool b: %d domains, %v; want 0", count, err)
+	}
+}
+
+func TestUniqueViolationsAreNamedLikePostgres(t *testing.T) {
+	db := openTestDB(t)
+	var domainID, roleID string
+	if err := db.QueryRow("INSERT INTO domains (name, domain) VALUES ('Acme', 'acme.example.com') RETURNING domain_id").Scan(&domainID); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("INSERT INTO roles (domain_id, role_name) VALUES ($1, 'member') RETURNING id", domainID).Scan(&roleID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO users (domain_id, role_id, first_name, last_name, username, email, password_hash) VALUES ($1, $2, 'J', 'Doe', 'jdoe', 'j@example.com', 'x')", domainID, roleID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, query, constraint string
+		args                    []any
+	}{
+		{"unique index", "INSERT INTO users (domain_id, role_id, first_name, last_name, username, email, password_hash) VALUES ($1, $2, 'K', 'Doe', 'jdoe', 'k@example.com', 'x')",
+			"idx_users_domain_username", []any{domainID, roleID}},
+		{"unique constraint", "INSERT INTO roles (domain_id, role_name) VALUES ($1, 'member') RETURNING id",
+			"roles_domain_id_role_name_key", []any{domainID}},
+		{"primary key", "INSERT INTO domains (domain_id, name, domain) VALUES ($1, 'Other', 'other.example.com')",
+			"domains_pkey", []any{domainID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var id string
+			err := db.QueryRow(tt.query, tt.args...).Scan(&id)
+			var violation *UniqueViolation
+			if !errors.As(err, &violation) || violation.Constraint != tt.constraint {
+				t.Errorf("err = %#v, want a unique violation of %s", err, tt.constraint)
+			}
+		})
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+	db := openTestDB(t)
+	validUntil := time.Date(2030, 1, 2, 3, 4, 5, 123456789, time.FixedZone("WIB", 7*3600))
+	var domainID string
+	if err := db.QueryRow("INSERT INTO domains (name, domain) VALUES ('Acme', 'acme.example.com') RETURNING domain_id").Scan(&domainID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("UPDATE domains SET updated_at = $1 WHERE domain_id = $2", validUntil, domainID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stored in UTC with microseconds, read back from the column and from an expression
+	var createdAt, updatedAt, latest time.Time
+	var later bool
+	err := db.QueryRow(`SELECT created_at, updated_at, MAX(updated_at), created_at > $2
+		FROM domains WHERE domain_id = $1`, domainID, time.Now().Add(-time.Minute)).Scan(&createdAt, &updatedAt, &latest, &later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := validUntil.Truncate(time.Microsecond); !updatedAt.Equal(want) || !latest.Equal(want) {
+		t.Errorf("updated_at = %v, MAX = %v; want %v", updatedAt, latest, want)
+	}
+	if time.Since(createdAt) > time.Minute || !later {
+		t.Errorf("created_at = %v, recent = %v; want about now", createdAt, later)
+	}
+}
+
+func TestNilBytesAreNull(t *testing.T) {
+	db := openTestDB(t)
+	var isNull, isEmpty bool
+	if err := db.QueryRow("SELECT $1 IS NULL, $2 = ''", []byte(nil), []byte{}).Scan(&isNull, &isEmpty); err != nil {
+		t.Fatal(err)
+	}
+	if !isNull || !isEmpty {
+		t.Errorf("nil bound as NULL = %v, empty bound as '' = %v; want both", isNull, isEmpty)
+	}
+}
+
+func TestJSONBContains(t *testing.T) {
+	db := openTestDB(t)
+	tests := []struct {
+		doc, contains string
+		want          bool
+	}{
+		{`{"permissions": ["users:read", "users:write"], "level": 2}`, `{"permissions": ["users:write"]}`, true},
+		{`{"permissions": ["users:read"]}`, `{"permissions": ["users:write"]}`, false},
+		{`{"a": {"b": 1, "c": 2}}`, `{"a": {"b": 1}}`, true},
+		{`{"a": 1}`, `{"a": "1"}`, false},
+		{`[1, 2, 3]`, `[3, 1]`, true},
+	}
+	for _, tt := range tests {
+		var got bool
+		if err := db.QueryRow("SELECT "+Dialect{}.JSONContains("$1", "$2"), tt.doc, tt.contains).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s @> %s = %v, want %v", tt.doc, tt.contains, got, tt.want)
+		}
+	}
+}
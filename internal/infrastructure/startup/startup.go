@@ -0,0 +1,164 @@
+// Package startup checks the server's dependencies before it binds its port. Required
+// dependencies are retried with backoff; every outcome is collected for a readiness summary.
+package startup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+)
+
+// Check outcomes.
+const (
+	StatusOK      = "ok"
+	StatusWarning = "warning" // failed, but the server can run without it
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+)
+
+// Result is the outcome of one dependency check.
+type Result struct {
+	Name     string
+	Status   string
+	Attempts int
+	Elapsed  time.Duration
+	Detail   string
+}
+
+// Checker runs the checks and keeps their results in order.
+type Checker struct {
+	config  *config.StartupConfig
+	results []Result
+	started time.Time
+}
+
+func New(cfg *config.StartupConfig) *Checker {
+	return &Checker{config: cfg, started: time.Now()}
+}
+
+// Open calls open until it succeeds, retrying with exponential backoff until the attempts run out
+// or ctx is cancelled, and returns the last error on failure.
+func Open[T any](ctx context.Context, c *Checker, name string, open func() (T, error)) (T, error) {
+	started := time.Now()
+	backoff := c.config.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		value, err := open()
+		if err == nil {
+			c.add(Result{Name: name, Status: StatusOK, Attempts: attempt, Elapsed: time.Since(started)})
+			return value, nil
+		}
+		if attempt >= c.config.RetryAttempts {
+			c.add(Result{Name: name, Status: StatusFailed, Attempts: attempt, Elapsed: time.Since(started), Detail: err.Error()})
+			return value, err
+		}
+
+		log.Printf("Startup: %s unavailable (attempt %d of %d), retrying in %s: %v", name, attempt, c.config.RetryAttempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			c.add(Result{Name: name, Status: StatusFailed, Attempts: attempt, Elapsed: time.Since(started), Detail: err.Error()})
+			return value, fmt.Errorf("%w (gave up: %v)", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, c.config.RetryMaxBackoff)
+	}
+}
+
+// Verify runs an optional check once. A failure is recorded as a warning, or as a failure and
+// returned when required is set.
+func (c *Checker) Verify(ctx context.Context, name string, required bool, check func(ctx context.Context) (detail string, err error)) error {
+	started := time.Now()
+	detail, err := check(ctx)
+	result := Result{Name: name, Status: StatusOK, Attempts: 1, Elapsed: time.Since(started), Detail: detail}
+	if err != nil {
+		result.Status, result.Detail = StatusWarning, err.Error()
+		if required {
+			result.Status = StatusFailed
+		}
+	}
+	c.add(result)
+	if required {
+		return err
+	}
+	return nil
+}
+
+// Migrations returns a check that compares the newest migration shipped in dir with the newest
+// one recorded in each database. Databases migrated without run_migrations.sh can't be verified
+// and fail the check as well.
+func Migrations(dir string, schema repositories.SchemaRepository) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		latest := config.LatestMigration(dir)
+		if latest == "" {
+			return "", fmt.Errorf("no migrations found in %s", dir)
+		}
+		applied, err := schema.AppliedMigrations(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		var problems []string
+		for residency, version := range applied {
+			switch {
+			case version == "":
+				problems = append(problems, residency+" has no schema_migrations record")
+			case version < latest:
+				problems = append(problems, fmt.Sprintf("%s is at %s", residency, version))
+			}
+		}
+		if len(problems) > 0 {
+			sort.Strings(problems)
+			return "", fmt.Errorf("behind %s: %s", latest, strings.Join(problems, ", "))
+		}
+		return "at " + latest, nil
+	}
+}
+
+// Skip records a dependency that isn't configured.
+func (c *Checker) Skip(name, reason string) {
+	c.add(Result{Name: name, Status: StatusSkipped, Detail: reason})
+}
+
+func (c *Checker) add(result Result) {
+	c.results = append(c.results, result)
+}
+
+// Results returns the outcomes in the order the checks ran.
+func (c *Checker) Results() []Result {
+	return c.results
+}
+
+// LogSummary logs one line per check under a headline saying whether startup can go on.
+func (c *Checker) LogSummary() {
+	headline := "ready"
+	width := 0
+	for _, result := range c.results {
+		width = max(width, len(result.Name))
+		switch result.Status {
+		case StatusFailed:
+			headline = "failed"
+		case StatusWarning:
+			if headline == "ready" {
+				headline = "ready with warnings"
+			}
+		}
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Startup checks %s after %s:", headline, time.Since(c.started).Round(time.Millisecond))
+	for _, result := range c.results {
+		fmt.Fprintf(&summary, "\n  %-*s  %-7s", width, result.Name, result.Status)
+		if result.Attempts > 0 {
+			fmt.Fprintf(&summary, "  %d attempt(s), %s", result.Attempts, result.Elapsed.Round(time.Millisecond))
+		}
+		if result.Detail != "" {
+			fmt.Fprintf(&summary, "  %s", result.Detail)
+		}
+	}
+	log.Println(summary.String())
+}
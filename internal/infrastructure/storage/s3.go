@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/internal/infrastructure/awssig"
+)
+
+// S3Config names the bucket objects are stored in. Endpoint is only needed for S3-compatible
+// stores, which are addressed path-style; AWS itself is addressed by virtual host. Objects are
+// fetched from PublicURL, such as a CDN in front of the bucket, or else from the bucket itself,
+// which must then allow public reads.
+type S3Config struct {
+	Bucket          string
+	Region          string
+	Endpoint        string
+	PublicURL       string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+type S3Storage struct {
+	cfg    S3Config
+	signer awssig.Signer
+	http   *http.Client
+}
+
+func NewS3Storage(cfg S3Config, timeout time.Duration) *S3Storage {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://" + cfg.Bucket + ".s3." + cfg.Region + ".amazonaws.com"
+	} else {
+		cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/") + "/" + cfg.Bucket
+	}
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = cfg.Endpoint
+	}
+	cfg.PublicURL = strings.TrimSuffix(cfg.PublicURL, "/")
+	signer := awssig.Signer{Service: "s3", Region: cfg.Region, AccessKeyID: cfg.AccessKeyID, SecretAccessKey: cfg.SecretAccessKey, SessionToken: cfg.SessionToken}
+	return &S3Storage{cfg: cfg, signer: signer, http: &http.Client{Timeout: timeout}}
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	return s.do(ctx, http.MethodPut, key, body, contentType)
+}
+
+// Delete succeeds for missing objects too, as S3 does.
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	return s.do(ctx, http.MethodDelete, key, nil, "")
+}
+
+func (s *S3Storage) URL(key string) string {
+	return s.cfg.PublicURL + "/" + awssig.EscapePath(key)
+}
+
+func (s *S3Storage) do(ctx context.Context, method, key string, body []byte, contentType string) error {
+	target, err := url.Parse(s.cfg.Endpoint + "/" + key)
+	if err != nil {
+		return err
+	}
+	target.RawPath = awssig.EscapePath(target.Path)
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.signer.Sign(req, body, time.Now())
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3 unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("s3 returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
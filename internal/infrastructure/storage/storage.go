@@ -0,0 +1,13 @@
+// Package storage keeps uploaded files, such as user avatars, as objects on local disk or in S3.
+package storage
+
+import "context"
+
+// ObjectStorage stores objects by key and tells clients where to fetch them.
+type ObjectStorage interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	// Delete removes an object; a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// URL is where clients fetch an object
+	URL(key string) string
+}
@@ -0,0 +1,189 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const bigQueryInsertScope = "https://www.googleapis.com/auth/bigquery.insertdata"
+
+// BigQueryConfig names the table records are streamed into and the service account key file
+// authorizing it. The table needs a column of the same name and a compatible type for every
+// LoginRecord field.
+type BigQueryConfig struct {
+	ProjectID       string
+	Dataset         string
+	Table           string
+	CredentialsFile string
+}
+
+// BigQuerySink streams records with the tabledata.insertAll API. Each row's insert ID is its
+// event ID, so BigQuery drops most of the copies a retried batch produces.
+type BigQuerySink struct {
+	endpoint string
+	account  serviceAccount
+	http     *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+type serviceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+func NewBigQuerySink(cfg BigQueryConfig, timeout time.Duration) (*BigQuerySink, error) {
+	raw, err := os.ReadFile(cfg.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("read bigquery credentials: %w", err)
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("parse bigquery credentials: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("bigquery credentials are not a service account key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	endpoint := fmt.Sprintf("https://bigquery.googleapis.com/bigquery/v2/projects/%s/datasets/%s/tables/%s/insertAll",
+		url.PathEscape(cfg.ProjectID), url.PathEscape(cfg.Dataset), url.PathEscape(cfg.Table))
+	return &BigQuerySink{endpoint: endpoint, account: account, http: &http.Client{Timeout: timeout}}, nil
+}
+
+type insertAllRow struct {
+	InsertID string       `json:"insertId"`
+	JSON     *LoginRecord `json:"json"`
+}
+
+type insertAllResponse struct {
+	InsertErrors []struct {
+		Index  int `json:"index"`
+		Errors []struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"insertErrors"`
+}
+
+func (s *BigQuerySink) Write(ctx context.Context, batch Batch) error {
+	rows := make([]insertAllRow, len(batch.Records))
+	for i := range batch.Records {
+		rows[i] = insertAllRow{InsertID: batch.Records[i].EventID, JSON: &batch.Records[i]}
+	}
+	body, err := json.Marshal(map[string]any{"rows": rows})
+	if err != nil {
+		return err
+	}
+
+	token, err := s.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("bigquery unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		s.mu.Lock()
+		s.token = ""
+		s.mu.Unlock()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("bigquery returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	// A 200 can still reject rows, in which case none of the batch is stored
+	var result insertAllResponse
+	if err := json.Unmarshal(detail, &result); err == nil && len(result.InsertErrors) > 0 {
+		first := result.InsertErrors[0]
+		if len(first.Errors) > 0 {
+			return fmt.Errorf("bigquery rejected row %d: %s: %s", first.Index, first.Errors[0].Reason, first.Errors[0].Message)
+		}
+		return fmt.Errorf("bigquery rejected row %d", first.Index)
+	}
+	return nil
+}
+
+// accessToken exchanges a JWT signed with the service account key for an OAuth access token,
+// reusing it until shortly before it expires.
+func (s *BigQuerySink) accessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Before(s.tokenExpiry) {
+		return s.token, nil
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(s.account.PrivateKey))
+	if err != nil {
+		return "", fmt.Errorf("parse bigquery private key: %w", err)
+	}
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.account.ClientEmail,
+		"scope": bigQueryInsertScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("google token endpoint unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return "", fmt.Errorf("google token endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("decode google token: %w", err)
+	}
+
+	s.token = token.AccessToken
+	s.tokenExpiry = now.Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return s.token, nil
+}
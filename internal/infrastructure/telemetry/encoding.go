@@ -0,0 +1,47 @@
+package telemetry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+)
+
+// Object formats written by file-based sinks.
+const (
+	FormatNDJSON  = "ndjson"
+	FormatParquet = "parquet"
+)
+
+// encoding turns a batch into the body of one object.
+type encoding struct {
+	extension   string
+	contentType string
+	encode      func(records []LoginRecord) ([]byte, error)
+}
+
+func encodingFor(format string) (encoding, error) {
+	switch format {
+	case FormatNDJSON:
+		return encoding{extension: ".ndjson.gz", contentType: "application/x-ndjson", encode: encodeNDJSON}, nil
+	case FormatParquet:
+		return encoding{extension: ".parquet", contentType: "application/vnd.apache.parquet", encode: encodeParquet}, nil
+	}
+	return encoding{}, fmt.Errorf("unknown telemetry format %q", format)
+}
+
+// encodeNDJSON writes one JSON object per line, gzip-compressed.
+func encodeNDJSON(records []LoginRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for i := range records {
+		if err := enc.Encode(&records[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,21 @@
+package telemetry
+
+import (
+	"bytes"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// encodeParquet writes the records as a single Snappy-compressed row group, with the schema taken
+// from the parquet tags of LoginRecord.
+func encodeParquet(records []LoginRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[LoginRecord](&buf, parquet.Compression(&parquet.Snappy))
+	if _, err := w.Write(records); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,87 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/internal/infrastructure/awssig"
+)
+
+// S3Config names the bucket batches are written to. Endpoint is only needed for S3-compatible
+// stores, which are addressed path-style; AWS itself is addressed by virtual host.
+type S3Config struct {
+	Bucket          string
+	Prefix          string
+	Region          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3Sink writes each batch as one object under
+// <prefix>/domain=<domain id>/dt=<date>/<first sequence>-<last sequence><extension>, a layout
+// Athena, Glue and BigQuery external tables can partition on.
+type S3Sink struct {
+	cfg      S3Config
+	signer   awssig.Signer
+	encoding encoding
+	http     *http.Client
+}
+
+func NewS3Sink(cfg S3Config, format string, timeout time.Duration) (*S3Sink, error) {
+	enc, err := encodingFor(format)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://" + cfg.Bucket + ".s3." + cfg.Region + ".amazonaws.com"
+	} else {
+		cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/") + "/" + cfg.Bucket
+	}
+	signer := awssig.Signer{Service: "s3", Region: cfg.Region, AccessKeyID: cfg.AccessKeyID, SecretAccessKey: cfg.SecretAccessKey, SessionToken: cfg.SessionToken}
+	return &S3Sink{cfg: cfg, signer: signer, encoding: enc, http: &http.Client{Timeout: timeout}}, nil
+}
+
+func (s *S3Sink) Write(ctx context.Context, batch Batch) error {
+	body, err := s.encoding.encode(batch.Records)
+	if err != nil {
+		return fmt.Errorf("encode batch: %w", err)
+	}
+
+	key := fmt.Sprintf("domain=%s/dt=%s/%020d-%020d%s", batch.DomainID,
+		batch.Records[0].OccurredAt.UTC().Format("2006-01-02"), batch.FirstSequence, batch.LastSequence, s.encoding.extension)
+	if prefix := strings.Trim(s.cfg.Prefix, "/"); prefix != "" {
+		key = prefix + "/" + key
+	}
+	target, err := url.Parse(s.cfg.Endpoint + "/" + key)
+	if err != nil {
+		return err
+	}
+	target.RawPath = awssig.EscapePath(target.Path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", s.encoding.contentType)
+	s.signer.Sign(req, body, time.Now())
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3 unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("s3 returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+// Package telemetry ships anonymized sign-in funnel records to an analytics warehouse.
+package telemetry
+
+import (
+	"context"
+	"time"
+)
+
+// LoginRecord is one step of a sign-in, stripped of anything identifying the person: the account
+// is only named by a keyed hash that is stable within the domain, and usernames, emails and
+// client IPs are never included.
+type LoginRecord struct {
+	EventID    string    `json:"event_id" parquet:"event_id"`
+	DomainID   string    `json:"domain_id" parquet:"domain_id"`
+	Sequence   int64     `json:"sequence" parquet:"sequence"`
+	Event      string    `json:"event" parquet:"event"` // login.succeeded, login.failed, login.challenged or login.code_sent
+	Method     string    `json:"method" parquet:"method"`
+	Reason     string    `json:"reason" parquet:"reason"`       // why a failed attempt was refused
+	Challenge  string    `json:"challenge" parquet:"challenge"` // captcha or mfa, for a challenged attempt
+	Actor      string    `json:"actor" parquet:"actor"`         // empty when no account matched
+	OccurredAt time.Time `json:"occurred_at" parquet:"occurred_at,timestamp(millisecond)"`
+}
+
+// Batch is a run of one domain's records, ordered by sequence.
+type Batch struct {
+	DomainID      string
+	FirstSequence int64
+	LastSequence  int64
+	Records       []LoginRecord
+}
+
+// Sink stores batches in the warehouse. A batch whose export was interrupted is written again, so
+// analyses should count records by event ID.
+type Sink interface {
+	Write(ctx context.Context, batch Batch) error
+}
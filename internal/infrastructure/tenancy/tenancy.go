@@ -0,0 +1,39 @@
+// Package tenancy carries the domain a database call is made for, so the database driver can
+// scope the Postgres session to it for row-level security.
+package tenancy
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type contextKey struct{}
+
+type systemKey struct{}
+
+// WithDomain marks ctx as belonging to the domain's tenant data.
+func WithDomain(ctx context.Context, domainID uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextKey{}, domainID)
+}
+
+// DomainFrom returns the domain set by WithDomain, if any.
+func DomainFrom(ctx context.Context) (uuid.UUID, bool) {
+	domainID, ok := ctx.Value(contextKey{}).(uuid.UUID)
+	return domainID, ok && domainID != uuid.Nil
+}
+
+// AsSystem marks ctx as one of the system paths that read and write every tenant's data: the
+// background sweeps and workers, the platform operator and system admins, and the few lookups
+// that find the domain of a record before anyone is authenticated for it. It clears a domain set
+// before it, while a domain set with WithDomain after it takes precedence. Every other call
+// without a domain sees no tenant rows.
+func AsSystem(ctx context.Context) context.Context {
+	return context.WithValue(context.WithValue(ctx, contextKey{}, uuid.Nil), systemKey{}, true)
+}
+
+// IsSystem reports whether ctx was marked with AsSystem.
+func IsSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey{}).(bool)
+	return system
+}
@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"backend/internal/infrastructure/netguard"
+)
+
+func TestDeliverGuard(t *testing.T) {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer endpoint.Close()
+	req := Request{URL: endpoint.URL, Secret: "secret", DeliveryID: "1", EventType: "user.created", Body: []byte("{}")}
+
+	refused := NewClient(time.Second, netguard.New(nil))
+	if status, err := refused.Deliver(context.Background(), req); !errors.Is(err, netguard.ErrForbiddenAddress) {
+		t.Errorf("Deliver to loopback = %d, %v; want ErrForbiddenAddress", status, err)
+	}
+
+	allowed := NewClient(time.Second, netguard.New([]netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}))
+	if status, err := allowed.Deliver(context.Background(), req); err != nil || status != http.StatusNoContent {
+		t.Errorf("Deliver to allowed loopback = %d, %v; want 204", status, err)
+	}
+}
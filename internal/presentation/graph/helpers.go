@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+type maskerKey struct{}
+
+// WithUserMasker attaches the masker every user of the request is returned through.
+func WithUserMasker(ctx context.Context, masker *services.UserMasker) context.Context {
+	return context.WithValue(ctx, maskerKey{}, masker)
+}
+
+// maskUser masks the user for the requesting admin. Without a masker no user is returned, so a
+// missing one can't leak unmasked fields.
+func maskUser(ctx context.Context, user *entities.User) (*entities.User, error) {
+	masker, ok := ctx.Value(maskerKey{}).(*services.UserMasker)
+	if !ok {
+		return nil, errors.New("no user masker in context")
+	}
+	return masker.Mask(ctx, user)
+}
+
+func maskUsers(ctx context.Context, users []*entities.User) ([]*entities.User, error) {
+	masker, ok := ctx.Value(maskerKey{}).(*services.UserMasker)
+	if !ok {
+		return nil, errors.New("no user masker in context")
+	}
+	return masker.MaskAll(ctx, users)
+}
+
+func maskUserPage(ctx context.Context, result *repositories.UserListResult) (*repositories.UserListResult, error) {
+	users, err := maskUsers(ctx, result.Users)
+	if err != nil {
+		return nil, err
+	}
+	result.Users = users
+	return result, nil
+}
+
+// orNull turns a not found error into null, as nullable lookups answer in GraphQL.
+func orNull[T any](value *T, err error) (*T, error) {
+	if errors.Is(err, domainerrors.ErrNotFound) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// optionalID is the ID, or uuid.Nil (no filter) when it's omitted.
+func optionalID(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+	return *id
+}
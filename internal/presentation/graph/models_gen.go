@@ -0,0 +1,36 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graph
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateUserInput struct {
+	DomainID   uuid.UUID  `json:"domainId"`
+	RoleID     uuid.UUID  `json:"roleId"`
+	FirstName  string     `json:"firstName"`
+	LastName   string     `json:"lastName"`
+	Username   string     `json:"username"`
+	Email      string     `json:"email"`
+	Password   string     `json:"password"`
+	ExternalID *string    `json:"externalId,omitempty"`
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
+}
+
+type Mutation struct {
+}
+
+type Query struct {
+}
+
+type UpdateUserInput struct {
+	RoleID     uuid.UUID `json:"roleId"`
+	FirstName  string    `json:"firstName"`
+	LastName   string    `json:"lastName"`
+	Username   string    `json:"username"`
+	Email      string    `json:"email"`
+	ExternalID *string   `json:"externalId,omitempty"`
+}
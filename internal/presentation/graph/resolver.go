@@ -0,0 +1,15 @@
+// Package graph serves the admin console's GraphQL schema on top of the application services.
+package graph
+
+//go:generate go tool gqlgen generate
+
+import "backend/internal/application/services"
+
+// Resolver holds the services the resolvers call. It is shared by every request; per-request
+// state, such as the user masker, travels in the context.
+type Resolver struct {
+	DomainService services.DomainService
+	RoleService   services.RoleService
+	UserService   services.UserService
+	GroupService  services.GroupService
+}
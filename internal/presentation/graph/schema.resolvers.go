@@ -0,0 +1,199 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.74
+
+import (
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/repositories"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Roles is the resolver for the roles field.
+func (r *domainResolver) Roles(ctx context.Context, obj *entities.Domain) ([]*entities.Role, error) {
+	return r.RoleService.GetRolesByDomainID(ctx, obj.DomainID)
+}
+
+// Users is the resolver for the users field.
+func (r *domainResolver) Users(ctx context.Context, obj *entities.Domain, search *string, page *int, limit *int) (*repositories.UserListResult, error) {
+	result, err := r.UserService.ListUsersWithPagination(ctx, repositories.UserListFilter{Search: *search}, obj.DomainID, *page, *limit)
+	if err != nil {
+		return nil, err
+	}
+	return maskUserPage(ctx, result)
+}
+
+// Groups is the resolver for the groups field.
+func (r *domainResolver) Groups(ctx context.Context, obj *entities.Domain) ([]*entities.Group, error) {
+	return r.GroupService.ListGroups(ctx, obj.DomainID)
+}
+
+// Domain is the resolver for the domain field.
+func (r *groupResolver) Domain(ctx context.Context, obj *entities.Group) (*entities.Domain, error) {
+	return r.DomainService.GetDomainByID(ctx, obj.DomainID)
+}
+
+// Members is the resolver for the members field.
+func (r *groupResolver) Members(ctx context.Context, obj *entities.Group) ([]*entities.User, error) {
+	members, err := r.GroupService.ListMembers(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return maskUsers(ctx, members)
+}
+
+// Roles is the resolver for the roles field.
+func (r *groupResolver) Roles(ctx context.Context, obj *entities.Group) ([]*entities.Role, error) {
+	return r.GroupService.ListRoles(ctx, obj.ID)
+}
+
+// CreateRole is the resolver for the createRole field.
+func (r *mutationResolver) CreateRole(ctx context.Context, domainID uuid.UUID, roleName string, roleClaims map[string]any) (*entities.Role, error) {
+	return r.RoleService.CreateRole(ctx, domainID, roleName, roleClaims)
+}
+
+// UpdateRole is the resolver for the updateRole field.
+func (r *mutationResolver) UpdateRole(ctx context.Context, id uuid.UUID, roleName string, roleClaims map[string]any) (*entities.Role, error) {
+	return r.RoleService.UpdateRole(ctx, id, roleName, roleClaims, nil)
+}
+
+// DeleteRole is the resolver for the deleteRole field.
+func (r *mutationResolver) DeleteRole(ctx context.Context, id uuid.UUID) (bool, error) {
+	if _, err := r.RoleService.DeleteRole(ctx, id, nil, false); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateUser is the resolver for the createUser field.
+func (r *mutationResolver) CreateUser(ctx context.Context, input CreateUserInput) (*entities.User, error) {
+	user, err := r.UserService.CreateUser(ctx, input.DomainID, input.RoleID, input.FirstName, input.LastName, input.Username, input.Email, input.Password, input.ExternalID, input.ValidUntil)
+	if err != nil {
+		return nil, err
+	}
+	return maskUser(ctx, user)
+}
+
+// UpdateUser is the resolver for the updateUser field.
+func (r *mutationResolver) UpdateUser(ctx context.Context, id uuid.UUID, input UpdateUserInput) (*entities.User, error) {
+	user, err := r.UserService.UpdateUser(ctx, id, input.FirstName, input.LastName, input.Username, input.Email, input.RoleID, input.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	return maskUser(ctx, user)
+}
+
+// DeleteUser is the resolver for the deleteUser field.
+func (r *mutationResolver) DeleteUser(ctx context.Context, id uuid.UUID) (bool, error) {
+	if err := r.UserService.DeleteUser(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// AddGroupMember is the resolver for the addGroupMember field.
+func (r *mutationResolver) AddGroupMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) (*entities.Group, error) {
+	if err := r.GroupService.AddMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	return r.GroupService.GetGroupByID(ctx, groupID)
+}
+
+// RemoveGroupMember is the resolver for the removeGroupMember field.
+func (r *mutationResolver) RemoveGroupMember(ctx context.Context, groupID uuid.UUID, userID uuid.UUID) (*entities.Group, error) {
+	if err := r.GroupService.RemoveMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+	return r.GroupService.GetGroupByID(ctx, groupID)
+}
+
+// Domain is the resolver for the domain field.
+func (r *queryResolver) Domain(ctx context.Context, id uuid.UUID) (*entities.Domain, error) {
+	return orNull(r.DomainService.GetDomainByID(ctx, id))
+}
+
+// Domains is the resolver for the domains field.
+func (r *queryResolver) Domains(ctx context.Context, search *string, page *int, limit *int) (*repositories.DomainListResult, error) {
+	return r.DomainService.ListDomainsWithPagination(ctx, *search, *page, *limit)
+}
+
+// Role is the resolver for the role field.
+func (r *queryResolver) Role(ctx context.Context, id uuid.UUID) (*entities.Role, error) {
+	return orNull(r.RoleService.GetRoleByID(ctx, id))
+}
+
+// Roles is the resolver for the roles field.
+func (r *queryResolver) Roles(ctx context.Context, domainID *uuid.UUID, search *string, claim *string, page *int, limit *int) (*repositories.RoleListResult, error) {
+	return r.RoleService.ListRolesWithPagination(ctx, repositories.RoleListFilter{Search: *search, Claim: *claim}, optionalID(domainID), *page, *limit)
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	user, err := orNull(r.UserService.GetUserByID(ctx, id))
+	if user == nil || err != nil {
+		return nil, err
+	}
+	return maskUser(ctx, user)
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, domainID *uuid.UUID, search *string, page *int, limit *int) (*repositories.UserListResult, error) {
+	result, err := r.UserService.ListUsersWithPagination(ctx, repositories.UserListFilter{Search: *search}, optionalID(domainID), *page, *limit)
+	if err != nil {
+		return nil, err
+	}
+	return maskUserPage(ctx, result)
+}
+
+// Group is the resolver for the group field.
+func (r *queryResolver) Group(ctx context.Context, id uuid.UUID) (*entities.Group, error) {
+	return orNull(r.GroupService.GetGroupByID(ctx, id))
+}
+
+// Domain is the resolver for the domain field.
+func (r *roleResolver) Domain(ctx context.Context, obj *entities.Role) (*entities.Domain, error) {
+	return r.DomainService.GetDomainByID(ctx, obj.DomainID)
+}
+
+// Role is the resolver for the role field.
+func (r *userResolver) Role(ctx context.Context, obj *entities.User) (*entities.Role, error) {
+	return orNull(r.RoleService.GetRoleByID(ctx, obj.RoleID))
+}
+
+// Domain is the resolver for the domain field.
+func (r *userResolver) Domain(ctx context.Context, obj *entities.User) (*entities.Domain, error) {
+	return r.DomainService.GetDomainByID(ctx, obj.DomainID)
+}
+
+// Groups is the resolver for the groups field.
+func (r *userResolver) Groups(ctx context.Context, obj *entities.User) ([]*entities.Group, error) {
+	return r.GroupService.ListUserGroups(ctx, obj.ID)
+}
+
+// Domain returns DomainResolver implementation.
+func (r *Resolver) Domain() DomainResolver { return &domainResolver{r} }
+
+// Group returns GroupResolver implementation.
+func (r *Resolver) Group() GroupResolver { return &groupResolver{r} }
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Role returns RoleResolver implementation.
+func (r *Resolver) Role() RoleResolver { return &roleResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+type domainResolver struct{ *Resolver }
+type groupResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type roleResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"backend/internal/presentation/middleware"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/google/uuid"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// maxQueryComplexity bounds the work of one query: each field counts 1, times the size of
+	// every list it's nested in. 100 users with their role, domain and groups fit.
+	maxQueryComplexity = 5000
+	// unpagedListSize is the size assumed for lists without a limit, such as a user's groups.
+	unpagedListSize = 20
+)
+
+// NewServer serves the schema over GET (queries only) and POST.
+func NewServer(resolver *Resolver) *handler.Server {
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: resolver, Complexity: complexity()}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.FixedComplexityLimit(maxQueryComplexity))
+	srv.SetErrorPresenter(presentError)
+	return srv
+}
+
+// complexity weighs list fields by the number of items they may return.
+func complexity() ComplexityRoot {
+	var c ComplexityRoot
+	c.Query.Domains = func(child int, _ *string, _, limit *int) int { return child * pageSize(limit) }
+	c.Query.Roles = func(child int, _ *uuid.UUID, _, _ *string, _, limit *int) int { return child * pageSize(limit) }
+	c.Query.Users = func(child int, _ *uuid.UUID, _ *string, _, limit *int) int { return child * pageSize(limit) }
+	c.Domain.Users = func(child int, _ *string, _, limit *int) int { return child * pageSize(limit) }
+
+	unpaged := func(child int) int { return child * unpagedListSize }
+	c.Domain.Roles, c.Domain.Groups = unpaged, unpaged
+	c.Group.Members, c.Group.Roles = unpaged, unpaged
+	c.User.Groups = unpaged
+	return c
+}
+
+// pageSize is the limit the services will apply: 10 unless between 1 and 100.
+func pageSize(limit *int) int {
+	if limit == nil || *limit < 1 || *limit > 100 {
+		return 10
+	}
+	return *limit
+}
+
+// presentError describes domain errors the way REST responses do, with their code and status in
+// the error's extensions, and hides the details of any other error.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) && gqlErr.Err == nil {
+		// Parse and validation errors of the query itself
+		return presented
+	}
+
+	status, code, message, ok := middleware.DescribeError(err)
+	if !ok {
+		cause := err
+		if gqlErr != nil {
+			cause = gqlErr.Err
+		}
+		log.Printf("/graphql %s: %v", presented.Path, cause)
+	}
+	presented.Message = message
+	presented.Extensions = map[string]interface{}{"code": code, "status": status}
+	return presented
+}
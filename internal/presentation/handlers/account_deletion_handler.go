@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountDeletionHandler struct {
+	deletionService services.AccountDeletionService
+	authService     services.AuthService
+}
+
+func NewAccountDeletionHandler(deletionService services.AccountDeletionService, authService services.AuthService) *AccountDeletionHandler {
+	return &AccountDeletionHandler{deletionService: deletionService, authService: authService}
+}
+
+// RequestDeletion godoc
+//
+//	@Summary		Delete my account
+//	@Description	Schedule the deletion of the authenticated user's account, where the domain's account_deletion settings allow it (403 with code account_deletion_disabled otherwise). The account is deleted once the grace period has passed and can be kept until then with POST /auth/me/cancel-deletion; the user is emailed when the deletion is scheduled, cancelled and carried out. Requesting again while a deletion is pending keeps the original date.
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		202				{object}	entities.User
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/me [delete]
+func (h *AccountDeletionHandler) RequestDeletion(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	user, err := h.deletionService.RequestDeletion(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondError(c, err, "Failed to request account deletion")
+		return
+	}
+	c.JSON(http.StatusAccepted, user)
+}
+
+// CancelDeletion godoc
+//
+//	@Summary		Cancel my account deletion
+//	@Description	Cancel the pending deletion of the authenticated user's account (409 with code no_deletion_pending when none is pending)
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200				{object}	entities.User
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		409				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/me/cancel-deletion [post]
+func (h *AccountDeletionHandler) CancelDeletion(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	user, err := h.deletionService.CancelDeletion(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondError(c, err, "Failed to cancel account deletion")
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"backend/internal/application/services"
+	"backend/internal/infrastructure/faults"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetFaultsRequest sets the faults to inject. Durations are Go duration strings such as "500ms".
+type SetFaultsRequest struct {
+	DBLatency                  string   `json:"db_latency" example:"500ms"`
+	DBLatencyRate              *float64 `json:"db_latency_rate" example:"1"` // defaults to 1
+	TokenValidationFailureRate float64  `json:"token_validation_failure_rate" example:"0.25"`
+	DeliveryFailureRate        float64  `json:"delivery_failure_rate" example:"0"`
+	Duration                   string   `json:"duration" example:"15m"` // defaults to FAULT_INJECTION_MAX_DURATION
+}
+
+// AdminHandler serves the platform operator endpoints for running the service.
+type AdminHandler struct {
+	snapshotService services.ConfigSnapshotService
+	faultService    services.FaultInjectionService
+}
+
+func NewAdminHandler(snapshotService services.ConfigSnapshotService, faultService services.FaultInjectionService) *AdminHandler {
+	return &AdminHandler{snapshotService: snapshotService, faultService: faultService}
+}
+
+// GetConfigSnapshot godoc
+//
+//	@Summary		Get a configuration snapshot
+//	@Description	Dump the effective non-secret configuration, enabled features, signing key fingerprints, build and migration versions of this instance, to compare environments during an incident. Secrets are reported only as whether they are set. Platform operators only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Success		200	{object}	services.ConfigSnapshot
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/admin/config-snapshot [get]
+func (h *AdminHandler) GetConfigSnapshot(c *gin.Context) {
+	snapshot, err := h.snapshotService.Snapshot(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to build configuration snapshot")
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// GetFaults godoc
+//
+//	@Summary		Get injected faults
+//	@Description	Get the artificial failures this instance is injecting. Only available when FAULT_INJECTION_ENABLED is true. Platform operators only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Success		200	{object}	services.FaultInjection
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Router			/api/v1/admin/faults [get]
+func (h *AdminHandler) GetFaults(c *gin.Context) {
+	c.JSON(http.StatusOK, h.faultService.GetFaults(c.Request.Context()))
+}
+
+// SetFaults godoc
+//
+//	@Summary		Inject faults
+//	@Description	Replace the artificial failures this instance injects, for resilience testing of consuming apps: latency added to a share of database calls, a share of token validations failing with 401, and a share of outbound email deliveries failing. Faults wear off after the duration, at most FAULT_INJECTION_MAX_DURATION, and apply to this instance only. Only available when FAULT_INJECTION_ENABLED is true; never enable it in production. Platform operators only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Param			faults	body		SetFaultsRequest	true	"Faults to inject"
+//	@Success		200		{object}	services.FaultInjection
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Router			/api/v1/admin/faults [put]
+func (h *AdminHandler) SetFaults(c *gin.Context) {
+	var req SetFaultsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	settings := faults.Settings{
+		DBLatencyRate:              1,
+		TokenValidationFailureRate: req.TokenValidationFailureRate,
+		DeliveryFailureRate:        req.DeliveryFailureRate,
+	}
+	if req.DBLatencyRate != nil {
+		settings.DBLatencyRate = *req.DBLatencyRate
+	}
+	latency, err := parseOptionalDuration(req.DBLatency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid db_latency; use a duration such as 500ms"})
+		return
+	}
+	settings.DBLatency = latency
+	duration, err := parseOptionalDuration(req.Duration)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid duration; use a duration such as 15m"})
+		return
+	}
+
+	result, err := h.faultService.SetFaults(c.Request.Context(), settings, duration)
+	if err != nil {
+		respondError(c, err, "Failed to inject faults")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ClearFaults godoc
+//
+//	@Summary		Stop injecting faults
+//	@Description	Stop all artificial failures on this instance. Only available when FAULT_INJECTION_ENABLED is true. Platform operators only.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Success		204	{object}	MessageResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Router			/api/v1/admin/faults [delete]
+func (h *AdminHandler) ClearFaults(c *gin.Context) {
+	h.faultService.ClearFaults(c.Request.Context())
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Faults cleared successfully"})
+}
+
+func parseOptionalDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateAPIKeyRequest struct {
+	Name               string `json:"name" binding:"required" example:"ci-pipeline"`
+	RateLimitPerMinute *int   `json:"rate_limit_per_minute" binding:"omitempty,min=1" example:"120"`
+	DailyQuota         *int   `json:"daily_quota" binding:"omitempty,min=1" example:"50000"`
+}
+
+type UpdateAPIKeyLimitsRequest struct {
+	RateLimitPerMinute *int `json:"rate_limit_per_minute" binding:"omitempty,min=1" example:"120"`
+	DailyQuota         *int `json:"daily_quota" binding:"omitempty,min=1" example:"50000"`
+}
+
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// CreateAPIKey godoc
+//
+//	@Summary		Create an API key
+//	@Description	Issue an API key for the domain with optional rate limit overrides. The key secret is only returned in this response.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string				true	"Domain ID"
+//	@Param			apiKey		body		CreateAPIKeyRequest	true	"API key data"
+//	@Success		201			{object}	services.CreatedAPIKey
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	key, err := h.apiKeyService.CreateAPIKey(c.Request.Context(), domainID, req.Name, req.RateLimitPerMinute, req.DailyQuota)
+	if err != nil {
+		respondError(c, err, "Failed to create API key")
+		return
+	}
+	c.JSON(http.StatusCreated, key)
+}
+
+// ListAPIKeys godoc
+//
+//	@Summary		List domain API keys
+//	@Description	Get all API keys of a domain, including revoked ones
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{array}		entities.APIKey
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	keys, err := h.apiKeyService.ListAPIKeys(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to list API keys")
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// GetAPIKey godoc
+//
+//	@Summary		Get an API key
+//	@Description	Get API key metadata by ID
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"API key ID"
+//	@Success		200	{object}	entities.APIKey
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/api-keys/{id} [get]
+func (h *APIKeyHandler) GetAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	key, err := h.apiKeyService.GetAPIKey(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get API key")
+		return
+	}
+	c.JSON(http.StatusOK, key)
+}
+
+// RevokeAPIKey godoc
+//
+//	@Summary		Revoke an API key
+//	@Description	Revoke an API key; further requests using it are rejected
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"API key ID"
+//	@Success		204	{object}	MessageResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to revoke API key")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "API key revoked successfully"})
+}
+
+// GetAPIKeyLimits godoc
+//
+//	@Summary		Get API key limits
+//	@Description	Get the rate limit and daily quota in force for a key and whether each is an override
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"API key ID"
+//	@Success		200	{object}	services.APIKeyLimits
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/api-keys/{id}/limits [get]
+func (h *APIKeyHandler) GetAPIKeyLimits(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	limits, err := h.apiKeyService.GetLimits(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get API key limits")
+		return
+	}
+	c.JSON(http.StatusOK, limits)
+}
+
+// UpdateAPIKeyLimits godoc
+//
+//	@Summary		Set API key limits
+//	@Description	Replace the key's rate limit overrides; omitted or null values fall back to the server defaults
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string						true	"API key ID"
+//	@Param			limits	body		UpdateAPIKeyLimitsRequest	true	"Limit overrides"
+//	@Success		200		{object}	services.APIKeyLimits
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/api-keys/{id}/limits [put]
+func (h *APIKeyHandler) UpdateAPIKeyLimits(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req UpdateAPIKeyLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	h.respondLimits(c, id, req.RateLimitPerMinute, req.DailyQuota)
+}
+
+// DeleteAPIKeyLimits godoc
+//
+//	@Summary		Clear API key limits
+//	@Description	Remove the key's rate limit overrides so the server defaults apply
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"API key ID"
+//	@Success		200	{object}	services.APIKeyLimits
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/api-keys/{id}/limits [delete]
+func (h *APIKeyHandler) DeleteAPIKeyLimits(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	h.respondLimits(c, id, nil, nil)
+}
+
+// GetAPIKeyUsage godoc
+//
+//	@Summary		Get API key usage
+//	@Description	Get the key's consumption and remaining allowance for the current minute and UTC day. Counters are kept per server instance.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"API key ID"
+//	@Success		200	{object}	services.APIKeyUsage
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/api-keys/{id}/usage [get]
+func (h *APIKeyHandler) GetAPIKeyUsage(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	usage, err := h.apiKeyService.GetUsage(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get API key usage")
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
+func (h *APIKeyHandler) respondLimits(c *gin.Context, id uuid.UUID, ratePerMinute, dailyQuota *int) {
+	limits, err := h.apiKeyService.SetLimits(c.Request.Context(), id, ratePerMinute, dailyQuota)
+	if err != nil {
+		respondError(c, err, "Failed to update API key limits")
+		return
+	}
+	c.JSON(http.StatusOK, limits)
+}
@@ -1,28 +1,44 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"backend/internal/application/services"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/signing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username string `json:"username" binding:"required" example:"jdoe"`
+	Password string `json:"password" binding:"required" example:"S3cure-pass"`
+	// CaptchaToken is the token of the CAPTCHA widget, sent when an earlier attempt was challenged
+	CaptchaToken string `json:"captcha_token" example:"03AFcWeA5m..."`
+	// RememberDevice trusts this device, so later logins from it skip MFA challenges
+	RememberDevice bool `json:"remember_device" example:"true"`
+	// DeviceToken is the token of a remembered device; the device cookie is used when it is empty
+	DeviceToken string `json:"device_token" example:"4f9c2a7e1b3d5f6a8c0e2b4d6f8a1c3e5b7d9f0a2c4e6b8d0f1a3c5e7b9d2f4a"`
+	// Scope narrows the token to these space-separated permissions of the user
+	Scope string `json:"scope" example:"users:read groups:read"`
 }
 
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  struct {
-		ID        string `json:"id"`
-		Username  string `json:"username"`
-		Email     string `json:"email"`
-		FirstName string `json:"first_name"`
-		LastName  string `json:"last_name"`
+	Token  string                     `json:"token"`
+	Scope  string                     `json:"scope,omitempty"` // the scopes granted, when some were requested
+	Risk   *services.RiskAssessment   `json:"risk"`
+	Device *services.RememberedDevice `json:"device,omitempty"` // set when remember_device was asked
+	User   struct {
+		ID        string  `json:"id"`
+		Username  string  `json:"username"`
+		Email     string  `json:"email"`
+		FirstName string  `json:"first_name"`
+		LastName  string  `json:"last_name"`
+		AvatarURL *string `json:"avatar_url" example:"https://cdn.example.com/avatars/jdoe.png"`
 		Role      struct {
 			ID          string                 `json:"id"`
 			Name        string                 `json:"name"`
@@ -37,65 +53,236 @@ type AuthResponse struct {
 	} `json:"user"`
 }
 
+type ChangeExpiredPasswordRequest struct {
+	ChangeToken string `json:"change_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	NewPassword string `json:"new_password" binding:"required" example:"N3w-S3cure-pass"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"S3cure-pass"`
+	NewPassword     string `json:"new_password" binding:"required" example:"N3w-S3cure-pass"`
+}
+
+type RevokeTokenRequest struct {
+	Token string `json:"token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+type PasswordlessStartRequest struct {
+	Email string `json:"email" binding:"required,email" example:"jane.doe@example.com"`
+}
+
+type PasswordlessVerifyRequest struct {
+	Email string `json:"email" example:"jane.doe@example.com"`
+	Code  string `json:"code" example:"123456"`
+	Token string `json:"token" example:"kq3l0Zt9vXo2bW7yR1sN8cH5dE4fG6aJ"`
+}
+
 type AuthHandler struct {
-	authService services.AuthService
+	authService    services.AuthService
+	introspection  services.TokenIntrospectionService
+	trustedDevices *config.TrustedDeviceConfig
 }
 
-func NewAuthHandler(authService services.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService services.AuthService, introspection services.TokenIntrospectionService, trustedDevices *config.TrustedDeviceConfig) *AuthHandler {
+	return &AuthHandler{authService: authService, introspection: introspection, trustedDevices: trustedDevices}
 }
 
 // Login godoc
 //
 //	@Summary		User login
-//	@Description	Authenticate user and return JWT token
+//	@Description	Authenticate user and return JWT token. Logins are risk-scored by client IP; depending on the domain's risk policy a risky login is rejected with 401 and a "challenge" field (captcha or mfa), or blocked with 403. A captcha challenge is answered by repeating the login with the token of the widget described in the domain's capabilities as captcha_token; a token the provider rejects returns the challenge again with code captcha_invalid. With remember_device the response carries a device token, also set as an HttpOnly cookie for this endpoint; later logins of the same user presenting it, as device_token or through the cookie, skip MFA challenges. Users list and revoke their devices at /auth/devices. A scope, space-separated as in OAuth, narrows the token to those of the user's permissions and role claims it holds, returned in scope; routes guarded by a scope reject tokens narrowed to others with 403 and code insufficient_scope. If the user holds none of the requested scopes the login is rejected with 400 and code invalid_scope. Passwordless domains reject password login with 403, as do disabled accounts and accounts past their end date. A password older than the domain's max_age_days is rejected with 403, code password_expired and a short-lived change_token for /auth/change-expired-password. Unknown usernames and wrong passwords get the same 401, and failed logins take at least 250ms, so neither tells whether an account exists.
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
-//	@Param			X-NRM-DID	header		string				true	"Domain ID"
-//	@Param			credentials	body		LoginRequest		true	"Login credentials"
+//	@Param			X-NRM-DID		header		string				false	"Domain ID (required unless X-NRM-Domain is set)"
+//	@Param			X-NRM-Domain	header		string				false	"Domain hostname or alias, used when X-NRM-DID is absent"
+//	@Param			credentials		body		LoginRequest		true	"Login credentials"
 //	@Success		200			{object}	AuthResponse
-//	@Failure		400			{object}	map[string]string
-//	@Failure		401			{object}	map[string]string
-//	@Failure		500			{object}	map[string]string
-//	@Router			/auth/login [post]
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ChallengeResponse
+//	@Failure		403			{object}	PasswordExpiredResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
-	domainIdStr := c.GetHeader("X-NRM-DID")
-	if domainIdStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "X-NRM-DID header is required"})
+	domainID, ok := h.resolveLoginDomain(c)
+	if !ok {
+		return
+	}
+
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	domainID, err := uuid.Parse(domainIdStr)
+	opts := services.LoginOptions{
+		CaptchaToken:   req.CaptchaToken,
+		DeviceToken:    req.DeviceToken,
+		RememberDevice: req.RememberDevice,
+		DeviceName:     c.Request.UserAgent(),
+		Scopes:         services.ParseScope(req.Scope),
+	}
+	if opts.DeviceToken == "" {
+		opts.DeviceToken, _ = c.Cookie(deviceCookieName(domainID))
+	}
+	loginResp, err := h.authService.Login(services.WithUserAgent(c.Request.Context(), c.Request.UserAgent()), domainID, req.Username, req.Password, c.ClientIP(), opts)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain UUID in X-NRM-DID header"})
+		respondLoginError(c, err, "Login failed")
 		return
 	}
 
-	var req LoginRequest
+	if device := loginResp.Device; device != nil {
+		// Like the hosted session cookie, SameSite=None lets SPAs on other sites send it
+		sameSite := http.SameSiteLaxMode
+		if h.trustedDevices.CookieSecure {
+			sameSite = http.SameSiteNoneMode
+		}
+		c.SetSameSite(sameSite)
+		// Scoped to the login route the client called, so each API version gets the cookie back
+		c.SetCookie(deviceCookieName(domainID), device.Token, int(time.Until(device.ExpiresAt).Seconds()), c.FullPath(), "", h.trustedDevices.CookieSecure, true)
+	}
+	c.JSON(http.StatusOK, newAuthResponse(loginResp))
+}
+
+// ChangeExpiredPassword godoc
+//
+//	@Summary		Change an expired password
+//	@Description	Set a new password with the change_token returned by a login whose password had expired, and complete the login. The new password must meet the domain's password policy and may not repeat a recent password. Each change token works once and expires after 10 minutes.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ChangeExpiredPasswordRequest	true	"Change token and new password"
+//	@Success		200		{object}	AuthResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/auth/change-expired-password [post]
+func (h *AuthHandler) ChangeExpiredPassword(c *gin.Context) {
+	var req ChangeExpiredPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	loginResp, err := h.authService.ChangeExpiredPassword(c.Request.Context(), req.ChangeToken, req.NewPassword)
+	if err != nil {
+		respondError(c, err, "Failed to change password")
+		return
+	}
+	c.JSON(http.StatusOK, newAuthResponse(loginResp))
+}
+
+// StartPasswordless godoc
+//
+//	@Summary		Start passwordless login
+//	@Description	Email a one-time code and magic link to the user of a passwordless domain. The response, and how long it takes, is the same whether or not the email is registered.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-NRM-DID		header		string						false	"Domain ID (required unless X-NRM-Domain is set)"
+//	@Param			X-NRM-Domain	header		string						false	"Domain hostname or alias, used when X-NRM-DID is absent"
+//	@Param			request			body		PasswordlessStartRequest	true	"User email"
+//	@Success		202				{object}	MessageResponse
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ChallengeResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/passwordless/start [post]
+func (h *AuthHandler) StartPasswordless(c *gin.Context) {
+	domainID, ok := h.resolveLoginDomain(c)
+	if !ok {
+		return
+	}
+
+	var req PasswordlessStartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.authService.StartPasswordlessLogin(c.Request.Context(), domainID, req.Email, c.ClientIP()); err != nil {
+		respondLoginError(c, err, "Passwordless login failed")
+		return
+	}
+	c.JSON(http.StatusAccepted, MessageResponse{Message: "If the email is registered, a login code has been sent"})
+}
+
+// VerifyPasswordless godoc
+//
+//	@Summary		Complete passwordless login
+//	@Description	Exchange the emailed code (with the email) or the magic link token for a JWT token. Codes are single-use and expire; repeated wrong codes lock the code.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-NRM-DID		header		string						false	"Domain ID (required unless X-NRM-Domain is set)"
+//	@Param			X-NRM-Domain	header		string						false	"Domain hostname or alias, used when X-NRM-DID is absent"
+//	@Param			request			body		PasswordlessVerifyRequest	true	"Email and code, or magic link token"
+//	@Success		200				{object}	AuthResponse
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ChallengeResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/passwordless/verify [post]
+func (h *AuthHandler) VerifyPasswordless(c *gin.Context) {
+	domainID, ok := h.resolveLoginDomain(c)
+	if !ok {
+		return
+	}
+
+	var req PasswordlessVerifyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	loginResp, err := h.authService.Login(domainID, req.Username, req.Password)
+	loginResp, err := h.authService.VerifyPasswordlessLogin(services.WithUserAgent(c.Request.Context(), c.Request.UserAgent()), domainID, req.Email, req.Code, req.Token, c.ClientIP())
 	if err != nil {
-		if strings.Contains(err.Error(), "invalid credentials") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
-			return
+		respondLoginError(c, err, "Passwordless login failed")
+		return
+	}
+	c.JSON(http.StatusOK, newAuthResponse(loginResp))
+}
+
+// respondLoginError answers a risk challenge with a ChallengeResponse and an expired password with
+// a PasswordExpiredResponse, and hands any other error to the error middleware.
+func respondLoginError(c *gin.Context, err error, fallback string) {
+	var challenge *services.LoginChallengeError
+	if errors.As(err, &challenge) {
+		response := ChallengeResponse{Error: "Additional verification required", Challenge: challenge.Risk.Action, RiskScore: challenge.Risk.Score}
+		if challenge.Rejected {
+			response.Error, response.Code = "CAPTCHA verification failed", "captcha_invalid"
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Login failed"})
+		c.JSON(http.StatusUnauthorized, response)
 		return
 	}
+	var expired *services.PasswordExpiredError
+	if errors.As(err, &expired) {
+		c.JSON(http.StatusForbidden, PasswordExpiredResponse{Error: "Password expired", Code: "password_expired", ChangeToken: expired.ChangeToken, ExpiresAt: expired.ExpiresAt})
+		return
+	}
+	respondError(c, err, fallback)
+}
 
-	response := AuthResponse{
-		Token: loginResp.AccessToken,
+// deviceCookieName is per domain, like the hosted session cookie, so a browser can be trusted by
+// several domains served from the same host.
+func deviceCookieName(domainID uuid.UUID) string {
+	return "nrm_device_" + domainID.String()
+}
+
+func newAuthResponse(loginResp *services.LoginResponse) *AuthResponse {
+	response := &AuthResponse{
+		Token:  loginResp.AccessToken,
+		Scope:  loginResp.Scope,
+		Risk:   loginResp.Risk,
+		Device: loginResp.Device,
 	}
 	response.User.ID = loginResp.User.ID.String()
 	response.User.Username = loginResp.User.Username
 	response.User.Email = loginResp.User.Email
 	response.User.FirstName = loginResp.User.FirstName
 	response.User.LastName = loginResp.User.LastName
+	response.User.AvatarURL = loginResp.User.AvatarURL
 	response.User.Role.ID = loginResp.User.Role.ID.String()
 	response.User.Role.Name = loginResp.User.Role.Name
 	response.User.Role.Description = loginResp.User.Role.Description
@@ -104,44 +291,71 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	response.User.Domain.Name = loginResp.User.Domain.Name
 	response.User.Domain.Description = loginResp.User.Domain.Description
 
-	c.JSON(http.StatusOK, response)
+	return response
 }
 
 // ValidateToken godoc
 //
 //	@Summary		Validate JWT token
-//	@Description	Validate JWT token and return user information
+//	@Description	Validate JWT token and return user information. Tokens of disabled accounts, tokens revoked through /auth/revoke, and tokens issued before the account's sessions were revoked, are rejected. Results are cached briefly (INTROSPECTION_CACHE_TTL, INTROSPECTION_NEGATIVE_CACHE_TTL), so a disabled account or revoked session may still validate for a few seconds. Instead of the per-IP and per-user limits, this endpoint is limited per client: per API key when X-API-Key is sent, per IP otherwise; over the limit it returns 429 with code rate_limited and Retry-After.
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
-//	@Param			Authorization	header		string	true	"Bearer token"
-//	@Success		200				{object}	map[string]interface{}
-//	@Failure		401				{object}	map[string]string
-//	@Failure		500				{object}	map[string]string
-//	@Router			/auth/validate [post]
+//	@Security		BearerAuth
+//	@Param			X-API-Key		header		string	false	"API key identifying the calling gateway for rate limiting"
+//	@Success		200				{object}	TokenValidationResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		429				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/validate [post]
 func (h *AuthHandler) ValidateToken(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Authorization header is required"})
 		return
 	}
 
 	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 	if tokenString == authHeader {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid authorization header format"})
 		return
 	}
 
-	claims, err := h.authService.ValidateToken(tokenString)
+	claims, err := h.introspection.Introspect(c.Request.Context(), tokenString)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenValidationResponse{Valid: true, Claims: claims})
+}
+
+// RevokeToken godoc
+//
+//	@Summary		Revoke an access token
+//	@Description	Revoke an access token before it expires, e.g. on logout; /auth/validate and every authenticated endpoint reject it afterwards. Only the given token is revoked. Revoking an invalid, expired or already revoked token succeeds without effect. All of a user's tokens are revoked automatically when their password changes, their account is suspended by setting its end date to now or earlier, or they lose a role or group.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		RevokeTokenRequest	true	"Token to revoke"
+//	@Success		200		{object}	MessageResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/auth/revoke [post]
+func (h *AuthHandler) RevokeToken(c *gin.Context) {
+	var req RevokeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"valid":  true,
-		"claims": claims,
-	})
+	if err := h.authService.RevokeToken(c.Request.Context(), req.Token); err != nil {
+		respondError(c, err, "Failed to revoke token")
+		return
+	}
+	h.introspection.Forget(c.Request.Context(), req.Token)
+	c.JSON(http.StatusOK, MessageResponse{Message: "Token revoked successfully"})
 }
 
 // GetProfile godoc
@@ -151,56 +365,194 @@ func (h *AuthHandler) ValidateToken(c *gin.Context) {
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
-//	@Param			Authorization	header		string	true	"Bearer token"
-//	@Success		200				{object}	map[string]interface{}
-//	@Failure		401				{object}	map[string]string
-//	@Failure		500				{object}	map[string]string
-//	@Router			/auth/profile [get]
+//	@Security		BearerAuth
+//	@Success		200				{object}	services.UserProfile
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/profile [get]
 func (h *AuthHandler) GetProfile(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Authorization header is required"})
 		return
 	}
 
 	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 	if tokenString == authHeader {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid authorization header format"})
 		return
 	}
 
 	// Validate token and get claims
-	claims, err := h.authService.ValidateToken(tokenString)
+	claims, err := h.authService.ValidateToken(c.Request.Context(), tokenString)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired token"})
 		return
 	}
 
 	// Get user profile using user ID from token
-	user, err := h.authService.GetProfile(claims.UserID)
+	user, err := h.authService.GetProfile(c.Request.Context(), claims.UserID)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
-		return
-	}
-
-	profile := map[string]interface{}{
-		"id":         user.ID,
-		"username":   user.Username,
-		"email":      user.Email,
-		"first_name": user.FirstName,
-		"last_name":  user.LastName,
-		"role": map[string]interface{}{
-			"id":          user.Role.ID,
-			"name":        user.Role.Name,
-			"description": user.Role.Description,
-			"claims":      user.Role.Claims,
-		},
-		"domain": map[string]interface{}{
-			"id":          user.Domain.ID,
-			"name":        user.Domain.Name,
-			"description": user.Domain.Description,
-		},
-	}
-
-	c.JSON(http.StatusOK, profile)
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Change own password
+//	@Description	Change the authenticated user's password after verifying the current one. The new password must meet the domain's password policy and may not repeat a recent password. All of the user's tokens, including the one used for this request, are revoked, so the user signs in again. Administrators reset other users' passwords with POST /users/{id}/reset-password instead. Break-glass accounts are rotated by platform operators and get 403.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request			body		ChangePasswordRequest	true	"Current and new password"
+//	@Success		200				{object}	MessageResponse
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/change-password [post]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Authorization header is required"})
+		return
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid authorization header format"})
+		return
+	}
+
+	claims, err := h.authService.ValidateToken(c.Request.Context(), tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired token"})
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.authService.ChangePassword(c.Request.Context(), claims.UserID, req.CurrentPassword, req.NewPassword); err != nil {
+		respondError(c, err, "Failed to change password")
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: "Password changed successfully"})
+}
+
+// GetPermissions godoc
+//
+//	@Summary		Get effective permissions
+//	@Description	Get the authenticated user's effective permission set, combining role claims and catalog permissions assigned to the role
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200				{object}	services.EffectivePermissions
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/permissions [get]
+func (h *AuthHandler) GetPermissions(c *gin.Context) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Authorization header is required"})
+		return
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid authorization header format"})
+		return
+	}
+
+	claims, err := h.authService.ValidateToken(c.Request.Context(), tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired token"})
+		return
+	}
+
+	permissions, err := h.authService.GetEffectivePermissions(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondError(c, err, "Failed to resolve permissions")
+		return
+	}
+	c.JSON(http.StatusOK, permissions)
+}
+
+// GetCapabilities godoc
+//
+//	@Summary		Discover domain login capabilities
+//	@Description	Report the login features enabled for a domain (password policy, passwordless methods, risk challenges, MFA, federation and SCIM) so client apps can adapt their UI. MFA methods, federation and SCIM are not offered by this server and are always reported as disabled.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			X-NRM-DID		header		string	false	"Domain ID (required unless X-NRM-Domain is set)"
+//	@Param			X-NRM-Domain	header		string	false	"Domain hostname or alias, used when X-NRM-DID is absent"
+//	@Success		200				{object}	services.Capabilities
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/.well-known/iam-capabilities [get]
+func (h *AuthHandler) GetCapabilities(c *gin.Context) {
+	domainID, ok := h.resolveLoginDomain(c)
+	if !ok {
+		return
+	}
+
+	capabilities, err := h.authService.GetCapabilities(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to get capabilities")
+		return
+	}
+	c.JSON(http.StatusOK, capabilities)
+}
+
+// GetJWKS godoc
+//
+//	@Summary		Token signing public keys
+//	@Description	Publish the public keys access tokens are signed with as a JSON Web Key Set, so resource servers can validate tokens offline by matching the token's kid header. Keys replaced by a rotation are listed after the current one while their tokens may still be valid. The set is empty when tokens are signed with a shared HS256 secret.
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	signing.JWKSet
+//	@Router			/.well-known/jwks.json [get]
+func (h *AuthHandler) GetJWKS(c *gin.Context) {
+	var keys signing.JWKSet = h.authService.JWKS()
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *AuthHandler) resolveLoginDomain(c *gin.Context) (uuid.UUID, bool) {
+	return resolveLoginDomain(c, h.authService)
+}
+
+// resolveLoginDomain reads the tenant from X-NRM-DID, falling back to a hostname in X-NRM-Domain.
+func resolveLoginDomain(c *gin.Context, authService services.AuthService) (uuid.UUID, bool) {
+	domainIdStr := c.GetHeader("X-NRM-DID")
+	if domainIdStr != "" {
+		domainID, err := uuid.Parse(domainIdStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID in X-NRM-DID header"})
+			return uuid.Nil, false
+		}
+		return domainID, true
+	}
+
+	hostname := c.GetHeader("X-NRM-Domain")
+	if hostname == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "X-NRM-DID or X-NRM-Domain header is required"})
+		return uuid.Nil, false
+	}
+
+	domainID, err := authService.ResolveDomainID(c.Request.Context(), hostname)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unknown domain in X-NRM-Domain header"})
+		return uuid.Nil, false
+	}
+	return domainID, true
 }
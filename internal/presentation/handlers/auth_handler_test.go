@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/application/services"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/config"
+	"backend/internal/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// fakeAuth accepts the password "secret" for jdoe in any domain and resolves the hostname
+// tenant.example.com. The user risky is challenged and the user expired has an expired password.
+type fakeAuth struct {
+	services.AuthService
+	tenantID uuid.UUID
+
+	// the arguments of the last login
+	domainID uuid.UUID
+	clientIP string
+	opts     services.LoginOptions
+}
+
+func (f *fakeAuth) Login(ctx context.Context, domainID uuid.UUID, username, password, clientIP string, opts services.LoginOptions) (*services.LoginResponse, error) {
+	f.domainID, f.clientIP, f.opts = domainID, clientIP, opts
+	switch username {
+	case "risky":
+		return nil, &services.LoginChallengeError{Risk: &services.RiskAssessment{Score: 70, Action: "captcha"}, Rejected: opts.CaptchaToken != ""}
+	case "expired":
+		return nil, &services.PasswordExpiredError{ChangeToken: "change-token", ExpiresAt: time.Now().Add(10 * time.Minute)}
+	}
+	if username != "jdoe" || password != "secret" {
+		return nil, domainerrors.Unauthorized("invalid credentials")
+	}
+	response := &services.LoginResponse{
+		AccessToken: "access-token",
+		Risk:        &services.RiskAssessment{Action: "allow"},
+		User: &services.UserProfile{
+			ID:       uuid.New(),
+			Username: username,
+			Role:     &services.RoleProfile{ID: uuid.New(), Name: "member"},
+			Domain:   &services.DomainProfile{ID: domainID, Name: "Tenant"},
+		},
+	}
+	if opts.RememberDevice {
+		response.Device = &services.RememberedDevice{ID: uuid.New(), Token: "device-token", ExpiresAt: time.Now().Add(time.Hour)}
+	}
+	return response, nil
+}
+
+func (f *fakeAuth) ResolveDomainID(ctx context.Context, hostname string) (uuid.UUID, error) {
+	if hostname != "tenant.example.com" {
+		return uuid.Nil, domainerrors.NotFound("domain not found")
+	}
+	return f.tenantID, nil
+}
+
+// fakeIntrospection accepts the token "valid".
+type fakeIntrospection struct {
+	services.TokenIntrospectionService
+}
+
+func (fakeIntrospection) Introspect(ctx context.Context, token string) (*services.TokenClaims, error) {
+	if token != "valid" {
+		return nil, domainerrors.Unauthorized("invalid token")
+	}
+	return &services.TokenClaims{UserID: uuid.New(), DomainID: uuid.New()}, nil
+}
+
+func newAuthRouter(auth *fakeAuth) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewAuthHandler(auth, fakeIntrospection{}, &config.TrustedDeviceConfig{TTL: time.Hour, CookieSecure: true})
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	for _, group := range []*gin.RouterGroup{r.Group("/api/v1"), r.Group("/api/v2", middleware.Envelope())} {
+		group.POST("/auth/login", handler.Login)
+		group.POST("/auth/validate", handler.ValidateToken)
+	}
+	return r
+}
+
+func serve(r *gin.Engine, method, path string, headers map[string]string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "192.0.2.10:5555"
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestLogin(t *testing.T) {
+	domainID := uuid.New()
+	withDomain := map[string]string{"X-NRM-DID": domainID.String()}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		body     string
+		want     int
+		wantBody map[string]interface{}
+	}{
+		{"no domain", nil, `{"username":"jdoe","password":"secret"}`, http.StatusBadRequest,
+			map[string]interface{}{"error": "X-NRM-DID or X-NRM-Domain header is required"}},
+		{"malformed domain ID", map[string]string{"X-NRM-DID": "tenant"}, `{"username":"jdoe","password":"secret"}`, http.StatusBadRequest,
+			map[string]interface{}{"error": "Invalid domain UUID in X-NRM-DID header"}},
+		{"unknown hostname", map[string]string{"X-NRM-Domain": "other.example.com"}, `{"username":"jdoe","password":"secret"}`, http.StatusBadRequest,
+			map[string]interface{}{"error": "Unknown domain in X-NRM-Domain header"}},
+		{"missing password", withDomain, `{"username":"jdoe"}`, http.StatusBadRequest, nil},
+		{"wrong password", withDomain, `{"username":"jdoe","password":"guess"}`, http.StatusUnauthorized,
+			map[string]interface{}{"error": "Invalid credentials", "code": "unauthorized"}},
+		{"risky login", withDomain, `{"username":"risky","password":"secret"}`, http.StatusUnauthorized,
+			map[string]interface{}{"error": "Additional verification required", "challenge": "captcha", "risk_score": 70.0}},
+		{"rejected captcha", withDomain, `{"username":"risky","password":"secret","captcha_token":"bad"}`, http.StatusUnauthorized,
+			map[string]interface{}{"error": "CAPTCHA verification failed", "challenge": "captcha", "code": "captcha_invalid"}},
+		{"expired password", withDomain, `{"username":"expired","password":"secret"}`, http.StatusForbidden,
+			map[string]interface{}{"code": "password_expired", "change_token": "change-token"}},
+		{"success", withDomain, `{"username":"jdoe","password":"secret"}`, http.StatusOK,
+			map[string]interface{}{"token": "access-token"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(newAuthRouter(&fakeAuth{}), http.MethodPost, "/api/v1/auth/login", tt.headers, tt.body)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			for field, want := range tt.wantBody {
+				if body[field] != want {
+					t.Errorf("%s = %v, want %v", field, body[field], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoginPassesRequest(t *testing.T) {
+	tenantID := uuid.New()
+	auth := &fakeAuth{tenantID: tenantID}
+	w := serve(newAuthRouter(auth), http.MethodPost, "/api/v1/auth/login",
+		map[string]string{"X-NRM-Domain": "tenant.example.com"},
+		`{"username":"jdoe","password":"secret","scope":"users:read groups:read","remember_device":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if auth.domainID != tenantID {
+		t.Errorf("domain = %s, want the one of the hostname %s", auth.domainID, tenantID)
+	}
+	if auth.clientIP != "192.0.2.10" {
+		t.Errorf("client IP = %q, want 192.0.2.10", auth.clientIP)
+	}
+	if strings.Join(auth.opts.Scopes, " ") != "users:read groups:read" {
+		t.Errorf("scopes = %v", auth.opts.Scopes)
+	}
+
+	var response AuthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.User.Role.Name != "member" || response.User.Domain.ID != tenantID.String() {
+		t.Errorf("user = %+v", response.User)
+	}
+	if response.Device == nil || response.Device.Token != "device-token" {
+		t.Fatalf("device = %+v, want the remembered device", response.Device)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != deviceCookieName(tenantID) || cookies[0].Value != "device-token" || !cookies[0].HttpOnly || !cookies[0].Secure {
+		t.Fatalf("cookies = %+v, want a secure HttpOnly device cookie", cookies)
+	}
+
+	// The device cookie is sent back on the next login
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(`{"username":"jdoe","password":"secret"}`))
+	req.Header.Set("X-NRM-DID", tenantID.String())
+	req.AddCookie(cookies[0])
+	newAuthRouter(auth).ServeHTTP(httptest.NewRecorder(), req)
+	if auth.opts.DeviceToken != "device-token" {
+		t.Errorf("device token = %q, want the cookie's", auth.opts.DeviceToken)
+	}
+}
+
+func TestDeviceCookiePath(t *testing.T) {
+	for _, path := range []string{"/api/v1/auth/login", "/api/v2/auth/login"} {
+		tenantID := uuid.New()
+		w := serve(newAuthRouter(&fakeAuth{}), http.MethodPost, path, map[string]string{"X-NRM-DID": tenantID.String()},
+			`{"username":"jdoe","password":"secret","remember_device":true}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", path, w.Code, w.Body.String())
+		}
+		if cookies := w.Result().Cookies(); len(cookies) != 1 || cookies[0].Path != path {
+			t.Errorf("%s: cookies = %+v, want the device cookie scoped to the route", path, cookies)
+		}
+	}
+}
+
+func TestLoginV2Envelope(t *testing.T) {
+	r := newAuthRouter(&fakeAuth{})
+	headers := map[string]string{"X-NRM-DID": uuid.NewString()}
+
+	w := serve(r, http.MethodPost, "/api/v2/auth/login", headers, `{"username":"jdoe","password":"secret"}`)
+	var success struct {
+		Data AuthResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &success); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if success.Data.Token != "access-token" {
+		t.Errorf("data.token = %q, want access-token", success.Data.Token)
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		want        int
+		wantCode    string
+		wantDetails string
+	}{
+		{"domain error", `{"username":"jdoe","password":"guess"}`, http.StatusUnauthorized, "unauthorized", ""},
+		{"challenge", `{"username":"risky","password":"secret"}`, http.StatusUnauthorized, "unauthorized", "challenge"},
+		{"expired password", `{"username":"expired","password":"secret"}`, http.StatusForbidden, "password_expired", "change_token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodPost, "/api/v2/auth/login", headers, tt.body)
+			var failure struct {
+				Error struct {
+					Code    string                 `json:"code"`
+					Message string                 `json:"message"`
+					Details map[string]interface{} `json:"details"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &failure); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != tt.want || failure.Error.Code != tt.wantCode || failure.Error.Message == "" {
+				t.Errorf("got %d %s", w.Code, w.Body.String())
+			}
+			if tt.wantDetails != "" && failure.Error.Details[tt.wantDetails] == nil {
+				t.Errorf("details = %v, want %s", failure.Error.Details, tt.wantDetails)
+			}
+		})
+	}
+}
+
+func TestValidateToken(t *testing.T) {
+	r := newAuthRouter(&fakeAuth{})
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{"no header", "", http.StatusUnauthorized},
+		{"not a bearer token", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+		{"invalid token", "Bearer forged", http.StatusUnauthorized},
+		{"valid token", "Bearer valid", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{}
+			if tt.authorization != "" {
+				headers["Authorization"] = tt.authorization
+			}
+			w := serve(r, http.MethodPost, "/api/v1/auth/validate", headers, "")
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/application/services"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CheckRequest struct {
+	UserID   string `json:"user_id" binding:"required" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Resource string `json:"resource" binding:"required" example:"documents"`
+	Action   string `json:"action" binding:"required" example:"read"`
+}
+
+type SimulateRequest struct {
+	RoleID      string                 `json:"role_id" binding:"required" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	RoleClaims  map[string]interface{} `json:"role_claims"`
+	Permissions []string               `json:"permissions" example:"documents:read,documents:write"`
+	WindowHours int                    `json:"window_hours" minimum:"0" example:"168"`
+	Limit       int                    `json:"limit" minimum:"0" maximum:"10000" example:"1000"`
+}
+
+// AccessSimulationRequest names either a user or a role and the checks to evaluate for it.
+type AccessSimulationRequest struct {
+	UserID  string                 `json:"user_id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	RoleID  string                 `json:"role_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Checks  []AccessCheckRequest   `json:"checks" binding:"required,min=1,dive"`
+	Context map[string]interface{} `json:"context"`
+}
+
+type AccessCheckRequest struct {
+	Resource           string                 `json:"resource" binding:"required" example:"documents"`
+	Action             string                 `json:"action" binding:"required" example:"read"`
+	ResourceAttributes map[string]interface{} `json:"resource_attributes"`
+}
+
+type AuthzHandler struct {
+	authzService services.AuthzService
+}
+
+func NewAuthzHandler(authzService services.AuthzService) *AuthzHandler {
+	return &AuthzHandler{authzService: authzService}
+}
+
+// WhoCan godoc
+//
+//	@Summary		Who can access a resource
+//	@Description	List users in a domain whose effective claims allow the action on the resource. Claims match as resource:action, resource:*, *:action, *:* or *.
+//	@Tags			authz
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	query		string	true	"Domain ID"
+//	@Param			resource	query		string	true	"Resource name, e.g. users"
+//	@Param			action		query		string	true	"Action name, e.g. write"
+//	@Param			page		query		int		false	"Page number"	minimum(1)	default(1)
+//	@Param			limit		query		int		false	"Items per page"	minimum(1)	maximum(100)	default(10)
+//	@Success		200			{object}	UserListResponse
+//	@Header			200			{string}	Link	"Links to the first, previous, next and last pages (RFC 5988)"
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/authz/who-can [get]
+func (h *AuthzHandler) WhoCan(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Query("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	result, err := h.authzService.WhoCan(c.Request.Context(), domainID, c.Query("resource"), c.Query("action"), page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to resolve who can access the resource")
+		return
+	}
+	c.JSON(http.StatusOK, UserListResponse{UserListResult: result, Links: pageLinks(c, result.Page, result.Limit, result.TotalPages)})
+}
+
+// Check godoc
+//
+//	@Summary		Check an authorization decision
+//	@Description	Evaluate whether a user may perform an action on a resource. The caller must be an admin of the user's domain. The decision is recorded for simulation and audit according to the AUTHZ_DECISION_LOG_* sampling settings.
+//	@Tags			authz
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			check	body		CheckRequest	true	"Principal, resource and action"
+//	@Success		200		{object}	entities.AuthzDecision
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/authz/check [post]
+func (h *AuthzHandler) Check(c *gin.Context) {
+	var req CheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user UUID"})
+		return
+	}
+
+	decision, err := h.authzService.Check(c.Request.Context(), userID, req.Resource, req.Action)
+	if err != nil {
+		respondError(c, err, "Failed to evaluate authorization")
+		return
+	}
+	c.JSON(http.StatusOK, decision)
+}
+
+// Simulate godoc
+//
+//	@Summary		Simulate a policy change
+//	@Description	Replay a role's recently allowed decisions against hypothetical role claims and/or catalog permissions and report which would now be denied. Omitted fields keep their current value; nothing is saved.
+//	@Tags			authz
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			simulation	body		SimulateRequest	true	"Hypothetical role change"
+//	@Success		200			{object}	services.SimulationResult
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/authz/simulate [post]
+func (h *AuthzHandler) Simulate(c *gin.Context) {
+	var req SimulateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	roleID, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+
+	window := time.Duration(req.WindowHours) * time.Hour
+	result, err := h.authzService.Simulate(c.Request.Context(), roleID, req.RoleClaims, req.Permissions, window, req.Limit)
+	if err != nil {
+		respondError(c, err, "Failed to simulate policy change")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListDecisions godoc
+//
+//	@Summary		List authorization decisions
+//	@Description	Get logged /authz/check decisions of a domain, newest first. Only sampled decisions are present when sampling is enabled.
+//	@Tags			authz
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	query		string	true	"Domain ID"
+//	@Param			userId		query		string	false	"Filter by principal user ID"
+//	@Param			resource	query		string	false	"Filter by resource"
+//	@Param			action		query		string	false	"Filter by action"
+//	@Param			allowed		query		bool	false	"Filter by result"
+//	@Param			page		query		int		false	"Page number"	minimum(1)	default(1)
+//	@Param			limit		query		int		false	"Items per page"	minimum(1)	maximum(100)	default(10)
+//	@Success		200			{object}	AuthzDecisionListResponse
+//	@Header			200			{string}	Link	"Links to the first, previous, next and last pages (RFC 5988)"
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/authz/decisions [get]
+func (h *AuthzHandler) ListDecisions(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Query("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	filter := repositories.AuthzDecisionFilter{
+		DomainID: domainID,
+		Resource: c.Query("resource"),
+		Action:   c.Query("action"),
+	}
+
+	if userIdStr := c.Query("userId"); userIdStr != "" {
+		filter.UserID, err = uuid.Parse(userIdStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user UUID"})
+			return
+		}
+	}
+
+	if allowedStr := c.Query("allowed"); allowedStr != "" {
+		allowed, err := strconv.ParseBool(allowedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid allowed value"})
+			return
+		}
+		filter.Allowed = &allowed
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	result, err := h.authzService.ListDecisions(c.Request.Context(), filter, page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to list authorization decisions")
+		return
+	}
+	c.JSON(http.StatusOK, AuthzDecisionListResponse{AuthzDecisionListResult: result, Links: pageLinks(c, result.Page, result.Limit, result.TotalPages)})
+}
+
+// SimulateAccess godoc
+//
+//	@Summary		Dry-run permission evaluation
+//	@Description	Evaluate a list of actions on resources for a user, or for a holder of a role, without the user having to try them. Send exactly one of user_id and role_id. Each result carries the claims decision as /authz/check makes it, with the claim that matched (matched_rule) and the role granting it, and the decision of the domain's ABAC policies as /auth/authorize makes it, with the policies that matched. A role is evaluated on its own, as if held by a user with no groups. Nothing is recorded in the decision log. At most 100 checks per request.
+//	@Tags			authz
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			simulation	body		AccessSimulationRequest	true	"Subject and checks"
+//	@Success		200			{object}	services.AccessSimulationResult
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/auth/simulate [post]
+func (h *AuthzHandler) SimulateAccess(c *gin.Context) {
+	var req AccessSimulationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	simulation := &services.AccessSimulationRequest{Context: req.Context}
+	if req.UserID != "" {
+		userID, err := uuid.Parse(req.UserID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user UUID"})
+			return
+		}
+		simulation.UserID = &userID
+	}
+	if req.RoleID != "" {
+		roleID, err := uuid.Parse(req.RoleID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+			return
+		}
+		simulation.RoleID = &roleID
+	}
+	for _, check := range req.Checks {
+		simulation.Checks = append(simulation.Checks, services.AccessCheck{
+			Resource:           check.Resource,
+			Action:             check.Action,
+			ResourceAttributes: check.ResourceAttributes,
+		})
+	}
+
+	result, err := h.authzService.SimulateAccess(c.Request.Context(), simulation)
+	if err != nil {
+		respondError(c, err, "Failed to simulate access")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
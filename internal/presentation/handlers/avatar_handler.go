@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AvatarHandler struct {
+	avatarService services.AvatarService
+	masking       services.DataMaskingService
+}
+
+func NewAvatarHandler(avatarService services.AvatarService, masking services.DataMaskingService) *AvatarHandler {
+	return &AvatarHandler{avatarService: avatarService, masking: masking}
+}
+
+// UploadAvatar godoc
+//
+//	@Summary		Upload a user's avatar
+//	@Description	Set a user's avatar from a JPEG, PNG or GIF image. The image is cropped to its centre square and scaled down to the configured size (AVATAR_SIZE, 256 pixels by default); JPEG uploads are stored as JPEG, others as PNG. The previous avatar is removed and the returned user carries the new avatar_url. Files over AVATAR_MAX_BYTES are rejected with code avatar_too_large, anything that isn't a supported image with code invalid_avatar.
+//	@Tags			users
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string	true	"User ID"
+//	@Param			avatar	formData	file	true	"Image file"
+//	@Success		200		{object}	entities.User
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		413		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/users/{id}/avatar [post]
+func (h *AvatarHandler) UploadAvatar(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Avatar file is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to read avatar file"})
+		return
+	}
+	defer file.Close()
+
+	user, err := h.avatarService.SetAvatar(c.Request.Context(), id, file)
+	if err != nil {
+		respondError(c, err, "Failed to upload avatar")
+		return
+	}
+	respondMaskedUser(c, h.masking, http.StatusOK, user)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateBreakGlassAccountRequest struct {
+	RoleID    string `json:"role_id" binding:"required" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	FirstName string `json:"first_name" binding:"required" example:"Emergency"`
+	LastName  string `json:"last_name" binding:"required" example:"Access"`
+	Username  string `json:"username" binding:"required" example:"breakglass-1"`
+	Email     string `json:"email" binding:"required,email" example:"security-oncall@example.com"`
+	Password  string `json:"password" binding:"required,min=16" example:"correct-horse-battery-staple"`
+}
+
+type RotateBreakGlassPasswordRequest struct {
+	Password string `json:"password" binding:"required,min=16" example:"correct-horse-battery-staple"`
+}
+
+// BreakGlassHandler serves the platform operator endpoints for emergency access accounts.
+type BreakGlassHandler struct {
+	userService services.UserService
+}
+
+func NewBreakGlassHandler(userService services.UserService) *BreakGlassHandler {
+	return &BreakGlassHandler{userService: userService}
+}
+
+// ListBreakGlassAccounts godoc
+//
+//	@Summary		List break-glass accounts
+//	@Description	Get the break-glass emergency access accounts of every domain. Platform operators only.
+//	@Tags			break-glass
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Success		200	{array}		entities.User
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/operator/break-glass-accounts [get]
+func (h *BreakGlassHandler) ListBreakGlassAccounts(c *gin.Context) {
+	users, err := h.userService.ListBreakGlassAccounts(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to list break-glass accounts")
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// CreateBreakGlassAccount godoc
+//
+//	@Summary		Create a break-glass account
+//	@Description	Create an emergency access account in the domain. It signs in with its local password even in passwordless domains, skips CAPTCHA and MFA challenges, gets short-lived sessions and alerts on every sign-in. Platform operators only.
+//	@Tags			break-glass
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Param			domainId	path		string							true	"Domain ID"
+//	@Param			account		body		CreateBreakGlassAccountRequest	true	"Account data"
+//	@Success		201			{object}	entities.User
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/operator/domains/{domainId}/break-glass-accounts [post]
+func (h *BreakGlassHandler) CreateBreakGlassAccount(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req CreateBreakGlassAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	roleID, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+
+	user, err := h.userService.CreateBreakGlassAccount(c.Request.Context(), domainID, roleID, req.FirstName, req.LastName, req.Username, req.Email, req.Password)
+	if err != nil {
+		respondError(c, err, "Failed to create break-glass account")
+		return
+	}
+	c.JSON(http.StatusCreated, user)
+}
+
+// RotateBreakGlassPassword godoc
+//
+//	@Summary		Rotate a break-glass password
+//	@Description	Replace the password of a break-glass account, e.g. after it has been used. Platform operators only.
+//	@Tags			break-glass
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Param			id			path		string							true	"User ID"
+//	@Param			password	body		RotateBreakGlassPasswordRequest	true	"New password"
+//	@Success		200			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/operator/break-glass-accounts/{id}/password [put]
+func (h *BreakGlassHandler) RotateBreakGlassPassword(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req RotateBreakGlassPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.userService.RotateBreakGlassPassword(c.Request.Context(), id, req.Password); err != nil {
+		respondError(c, err, "Failed to rotate break-glass password")
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: "Break-glass password rotated successfully"})
+}
+
+// DeleteBreakGlassAccount godoc
+//
+//	@Summary		Delete a break-glass account
+//	@Description	Delete a break-glass account. Platform operators only.
+//	@Tags			break-glass
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"User ID"
+//	@Success		204	{object}	MessageResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/operator/break-glass-accounts/{id} [delete]
+func (h *BreakGlassHandler) DeleteBreakGlassAccount(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	if err := h.userService.DeleteBreakGlassAccount(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to delete break-glass account")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Break-glass account deleted successfully"})
+}
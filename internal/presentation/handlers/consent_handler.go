@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+	"backend/internal/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type GrantConsentRequest struct {
+	Fields []string `json:"fields" binding:"required" enums:"username,email,first_name,last_name,role,groups" example:"email"`
+}
+
+type ConsentHandler struct {
+	consentService services.ConsentService
+	authService    services.AuthService
+}
+
+func NewConsentHandler(consentService services.ConsentService, authService services.AuthService) *ConsentHandler {
+	return &ConsentHandler{consentService: consentService, authService: authService}
+}
+
+// ListConsents godoc
+//
+//	@Summary		List profile sharing consents
+//	@Description	Get the client apps the authenticated user shares profile fields with, and which fields each one receives from /oauth/userinfo
+//	@Tags			consents
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200				{array}		entities.ProfileConsent
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/consents [get]
+func (h *ConsentHandler) ListConsents(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	consents, err := h.consentService.ListConsents(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondError(c, err, "Failed to list consents")
+		return
+	}
+	if consents == nil {
+		consents = []*entities.ProfileConsent{}
+	}
+	c.JSON(http.StatusOK, consents)
+}
+
+// GrantConsent godoc
+//
+//	@Summary		Set profile fields shared with a client
+//	@Description	Replace the profile fields the authenticated user shares with a client app, identified by its API key ID. An empty list keeps the consent but shares nothing beyond the user ID.
+//	@Tags			consents
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			clientId		path		string				true	"Client API key ID"
+//	@Param			consent			body		GrantConsentRequest	true	"Fields to share"
+//	@Success		200				{object}	entities.ProfileConsent
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/consents/{clientId} [put]
+func (h *ConsentHandler) GrantConsent(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	clientID, err := uuid.Parse(c.Param("clientId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid client UUID"})
+		return
+	}
+
+	var req GrantConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	consent, err := h.consentService.GrantConsent(c.Request.Context(), claims.UserID, clientID, req.Fields)
+	if err != nil {
+		respondError(c, err, "Failed to save consent")
+		return
+	}
+	c.JSON(http.StatusOK, consent)
+}
+
+// RevokeConsent godoc
+//
+//	@Summary		Revoke a client's consent
+//	@Description	Stop sharing profile fields with a client app; /oauth/userinfo then returns only the user ID to it
+//	@Tags			consents
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			clientId		path		string	true	"Client API key ID"
+//	@Success		204				{object}	MessageResponse
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/consents/{clientId} [delete]
+func (h *ConsentHandler) RevokeConsent(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	clientID, err := uuid.Parse(c.Param("clientId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid client UUID"})
+		return
+	}
+
+	if err := h.consentService.RevokeConsent(c.Request.Context(), claims.UserID, clientID); err != nil {
+		respondError(c, err, "Failed to revoke consent")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Consent revoked successfully"})
+}
+
+// UserInfo godoc
+//
+//	@Summary		Get consented user info
+//	@Description	Return the user's ID as sub plus only the profile fields the user consented to share with the calling client. The client authenticates with its X-API-Key; the user with their bearer token.
+//	@Tags			consents
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			X-API-Key		header		string	true	"Client API key"
+//	@Success		200				{object}	UserInfoResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/oauth/userinfo [get]
+func (h *ConsentHandler) UserInfo(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	client, ok := c.Get(middleware.APIKeyContextKey)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "X-API-Key identifying the client is required"})
+		return
+	}
+
+	info, err := h.consentService.UserInfo(c.Request.Context(), claims.UserID, client.(*entities.APIKey))
+	if err != nil {
+		respondError(c, err, "Failed to get user info")
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// bearerClaims validates the Authorization bearer token and answers 401 when it is missing or invalid.
+func bearerClaims(c *gin.Context, authService services.AuthService) (*services.TokenClaims, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Authorization header is required"})
+		return nil, false
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid authorization header format"})
+		return nil, false
+	}
+
+	claims, err := authService.ValidateToken(c.Request.Context(), tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired token"})
+		return nil, false
+	}
+	return claims, true
+}
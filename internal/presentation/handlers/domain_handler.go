@@ -1,31 +1,61 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"backend/internal/application/services"
+	"backend/internal/domain/entities"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type CreateDomainRequest struct {
-	Name   string `json:"name" binding:"required"`
-	Domain string `json:"domain" binding:"required"`
+	Name           string                   `json:"name" binding:"required" example:"Acme Corp"`
+	Domain         string                   `json:"domain" binding:"required" example:"acme.example.com"`
+	Residency      string                   `json:"residency" example:"eu"`
+	LoginMode      string                   `json:"login_mode" enums:"password,passwordless" example:"password"`
+	PasswordPolicy *entities.PasswordPolicy `json:"password_policy"`
+}
+
+// OnboardDomainRequest creates a domain like CreateDomainRequest, together with its first admin.
+type OnboardDomainRequest struct {
+	CreateDomainRequest
+	Admin OnboardAdminRequest `json:"admin" binding:"required"`
+}
+
+type OnboardAdminRequest struct {
+	Username  string `json:"username" binding:"required" example:"jdoe"`
+	Email     string `json:"email" binding:"required,email" example:"jane.doe@acme.example.com"`
+	FirstName string `json:"first_name" binding:"required" example:"Jane"`
+	LastName  string `json:"last_name" binding:"required" example:"Doe"`
 }
 
 type UpdateDomainRequest struct {
-	Name   string `json:"name" binding:"required"`
-	Domain string `json:"domain" binding:"required"`
+	Name            string                            `json:"name" binding:"required" example:"Acme Corp"`
+	Domain          string                            `json:"domain" binding:"required" example:"acme.example.com"`
+	LoginMode       string                            `json:"login_mode" enums:"password,passwordless" example:"password"`
+	PasswordPolicy  *entities.PasswordPolicy          `json:"password_policy"`
+	Registration    *entities.RegistrationSettings    `json:"registration"`
+	Branding        *entities.DomainBranding          `json:"branding"`
+	AccountDeletion *entities.AccountDeletionSettings `json:"account_deletion"`
+}
+
+type CreateDomainAliasRequest struct {
+	Hostname  string `json:"hostname" binding:"required" example:"login.acme.example.com"`
+	IsPrimary bool   `json:"is_primary" example:"true"`
 }
 
 type DomainHandler struct {
-	domainService services.DomainService
+	domainService     services.DomainService
+	onboardingService services.DomainOnboardingService
+	deletionService   services.DomainDeletionService
 }
 
-func NewDomainHandler(domainService services.DomainService) *DomainHandler {
-	return &DomainHandler{domainService: domainService}
+func NewDomainHandler(domainService services.DomainService, onboardingService services.DomainOnboardingService, deletionService services.DomainDeletionService) *DomainHandler {
+	return &DomainHandler{domainService: domainService, onboardingService: onboardingService, deletionService: deletionService}
 }
 
 // GetDomain godoc
@@ -35,21 +65,26 @@ func NewDomainHandler(domainService services.DomainService) *DomainHandler {
 //	@Tags			domains
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
 //	@Param			domainId	path		string			true	"Domain ID"
 //	@Success		200	{object}	entities.Domain
-//	@Failure		400	{object}	map[string]string
-//	@Failure		404	{object}	map[string]string
-//	@Router			/domains/{domainId} [get]
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId} [get]
 func (h *DomainHandler) GetDomain(c *gin.Context) {
 	idStr := c.Param("domainId")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
 		return
 	}
-	domain, err := h.domainService.GetDomainByID(id)
+	domain, err := h.domainService.GetDomainByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found"})
+		respondError(c, err, "Failed to get domain")
 		return
 	}
 	c.JSON(http.StatusOK, domain)
@@ -58,42 +93,88 @@ func (h *DomainHandler) GetDomain(c *gin.Context) {
 // CreateDomain godoc
 //
 //	@Summary		Create a domain
-//	@Description	Create a new domain
+//	@Description	Create a new domain. Residency pins tenant data to a regional database shard and cannot be changed later. Login mode is password (default) or passwordless, where users sign in with emailed codes or magic links. Password policy defaults to a 6 character minimum. Send an Idempotency-Key header to retry safely: for 24 hours (IDEMPOTENCY_KEY_TTL) a retry with the same key and body returns the original response with Idempotent-Replayed: true instead of creating a duplicate. A key reused for a different body returns 400 with code idempotency_key_reused, and while the first request is still running 409 with code idempotency_key_in_progress.
 //	@Tags			domains
 //	@Accept			json
 //	@Produce		json
-//	@Param			domain	body		CreateDomainRequest	true	"Domain data"
-//	@Success		201		{object}	entities.Domain
-//	@Failure		400		{object}	map[string]string
-//	@Failure		500		{object}	map[string]string
-//	@Router			/domains [post]
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			Idempotency-Key	header		string				false	"Key making retries of this request return the original response"
+//	@Param			domain			body		CreateDomainRequest	true	"Domain data"
+//	@Success		201				{object}	entities.Domain
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		409				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/domains [post]
 func (h *DomainHandler) CreateDomain(c *gin.Context) {
 	var req CreateDomainRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
-	domain, err := h.domainService.CreateDomain(req.Name, req.Domain)
+	domain, err := h.domainService.CreateDomain(c.Request.Context(), req.Name, req.Domain, req.Residency, req.LoginMode, req.PasswordPolicy)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create domain"})
+		respondError(c, err, "Failed to create domain")
 		return
 	}
 	c.JSON(http.StatusCreated, domain)
 }
 
+// OnboardDomain godoc
+//
+//	@Summary		Onboard a domain
+//	@Description	Create a domain ready for use in one call: the domain as with POST /domains, the roles admin (domain:admin and pii:read) and member, and an admin user holding the admin role. The admin is emailed a generated temporary password, or told to sign in with an emailed code in passwordless domains. If any step fails, including the email, nothing is left behind.
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domain	body		OnboardDomainRequest	true	"Domain and admin data"
+//	@Success		201		{object}	services.DomainOnboarding
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/domains/onboard [post]
+func (h *DomainHandler) OnboardDomain(c *gin.Context) {
+	var req OnboardDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	admin := services.OnboardingAdmin{Username: req.Admin.Username, Email: req.Admin.Email, FirstName: req.Admin.FirstName, LastName: req.Admin.LastName}
+	onboarding, err := h.onboardingService.OnboardDomain(c.Request.Context(), req.Name, req.Domain, req.Residency, req.LoginMode, req.PasswordPolicy, admin)
+	if err != nil {
+		respondError(c, err, "Failed to onboard domain")
+		return
+	}
+	c.JSON(http.StatusCreated, onboarding)
+}
+
 // ListDomains godoc
 //
 //	@Summary		List all domains
-//	@Description	Get all domains with pagination and search
+//	@Description	Get all domains with pagination and search. Set cursor to page through domains oldest first instead, which stays fast however deep the listing goes: pass an empty cursor for the first page and the next_cursor of each response for the one after it. Cursor pages carry domains, limit and next_cursor (omitted on the last page) in place of page, total and total_pages.
 //	@Tags			domains
 //	@Accept			json
 //	@Produce		json
-//	@Param			search	query		string	false	"Search term for domain name"
-//	@Param			page	query		int		false	"Page number (default: 1)"
-//	@Param			limit	query		int		false	"Items per page (default: 10, max: 100)"
-//	@Success		200		{object}	repositories.DomainListResult
-//	@Failure		500		{object}	map[string]string
-//	@Router			/domains [get]
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			search				query		string	false	"Search term for domain name"
+//	@Param			page				query		int		false	"Page number"		minimum(1)	default(1)
+//	@Param			limit				query		int		false	"Items per page"	minimum(1)	maximum(100)	default(10)
+//	@Param			cursor				query		string	false	"Cursor pagination: empty for the first page, then the previous response's next_cursor"
+//	@Param			X-Consistency-Token	header		string	false	"Token from a previous write; replicas behind it are not read"
+//	@Success		200					{object}	DomainListResponse
+//	@Header			200					{string}	Link	"Links to the first, previous, next and last pages (RFC 5988)"
+//	@Failure		400					{object}	ErrorResponse
+//	@Failure		401					{object}	ErrorResponse
+//	@Failure		403					{object}	ErrorResponse
+//	@Failure		500					{object}	ErrorResponse
+//	@Router			/api/v1/domains [get]
 func (h *DomainHandler) ListDomains(c *gin.Context) {
 	// Parse query parameters
 	search := c.DefaultQuery("search", "")
@@ -110,74 +191,529 @@ func (h *DomainHandler) ListDomains(c *gin.Context) {
 		limit = 10
 	}
 
-	result, err := h.domainService.ListDomainsWithPagination(search, page, limit)
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		result, err := h.domainService.ListDomainsAfter(c.Request.Context(), search, cursor, limit)
+		if err != nil {
+			respondError(c, err, "Failed to list domains")
+			return
+		}
+		links := cursorLinks(c, "cursor", "", cursor, result.NextCursor, result.NextCursor != "")
+		c.JSON(http.StatusOK, DomainCursorListResponse{DomainCursorPage: result, Links: links})
+		return
+	}
+
+	result, err := h.domainService.ListDomainsWithPagination(c.Request.Context(), search, page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list domains"})
+		respondError(c, err, "Failed to list domains")
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, DomainListResponse{DomainListResult: result, Links: pageLinks(c, result.Page, result.Limit, result.TotalPages)})
 }
 
 // UpdateDomain godoc
 //
 //	@Summary		Update a domain
-//	@Description	Update domain by ID
+//	@Description	Update domain by ID. Omitting login_mode, password_policy, registration, branding or account_deletion keeps the current setting. Open registration requires a default_role_id belonging to the domain. Branding themes the hosted login page at /login; its colors must be hex colors and its redirect_uris list the only pages that page may return users to. Account deletion lets users delete their own account through DELETE /auth/me after grace_days (0 to 90, 0 uses the server default).
 //	@Tags			domains
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
 //	@Param			domainId	path		string					true	"Domain ID"
 //	@Param			domain	body		UpdateDomainRequest	true	"Domain data"
 //	@Success		200		{object}	entities.Domain
-//	@Failure		400		{object}	map[string]string
-//	@Failure		404		{object}	map[string]string
-//	@Failure		500		{object}	map[string]string
-//	@Router			/domains/{domainId} [put]
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId} [put]
 func (h *DomainHandler) UpdateDomain(c *gin.Context) {
 	idStr := c.Param("domainId")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
 		return
 	}
 
 	var req UpdateDomainRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	domain, err := h.domainService.UpdateDomain(id, req.Name, req.Domain)
+	domain, err := h.domainService.UpdateDomain(c.Request.Context(), id, req.Name, req.Domain, req.LoginMode, req.PasswordPolicy, req.Registration, req.Branding, req.AccountDeletion)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update domain"})
+		respondError(c, err, "Failed to update domain")
 		return
 	}
 	c.JSON(http.StatusOK, domain)
 }
 
+// GetPasswordPolicy godoc
+//
+//	@Summary		Get a domain's password policy
+//	@Description	Get the password rules of the domain, with defaults applied, so frontends can validate passwords before submitting them
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{object}	entities.PasswordPolicy
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/password-policy [get]
+func (h *DomainHandler) GetPasswordPolicy(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	policy, err := h.domainService.GetPasswordPolicy(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get password policy")
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// GetTokenSettings godoc
+//
+//	@Summary		Get a domain's token settings
+//	@Description	Get the lifetimes, audience and extra claims applied to access tokens issued for the domain
+//	@Tags			domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{object}	entities.DomainTokenSettings
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/token-settings [get]
+func (h *DomainHandler) GetTokenSettings(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	settings, err := h.domainService.GetTokenSettings(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get token settings")
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateTokenSettings godoc
+//
+//	@Summary		Update a domain's token settings
+//	@Description	Replace the token settings of the domain. access_token_ttl_minutes (at most 7 days) sets how long access tokens last and refresh_ttl_minutes (at most 90 days) how long the hosted login session renews them; 0 uses the server defaults. audience sets the aud claim. claim_template embeds the role's claims (role_claims), effective permissions (permissions), group names (group_names) and selected user attributes in access tokens; when they would exceed 4 KB, permissions, then role_claims, then group_names are left out and claims_overage is set to true. extra_claims are added to every access token as static values and may not use the standard or template claim names. With namespace_claims set, template and extra claims are issued prefixed with claim_namespace (an http or https URL, normalized to end with a slash), or with https://<domain>/claims/ when it is empty, e.g. https://acme.example.com/claims/permissions; a namespace overlapping another domain's returns 409 with code claim_namespace_taken. Tokens already issued are unaffected.
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string						true	"Domain ID"
+//	@Param			settings	body		entities.DomainTokenSettings	true	"Token settings"
+//	@Success		200			{object}	entities.DomainTokenSettings
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/token-settings [put]
+func (h *DomainHandler) UpdateTokenSettings(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req entities.DomainTokenSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	settings, err := h.domainService.UpdateTokenSettings(c.Request.Context(), id, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update token settings")
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// GetDataMasking godoc
+//
+//	@Summary		Get a domain's data masking
+//	@Description	Get the user fields masked in admin responses for viewers without the pii:read permission
+//	@Tags			domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{object}	entities.DataMaskingSettings
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/data-masking [get]
+func (h *DomainHandler) GetDataMasking(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	settings, err := h.domainService.GetDataMasking(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get data masking")
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateDataMasking godoc
+//
+//	@Summary		Update a domain's data masking
+//	@Description	Replace the user fields (email, first_name, last_name, external_id) masked in user and group member responses. Only admins whose bearer token grants the pii:read permission in the domain see them unmasked, and each such view is recorded as a user.pii_viewed event naming the viewer, the endpoint and the users seen. Requests without a bearer token see the fields masked.
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string						true	"Domain ID"
+//	@Param			settings	body		entities.DataMaskingSettings	true	"Masked fields"
+//	@Success		200			{object}	entities.DataMaskingSettings
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/data-masking [put]
+func (h *DomainHandler) UpdateDataMasking(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req entities.DataMaskingSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	settings, err := h.domainService.UpdateDataMasking(c.Request.Context(), id, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update data masking")
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
 // DeleteDomain godoc
 //
 //	@Summary		Delete a domain
-//	@Description	Delete domain by ID
+//	@Description	Delete a domain. A domain that still has users, roles, groups, permissions, policies, invitations, registration codes, webhooks or API keys is only deleted with force=true, which deletes them with it, along with its events and aliases; without it the response is 409 with code domain_in_use and the counts. With dry_run=true nothing is deleted and the response counts what would be. Large domains are better deleted in the background with POST /domains/{domainId}/deletion.
 //	@Tags			domains
 //	@Accept			json
 //	@Produce		json
-//	@Param			domainId	path		string			true	"Domain ID"
-//	@Success		204	{object}	map[string]string
-//	@Failure		400	{object}	map[string]string
-//	@Failure		500	{object}	map[string]string
-//	@Router			/domains/{domainId} [delete]
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			force		query		bool	false	"Delete the domain's users, roles and other data with it"
+//	@Param			dry_run		query		bool	false	"Return the planned effect without deleting"
+//	@Success		200			{object}	services.DomainDeletionPlan	"Dry run"
+//	@Success		204			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	DomainInUseResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId} [delete]
 func (h *DomainHandler) DeleteDomain(c *gin.Context) {
 	idStr := c.Param("domainId")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	force, ok := parseBoolQuery(c, "force")
+	if !ok {
+		return
+	}
+	dryRun, ok := parseDryRun(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.domainService.DeleteDomain(c.Request.Context(), id, force, dryRun)
+	if err != nil {
+		var inUse *services.DomainInUseError
+		if errors.As(err, &inUse) {
+			c.JSON(http.StatusConflict, DomainInUseResponse{Error: "Domain still has users, roles or other data", Code: "domain_in_use", Dependents: inUse.Dependents})
+			return
+		}
+		respondError(c, err, "Failed to delete domain")
+		return
+	}
+	if dryRun {
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Domain deleted successfully"})
+}
+
+// RequestDomainDeletion godoc
+//
+//	@Summary		Delete a domain in the background
+//	@Description	Mark a domain for deletion and queue it. Its users can no longer sign in and their tokens stop working at once; a background worker then deletes its users, roles, sessions, events and other data in batches, and finally the domain. Requesting it again returns the deletion under way, or resumes a failed one. Follow progress with GET /domains/{domainId}/deletion.
+//	@Tags			domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		202			{object}	entities.DomainDeletion
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/deletion [post]
+func (h *DomainHandler) RequestDomainDeletion(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	deletion, err := h.deletionService.RequestDeletion(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to request domain deletion")
+		return
+	}
+	c.JSON(http.StatusAccepted, deletion)
+}
+
+// GetDomainDeletion godoc
+//
+//	@Summary		Get the progress of a domain deletion
+//	@Description	Get the domain's latest deletion: its status, the step it is at and how many rows each step has deleted. It stays readable after the domain is gone.
+//	@Tags			domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{object}	entities.DomainDeletion
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/deletion [get]
+func (h *DomainHandler) GetDomainDeletion(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	deletion, err := h.deletionService.GetDeletion(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get domain deletion")
+		return
+	}
+	c.JSON(http.StatusOK, deletion)
+}
+
+// ResolveDomain godoc
+//
+//	@Summary		Resolve a domain by hostname
+//	@Description	Resolve a domain by its canonical hostname or any registered alias
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Param			host	query		string	true	"Hostname to resolve"
+//	@Success		200		{object}	entities.Domain
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/domains/resolve [get]
+func (h *DomainHandler) ResolveDomain(c *gin.Context) {
+	host := c.Query("host")
+	if host == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "host query parameter is required"})
+		return
+	}
+
+	domain, err := h.domainService.ResolveDomain(c.Request.Context(), host)
+	if err != nil {
+		respondError(c, err, "Failed to resolve domain")
+		return
+	}
+	c.JSON(http.StatusOK, domain)
+}
+
+// ListDomainAliases godoc
+//
+//	@Summary		List domain aliases
+//	@Description	Get all hostname aliases registered for a domain
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{array}		entities.DomainAlias
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/aliases [get]
+func (h *DomainHandler) ListDomainAliases(c *gin.Context) {
+	idStr := c.Param("domainId")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	aliases, err := h.domainService.ListAliases(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to list domain aliases")
+		return
+	}
+	c.JSON(http.StatusOK, aliases)
+}
+
+// CreateDomainAlias godoc
+//
+//	@Summary		Add a domain alias
+//	@Description	Register an additional hostname that resolves to the domain. The first alias becomes primary.
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string						true	"Domain ID"
+//	@Param			alias		body		CreateDomainAliasRequest	true	"Alias data"
+//	@Success		201			{object}	entities.DomainAlias
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/aliases [post]
+func (h *DomainHandler) CreateDomainAlias(c *gin.Context) {
+	idStr := c.Param("domainId")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req CreateDomainAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	alias, err := h.domainService.AddAlias(c.Request.Context(), id, req.Hostname, req.IsPrimary)
+	if err != nil {
+		respondError(c, err, "Failed to create domain alias")
+		return
+	}
+	c.JSON(http.StatusCreated, alias)
+}
+
+// SetPrimaryDomainAlias godoc
+//
+//	@Summary		Set primary domain alias
+//	@Description	Mark an alias as the primary hostname of the domain
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			aliasId		path		string	true	"Alias ID"
+//	@Success		200			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/aliases/{aliasId}/primary [put]
+func (h *DomainHandler) SetPrimaryDomainAlias(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+	aliasID, err := uuid.Parse(c.Param("aliasId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid alias UUID"})
+		return
+	}
+
+	err = h.domainService.SetPrimaryAlias(c.Request.Context(), domainID, aliasID)
+	if err != nil {
+		respondError(c, err, "Failed to set primary alias")
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: "Primary alias updated successfully"})
+}
+
+// DeleteDomainAlias godoc
+//
+//	@Summary		Delete a domain alias
+//	@Description	Remove a hostname alias from the domain
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			aliasId		path		string	true	"Alias ID"
+//	@Success		204			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/aliases/{aliasId} [delete]
+func (h *DomainHandler) DeleteDomainAlias(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+	aliasID, err := uuid.Parse(c.Param("aliasId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid alias UUID"})
 		return
 	}
 
-	err = h.domainService.DeleteDomain(id)
+	err = h.domainService.RemoveAlias(c.Request.Context(), domainID, aliasID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete domain"})
+		respondError(c, err, "Failed to delete domain alias")
 		return
 	}
-	c.JSON(http.StatusNoContent, gin.H{"message": "Domain deleted successfully"})
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Domain alias deleted successfully"})
 }
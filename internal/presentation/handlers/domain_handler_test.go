@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"backend/internal/application/services"
+	"backend/internal/application/services/mocks"
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func newDomainRouter(domains services.DomainService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewDomainHandler(domains, nil, nil)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	api := r.Group("/api/v1")
+	api.GET("/domains/:domainId", handler.GetDomain)
+	api.POST("/domains", handler.CreateDomain)
+	api.PUT("/domains/:domainId", handler.UpdateDomain)
+	api.DELETE("/domains/:domainId", handler.DeleteDomain)
+	return r
+}
+
+func TestDomainHandler(t *testing.T) {
+	id := uuid.New()
+	domain := &entities.Domain{DomainID: id, Name: "Acme Corp", Domain: "acme.example.com"}
+	body := `{"name":"Acme Corp","domain":"acme.example.com","login_mode":"password"}`
+	inUse := &services.DomainInUseError{Err: domainerrors.Conflict("domain still has users"), Dependents: repositories.DomainDependents{Users: 120}}
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		expect       func(domains *mocks.MockDomainServiceMockRecorder)
+		want         int
+		wantBody     map[string]interface{}
+	}{
+		{"get: malformed ID", http.MethodGet, "/api/v1/domains/acme", "", nil, http.StatusBadRequest,
+			map[string]interface{}{"error": "Invalid UUID"}},
+		{"get: not found", http.MethodGet, "/api/v1/domains/" + id.String(), "", func(domains *mocks.MockDomainServiceMockRecorder) {
+			domains.GetDomainByID(gomock.Any(), id).Return(nil, domainerrors.NotFound("domain not found"))
+		}, http.StatusNotFound, map[string]interface{}{"error": "Domain not found", "code": "not_found"}},
+		{"get", http.MethodGet, "/api/v1/domains/" + id.String(), "", func(domains *mocks.MockDomainServiceMockRecorder) {
+			domains.GetDomainByID(gomock.Any(), id).Return(domain, nil)
+		}, http.StatusOK, map[string]interface{}{"domain": "acme.example.com"}},
+
+		{"create: missing hostname", http.MethodPost, "/api/v1/domains", `{"name":"Acme Corp"}`, nil, http.StatusBadRequest, nil},
+		{"create: unknown residency", http.MethodPost, "/api/v1/domains", `{"name":"Acme Corp","domain":"acme.example.com","residency":"mars"}`,
+			func(domains *mocks.MockDomainServiceMockRecorder) {
+				domains.CreateDomain(gomock.Any(), "Acme Corp", "acme.example.com", "mars", "", nil).Return(nil, domainerrors.Validation("unknown residency mars"))
+			}, http.StatusBadRequest, map[string]interface{}{"code": "validation_failed"}},
+		{"create: hostname taken", http.MethodPost, "/api/v1/domains", body, func(domains *mocks.MockDomainServiceMockRecorder) {
+			domains.CreateDomain(gomock.Any(), "Acme Corp", "acme.example.com", "", "password", nil).Return(nil, domainerrors.Conflict("domain already exists"))
+		}, http.StatusConflict, map[string]interface{}{"error": "Domain already exists", "code": "conflict"}},
+		{"create", http.MethodPost, "/api/v1/domains", body, func(domains *mocks.MockDomainServiceMockRecorder) {
+			domains.CreateDomain(gomock.Any(), "Acme Corp", "acme.example.com", "", "password", nil).Return(domain, nil)
+		}, http.StatusCreated, map[string]interface{}{"domain_id": id.String()}},
+
+		{"update: malformed ID", http.MethodPut, "/api/v1/domains/acme", body, nil, http.StatusBadRequest, nil},
+		{"update: not found", http.MethodPut, "/api/v1/domains/" + id.String(), body, func(domains *mocks.MockDomainServiceMockRecorder) {
+			domains.UpdateDomain(gomock.Any(), id, "Acme Corp", "acme.example.com", "password", nil, nil, nil, nil).Return(nil, domainerrors.NotFound("domain not found"))
+		}, http.StatusNotFound, nil},
+		{"update: hostname taken", http.MethodPut, "/api/v1/domains/" + id.String(), body, func(domains *mocks.MockDomainServiceMockRecorder) {
+			domains.UpdateDomain(gomock.Any(), id, "Acme Corp", "acme.example.com", "password", nil, nil, nil, nil).Return(nil, domainerrors.Conflict("domain already exists"))
+		}, http.StatusConflict, nil},
+		{"update", http.MethodPut, "/api/v1/domains/" + id.String(), body, func(domains *mocks.MockDomainServiceMockRecorder) {
+			domains.UpdateDomain(gomock.Any(), id, "Acme Corp", "acme.example.com", "password", nil, nil, nil, nil).Return(domain, nil)
+		}, http.StatusOK, map[string]interface{}{"name": "Acme Corp"}},
+
+		{"delete: malformed force", http.MethodDelete, "/api/v1/domains/" + id.String() + "?force=always", "", nil, http.StatusBadRequest, nil},
+		{"delete: in use", http.MethodDelete, "/api/v1/domains/" + id.String(), "", func(domains *mocks.MockDomainServiceMockRecorder) {
+			domains.DeleteDomain(gomock.Any(), id, false, false).Return(nil, inUse)
+		}, http.StatusConflict, map[string]interface{}{"code": "domain_in_use"}},
+		{"delete: not found", http.MethodDelete, "/api/v1/domains/" + id.String() + "?force=true", "", func(domains *mocks.MockDomainServiceMockRecorder) {
+			domains.DeleteDomain(gomock.Any(), id, true, false).Return(nil, domainerrors.NotFound("domain not found"))
+		}, http.StatusNotFound, nil},
+		{"delete: dry run", http.MethodDelete, "/api/v1/domains/" + id.String() + "?force=true&dry_run=true", "", func(domains *mocks.MockDomainServiceMockRecorder) {
+			domains.DeleteDomain(gomock.Any(), id, true, true).Return(&services.DomainDeletionPlan{Domain: domain, DryRun: true}, nil)
+		}, http.StatusOK, map[string]interface{}{"dry_run": true}},
+		{"delete", http.MethodDelete, "/api/v1/domains/" + id.String() + "?force=true", "", func(domains *mocks.MockDomainServiceMockRecorder) {
+			domains.DeleteDomain(gomock.Any(), id, true, false).Return(&services.DomainDeletionPlan{Domain: domain}, nil)
+		}, http.StatusNoContent, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domains := mocks.NewMockDomainService(gomock.NewController(t))
+			if tt.expect != nil {
+				tt.expect(domains.EXPECT())
+			}
+			w := serve(newDomainRouter(domains), tt.method, tt.path, nil, tt.body)
+			checkResponse(t, w, tt.want, tt.wantBody)
+		})
+	}
+}
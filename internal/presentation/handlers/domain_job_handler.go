@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateDomainJobRequest struct {
+	Action   string                         `json:"action" binding:"required" enums:"suspend,unsuspend,message,update_settings" example:"suspend"`
+	Selector entities.DomainSelector        `json:"selector"`
+	Message  *entities.DomainMessage        `json:"message,omitempty"`
+	Settings *entities.DomainSettingsChange `json:"settings,omitempty"`
+}
+
+type SetDomainLabelsRequest struct {
+	Plan string   `json:"plan" example:"enterprise"`
+	Tags []string `json:"tags" example:"eu-pilot"`
+}
+
+// DomainJobHandler serves the platform operator endpoints for bulk domain operations.
+type DomainJobHandler struct {
+	domainJobService services.DomainJobService
+}
+
+func NewDomainJobHandler(domainJobService services.DomainJobService) *DomainJobHandler {
+	return &DomainJobHandler{domainJobService: domainJobService}
+}
+
+// CreateDomainJob godoc
+//
+//	@Summary		Start a bulk domain operation
+//	@Description	Suspend, unsuspend, message the active users of, or change the settings of every domain matching the selector. The job runs in the background one domain at a time; poll it for per-domain results. Platform operators only.
+//	@Tags			domain-jobs
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Param			job	body		CreateDomainJobRequest	true	"Operation and domain selector"
+//	@Success		202	{object}	entities.DomainJob
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/operator/domain-jobs [post]
+func (h *DomainJobHandler) CreateDomainJob(c *gin.Context) {
+	var req CreateDomainJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	job, err := h.domainJobService.StartJob(c.Request.Context(), req.Action, req.Selector, req.Message, req.Settings)
+	if err != nil {
+		respondError(c, err, "Failed to start domain job")
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ListDomainJobs godoc
+//
+//	@Summary		List bulk domain operations
+//	@Description	Get the most recent bulk domain operations first. Platform operators only.
+//	@Tags			domain-jobs
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Param			limit	query		int	false	"Maximum number of jobs"	minimum(1)	maximum(200)	default(50)
+//	@Success		200		{array}		entities.DomainJob
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/operator/domain-jobs [get]
+func (h *DomainJobHandler) ListDomainJobs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+
+	jobs, err := h.domainJobService.ListJobs(c.Request.Context(), limit)
+	if err != nil {
+		respondError(c, err, "Failed to list domain jobs")
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GetDomainJob godoc
+//
+//	@Summary		Get a bulk domain operation
+//	@Description	Get a bulk domain operation with its progress and per-domain results. Platform operators only.
+//	@Tags			domain-jobs
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Job ID"
+//	@Success		200	{object}	entities.DomainJob
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/operator/domain-jobs/{id} [get]
+func (h *DomainJobHandler) GetDomainJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	job, err := h.domainJobService.GetJob(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get domain job")
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// SetDomainLabels godoc
+//
+//	@Summary		Set a domain's plan and tags
+//	@Description	Replace the plan and tags that bulk domain operations select the domain by. Tags are lowercased. Platform operators only.
+//	@Tags			domain-jobs
+//	@Accept			json
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Param			domainId	path		string					true	"Domain ID"
+//	@Param			labels		body		SetDomainLabelsRequest	true	"Plan and tags"
+//	@Success		200			{object}	entities.Domain
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/operator/domains/{domainId}/labels [put]
+func (h *DomainJobHandler) SetDomainLabels(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req SetDomainLabelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	domain, err := h.domainJobService.SetDomainLabels(c.Request.Context(), domainID, req.Plan, req.Tags)
+	if err != nil {
+		respondError(c, err, "Failed to set domain labels")
+		return
+	}
+	c.JSON(http.StatusOK, domain)
+}
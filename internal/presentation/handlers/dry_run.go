@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseDryRun reads the dry_run query parameter, responding 400 when it isn't a boolean.
+func parseDryRun(c *gin.Context) (dryRun, ok bool) {
+	return parseBoolQuery(c, "dry_run")
+}
+
+// parseBoolQuery reads an optional boolean query parameter, false when absent, responding 400
+// when it isn't a boolean.
+func parseBoolQuery(c *gin.Context, name string) (value, ok bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return false, true
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid " + name + " value"})
+		return false, false
+	}
+	return value, true
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UpdateEmailBrandingRequest struct {
+	ProductName  string                            `json:"product_name" example:"Acme Portal"`
+	SupportEmail string                            `json:"support_email" example:"support@acme.example.com"`
+	Footer       string                            `json:"footer" example:"Acme Corp, 1 Main Street"`
+	Templates    map[string]entities.EmailTemplate `json:"templates"`
+}
+
+type EmailBrandingHandler struct {
+	emailService services.EmailService
+}
+
+func NewEmailBrandingHandler(emailService services.EmailService) *EmailBrandingHandler {
+	return &EmailBrandingHandler{emailService: emailService}
+}
+
+// GetEmailBranding godoc
+//
+//	@Summary		Get domain email branding
+//	@Description	Get the product name, support contact, footer and template overrides the domain's emails use. 404 means the domain uses the built-in templates with its name.
+//	@Tags			mail
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{object}	entities.EmailBranding
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/email-branding [get]
+func (h *EmailBrandingHandler) GetEmailBranding(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	branding, err := h.emailService.GetBranding(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to get email branding")
+		return
+	}
+	c.JSON(http.StatusOK, branding)
+}
+
+// UpdateEmailBranding godoc
+//
+//	@Summary		Set domain email branding
+//	@Description	Replace the domain's email branding. templates overrides the built-in invitation, verification and password_reset templates by name; they are Go text/template templates and can use .Product, .Domain and .SupportEmail besides each template's own fields (.Link and .ExpiresIn for invitation; .Code, .Link and .ExpiresIn for verification; .Username for password_reset). A template using an unknown field is rejected.
+//	@Tags			mail
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string						true	"Domain ID"
+//	@Param			branding	body		UpdateEmailBrandingRequest	true	"Email branding"
+//	@Success		200			{object}	entities.EmailBranding
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/email-branding [put]
+func (h *EmailBrandingHandler) UpdateEmailBranding(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req UpdateEmailBrandingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	branding := &entities.EmailBranding{
+		DomainID:     domainID,
+		ProductName:  req.ProductName,
+		SupportEmail: req.SupportEmail,
+		Footer:       req.Footer,
+		Templates:    req.Templates,
+	}
+	branding, err = h.emailService.UpdateBranding(c.Request.Context(), branding)
+	if err != nil {
+		respondError(c, err, "Failed to update email branding")
+		return
+	}
+	c.JSON(http.StatusOK, branding)
+}
+
+// DeleteEmailBranding godoc
+//
+//	@Summary		Remove domain email branding
+//	@Description	Remove the domain's email branding so its emails use the built-in templates again
+//	@Tags			mail
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		204			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/email-branding [delete]
+func (h *EmailBrandingHandler) DeleteEmailBranding(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	if err := h.emailService.DeleteBranding(c.Request.Context(), domainID); err != nil {
+		respondError(c, err, "Failed to delete email branding")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Email branding deleted successfully"})
+}
+
+// PreviewEmail godoc
+//
+//	@Summary		Preview a domain email
+//	@Description	Render one of the templates (invitation, verification or password_reset) in the domain's branding with sample values
+//	@Tags			mail
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			template	path		string	true	"Template"	Enums(invitation, verification, password_reset)
+//	@Success		200			{object}	services.RenderedEmail
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/email-branding/preview/{template} [get]
+func (h *EmailBrandingHandler) PreviewEmail(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	email, err := h.emailService.Preview(c.Request.Context(), domainID, c.Param("template"))
+	if err != nil {
+		respondError(c, err, "Failed to preview email")
+		return
+	}
+	c.JSON(http.StatusOK, email)
+}
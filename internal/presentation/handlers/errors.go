@@ -0,0 +1,9 @@
+package handlers
+
+import "github.com/gin-gonic/gin"
+
+// respondError hands err to middleware.ErrorHandler, which answers domain errors with the status
+// of their kind and anything else with 500 and fallback as the message.
+func respondError(c *gin.Context, err error, fallback string) {
+	_ = c.Error(err).SetMeta(fallback)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type EventHandler struct {
+	eventService services.EventService
+}
+
+func NewEventHandler(eventService services.EventService) *EventHandler {
+	return &EventHandler{eventService: eventService}
+}
+
+// ListEvents godoc
+//
+//	@Summary		Replay domain events
+//	@Description	Get the events of a domain in sequence order, starting after the given sequence number. Sequence numbers are gapless per domain, so integrators that missed deliveries can backfill deterministically by passing next_since from the previous page until has_more is false.
+//	@Tags			events
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	query		string	true	"Domain ID"
+//	@Param			since		query		int		false	"Return events with a greater sequence number"	minimum(0)	default(0)
+//	@Param			limit		query		int		false	"Maximum events to return"	minimum(1)	maximum(1000)	default(100)
+//	@Success		200			{object}	EventPageResponse
+//	@Header			200			{string}	Link	"Links to the first and next pages (RFC 5988)"
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/events [get]
+func (h *EventHandler) ListEvents(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Query("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	since, err := strconv.ParseInt(c.DefaultQuery("since", "0"), 10, 64)
+	if err != nil || since < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid since value"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil {
+		limit = 100
+	}
+
+	page, err := h.eventService.ListEvents(c.Request.Context(), domainID, since, limit)
+	if err != nil {
+		respondError(c, err, "Failed to list events")
+		return
+	}
+	links := cursorLinks(c, "since", "0", strconv.FormatInt(since, 10), strconv.FormatInt(page.NextSince, 10), page.HasMore)
+	c.JSON(http.StatusOK, EventPageResponse{EventPage: page, Links: links})
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportFlushEvery controls how many records are buffered before they are flushed to the client.
+const exportFlushEvery = 100
+
+// exportWriteTimeout is the time the client gets to take each flushed batch. The server's write
+// timeout would otherwise cut off exports taking longer than it as a whole.
+const exportWriteTimeout = 30 * time.Second
+
+// exportStream writes records to the response as they are produced. Headers are only sent with
+// the first record, so a failure before any output can still be answered with a JSON error.
+type exportStream struct {
+	c        *gin.Context
+	format   string
+	filename string
+	header   []string
+	csv      *csv.Writer
+	count    int
+	started  bool
+}
+
+// newExportStream validates the format ("csv" or "json", default csv) and prepares a stream;
+// header names the CSV columns.
+func newExportStream(c *gin.Context, format, filename string, header []string) (*exportStream, error) {
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return nil, fmt.Errorf("unsupported format")
+	}
+	return &exportStream{c: c, format: format, filename: filename, header: header}, nil
+}
+
+func (e *exportStream) start() error {
+	e.started = true
+	e.extendDeadline()
+	name := fmt.Sprintf("%s-%s.%s", e.filename, time.Now().UTC().Format("20060102T150405Z"), e.format)
+	e.c.Header("Content-Disposition", `attachment; filename="`+name+`"`)
+	e.c.Status(http.StatusOK)
+
+	if e.format == "json" {
+		e.c.Header("Content-Type", "application/json")
+		_, err := e.c.Writer.WriteString("[")
+		return err
+	}
+	e.c.Header("Content-Type", "text/csv")
+	e.csv = csv.NewWriter(e.c.Writer)
+	return e.csv.Write(e.header)
+}
+
+// Write emits one record: the CSV row for csv exports, item marshalled as JSON otherwise.
+func (e *exportStream) Write(row []string, item interface{}) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	if e.format == "json" {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if e.count > 0 {
+			if _, err := e.c.Writer.WriteString(","); err != nil {
+				return err
+			}
+		}
+		if _, err := e.c.Writer.Write(data); err != nil {
+			return err
+		}
+	} else if err := e.csv.Write(row); err != nil {
+		return err
+	}
+
+	e.count++
+	if e.count%exportFlushEvery == 0 {
+		e.flush()
+	}
+	return nil
+}
+
+func (e *exportStream) flush() {
+	if e.csv != nil {
+		e.csv.Flush()
+	}
+	e.c.Writer.Flush()
+	e.extendDeadline()
+}
+
+// extendDeadline gives the client exportWriteTimeout from now for the next batch. Writers that
+// can't set deadlines, such as test recorders, have none to extend.
+func (e *exportStream) extendDeadline() {
+	_ = http.NewResponseController(e.c.Writer).SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+}
+
+// Finish completes the document. When err is set and nothing was written yet the error is
+// handed to the error middleware with fallback as message; once streaming has begun the status can no longer
+// change, so the document is left unterminated and the error is recorded on the context.
+func (e *exportStream) Finish(err error, fallback string) {
+	if err != nil {
+		if !e.started {
+			respondError(e.c, err, fallback)
+			return
+		}
+		e.flush()
+		_ = e.c.Error(err)
+		e.c.Abort()
+		return
+	}
+
+	if !e.started {
+		if err := e.start(); err != nil {
+			return
+		}
+	}
+	if e.format == "json" {
+		_, _ = e.c.Writer.WriteString("]")
+	}
+	e.flush()
+}
+
+func formatExportTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestExportOutlastsWriteTimeout streams an export taking longer than the server's write timeout;
+// each flushed batch extends the deadline, so it arrives whole.
+func TestExportOutlastsWriteTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/export", func(c *gin.Context) {
+		stream, err := newExportStream(c, "csv", "rows", []string{"n"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3*exportFlushEvery; i++ {
+			if i%exportFlushEvery == 0 {
+				time.Sleep(150 * time.Millisecond)
+			}
+			if err := stream.Write([]string{strconv.Itoa(i)}, nil); err != nil {
+				stream.Finish(err, "export failed")
+				return
+			}
+		}
+		stream.Finish(nil, "")
+	})
+
+	server := httptest.NewUnstartedServer(r)
+	server.Config.WriteTimeout = 200 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("export cut off after %d bytes: %v", len(body), err)
+	}
+	if lines := strings.Count(string(body), "\n"); lines != 3*exportFlushEvery+1 {
+		t.Errorf("got %d lines, want %d", lines, 3*exportFlushEvery+1)
+	}
+}
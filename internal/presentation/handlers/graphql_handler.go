@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+	"backend/internal/presentation/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLRequest documents the body of a GraphQL POST.
+type GraphQLRequest struct {
+	Query         string                 `json:"query" example:"{ users(limit: 5) { total users { username role { roleName } domain { name } } } }"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLResponse documents a GraphQL response. Failed fields are null and described in errors,
+// whose extensions carry the same code and status as the REST error.
+type GraphQLResponse struct {
+	Data   map[string]interface{}   `json:"data"`
+	Errors []map[string]interface{} `json:"errors,omitempty"`
+}
+
+// GraphQLHandler serves the admin console's GraphQL endpoint.
+type GraphQLHandler struct {
+	server  http.Handler
+	masking services.DataMaskingService
+}
+
+func NewGraphQLHandler(resolver *graph.Resolver, masking services.DataMaskingService) *GraphQLHandler {
+	return &GraphQLHandler{server: graph.NewServer(resolver), masking: masking}
+}
+
+// Query godoc
+//
+//	@Summary		Query the admin GraphQL API
+//	@Description	Query users, roles, domains and groups with nested fields (e.g. a user's role and domain) in one request, or change them with mutations. Lists take page and limit like their REST counterparts. Users are masked like REST responses. GET accepts queries only, passed as the query parameter.
+//	@Tags			graphql
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			request	body		GraphQLRequest	true	"GraphQL request"
+//	@Success		200		{object}	GraphQLResponse
+//	@Failure		422		{object}	GraphQLResponse
+//	@Router			/api/v1/graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	masker := newUserMasker(c, h.masking)
+	ctx := graph.WithUserMasker(c.Request.Context(), masker)
+	h.server.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+	masker.Finish(ctx)
+}
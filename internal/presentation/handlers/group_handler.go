@@ -0,0 +1,441 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateGroupRequest struct {
+	Name        string `json:"name" binding:"required" example:"engineering"`
+	Description string `json:"description" example:"Engineering department"`
+}
+
+type UpdateGroupRequest struct {
+	Name        string `json:"name" binding:"required" example:"engineering"`
+	Description string `json:"description" example:"Engineering department"`
+}
+
+type AddGroupMemberRequest struct {
+	UserID string `json:"user_id" binding:"required" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+}
+
+type AddGroupRoleRequest struct {
+	RoleID string `json:"role_id" binding:"required" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+}
+
+type GroupHandler struct {
+	groupService services.GroupService
+	masking      services.DataMaskingService
+}
+
+func NewGroupHandler(groupService services.GroupService, masking services.DataMaskingService) *GroupHandler {
+	return &GroupHandler{groupService: groupService, masking: masking}
+}
+
+// GetGroup godoc
+//
+//	@Summary		Get a group
+//	@Description	Get group by ID
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Group ID"
+//	@Success		200	{object}	entities.Group
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/groups/{id} [get]
+func (h *GroupHandler) GetGroup(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	group, err := h.groupService.GetGroupByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get group")
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+// ListGroups godoc
+//
+//	@Summary		List domain groups
+//	@Description	Get all groups of a domain
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{array}		entities.Group
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/groups [get]
+func (h *GroupHandler) ListGroups(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	groups, err := h.groupService.ListGroups(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to list groups")
+		return
+	}
+	c.JSON(http.StatusOK, groups)
+}
+
+// CreateGroup godoc
+//
+//	@Summary		Create a group
+//	@Description	Create a new group in the domain
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string				true	"Domain ID"
+//	@Param			group		body		CreateGroupRequest	true	"Group data"
+//	@Success		201			{object}	entities.Group
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/groups [post]
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req CreateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	group, err := h.groupService.CreateGroup(c.Request.Context(), domainID, req.Name, req.Description)
+	if err != nil {
+		respondError(c, err, "Failed to create group")
+		return
+	}
+	c.JSON(http.StatusCreated, group)
+}
+
+// UpdateGroup godoc
+//
+//	@Summary		Update a group
+//	@Description	Update group by ID
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string				true	"Group ID"
+//	@Param			group	body		UpdateGroupRequest	true	"Group data"
+//	@Success		200		{object}	entities.Group
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/groups/{id} [put]
+func (h *GroupHandler) UpdateGroup(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req UpdateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	group, err := h.groupService.UpdateGroup(c.Request.Context(), id, req.Name, req.Description)
+	if err != nil {
+		respondError(c, err, "Failed to update group")
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteGroup godoc
+//
+//	@Summary		Delete a group
+//	@Description	Delete group by ID; members lose the roles inherited through it and their existing tokens are revoked
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Group ID"
+//	@Success		204	{object}	MessageResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/groups/{id} [delete]
+func (h *GroupHandler) DeleteGroup(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	if err := h.groupService.DeleteGroup(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to delete group")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Group deleted successfully"})
+}
+
+// ListGroupMembers godoc
+//
+//	@Summary		List group members
+//	@Description	Get the users that belong to a group
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Group ID"
+//	@Success		200	{array}		entities.User
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/groups/{id}/members [get]
+func (h *GroupHandler) ListGroupMembers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	members, err := h.groupService.ListMembers(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to list group members")
+		return
+	}
+	respondMaskedUsers(c, h.masking, http.StatusOK, members)
+}
+
+// AddGroupMember godoc
+//
+//	@Summary		Add a group member
+//	@Description	Add a user of the same domain to the group
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string					true	"Group ID"
+//	@Param			member	body		AddGroupMemberRequest	true	"User to add"
+//	@Success		204		{object}	MessageResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/groups/{id}/members [post]
+func (h *GroupHandler) AddGroupMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req AddGroupMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user UUID"})
+		return
+	}
+
+	err = h.groupService.AddMember(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, err, "Failed to add group member")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Member added successfully"})
+}
+
+// RemoveGroupMember godoc
+//
+//	@Summary		Remove a group member
+//	@Description	Remove a user from the group and revoke the user's existing tokens
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string	true	"Group ID"
+//	@Param			userId	path		string	true	"User ID"
+//	@Success		204		{object}	MessageResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/groups/{id}/members/{userId} [delete]
+func (h *GroupHandler) RemoveGroupMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user UUID"})
+		return
+	}
+
+	err = h.groupService.RemoveMember(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, err, "Failed to remove group member")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Member removed successfully"})
+}
+
+// ListGroupRoles godoc
+//
+//	@Summary		List group roles
+//	@Description	Get the roles inherited by members of a group
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Group ID"
+//	@Success		200	{array}		entities.Role
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/groups/{id}/roles [get]
+func (h *GroupHandler) ListGroupRoles(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	roles, err := h.groupService.ListRoles(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to list group roles")
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// AddGroupRole godoc
+//
+//	@Summary		Add a group role
+//	@Description	Grant a role of the same domain to every member of the group
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string				true	"Group ID"
+//	@Param			role	body		AddGroupRoleRequest	true	"Role to add"
+//	@Success		204		{object}	MessageResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/groups/{id}/roles [post]
+func (h *GroupHandler) AddGroupRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req AddGroupRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	roleID, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+
+	err = h.groupService.AddRole(c.Request.Context(), id, roleID)
+	if err != nil {
+		respondError(c, err, "Failed to add group role")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Role added successfully"})
+}
+
+// RemoveGroupRole godoc
+//
+//	@Summary		Remove a group role
+//	@Description	Stop granting a role to the group's members and revoke their existing tokens
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string	true	"Group ID"
+//	@Param			roleId	path		string	true	"Role ID"
+//	@Success		204		{object}	MessageResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/groups/{id}/roles/{roleId} [delete]
+func (h *GroupHandler) RemoveGroupRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+	roleID, err := uuid.Parse(c.Param("roleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+
+	err = h.groupService.RemoveRole(c.Request.Context(), id, roleID)
+	if err != nil {
+		respondError(c, err, "Failed to remove group role")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Role removed successfully"})
+}
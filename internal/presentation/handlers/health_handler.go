@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+	"backend/internal/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	healthService services.HealthService
+}
+
+func NewHealthHandler(healthService services.HealthService) *HealthHandler {
+	return &HealthHandler{healthService: healthService}
+}
+
+// Readiness godoc
+//
+//	@Summary		Check readiness
+//	@Description	Probe the databases, the lookup cache and the token revocation store, and report which features (login, token_validation, admin_reads, admin_writes) their failures impact and how (slow, partial or unavailable). Returns 503 only when the primary database is down; a degraded instance stays ready.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	services.HealthReport
+//	@Failure		503	{object}	services.HealthReport
+//	@Router			/readyz [get]
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	report := h.healthService.Check(c.Request.Context())
+	if value := report.Header(); value != "" {
+		c.Header(middleware.DegradationHeader, value)
+	}
+	if report.Status == services.HealthUnavailable {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required" maxLength:"500" example:"Reproducing ticket SUP-1234"`
+}
+
+type ImpersonationHandler struct {
+	authService services.AuthService
+}
+
+func NewImpersonationHandler(authService services.AuthService) *ImpersonationHandler {
+	return &ImpersonationHandler{authService: authService}
+}
+
+// Impersonate godoc
+//
+//	@Summary		Impersonate a user
+//	@Description	Issue a short-lived access token acting as a user of the caller's domain, for support engineers whose bearer token grants the impersonate permission; a token narrowed with a scope needs impersonate among its scopes. The token lasts IMPERSONATION_TOKEN_TTL, or the domain's access token lifetime if shorter, and names the engineer in its act claim (RFC 8693), which token validation returns. The start is recorded as a user.impersonated event with the reason, and every event caused with the token carries the engineer in impersonator_id. Impersonation tokens cannot use the admin API, impersonate again, or change the user's own account, password, devices, consents or tokens, and break-glass and service accounts cannot be impersonated.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id				path		string						true	"User ID"
+//	@Param			request			body		ImpersonateRequest			true	"Why the user is impersonated, e.g. a support ticket"
+//	@Success		200				{object}	services.ImpersonationResponse
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	resp, err := h.authService.Impersonate(c.Request.Context(), claims, userID, req.Reason)
+	if err != nil {
+		respondError(c, err, "Failed to impersonate user")
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type IntegrationHandler struct {
+	integrationService services.IntegrationHealthService
+}
+
+func NewIntegrationHandler(integrationService services.IntegrationHealthService) *IntegrationHandler {
+	return &IntegrationHandler{integrationService: integrationService}
+}
+
+// ListIntegrations godoc
+//
+//	@Summary		List domain integrations
+//	@Description	Get the health of each integration the domain has enabled (its own SMTP sender and its webhooks), as found by the scheduled health checks. Webhooks are unhealthy while the latest delivery to one of them failed. Integrations not checked yet have status unknown.
+//	@Tags			integrations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{array}		entities.IntegrationHealth
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/integrations [get]
+func (h *IntegrationHandler) ListIntegrations(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	integrations, err := h.integrationService.ListIntegrations(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to list integrations")
+		return
+	}
+	c.JSON(http.StatusOK, integrations)
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateInvitationRequest struct {
+	Email     string `json:"email" binding:"required,email" example:"jane.doe@example.com"`
+	RoleID    string `json:"role_id" binding:"required" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	FirstName string `json:"first_name" example:"Jane"`
+	LastName  string `json:"last_name" example:"Doe"`
+}
+
+type AcceptInvitationRequest struct {
+	Token     string `json:"token" binding:"required" example:"5f2b8c0e9a7d4e3f8b1c6a2d9e0f7b3c5f2b8c0e9a7d4e3f8b1c6a2d9e0f7b3c"`
+	Password  string `json:"password" binding:"omitempty,min=6" example:"S3cure-pass"`
+	Username  string `json:"username" example:"jdoe"`
+	FirstName string `json:"first_name" example:"Jane"`
+	LastName  string `json:"last_name" example:"Doe"`
+}
+
+type InvitationHandler struct {
+	invitationService services.InvitationService
+	authService       services.AuthService
+}
+
+func NewInvitationHandler(invitationService services.InvitationService, authService services.AuthService) *InvitationHandler {
+	return &InvitationHandler{invitationService: invitationService, authService: authService}
+}
+
+// CreateInvitation godoc
+//
+//	@Summary		Invite a user by email
+//	@Description	Email an invitation link for the given role. The invitee accepts through POST /auth/accept-invitation before the link expires. An email that already belongs to a user returns 409 with code email_taken; one with a pending invitation returns 409 with code invitation_pending.
+//	@Tags			invitations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string					true	"Domain ID"
+//	@Param			invitation	body		CreateInvitationRequest	true	"Invitation data"
+//	@Success		201			{object}	entities.Invitation
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/invitations [post]
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	roleID, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+
+	invitation, err := h.invitationService.CreateInvitation(c.Request.Context(), domainID, roleID, req.Email, req.FirstName, req.LastName)
+	if err != nil {
+		respondError(c, err, "Failed to create invitation")
+		return
+	}
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// ListInvitations godoc
+//
+//	@Summary		List invitations
+//	@Description	Get the domain's invitations, newest first, optionally filtered by status
+//	@Tags			invitations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			status		query		string	false	"Only invitations with this status"	Enums(pending, accepted, revoked, expired)
+//	@Success		200			{array}		entities.Invitation
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/invitations [get]
+func (h *InvitationHandler) ListInvitations(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	invitations, err := h.invitationService.ListInvitations(c.Request.Context(), domainID, c.Query("status"))
+	if err != nil {
+		respondError(c, err, "Failed to list invitations")
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+// ResendInvitation godoc
+//
+//	@Summary		Resend an invitation
+//	@Description	Email a new invitation link and restart the expiry; the previous link stops working. Expired invitations can be resent; accepted or revoked ones return 409 with code invitation_closed.
+//	@Tags			invitations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId		path		string	true	"Domain ID"
+//	@Param			invitationId	path		string	true	"Invitation ID"
+//	@Success		200				{object}	entities.Invitation
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		409				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/invitations/{invitationId}/resend [post]
+func (h *InvitationHandler) ResendInvitation(c *gin.Context) {
+	domainID, invitationID, ok := parseInvitationPath(c)
+	if !ok {
+		return
+	}
+
+	invitation, err := h.invitationService.ResendInvitation(c.Request.Context(), domainID, invitationID)
+	if err != nil {
+		respondError(c, err, "Failed to resend invitation")
+		return
+	}
+	c.JSON(http.StatusOK, invitation)
+}
+
+// RevokeInvitation godoc
+//
+//	@Summary		Revoke an invitation
+//	@Description	Revoke a pending or expired invitation so its link can no longer be used
+//	@Tags			invitations
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId		path		string	true	"Domain ID"
+//	@Param			invitationId	path		string	true	"Invitation ID"
+//	@Success		204				{object}	MessageResponse
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/invitations/{invitationId} [delete]
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	domainID, invitationID, ok := parseInvitationPath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.invitationService.RevokeInvitation(c.Request.Context(), domainID, invitationID); err != nil {
+		respondError(c, err, "Failed to revoke invitation")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Invitation revoked successfully"})
+}
+
+// AcceptInvitation godoc
+//
+//	@Summary		Accept an invitation
+//	@Description	Create the invited account using the token from the invitation email. Password domains require a password meeting the domain's policy; passwordless domains must omit it. The username defaults to the invited email and the names to those in the invitation. An unknown, expired, revoked or used token returns 401 with code invalid_invitation.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-NRM-DID		header		string					false	"Domain ID (required unless X-NRM-Domain is set)"
+//	@Param			X-NRM-Domain	header		string					false	"Domain hostname or alias, used when X-NRM-DID is absent"
+//	@Param			invitation		body		AcceptInvitationRequest	true	"Invitation token and account details"
+//	@Success		201				{object}	entities.User
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		409				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/accept-invitation [post]
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	domainID, ok := resolveLoginDomain(c, h.authService)
+	if !ok {
+		return
+	}
+
+	var req AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	user, err := h.invitationService.AcceptInvitation(c.Request.Context(), domainID, req.Token, req.Username, req.FirstName, req.LastName, req.Password)
+	if err != nil {
+		respondError(c, err, "Failed to accept invitation")
+		return
+	}
+	c.JSON(http.StatusCreated, user)
+}
+
+func parseInvitationPath(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	invitationID, err := uuid.Parse(c.Param("invitationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid invitation UUID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return domainID, invitationID, true
+}
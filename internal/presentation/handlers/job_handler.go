@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// JobHandler serves the platform operator endpoints for the background job queue.
+type JobHandler struct {
+	jobService services.JobService
+}
+
+func NewJobHandler(jobService services.JobService) *JobHandler {
+	return &JobHandler{jobService: jobService}
+}
+
+// ListJobs godoc
+//
+//	@Summary		List background jobs
+//	@Description	Get the most recent background jobs first, such as queued notification emails, with their attempts and last error. Platform operators only.
+//	@Tags			jobs
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Param			status	query		string	false	"Only jobs with this status"	Enums(pending, running, succeeded, failed)
+//	@Param			kind	query		string	false	"Only jobs of this kind, e.g. mail.send"
+//	@Param			limit	query		int		false	"Maximum number of jobs"	minimum(1)	maximum(200)	default(50)
+//	@Success		200		{array}		entities.Job
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/operator/jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+
+	jobs, err := h.jobService.ListJobs(c.Request.Context(), c.Query("status"), c.Query("kind"), limit)
+	if err != nil {
+		respondError(c, err, "Failed to list jobs")
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GetJobStats godoc
+//
+//	@Summary		Count background jobs by status
+//	@Description	Count the jobs of the background queue that are pending, running, succeeded and failed. Platform operators only.
+//	@Tags			jobs
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Success		200	{object}	entities.JobStats
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/operator/jobs/stats [get]
+func (h *JobHandler) GetJobStats(c *gin.Context) {
+	stats, err := h.jobService.GetStats(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to count jobs")
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetJob godoc
+//
+//	@Summary		Get a background job
+//	@Description	Get a background job with its payload, attempts and last error. Platform operators only.
+//	@Tags			jobs
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Job ID"
+//	@Success		200	{object}	entities.Job
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/operator/jobs/{id} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	job, err := h.jobService.GetJob(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get job")
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// RetryJob godoc
+//
+//	@Summary		Retry a failed background job
+//	@Description	Queue a job that ran out of attempts again, with a fresh set of attempts. Jobs that haven't failed answer 409 with code job_not_failed. Platform operators only.
+//	@Tags			jobs
+//	@Produce		json
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Job ID"
+//	@Success		202	{object}	entities.Job
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/operator/jobs/{id}/retry [post]
+func (h *JobHandler) RetryJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	job, err := h.jobService.RetryJob(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to retry job")
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseCreatedRange reads the created_after and created_before query parameters as RFC 3339
+// times, responding 400 when either is malformed.
+func parseCreatedRange(c *gin.Context) (after, before *time.Time, ok bool) {
+	for _, bound := range []struct {
+		param  string
+		target **time.Time
+	}{{"created_after", &after}, {"created_before", &before}} {
+		raw := c.Query(bound.param)
+		if raw == "" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid " + bound.param + ", expected an RFC 3339 time"})
+			return nil, nil, false
+		}
+		*bound.target = &at
+	}
+	return after, before, true
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LoginHistoryHandler struct {
+	loginHistoryService services.LoginHistoryService
+}
+
+func NewLoginHistoryHandler(loginHistoryService services.LoginHistoryService) *LoginHistoryHandler {
+	return &LoginHistoryHandler{loginHistoryService: loginHistoryService}
+}
+
+// GetLoginHistory godoc
+//
+//	@Summary		Get a user's login history
+//	@Description	Get the sign-in attempts on a user's account, newest first, with their result, method, client IP and user agent. Failed attempts carry the reason they were refused. Attempts stopped by the risk policy before the account was known are only in the event log.
+//	@Tags			users
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id				path		string	true	"User ID"
+//	@Param			page			query		int		false	"Page number"		minimum(1)	default(1)
+//	@Param			limit			query		int		false	"Items per page"	minimum(1)	maximum(100)	default(10)
+//	@Success		200				{object}	LoginHistoryListResponse
+//	@Header			200				{string}	Link	"Links to the first, previous, next and last pages (RFC 5988)"
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/users/{id}/login-history [get]
+func (h *LoginHistoryHandler) GetLoginHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	result, err := h.loginHistoryService.ListHistory(c.Request.Context(), id, page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to get login history")
+		return
+	}
+	c.JSON(http.StatusOK, LoginHistoryListResponse{LoginHistoryListResult: result, Links: pageLinks(c, result.Page, result.Limit, result.TotalPages)})
+}
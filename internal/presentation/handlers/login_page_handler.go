@@ -0,0 +1,465 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/tenancy"
+	"backend/internal/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+//go:embed templates/login.html templates/session.html
+var loginTemplates embed.FS
+
+var (
+	loginTemplate   = template.Must(template.ParseFS(loginTemplates, "templates/login.html"))
+	sessionTemplate = template.Must(template.ParseFS(loginTemplates, "templates/session.html"))
+)
+
+// loginCSRFCookie holds the token the login form must echo back (double-submit), so other sites
+// cannot post credentials to the page on a user's behalf.
+const loginCSRFCookie = "nrm_login_csrf"
+
+// Steps of the login form. Passwordless domains ask for an email, then for the emailed code.
+const (
+	loginStepPassword = "password"
+	loginStepEmail    = "email"
+	loginStepCode     = "code"
+)
+
+const (
+	defaultLoginPrimaryColor    = "#2563eb"
+	defaultLoginBackgroundColor = "#f6f8fa"
+)
+
+// loginPageView is what templates/login.html renders.
+type loginPageView struct {
+	Title           string
+	LogoURL         string
+	PrimaryColor    string
+	BackgroundColor string
+	Error           string
+
+	// Ready is false when the request itself is invalid and only the error is shown
+	Ready           bool
+	CSRFToken       string
+	DomainID        string
+	RedirectURI     string
+	State           string
+	ClientID        string
+	ClientName      string
+	Fields          string
+	RequestedFields []string
+	Step            string
+	Username        string
+	Email           string
+}
+
+type LoginPageHandler struct {
+	authService services.AuthService
+	hostedLogin services.HostedLoginService
+	session     *config.HostedSessionConfig
+}
+
+func NewLoginPageHandler(authService services.AuthService, hostedLogin services.HostedLoginService, session *config.HostedSessionConfig) *LoginPageHandler {
+	return &LoginPageHandler{authService: authService, hostedLogin: hostedLogin, session: session}
+}
+
+// ShowLoginPage godoc
+//
+//	@Summary		Hosted login page
+//	@Description	Serve a sign-in page themed by the domain's branding, for domains without their own login frontend. The domain comes from domain_id or, when absent, the request's Host. redirect_uri must be one of the domain's branding redirect_uris. A client_id (an API key ID of the domain) may ask for profile fields, which the user can choose to share on the page. After sign-in the browser is redirected to redirect_uri with access_token, token_type and state in the URL fragment, and a session cookie is set for renewing tokens at /auth/session/refresh.
+//	@Tags			auth
+//	@Produce		html
+//	@Param			domain_id		query	string	false	"Domain ID (defaults to the domain serving the request's Host)"
+//	@Param			redirect_uri	query	string	true	"Where to send the user after sign-in"
+//	@Param			state			query	string	false	"Opaque value returned unchanged in the redirect"
+//	@Param			client_id		query	string	false	"API key ID of the app asking for profile fields"
+//	@Param			fields			query	string	false	"Comma-separated profile fields the app asks the user to share"
+//	@Success		200				{string}	string	"Login page"
+//	@Failure		400				{string}	string	"Login page showing the error"
+//	@Failure		404				{string}	string	"Login page showing the error"
+//	@Router			/login [get]
+func (h *LoginPageHandler) ShowLoginPage(c *gin.Context) {
+	page, err := h.preparePage(c, c.Query("domain_id"), c.Query("redirect_uri"), c.Query("state"), c.Query("client_id"), c.Query("fields"))
+	if err != nil {
+		h.renderError(c, nil, err)
+		return
+	}
+
+	view := h.newView(c, page)
+	if page.Domain.LoginMode == entities.LoginModePasswordless {
+		view.Step = loginStepEmail
+	}
+	h.render(c, http.StatusOK, view)
+}
+
+// SubmitLoginPage godoc
+//
+//	@Summary		Submit the hosted login page
+//	@Description	Check the credentials posted by the hosted login page and redirect to the redirect_uri with the access token in the URL fragment. Profile fields ticked under "share" are granted to the requesting client. Errors are shown on the page.
+//	@Tags			auth
+//	@Accept			x-www-form-urlencoded
+//	@Produce		html
+//	@Success		303	{string}	string	"Redirect to redirect_uri"
+//	@Failure		400	{string}	string	"Login page showing the error"
+//	@Failure		401	{string}	string	"Login page showing the error"
+//	@Failure		403	{string}	string	"Login page showing the error"
+//	@Router			/login [post]
+func (h *LoginPageHandler) SubmitLoginPage(c *gin.Context) {
+	page, err := h.preparePage(c, c.PostForm("domain_id"), c.PostForm("redirect_uri"), c.PostForm("state"), c.PostForm("client_id"), c.PostForm("fields"))
+	if err != nil {
+		h.renderError(c, nil, err)
+		return
+	}
+
+	view := h.newView(c, page)
+	view.Step = c.PostForm("step")
+	view.Username = c.PostForm("username")
+	view.Email = strings.TrimSpace(c.PostForm("email"))
+
+	cookie, _ := c.Cookie(loginCSRFCookie)
+	token := c.PostForm("csrf_token")
+	if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(token)) != 1 {
+		view.Error = "Your session expired, please try again"
+		h.render(c, http.StatusForbidden, view)
+		return
+	}
+
+	domainID := page.Domain.DomainID
+	// The sign-in, the consent it shares and the session it starts all stay in the page's tenant
+	ctx := tenancy.WithDomain(services.WithUserAgent(c.Request.Context(), c.Request.UserAgent()), domainID)
+	var login *services.LoginResponse
+	switch {
+	case page.Domain.LoginMode != entities.LoginModePasswordless:
+		view.Step = loginStepPassword
+		login, err = h.authService.Login(ctx, domainID, view.Username, c.PostForm("password"), c.ClientIP(), services.LoginOptions{})
+	case view.Step == loginStepCode:
+		login, err = h.authService.VerifyPasswordlessLogin(ctx, domainID, view.Email, c.PostForm("code"), "", c.ClientIP())
+	case view.Step == loginStepEmail:
+		if err := h.authService.StartPasswordlessLogin(ctx, domainID, view.Email, c.ClientIP()); err != nil {
+			h.renderError(c, view, err)
+			return
+		}
+		view.Step = loginStepCode
+		h.render(c, http.StatusOK, view)
+		return
+	default:
+		view.Step = loginStepEmail
+		h.renderError(c, view, errInvalidLoginRequest("Invalid login step"))
+		return
+	}
+	if err != nil {
+		h.renderError(c, view, err)
+		return
+	}
+
+	location, err := h.hostedLogin.Complete(ctx, page, login, c.PostFormArray("share"))
+	if err != nil {
+		h.renderError(c, view, err)
+		return
+	}
+
+	// The sign-in succeeded even without a session; apps then send the user here again to renew
+	if session, err := h.authService.StartSession(ctx, login.User.ID); err != nil {
+		log.Printf("Failed to start hosted login session: %v", err)
+	} else {
+		h.setSessionCookie(c, domainID, session.Token, int(time.Until(session.ExpiresAt).Seconds()))
+	}
+	c.Redirect(http.StatusSeeOther, location)
+}
+
+// RefreshSession godoc
+//
+//	@Summary		Renew an access token from the hosted login session
+//	@Description	Load in a hidden iframe to get a new access token without storing refresh tokens in JavaScript. The page posts a message to the parent window at the origin of redirect_uri, which must be one of the domain's branding redirect_uris: {"type": "nrm_session", "access_token", "token_type", "state"}, or {"type": "nrm_session", "error", "state"} with error login_required when the session cookie set by the hosted login page is missing, expired or revoked. The domain comes from domain_id or, when absent, the request's Host.
+//	@Tags			auth
+//	@Produce		html
+//	@Param			domain_id		query	string	false	"Domain ID (defaults to the domain serving the request's Host)"
+//	@Param			redirect_uri	query	string	true	"Registered redirect URI of the app; its origin receives the message"
+//	@Param			state			query	string	false	"Opaque value returned unchanged in the message"
+//	@Success		200				{string}	string	"Page posting the result to the parent window"
+//	@Failure		400				{string}	string	"Login page showing the error"
+//	@Failure		404				{string}	string	"Login page showing the error"
+//	@Router			/auth/session/refresh [get]
+func (h *LoginPageHandler) RefreshSession(c *gin.Context) {
+	page, err := h.preparePage(c, c.Query("domain_id"), c.Query("redirect_uri"), c.Query("state"), "", "")
+	if err != nil {
+		h.renderError(c, nil, err)
+		return
+	}
+	target, err := url.Parse(page.RedirectURI)
+	if err != nil {
+		h.renderError(c, nil, errInvalidLoginRequest("Invalid redirect_uri"))
+		return
+	}
+	origin := target.Scheme + "://" + target.Host
+	message := gin.H{"type": "nrm_session"}
+	if page.State != "" {
+		message["state"] = page.State
+	}
+
+	domainID := page.Domain.DomainID
+	sessionToken, _ := c.Cookie(sessionCookieName(domainID))
+	login, err := h.authService.RefreshSession(c.Request.Context(), domainID, sessionToken)
+	if err != nil {
+		_, code, _, ok := middleware.DescribeError(err)
+		if !ok {
+			log.Printf("%s %s: %v", c.Request.Method, c.FullPath(), err)
+		}
+		if code == "login_required" && sessionToken != "" {
+			h.setSessionCookie(c, domainID, "", -1)
+		}
+		message["error"] = code
+	} else {
+		message["access_token"] = login.AccessToken
+		message["token_type"] = "Bearer"
+	}
+
+	var body strings.Builder
+	if err := sessionTemplate.Execute(&body, gin.H{"Message": message, "Origin": origin}); err != nil {
+		log.Printf("Failed to render session page: %v", err)
+		c.String(http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Security-Policy", "frame-ancestors "+origin)
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(body.String()))
+}
+
+// EndSession godoc
+//
+//	@Summary		Sign out of the hosted login session
+//	@Description	Revoke the session cookie set by the hosted login page so /auth/session/refresh stops issuing tokens. Access tokens already issued stay valid until they expire or are revoked at /auth/revoke. The domain comes from domain_id or, when absent, the request's Host. Browsers may only post it from this server's pages or from the origin of one of the domain's branding redirect_uris; the Origin (or Referer) of other sites is refused with 403.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			domain_id		query		string	false	"Domain ID (defaults to the domain serving the request's Host)"
+//	@Param			redirect_uri	query		string	false	"Registered redirect URI to send the browser back to"
+//	@Success		200				{object}	MessageResponse
+//	@Success		303				{string}	string	"Redirect to redirect_uri"
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/auth/session/logout [post]
+func (h *LoginPageHandler) EndSession(c *gin.Context) {
+	domainID, err := h.resolveDomain(c, c.Query("domain_id"))
+	if err != nil {
+		var invalid errInvalidLoginRequest
+		if errors.As(err, &invalid) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: invalid.Error()})
+			return
+		}
+		respondError(c, err, "Failed to sign out")
+		return
+	}
+
+	allowed, err := h.allowsSignOutFrom(c, domainID)
+	if err != nil {
+		respondError(c, err, "Failed to sign out")
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Sign-out is not allowed from this origin"})
+		return
+	}
+
+	if sessionToken, _ := c.Cookie(sessionCookieName(domainID)); sessionToken != "" {
+		if err := h.authService.EndSession(c.Request.Context(), sessionToken); err != nil {
+			respondError(c, err, "Failed to sign out")
+			return
+		}
+	}
+	h.setSessionCookie(c, domainID, "", -1)
+
+	// Apps sign out with a top-level form post, so the browser can be sent back to them
+	if redirectURI := c.Query("redirect_uri"); redirectURI != "" {
+		if _, err := h.hostedLogin.PreparePage(c.Request.Context(), domainID, redirectURI, "", nil, nil); err != nil {
+			respondError(c, err, "Failed to sign out")
+			return
+		}
+		c.Redirect(http.StatusSeeOther, redirectURI)
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: "Signed out successfully"})
+}
+
+// allowsSignOutFrom reports whether the sign-out request comes from this server's pages or from
+// one of the domain's apps. The session cookie is SameSite=None for the refresh iframe, so it rides
+// along on posts from any site; their Origin, or the Referer's origin when browsers leave it out,
+// tells them apart. Requests with neither don't come from a page and are allowed.
+func (h *LoginPageHandler) allowsSignOutFrom(c *gin.Context, domainID uuid.UUID) (bool, error) {
+	origin := c.GetHeader("Origin")
+	if origin == "" {
+		referer, err := url.Parse(c.GetHeader("Referer"))
+		if err != nil {
+			return false, nil
+		}
+		if referer.Host == "" {
+			return true, nil
+		}
+		origin = referer.Scheme + "://" + referer.Host
+	}
+	if source, err := url.Parse(origin); err == nil && source.Host != "" && strings.EqualFold(source.Host, c.Request.Host) {
+		return true, nil
+	}
+	return h.hostedLogin.AllowsOrigin(c.Request.Context(), domainID, origin)
+}
+
+// sessionCookieName is per domain, so one browser can hold sessions of several domains served
+// from the same host.
+func sessionCookieName(domainID uuid.UUID) string {
+	return "nrm_session_" + domainID.String()
+}
+
+// setSessionCookie sets the session cookie for maxAge seconds, or clears it when maxAge is negative.
+func (h *LoginPageHandler) setSessionCookie(c *gin.Context, domainID uuid.UUID, token string, maxAge int) {
+	// SameSite=None lets the cookie reach the refresh iframe embedded by apps on other sites
+	sameSite := http.SameSiteLaxMode
+	if h.session.CookieSecure {
+		sameSite = http.SameSiteNoneMode
+	}
+	c.SetSameSite(sameSite)
+	c.SetCookie(sessionCookieName(domainID), token, maxAge, "/auth/session", "", h.session.CookieSecure, true)
+}
+
+// preparePage resolves the domain, from domainIDStr or the request's Host, and checks the request.
+func (h *LoginPageHandler) preparePage(c *gin.Context, domainIDStr, redirectURI, state, clientIDStr, fieldList string) (*services.HostedLoginPage, error) {
+	domainID, err := h.resolveDomain(c, domainIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var clientID *uuid.UUID
+	if clientIDStr != "" {
+		id, err := uuid.Parse(clientIDStr)
+		if err != nil {
+			return nil, errInvalidLoginRequest("Invalid client UUID")
+		}
+		clientID = &id
+	}
+
+	var fields []string
+	for _, field := range strings.Split(fieldList, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return h.hostedLogin.PreparePage(c.Request.Context(), domainID, redirectURI, state, clientID, fields)
+}
+
+// resolveDomain parses domainIDStr, or resolves the domain serving the request's Host when it is empty.
+func (h *LoginPageHandler) resolveDomain(c *gin.Context, domainIDStr string) (uuid.UUID, error) {
+	if domainIDStr == "" {
+		return h.authService.ResolveDomainID(c.Request.Context(), c.Request.Host)
+	}
+	domainID, err := uuid.Parse(domainIDStr)
+	if err != nil {
+		return uuid.Nil, errInvalidLoginRequest("Invalid domain UUID")
+	}
+	return domainID, nil
+}
+
+// errInvalidLoginRequest is a malformed login page request, shown to the user as is.
+type errInvalidLoginRequest string
+
+func (e errInvalidLoginRequest) Error() string {
+	return string(e)
+}
+
+// newView fills in the branding and request of page and sets a fresh CSRF token.
+func (h *LoginPageHandler) newView(c *gin.Context, page *services.HostedLoginPage) *loginPageView {
+	branding := page.Domain.Branding
+	view := &loginPageView{
+		Title:           branding.DisplayName,
+		LogoURL:         branding.LogoURL,
+		PrimaryColor:    branding.PrimaryColor,
+		BackgroundColor: branding.BackgroundColor,
+		Ready:           true,
+		CSRFToken:       newLoginCSRFToken(),
+		DomainID:        page.Domain.DomainID.String(),
+		RedirectURI:     page.RedirectURI,
+		State:           page.State,
+		Fields:          strings.Join(page.Fields, ","),
+		RequestedFields: page.Fields,
+		Step:            loginStepPassword,
+	}
+	if view.Title == "" {
+		view.Title = page.Domain.Name
+	}
+	if page.Client != nil {
+		view.ClientID = page.Client.ID.String()
+		view.ClientName = page.Client.Name
+	}
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(loginCSRFCookie, view.CSRFToken, 0, "/login", "", c.Request.TLS != nil, true)
+	return view
+}
+
+func newLoginCSRFToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// renderError shows err on the page, keeping the form when view is set.
+func (h *LoginPageHandler) renderError(c *gin.Context, view *loginPageView, err error) {
+	if view == nil {
+		view = &loginPageView{Title: "your account"}
+	}
+
+	var invalid errInvalidLoginRequest
+	var challenge *services.LoginChallengeError
+	status := http.StatusBadRequest
+	switch {
+	case errors.As(err, &invalid):
+		view.Error = invalid.Error()
+	case errors.As(err, &challenge):
+		status = http.StatusUnauthorized
+		view.Error = "This sign-in needs additional verification that this page does not support. Please sign in through your app."
+	default:
+		var ok bool
+		status, _, view.Error, ok = middleware.DescribeError(err)
+		if !ok {
+			log.Printf("%s %s: %v", c.Request.Method, c.FullPath(), err)
+			view.Error = "Sign-in failed, please try again later"
+		}
+	}
+	h.render(c, status, view)
+}
+
+func (h *LoginPageHandler) render(c *gin.Context, status int, view *loginPageView) {
+	if view.PrimaryColor == "" {
+		view.PrimaryColor = defaultLoginPrimaryColor
+	}
+	if view.BackgroundColor == "" {
+		view.BackgroundColor = defaultLoginBackgroundColor
+	}
+
+	var body strings.Builder
+	if err := loginTemplate.Execute(&body, view); err != nil {
+		log.Printf("Failed to render login page: %v", err)
+		c.String(http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Data(status, "text/html; charset=utf-8", []byte(body.String()))
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/internal/application/services"
+	"backend/internal/application/services/mocks"
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+// brandedDomains serves domains whose app is registered at https://app.acme.example.com.
+type brandedDomains struct {
+	repositories.DomainRepository
+}
+
+func (brandedDomains) GetByID(ctx context.Context, id uuid.UUID) (*entities.Domain, error) {
+	return &entities.Domain{DomainID: id, Branding: entities.DomainBranding{RedirectURIs: []string{"https://app.acme.example.com/callback"}}}, nil
+}
+
+func TestEndSessionOrigin(t *testing.T) {
+	domainID := uuid.New()
+	tests := []struct {
+		name    string
+		headers map[string]string
+		allowed bool
+	}{
+		{"registered app", map[string]string{"Origin": "https://app.acme.example.com"}, true},
+		{"this server", map[string]string{"Origin": "https://iam.example.com"}, true},
+		{"referer of a registered app", map[string]string{"Referer": "https://app.acme.example.com/settings"}, true},
+		{"not from a page", nil, true},
+		{"other site", map[string]string{"Origin": "https://evil.example.com"}, false},
+		{"other scheme", map[string]string{"Origin": "http://app.acme.example.com"}, false},
+		{"referer of another site", map[string]string{"Referer": "https://evil.example.com/"}, false},
+		{"opaque origin", map[string]string{"Origin": "null"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := mocks.NewMockAuthService(gomock.NewController(t))
+			if tt.allowed {
+				auth.EXPECT().EndSession(gomock.Any(), "session-token").Return(nil)
+			}
+			gin.SetMode(gin.TestMode)
+			handler := NewLoginPageHandler(auth, services.NewHostedLoginService(brandedDomains{}, nil, nil), &config.HostedSessionConfig{CookieSecure: true})
+			r := gin.New()
+			r.Use(middleware.ErrorHandler())
+			r.POST("/auth/session/logout", handler.EndSession)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/session/logout?domain_id="+domainID.String(), strings.NewReader(""))
+			req.Host = "iam.example.com"
+			req.AddCookie(&http.Cookie{Name: sessionCookieName(domainID), Value: "session-token"})
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			want := http.StatusForbidden
+			if tt.allowed {
+				want = http.StatusOK
+			}
+			if w.Code != want {
+				t.Errorf("status = %d, want %d: %s", w.Code, want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UpdateLoginRiskPolicyRequest struct {
+	CaptchaThreshold *int `json:"captcha_threshold" binding:"omitempty,min=1,max=100" example:"40"`
+	MFAThreshold     *int `json:"mfa_threshold" binding:"omitempty,min=1,max=100" example:"70"`
+	BlockThreshold   *int `json:"block_threshold" binding:"omitempty,min=1,max=100" example:"90"`
+	// CaptchaAfterFailures requires a CAPTCHA from an IP with this many recent failed logins
+	CaptchaAfterFailures *int `json:"captcha_after_failures" binding:"omitempty,min=1,max=100" example:"3"`
+}
+
+type LoginRiskHandler struct {
+	riskService services.LoginRiskService
+}
+
+func NewLoginRiskHandler(riskService services.LoginRiskService) *LoginRiskHandler {
+	return &LoginRiskHandler{riskService: riskService}
+}
+
+// GetRiskPolicy godoc
+//
+//	@Summary		Get login risk policy
+//	@Description	Get the domain's login risk thresholds (0-100). Unset thresholds are disabled.
+//	@Tags			risk
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{object}	entities.LoginRiskPolicy
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/risk-policy [get]
+func (h *LoginRiskHandler) GetRiskPolicy(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	policy, err := h.riskService.GetPolicy(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to get risk policy")
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdateRiskPolicy godoc
+//
+//	@Summary		Set login risk policy
+//	@Description	Replace the domain's login risk thresholds. Logins scoring at or above a threshold require a CAPTCHA, an MFA step-up, or are blocked; the most severe match wins. captcha_after_failures also requires a CAPTCHA from an IP with that many failed logins within LOGIN_RISK_FAILURE_WINDOW, whatever its score. Omitted or null thresholds are disabled.
+//	@Tags			risk
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string							true	"Domain ID"
+//	@Param			policy		body		UpdateLoginRiskPolicyRequest	true	"Risk thresholds"
+//	@Success		200			{object}	entities.LoginRiskPolicy
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/risk-policy [put]
+func (h *LoginRiskHandler) UpdateRiskPolicy(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req UpdateLoginRiskPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	policy := &entities.LoginRiskPolicy{
+		DomainID:             domainID,
+		CaptchaThreshold:     req.CaptchaThreshold,
+		MFAThreshold:         req.MFAThreshold,
+		BlockThreshold:       req.BlockThreshold,
+		CaptchaAfterFailures: req.CaptchaAfterFailures,
+	}
+	policy, err = h.riskService.SetPolicy(c.Request.Context(), policy)
+	if err != nil {
+		respondError(c, err, "Failed to update risk policy")
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UpdateMailSettingsRequest struct {
+	Provider    string  `json:"provider" enums:"smtp,ses" example:"smtp"`
+	Host        string  `json:"host" example:"smtp.acme.example.com"`
+	Port        string  `json:"port" example:"587"`
+	Region      string  `json:"region" example:"eu-west-1"`
+	Username    string  `json:"username" example:"mailer@acme.example.com"`
+	Password    *string `json:"password" example:"smtp-password"`
+	FromAddress string  `json:"from_address" binding:"required" example:"Acme <no-reply@acme.example.com>"`
+	Enabled     *bool   `json:"enabled" example:"true"`
+}
+
+type TestMailSettingsRequest struct {
+	To string `json:"to" binding:"omitempty,email" example:"admin@acme.example.com"`
+}
+
+type MailSettingsHandler struct {
+	mailSettingsService services.MailSettingsService
+}
+
+func NewMailSettingsHandler(mailSettingsService services.MailSettingsService) *MailSettingsHandler {
+	return &MailSettingsHandler{mailSettingsService: mailSettingsService}
+}
+
+// GetMailSettings godoc
+//
+//	@Summary		Get domain mail sender
+//	@Description	Get the domain's own outgoing mail sender. The password is never returned. 404 means the domain sends through the platform default.
+//	@Tags			mail
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{object}	entities.DomainMailSettings
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/mail-settings [get]
+func (h *MailSettingsHandler) GetMailSettings(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	settings, err := h.mailSettingsService.GetSettings(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to get mail settings")
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateMailSettings godoc
+//
+//	@Summary		Set domain mail sender
+//	@Description	Replace the domain's outgoing mail sender so emails to its users come from its own brand. Provider smtp needs a host, which must not resolve to a private, loopback or link-local address unless OUTBOUND_ALLOWED_NETWORKS allows it; ses needs a region and SES SMTP credentials. The password is stored encrypted and never returned; omitting it keeps the stored one. If sending through the domain's sender fails, the platform default is used.
+//	@Tags			mail
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string						true	"Domain ID"
+//	@Param			settings	body		UpdateMailSettingsRequest	true	"Mail sender"
+//	@Success		200			{object}	entities.DomainMailSettings
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/mail-settings [put]
+func (h *MailSettingsHandler) UpdateMailSettings(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req UpdateMailSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	settings := &entities.DomainMailSettings{
+		DomainID:    domainID,
+		Provider:    req.Provider,
+		Host:        req.Host,
+		Port:        req.Port,
+		Region:      req.Region,
+		Username:    req.Username,
+		FromAddress: req.FromAddress,
+		Enabled:     req.Enabled == nil || *req.Enabled,
+	}
+	settings, err = h.mailSettingsService.UpdateSettings(c.Request.Context(), settings, req.Password)
+	if err != nil {
+		respondError(c, err, "Failed to update mail settings")
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// DeleteMailSettings godoc
+//
+//	@Summary		Remove domain mail sender
+//	@Description	Remove the domain's own mail sender so it sends through the platform default again
+//	@Tags			mail
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		204			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/mail-settings [delete]
+func (h *MailSettingsHandler) DeleteMailSettings(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	if err := h.mailSettingsService.DeleteSettings(c.Request.Context(), domainID); err != nil {
+		respondError(c, err, "Failed to delete mail settings")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Mail settings deleted successfully"})
+}
+
+// TestMailSettings godoc
+//
+//	@Summary		Test domain mail sender
+//	@Description	Connect and authenticate with the domain's mail sender and, when to is given, send a test email through it. The result is returned in last_tested_at and last_test_error; a failed test still answers 200.
+//	@Tags			mail
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string					true	"Domain ID"
+//	@Param			test		body		TestMailSettingsRequest	false	"Test recipient"
+//	@Success		200			{object}	entities.DomainMailSettings
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/mail-settings/test [post]
+func (h *MailSettingsHandler) TestMailSettings(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req TestMailSettingsRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	settings, err := h.mailSettingsService.TestSettings(c.Request.Context(), domainID, req.To)
+	if err != nil {
+		respondError(c, err, "Failed to test mail settings")
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
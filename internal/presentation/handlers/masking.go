@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"strings"
+
+	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newUserMasker masks users for the admin presenting the request's bearer token. Requests without
+// one see masked fields masked. The route names the view in the audit log.
+func newUserMasker(c *gin.Context, masking services.DataMaskingService) *services.UserMasker {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		token = ""
+	}
+	viewer := masking.ResolveViewer(c.Request.Context(), token)
+	return masking.NewMasker(viewer, c.Request.Method+" "+c.FullPath())
+}
+
+// respondMaskedUser answers with the user masked for the requesting admin.
+func respondMaskedUser(c *gin.Context, masking services.DataMaskingService, status int, user *entities.User) {
+	masker := newUserMasker(c, masking)
+	masked, err := masker.Mask(c.Request.Context(), user)
+	if err != nil {
+		respondError(c, err, "Failed to prepare user")
+		return
+	}
+	c.JSON(status, masked)
+	masker.Finish(c.Request.Context())
+}
+
+// respondMaskedUsers answers with the users masked for the requesting admin.
+func respondMaskedUsers(c *gin.Context, masking services.DataMaskingService, status int, users []*entities.User) {
+	masker := newUserMasker(c, masking)
+	masked, err := masker.MaskAll(c.Request.Context(), users)
+	if err != nil {
+		respondError(c, err, "Failed to prepare users")
+		return
+	}
+	c.JSON(status, masked)
+	masker.Finish(c.Request.Context())
+}
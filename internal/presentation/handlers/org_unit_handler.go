@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OrgUnitRequest struct {
+	// ParentID places the unit under another unit of the domain; omit it for the top of the tree
+	ParentID    *uuid.UUID `json:"parent_id" format:"uuid" example:"b3c4d5e6-7f8a-4b9c-8d0e-1f2a3b4c5d6e"`
+	Name        string     `json:"name" binding:"required" example:"Platform Engineering"`
+	Description string     `json:"description" example:"Infrastructure and developer tooling"`
+}
+
+type SetUserOrgUnitRequest struct {
+	// OrgUnitID is the user's new org unit; null takes them out of any
+	OrgUnitID *uuid.UUID `json:"org_unit_id" format:"uuid" example:"c4d5e6f7-8a9b-4c0d-9e1f-2a3b4c5d6e7f"`
+}
+
+type AssignOrgUnitRoleRequest struct {
+	RoleID string `json:"role_id" binding:"required" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+}
+
+type OrgUnitHandler struct {
+	orgUnitService services.OrgUnitService
+	masking        services.DataMaskingService
+}
+
+func NewOrgUnitHandler(orgUnitService services.OrgUnitService, masking services.DataMaskingService) *OrgUnitHandler {
+	return &OrgUnitHandler{orgUnitService: orgUnitService, masking: masking}
+}
+
+// ListOrgUnits godoc
+//
+//	@Summary		List domain org units
+//	@Description	Get the org units of a domain, ordered by name; each names its parent, from which clients build the tree. Org unit admins get the units of their own unit's subtree.
+//	@Tags			org-units
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{array}		entities.OrgUnit
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/org-units [get]
+func (h *OrgUnitHandler) ListOrgUnits(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	units, err := h.orgUnitService.ListOrgUnits(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to list org units")
+		return
+	}
+	c.JSON(http.StatusOK, units)
+}
+
+// CreateOrgUnit godoc
+//
+//	@Summary		Create an org unit
+//	@Description	Create an org unit in the domain, under parent_id or at the top of the tree. Names are unique per domain.
+//	@Tags			org-units
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string			true	"Domain ID"
+//	@Param			unit		body		OrgUnitRequest	true	"Org unit data"
+//	@Success		201			{object}	entities.OrgUnit
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/org-units [post]
+func (h *OrgUnitHandler) CreateOrgUnit(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req OrgUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	unit, err := h.orgUnitService.CreateOrgUnit(c.Request.Context(), domainID, req.ParentID, req.Name, req.Description)
+	if err != nil {
+		respondError(c, err, "Failed to create org unit")
+		return
+	}
+	c.JSON(http.StatusCreated, unit)
+}
+
+// GetOrgUnit godoc
+//
+//	@Summary		Get an org unit
+//	@Description	Get org unit by ID
+//	@Tags			org-units
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Org unit ID"
+//	@Success		200	{object}	entities.OrgUnit
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/org-units/{id} [get]
+func (h *OrgUnitHandler) GetOrgUnit(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	unit, err := h.orgUnitService.GetOrgUnit(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get org unit")
+		return
+	}
+	c.JSON(http.StatusOK, unit)
+}
+
+// UpdateOrgUnit godoc
+//
+//	@Summary		Update an org unit
+//	@Description	Rename an org unit and move it, with its subtree, under parent_id, or to the top of the tree without one. Moving a unit under itself or one of its descendants is rejected with code org_unit_cycle.
+//	@Tags			org-units
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string			true	"Org unit ID"
+//	@Param			unit	body		OrgUnitRequest	true	"Org unit data"
+//	@Success		200		{object}	entities.OrgUnit
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/org-units/{id} [put]
+func (h *OrgUnitHandler) UpdateOrgUnit(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req OrgUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	unit, err := h.orgUnitService.UpdateOrgUnit(c.Request.Context(), id, req.ParentID, req.Name, req.Description)
+	if err != nil {
+		respondError(c, err, "Failed to update org unit")
+		return
+	}
+	c.JSON(http.StatusOK, unit)
+}
+
+// DeleteOrgUnit godoc
+//
+//	@Summary		Delete an org unit
+//	@Description	Delete an org unit without child units; its users are left without an org unit. A unit with children is rejected with 409 and code org_unit_not_empty.
+//	@Tags			org-units
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Org unit ID"
+//	@Success		204	{object}	MessageResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/org-units/{id} [delete]
+func (h *OrgUnitHandler) DeleteOrgUnit(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	if err := h.orgUnitService.DeleteOrgUnit(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to delete org unit")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Org unit deleted successfully"})
+}
+
+// AssignOrgUnitRole godoc
+//
+//	@Summary		Assign a role to an org unit's users
+//	@Description	Give a role of the domain to every user of the org unit and its descendants. Users whose role changed have their existing tokens revoked; the response counts them.
+//	@Tags			org-units
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id			path		string						true	"Org unit ID"
+//	@Param			assignment	body		AssignOrgUnitRoleRequest	true	"Role to assign"
+//	@Success		200			{object}	services.OrgUnitRoleAssignment
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/org-units/{id}/role-assignments [post]
+func (h *OrgUnitHandler) AssignOrgUnitRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req AssignOrgUnitRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	roleID, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+
+	assignment, err := h.orgUnitService.AssignRole(c.Request.Context(), id, roleID)
+	if err != nil {
+		respondError(c, err, "Failed to assign role")
+		return
+	}
+	c.JSON(http.StatusOK, assignment)
+}
+
+// SetUserOrgUnit godoc
+//
+//	@Summary		Set a user's org unit
+//	@Description	Move a user into an org unit of their domain, or out of any with a null org_unit_id. Org unit admins can only move users between the units of their own subtree.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string					true	"User ID"
+//	@Param			unit	body		SetUserOrgUnitRequest	true	"Org unit"
+//	@Success		200		{object}	entities.User
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/users/{id}/org-unit [put]
+func (h *OrgUnitHandler) SetUserOrgUnit(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req SetUserOrgUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	user, err := h.orgUnitService.SetUserOrgUnit(c.Request.Context(), id, req.OrgUnitID)
+	if err != nil {
+		respondError(c, err, "Failed to set org unit")
+		return
+	}
+	respondMaskedUser(c, h.masking, http.StatusOK, user)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PageLinks are the URLs of a listing's neighbouring pages, relative to the API root. Links that
+// do not apply, such as prev on the first page, are omitted.
+type PageLinks struct {
+	Self  string `json:"self" example:"/users?limit=10&page=2"`
+	First string `json:"first,omitempty" example:"/users?limit=10&page=1"`
+	Prev  string `json:"prev,omitempty" example:"/users?limit=10&page=1"`
+	Next  string `json:"next,omitempty" example:"/users?limit=10&page=3"`
+	Last  string `json:"last,omitempty" example:"/users?limit=10&page=5"`
+}
+
+// pageLinks builds the links of a page-numbered listing, keeping the request's other query
+// parameters, and sets them as an RFC 5988 Link header.
+func pageLinks(c *gin.Context, page, limit, totalPages int) PageLinks {
+	last := max(totalPages, 1)
+	at := func(p int) string {
+		return linkURL(c, map[string]string{"page": strconv.Itoa(p), "limit": strconv.Itoa(limit)})
+	}
+
+	links := PageLinks{Self: at(page), First: at(1), Last: at(last)}
+	if page > 1 {
+		links.Prev = at(min(page-1, last))
+	}
+	if page < last {
+		links.Next = at(page + 1)
+	}
+	setLinkHeader(c, links)
+	return links
+}
+
+// cursorLinks builds the links of a cursor-paginated listing, where param carries the cursor, and
+// sets them as an RFC 5988 Link header. There is no last page to link to.
+func cursorLinks(c *gin.Context, param, first, current, next string, hasMore bool) PageLinks {
+	links := PageLinks{
+		Self:  linkURL(c, map[string]string{param: current}),
+		First: linkURL(c, map[string]string{param: first}),
+	}
+	if hasMore {
+		links.Next = linkURL(c, map[string]string{param: next})
+	}
+	setLinkHeader(c, links)
+	return links
+}
+
+// linkURL returns the request's path and query with the given parameters replaced.
+func linkURL(c *gin.Context, params map[string]string) string {
+	query := c.Request.URL.Query()
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	return (&url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}).String()
+}
+
+func setLinkHeader(c *gin.Context, links PageLinks) {
+	var parts []string
+	for _, link := range []struct{ rel, url string }{
+		{"first", links.First}, {"prev", links.Prev}, {"next", links.Next}, {"last", links.Last},
+	} {
+		if link.url != "" {
+			parts = append(parts, "<"+link.url+`>; rel="`+link.rel+`"`)
+		}
+	}
+	if len(parts) > 0 {
+		// Added rather than set, so a successor-version link of a deprecated path is kept
+		c.Writer.Header().Add("Link", strings.Join(parts, ", "))
+	}
+}
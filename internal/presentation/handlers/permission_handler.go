@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreatePermissionRequest struct {
+	Resource    string `json:"resource" binding:"required" example:"documents"`
+	Action      string `json:"action" binding:"required" example:"read"`
+	Description string `json:"description" example:"Read documents"`
+}
+
+type AssignPermissionRequest struct {
+	PermissionID string `json:"permission_id" binding:"required" format:"uuid" example:"a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d"`
+}
+
+type PermissionHandler struct {
+	permissionService services.PermissionService
+}
+
+func NewPermissionHandler(permissionService services.PermissionService) *PermissionHandler {
+	return &PermissionHandler{permissionService: permissionService}
+}
+
+// ListPermissions godoc
+//
+//	@Summary		List domain permissions
+//	@Description	Get the permission catalog of a domain
+//	@Tags			permissions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{array}		entities.Permission
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/permissions [get]
+func (h *PermissionHandler) ListPermissions(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	permissions, err := h.permissionService.ListPermissions(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to list permissions")
+		return
+	}
+	c.JSON(http.StatusOK, permissions)
+}
+
+// CreatePermission godoc
+//
+//	@Summary		Create a permission
+//	@Description	Add a resource:action permission to the domain catalog
+//	@Tags			permissions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string					true	"Domain ID"
+//	@Param			permission	body		CreatePermissionRequest	true	"Permission data"
+//	@Success		201			{object}	entities.Permission
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/permissions [post]
+func (h *PermissionHandler) CreatePermission(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req CreatePermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	permission, err := h.permissionService.CreatePermission(c.Request.Context(), domainID, req.Resource, req.Action, req.Description)
+	if err != nil {
+		respondError(c, err, "Failed to create permission")
+		return
+	}
+	c.JSON(http.StatusCreated, permission)
+}
+
+// DeletePermission godoc
+//
+//	@Summary		Delete a permission
+//	@Description	Remove a permission from the catalog and from every role it was assigned to
+//	@Tags			permissions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Permission ID"
+//	@Success		204	{object}	MessageResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/permissions/{id} [delete]
+func (h *PermissionHandler) DeletePermission(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	if err := h.permissionService.DeletePermission(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to delete permission")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Permission deleted successfully"})
+}
+
+// ListRolePermissions godoc
+//
+//	@Summary		List role permissions
+//	@Description	Get the catalog permissions assigned to a role
+//	@Tags			permissions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Role ID"
+//	@Success		200	{array}		entities.Permission
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/roles/{id}/permissions [get]
+func (h *PermissionHandler) ListRolePermissions(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+
+	permissions, err := h.permissionService.ListRolePermissions(c.Request.Context(), roleID)
+	if err != nil {
+		respondError(c, err, "Failed to list role permissions")
+		return
+	}
+	c.JSON(http.StatusOK, permissions)
+}
+
+// AssignRolePermission godoc
+//
+//	@Summary		Assign a permission to a role
+//	@Description	Grant a catalog permission from the role's domain to the role
+//	@Tags			permissions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id			path		string					true	"Role ID"
+//	@Param			permission	body		AssignPermissionRequest	true	"Permission to assign"
+//	@Success		204			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/roles/{id}/permissions [post]
+func (h *PermissionHandler) AssignRolePermission(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+
+	var req AssignPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	permissionID, err := uuid.Parse(req.PermissionID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid permission UUID"})
+		return
+	}
+
+	err = h.permissionService.AssignPermission(c.Request.Context(), roleID, permissionID)
+	if err != nil {
+		respondError(c, err, "Failed to assign permission")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Permission assigned successfully"})
+}
+
+// RevokeRolePermission godoc
+//
+//	@Summary		Revoke a permission from a role
+//	@Description	Remove a catalog permission assignment from the role
+//	@Tags			permissions
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id				path		string	true	"Role ID"
+//	@Param			permissionId	path		string	true	"Permission ID"
+//	@Success		204				{object}	MessageResponse
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/roles/{id}/permissions/{permissionId} [delete]
+func (h *PermissionHandler) RevokeRolePermission(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+	permissionID, err := uuid.Parse(c.Param("permissionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid permission UUID"})
+		return
+	}
+
+	err = h.permissionService.RevokePermission(c.Request.Context(), roleID, permissionID)
+	if err != nil {
+		respondError(c, err, "Failed to revoke permission")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Permission revoked successfully"})
+}
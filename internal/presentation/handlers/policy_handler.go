@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreatePolicyRequest struct {
+	Name        string          `json:"name" binding:"required" example:"deny-outside-office-hours"`
+	Description string          `json:"description" example:"Deny writes outside office hours"`
+	Document    json.RawMessage `json:"document" binding:"required" swaggertype:"object"`
+}
+
+type UpdatePolicyRequest struct {
+	Name        string          `json:"name" binding:"required" example:"deny-outside-office-hours"`
+	Description string          `json:"description" example:"Deny writes outside office hours"`
+	Document    json.RawMessage `json:"document" binding:"required" swaggertype:"object"`
+}
+
+type AuthorizeRequest struct {
+	UserID             string                 `json:"user_id" binding:"required" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Resource           string                 `json:"resource" binding:"required" example:"documents"`
+	Action             string                 `json:"action" binding:"required" example:"read"`
+	ResourceAttributes map[string]interface{} `json:"resource_attributes"`
+	Context            map[string]interface{} `json:"context"`
+}
+
+type PolicyHandler struct {
+	policyService services.PolicyService
+}
+
+func NewPolicyHandler(policyService services.PolicyService) *PolicyHandler {
+	return &PolicyHandler{policyService: policyService}
+}
+
+// GetPolicy godoc
+//
+//	@Summary		Get a policy
+//	@Description	Get ABAC policy by ID
+//	@Tags			policies
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Policy ID"
+//	@Success		200	{object}	entities.Policy
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/policies/{id} [get]
+func (h *PolicyHandler) GetPolicy(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	policy, err := h.policyService.GetPolicyByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get policy")
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// ListPolicies godoc
+//
+//	@Summary		List domain policies
+//	@Description	Get all ABAC policies of a domain
+//	@Tags			policies
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{array}		entities.Policy
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/policies [get]
+func (h *PolicyHandler) ListPolicies(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	policies, err := h.policyService.ListPolicies(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to list policies")
+		return
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+// CreatePolicy godoc
+//
+//	@Summary		Create a policy
+//	@Description	Create an ABAC policy for the domain. The document holds effect (allow or deny), resources, actions and optional conditions on user.*, domain.*, claims.*, resource.* and context.* attributes.
+//	@Tags			policies
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string				true	"Domain ID"
+//	@Param			policy		body		CreatePolicyRequest	true	"Policy data"
+//	@Success		201			{object}	entities.Policy
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/policies [post]
+func (h *PolicyHandler) CreatePolicy(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req CreatePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	policy, err := h.policyService.CreatePolicy(c.Request.Context(), domainID, req.Name, req.Description, req.Document)
+	if err != nil {
+		respondError(c, err, "Failed to create policy")
+		return
+	}
+	c.JSON(http.StatusCreated, policy)
+}
+
+// UpdatePolicy godoc
+//
+//	@Summary		Update a policy
+//	@Description	Replace an ABAC policy's name, description and document
+//	@Tags			policies
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string				true	"Policy ID"
+//	@Param			policy	body		UpdatePolicyRequest	true	"Policy data"
+//	@Success		200		{object}	entities.Policy
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/policies/{id} [put]
+func (h *PolicyHandler) UpdatePolicy(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req UpdatePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	policy, err := h.policyService.UpdatePolicy(c.Request.Context(), id, req.Name, req.Description, req.Document)
+	if err != nil {
+		respondError(c, err, "Failed to update policy")
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeletePolicy godoc
+//
+//	@Summary		Delete a policy
+//	@Description	Delete ABAC policy by ID
+//	@Tags			policies
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Policy ID"
+//	@Success		204	{object}	MessageResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/policies/{id} [delete]
+func (h *PolicyHandler) DeletePolicy(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	if err := h.policyService.DeletePolicy(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to delete policy")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Policy deleted successfully"})
+}
+
+// Authorize godoc
+//
+//	@Summary		Authorize with ABAC policies
+//	@Description	Ask whether a user may perform an action on a resource. The caller must be an admin of the user's domain. The policies of the user's domain are evaluated against the user's attributes, merged role claims and the supplied resource attributes and context; any matching deny wins, otherwise one matching allow is required.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			request	body		AuthorizeRequest	true	"Authorization request"
+//	@Success		200		{object}	services.AuthorizeResult
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/auth/authorize [post]
+func (h *PolicyHandler) Authorize(c *gin.Context) {
+	var req AuthorizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user UUID"})
+		return
+	}
+
+	result, err := h.policyService.Authorize(c.Request.Context(), &services.AuthorizeRequest{
+		UserID:             userID,
+		Resource:           req.Resource,
+		Action:             req.Action,
+		ResourceAttributes: req.ResourceAttributes,
+		Context:            req.Context,
+	})
+	if err != nil {
+		respondError(c, err, "Failed to authorize")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
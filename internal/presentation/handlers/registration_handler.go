@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RegisterRequest struct {
+	FirstName        string `json:"first_name" binding:"required" example:"Jane"`
+	LastName         string `json:"last_name" binding:"required" example:"Doe"`
+	Username         string `json:"username" binding:"required" example:"jdoe"`
+	Email            string `json:"email" binding:"required,email" example:"jane.doe@example.com"`
+	Password         string `json:"password" binding:"omitempty,min=6" example:"S3cure-pass"`
+	RegistrationCode string `json:"registration_code" example:"K7QF2M9XD4TA8BNC"`
+}
+
+type CreateRegistrationCodeRequest struct {
+	RoleID    *uuid.UUID `json:"role_id" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	MaxUses   *int       `json:"max_uses" binding:"omitempty,min=1" example:"50"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2026-12-31T23:59:59Z"`
+}
+
+type RegistrationHandler struct {
+	registrationService services.RegistrationService
+	authService         services.AuthService
+}
+
+func NewRegistrationHandler(registrationService services.RegistrationService, authService services.AuthService) *RegistrationHandler {
+	return &RegistrationHandler{registrationService: registrationService, authService: authService}
+}
+
+// Register godoc
+//
+//	@Summary		Sign up to a domain
+//	@Description	Create an account in the domain without an administrator. Domains open to registration accept anyone and give them the domain's default role; other domains require a registration code, whose role (or the default role) the user gets. A closed domain returns 403 with code registration_closed and an unusable code returns 403 with code invalid_registration_code. Password rules and uniqueness checks are the same as for POST /users.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-NRM-DID		header		string			false	"Domain ID (required unless X-NRM-Domain is set)"
+//	@Param			X-NRM-Domain	header		string			false	"Domain hostname or alias, used when X-NRM-DID is absent"
+//	@Param			registration	body		RegisterRequest	true	"Sign-up data"
+//	@Success		201				{object}	entities.User
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		409				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/register [post]
+func (h *RegistrationHandler) Register(c *gin.Context) {
+	domainID, ok := resolveLoginDomain(c, h.authService)
+	if !ok {
+		return
+	}
+
+	var req RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	user, err := h.registrationService.Register(c.Request.Context(), domainID, req.RegistrationCode, req.FirstName, req.LastName, req.Username, req.Email, req.Password)
+	if err != nil {
+		respondError(c, err, "Failed to register")
+		return
+	}
+	c.JSON(http.StatusCreated, user)
+}
+
+// CreateRegistrationCode godoc
+//
+//	@Summary		Create a registration code
+//	@Description	Issue a code that lets people sign up to the domain through /auth/register. Without role_id users get the domain's default role; without max_uses or expires_at the code has no use limit or expiry. The code is only returned in this response.
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string							true	"Domain ID"
+//	@Param			code		body		CreateRegistrationCodeRequest	true	"Registration code settings"
+//	@Success		201			{object}	services.CreatedRegistrationCode
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/registration-codes [post]
+func (h *RegistrationHandler) CreateRegistrationCode(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req CreateRegistrationCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	code, err := h.registrationService.CreateCode(c.Request.Context(), domainID, req.RoleID, req.MaxUses, req.ExpiresAt)
+	if err != nil {
+		respondError(c, err, "Failed to create registration code")
+		return
+	}
+	c.JSON(http.StatusCreated, code)
+}
+
+// ListRegistrationCodes godoc
+//
+//	@Summary		List registration codes
+//	@Description	Get all registration codes of a domain, including revoked and used-up ones
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{array}		entities.RegistrationCode
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/registration-codes [get]
+func (h *RegistrationHandler) ListRegistrationCodes(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	codes, err := h.registrationService.ListCodes(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to list registration codes")
+		return
+	}
+	c.JSON(http.StatusOK, codes)
+}
+
+// RevokeRegistrationCode godoc
+//
+//	@Summary		Revoke a registration code
+//	@Description	Revoke a registration code so it can no longer be used to sign up; existing accounts are unaffected
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			codeId		path		string	true	"Registration code ID"
+//	@Success		204			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/registration-codes/{codeId} [delete]
+func (h *RegistrationHandler) RevokeRegistrationCode(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+	codeID, err := uuid.Parse(c.Param("codeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid registration code UUID"})
+		return
+	}
+
+	if err := h.registrationService.RevokeCode(c.Request.Context(), domainID, codeID); err != nil {
+		respondError(c, err, "Failed to revoke registration code")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Registration code revoked successfully"})
+}
@@ -1,31 +1,44 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/repositories"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type CreateRoleRequest struct {
-	RoleName   string                 `json:"role_name" binding:"required"`
+	RoleName   string                 `json:"role_name" binding:"required" example:"editor"`
 	RoleClaims map[string]interface{} `json:"role_claims"`
 }
 
 type UpdateRoleRequest struct {
-	RoleName   string                 `json:"role_name" binding:"required"`
-	RoleClaims map[string]interface{} `json:"role_claims"`
+	RoleName   string                   `json:"role_name" binding:"required" example:"editor"`
+	RoleClaims map[string]interface{}   `json:"role_claims"`
+	Notify     *RoleChangeNotifyRequest `json:"notify"`
+}
+
+// RoleChangeNotifyRequest asks for emails describing the access gained or lost through a role update.
+type RoleChangeNotifyRequest struct {
+	Users       bool     `json:"users" example:"true"`
+	AdminEmails []string `json:"admin_emails" binding:"omitempty,dive,email" example:"security@acme.example.com"`
 }
 
 type RoleHandler struct {
 	roleService services.RoleService
+	masking     services.DataMaskingService
 }
 
-func NewRoleHandler(roleService services.RoleService) *RoleHandler {
-	return &RoleHandler{roleService: roleService}
+func NewRoleHandler(roleService services.RoleService, masking services.DataMaskingService) *RoleHandler {
+	return &RoleHandler{roleService: roleService, masking: masking}
 }
 
 // GetRole godoc
@@ -35,26 +48,81 @@ func NewRoleHandler(roleService services.RoleService) *RoleHandler {
 //	@Tags			roles
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
 //	@Param			id	path		string			true	"Role ID"
 //	@Success		200	{object}	entities.Role
-//	@Failure		400	{object}	map[string]string
-//	@Failure		404	{object}	map[string]string
-//	@Router			/roles/{id} [get]
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/roles/{id} [get]
 func (h *RoleHandler) GetRole(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
 		return
 	}
-	role, err := h.roleService.GetRoleByID(id)
+	role, err := h.roleService.GetRoleByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		respondError(c, err, "Failed to get role")
 		return
 	}
 	c.JSON(http.StatusOK, role)
 }
 
+// ListRoleMembers godoc
+//
+//	@Summary		List role members
+//	@Description	Get the users holding a role, directly or through a group, ordered by username; review them before editing or deleting the role. Fields the domain masks (see /domains/{domainId}/data-masking) are masked unless the bearer token grants pii:read in the role's domain.
+//	@Tags			roles
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string	true	"Role ID"
+//	@Param			page	query		int		false	"Page number"		minimum(1)	default(1)
+//	@Param			limit	query		int		false	"Items per page"	minimum(1)	maximum(100)	default(10)
+//	@Success		200		{object}	UserListResponse
+//	@Header			200		{string}	Link	"Links to the first, previous, next and last pages (RFC 5988)"
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/roles/{id}/users [get]
+func (h *RoleHandler) ListRoleMembers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	result, err := h.roleService.ListRoleMembers(c.Request.Context(), id, page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to list role members")
+		return
+	}
+	masker := newUserMasker(c, h.masking)
+	if result.Users, err = masker.MaskAll(c.Request.Context(), result.Users); err != nil {
+		respondError(c, err, "Failed to prepare users")
+		return
+	}
+	c.JSON(http.StatusOK, UserListResponse{UserListResult: result, Links: pageLinks(c, result.Page, result.Limit, result.TotalPages)})
+	masker.Finish(c.Request.Context())
+}
+
 // GetRolesByDomain godoc
 //
 //	@Summary		Get roles by domain
@@ -62,44 +130,136 @@ func (h *RoleHandler) GetRole(c *gin.Context) {
 //	@Tags			roles
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
 //	@Param			domainId	path		string			true	"Domain ID"
 //	@Success		200			{array}		entities.Role
-//	@Failure		400			{object}	map[string]string
-//	@Failure		500			{object}	map[string]string
-//	@Router			/domains/{domainId}/roles [get]
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/roles [get]
 func (h *RoleHandler) GetRolesByDomain(c *gin.Context) {
 	domainIdStr := c.Param("domainId")
 	domainID, err := uuid.Parse(domainIdStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
 		return
 	}
-	roles, err := h.roleService.GetRolesByDomainID(domainID)
+	roles, err := h.roleService.GetRolesByDomainID(c.Request.Context(), domainID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get roles"})
+		respondError(c, err, "Failed to get roles")
 		return
 	}
 	c.JSON(http.StatusOK, roles)
 }
 
+// ExportRoles godoc
+//
+//	@Summary		Export roles
+//	@Description	Stream every role of a domain as CSV or JSON for compliance reviews. Rows are written as they are read from the database; in CSV the role claims are a JSON-encoded column.
+//	@Tags			roles
+//	@Produce		text/csv
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	query		string	true	"Domain ID"
+//	@Param			format		query		string	false	"Export format"	Enums(csv, json)	default(csv)
+//	@Success		200			{array}		entities.Role
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/roles/export [get]
+func (h *RoleHandler) ExportRoles(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Query("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	stream, err := newExportStream(c, strings.ToLower(c.Query("format")), "roles",
+		[]string{"id", "domain_id", "role_name", "role_claims", "created_at", "updated_at"})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unsupported format, use csv or json"})
+		return
+	}
+
+	err = h.roleService.ExportRoles(c.Request.Context(), domainID, func(role *entities.Role) error {
+		claims, err := json.Marshal(role.RoleClaims)
+		if err != nil {
+			return err
+		}
+		return stream.Write([]string{
+			role.ID.String(), role.DomainID.String(), role.RoleName, string(claims),
+			formatExportTime(role.CreatedAt), formatExportTime(role.UpdatedAt),
+		}, role)
+	})
+	stream.Finish(err, "Failed to export roles")
+}
+
+// BatchGetRoles godoc
+//
+//	@Summary		Get roles by ID
+//	@Description	Get up to 100 roles in one request. Roles are returned in the order of their IDs in the request (an ID repeated in the request is answered once), and IDs without a role are listed in missing.
+//	@Tags			roles
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			request	body		BatchGetRequest	true	"Role IDs"
+//	@Success		200		{object}	services.RoleBatch
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/roles/batch-get [post]
+func (h *RoleHandler) BatchGetRoles(c *gin.Context) {
+	var req BatchGetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	batch, err := h.roleService.GetRolesByIDs(c.Request.Context(), req.IDs)
+	if err != nil {
+		respondError(c, err, "Failed to get roles")
+		return
+	}
+	c.JSON(http.StatusOK, batch)
+}
+
 // ListRoles godoc
 //
 //	@Summary		List roles with pagination
-//	@Description	Get roles with pagination and search
+//	@Description	Get roles with pagination, search and filters. Sort by role_name (the default), created_at or updated_at, optionally suffixed with :asc or :desc. Use claim to find roles granting a permission, either as a top-level claim key or an entry in the permissions array. Set cursor to page through roles oldest first instead, which stays fast however deep the listing goes: pass an empty cursor for the first page and the next_cursor of each response for the one after it. Cursor pages carry roles, limit and next_cursor (omitted on the last page) in place of page, total and total_pages.
 //	@Tags			roles
 //	@Accept			json
 //	@Produce		json
-//	@Param			domainId	query		string	false	"Domain ID to filter roles"
-//	@Param			search		query		string	false	"Search term for role name"
-//	@Param			page		query		int		false	"Page number (default: 1)"
-//	@Param			limit		query		int		false	"Items per page (default: 10, max: 100)"
-//	@Success		200			{object}	repositories.RoleListResult
-//	@Failure		400			{object}	map[string]string
-//	@Failure		500			{object}	map[string]string
-//	@Router			/roles [get]
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId			query		string	false	"Domain to list; defaults to the domain of a domain admin's token and is required otherwise"
+//	@Param			search				query		string	false	"Search term for role name"
+//	@Param			claim				query		string	false	"Claim key to match, e.g. users:write"
+//	@Param			created_after		query		string	false	"Only roles created at or after this RFC 3339 time"
+//	@Param			created_before		query		string	false	"Only roles created before this RFC 3339 time"
+//	@Param			sort				query		string	false	"Sort field and direction, e.g. role_name:desc"
+//	@Param			page				query		int		false	"Page number"		minimum(1)	default(1)
+//	@Param			limit				query		int		false	"Items per page"	minimum(1)	maximum(100)	default(10)
+//	@Param			cursor				query		string	false	"Cursor pagination: empty for the first page, then the previous response's next_cursor"
+//	@Param			X-Consistency-Token	header		string	false	"Token from a previous write; replicas behind it are not read"
+//	@Success		200					{object}	RoleListResponse
+//	@Header			200					{string}	Link	"Links to the first, previous, next and last pages (RFC 5988)"
+//	@Failure		400					{object}	ErrorResponse
+//	@Failure		401					{object}	ErrorResponse
+//	@Failure		403					{object}	ErrorResponse
+//	@Failure		500					{object}	ErrorResponse
+//	@Router			/api/v1/roles [get]
 func (h *RoleHandler) ListRoles(c *gin.Context) {
 	// Parse query parameters
 	search := c.DefaultQuery("search", "")
+	claim := c.DefaultQuery("claim", "")
 	domainIdStr := c.DefaultQuery("domainId", "")
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "10")
@@ -118,17 +278,38 @@ func (h *RoleHandler) ListRoles(c *gin.Context) {
 	if domainIdStr != "" {
 		domainID, err = uuid.Parse(domainIdStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain UUID"})
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+			return
+		}
+	}
+
+	filter := repositories.RoleListFilter{Search: search, Claim: claim}
+	var ok bool
+	if filter.CreatedAfter, filter.CreatedBefore, ok = parseCreatedRange(c); !ok {
+		return
+	}
+	if filter.Sort, err = repositories.ParseListSort(c.Query("sort"), repositories.RoleSortFields); err != nil {
+		respondError(c, err, "Failed to list roles")
+		return
+	}
+
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		result, err := h.roleService.ListRolesAfter(c.Request.Context(), filter, domainID, cursor, limit)
+		if err != nil {
+			respondError(c, err, "Failed to list roles")
 			return
 		}
+		links := cursorLinks(c, "cursor", "", cursor, result.NextCursor, result.NextCursor != "")
+		c.JSON(http.StatusOK, RoleCursorListResponse{RoleCursorPage: result, Links: links})
+		return
 	}
 
-	result, err := h.roleService.ListRolesWithPagination(search, domainID, page, limit)
+	result, err := h.roleService.ListRolesWithPagination(c.Request.Context(), filter, domainID, page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list roles"})
+		respondError(c, err, "Failed to list roles")
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, RoleListResponse{RoleListResult: result, Links: pageLinks(c, result.Page, result.Limit, result.TotalPages)})
 }
 
 // CreateRole godoc
@@ -138,65 +319,160 @@ func (h *RoleHandler) ListRoles(c *gin.Context) {
 //	@Tags			roles
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
 //	@Param			domainId	path		string					true	"Domain ID"
 //	@Param			role		body		CreateRoleRequest		true	"Role data"
 //	@Success		201			{object}	entities.Role
-//	@Failure		400			{object}	map[string]string
-//	@Failure		500			{object}	map[string]string
-//	@Router			/domains/{domainId}/roles [post]
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/roles [post]
 func (h *RoleHandler) CreateRole(c *gin.Context) {
 	domainIdStr := c.Param("domainId")
 	domainID, err := uuid.Parse(domainIdStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
 		return
 	}
 
 	var req CreateRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	role, err := h.roleService.CreateRole(domainID, req.RoleName, req.RoleClaims)
+	role, err := h.roleService.CreateRole(c.Request.Context(), domainID, req.RoleName, req.RoleClaims)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create role"})
+		respondError(c, err, "Failed to create role")
 		return
 	}
 	c.JSON(http.StatusCreated, role)
 }
 
+// ListRoleRevisions godoc
+//
+//	@Summary		List role revisions
+//	@Description	Get the change history of a role, newest first. Each revision records who made the update (actor_id, null for the platform operator), the name and claims before and after it, and the claims diff: top-level claims added, removed and changed. Rollbacks name the revision they undid in rollback_of.
+//	@Tags			roles
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string	true	"Role ID"
+//	@Param			page	query		int		false	"Page number"		minimum(1)	default(1)
+//	@Param			limit	query		int		false	"Items per page"	minimum(1)	maximum(100)	default(10)
+//	@Success		200		{object}	RoleRevisionListResponse
+//	@Header			200		{string}	Link	"Links to the first, previous, next and last pages (RFC 5988)"
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/roles/{id}/revisions [get]
+func (h *RoleHandler) ListRoleRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	result, err := h.roleService.ListRoleRevisions(c.Request.Context(), id, page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to list role revisions")
+		return
+	}
+	c.JSON(http.StatusOK, RoleRevisionListResponse{RoleRevisionListResult: result, Links: pageLinks(c, result.Page, result.Limit, result.TotalPages)})
+}
+
+// RollbackRole godoc
+//
+//	@Summary		Roll back a role revision
+//	@Description	Undo a revision of a role: the role gets back the name and claims it had before that revision. The rollback is itself recorded as a new revision with rollback_of set, and publishes role.updated like any update.
+//	@Tags			roles
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Role ID"
+//	@Param			rev	path		int		true	"Revision number"
+//	@Success		200	{object}	entities.Role
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/roles/{id}/revisions/{rev}/rollback [post]
+func (h *RoleHandler) RollbackRole(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("rev"))
+	if err != nil || revision < 1 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid revision number"})
+		return
+	}
+
+	role, err := h.roleService.RollbackRole(c.Request.Context(), id, revision)
+	if err != nil {
+		respondError(c, err, "Failed to roll back role")
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
+
 // UpdateRole godoc
 //
 //	@Summary		Update a role
-//	@Description	Update role by ID
+//	@Description	Update role by ID. Every update is recorded as a revision (see /roles/{id}/revisions). Set notify to email affected users (users: true) and/or admins (admin_emails) a summary of the permissions each holder gained or lost, including holders through groups. Emails are sent after the response.
 //	@Tags			roles
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
 //	@Param			id		path		string					true	"Role ID"
 //	@Param			role	body		UpdateRoleRequest		true	"Role data"
 //	@Success		200		{object}	entities.Role
-//	@Failure		400		{object}	map[string]string
-//	@Failure		404		{object}	map[string]string
-//	@Failure		500		{object}	map[string]string
-//	@Router			/roles/{id} [put]
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/roles/{id} [put]
 func (h *RoleHandler) UpdateRole(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
 		return
 	}
 
 	var req UpdateRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	role, err := h.roleService.UpdateRole(id, req.RoleName, req.RoleClaims)
+	var notify *services.RoleChangeNotification
+	if req.Notify != nil {
+		notify = &services.RoleChangeNotification{Users: req.Notify.Users, AdminEmails: req.Notify.AdminEmails}
+	}
+
+	role, err := h.roleService.UpdateRole(c.Request.Context(), id, req.RoleName, req.RoleClaims, notify)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		respondError(c, err, "Failed to update role")
 		return
 	}
 	c.JSON(http.StatusOK, role)
@@ -205,27 +481,59 @@ func (h *RoleHandler) UpdateRole(c *gin.Context) {
 // DeleteRole godoc
 //
 //	@Summary		Delete a role
-//	@Description	Delete role by ID
+//	@Description	Delete a role. With reassign_to, its users, group grants, invitations and registration codes move to that role of the same domain, as does the default registration role. Without it, a role still held by users or referenced by invitations or registration codes is not deleted and the response is 409 with code role_in_use and the counts, and deleting the default registration role also returns 409 with code role_in_use; groups granting the role lose the grant. Permission assignments are always deleted. With dry_run=true nothing is changed and the response counts what would be.
 //	@Tags			roles
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string			true	"Role ID"
-//	@Success		204	{object}	map[string]string
-//	@Failure		400	{object}	map[string]string
-//	@Failure		500	{object}	map[string]string
-//	@Router			/roles/{id} [delete]
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id			path		string	true	"Role ID"
+//	@Param			reassign_to	query		string	false	"Role ID to move the role's users and grants to"
+//	@Param			dry_run		query		bool	false	"Return the planned effect without deleting"
+//	@Success		200			{object}	services.RoleDeletionPlan	"Dry run"
+//	@Success		204			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	RoleInUseResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/roles/{id} [delete]
 func (h *RoleHandler) DeleteRole(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var replacementID *uuid.UUID
+	if reassignTo := c.Query("reassign_to"); reassignTo != "" {
+		parsed, err := uuid.Parse(reassignTo)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid reassign_to UUID"})
+			return
+		}
+		replacementID = &parsed
+	}
+	dryRun, ok := parseDryRun(c)
+	if !ok {
 		return
 	}
 
-	err = h.roleService.DeleteRole(id)
+	plan, err := h.roleService.DeleteRole(c.Request.Context(), id, replacementID, dryRun)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete role"})
+		var inUse *services.RoleInUseError
+		if errors.As(err, &inUse) {
+			c.JSON(http.StatusConflict, RoleInUseResponse{Error: "Role is still in use", Code: "role_in_use", Dependents: inUse.Dependents})
+			return
+		}
+		respondError(c, err, "Failed to delete role")
+		return
+	}
+	if dryRun {
+		c.JSON(http.StatusOK, plan)
 		return
 	}
-	c.JSON(http.StatusNoContent, gin.H{"message": "Role deleted successfully"})
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Role deleted successfully"})
 }
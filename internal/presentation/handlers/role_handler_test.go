@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"backend/internal/application/services"
+	"backend/internal/application/services/mocks"
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func newRoleRouter(roles services.RoleService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewRoleHandler(roles, nil)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	api := r.Group("/api/v1")
+	api.GET("/roles/:id", handler.GetRole)
+	api.POST("/domains/:domainId/roles", handler.CreateRole)
+	api.PUT("/roles/:id", handler.UpdateRole)
+	api.DELETE("/roles/:id", handler.DeleteRole)
+	return r
+}
+
+func TestRoleHandler(t *testing.T) {
+	id, domainID, replacementID := uuid.New(), uuid.New(), uuid.New()
+	role := &entities.Role{ID: id, DomainID: domainID, RoleName: "editor", RoleClaims: map[string]interface{}{"posts": true}}
+	claims := map[string]interface{}{"posts": true}
+	inUse := &services.RoleInUseError{Err: domainerrors.Conflict("role is still in use"), Dependents: repositories.RoleDependents{Users: 3}}
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		expect       func(roles *mocks.MockRoleServiceMockRecorder)
+		want         int
+		wantBody     map[string]interface{}
+	}{
+		{"get: malformed ID", http.MethodGet, "/api/v1/roles/editor", "", nil, http.StatusBadRequest,
+			map[string]interface{}{"error": "Invalid UUID"}},
+		{"get: not found", http.MethodGet, "/api/v1/roles/" + id.String(), "", func(roles *mocks.MockRoleServiceMockRecorder) {
+			roles.GetRoleByID(gomock.Any(), id).Return(nil, domainerrors.NotFound("role not found"))
+		}, http.StatusNotFound, map[string]interface{}{"error": "Role not found", "code": "not_found"}},
+		{"get", http.MethodGet, "/api/v1/roles/" + id.String(), "", func(roles *mocks.MockRoleServiceMockRecorder) {
+			roles.GetRoleByID(gomock.Any(), id).Return(role, nil)
+		}, http.StatusOK, map[string]interface{}{"role_name": "editor"}},
+
+		{"create: malformed domain ID", http.MethodPost, "/api/v1/domains/acme/roles", `{"role_name":"editor"}`, nil, http.StatusBadRequest,
+			map[string]interface{}{"error": "Invalid domain UUID"}},
+		{"create: missing name", http.MethodPost, "/api/v1/domains/" + domainID.String() + "/roles", `{"role_claims":{}}`, nil, http.StatusBadRequest, nil},
+		{"create: invalid claims", http.MethodPost, "/api/v1/domains/" + domainID.String() + "/roles", `{"role_name":"editor","role_claims":{"posts":true}}`,
+			func(roles *mocks.MockRoleServiceMockRecorder) {
+				roles.CreateRole(gomock.Any(), domainID, "editor", claims).Return(nil, domainerrors.Validation("unknown permission posts"))
+			}, http.StatusBadRequest, map[string]interface{}{"code": "validation_failed"}},
+		{"create: name taken", http.MethodPost, "/api/v1/domains/" + domainID.String() + "/roles", `{"role_name":"editor","role_claims":{"posts":true}}`,
+			func(roles *mocks.MockRoleServiceMockRecorder) {
+				roles.CreateRole(gomock.Any(), domainID, "editor", claims).Return(nil, domainerrors.Conflict("role name already exists in domain"))
+			}, http.StatusConflict, map[string]interface{}{"error": "Role name already exists in domain"}},
+		{"create", http.MethodPost, "/api/v1/domains/" + domainID.String() + "/roles", `{"role_name":"editor","role_claims":{"posts":true}}`,
+			func(roles *mocks.MockRoleServiceMockRecorder) {
+				roles.CreateRole(gomock.Any(), domainID, "editor", claims).Return(role, nil)
+			}, http.StatusCreated, map[string]interface{}{"id": id.String()}},
+
+		{"update: invalid notification", http.MethodPut, "/api/v1/roles/" + id.String(), `{"role_name":"editor","notify":{"admin_emails":["security"]}}`,
+			nil, http.StatusBadRequest, nil},
+		{"update: not found", http.MethodPut, "/api/v1/roles/" + id.String(), `{"role_name":"editor","role_claims":{"posts":true}}`,
+			func(roles *mocks.MockRoleServiceMockRecorder) {
+				roles.UpdateRole(gomock.Any(), id, "editor", claims, nil).Return(nil, domainerrors.NotFound("role not found"))
+			}, http.StatusNotFound, nil},
+		{"update", http.MethodPut, "/api/v1/roles/" + id.String(), `{"role_name":"editor","role_claims":{"posts":true},"notify":{"users":true}}`,
+			func(roles *mocks.MockRoleServiceMockRecorder) {
+				roles.UpdateRole(gomock.Any(), id, "editor", claims, &services.RoleChangeNotification{Users: true}).Return(role, nil)
+			}, http.StatusOK, map[string]interface{}{"role_name": "editor"}},
+
+		{"delete: malformed replacement", http.MethodDelete, "/api/v1/roles/" + id.String() + "?reassign_to=viewer", "", nil, http.StatusBadRequest,
+			map[string]interface{}{"error": "Invalid reassign_to UUID"}},
+		{"delete: in use", http.MethodDelete, "/api/v1/roles/" + id.String(), "", func(roles *mocks.MockRoleServiceMockRecorder) {
+			roles.DeleteRole(gomock.Any(), id, nil, false).Return(nil, inUse)
+		}, http.StatusConflict, map[string]interface{}{"code": "role_in_use"}},
+		{"delete: not found", http.MethodDelete, "/api/v1/roles/" + id.String(), "", func(roles *mocks.MockRoleServiceMockRecorder) {
+			roles.DeleteRole(gomock.Any(), id, nil, false).Return(nil, domainerrors.NotFound("role not found"))
+		}, http.StatusNotFound, nil},
+		{"delete: dry run", http.MethodDelete, "/api/v1/roles/" + id.String() + "?reassign_to=" + replacementID.String() + "&dry_run=true", "",
+			func(roles *mocks.MockRoleServiceMockRecorder) {
+				roles.DeleteRole(gomock.Any(), id, &replacementID, true).Return(&services.RoleDeletionPlan{}, nil)
+			}, http.StatusOK, nil},
+		{"delete", http.MethodDelete, "/api/v1/roles/" + id.String(), "", func(roles *mocks.MockRoleServiceMockRecorder) {
+			roles.DeleteRole(gomock.Any(), id, nil, false).Return(nil, nil)
+		}, http.StatusNoContent, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles := mocks.NewMockRoleService(gomock.NewController(t))
+			if tt.expect != nil {
+				tt.expect(roles.EXPECT())
+			}
+			w := serve(newRoleRouter(roles), tt.method, tt.path, nil, tt.body)
+			checkResponse(t, w, tt.want, tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RoleTemplateRequest struct {
+	Name        string                 `json:"name" binding:"required" example:"editor"`
+	Description string                 `json:"description" example:"Edits documents but cannot publish them"`
+	RoleClaims  map[string]interface{} `json:"role_claims"`
+	Permissions []string               `json:"permissions" example:"documents:read,documents:write"`
+}
+
+type RoleTemplateHandler struct {
+	templateService services.RoleTemplateService
+}
+
+func NewRoleTemplateHandler(templateService services.RoleTemplateService) *RoleTemplateHandler {
+	return &RoleTemplateHandler{templateService: templateService}
+}
+
+// ListRoleTemplates godoc
+//
+//	@Summary		List role templates
+//	@Description	List the platform-wide catalog of role templates, ordered by name
+//	@Tags			role-templates
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Success		200	{array}		entities.RoleTemplate
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/role-templates [get]
+func (h *RoleTemplateHandler) ListRoleTemplates(c *gin.Context) {
+	templates, err := h.templateService.ListRoleTemplates(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to list role templates")
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetRoleTemplate godoc
+//
+//	@Summary		Get a role template
+//	@Description	Get role template by ID
+//	@Tags			role-templates
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Role template ID"
+//	@Success		200	{object}	entities.RoleTemplate
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/role-templates/{id} [get]
+func (h *RoleTemplateHandler) GetRoleTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+	template, err := h.templateService.GetRoleTemplate(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get role template")
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// CreateRoleTemplate godoc
+//
+//	@Summary		Create a role template
+//	@Description	Add a role template to the catalog. Permissions are resource:action names; roles created from the template are assigned them, and those missing from a domain's catalog are added to it. Only system admins can manage templates.
+//	@Tags			role-templates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			template	body		RoleTemplateRequest	true	"Role template"
+//	@Success		201			{object}	entities.RoleTemplate
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/role-templates [post]
+func (h *RoleTemplateHandler) CreateRoleTemplate(c *gin.Context) {
+	var req RoleTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	template, err := h.templateService.CreateRoleTemplate(c.Request.Context(), req.Name, req.Description, req.RoleClaims, req.Permissions)
+	if err != nil {
+		respondError(c, err, "Failed to create role template")
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+// UpdateRoleTemplate godoc
+//
+//	@Summary		Update a role template
+//	@Description	Replace a role template. Roles already created from it are not changed.
+//	@Tags			role-templates
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id			path		string				true	"Role template ID"
+//	@Param			template	body		RoleTemplateRequest	true	"Role template"
+//	@Success		200			{object}	entities.RoleTemplate
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/role-templates/{id} [put]
+func (h *RoleTemplateHandler) UpdateRoleTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+	var req RoleTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	template, err := h.templateService.UpdateRoleTemplate(c.Request.Context(), id, req.Name, req.Description, req.RoleClaims, req.Permissions)
+	if err != nil {
+		respondError(c, err, "Failed to update role template")
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteRoleTemplate godoc
+//
+//	@Summary		Delete a role template
+//	@Description	Remove a role template from the catalog. Roles created from it are kept.
+//	@Tags			role-templates
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id	path		string	true	"Role template ID"
+//	@Success		204	{object}	MessageResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/role-templates/{id} [delete]
+func (h *RoleTemplateHandler) DeleteRoleTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+	if err := h.templateService.DeleteRoleTemplate(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to delete role template")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Role template deleted successfully"})
+}
+
+// CreateRoleFromTemplate godoc
+//
+//	@Summary		Create a role from a template
+//	@Description	Create a role named after the template in the domain, with the template's claims and permissions. Permissions missing from the domain's catalog are added to it.
+//	@Tags			roles
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			templateId	path		string	true	"Role template ID"
+//	@Success		201			{object}	services.RoleWithPermissions
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/roles/from-template/{templateId} [post]
+func (h *RoleTemplateHandler) CreateRoleFromTemplate(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid template UUID"})
+		return
+	}
+
+	role, err := h.templateService.CreateRoleFromTemplate(c.Request.Context(), domainID, templateID)
+	if err != nil {
+		respondError(c, err, "Failed to create role from template")
+		return
+	}
+	c.JSON(http.StatusCreated, role)
+}
+
+// CloneRole godoc
+//
+//	@Summary		Clone a role
+//	@Description	Copy a role with its claims and permissions into another domain, or into its own domain under a new name. Permissions are matched by name in the target domain's catalog and added to it when missing.
+//	@Tags			roles
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id				path		string	true	"Role ID"
+//	@Param			targetDomainId	query		string	false	"Domain to copy the role into; defaults to the role's own domain"
+//	@Param			name			query		string	false	"Name of the copy; defaults to the role's name"
+//	@Success		201				{object}	services.RoleWithPermissions
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		409				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/roles/{id}/clone [post]
+func (h *RoleTemplateHandler) CloneRole(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+	var targetDomainID uuid.UUID
+	if raw := c.Query("targetDomainId"); raw != "" {
+		if targetDomainID, err = uuid.Parse(raw); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid target domain UUID"})
+			return
+		}
+	}
+
+	role, err := h.templateService.CloneRole(c.Request.Context(), roleID, targetDomainID, c.Query("name"))
+	if err != nil {
+		respondError(c, err, "Failed to clone role")
+		return
+	}
+	c.JSON(http.StatusCreated, role)
+}
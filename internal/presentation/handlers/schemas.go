@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"time"
+
+	"backend/internal/application/services"
+	"backend/internal/infrastructure/repositories"
+)
+
+// Response shapes shared by all handlers. Handlers return these instead of ad-hoc maps so the
+// generated OpenAPI document describes every body precisely.
+
+// ErrorResponse is returned with every 4xx and 5xx status. Errors from the service layer always
+// carry a code: the kind of failure (not_found, conflict, validation_failed, unauthorized,
+// forbidden, internal_error) or a more specific one such as username_taken.
+type ErrorResponse struct {
+	Error string `json:"error" example:"User not found"`
+	Code  string `json:"code,omitempty" example:"not_found"`
+}
+
+// ChallengeResponse is returned with 401 when a login needs additional verification.
+type ChallengeResponse struct {
+	Error     string `json:"error" example:"Additional verification required"`
+	Challenge string `json:"challenge" enums:"captcha,mfa" example:"captcha"`
+	RiskScore int    `json:"risk_score" minimum:"0" maximum:"100" example:"55"`
+	Code      string `json:"code,omitempty" example:"captcha_invalid"` // set when the captcha_token was rejected
+}
+
+// PasswordExpiredResponse is returned with 403 and code password_expired when the password is
+// older than the domain allows. Other 403 errors from login carry only error and code.
+type PasswordExpiredResponse struct {
+	Error       string    `json:"error" example:"Password expired"`
+	Code        string    `json:"code" example:"password_expired"`
+	ChangeToken string    `json:"change_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// RoleInUseResponse is returned with 409 and code role_in_use when a role to delete without a
+// replacement is still referenced; Dependents counts the references.
+type RoleInUseResponse struct {
+	Error      string                      `json:"error" example:"Role is still in use"`
+	Code       string                      `json:"code" example:"role_in_use"`
+	Dependents repositories.RoleDependents `json:"dependents"`
+}
+
+// DomainInUseResponse is returned with 409 and code domain_in_use when a domain to delete without
+// force still has tenant data; Dependents counts it.
+type DomainInUseResponse struct {
+	Error      string                        `json:"error" example:"Domain still has users, roles or other data"`
+	Code       string                        `json:"code" example:"domain_in_use"`
+	Dependents repositories.DomainDependents `json:"dependents"`
+}
+
+// MessageResponse confirms an operation that has no resource to return.
+type MessageResponse struct {
+	Message string `json:"message" example:"User deleted successfully"`
+}
+
+// TokenValidationResponse is returned by /auth/validate for a valid token.
+type TokenValidationResponse struct {
+	Valid  bool                  `json:"valid" example:"true"`
+	Claims *services.TokenClaims `json:"claims" swaggertype:"object"`
+}
+
+// UserInfoResponse is returned by /oauth/userinfo: sub always, the other fields only when the
+// user consented to share them with the calling client.
+type UserInfoResponse struct {
+	Sub       string                   `json:"sub" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Username  string                   `json:"username,omitempty" example:"jdoe"`
+	Email     string                   `json:"email,omitempty" example:"jdoe@example.com"`
+	FirstName string                   `json:"first_name,omitempty" example:"John"`
+	LastName  string                   `json:"last_name,omitempty" example:"Doe"`
+	Role      *services.RoleProfile    `json:"role,omitempty"`
+	Groups    []*services.GroupProfile `json:"groups,omitempty"`
+}
+
+// Paginated listings add links to their neighbouring pages to the repository's page. The same
+// links are sent in the Link header.
+
+type UserListResponse struct {
+	*repositories.UserListResult
+	Links PageLinks `json:"links"`
+}
+
+type RoleListResponse struct {
+	*repositories.RoleListResult
+	Links PageLinks `json:"links"`
+}
+
+type RoleRevisionListResponse struct {
+	*repositories.RoleRevisionListResult
+	Links PageLinks `json:"links"`
+}
+
+type DomainListResponse struct {
+	*repositories.DomainListResult
+	Links PageLinks `json:"links"`
+}
+
+type UserCursorListResponse struct {
+	*repositories.UserCursorPage
+	Links PageLinks `json:"links"`
+}
+
+type RoleCursorListResponse struct {
+	*repositories.RoleCursorPage
+	Links PageLinks `json:"links"`
+}
+
+type DomainCursorListResponse struct {
+	*repositories.DomainCursorPage
+	Links PageLinks `json:"links"`
+}
+
+type AuthzDecisionListResponse struct {
+	*repositories.AuthzDecisionListResult
+	Links PageLinks `json:"links"`
+}
+
+type LoginHistoryListResponse struct {
+	*repositories.LoginHistoryListResult
+	Links PageLinks `json:"links"`
+}
+
+type EventPageResponse struct {
+	*services.EventPage
+	Links PageLinks `json:"links"`
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateServiceAccountRequest struct {
+	RoleID   string `json:"role_id" binding:"required" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Username string `json:"username" binding:"required" example:"billing-worker"`
+	Name     string `json:"name" example:"Billing worker"` // defaults to the username
+}
+
+// ClientCredentialsRequest is the token request of the client credentials grant. The client ID
+// and secret may instead be sent with HTTP Basic authentication.
+type ClientCredentialsRequest struct {
+	GrantType    string `form:"grant_type" json:"grant_type" binding:"required" example:"client_credentials"`
+	ClientID     string `form:"client_id" json:"client_id" format:"uuid" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	ClientSecret string `form:"client_secret" json:"client_secret" example:"nrs_6f1c2b9e..."`
+	// Scope narrows the token to these space-separated permissions of the account
+	Scope string `form:"scope" json:"scope" example:"users:read groups:read"`
+}
+
+type ServiceAccountHandler struct {
+	userService services.UserService
+	authService services.AuthService
+}
+
+func NewServiceAccountHandler(userService services.UserService, authService services.AuthService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{userService: userService, authService: authService}
+}
+
+// CreateServiceAccount godoc
+//
+//	@Summary		Create a service account
+//	@Description	Create a user of type service for a backend service, with the given role. The response carries its client_id, the user ID, and client_secret, which is only shown once; the service exchanges them for access tokens at /auth/token. Service accounts have no password or email and cannot use password or passwordless login.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string						true	"Domain ID"
+//	@Param			account		body		CreateServiceAccountRequest	true	"Service account data"
+//	@Success		201			{object}	services.CreatedServiceAccount
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/service-accounts [post]
+func (h *ServiceAccountHandler) CreateServiceAccount(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	roleID, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+
+	account, err := h.userService.CreateServiceAccount(c.Request.Context(), domainID, roleID, req.Username, req.Name)
+	if err != nil {
+		respondError(c, err, "Failed to create service account")
+		return
+	}
+	c.JSON(http.StatusCreated, account)
+}
+
+// Token godoc
+//
+//	@Summary		Client credentials token
+//	@Description	Issue an access token to a service account (OAuth 2.0 client credentials grant, RFC 6749 section 4.4). Send grant_type=client_credentials with the client_id and client_secret as form fields, JSON, or HTTP Basic authentication. The token carries the account's role and groups and lasts the domain's access token lifetime. A scope narrows the token to those of the account's permissions and role claims it holds, returned in scope; if it holds none of them the request is rejected with 400 and code invalid_scope. Any wrong credential is rejected with 401 and code invalid_client; other grant types with 400 and code unsupported_grant_type.
+//	@Tags			auth
+//	@Accept			x-www-form-urlencoded
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ClientCredentialsRequest	true	"Token request"
+//	@Success		200		{object}	services.ClientCredentialsToken
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/auth/token [post]
+func (h *ServiceAccountHandler) Token(c *gin.Context) {
+	var req ClientCredentialsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error(), Code: "invalid_request"})
+		return
+	}
+	if req.GrantType != "client_credentials" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "grant_type must be client_credentials", Code: "unsupported_grant_type"})
+		return
+	}
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		req.ClientID, req.ClientSecret = id, secret
+	}
+
+	token, err := h.authService.ClientCredentials(services.WithUserAgent(c.Request.Context(), c.Request.UserAgent()), req.ClientID, req.ClientSecret, c.ClientIP(), services.ParseScope(req.Scope))
+	if err != nil {
+		respondError(c, err, "Failed to issue token")
+		return
+	}
+	// Tokens must not be cached (RFC 6749 section 5.1)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, token)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TelemetryHandler struct {
+	telemetryService services.TelemetryExportService
+}
+
+func NewTelemetryHandler(telemetryService services.TelemetryExportService) *TelemetryHandler {
+	return &TelemetryHandler{telemetryService: telemetryService}
+}
+
+// GetLoginTelemetry godoc
+//
+//	@Summary		Get a domain's login telemetry export
+//	@Description	Get whether the domain's anonymized sign-in funnel is exported to the analytics warehouse, with the sequence of the last event exported, when the export last succeeded and why it last failed. sink_configured is false while the platform has no warehouse configured, in which case nothing is exported.
+//	@Tags			domains
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{object}	services.LoginTelemetry
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/telemetry [get]
+func (h *TelemetryHandler) GetLoginTelemetry(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	result, err := h.telemetryService.GetLoginTelemetry(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get login telemetry")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateLoginTelemetry godoc
+//
+//	@Summary		Update a domain's login telemetry export
+//	@Description	Opt the domain in to or out of exporting its sign-in funnel (login.code_sent, login.challenged, login.failed and login.succeeded events) to the analytics warehouse. Exported records carry the event, method, failure reason and challenge, and an actor that is a keyed hash of the account; usernames, emails and client IPs are never exported. The export starts with the events recorded after the domain opts in.
+//	@Tags			domains
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string						true	"Domain ID"
+//	@Param			settings	body		entities.TelemetrySettings	true	"Telemetry opt-in"
+//	@Success		200			{object}	services.LoginTelemetry
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/telemetry [put]
+func (h *TelemetryHandler) UpdateLoginTelemetry(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req entities.TelemetrySettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	result, err := h.telemetryService.UpdateLoginTelemetry(c.Request.Context(), id, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update login telemetry")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
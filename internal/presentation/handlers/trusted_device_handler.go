@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TrustedDeviceHandler struct {
+	deviceService services.TrustedDeviceService
+	authService   services.AuthService
+}
+
+func NewTrustedDeviceHandler(deviceService services.TrustedDeviceService, authService services.AuthService) *TrustedDeviceHandler {
+	return &TrustedDeviceHandler{deviceService: deviceService, authService: authService}
+}
+
+// ListDevices godoc
+//
+//	@Summary		List trusted devices
+//	@Description	Get the devices the authenticated user remembered at login, most recently used first. Logins from them skip MFA challenges until they go TRUSTED_DEVICE_TTL without use.
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200				{array}		entities.TrustedDevice
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/devices [get]
+func (h *TrustedDeviceHandler) ListDevices(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	devices, err := h.deviceService.ListDevices(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondError(c, err, "Failed to list devices")
+		return
+	}
+	c.JSON(http.StatusOK, devices)
+}
+
+// RevokeDevice godoc
+//
+//	@Summary		Revoke a trusted device
+//	@Description	Forget one of the authenticated user's devices; logins from it are challenged again
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id				path		string	true	"Device ID"
+//	@Success		204				{object}	MessageResponse
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/devices/{id} [delete]
+func (h *TrustedDeviceHandler) RevokeDevice(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	deviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid device UUID"})
+		return
+	}
+
+	if err := h.deviceService.RevokeDevice(c.Request.Context(), claims.UserID, deviceID); err != nil {
+		respondError(c, err, "Failed to revoke device")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Device revoked successfully"})
+}
+
+// RevokeAllDevices godoc
+//
+//	@Summary		Revoke all trusted devices
+//	@Description	Forget every device of the authenticated user, e.g. after losing one
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200				{object}	MessageResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/auth/devices [delete]
+func (h *TrustedDeviceHandler) RevokeAllDevices(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.deviceService.RevokeAllDevices(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondError(c, err, "Failed to revoke devices")
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: fmt.Sprintf("Revoked %d device(s)", revoked)})
+}
@@ -1,70 +1,146 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+	"backend/internal/infrastructure/repositories"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type CreateUserRequest struct {
-	DomainID  string `json:"domain_id" binding:"required"`
-	RoleID    string `json:"role_id" binding:"required"`
-	FirstName string `json:"first_name" binding:"required"`
-	LastName  string `json:"last_name" binding:"required"`
-	Username  string `json:"username" binding:"required"`
-	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required,min=6"`
+	DomainID   string     `json:"domain_id" binding:"required" format:"uuid" example:"9b2e7c1a-4d3f-4e8a-9c6b-1f2d3e4a5b6c"`
+	RoleID     string     `json:"role_id" binding:"required" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	FirstName  string     `json:"first_name" binding:"required" example:"Jane"`
+	LastName   string     `json:"last_name" binding:"required" example:"Doe"`
+	Username   string     `json:"username" binding:"required" example:"jdoe"`
+	Email      string     `json:"email" binding:"required,email" example:"jane.doe@example.com"`
+	Password   string     `json:"password" binding:"omitempty,min=6" example:"S3cure-pass"`
+	ExternalID *string    `json:"external_id" example:"EMP-00123"`
+	ValidUntil *time.Time `json:"valid_until" example:"2026-12-31T23:59:59Z"`
 }
 
 type UpdateUserRequest struct {
-	FirstName string `json:"first_name" binding:"required"`
-	LastName  string `json:"last_name" binding:"required"`
-	Username  string `json:"username" binding:"required"`
-	Email     string `json:"email" binding:"required,email"`
-	RoleID    string `json:"role_id" binding:"required"`
+	FirstName  string  `json:"first_name" binding:"required" example:"Jane"`
+	LastName   string  `json:"last_name" binding:"required" example:"Doe"`
+	Username   string  `json:"username" binding:"required" example:"jdoe"`
+	Email      string  `json:"email" binding:"required,email" example:"jane.doe@example.com"`
+	RoleID     string  `json:"role_id" binding:"required" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	ExternalID *string `json:"external_id" example:"EMP-00123"`
+}
+
+// UpsertUserRequest is the body for upserting a user by external ID. Password is only used when
+// the user is created.
+type UpsertUserRequest struct {
+	FirstName string `json:"first_name" binding:"required" example:"Jane"`
+	LastName  string `json:"last_name" binding:"required" example:"Doe"`
+	Username  string `json:"username" binding:"required" example:"jdoe"`
+	Email     string `json:"email" binding:"required,email" example:"jane.doe@example.com"`
+	RoleID    string `json:"role_id" binding:"required" format:"uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Password  string `json:"password" binding:"omitempty,min=6" example:"S3cure-pass"`
+}
+
+// SetValidUntilRequest sets the account end date; null removes it.
+type SetValidUntilRequest struct {
+	ValidUntil *time.Time `json:"valid_until" example:"2026-12-31T23:59:59Z"`
+}
+
+// BatchGetRequest lists the IDs of a batch lookup of users or roles.
+type BatchGetRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required" swaggertype:"array,string" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6,7c9e6679-7425-40de-944b-e07fc1f90ae7"`
 }
 
 type ResetPasswordRequest struct {
-	NewPassword string `json:"new_password" binding:"required,min=6"`
+	NewPassword string `json:"new_password" binding:"required,min=6" example:"N3w-secure-pass"`
 }
 
 type UserHandler struct {
 	userService services.UserService
+	masking     services.DataMaskingService
 }
 
-func NewUserHandler(userService services.UserService) *UserHandler {
-	return &UserHandler{userService: userService}
+func NewUserHandler(userService services.UserService, masking services.DataMaskingService) *UserHandler {
+	return &UserHandler{userService: userService, masking: masking}
 }
 
 // GetUser godoc
 //
 //	@Summary		Get a user
-//	@Description	Get user by ID
+//	@Description	Get user by ID. Fields the domain masks (see /domains/{domainId}/data-masking) are masked unless the bearer token grants pii:read in the user's domain.
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string			true	"User ID"
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id				path		string	true	"User ID"
 //	@Success		200	{object}	entities.User
-//	@Failure		400	{object}	map[string]string
-//	@Failure		404	{object}	map[string]string
-//	@Router			/users/{id} [get]
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/users/{id} [get]
 func (h *UserHandler) GetUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
 		return
 	}
-	user, err := h.userService.GetUserByID(id)
+	user, err := h.userService.GetUserByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		respondError(c, err, "Failed to get user")
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	respondMaskedUser(c, h.masking, http.StatusOK, user)
+}
+
+// BatchGetUsers godoc
+//
+//	@Summary		Get users by ID
+//	@Description	Get up to 100 users in one request. Users are returned in the order of their IDs in the request (an ID repeated in the request is answered once), and IDs without a user are listed in missing. Fields the domain masks (see /domains/{domainId}/data-masking) are masked unless the bearer token grants pii:read in the user's domain.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			request			body		BatchGetRequest	true	"User IDs"
+//	@Success		200				{object}	services.UserBatch
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/users/batch-get [post]
+func (h *UserHandler) BatchGetUsers(c *gin.Context) {
+	var req BatchGetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	batch, err := h.userService.GetUsersByIDs(c.Request.Context(), req.IDs)
+	if err != nil {
+		respondError(c, err, "Failed to get users")
+		return
+	}
+	masker := newUserMasker(c, h.masking)
+	if batch.Users, err = masker.MaskAll(c.Request.Context(), batch.Users); err != nil {
+		respondError(c, err, "Failed to prepare users")
+		return
+	}
+	c.JSON(http.StatusOK, batch)
+	masker.Finish(c.Request.Context())
 }
 
 // GetUsersByDomain godoc
@@ -74,41 +150,59 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
 //	@Param			domainId	path		string			true	"Domain ID"
 //	@Success		200			{array}		entities.User
-//	@Failure		400			{object}	map[string]string
-//	@Failure		500			{object}	map[string]string
-//	@Router			/domains/{domainId}/users [get]
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/users [get]
 func (h *UserHandler) GetUsersByDomain(c *gin.Context) {
 	domainIdStr := c.Param("domainId")
 	domainID, err := uuid.Parse(domainIdStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
 		return
 	}
-	users, err := h.userService.GetUsersByDomainID(domainID)
+	users, err := h.userService.GetUsersByDomainID(c.Request.Context(), domainID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get users"})
+		respondError(c, err, "Failed to get users")
 		return
 	}
-	c.JSON(http.StatusOK, users)
+	respondMaskedUsers(c, h.masking, http.StatusOK, users)
 }
 
 // ListUsers godoc
 //
 //	@Summary		List users with pagination
-//	@Description	Get users with pagination and search
+//	@Description	Get users with pagination, search and filters. Org unit admins may list their own domain, and only see the users of their unit's subtree. Sort by username (the default), email, first_name, last_name, created_at or updated_at, optionally suffixed with :asc or :desc. Fields the domain masks (see /domains/{domainId}/data-masking) are masked unless the bearer token grants pii:read in the user's domain. Set cursor to page through users oldest first instead, which stays fast however deep the listing goes: pass an empty cursor for the first page and the next_cursor of each response for the one after it. Cursor pages carry users, limit and next_cursor (omitted on the last page) in place of page, total and total_pages.
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			domainId	query		string	false	"Domain ID to filter users"
-//	@Param			search		query		string	false	"Search term for username, email, first name, or last name"
-//	@Param			page		query		int		false	"Page number (default: 1)"
-//	@Param			limit		query		int		false	"Items per page (default: 10, max: 100)"
-//	@Success		200			{object}	repositories.UserListResult
-//	@Failure		400			{object}	map[string]string
-//	@Failure		500			{object}	map[string]string
-//	@Router			/users [get]
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId			query		string	false	"Domain to list; defaults to the domain of a domain admin's token and is required otherwise"
+//	@Param			search				query		string	false	"Search term for username, email, first name, or last name"
+//	@Param			role_id				query		string	false	"Only users holding this role, directly or through a group"
+//	@Param			org_unit_id			query		string	false	"Only users of this org unit and its descendants"
+//	@Param			status				query		string	false	"Only users in this state"	Enums(active, disabled, expired, pending_deletion)
+//	@Param			email_domain		query		string	false	"Only users whose email is at this domain, e.g. acme.com"
+//	@Param			created_after		query		string	false	"Only users created at or after this RFC 3339 time"
+//	@Param			created_before		query		string	false	"Only users created before this RFC 3339 time"
+//	@Param			sort				query		string	false	"Sort field and direction, e.g. created_at:desc"
+//	@Param			page				query		int		false	"Page number"		minimum(1)	default(1)
+//	@Param			limit				query		int		false	"Items per page"	minimum(1)	maximum(100)	default(10)
+//	@Param			cursor				query		string	false	"Cursor pagination: empty for the first page, then the previous response's next_cursor"
+//	@Param			X-Consistency-Token	header		string	false	"Token from a previous write; replicas behind it are not read"
+//	@Success		200					{object}	UserListResponse
+//	@Header			200					{string}	Link	"Links to the first, previous, next and last pages (RFC 5988)"
+//	@Failure		400					{object}	ErrorResponse
+//	@Failure		401					{object}	ErrorResponse
+//	@Failure		403					{object}	ErrorResponse
+//	@Failure		500					{object}	ErrorResponse
+//	@Router			/api/v1/users [get]
 func (h *UserHandler) ListUsers(c *gin.Context) {
 	// Parse query parameters
 	search := c.DefaultQuery("search", "")
@@ -130,134 +224,557 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	if domainIdStr != "" {
 		domainID, err = uuid.Parse(domainIdStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain UUID"})
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+			return
+		}
+	}
+
+	filter := repositories.UserListFilter{
+		Search:      search,
+		Status:      c.Query("status"),
+		EmailDomain: c.Query("email_domain"),
+	}
+	if roleIdStr := c.Query("role_id"); roleIdStr != "" {
+		filter.RoleID, err = uuid.Parse(roleIdStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+			return
+		}
+	}
+	var ok bool
+	if filter.CreatedAfter, filter.CreatedBefore, ok = parseCreatedRange(c); !ok {
+		return
+	}
+	if orgUnitIdStr := c.Query("org_unit_id"); orgUnitIdStr != "" {
+		filter.OrgUnitID, err = uuid.Parse(orgUnitIdStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid org unit UUID"})
 			return
 		}
 	}
+	if filter.Sort, err = repositories.ParseListSort(c.Query("sort"), repositories.UserSortFields); err != nil {
+		respondError(c, err, "Failed to list users")
+		return
+	}
 
-	result, err := h.userService.ListUsersWithPagination(search, domainID, page, limit)
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		h.listUsersAfter(c, filter, domainID, cursor, limit)
+		return
+	}
+
+	result, err := h.userService.ListUsersWithPagination(c.Request.Context(), filter, domainID, page, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list users"})
+		respondError(c, err, "Failed to list users")
+		return
+	}
+	masker := newUserMasker(c, h.masking)
+	if result.Users, err = masker.MaskAll(c.Request.Context(), result.Users); err != nil {
+		respondError(c, err, "Failed to prepare users")
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, UserListResponse{UserListResult: result, Links: pageLinks(c, result.Page, result.Limit, result.TotalPages)})
+	masker.Finish(c.Request.Context())
+}
+
+func (h *UserHandler) listUsersAfter(c *gin.Context, filter repositories.UserListFilter, domainID uuid.UUID, cursor string, limit int) {
+	result, err := h.userService.ListUsersAfter(c.Request.Context(), filter, domainID, cursor, limit)
+	if err != nil {
+		respondError(c, err, "Failed to list users")
+		return
+	}
+	masker := newUserMasker(c, h.masking)
+	if result.Users, err = masker.MaskAll(c.Request.Context(), result.Users); err != nil {
+		respondError(c, err, "Failed to prepare users")
+		return
+	}
+	links := cursorLinks(c, "cursor", "", cursor, result.NextCursor, result.NextCursor != "")
+	c.JSON(http.StatusOK, UserCursorListResponse{UserCursorPage: result, Links: links})
+	masker.Finish(c.Request.Context())
 }
 
 // CreateUser godoc
 //
 //	@Summary		Create a user
-//	@Description	Create a new user
+//	@Description	Create a new user. A password satisfying the domain's password policy is required in password domains and must be omitted in passwordless domains. An optional external_id links the user to an upstream system, and an optional valid_until sets an end date after which the account is disabled and its sessions revoked. Username, email and external_id must be unique in the domain; a clash returns 409 with code username_taken, email_taken or external_id_taken. Send an Idempotency-Key header to retry safely: for 24 hours (IDEMPOTENCY_KEY_TTL) a retry with the same key and body returns the original response with Idempotent-Replayed: true instead of creating a duplicate. A key reused for a different body returns 400 with code idempotency_key_reused, and while the first request is still running 409 with code idempotency_key_in_progress.
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			user	body		CreateUserRequest	true	"User data"
-//	@Success		201		{object}	entities.User
-//	@Failure		400		{object}	map[string]string
-//	@Failure		500		{object}	map[string]string
-//	@Router			/users [post]
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			Idempotency-Key	header		string				false	"Key making retries of this request return the original response"
+//	@Param			user			body		CreateUserRequest	true	"User data"
+//	@Success		201				{object}	entities.User
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		403				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		409				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
+//	@Router			/api/v1/users [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
 	domainID, err := uuid.Parse(req.DomainID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
 		return
 	}
 
 	roleID, err := uuid.Parse(req.RoleID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
 		return
 	}
 
-	user, err := h.userService.CreateUser(domainID, roleID, req.FirstName, req.LastName, req.Username, req.Email, req.Password)
+	user, err := h.userService.CreateUser(c.Request.Context(), domainID, roleID, req.FirstName, req.LastName, req.Username, req.Email, req.Password, req.ExternalID, req.ValidUntil)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		respondError(c, err, "Failed to create user")
 		return
 	}
-	c.JSON(http.StatusCreated, user)
+	respondMaskedUser(c, h.masking, http.StatusCreated, user)
+}
+
+// ImportUsers godoc
+//
+//	@Summary		Bulk import users
+//	@Description	Import users from a CSV or JSON file. CSV files need a header row with the columns username, email, first_name, last_name and optionally role_id, password and external_id; JSON files hold an array of objects with the same keys. Every row is validated, rows whose username, email or external ID already exist (or repeat an earlier row) are skipped, and the remaining rows are inserted in a single transaction. The response reports the outcome of every row. With dry_run=true the rows are checked the same way but nothing is inserted, and rows reported as created are the ones that would be.
+//	@Tags			users
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			file		formData	file	true	"CSV or JSON file"
+//	@Param			domain_id	formData	string	true	"Domain ID"
+//	@Param			role_id		formData	string	false	"Default role for rows without role_id"
+//	@Param			format		formData	string	false	"File format (default: detected from the file name)"	Enums(csv, json)
+//	@Param			dry_run		query		bool	false	"Validate and report without inserting"
+//	@Success		200			{object}	services.UserImportReport
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		413			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/users/import [post]
+func (h *UserHandler) ImportUsers(c *gin.Context) {
+	domainID, err := uuid.Parse(c.PostForm("domain_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+	dryRun, ok := parseDryRun(c)
+	if !ok {
+		return
+	}
+
+	var defaultRoleID *uuid.UUID
+	if roleIDStr := c.PostForm("role_id"); roleIDStr != "" {
+		roleID, err := uuid.Parse(roleIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+			return
+		}
+		defaultRoleID = &roleID
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "File is required"})
+		return
+	}
+	if fileHeader.Size > maxUserImportFileSize {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "File is too large (max 10MB)"})
+		return
+	}
+
+	format := strings.ToLower(c.PostForm("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	var rows []*services.UserImportRow
+	switch format {
+	case "csv":
+		rows, err = parseUserImportCSV(file)
+	case "json":
+		err = json.NewDecoder(file).Decode(&rows)
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unsupported format, use csv or json"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid " + format + " file: " + err.Error()})
+		return
+	}
+
+	report, err := h.userService.ImportUsers(c.Request.Context(), domainID, defaultRoleID, rows, dryRun)
+	if err != nil {
+		respondError(c, err, "Failed to import users")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportUsers godoc
+//
+//	@Summary		Export users
+//	@Description	Stream every user of a domain as CSV or JSON for compliance reviews. Rows are written as they are read from the database, so large domains are not held in memory. Password hashes are never exported. Fields the domain masks (see /domains/{domainId}/data-masking) are masked unless the bearer token grants pii:read in the user's domain.
+//	@Tags			users
+//	@Produce		text/csv
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	query		string	true	"Domain ID"
+//	@Param			format		query		string	false	"Export format"	Enums(csv, json)	default(csv)
+//	@Success		200			{array}		entities.User
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/users/export [get]
+func (h *UserHandler) ExportUsers(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Query("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	stream, err := newExportStream(c, strings.ToLower(c.Query("format")), "users",
+		[]string{"id", "domain_id", "role_id", "external_id", "first_name", "last_name", "username", "email", "created_at", "updated_at"})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unsupported format, use csv or json"})
+		return
+	}
+
+	masker := newUserMasker(c, h.masking)
+	err = h.userService.ExportUsers(c.Request.Context(), domainID, func(user *entities.User) error {
+		user, err := masker.Mask(c.Request.Context(), user)
+		if err != nil {
+			return err
+		}
+		externalID := ""
+		if user.ExternalID != nil {
+			externalID = *user.ExternalID
+		}
+		return stream.Write([]string{
+			user.ID.String(), user.DomainID.String(), user.RoleID.String(), externalID, user.FirstName, user.LastName,
+			user.Username, user.Email, formatExportTime(user.CreatedAt), formatExportTime(user.UpdatedAt),
+		}, user)
+	})
+	stream.Finish(err, "Failed to export users")
+	masker.Finish(c.Request.Context())
 }
 
 // UpdateUser godoc
 //
 //	@Summary		Update a user
-//	@Description	Update user by ID
+//	@Description	Update user by ID. Omitting external_id keeps the current one; an empty string clears it. A username, email or external_id already used in the domain returns 409 with a code. Changing the role revokes the user's existing tokens.
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
 //	@Param			id		path		string					true	"User ID"
 //	@Param			user	body		UpdateUserRequest		true	"User data"
 //	@Success		200		{object}	entities.User
-//	@Failure		400		{object}	map[string]string
-//	@Failure		404		{object}	map[string]string
-//	@Failure		500		{object}	map[string]string
-//	@Router			/users/{id} [put]
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		409		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	roleID, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+
+	user, err := h.userService.UpdateUser(c.Request.Context(), id, req.FirstName, req.LastName, req.Username, req.Email, roleID, req.ExternalID)
+	if err != nil {
+		respondError(c, err, "Failed to update user")
+		return
+	}
+	respondMaskedUser(c, h.masking, http.StatusOK, user)
+}
+
+// GetUserByExternalID godoc
+//
+//	@Summary		Get a user by external ID
+//	@Description	Get a user by the ID assigned by an external system (e.g. an HR platform). External IDs are unique per domain.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id			path		string	true	"External ID"
+//	@Param			domainId	query		string	true	"Domain ID"
+//	@Success		200			{object}	entities.User
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/users/by-external-id/{id} [get]
+func (h *UserHandler) GetUserByExternalID(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Query("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	user, err := h.userService.GetUserByExternalID(c.Request.Context(), domainID, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "Failed to get user")
+		return
+	}
+	respondMaskedUser(c, h.masking, http.StatusOK, user)
+}
+
+// UpdateUserByExternalID godoc
+//
+//	@Summary		Update a user by external ID
+//	@Description	Update the user holding the external ID in the domain. The external ID itself is kept; external_id in the body is ignored.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id			path		string				true	"External ID"
+//	@Param			domainId	query		string				true	"Domain ID"
+//	@Param			user		body		UpdateUserRequest	true	"User data"
+//	@Success		200			{object}	entities.User
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/users/by-external-id/{id} [put]
+func (h *UserHandler) UpdateUserByExternalID(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Query("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
 		return
 	}
 
 	var req UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
 	roleID, err := uuid.Parse(req.RoleID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
 		return
 	}
 
-	user, err := h.userService.UpdateUser(id, req.FirstName, req.LastName, req.Username, req.Email, roleID)
+	user, err := h.userService.UpdateUserByExternalID(c.Request.Context(), domainID, c.Param("id"), req.FirstName, req.LastName, req.Username, req.Email, roleID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		respondError(c, err, "Failed to update user")
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	respondMaskedUser(c, h.masking, http.StatusOK, user)
+}
+
+// UpsertUserByExternalID godoc
+//
+//	@Summary		Create or update a user by external ID
+//	@Description	Create the user if no user in the domain holds the external ID, otherwise update it. Intended for idempotent syncs from HR/ERP systems: sending the same record again leaves the user untouched. The password is only applied when the user is created.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string				true	"Domain ID"
+//	@Param			id			path		string				true	"External ID"
+//	@Param			user		body		UpsertUserRequest	true	"User data"
+//	@Success		200			{object}	entities.User		"Updated or unchanged"
+//	@Success		201			{object}	entities.User		"Created"
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/users/by-external-id/{id} [put]
+func (h *UserHandler) UpsertUserByExternalID(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req UpsertUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	roleID, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid role UUID"})
+		return
+	}
+
+	user, created, err := h.userService.UpsertUserByExternalID(c.Request.Context(), domainID, c.Param("id"), req.FirstName, req.LastName, req.Username, req.Email, req.Password, roleID)
+	if err != nil {
+		respondError(c, err, "Failed to upsert user")
+		return
+	}
+	if created {
+		respondMaskedUser(c, h.masking, http.StatusCreated, user)
+		return
+	}
+	respondMaskedUser(c, h.masking, http.StatusOK, user)
 }
 
 // ResetUserPassword godoc
 //
 //	@Summary		Reset user password
-//	@Description	Reset user password by ID
+//	@Description	Reset user password by ID. The new password must satisfy the domain's password policy (400 with code password_policy_violation) and must not be one of the user's last history_count passwords (code password_reused). The user's existing tokens are revoked.
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
 //	@Param			id			path		string					true	"User ID"
 //	@Param			password	body		ResetPasswordRequest	true	"New password data"
-//	@Success		200			{object}	map[string]string
-//	@Failure		400			{object}	map[string]string
-//	@Failure		404			{object}	map[string]string
-//	@Failure		500			{object}	map[string]string
-//	@Router			/users/{id}/reset-password [post]
+//	@Success		200			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/users/{id}/reset-password [post]
 func (h *UserHandler) ResetUserPassword(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
 		return
 	}
 
 	var req ResetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	err = h.userService.ResetUserPassword(id, req.NewPassword)
+	err = h.userService.ResetUserPassword(c.Request.Context(), id, req.NewPassword)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		respondError(c, err, "Failed to reset password")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+	c.JSON(http.StatusOK, MessageResponse{Message: "Password reset successfully"})
+}
+
+// SetUserValidUntil godoc
+//
+//	@Summary		Set account end date
+//	@Description	Set or clear (null) the date after which the account is disabled and its sessions revoked, e.g. for contractors. An end date of now or earlier suspends the account at once and revokes its tokens. Moving the end date of a disabled account into the future, or clearing it, re-enables the account.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			id		path		string					true	"User ID"
+//	@Param			request	body		SetValidUntilRequest	true	"Account end date"
+//	@Success		200		{object}	entities.User
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/users/{id}/valid-until [put]
+func (h *UserHandler) SetUserValidUntil(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
+		return
+	}
+
+	var req SetValidUntilRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	user, err := h.userService.SetUserValidUntil(c.Request.Context(), id, req.ValidUntil)
+	if err != nil {
+		respondError(c, err, "Failed to update account end date")
+		return
+	}
+	respondMaskedUser(c, h.masking, http.StatusOK, user)
+}
+
+// ListExpiringUsers godoc
+//
+//	@Summary		List expiring accounts
+//	@Description	Report the active users of a domain whose account end date falls within the next days, soonest first. Accounts already past their end date but not yet disabled by the sweep are included.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			days		query		int		false	"Look-ahead window in days"	minimum(1)	maximum(365)	default(30)
+//	@Success		200			{array}		entities.User
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/users/expiring [get]
+func (h *UserHandler) ListExpiringUsers(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	days := 0
+	if value := c.Query("days"); value != "" {
+		days, err = strconv.Atoi(value)
+		if err != nil || days < 1 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "days must be a positive integer"})
+			return
+		}
+	}
+
+	users, err := h.userService.ListExpiringUsers(c.Request.Context(), domainID, time.Duration(days)*24*time.Hour)
+	if err != nil {
+		respondError(c, err, "Failed to list expiring users")
+		return
+	}
+	respondMaskedUsers(c, h.masking, http.StatusOK, users)
 }
 
 // DeleteUser godoc
@@ -267,23 +784,77 @@ func (h *UserHandler) ResetUserPassword(c *gin.Context) {
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
 //	@Param			id	path		string			true	"User ID"
-//	@Success		204	{object}	map[string]string
-//	@Failure		400	{object}	map[string]string
-//	@Failure		500	{object}	map[string]string
-//	@Router			/users/{id} [delete]
+//	@Success		204	{object}	MessageResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid UUID"})
 		return
 	}
 
-	err = h.userService.DeleteUser(id)
+	err = h.userService.DeleteUser(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		respondError(c, err, "Failed to delete user")
 		return
 	}
-	c.JSON(http.StatusNoContent, gin.H{"message": "User deleted successfully"})
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "User deleted successfully"})
+}
+
+const maxUserImportFileSize = 10 << 20
+
+// parseUserImportCSV maps columns by header name, so column order is free and unknown columns are ignored.
+func parseUserImportCSV(r io.Reader) ([]*services.UserImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header row")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"username", "email"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	var rows []*services.UserImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		rows = append(rows, &services.UserImportRow{
+			Username:   field("username"),
+			Email:      field("email"),
+			FirstName:  field("first_name"),
+			LastName:   field("last_name"),
+			RoleID:     field("role_id"),
+			Password:   field("password"),
+			ExternalID: field("external_id"),
+		})
+	}
+	return rows, nil
 }
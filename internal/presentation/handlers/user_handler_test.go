@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/application/services"
+	"backend/internal/application/services/mocks"
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+// maskingDomains serves the domains the masking service reads; they mask emails when maskEmail is set.
+type maskingDomains struct {
+	repositories.DomainRepository
+	maskEmail bool
+}
+
+func (d maskingDomains) GetByID(ctx context.Context, id uuid.UUID) (*entities.Domain, error) {
+	domain := &entities.Domain{DomainID: id}
+	if d.maskEmail {
+		domain.DataMasking.Fields = []string{"email"}
+	}
+	return domain, nil
+}
+
+// publishedEvents records the events the masking service publishes.
+type publishedEvents struct {
+	services.EventService
+	types []string
+}
+
+func (e *publishedEvents) Publish(ctx context.Context, domainID uuid.UUID, eventType string, subjectID uuid.UUID, payload interface{}) {
+	e.types = append(e.types, eventType)
+}
+
+func newUserRouter(users services.UserService, masking services.DataMaskingService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewUserHandler(users, masking)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	api := r.Group("/api/v1")
+	api.GET("/users/:id", handler.GetUser)
+	api.POST("/users", handler.CreateUser)
+	api.PUT("/users/:id", handler.UpdateUser)
+	api.DELETE("/users/:id", handler.DeleteUser)
+	return r
+}
+
+// checkResponse fails the test unless w has the status and its JSON body the fields.
+func checkResponse(t *testing.T, w *httptest.ResponseRecorder, status int, fields map[string]interface{}) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d: %s", w.Code, status, w.Body.String())
+	}
+	if len(fields) == 0 {
+		return
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	for field, want := range fields {
+		if body[field] != want {
+			t.Errorf("%s = %v, want %v", field, body[field], want)
+		}
+	}
+}
+
+func TestUserHandler(t *testing.T) {
+	id, domainID, roleID := uuid.New(), uuid.New(), uuid.New()
+	user := &entities.User{ID: id, DomainID: domainID, RoleID: roleID, Username: "jdoe", Email: "jane@example.com"}
+	createBody := `{"domain_id":"` + domainID.String() + `","role_id":"` + roleID.String() + `","first_name":"Jane","last_name":"Doe","username":"jdoe","email":"jane@example.com","password":"secret"}`
+	updateBody := `{"first_name":"Jane","last_name":"Doe","username":"jdoe","email":"jane@example.com","role_id":"` + roleID.String() + `"}`
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		expect       func(users *mocks.MockUserServiceMockRecorder)
+		want         int
+		wantBody     map[string]interface{}
+	}{
+		{"get: malformed ID", http.MethodGet, "/api/v1/users/jdoe", "", nil, http.StatusBadRequest,
+			map[string]interface{}{"error": "Invalid UUID"}},
+		{"get: not found", http.MethodGet, "/api/v1/users/" + id.String(), "", func(users *mocks.MockUserServiceMockRecorder) {
+			users.GetUserByID(gomock.Any(), id).Return(nil, domainerrors.NotFound("user not found"))
+		}, http.StatusNotFound, map[string]interface{}{"error": "User not found", "code": "not_found"}},
+		{"get: failure", http.MethodGet, "/api/v1/users/" + id.String(), "", func(users *mocks.MockUserServiceMockRecorder) {
+			users.GetUserByID(gomock.Any(), id).Return(nil, errors.New("connection refused"))
+		}, http.StatusInternalServerError, map[string]interface{}{"error": "Failed to get user"}},
+		{"get", http.MethodGet, "/api/v1/users/" + id.String(), "", func(users *mocks.MockUserServiceMockRecorder) {
+			users.GetUserByID(gomock.Any(), id).Return(user, nil)
+		}, http.StatusOK, map[string]interface{}{"username": "jdoe", "email": "jane@example.com"}},
+
+		{"create: missing fields", http.MethodPost, "/api/v1/users", `{"username":"jdoe"}`, nil, http.StatusBadRequest, nil},
+		{"create: invalid email", http.MethodPost, "/api/v1/users",
+			`{"domain_id":"` + domainID.String() + `","role_id":"` + roleID.String() + `","first_name":"Jane","last_name":"Doe","username":"jdoe","email":"jane"}`,
+			nil, http.StatusBadRequest, nil},
+		{"create: malformed domain ID", http.MethodPost, "/api/v1/users",
+			`{"domain_id":"acme","role_id":"` + roleID.String() + `","first_name":"Jane","last_name":"Doe","username":"jdoe","email":"jane@example.com"}`,
+			nil, http.StatusBadRequest, map[string]interface{}{"error": "Invalid domain UUID"}},
+		{"create: rejected by the service", http.MethodPost, "/api/v1/users", createBody, func(users *mocks.MockUserServiceMockRecorder) {
+			users.CreateUser(gomock.Any(), domainID, roleID, "Jane", "Doe", "jdoe", "jane@example.com", "secret", nil, nil).
+				Return(nil, domainerrors.Validation("password is too short"))
+		}, http.StatusBadRequest, map[string]interface{}{"error": "Password is too short", "code": "validation_failed"}},
+		{"create: username taken", http.MethodPost, "/api/v1/users", createBody, func(users *mocks.MockUserServiceMockRecorder) {
+			users.CreateUser(gomock.Any(), domainID, roleID, "Jane", "Doe", "jdoe", "jane@example.com", "secret", nil, nil).
+				Return(nil, domainerrors.Conflict("username already exists").WithCode("username_taken"))
+		}, http.StatusConflict, map[string]interface{}{"code": "username_taken"}},
+		{"create", http.MethodPost, "/api/v1/users", createBody, func(users *mocks.MockUserServiceMockRecorder) {
+			users.CreateUser(gomock.Any(), domainID, roleID, "Jane", "Doe", "jdoe", "jane@example.com", "secret", nil, nil).Return(user, nil)
+		}, http.StatusCreated, map[string]interface{}{"id": id.String()}},
+
+		{"update: malformed role ID", http.MethodPut, "/api/v1/users/" + id.String(),
+			`{"first_name":"Jane","last_name":"Doe","username":"jdoe","email":"jane@example.com","role_id":"admin"}`,
+			nil, http.StatusBadRequest, map[string]interface{}{"error": "Invalid role UUID"}},
+		{"update: not found", http.MethodPut, "/api/v1/users/" + id.String(), updateBody, func(users *mocks.MockUserServiceMockRecorder) {
+			users.UpdateUser(gomock.Any(), id, "Jane", "Doe", "jdoe", "jane@example.com", roleID, nil).Return(nil, domainerrors.NotFound("user not found"))
+		}, http.StatusNotFound, nil},
+		{"update: email taken", http.MethodPut, "/api/v1/users/" + id.String(), updateBody, func(users *mocks.MockUserServiceMockRecorder) {
+			users.UpdateUser(gomock.Any(), id, "Jane", "Doe", "jdoe", "jane@example.com", roleID, nil).Return(nil, domainerrors.Conflict("email already exists"))
+		}, http.StatusConflict, map[string]interface{}{"code": "conflict"}},
+		{"update", http.MethodPut, "/api/v1/users/" + id.String(), updateBody, func(users *mocks.MockUserServiceMockRecorder) {
+			users.UpdateUser(gomock.Any(), id, "Jane", "Doe", "jdoe", "jane@example.com", roleID, nil).Return(user, nil)
+		}, http.StatusOK, map[string]interface{}{"username": "jdoe"}},
+
+		{"delete: malformed ID", http.MethodDelete, "/api/v1/users/1", "", nil, http.StatusBadRequest, nil},
+		{"delete: not found", http.MethodDelete, "/api/v1/users/" + id.String(), "", func(users *mocks.MockUserServiceMockRecorder) {
+			users.DeleteUser(gomock.Any(), id).Return(domainerrors.NotFound("user not found"))
+		}, http.StatusNotFound, nil},
+		{"delete", http.MethodDelete, "/api/v1/users/" + id.String(), "", func(users *mocks.MockUserServiceMockRecorder) {
+			users.DeleteUser(gomock.Any(), id).Return(nil)
+		}, http.StatusNoContent, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			users := mocks.NewMockUserService(ctrl)
+			if tt.expect != nil {
+				tt.expect(users.EXPECT())
+			}
+			masking := services.NewDataMaskingService(maskingDomains{}, mocks.NewMockAuthService(ctrl), &publishedEvents{})
+			w := serve(newUserRouter(users, masking), tt.method, tt.path, nil, tt.body)
+			checkResponse(t, w, tt.want, tt.wantBody)
+		})
+	}
+}
+
+func TestUserHandlerMasking(t *testing.T) {
+	id, domainID, adminID := uuid.New(), uuid.New(), uuid.New()
+	user := &entities.User{ID: id, DomainID: domainID, Username: "jdoe", Email: "jane@example.com"}
+
+	tests := []struct {
+		name        string
+		token       string
+		permissions []string
+		want        string
+		wantEvents  int
+	}{
+		{"anonymous", "", nil, "j***@example.com", 0},
+		{"without pii:read", "admin-token", []string{"users:read"}, "j***@example.com", 0},
+		{"with pii:read", "admin-token", []string{"users:read", entities.PermissionReadPII}, "jane@example.com", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			users := mocks.NewMockUserService(ctrl)
+			users.EXPECT().GetUserByID(gomock.Any(), id).Return(user, nil)
+			auth := mocks.NewMockAuthService(ctrl)
+			headers := map[string]string{}
+			if tt.token != "" {
+				headers["Authorization"] = "Bearer " + tt.token
+				auth.EXPECT().ValidateToken(gomock.Any(), tt.token).Return(&services.TokenClaims{UserID: adminID, DomainID: domainID}, nil)
+				auth.EXPECT().GetEffectivePermissions(gomock.Any(), adminID).Return(&services.EffectivePermissions{Permissions: tt.permissions}, nil)
+			}
+			events := &publishedEvents{}
+			masking := services.NewDataMaskingService(maskingDomains{maskEmail: true}, auth, events)
+
+			w := serve(newUserRouter(users, masking), http.MethodGet, "/api/v1/users/"+id.String(), headers, "")
+			checkResponse(t, w, http.StatusOK, map[string]interface{}{"email": tt.want})
+			if len(events.types) != tt.wantEvents {
+				t.Errorf("published %v, want %d PII view events", events.types, tt.wantEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookRequest struct {
+	URL     string   `json:"url" binding:"required" example:"https://hooks.acme.example.com/iam"`
+	Events  []string `json:"events" example:"user.created,login.failed"`
+	Enabled *bool    `json:"enabled" example:"true"`
+}
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// CreateWebhook godoc
+//
+//	@Summary		Create a webhook
+//	@Description	Subscribe a URL to the domain's events, or only to the listed event types. Each event is POSTed as JSON with the headers X-NRM-Event, X-NRM-Delivery and X-NRM-Signature (t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>" keyed with the secret). Any response outside 2xx is retried with exponential backoff. The secret is only returned here and when it is rotated. A domain can have at most 10 webhooks; more return 409 with code webhook_limit.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string			true	"Domain ID"
+//	@Param			webhook		body		WebhookRequest	true	"Webhook URL and event filter"
+//	@Success		201			{object}	services.CreatedWebhook
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		409			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), domainID, req.URL, req.Events, req.Enabled)
+	if err != nil {
+		respondError(c, err, "Failed to create webhook")
+		return
+	}
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// ListWebhooks godoc
+//
+//	@Summary		List webhooks
+//	@Description	Get the domain's webhooks, oldest first. Secrets are not included.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Success		200			{array}		entities.Webhook
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return
+	}
+
+	webhooks, err := h.webhookService.ListWebhooks(c.Request.Context(), domainID)
+	if err != nil {
+		respondError(c, err, "Failed to list webhooks")
+		return
+	}
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// GetWebhook godoc
+//
+//	@Summary		Get a webhook
+//	@Description	Get a webhook of the domain. The secret is not included.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Success		200			{object}	entities.Webhook
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/webhooks/{webhookId} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	domainID, webhookID, ok := parseWebhookPath(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(c.Request.Context(), domainID, webhookID)
+	if err != nil {
+		respondError(c, err, "Failed to get webhook")
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook godoc
+//
+//	@Summary		Update a webhook
+//	@Description	Replace the URL and event filter of a webhook; enabled is kept when omitted. Deliveries of a disabled webhook stay pending until it is enabled again.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string			true	"Domain ID"
+//	@Param			webhookId	path		string			true	"Webhook ID"
+//	@Param			webhook		body		WebhookRequest	true	"Webhook URL and event filter"
+//	@Success		200			{object}	entities.Webhook
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/webhooks/{webhookId} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	domainID, webhookID, ok := parseWebhookPath(c)
+	if !ok {
+		return
+	}
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Request.Context(), domainID, webhookID, req.URL, req.Events, req.Enabled)
+	if err != nil {
+		respondError(c, err, "Failed to update webhook")
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// RotateWebhookSecret godoc
+//
+//	@Summary		Rotate a webhook secret
+//	@Description	Replace the signing secret of a webhook and return the new one. Deliveries sent from now on are signed with it.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Success		200			{object}	services.CreatedWebhook
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/webhooks/{webhookId}/rotate-secret [post]
+func (h *WebhookHandler) RotateWebhookSecret(c *gin.Context) {
+	domainID, webhookID, ok := parseWebhookPath(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.RotateSecret(c.Request.Context(), domainID, webhookID)
+	if err != nil {
+		respondError(c, err, "Failed to rotate webhook secret")
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook godoc
+//
+//	@Summary		Delete a webhook
+//	@Description	Delete a webhook together with its delivery log; pending deliveries are dropped
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Success		204			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/webhooks/{webhookId} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	domainID, webhookID, ok := parseWebhookPath(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), domainID, webhookID); err != nil {
+		respondError(c, err, "Failed to delete webhook")
+		return
+	}
+	c.JSON(http.StatusNoContent, MessageResponse{Message: "Webhook deleted successfully"})
+}
+
+// ListWebhookDeliveries godoc
+//
+//	@Summary		List webhook deliveries
+//	@Description	Get the most recent deliveries of a webhook, newest first, with the attempts made, the response status and error of the last attempt, and when a pending delivery is tried next. Deliveries that ran out of attempts have status failed.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Param			status		query		string	false	"Only deliveries with this status"	Enums(pending, succeeded, failed)
+//	@Param			limit		query		int		false	"Maximum deliveries to return"	minimum(1)	maximum(500)	default(50)
+//	@Success		200			{array}		entities.WebhookDelivery
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/webhooks/{webhookId}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	domainID, webhookID, ok := parseWebhookPath(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil {
+		limit = 50
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), domainID, webhookID, c.Query("status"), limit)
+	if err != nil {
+		respondError(c, err, "Failed to list webhook deliveries")
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// RedeliverWebhookDelivery godoc
+//
+//	@Summary		Redeliver a webhook delivery
+//	@Description	Queue a delivery again with a fresh set of attempts, e.g. after a failed endpoint has been fixed
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Security		OperatorToken
+//	@Param			domainId	path		string	true	"Domain ID"
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Param			deliveryId	path		string	true	"Delivery ID"
+//	@Success		202			{object}	MessageResponse
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/domains/{domainId}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhookDelivery(c *gin.Context) {
+	domainID, webhookID, ok := parseWebhookPath(c)
+	if !ok {
+		return
+	}
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid delivery UUID"})
+		return
+	}
+
+	if err := h.webhookService.Redeliver(c.Request.Context(), domainID, webhookID, deliveryID); err != nil {
+		respondError(c, err, "Failed to redeliver webhook delivery")
+		return
+	}
+	c.JSON(http.StatusAccepted, MessageResponse{Message: "Delivery queued"})
+}
+
+func parseWebhookPath(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	domainID, err := uuid.Parse(c.Param("domainId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid domain UUID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	webhookID, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid webhook UUID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return domainID, webhookID, true
+}
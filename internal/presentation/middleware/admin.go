@@ -0,0 +1,238 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"backend/internal/application/services"
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminAuth authorizes the admin API. Authenticate resolves the caller of every admin route; the
+// routes of one domain then check the caller manages it with one of the scoping handlers, and
+// domain-wide routes with SystemAdmin. When not enforced, every handler lets all requests through.
+type AdminAuth struct {
+	service       services.AdminAuthorizationService
+	operatorToken string
+	enforced      bool
+}
+
+func NewAdminAuth(service services.AdminAuthorizationService, operatorToken string, enforced bool) *AdminAuth {
+	return &AdminAuth{service: service, operatorToken: operatorToken, enforced: enforced}
+}
+
+// Authenticate resolves the caller from X-Operator-Token, which makes them a system admin, or else
+// from the bearer token, and attaches them to the request context for the handlers below. A
+// narrowed token acts as admin on the routes of a RequireScope before it that it holds. When admin
+// authorization is off, every caller works on every tenant's data, as a system admin would.
+func (a *AdminAuth) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !a.enforced {
+			c.Request = c.Request.WithContext(tenancy.AsSystem(c.Request.Context()))
+			c.Next()
+			return
+		}
+		principal, err := a.resolve(c)
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(services.WithAdminPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+func (a *AdminAuth) resolve(c *gin.Context) (*services.AdminPrincipal, error) {
+	if presented := c.GetHeader("X-Operator-Token"); presented != "" {
+		if a.operatorToken == "" || subtle.ConstantTimeCompare([]byte(presented), []byte(a.operatorToken)) != 1 {
+			return nil, domainerrors.Unauthorized("invalid operator token")
+		}
+		return &services.AdminPrincipal{System: true}, nil
+	}
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		token = ""
+	}
+	return a.service.ResolveAdmin(c.Request.Context(), token, c.GetString(routeScopeKey))
+}
+
+// SystemAdmin restricts a route to system admins, such as those creating or deleting domains.
+func (a *AdminAuth) SystemAdmin() gin.HandlerFunc {
+	return a.authorize(func(c *gin.Context, principal *services.AdminPrincipal) error {
+		if !principal.System {
+			return domainerrors.Forbidden("only system admins can manage domains").WithCode("system_admin_required")
+		}
+		return nil
+	})
+}
+
+// DomainParam scopes a route to the domain in a path parameter.
+func (a *AdminAuth) DomainParam(name string) gin.HandlerFunc {
+	return a.domainFrom(false, func(c *gin.Context) string { return c.Param(name) })
+}
+
+// DomainQuery scopes a route to the domain in a query parameter.
+func (a *AdminAuth) DomainQuery(name string) gin.HandlerFunc {
+	return a.domainFrom(false, func(c *gin.Context) string { return c.Query(name) })
+}
+
+// DelegatedDomainParam is DomainParam for routes org unit admins may also use in their own domain;
+// the service narrows what they see to their unit's subtree.
+func (a *AdminAuth) DelegatedDomainParam(name string) gin.HandlerFunc {
+	return a.domainFrom(true, func(c *gin.Context) string { return c.Param(name) })
+}
+
+// DelegatedDomainQuery is DomainQuery for routes org unit admins may also use in their own domain.
+func (a *AdminAuth) DelegatedDomainQuery(name string) gin.HandlerFunc {
+	return a.domainFrom(true, func(c *gin.Context) string { return c.Query(name) })
+}
+
+// DomainForm scopes a route to the domain in a multipart or URL-encoded form field.
+func (a *AdminAuth) DomainForm(name string) gin.HandlerFunc {
+	return a.domainFrom(false, func(c *gin.Context) string { return c.PostForm(name) })
+}
+
+// DomainJSON scopes a route to the domain in a string field of its JSON body, which must be set.
+// The field is read the way the handler binds it, and the body is put back for the handler.
+func (a *AdminAuth) DomainJSON(field string) gin.HandlerFunc {
+	return a.authorize(func(c *gin.Context, principal *services.AdminPrincipal) error {
+		value, err := jsonUUIDField(c, field, true)
+		if err != nil {
+			return err
+		}
+		return checkDomain(principal, uuid.MustParse(value), false)
+	})
+}
+
+// Resource scopes a route to the domain of the user, role, group, policy, permission, API key or
+// org unit whose ID is in a path parameter. Org unit admins only pass for users of their subtree.
+func (a *AdminAuth) Resource(resource, param string) gin.HandlerFunc {
+	return a.resourceFrom(resource, func(c *gin.Context) (string, error) { return c.Param(param), nil })
+}
+
+// ResourceJSON scopes a route to the domain of the resource whose ID is in a field of its JSON
+// body, which must be set.
+func (a *AdminAuth) ResourceJSON(resource, field string) gin.HandlerFunc {
+	return a.resourceFrom(resource, func(c *gin.Context) (string, error) { return jsonUUIDField(c, field, true) })
+}
+
+// OptionalResourceJSON is ResourceJSON for a field the route may leave out; without it the route
+// addresses no such resource and passes.
+func (a *AdminAuth) OptionalResourceJSON(resource, field string) gin.HandlerFunc {
+	return a.resourceFrom(resource, func(c *gin.Context) (string, error) { return jsonUUIDField(c, field, false) })
+}
+
+// domainFrom checks the caller manages the domain read by value, or with delegated is an org unit
+// admin of it. A missing or malformed ID in a path or query parameter, which the handler reads the
+// same way, is left to the handler, which rejects it or, for listings, falls back to the admin's
+// own domain.
+func (a *AdminAuth) domainFrom(delegated bool, value func(*gin.Context) string) gin.HandlerFunc {
+	return a.authorize(func(c *gin.Context, principal *services.AdminPrincipal) error {
+		domainID, err := uuid.Parse(value(c))
+		if err != nil {
+			return nil
+		}
+		return checkDomain(principal, domainID, delegated)
+	})
+}
+
+// checkDomain checks the caller manages the domain, or with delegated is an org unit admin of it.
+func checkDomain(principal *services.AdminPrincipal, domainID uuid.UUID, delegated bool) error {
+	if principal.System {
+		return nil
+	}
+	if principal.Delegated() {
+		if delegated && principal.DomainID == domainID {
+			return nil
+		}
+		return domainerrors.Forbidden("org unit admins can only manage the users of their org unit").WithCode("org_unit_forbidden")
+	}
+	if !principal.CanManage(domainID) {
+		return domainerrors.Forbidden("domain admins can only manage their own domain").WithCode("domain_forbidden")
+	}
+	return nil
+}
+
+// resourceFrom checks the caller manages the domain of the resource whose ID is read by value. An
+// empty or malformed ID that value doesn't reject is left to the handler.
+func (a *AdminAuth) resourceFrom(resource string, value func(*gin.Context) (string, error)) gin.HandlerFunc {
+	return a.authorize(func(c *gin.Context, principal *services.AdminPrincipal) error {
+		raw, err := value(c)
+		if err != nil || raw == "" {
+			return err
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil
+		}
+		return a.service.AuthorizeResource(c.Request.Context(), principal, resource, id)
+	})
+}
+
+// authorize runs check against the caller resolved by Authenticate.
+func (a *AdminAuth) authorize(check func(*gin.Context, *services.AdminPrincipal) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !a.enforced {
+			c.Next()
+			return
+		}
+		principal := services.AdminPrincipalFrom(c.Request.Context())
+		if principal == nil {
+			_ = c.Error(domainerrors.Unauthorized("admin credentials are required"))
+			c.Abort()
+			return
+		}
+		if err := check(c, principal); err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// jsonUUIDField reads a UUID string field of the request's JSON body and restores the body. It
+// decodes the body as the handler's ShouldBindJSON does, so keys matching field in another case
+// count and the last one wins: the value checked is the value the handler will bind. A body that
+// isn't a JSON object, a field that isn't a UUID string, or a missing field when required is
+// rejected as invalid, since the handler could otherwise bind an ID that was never authorized.
+func jsonUUIDField(c *gin.Context, field string, required bool) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return "", domainerrors.Validation("failed to read the request body").Wrap(err)
+		}
+	}
+	target := reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: "Value",
+		Type: reflect.TypeOf(""),
+		Tag:  reflect.StructTag(fmt.Sprintf(`json:%q`, field)),
+	}}))
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(target.Interface()); err != nil {
+		return "", domainerrors.Validation("invalid request body: %s must be a string", field)
+	}
+	value := target.Elem().Field(0).String()
+	if value == "" {
+		if required {
+			return "", domainerrors.Validation("%s is required", field)
+		}
+		return "", nil
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		return "", domainerrors.Validation("%s must be a UUID", field)
+	}
+	return value, nil
+}
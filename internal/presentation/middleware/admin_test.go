@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var testSigningKey = []byte("test-signing-key")
+
+// fakeAuth verifies the tokens of testToken and gives every user the permissions it was built with.
+type fakeAuth struct {
+	services.AuthService
+	permissions []string
+}
+
+func (f *fakeAuth) ValidateToken(ctx context.Context, token string) (*services.TokenClaims, error) {
+	claims := &services.TokenClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return testSigningKey, nil }); err != nil {
+		return nil, domainerrors.Unauthorized("invalid token")
+	}
+	return claims, nil
+}
+
+func (f *fakeAuth) GetEffectivePermissions(ctx context.Context, userID uuid.UUID) (*services.EffectivePermissions, error) {
+	return &services.EffectivePermissions{UserID: userID, Permissions: f.permissions}, nil
+}
+
+func testToken(t *testing.T, scope string) string {
+	t.Helper()
+	return signTestToken(t, services.TokenClaims{UserID: uuid.New(), DomainID: uuid.New(), Scope: scope})
+}
+
+func signTestToken(t *testing.T, claims services.TokenClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSigningKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAdminAuthNarrowedTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := &fakeAuth{permissions: []string{entities.PermissionDomainAdmin, entities.ScopeUsersRead}}
+	adminAuth := NewAdminAuth(services.NewAdminAuthorizationService(auth, nil, nil, nil, nil, nil, nil, nil, uuid.Nil), "", true)
+
+	r := gin.New()
+	r.Use(ErrorHandler())
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	r.DELETE("/roles/:id", adminAuth.Authenticate(), ok)
+	r.GET("/users", RequireScope(entities.ScopeUsersRead), adminAuth.Authenticate(), ok)
+	r.POST("/users", RequireScope(entities.ScopeUsersWrite), adminAuth.Authenticate(), ok)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		scope  string
+		want   int
+	}{
+		{"unscoped token deletes a role", http.MethodDelete, "/roles/1", "", http.StatusNoContent},
+		{"users:read token can't delete a role", http.MethodDelete, "/roles/1", "users:read", http.StatusForbidden},
+		{"users:read token lists users", http.MethodGet, "/users", "users:read", http.StatusNoContent},
+		{"users:read token can't create a user", http.MethodPost, "/users", "users:read", http.StatusForbidden},
+		{"domain:admin token deletes a role", http.MethodDelete, "/roles/1", "domain:admin", http.StatusNoContent},
+		{"domain:admin token can't list users", http.MethodGet, "/users", "domain:admin", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+testToken(t, tt.scope))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminAuthCallers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	systemDomainID, domainID := uuid.New(), uuid.New()
+	newRouter := func(permissions ...string) *gin.Engine {
+		auth := &fakeAuth{permissions: permissions}
+		adminAuth := NewAdminAuth(services.NewAdminAuthorizationService(auth, nil, nil, nil, nil, nil, nil, nil, systemDomainID), "operator-secret", true)
+		r := gin.New()
+		r.Use(ErrorHandler())
+		ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+		r.POST("/domains", adminAuth.Authenticate(), adminAuth.SystemAdmin(), ok)
+		r.GET("/domains/:id/users", adminAuth.Authenticate(), adminAuth.DomainParam("id"), ok)
+		return r
+	}
+	tokenIn := func(domain uuid.UUID) string {
+		return signTestToken(t, services.TokenClaims{UserID: uuid.New(), DomainID: domain})
+	}
+
+	tests := []struct {
+		name        string
+		permissions []string
+		method      string
+		path        string
+		headers     map[string]string
+		want        int
+		wantCode    string
+	}{
+		{"no credentials", nil, http.MethodGet, "/domains/" + domainID.String() + "/users", nil, http.StatusUnauthorized, "unauthorized"},
+		{"malformed bearer token", nil, http.MethodGet, "/domains/" + domainID.String() + "/users",
+			map[string]string{"Authorization": "Bearer not-a-jwt"}, http.StatusUnauthorized, "unauthorized"},
+		{"wrong operator token", nil, http.MethodPost, "/domains",
+			map[string]string{"X-Operator-Token": "guess"}, http.StatusUnauthorized, "unauthorized"},
+		{"operator token creates a domain", nil, http.MethodPost, "/domains",
+			map[string]string{"X-Operator-Token": "operator-secret"}, http.StatusNoContent, ""},
+		{"user without admin permissions", []string{entities.ScopeUsersRead}, http.MethodGet, "/domains/" + domainID.String() + "/users",
+			map[string]string{"Authorization": "Bearer " + tokenIn(domainID)}, http.StatusForbidden, "admin_required"},
+		{"domain admin manages their domain", []string{entities.PermissionDomainAdmin}, http.MethodGet, "/domains/" + domainID.String() + "/users",
+			map[string]string{"Authorization": "Bearer " + tokenIn(domainID)}, http.StatusNoContent, ""},
+		{"domain admin can't manage another domain", []string{entities.PermissionDomainAdmin}, http.MethodGet, "/domains/" + uuid.NewString() + "/users",
+			map[string]string{"Authorization": "Bearer " + tokenIn(domainID)}, http.StatusForbidden, "domain_forbidden"},
+		{"domain admin can't create domains", []string{entities.PermissionDomainAdmin}, http.MethodPost, "/domains",
+			map[string]string{"Authorization": "Bearer " + tokenIn(domainID)}, http.StatusForbidden, "system_admin_required"},
+		{"system:admin outside the system domain is no admin", []string{entities.PermissionSystemAdmin}, http.MethodPost, "/domains",
+			map[string]string{"Authorization": "Bearer " + tokenIn(domainID)}, http.StatusForbidden, "admin_required"},
+		{"system admin creates a domain", []string{entities.PermissionSystemAdmin}, http.MethodPost, "/domains",
+			map[string]string{"Authorization": "Bearer " + tokenIn(systemDomainID)}, http.StatusNoContent, ""},
+		{"system admin manages any domain", []string{entities.PermissionSystemAdmin}, http.MethodGet, "/domains/" + domainID.String() + "/users",
+			map[string]string{"Authorization": "Bearer " + tokenIn(systemDomainID)}, http.StatusNoContent, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			newRouter(tt.permissions...).ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.wantCode != "" {
+				var body struct{ Code string }
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatal(err)
+				}
+				if body.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q", body.Code, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+func TestAdminAuthDomainJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	domainID, otherDomainID := uuid.New(), uuid.New()
+	auth := &fakeAuth{permissions: []string{entities.PermissionDomainAdmin}}
+	adminAuth := NewAdminAuth(services.NewAdminAuthorizationService(auth, nil, nil, nil, nil, nil, nil, nil, uuid.Nil), "", true)
+
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.POST("/users", adminAuth.Authenticate(), adminAuth.DomainJSON("domain_id"), func(c *gin.Context) {
+		var req struct {
+			DomainID string `json:"domain_id"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.String(http.StatusOK, req.DomainID)
+	})
+	token := signTestToken(t, services.TokenClaims{UserID: uuid.New(), DomainID: domainID})
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"own domain", `{"domain_id":"` + domainID.String() + `"}`, http.StatusOK},
+		{"other domain", `{"domain_id":"` + otherDomainID.String() + `"}`, http.StatusForbidden},
+		{"missing domain", `{}`, http.StatusBadRequest},
+		{"malformed domain", `{"domain_id":"nope"}`, http.StatusBadRequest},
+		{"non-string domain", `{"domain_id":1}`, http.StatusBadRequest},
+		{"other domain under another case", `{"domain_id":"nope","DOMAIN_ID":"` + otherDomainID.String() + `"}`, http.StatusForbidden},
+		{"other domain after own domain", `{"domain_id":"` + domainID.String() + `","Domain_Id":"` + otherDomainID.String() + `"}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if w.Code == http.StatusOK && w.Body.String() != domainID.String() {
+				t.Errorf("handler bound domain %s, want %s", w.Body.String(), domainID)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyContextKey is the gin context key holding the authenticated *entities.APIKey.
+const APIKeyContextKey = "api_key"
+
+// APIKeyRateLimit enforces per-key limits on requests carrying X-API-Key and reports
+// consumption through X-RateLimit-* and X-Quota-* headers. Requests without a key pass through.
+func APIKeyRateLimit(apiKeyService services.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader("X-API-Key")
+		if rawKey == "" {
+			c.Next()
+			return
+		}
+
+		key, err := apiKeyService.Authenticate(c.Request.Context(), rawKey)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			return
+		}
+
+		usage, allowed := apiKeyService.Consume(key)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(usage.Minute.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(usage.Minute.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(usage.Minute.ResetAt.Unix(), 10))
+		c.Header("X-Quota-Limit", strconv.Itoa(usage.Day.Limit))
+		c.Header("X-Quota-Remaining", strconv.Itoa(usage.Day.Remaining))
+		c.Header("X-Quota-Reset", strconv.FormatInt(usage.Day.ResetAt.Unix(), 10))
+
+		if !allowed {
+			// An exhausted daily quota outlasts the per-minute window
+			resetAt := usage.Minute.ResetAt
+			if usage.Day.Remaining == 0 {
+				resetAt = usage.Day.ResetAt
+			}
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(resetAt).Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded"})
+			return
+		}
+
+		c.Set(APIKeyContextKey, key)
+		c.Next()
+	}
+}
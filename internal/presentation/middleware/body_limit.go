@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit answers 413 to requests whose Content-Length is over maxBytes and stops reading bodies
+// sent without one at maxBytes, which fails the handler's binding. Routes whose path ends with one
+// of importRoutes, such as "/users/import" under any API version, get importMaxBytes instead. It
+// must run before any middleware reading the body.
+func BodyLimit(maxBytes, importMaxBytes int64, importRoutes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := maxBytes
+		for _, route := range importRoutes {
+			if strings.HasSuffix(c.FullPath(), route) {
+				limit = importMaxBytes
+				break
+			}
+		}
+
+		if c.Request.ContentLength > limit {
+			abortBodyTooLarge(c)
+			return
+		}
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+		c.Next()
+	}
+}
+
+// isBodyTooLarge reports whether reading a request body failed on its BodyLimit.
+func isBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+func abortBodyTooLarge(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body is too large", "code": "request_too_large"})
+}
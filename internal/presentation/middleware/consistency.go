@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConsistencyTokenHeader carries the read-after-write token: returned after successful writes and
+// sent back by clients so their next reads don't hit a replica that hasn't caught up.
+const ConsistencyTokenHeader = "X-Consistency-Token"
+
+// ReadConsistency honours X-Consistency-Token on reads and issues a fresh token with every
+// successful write. It does nothing unless read replicas are configured.
+func ReadConsistency(router *repositories.ShardRouter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !router.HasReplicas() {
+			c.Next()
+			return
+		}
+
+		if token := c.GetHeader(ConsistencyTokenHeader); token != "" {
+			c.Request = c.Request.WithContext(repositories.WithConsistencyToken(c.Request.Context(), token))
+		}
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			// The token must be taken after the write but sent before the body, so it's added as
+			// the status is written
+			c.Writer = &consistencyWriter{ResponseWriter: c.Writer, ctx: c.Request.Context(), router: router}
+		}
+		c.Next()
+	}
+}
+
+type consistencyWriter struct {
+	gin.ResponseWriter
+	ctx     context.Context
+	router  *repositories.ShardRouter
+	written bool
+}
+
+func (w *consistencyWriter) WriteHeader(code int) {
+	w.addToken(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *consistencyWriter) WriteHeaderNow() {
+	w.addToken(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *consistencyWriter) Write(data []byte) (int, error) {
+	w.addToken(w.Status())
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *consistencyWriter) WriteString(s string) (int, error) {
+	w.addToken(w.Status())
+	return w.ResponseWriter.WriteString(s)
+}
+
+// addToken sets the header once, for successful responses only. A failed lookup leaves it out;
+// clients without a token may read slightly stale data but the write itself succeeded.
+func (w *consistencyWriter) addToken(code int) {
+	if w.written || w.ResponseWriter.Written() {
+		return
+	}
+	w.written = true
+	if code >= http.StatusBadRequest {
+		return
+	}
+	if token, err := w.router.ConsistencyToken(w.ctx); err == nil {
+		w.Header().Set(ConsistencyTokenHeader, token)
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"backend/internal/application/services"
+	"backend/internal/infrastructure/cache"
+	"backend/internal/infrastructure/config"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// corsExposedHeaders are the response headers browsers let scripts read. They're the API's own
+// headers, so they aren't configurable.
+var corsExposedHeaders = []string{
+	"Content-Length", "Link", "Retry-After", DegradationHeader, "Deprecation", "Sunset", "Idempotent-Replayed", "X-Consistency-Token",
+	"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-Quota-Limit", "X-Quota-Remaining", "X-Quota-Reset",
+	"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy",
+}
+
+// CORS answers preflight requests and sets the CORS headers of the origins cfg allows. With
+// cfg.DomainOrigins an origin not listed is also allowed when it's https:// and its hostname
+// resolves to a domain; the answers are kept in lookups, if not nil, for ttl.
+func CORS(cfg *config.CORSConfig, domains services.DomainService, lookups cache.Cache, ttl time.Duration) gin.HandlerFunc {
+	corsConfig := cors.Config{
+		AllowAllOrigins:  cfg.AllowsAnyOrigin(),
+		AllowMethods:     cfg.AllowedMethods,
+		AllowHeaders:     cfg.AllowedHeaders,
+		ExposeHeaders:    corsExposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		AllowWildcard:    true,
+		MaxAge:           cfg.MaxAge,
+	}
+	if !corsConfig.AllowAllOrigins {
+		corsConfig.AllowOrigins = cfg.AllowedOrigins
+	}
+	if cfg.DomainOrigins {
+		corsConfig.AllowOriginWithContextFunc = func(c *gin.Context, origin string) bool {
+			return domainOriginAllowed(c.Request.Context(), domains, lookups, ttl, origin)
+		}
+	}
+	return cors.New(corsConfig)
+}
+
+func domainOriginAllowed(ctx context.Context, domains services.DomainService, lookups cache.Cache, ttl time.Duration, origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.Path != "" {
+		return false
+	}
+	host := u.Hostname()
+
+	key := "cors:origin:" + host
+	if lookups != nil {
+		if value, found, err := lookups.Get(ctx, key); err == nil && found {
+			return string(value) == "1"
+		}
+	}
+	// A failed lookup counts as not allowed too, for ttl at most
+	_, err = domains.ResolveDomain(ctx, host)
+	allowed := err == nil
+	if lookups != nil {
+		value := []byte("0")
+		if allowed {
+			value = []byte("1")
+		}
+		_ = lookups.Set(ctx, key, value, ttl)
+	}
+	return allowed
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DegradationHeader lists the features impacted by failing dependencies, e.g.
+// "admin_writes=unavailable, login=slow". It's absent while nothing is degraded.
+const DegradationHeader = "Degradation"
+
+// Degradation adds DegradationHeader to every response from the latest background health probe,
+// so clients can tell an outage from their own mistakes without calling /readyz.
+func Degradation(health services.HealthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if report := health.Current(); report != nil {
+			if value := report.Header(); value != "" {
+				c.Header(DegradationHeader, value)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks routes superseded by the same path under successor, e.g. "/api/v1". Responses
+// carry a Deprecation header (RFC 9745), a Sunset header (RFC 8594) and a successor-version Link to
+// the new path. From sunset on, requests are answered 410 Gone instead.
+func Deprecated(deprecatedAt, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetHeader := sunset.UTC().Format(http.TimeFormat)
+
+	return func(c *gin.Context) {
+		link := successor + c.Request.URL.Path
+		if c.Request.URL.RawQuery != "" {
+			link += "?" + c.Request.URL.RawQuery
+		}
+		c.Writer.Header().Add("Link", "<"+link+`>; rel="successor-version"`)
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetHeader)
+
+		if !time.Now().Before(sunset) {
+			c.AbortWithStatusJSON(http.StatusGone, gin.H{
+				"error": "This path was retired on " + sunset.Format(time.DateOnly) + "; use " + successor + c.Request.URL.Path,
+				"code":  "api_version_sunset",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"backend/internal/presentation/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rawResponseKey marks a request whose response keeps its own format under Envelope.
+const rawResponseKey = "rawResponse"
+
+// RawResponse exempts a route from Envelope, for responses whose format a standard fixes, such as
+// OAuth token responses.
+func RawResponse() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(rawResponseKey, true)
+		c.Next()
+	}
+}
+
+// Envelope serves the handlers below it in the response envelope of the response package: their
+// JSON responses are buffered and rewritten, and errors recorded with c.Error are answered as error
+// envelopes. Other responses, downloads and routes marked with RawResponse pass through as they
+// are.
+func Envelope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		original := c.Writer
+		writer := &envelopeWriter{ResponseWriter: original, c: c, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		switch writer.mode {
+		case envelopeBuffering:
+			body := response.Wrap(writer.status, writer.body.Bytes())
+			original.Header().Del("Content-Length")
+			original.WriteHeader(writer.status)
+			_, _ = original.Write(body)
+		case envelopeUndecided:
+			if c.Errors.Last() != nil && !original.Written() {
+				status, code, message := describeContextError(c)
+				c.JSON(status, response.Failure(status, code, message, nil))
+				return
+			}
+			if writer.statusSet {
+				original.WriteHeader(writer.status)
+				original.WriteHeaderNow()
+			}
+		}
+	}
+}
+
+const (
+	envelopeUndecided = iota
+	envelopeBuffering
+	envelopePassthrough
+)
+
+// envelopeWriter decides with the first write whether the response is enveloped, and buffers it
+// if so.
+type envelopeWriter struct {
+	gin.ResponseWriter
+	c         *gin.Context
+	mode      int
+	status    int
+	statusSet bool
+	body      bytes.Buffer
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. for exports extending their
+// write deadline.
+func (w *envelopeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *envelopeWriter) decide() {
+	if w.mode != envelopeUndecided {
+		return
+	}
+	header := w.ResponseWriter.Header()
+	raw := w.c.GetBool(rawResponseKey)
+	json := strings.HasPrefix(header.Get("Content-Type"), "application/json")
+	if raw || !json || header.Get("Content-Disposition") != "" {
+		w.mode = envelopePassthrough
+		if w.statusSet {
+			w.ResponseWriter.WriteHeader(w.status)
+		}
+		return
+	}
+	w.mode = envelopeBuffering
+}
+
+func (w *envelopeWriter) WriteHeader(code int) {
+	if w.mode == envelopePassthrough {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 {
+		w.status, w.statusSet = code, true
+	}
+}
+
+func (w *envelopeWriter) WriteHeaderNow() {
+	w.decide()
+	if w.mode == envelopePassthrough {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.mode == envelopePassthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	w.decide()
+	if w.mode == envelopePassthrough {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
+
+func (w *envelopeWriter) Flush() {
+	if w.mode == envelopePassthrough {
+		w.ResponseWriter.Flush()
+	}
+}
+
+func (w *envelopeWriter) Status() int {
+	if w.mode == envelopePassthrough {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *envelopeWriter) Size() int {
+	if w.mode == envelopePassthrough {
+		return w.ResponseWriter.Size()
+	}
+	if w.mode == envelopeBuffering {
+		return w.body.Len()
+	}
+	return -1
+}
+
+func (w *envelopeWriter) Written() bool {
+	if w.mode == envelopePassthrough {
+		return w.ResponseWriter.Written()
+	}
+	return w.mode == envelopeBuffering
+}
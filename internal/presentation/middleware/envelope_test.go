@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	v2 := r.Group("/api/v2", Envelope())
+	v2.GET("/user", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": "u1", "username": "jdoe"})
+	})
+	v2.GET("/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"users": []string{"u1"}, "total": 1, "page": 1, "limit": 20, "total_pages": 1})
+	})
+	v2.POST("/users", func(c *gin.Context) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required", "code": "invalid_username", "field": "username"})
+	})
+	v2.GET("/roles/:id", func(c *gin.Context) {
+		_ = c.Error(domainerrors.NotFound("role not found"))
+	})
+	v2.POST("/roles", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	v2.POST("/oauth/token", RawResponse(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"access_token": "t", "token_type": "Bearer"})
+	})
+	v2.GET("/export", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/csv", []byte("id\nu1\n"))
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{"object", http.MethodGet, "/api/v2/user", http.StatusOK,
+			`{"data":{"id":"u1","username":"jdoe"}}`},
+		{"listing", http.MethodGet, "/api/v2/users", http.StatusOK,
+			`{"data":{"users":["u1"]},"meta":{"limit":20,"page":1,"total":1,"total_pages":1}}`},
+		{"v1 error", http.MethodPost, "/api/v2/users", http.StatusBadRequest,
+			`{"error":{"code":"invalid_username","message":"username is required","details":{"field":"username"}}}`},
+		{"domain error", http.MethodGet, "/api/v2/roles/1", http.StatusNotFound,
+			`{"error":{"code":"not_found","message":"Role not found"}}`},
+		{"no content", http.MethodPost, "/api/v2/roles", http.StatusNoContent, ``},
+		{"raw response", http.MethodPost, "/api/v2/oauth/token", http.StatusOK,
+			`{"access_token":"t","token_type":"Bearer"}`},
+		{"download", http.MethodGet, "/api/v2/export", http.StatusOK, "id\nu1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("body = %s, want %s", w.Body.String(), tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorKinds maps domain error kinds to their HTTP status and default code.
+var errorKinds = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{domainerrors.ErrNotFound, http.StatusNotFound, "not_found"},
+	{domainerrors.ErrConflict, http.StatusConflict, "conflict"},
+	{domainerrors.ErrValidation, http.StatusBadRequest, "validation_failed"},
+	{domainerrors.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{domainerrors.ErrForbidden, http.StatusForbidden, "forbidden"},
+}
+
+// ErrorHandler writes the error a handler recorded with c.Error as {"error", "code"}. Domain
+// errors get the status of their kind and their own message; any other error is logged and
+// answered with 500, using the error's meta string as the message when the handler set one.
+// Nothing is written when the handler already sent a response.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		last := c.Errors.Last()
+		if last == nil || c.Writer.Written() {
+			return
+		}
+
+		status, code, message := describeContextError(c)
+		c.JSON(status, gin.H{"error": message, "code": code})
+	}
+}
+
+// describeContextError describes the last error recorded on c, logging it when it isn't a domain
+// error.
+func describeContextError(c *gin.Context) (status int, code, message string) {
+	last := c.Errors.Last()
+	status, code, message, ok := DescribeError(last.Err)
+	if !ok {
+		log.Printf("%s %s: %v", c.Request.Method, c.FullPath(), last.Err)
+		if fallback, isString := last.Meta.(string); isString {
+			message = fallback
+		}
+	}
+	return status, code, message
+}
+
+// DescribeError returns the status, code and client-safe message for err. ok is false when err
+// is not a domain error, in which case it is described as a 500 internal error.
+func DescribeError(err error) (status int, code, message string, ok bool) {
+	var domainErr *domainerrors.Error
+	if errors.As(err, &domainErr) {
+		for _, k := range errorKinds {
+			if errors.Is(domainErr, k.kind) {
+				code = domainErr.Code
+				if code == "" {
+					code = k.code
+				}
+				return k.status, code, capitalize(domainErr.Message), true
+			}
+		}
+	}
+	return http.StatusInternalServerError, "internal_error", "Internal server error", false
+}
+
+// capitalize turns a Go-style error string into a sentence for clients.
+func capitalize(message string) string {
+	if message == "" {
+		return message
+	}
+	return strings.ToUpper(message[:1]) + message[1:]
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a create request it may retry
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from an earlier request with the same key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// Idempotency makes a route safe to retry with an Idempotency-Key header: the first successful
+// response for a key is stored and returned again for retries of the same request by the same
+// caller, without running the handler. Failed requests release the key so a retry runs again.
+// Requests without the header are unaffected. Mount it after the caller is authenticated.
+func Idempotency(service services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			if isBodyTooLarge(err) {
+				abortBodyTooLarge(c)
+				return
+			}
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		hash := sha256.Sum256(body)
+		scope := c.Request.Method + " " + c.FullPath() + " " + idempotencyCaller(c)
+
+		ctx := c.Request.Context()
+		stored, err := service.Begin(ctx, scope, key, hex.EncodeToString(hash[:]))
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// The client may have given up waiting, which is when it retries; the outcome must still
+		// be saved
+		ctx = context.WithoutCancel(ctx)
+		status := writer.Status()
+		if len(c.Errors) > 0 || status < http.StatusOK || status >= http.StatusMultipleChoices {
+			if err := service.Release(ctx, scope, key); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+		if err := service.Complete(ctx, scope, key, status, writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			log.Printf("Failed to store idempotent response: %v", err)
+		}
+	}
+}
+
+// idempotencyCaller identifies who sent the request, so one caller's key never replays another's
+// response: the admin resolved by AdminAuth, or else a hash of the credentials presented.
+func idempotencyCaller(c *gin.Context) string {
+	if principal := services.AdminPrincipalFrom(c.Request.Context()); principal != nil {
+		if principal.UserID == uuid.Nil {
+			return "operator"
+		}
+		return "admin:" + principal.UserID.String()
+	}
+	credentials := sha256.Sum256([]byte(c.GetHeader("Authorization") + "\n" + c.GetHeader("X-API-Key") + "\n" + c.GetHeader("X-Operator-Token")))
+	return "credentials:" + hex.EncodeToString(credentials[:])
+}
+
+// idempotencyWriter keeps a copy of the response body for storing.
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"backend/internal/application/services"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeIdempotencyKeys keeps idempotency keys in memory.
+type fakeIdempotencyKeys struct {
+	mu      sync.Mutex
+	records map[string]repositories.IdempotencyRecord
+}
+
+func (f *fakeIdempotencyKeys) Reserve(ctx context.Context, record *repositories.IdempotencyRecord, staleBefore time.Time) (*repositories.IdempotencyRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := record.Scope + "\x00" + record.Key
+	if existing, ok := f.records[id]; ok && existing.ExpiresAt.After(time.Now()) && (existing.StatusCode != 0 || existing.CreatedAt.After(staleBefore)) {
+		return &existing, nil
+	}
+	reserved := *record
+	reserved.CreatedAt = time.Now()
+	f.records[id] = reserved
+	return nil, nil
+}
+
+func (f *fakeIdempotencyKeys) Complete(ctx context.Context, record *repositories.IdempotencyRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := record.Scope + "\x00" + record.Key
+	stored := f.records[id]
+	stored.StatusCode, stored.ContentType, stored.Body = record.StatusCode, record.ContentType, record.Body
+	f.records[id] = stored
+	return nil
+}
+
+func (f *fakeIdempotencyKeys) Release(ctx context.Context, scope, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.records, scope+"\x00"+key)
+	return nil
+}
+
+func (f *fakeIdempotencyKeys) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+// idempotentRouter serves POST /users, which creates a user with the next ID unless the request
+// asks to fail. calls counts the handler's runs.
+func idempotentRouter(service services.IdempotencyService, calls *int) *gin.Engine {
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.POST("/users", Idempotency(service), func(c *gin.Context) {
+		*calls++
+		if c.Query("fail") != "" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": *calls})
+	})
+	return r
+}
+
+func postUser(r *gin.Engine, query, key, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/users"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func newTestIdempotencyService() services.IdempotencyService {
+	keys := &fakeIdempotencyKeys{records: make(map[string]repositories.IdempotencyRecord)}
+	return services.NewIdempotencyService(keys, &config.IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Minute})
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	r := idempotentRouter(newTestIdempotencyService(), &calls)
+
+	first := postUser(r, "", "key-1", "alice", `{"username":"jdoe"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first: status = %d, want %d", first.Code, http.StatusCreated)
+	}
+	retry := postUser(r, "", "key-1", "alice", `{"username":"jdoe"}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %s, want %d %s", retry.Code, retry.Body.String(), first.Code, first.Body.String())
+	}
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("retry is missing %s", IdempotentReplayedHeader)
+	}
+	if first.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Errorf("first response has %s", IdempotentReplayedHeader)
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+}
+
+func TestIdempotencyRunsAgain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name         string
+		firstQuery   string
+		key          string
+		retryKey     string
+		retryToken   string
+		wantRetryRun bool
+	}{
+		{"without a key", "", "", "", "alice", true},
+		{"with another key", "", "key-1", "key-2", "alice", true},
+		{"by another caller", "", "key-1", "key-1", "bob", true},
+		{"after a failed request", "?fail=1", "key-1", "key-1", "alice", true},
+		{"same caller and key", "", "key-1", "key-1", "alice", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			r := idempotentRouter(newTestIdempotencyService(), &calls)
+			postUser(r, tt.firstQuery, tt.key, "alice", `{}`)
+			postUser(r, "", tt.retryKey, tt.retryToken, `{}`)
+			if ran := calls == 2; ran != tt.wantRetryRun {
+				t.Errorf("retry ran = %v, want %v", ran, tt.wantRetryRun)
+			}
+		})
+	}
+}
+
+func TestIdempotencyRefusedKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := newTestIdempotencyService()
+	calls := 0
+	r := idempotentRouter(service, &calls)
+
+	postUser(r, "", "key-1", "alice", `{"username":"jdoe"}`)
+	reused := postUser(r, "", "key-1", "alice", `{"username":"someone-else"}`)
+	assertErrorCode(t, reused, http.StatusBadRequest, "idempotency_key_reused")
+
+	tooLong := postUser(r, "", strings.Repeat("k", 256), "alice", `{}`)
+	assertErrorCode(t, tooLong, http.StatusBadRequest, "invalid_idempotency_key")
+
+	// Another request of carol's still holds the key. There is no admin on the route, so the
+	// caller is identified by a hash of the credentials presented
+	credentials := sha256.Sum256([]byte("Bearer carol\n\n"))
+	bodyHash := sha256.Sum256([]byte(`{}`))
+	scope := "POST /users credentials:" + hex.EncodeToString(credentials[:])
+	if _, err := service.Begin(context.Background(), scope, "key-2", hex.EncodeToString(bodyHash[:])); err != nil {
+		t.Fatal(err)
+	}
+	inProgress := postUser(r, "", "key-2", "carol", `{}`)
+	assertErrorCode(t, inProgress, http.StatusConflict, "idempotency_key_in_progress")
+
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+}
+
+func assertErrorCode(t *testing.T, w *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	var body struct{ Code string }
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("status %d, body %q: %v", w.Code, w.Body.String(), err)
+	}
+	if w.Code != status || body.Code != code {
+		t.Errorf("got %d %q, want %d %q", w.Code, body.Code, status, code)
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRejectImpersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler(), Impersonation(&fakeAuth{}))
+	r.DELETE("/auth/me", RejectImpersonation(), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	user := services.TokenClaims{UserID: uuid.New(), DomainID: uuid.New()}
+	impersonated := user
+	impersonated.Act = &services.TokenActor{UserID: uuid.New(), Username: "support.alice"}
+
+	tests := []struct {
+		name   string
+		claims services.TokenClaims
+		want   int
+	}{
+		{"user's own token", user, http.StatusNoContent},
+		{"impersonation token", impersonated, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/auth/me", nil)
+			req.Header.Set("Authorization", "Bearer "+signTestToken(t, tt.claims))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	domainerrors "backend/internal/domain/errors"
+	"backend/internal/infrastructure/tenancy"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireOperator restricts a route group to platform operators presenting the configured token
+// in X-Operator-Token. When no token is configured the routes are closed to everyone. Operators
+// work on every tenant's data.
+func RequireOperator(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			_ = c.Error(domainerrors.Forbidden("operator access is not configured"))
+			c.Abort()
+			return
+		}
+		presented := c.GetHeader("X-Operator-Token")
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			_ = c.Error(domainerrors.Unauthorized("invalid operator token"))
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(tenancy.AsSystem(c.Request.Context()))
+		c.Next()
+	}
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend/internal/infrastructure/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// failingStore is a rate limit store whose backend is down.
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit, time.Time) (ratelimit.Decision, error) {
+	return ratelimit.Decision{}, errors.New("connection refused")
+}
+
+func rateLimitedRouter(store ratelimit.Store, limit ratelimit.Limit, key RateLimitKey) *gin.Engine {
+	r := gin.New()
+	r.GET("/limited", RateLimit(store, "test", limit, key), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return r
+}
+
+func getLimited(r *gin.Engine, remoteAddr string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	req.RemoteAddr = remoteAddr
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitExhausted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := rateLimitedRouter(ratelimit.NewMemoryStore(100), ratelimit.Limit{Requests: 2, Period: time.Minute}, ClientIPKey)
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		w := getLimited(r, "192.0.2.1:1234", nil)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d, want %d", i+1, w.Code, http.StatusNoContent)
+		}
+		if got := w.Header().Get("RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("request %d: RateLimit-Remaining = %q, want %q", i+1, got, wantRemaining)
+		}
+	}
+
+	w := getLimited(r, "192.0.2.1:1234", nil)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if retryAfter := w.Header().Get("Retry-After"); retryAfter != "30" {
+		t.Errorf("Retry-After = %q, want 30", retryAfter)
+	}
+	if policy := w.Header().Get("RateLimit-Policy"); policy != "2;w=60" {
+		t.Errorf("RateLimit-Policy = %q, want 2;w=60", policy)
+	}
+
+	// Other clients have buckets of their own
+	if w := getLimited(r, "192.0.2.2:1234", nil); w.Code != http.StatusNoContent {
+		t.Errorf("other client: status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+// TestRateLimitForwardedFor checks X-Forwarded-For only picks the bucket when a trusted proxy sent
+// it, as TRUSTED_PROXIES configures; otherwise a client could dodge the limit by varying it.
+func TestRateLimitForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		want           int
+	}{
+		{"no trusted proxies", nil, "10.0.0.1:1234", http.StatusTooManyRequests},
+		{"sent by a trusted proxy", []string{"10.0.0.0/8"}, "10.0.0.1:1234", http.StatusNoContent},
+		{"sent by an untrusted peer", []string{"10.0.0.0/8"}, "192.0.2.1:1234", http.StatusTooManyRequests},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rateLimitedRouter(ratelimit.NewMemoryStore(100), ratelimit.Limit{Requests: 1, Period: time.Minute}, ClientIPKey)
+			if err := r.SetTrustedProxies(tt.trustedProxies); err != nil {
+				t.Fatal(err)
+			}
+
+			if w := getLimited(r, tt.remoteAddr, map[string]string{"X-Forwarded-For": "203.0.113.1"}); w.Code != http.StatusNoContent {
+				t.Fatalf("first request: status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+			w := getLimited(r, tt.remoteAddr, map[string]string{"X-Forwarded-For": "203.0.113.2"})
+			if w.Code != tt.want {
+				t.Errorf("second request: status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitUserKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := rateLimitedRouter(ratelimit.NewMemoryStore(100), ratelimit.Limit{Requests: 1, Period: time.Minute}, UserKey(&fakeAuth{}))
+
+	alice := map[string]string{"Authorization": "Bearer " + testToken(t, "")}
+	bob := map[string]string{"Authorization": "Bearer " + testToken(t, "")}
+	if w := getLimited(r, "192.0.2.1:1234", alice); w.Code != http.StatusNoContent {
+		t.Fatalf("first request: status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := getLimited(r, "192.0.2.1:1234", alice); w.Code != http.StatusTooManyRequests {
+		t.Errorf("same user: status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if w := getLimited(r, "192.0.2.1:1234", bob); w.Code != http.StatusNoContent {
+		t.Errorf("other user: status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	// Requests without a valid token are left to the IP limit
+	for i := 0; i < 2; i++ {
+		if w := getLimited(r, "192.0.2.1:1234", map[string]string{"Authorization": "Bearer forged"}); w.Code != http.StatusNoContent {
+			t.Errorf("invalid token: status = %d, want %d", w.Code, http.StatusNoContent)
+		}
+	}
+}
+
+func TestRateLimitStoreUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := rateLimitedRouter(failingStore{}, ratelimit.Limit{Requests: 1, Period: time.Minute}, ClientIPKey)
+	for i := 0; i < 3; i++ {
+		if w := getLimited(r, "192.0.2.1:1234", nil); w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d, want %d", i+1, w.Code, http.StatusNoContent)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"strings"
+
+	"backend/internal/application/services"
+	domainerrors "backend/internal/domain/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// routeScopeKey holds the scope RequireScope guards the route with.
+const routeScopeKey = "route_scope"
+
+// RequireScope rejects requests whose bearer token was narrowed to scopes other than scope.
+// Unscoped tokens and requests without one pass; the route's authentication decides on them,
+// which for admin routes must come after it.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if ok && !services.TokenHasScope(token, scope) {
+			_ = c.Error(domainerrors.Forbidden("token lacks the %s scope", scope).WithCode("insufficient_scope"))
+			c.Abort()
+			return
+		}
+		c.Set(routeScopeKey, scope)
+		c.Next()
+	}
+}
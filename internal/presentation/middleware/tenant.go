@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"strings"
+
+	"backend/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tenant scopes the database statements of requests carrying a bearer token to the token's
+// domain, so that with row-level security a lookup by record ID can't reach another tenant's
+// rows. Admin routes rescope system admins to every tenant once they are authenticated.
+func Tenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			c.Request = c.Request.WithContext(services.WithTokenTenant(c.Request.Context(), token))
+		}
+		c.Next()
+	}
+}
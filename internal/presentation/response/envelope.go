@@ -0,0 +1,128 @@
+// Package response defines the envelope the v2 API answers with: {"data", "meta"} on success and
+// {"error": {"code", "message", "details"}} on failure.
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// Envelope wraps a successful response. Meta carries what describes the data rather than being
+// part of it, such as the pagination of listings.
+type Envelope struct {
+	Data interface{}            `json:"data"`
+	Meta map[string]interface{} `json:"meta,omitempty"`
+}
+
+// ErrorEnvelope wraps a failed response.
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    string `json:"code" example:"not_found"`
+	Message string `json:"message" example:"User not found"`
+	// Details carries what else the error reports, e.g. the dependents of a role in use
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// paginationFields are the fields of a listing moved to meta. A response is a listing when it has
+// links or total_pages.
+var paginationFields = []string{"total", "page", "limit", "total_pages", "next_cursor", "links"}
+
+// defaultCodes are the codes of error responses that don't name one, by status.
+var defaultCodes = map[int]string{
+	http.StatusBadRequest:            "validation_failed",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "not_found",
+	http.StatusConflict:              "conflict",
+	http.StatusGone:                  "gone",
+	http.StatusRequestEntityTooLarge: "payload_too_large",
+	http.StatusUnprocessableEntity:   "validation_failed",
+	http.StatusTooManyRequests:       "rate_limited",
+	http.StatusServiceUnavailable:    "unavailable",
+}
+
+// Success returns the envelope of data.
+func Success(data interface{}, meta map[string]interface{}) Envelope {
+	return Envelope{Data: data, Meta: meta}
+}
+
+// Failure returns the envelope of an error. An empty code is filled in from the status.
+func Failure(status int, code, message string, details map[string]interface{}) ErrorEnvelope {
+	if code == "" {
+		code = DefaultCode(status)
+	}
+	return ErrorEnvelope{Error: ErrorBody{Code: code, Message: message, Details: details}}
+}
+
+// DefaultCode is the code of an error response with status that doesn't name one.
+func DefaultCode(status int) string {
+	if code, ok := defaultCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return "internal_error"
+	}
+	return "error"
+}
+
+// Wrap puts a v1 JSON response into its envelope. v1 errors are {"error", "code"} objects, whose
+// other fields become the details; the pagination fields of v1 listings become the meta. A body
+// that isn't JSON is returned as it is.
+func Wrap(status int, body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+	fields, isObject := value.(map[string]interface{})
+
+	var wrapped interface{}
+	if status >= http.StatusBadRequest {
+		wrapped = wrapError(status, fields)
+	} else {
+		envelope := Success(value, nil)
+		if isObject && (fields["links"] != nil || fields["total_pages"] != nil) {
+			envelope.Meta = make(map[string]interface{})
+			for _, field := range paginationFields {
+				if fieldValue, ok := fields[field]; ok {
+					envelope.Meta[field] = fieldValue
+					delete(fields, field)
+				}
+			}
+		}
+		wrapped = envelope
+	}
+
+	out, err := json.Marshal(wrapped)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func wrapError(status int, fields map[string]interface{}) ErrorEnvelope {
+	message, _ := fields["error"].(string)
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	code, _ := fields["code"].(string)
+	var details map[string]interface{}
+	for key, value := range fields {
+		if key == "error" || key == "code" {
+			continue
+		}
+		if details == nil {
+			details = make(map[string]interface{})
+		}
+		details[key] = value
+	}
+	return Failure(status, code, message, details)
+}
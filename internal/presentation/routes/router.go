@@ -1,50 +1,209 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
+	"log"
 
 	"backend/internal/application/services"
+	"backend/internal/infrastructure/broker"
+	"backend/internal/infrastructure/cache"
+	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/jobs"
+	"backend/internal/infrastructure/mailer"
+	"backend/internal/infrastructure/netguard"
+	"backend/internal/infrastructure/ratelimit"
 	"backend/internal/infrastructure/repositories"
+	"backend/internal/infrastructure/signing"
+	"backend/internal/infrastructure/storage"
+	"backend/internal/infrastructure/telemetry"
+	"backend/internal/infrastructure/tenancy"
+	"backend/internal/presentation/graph"
 	"backend/internal/presentation/handlers"
+	"backend/internal/presentation/middleware"
 
 	_ "backend/docs"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func SetupRouter(db *sql.DB) *gin.Engine {
+// SetupRouter wires the application and starts its background jobs, which stop when ctx is cancelled.
+func SetupRouter(ctx context.Context, cfg *config.AppConfig, db *sql.DB, shards, replicas map[string]*sql.DB, rateLimitStore ratelimit.Store, lookupCache cache.Cache, revokedTokens repositories.RevokedTokenRepository, publisher broker.Publisher, telemetrySink telemetry.Sink, objectStore storage.ObjectStorage, keys *signing.KeySet, jobQueue *jobs.Queue, platformMailer mailer.Mailer) *gin.Engine {
 	// Initialize repositories
-	domainRepo := repositories.NewDomainRepository(db)
-	roleRepo := repositories.NewRoleRepository(db)
-	userRepo := repositories.NewUserRepository(db)
+	shardRouter := repositories.NewShardRouter(db, shards, replicas)
+	domainRepo := repositories.NewDomainRepository(shardRouter)
+	domainAliasRepo := repositories.NewDomainAliasRepository(db)
+	roleRepo := repositories.NewRoleRepository(shardRouter)
+	roleTemplateRepo := repositories.NewRoleTemplateRepository(db)
+	userRepo := repositories.NewUserRepository(shardRouter)
+	permissionRepo := repositories.NewPermissionRepository(shardRouter)
+	decisionRepo := repositories.NewAuthzDecisionRepository(shardRouter)
+	groupRepo := repositories.NewGroupRepository(shardRouter)
+	orgUnitRepo := repositories.NewOrgUnitRepository(shardRouter)
+	policyRepo := repositories.NewPolicyRepository(shardRouter)
+	loginCodeRepo := repositories.NewLoginCodeRepository(shardRouter)
+	apiKeyRepo := repositories.NewAPIKeyRepository(db)
+	riskPolicyRepo := repositories.NewLoginRiskPolicyRepository(db)
+	eventRepo := repositories.NewEventRepository(shardRouter, publisher != nil)
+	mailSettingsRepo := repositories.NewDomainMailSettingsRepository(db, cfg.Mail.Credentials)
+	emailBrandingRepo := repositories.NewDomainEmailBrandingRepository(db)
+	passwordHistoryRepo := repositories.NewPasswordHistoryRepository(shardRouter)
+	integrationHealthRepo := repositories.NewIntegrationHealthRepository(db)
+	profileConsentRepo := repositories.NewProfileConsentRepository(shardRouter)
+	trustedDeviceRepo := repositories.NewTrustedDeviceRepository(shardRouter)
+	loginHistoryRepo := repositories.NewLoginHistoryRepository(shardRouter)
+	registrationCodeRepo := repositories.NewRegistrationCodeRepository(shardRouter)
+	invitationRepo := repositories.NewInvitationRepository(shardRouter)
+	schemaRepo := repositories.NewSchemaRepository(shardRouter)
+	domainJobRepo := repositories.NewDomainJobRepository(db)
+	domainDeletionRepo := repositories.NewDomainDeletionRepository(shardRouter)
+	jobRepo := repositories.NewJobRepository(db)
+	healthRepo := repositories.NewHealthRepository(shardRouter)
+	webhookRepo := repositories.NewWebhookRepository(shardRouter)
+	eventOutboxRepo := repositories.NewEventOutboxRepository(shardRouter)
+	telemetryCursorRepo := repositories.NewTelemetryExportCursorRepository(shardRouter)
+	roleRevisionRepo := repositories.NewRoleRevisionRepository(shardRouter)
+	idempotencyKeyRepo := repositories.NewIdempotencyKeyRepository(db)
+	txManager := repositories.NewTxManager(shardRouter)
+	if lookupCache != nil {
+		domainRepo = repositories.NewCachedDomainRepository(domainRepo, lookupCache, cfg.Cache.TTL)
+		roleRepo = repositories.NewCachedRoleRepository(roleRepo, lookupCache, cfg.Cache.TTL)
+	}
+
+	// Connections made on behalf of domains mustn't reach the private network
+	outboundGuard := netguard.New(cfg.Outbound.AllowedNetworks)
 
 	// Initialize services
-	domainService := services.NewDomainService(domainRepo)
-	roleService := services.NewRoleService(roleRepo)
-	userService := services.NewUserService(userRepo)
-	authService := services.NewAuthService(userRepo, roleRepo, domainRepo, "your-secret-key") // TODO: Use environment variable for secret
+	mailSettingsService := services.NewMailSettingsService(mailSettingsRepo, domainRepo, platformMailer, outboundGuard)
+	eventService := services.NewEventService(eventRepo, domainRepo)
+	integrationService := services.NewIntegrationHealthService(integrationHealthRepo, domainRepo, mailSettingsRepo, webhookRepo, eventService, platformMailer, outboundGuard, cfg.IntegrationHealth)
+	domainService := services.NewDomainService(domainRepo, domainAliasRepo, roleRepo)
+	// Notifications no request waits on are queued, so those that fail to send are retried
+	queuedMailer := services.NewQueuedMailer(jobQueue, mailSettingsService)
+	emailService := services.NewEmailService(emailBrandingRepo, domainRepo, mailSettingsService)
+	queuedEmails := services.NewEmailService(emailBrandingRepo, domainRepo, queuedMailer)
+	roleService := services.NewRoleService(roleRepo, domainRepo, userRepo, permissionRepo, groupRepo, roleRevisionRepo, eventService, queuedMailer, txManager)
+	userService := services.NewUserService(userRepo, roleRepo, domainRepo, passwordHistoryRepo, eventService, queuedEmails, txManager)
+	permissionService := services.NewPermissionService(permissionRepo, roleRepo, domainRepo)
+	groupService := services.NewGroupService(groupRepo, userRepo, roleRepo, domainRepo)
+	policyService := services.NewPolicyService(policyRepo, userRepo, roleRepo, domainRepo, permissionRepo, groupRepo)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepo, domainRepo, cfg.APIKeyRateLimits)
+	loginRiskService := services.NewLoginRiskService(riskPolicyRepo, domainRepo, cfg.LoginRisk, cfg.Captcha)
+	trustedDeviceService := services.NewTrustedDeviceService(trustedDeviceRepo, userRepo, cfg.TrustedDevices)
+	loginHistoryService := services.NewLoginHistoryService(loginHistoryRepo, userRepo)
+	authService := services.NewAuthService(userRepo, roleRepo, domainRepo, permissionRepo, groupRepo, loginCodeRepo, passwordHistoryRepo, revokedTokens, loginRiskService, eventService, mailSettingsService, emailService, trustedDeviceService, loginHistoryService, cfg.Passwordless, cfg.BreakGlass, cfg.Impersonation, cfg.HostedSession, keys)
+	introspectionService := services.NewTokenIntrospectionService(authService, lookupCache, cfg.Introspection)
+	registrationService := services.NewRegistrationService(registrationCodeRepo, domainRepo, roleRepo, userService)
+	invitationService := services.NewInvitationService(invitationRepo, domainRepo, roleRepo, userRepo, userService, emailService, cfg.Invitations)
+	snapshotService := services.NewConfigSnapshotService(schemaRepo, authService, cfg)
+	faultService := services.NewFaultInjectionService(cfg.FaultInjection)
+	consentService := services.NewConsentService(profileConsentRepo, userRepo, apiKeyRepo, authService)
+	hostedLoginService := services.NewHostedLoginService(domainRepo, apiKeyRepo, consentService)
+	accountDeletionService := services.NewAccountDeletionService(userRepo, domainRepo, userService, eventService, queuedMailer, cfg.AccountDeletion)
+	dataMaskingService := services.NewDataMaskingService(domainRepo, authService, eventService)
+	healthService := services.NewHealthService(healthRepo, lookupCache, revokedTokens, cfg.Health)
+	domainJobService := services.NewDomainJobService(domainJobRepo, domainRepo, userRepo, mailSettingsService)
+	webhookService := services.NewWebhookService(webhookRepo, domainRepo, cfg.Webhooks, outboundGuard)
+	eventRelayService := services.NewEventRelayService(eventOutboxRepo, publisher, cfg.Broker)
+	telemetryService := services.NewTelemetryExportService(domainRepo, eventRepo, telemetryCursorRepo, telemetrySink, cfg.Telemetry)
+	authzService := services.NewAuthzService(userRepo, roleRepo, domainRepo, permissionRepo, groupRepo, policyRepo, decisionRepo, cfg.DecisionLog)
+	roleTemplateService := services.NewRoleTemplateService(roleTemplateRepo, roleRepo, permissionRepo, domainRepo, eventService, txManager)
+	onboardingService := services.NewDomainOnboardingService(domainService, roleRepo, userRepo, eventService, mailSettingsService, txManager)
+	domainDeletionService := services.NewDomainDeletionService(domainDeletionRepo, domainRepo, cfg.DomainDeletion)
+	jobService := services.NewJobService(jobRepo, jobQueue)
+	idempotencyService := services.NewIdempotencyService(idempotencyKeyRepo, cfg.Idempotency)
+	adminAuthService := services.NewAdminAuthorizationService(authService, userRepo, roleRepo, groupRepo, policyRepo, permissionRepo, apiKeyRepo, orgUnitRepo, cfg.AdminAuth.SystemDomainID)
 
 	// Initialize handlers
-	domainHandler := handlers.NewDomainHandler(domainService)
-	roleHandler := handlers.NewRoleHandler(roleService)
-	userHandler := handlers.NewUserHandler(userService)
-	authHandler := handlers.NewAuthHandler(authService)
+	domainHandler := handlers.NewDomainHandler(domainService, onboardingService, domainDeletionService)
+	roleHandler := handlers.NewRoleHandler(roleService, dataMaskingService)
+	roleTemplateHandler := handlers.NewRoleTemplateHandler(roleTemplateService)
+	userHandler := handlers.NewUserHandler(userService, dataMaskingService)
+	permissionHandler := handlers.NewPermissionHandler(permissionService)
+	groupHandler := handlers.NewGroupHandler(groupService, dataMaskingService)
+	orgUnitHandler := handlers.NewOrgUnitHandler(services.NewOrgUnitService(orgUnitRepo, userRepo, roleRepo, domainRepo, eventService), dataMaskingService)
+	policyHandler := handlers.NewPolicyHandler(policyService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+	loginRiskHandler := handlers.NewLoginRiskHandler(loginRiskService)
+	authHandler := handlers.NewAuthHandler(authService, introspectionService, cfg.TrustedDevices)
+	authzHandler := handlers.NewAuthzHandler(authzService)
+	consentHandler := handlers.NewConsentHandler(consentService, authService)
+	trustedDeviceHandler := handlers.NewTrustedDeviceHandler(trustedDeviceService, authService)
+	loginHistoryHandler := handlers.NewLoginHistoryHandler(loginHistoryService)
+	impersonationHandler := handlers.NewImpersonationHandler(authService)
+	serviceAccountHandler := handlers.NewServiceAccountHandler(userService, authService)
+	avatarHandler := handlers.NewAvatarHandler(services.NewAvatarService(userRepo, objectStore, eventService, cfg.Avatars), dataMaskingService)
+	accountDeletionHandler := handlers.NewAccountDeletionHandler(accountDeletionService, authService)
+	loginPageHandler := handlers.NewLoginPageHandler(authService, hostedLoginService, cfg.HostedSession)
+	registrationHandler := handlers.NewRegistrationHandler(registrationService, authService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService, authService)
+	eventHandler := handlers.NewEventHandler(eventService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	telemetryHandler := handlers.NewTelemetryHandler(telemetryService)
+	mailSettingsHandler := handlers.NewMailSettingsHandler(mailSettingsService)
+	emailBrandingHandler := handlers.NewEmailBrandingHandler(emailService)
+	integrationHandler := handlers.NewIntegrationHandler(integrationService)
+	breakGlassHandler := handlers.NewBreakGlassHandler(userService)
+	jobHandler := handlers.NewJobHandler(jobService)
+	domainJobHandler := handlers.NewDomainJobHandler(domainJobService)
+	healthHandler := handlers.NewHealthHandler(healthService)
+	graphQLHandler := handlers.NewGraphQLHandler(&graph.Resolver{DomainService: domainService, RoleService: roleService, UserService: userService, GroupService: groupService}, dataMaskingService)
+	adminHandler := handlers.NewAdminHandler(snapshotService, faultService)
+
+	// Background jobs, which work on every tenant's data
+	ctx = tenancy.AsSystem(ctx)
+	domainJobService.FailInterruptedJobs(ctx)
+	if interval := cfg.UserExpiry.SweepInterval; interval > 0 {
+		go userService.RunExpirySweep(ctx, interval)
+	}
+	if interval := cfg.AccountDeletion.SweepInterval; interval > 0 {
+		go accountDeletionService.RunDeletionSweep(ctx, interval)
+	}
+	if interval := cfg.DomainDeletion.WorkerInterval; interval > 0 {
+		go domainDeletionService.RunWorker(ctx, interval)
+	}
+	if interval := cfg.IntegrationHealth.CheckInterval; interval > 0 {
+		go integrationService.RunHealthChecks(ctx, interval)
+	}
+	if interval := cfg.TokenRevocation.SweepInterval; interval > 0 && cfg.TokenRevocation.Store == "db" {
+		go authService.RunRevocationSweep(ctx, interval)
+	}
+	if interval := cfg.Idempotency.SweepInterval; interval > 0 {
+		go idempotencyService.RunSweep(ctx, interval)
+	}
+	if interval := cfg.Health.ProbeInterval; interval > 0 {
+		go healthService.RunHealthProbes(ctx, interval)
+	}
+	if interval := cfg.Webhooks.DeliveryInterval; interval > 0 {
+		go webhookService.RunDeliveries(ctx, interval)
+	}
+	if interval := cfg.Broker.RelayInterval; interval > 0 && publisher != nil {
+		go eventRelayService.RunRelay(ctx, interval)
+	}
+	if interval := cfg.Telemetry.ExportInterval; interval > 0 && telemetrySink != nil {
+		go telemetryService.RunExports(ctx, interval)
+	}
 
 	// Setup Gin router
 	r := gin.Default()
+	// The client IP keys the per-IP rate limits and login risk, so X-Forwarded-For only counts
+	// when a trusted proxy sent it; otherwise any client could pick its own IP
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	r.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	r.Use(middleware.Metrics())
+	r.Use(middleware.ErrorHandler())
+	r.Use(middleware.Degradation(healthService))
 
-	// CORS middleware - allow all origins, support credentials
-	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "accept", "origin", "Cache-Control", "X-Requested-With", "X-NRM-DID", "X-Nrm-Did"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: false,     // Credentials cannot be used with AllowOrigins: ["*"]
-		MaxAge:           12 * 3600, // 12 hours
-	}))
+	// Before the routes, so preflight requests are answered for every route
+	r.Use(middleware.CORS(cfg.CORS, domainService, lookupCache, cfg.Cache.TTL))
+	// User imports and avatar uploads carry files, under every version the API is mounted at
+	r.Use(middleware.BodyLimit(int64(cfg.Server.MaxBodyBytes), int64(cfg.Server.MaxImportBodyBytes), "/users/import", "/users/:id/avatar"))
 
 	// Ping endpoint
 	r.GET("/ping", func(c *gin.Context) {
@@ -53,43 +212,104 @@ func SetupRouter(db *sql.DB) *gin.Engine {
 		})
 	})
 
-	// Handle OPTIONS requests for all routes
-	r.OPTIONS("/*any", func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "http://localhost:3000")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-NRM-DID")
-		c.Header("Access-Control-Max-Age", "86400") // Cache preflight for 24 hours
-		c.Status(200)
-	})
+	// Readiness endpoint, reporting degraded features
+	r.GET("/readyz", healthHandler.Readiness)
+
+	// Prometheus metrics endpoint
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Per-key rate limiting for requests authenticated with X-API-Key; applies to routes registered below
+	r.Use(middleware.APIKeyRateLimit(apiKeyService))
+
+	// Requests with a bearer token only reach its domain's rows under row-level security
+	r.Use(middleware.Tenant())
+	// Events caused with an impersonation token name the impersonating support engineer
+	r.Use(middleware.Impersonation(authService))
+
+	if cfg.FaultInjection.Enabled {
+		log.Println("Warning: fault injection is enabled; do not use this instance in production")
+	}
+	if !cfg.AdminAuth.Enforced {
+		log.Println("Warning: admin authorization is off; remove ADMIN_AUTHORIZATION=false to require domain or system admin tokens")
+	}
+	operatorToken := cfg.Operator.Token
+	v1 := &v1Routes{
+		accountDeletion: accountDeletionHandler,
+		admin:           adminHandler,
+		apiKey:          apiKeyHandler,
+		auth:            authHandler,
+		authz:           authzHandler,
+		breakGlass:      breakGlassHandler,
+		consent:         consentHandler,
+		domain:          domainHandler,
+		domainJob:       domainJobHandler,
+		emailBranding:   emailBrandingHandler,
+		event:           eventHandler,
+		graphQL:         graphQLHandler,
+		group:           groupHandler,
+		integration:     integrationHandler,
+		invitation:      invitationHandler,
+		job:             jobHandler,
+		loginRisk:       loginRiskHandler,
+		mailSettings:    mailSettingsHandler,
+		orgUnit:         orgUnitHandler,
+		permission:      permissionHandler,
+		policy:          policyHandler,
+		registration:    registrationHandler,
+		role:            roleHandler,
+		roleTemplate:    roleTemplateHandler,
+		trustedDevice:   trustedDeviceHandler,
+		loginHistory:    loginHistoryHandler,
+		impersonation:   impersonationHandler,
+		serviceAccount:  serviceAccountHandler,
+		avatar:          avatarHandler,
+		telemetry:       telemetryHandler,
+		user:            userHandler,
+		webhook:         webhookHandler,
+
+		introspectionLimit: middleware.RateLimit(rateLimitStore, "introspection", cfg.RequestRateLimits.Introspection, middleware.ClientKey),
+		ipLimit:            middleware.RateLimit(rateLimitStore, "ip", cfg.RequestRateLimits.PerIP, middleware.ClientIPKey),
+		userLimit:          middleware.RateLimit(rateLimitStore, "user", cfg.RequestRateLimits.PerUser, middleware.UserKey(authService)),
+		loginLimit:         middleware.RateLimit(rateLimitStore, "login", cfg.RequestRateLimits.Login, middleware.ClientIPKey),
+		emailSendLimit:     middleware.RateLimit(rateLimitStore, "email", cfg.RequestRateLimits.EmailSend, middleware.ClientIPKey),
+		readConsistency:    middleware.ReadConsistency(shardRouter),
+		idempotent:         middleware.Idempotency(idempotencyService),
+		requireOperator:    middleware.RequireOperator(operatorToken),
+		faultInjection:     cfg.FaultInjection.Enabled,
+
+		adminAuth: middleware.NewAdminAuth(adminAuthService, operatorToken, cfg.AdminAuth.Enforced),
+	}
+	v1.register(r.Group("/api/v1"))
+	// The same API with every JSON response in the {"data", "meta"} / {"error"} envelope
+	v1.register(r.Group("/api/v2", middleware.Envelope()))
+
+	// Unversioned routes: discovery documents and the hosted login page, whose URLs are fixed by
+	// standards and by the links and cookies browsers already hold
+	web := r.Group("", v1.ipLimit, v1.userLimit, v1.readConsistency)
+
+	web.GET("/.well-known/iam-capabilities", authHandler.GetCapabilities)
+	web.GET("/.well-known/jwks.json", authHandler.GetJWKS)
+
+	// Hosted login page
+	web.GET("/login", loginPageHandler.ShowLoginPage)
+	web.POST("/login", v1.loginLimit, func(c *gin.Context) {
+		// The email step of passwordless domains sends a code, like /auth/passwordless/start
+		if c.PostForm("step") == "email" {
+			v1.emailSendLimit(c)
+		}
+	}, loginPageHandler.SubmitLoginPage)
+	web.GET("/auth/session/refresh", loginPageHandler.RefreshSession)
+	web.POST("/auth/session/logout", loginPageHandler.EndSession)
+
+	// Uploaded files such as avatars, when they are kept on local disk
+	if cfg.Storage.Driver == "local" {
+		web.Static(config.LocalStoragePath, cfg.Storage.LocalDir)
+	}
 
-	// Role routes (must come before domain routes to avoid path conflicts)
-	r.GET("/roles", roleHandler.ListRoles)
-	r.GET("/roles/:id", roleHandler.GetRole)
-	r.GET("/domains/:domainId/roles", roleHandler.GetRolesByDomain)
-	r.POST("/domains/:domainId/roles", roleHandler.CreateRole)
-	r.PUT("/roles/:id", roleHandler.UpdateRole)
-	r.DELETE("/roles/:id", roleHandler.DeleteRole)
-
-	// User routes
-	r.GET("/users", userHandler.ListUsers)
-	r.GET("/users/:id", userHandler.GetUser)
-	r.POST("/users/:id/reset-password", userHandler.ResetUserPassword)
-	r.GET("/domains/:domainId/users", userHandler.GetUsersByDomain)
-	r.POST("/users", userHandler.CreateUser)
-	r.PUT("/users/:id", userHandler.UpdateUser)
-	r.DELETE("/users/:id", userHandler.DeleteUser)
-
-	// Auth routes
-	r.POST("/auth/login", authHandler.Login)
-	r.POST("/auth/validate", authHandler.ValidateToken)
-	r.GET("/auth/profile", authHandler.GetProfile)
-
-	// Domain routes
-	r.GET("/domains", domainHandler.ListDomains)
-	r.GET("/domains/:domainId", domainHandler.GetDomain)
-	r.POST("/domains", domainHandler.CreateDomain)
-	r.PUT("/domains/:domainId", domainHandler.UpdateDomain)
-	r.DELETE("/domains/:domainId", domainHandler.DeleteDomain)
+	// The v1 API at the paths it was served at before /api/v1, until their sunset
+	if cfg.API.LegacyRoutes {
+		v1.register(r.Group("", middleware.Deprecated(cfg.API.LegacyDeprecatedAt, cfg.API.LegacySunset, "/api/v1")))
+	}
 
 	// Swagger endpoint
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -0,0 +1,312 @@
+package routes
+
+import (
+	"backend/internal/application/services"
+	"backend/internal/domain/entities"
+	"backend/internal/presentation/handlers"
+	"backend/internal/presentation/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// v1Routes is what the v1 API is served with. It is mounted at /api/v1 and, until their sunset, at
+// the unversioned paths it was served at before. /api/v2 serves the same routes in the response
+// envelope; a version with other routes gets its own routes type and is mounted next to it.
+type v1Routes struct {
+	accountDeletion *handlers.AccountDeletionHandler
+	admin           *handlers.AdminHandler
+	apiKey          *handlers.APIKeyHandler
+	auth            *handlers.AuthHandler
+	authz           *handlers.AuthzHandler
+	avatar          *handlers.AvatarHandler
+	breakGlass      *handlers.BreakGlassHandler
+	consent         *handlers.ConsentHandler
+	domain          *handlers.DomainHandler
+	domainJob       *handlers.DomainJobHandler
+	emailBranding   *handlers.EmailBrandingHandler
+	event           *handlers.EventHandler
+	graphQL         *handlers.GraphQLHandler
+	group           *handlers.GroupHandler
+	integration     *handlers.IntegrationHandler
+	invitation      *handlers.InvitationHandler
+	job             *handlers.JobHandler
+	loginRisk       *handlers.LoginRiskHandler
+	mailSettings    *handlers.MailSettingsHandler
+	orgUnit         *handlers.OrgUnitHandler
+	permission      *handlers.PermissionHandler
+	policy          *handlers.PolicyHandler
+	registration    *handlers.RegistrationHandler
+	role            *handlers.RoleHandler
+	roleTemplate    *handlers.RoleTemplateHandler
+	telemetry       *handlers.TelemetryHandler
+	trustedDevice   *handlers.TrustedDeviceHandler
+	loginHistory    *handlers.LoginHistoryHandler
+	impersonation   *handlers.ImpersonationHandler
+	serviceAccount  *handlers.ServiceAccountHandler
+	user            *handlers.UserHandler
+	webhook         *handlers.WebhookHandler
+
+	// introspectionLimit applies to /auth/validate in place of ipLimit and userLimit
+	introspectionLimit gin.HandlerFunc
+	ipLimit            gin.HandlerFunc
+	userLimit          gin.HandlerFunc
+	loginLimit         gin.HandlerFunc
+	emailSendLimit     gin.HandlerFunc
+	readConsistency    gin.HandlerFunc
+	// idempotent replays the stored response of create requests retried with an Idempotency-Key
+	idempotent      gin.HandlerFunc
+	requireOperator gin.HandlerFunc
+	faultInjection  bool
+
+	adminAuth *middleware.AdminAuth
+}
+
+// register mounts the v1 API on api.
+func (v *v1Routes) register(api *gin.RouterGroup) {
+	// Token introspection, which gateways call for every request they forward, is limited per client
+	// instead of by the per-IP and per-user buckets
+	api.POST("/auth/validate", v.introspectionLimit, v.auth.ValidateToken)
+	// Per-IP and per-user token buckets, with stricter per-IP limits on sensitive endpoints below
+	api.Use(v.ipLimit, v.userLimit)
+	// Read-after-write consistency tokens when read replicas are configured
+	api.Use(v.readConsistency)
+
+	// Admin routes resolve their caller with requireAdmin. When admin authorization is enforced, the
+	// scopes after it keep domain admins to their own domain, found in the request or from the
+	// resource addressed, and the routes managing domains themselves to system admins
+	requireAdmin := v.adminAuth.Authenticate()
+	systemAdmin := v.adminAuth.SystemAdmin()
+	domainParam := v.adminAuth.DomainParam("domainId")
+	domainQuery := v.adminAuth.DomainQuery("domainId")
+	// Org unit admins may also list their domain's users and units, narrowed to their unit's subtree
+	delegatedParam := v.adminAuth.DelegatedDomainParam("domainId")
+	delegatedQuery := v.adminAuth.DelegatedDomainQuery("domainId")
+	domainForm := v.adminAuth.DomainForm("domain_id")
+	domainBody := v.adminAuth.DomainJSON("domain_id")
+	user := v.adminAuth.Resource(services.AdminResourceUser, "id")
+	role := v.adminAuth.Resource(services.AdminResourceRole, "id")
+	group := v.adminAuth.Resource(services.AdminResourceGroup, "id")
+	policy := v.adminAuth.Resource(services.AdminResourcePolicy, "id")
+	permission := v.adminAuth.Resource(services.AdminResourcePermission, "id")
+	apiKey := v.adminAuth.Resource(services.AdminResourceAPIKey, "id")
+	orgUnit := v.adminAuth.Resource(services.AdminResourceOrgUnit, "id")
+	simulatedRole := v.adminAuth.ResourceJSON(services.AdminResourceRole, "role_id")
+	// Decision endpoints answer for the user in their body, whose domain the caller must manage
+	checkedUser := v.adminAuth.ResourceJSON(services.AdminResourceUser, "user_id")
+	// /auth/simulate names either a user or a role
+	accessUser := v.adminAuth.OptionalResourceJSON(services.AdminResourceUser, "user_id")
+	accessRole := v.adminAuth.OptionalResourceJSON(services.AdminResourceRole, "role_id")
+	// Tokens narrowed with a scope at login only reach the routes their scopes name. They go before
+	// requireAdmin, which lets a narrowed token act as admin on a route whose scope it holds
+	usersRead := middleware.RequireScope(entities.ScopeUsersRead)
+	usersWrite := middleware.RequireScope(entities.ScopeUsersWrite)
+	// Support engineers acting as a user can't change the user's own account, sessions or consents
+	notImpersonated := middleware.RejectImpersonation()
+	// Responses whose format a standard fixes keep it in the enveloped v2 API
+	raw := middleware.RawResponse()
+
+	// GraphQL for the admin console, behind the same middleware as the REST routes
+	api.GET("/graphql", raw, requireAdmin, systemAdmin, v.graphQL.Query)
+	api.POST("/graphql", raw, requireAdmin, systemAdmin, v.graphQL.Query)
+
+	// Role routes (must come before domain routes to avoid path conflicts)
+	api.GET("/roles", requireAdmin, domainQuery, v.role.ListRoles)
+	api.GET("/roles/export", requireAdmin, domainQuery, v.role.ExportRoles)
+	api.POST("/roles/batch-get", requireAdmin, v.role.BatchGetRoles)
+	api.GET("/roles/:id", requireAdmin, role, v.role.GetRole)
+	api.GET("/roles/:id/users", usersRead, requireAdmin, role, v.role.ListRoleMembers)
+	api.GET("/domains/:domainId/roles", requireAdmin, domainParam, v.role.GetRolesByDomain)
+	api.POST("/domains/:domainId/roles", requireAdmin, domainParam, v.role.CreateRole)
+	api.PUT("/roles/:id", requireAdmin, role, v.role.UpdateRole)
+	api.GET("/roles/:id/revisions", requireAdmin, role, v.role.ListRoleRevisions)
+	api.POST("/roles/:id/revisions/:rev/rollback", requireAdmin, role, v.role.RollbackRole)
+	api.DELETE("/roles/:id", requireAdmin, role, v.role.DeleteRole)
+	api.POST("/roles/:id/clone", requireAdmin, role, v.adminAuth.DomainQuery("targetDomainId"), v.roleTemplate.CloneRole)
+	api.POST("/domains/:domainId/roles/from-template/:templateId", requireAdmin, domainParam, v.roleTemplate.CreateRoleFromTemplate)
+
+	// Role template routes; the catalog is shared by every domain, so only system admins change it
+	api.GET("/role-templates", requireAdmin, v.roleTemplate.ListRoleTemplates)
+	api.GET("/role-templates/:id", requireAdmin, v.roleTemplate.GetRoleTemplate)
+	api.POST("/role-templates", requireAdmin, systemAdmin, v.roleTemplate.CreateRoleTemplate)
+	api.PUT("/role-templates/:id", requireAdmin, systemAdmin, v.roleTemplate.UpdateRoleTemplate)
+	api.DELETE("/role-templates/:id", requireAdmin, systemAdmin, v.roleTemplate.DeleteRoleTemplate)
+
+	// Permission routes
+	api.GET("/domains/:domainId/permissions", requireAdmin, domainParam, v.permission.ListPermissions)
+	api.POST("/domains/:domainId/permissions", requireAdmin, domainParam, v.permission.CreatePermission)
+	api.DELETE("/permissions/:id", requireAdmin, permission, v.permission.DeletePermission)
+	api.GET("/roles/:id/permissions", requireAdmin, role, v.permission.ListRolePermissions)
+	api.POST("/roles/:id/permissions", requireAdmin, role, v.permission.AssignRolePermission)
+	api.DELETE("/roles/:id/permissions/:permissionId", requireAdmin, role, v.permission.RevokeRolePermission)
+
+	// User routes
+	api.GET("/users", usersRead, requireAdmin, delegatedQuery, v.user.ListUsers)
+	api.GET("/users/export", usersRead, requireAdmin, domainQuery, v.user.ExportUsers)
+	api.POST("/users/batch-get", usersRead, requireAdmin, v.user.BatchGetUsers)
+	api.GET("/users/:id", usersRead, requireAdmin, user, v.user.GetUser)
+	api.GET("/users/by-external-id/:id", usersRead, requireAdmin, domainQuery, v.user.GetUserByExternalID)
+	api.PUT("/users/by-external-id/:id", usersWrite, requireAdmin, domainQuery, v.user.UpdateUserByExternalID)
+	api.POST("/users/:id/reset-password", usersWrite, requireAdmin, user, v.user.ResetUserPassword)
+	api.PUT("/users/:id/valid-until", usersWrite, requireAdmin, user, v.user.SetUserValidUntil)
+	api.POST("/users/:id/avatar", usersWrite, requireAdmin, user, v.avatar.UploadAvatar)
+	api.PUT("/users/:id/org-unit", usersWrite, requireAdmin, user, v.orgUnit.SetUserOrgUnit)
+	api.GET("/users/:id/login-history", usersRead, requireAdmin, user, v.loginHistory.GetLoginHistory)
+	api.POST("/users/:id/impersonate", v.impersonation.Impersonate)
+	api.GET("/domains/:domainId/users", usersRead, requireAdmin, domainParam, v.user.GetUsersByDomain)
+	api.GET("/domains/:domainId/users/expiring", usersRead, requireAdmin, domainParam, v.user.ListExpiringUsers)
+	api.PUT("/domains/:domainId/users/by-external-id/:id", usersWrite, requireAdmin, domainParam, v.user.UpsertUserByExternalID)
+	api.POST("/domains/:domainId/service-accounts", usersWrite, requireAdmin, domainParam, v.serviceAccount.CreateServiceAccount)
+	api.POST("/users", usersWrite, requireAdmin, domainBody, v.idempotent, v.user.CreateUser)
+	api.POST("/users/import", usersWrite, requireAdmin, domainForm, v.user.ImportUsers)
+	api.PUT("/users/:id", usersWrite, requireAdmin, user, v.user.UpdateUser)
+	api.DELETE("/users/:id", usersWrite, requireAdmin, user, v.user.DeleteUser)
+
+	// Org unit routes
+	api.GET("/domains/:domainId/org-units", requireAdmin, delegatedParam, v.orgUnit.ListOrgUnits)
+	api.POST("/domains/:domainId/org-units", requireAdmin, domainParam, v.orgUnit.CreateOrgUnit)
+	api.GET("/org-units/:id", requireAdmin, orgUnit, v.orgUnit.GetOrgUnit)
+	api.PUT("/org-units/:id", requireAdmin, orgUnit, v.orgUnit.UpdateOrgUnit)
+	api.DELETE("/org-units/:id", requireAdmin, orgUnit, v.orgUnit.DeleteOrgUnit)
+	api.POST("/org-units/:id/role-assignments", usersWrite, requireAdmin, orgUnit, v.orgUnit.AssignOrgUnitRole)
+
+	// Group routes
+	api.GET("/domains/:domainId/groups", requireAdmin, domainParam, v.group.ListGroups)
+	api.POST("/domains/:domainId/groups", requireAdmin, domainParam, v.group.CreateGroup)
+	api.GET("/groups/:id", requireAdmin, group, v.group.GetGroup)
+	api.PUT("/groups/:id", requireAdmin, group, v.group.UpdateGroup)
+	api.DELETE("/groups/:id", requireAdmin, group, v.group.DeleteGroup)
+	api.GET("/groups/:id/members", requireAdmin, group, v.group.ListGroupMembers)
+	api.POST("/groups/:id/members", requireAdmin, group, v.group.AddGroupMember)
+	api.DELETE("/groups/:id/members/:userId", requireAdmin, group, v.group.RemoveGroupMember)
+	api.GET("/groups/:id/roles", requireAdmin, group, v.group.ListGroupRoles)
+	api.POST("/groups/:id/roles", requireAdmin, group, v.group.AddGroupRole)
+	api.DELETE("/groups/:id/roles/:roleId", requireAdmin, group, v.group.RemoveGroupRole)
+
+	// Policy routes
+	api.GET("/domains/:domainId/policies", requireAdmin, domainParam, v.policy.ListPolicies)
+	api.POST("/domains/:domainId/policies", requireAdmin, domainParam, v.policy.CreatePolicy)
+	api.GET("/policies/:id", requireAdmin, policy, v.policy.GetPolicy)
+	api.PUT("/policies/:id", requireAdmin, policy, v.policy.UpdatePolicy)
+	api.DELETE("/policies/:id", requireAdmin, policy, v.policy.DeletePolicy)
+
+	// API key routes
+	api.GET("/domains/:domainId/api-keys", requireAdmin, domainParam, v.apiKey.ListAPIKeys)
+	api.POST("/domains/:domainId/api-keys", requireAdmin, domainParam, v.apiKey.CreateAPIKey)
+	api.GET("/api-keys/:id", requireAdmin, apiKey, v.apiKey.GetAPIKey)
+	api.DELETE("/api-keys/:id", requireAdmin, apiKey, v.apiKey.RevokeAPIKey)
+	api.GET("/api-keys/:id/limits", requireAdmin, apiKey, v.apiKey.GetAPIKeyLimits)
+	api.PUT("/api-keys/:id/limits", requireAdmin, apiKey, v.apiKey.UpdateAPIKeyLimits)
+	api.DELETE("/api-keys/:id/limits", requireAdmin, apiKey, v.apiKey.DeleteAPIKeyLimits)
+	api.GET("/api-keys/:id/usage", requireAdmin, apiKey, v.apiKey.GetAPIKeyUsage)
+
+	// Mail sender routes
+	api.GET("/domains/:domainId/mail-settings", requireAdmin, domainParam, v.mailSettings.GetMailSettings)
+	api.PUT("/domains/:domainId/mail-settings", requireAdmin, domainParam, v.mailSettings.UpdateMailSettings)
+	api.DELETE("/domains/:domainId/mail-settings", requireAdmin, domainParam, v.mailSettings.DeleteMailSettings)
+	api.POST("/domains/:domainId/mail-settings/test", requireAdmin, domainParam, v.mailSettings.TestMailSettings)
+	api.GET("/domains/:domainId/integrations", requireAdmin, domainParam, v.integration.ListIntegrations)
+
+	// Email branding routes
+	api.GET("/domains/:domainId/email-branding", requireAdmin, domainParam, v.emailBranding.GetEmailBranding)
+	api.PUT("/domains/:domainId/email-branding", requireAdmin, domainParam, v.emailBranding.UpdateEmailBranding)
+	api.DELETE("/domains/:domainId/email-branding", requireAdmin, domainParam, v.emailBranding.DeleteEmailBranding)
+	api.GET("/domains/:domainId/email-branding/preview/:template", requireAdmin, domainParam, v.emailBranding.PreviewEmail)
+
+	// Login risk routes
+	api.GET("/domains/:domainId/risk-policy", requireAdmin, domainParam, v.loginRisk.GetRiskPolicy)
+	api.PUT("/domains/:domainId/risk-policy", requireAdmin, domainParam, v.loginRisk.UpdateRiskPolicy)
+
+	// Auth routes
+	api.POST("/auth/login", v.loginLimit, v.auth.Login)
+	api.POST("/auth/token", raw, v.loginLimit, v.serviceAccount.Token)
+	api.POST("/auth/register", v.loginLimit, v.registration.Register)
+	api.POST("/auth/accept-invitation", v.loginLimit, v.invitation.AcceptInvitation)
+	api.POST("/auth/change-expired-password", v.loginLimit, v.auth.ChangeExpiredPassword)
+	api.POST("/auth/passwordless/start", v.emailSendLimit, v.auth.StartPasswordless)
+	api.POST("/auth/passwordless/verify", v.loginLimit, v.auth.VerifyPasswordless)
+	api.POST("/auth/revoke", notImpersonated, v.auth.RevokeToken)
+	api.GET("/auth/profile", v.auth.GetProfile)
+	api.GET("/auth/permissions", v.auth.GetPermissions)
+	api.POST("/auth/change-password", v.loginLimit, notImpersonated, v.auth.ChangePassword)
+	api.GET("/auth/consents", v.consent.ListConsents)
+	api.PUT("/auth/consents/:clientId", notImpersonated, v.consent.GrantConsent)
+	api.DELETE("/auth/consents/:clientId", notImpersonated, v.consent.RevokeConsent)
+	api.GET("/auth/devices", v.trustedDevice.ListDevices)
+	api.DELETE("/auth/devices", notImpersonated, v.trustedDevice.RevokeAllDevices)
+	api.DELETE("/auth/devices/:id", notImpersonated, v.trustedDevice.RevokeDevice)
+	api.DELETE("/auth/me", notImpersonated, v.accountDeletion.RequestDeletion)
+	api.POST("/auth/me/cancel-deletion", notImpersonated, v.accountDeletion.CancelDeletion)
+	api.GET("/oauth/userinfo", raw, v.consent.UserInfo)
+	api.POST("/auth/authorize", requireAdmin, checkedUser, v.policy.Authorize)
+	api.POST("/auth/simulate", requireAdmin, accessUser, accessRole, v.authz.SimulateAccess)
+
+	// Authorization routes
+	api.GET("/authz/who-can", requireAdmin, domainQuery, v.authz.WhoCan)
+	api.POST("/authz/check", requireAdmin, checkedUser, v.authz.Check)
+	api.POST("/authz/simulate", requireAdmin, simulatedRole, v.authz.Simulate)
+	api.GET("/authz/decisions", requireAdmin, domainQuery, v.authz.ListDecisions)
+
+	// Event log routes
+	api.GET("/events", requireAdmin, domainQuery, v.event.ListEvents)
+
+	// Webhook routes
+	api.GET("/domains/:domainId/webhooks", requireAdmin, domainParam, v.webhook.ListWebhooks)
+	api.POST("/domains/:domainId/webhooks", requireAdmin, domainParam, v.webhook.CreateWebhook)
+	api.GET("/domains/:domainId/webhooks/:webhookId", requireAdmin, domainParam, v.webhook.GetWebhook)
+	api.PUT("/domains/:domainId/webhooks/:webhookId", requireAdmin, domainParam, v.webhook.UpdateWebhook)
+	api.DELETE("/domains/:domainId/webhooks/:webhookId", requireAdmin, domainParam, v.webhook.DeleteWebhook)
+	api.POST("/domains/:domainId/webhooks/:webhookId/rotate-secret", requireAdmin, domainParam, v.webhook.RotateWebhookSecret)
+	api.GET("/domains/:domainId/webhooks/:webhookId/deliveries", requireAdmin, domainParam, v.webhook.ListWebhookDeliveries)
+	api.POST("/domains/:domainId/webhooks/:webhookId/deliveries/:deliveryId/redeliver", requireAdmin, domainParam, v.webhook.RedeliverWebhookDelivery)
+
+	// Platform operator routes, authenticated with X-Operator-Token
+	operator := api.Group("/operator", v.requireOperator)
+	operator.GET("/break-glass-accounts", v.breakGlass.ListBreakGlassAccounts)
+	operator.POST("/domains/:domainId/break-glass-accounts", v.breakGlass.CreateBreakGlassAccount)
+	operator.PUT("/break-glass-accounts/:id/password", v.breakGlass.RotateBreakGlassPassword)
+	operator.DELETE("/break-glass-accounts/:id", v.breakGlass.DeleteBreakGlassAccount)
+	operator.POST("/domain-jobs", v.domainJob.CreateDomainJob)
+	operator.GET("/domain-jobs", v.domainJob.ListDomainJobs)
+	operator.GET("/domain-jobs/:id", v.domainJob.GetDomainJob)
+	operator.PUT("/domains/:domainId/labels", v.domainJob.SetDomainLabels)
+	operator.GET("/jobs", v.job.ListJobs)
+	operator.GET("/jobs/stats", v.job.GetJobStats)
+	operator.GET("/jobs/:id", v.job.GetJob)
+	operator.POST("/jobs/:id/retry", v.job.RetryJob)
+	admin := api.Group("/admin", v.requireOperator)
+	admin.GET("/config-snapshot", v.admin.GetConfigSnapshot)
+	if v.faultInjection {
+		admin.GET("/faults", v.admin.GetFaults)
+		admin.PUT("/faults", v.admin.SetFaults)
+		admin.DELETE("/faults", v.admin.ClearFaults)
+	}
+
+	// Domain routes
+	api.GET("/domains", requireAdmin, systemAdmin, v.domain.ListDomains)
+	api.GET("/domains/resolve", v.domain.ResolveDomain)
+	api.GET("/domains/:domainId", requireAdmin, domainParam, v.domain.GetDomain)
+	api.POST("/domains", requireAdmin, systemAdmin, v.idempotent, v.domain.CreateDomain)
+	api.POST("/domains/onboard", requireAdmin, systemAdmin, v.domain.OnboardDomain)
+	api.PUT("/domains/:domainId", requireAdmin, systemAdmin, v.domain.UpdateDomain)
+	api.DELETE("/domains/:domainId", requireAdmin, systemAdmin, v.domain.DeleteDomain)
+	api.POST("/domains/:domainId/deletion", requireAdmin, systemAdmin, v.domain.RequestDomainDeletion)
+	api.GET("/domains/:domainId/deletion", requireAdmin, domainParam, v.domain.GetDomainDeletion)
+	api.GET("/domains/:domainId/password-policy", requireAdmin, domainParam, v.domain.GetPasswordPolicy)
+	api.GET("/domains/:domainId/token-settings", requireAdmin, domainParam, v.domain.GetTokenSettings)
+	api.PUT("/domains/:domainId/token-settings", requireAdmin, domainParam, v.domain.UpdateTokenSettings)
+	api.GET("/domains/:domainId/data-masking", requireAdmin, domainParam, v.domain.GetDataMasking)
+	api.PUT("/domains/:domainId/data-masking", requireAdmin, domainParam, v.domain.UpdateDataMasking)
+	api.GET("/domains/:domainId/telemetry", requireAdmin, domainParam, v.telemetry.GetLoginTelemetry)
+	api.PUT("/domains/:domainId/telemetry", requireAdmin, domainParam, v.telemetry.UpdateLoginTelemetry)
+	api.GET("/domains/:domainId/aliases", requireAdmin, domainParam, v.domain.ListDomainAliases)
+	api.POST("/domains/:domainId/aliases", requireAdmin, systemAdmin, v.domain.CreateDomainAlias)
+	api.PUT("/domains/:domainId/aliases/:aliasId/primary", requireAdmin, systemAdmin, v.domain.SetPrimaryDomainAlias)
+	api.DELETE("/domains/:domainId/aliases/:aliasId", requireAdmin, systemAdmin, v.domain.DeleteDomainAlias)
+	api.GET("/domains/:domainId/registration-codes", requireAdmin, domainParam, v.registration.ListRegistrationCodes)
+	api.POST("/domains/:domainId/registration-codes", requireAdmin, domainParam, v.registration.CreateRegistrationCode)
+	api.DELETE("/domains/:domainId/registration-codes/:codeId", requireAdmin, domainParam, v.registration.RevokeRegistrationCode)
+	api.GET("/domains/:domainId/invitations", requireAdmin, domainParam, v.invitation.ListInvitations)
+	api.POST("/domains/:domainId/invitations", requireAdmin, domainParam, v.invitation.CreateInvitation)
+	api.POST("/domains/:domainId/invitations/:invitationId/resend", requireAdmin, domainParam, v.invitation.ResendInvitation)
+	api.DELETE("/domains/:domainId/invitations/:invitationId", requireAdmin, domainParam, v.invitation.RevokeInvitation)
+}
@@ -2,37 +2,258 @@
 //
 //	@title			Nusarithm IAM API
 //	@version		1.0
-//	@description	This is the API for Nusarithm IAM Backend
+//	@description	This is the API for Nusarithm IAM Backend. The API is served under /api/v1; its former unversioned paths still work until API_LEGACY_SUNSET and send Deprecation, Sunset and successor-version Link headers, then answer 410 Gone. /api/v2 serves the same routes with every JSON response in an envelope: {"data", "meta"} on success, with the pagination of listings in meta, and {"error": {"code", "message", "details"}} on failure; OAuth token and userinfo responses and GraphQL keep their standard formats. Unless ADMIN_AUTHORIZATION=false the admin routes, and the decision endpoints /authz/check and /auth/authorize, need a bearer token holding domain:admin, which manages only the token's own domain, or system:admin in the ADMIN_SYSTEM_DOMAIN_ID domain, which also manages domains; X-Operator-Token acts as a system admin. org_unit:admin manages only the users of the admin's own org unit and its descendants.
 //	@host			localhost:8080
 //	@BasePath		/
+//
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+//	@description				Access token from login, sent as "Bearer <token>"
+//
+//	@securityDefinitions.apikey	OperatorToken
+//	@in							header
+//	@name						X-Operator-Token
+//	@description				Platform operator token (PLATFORM_OPERATOR_TOKEN)
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"backend/internal/infrastructure/config"
+	"backend/internal/infrastructure/jobs"
+	"backend/internal/infrastructure/mailer"
+	"backend/internal/infrastructure/repositories"
+	"backend/internal/infrastructure/startup"
+	"backend/internal/infrastructure/tenancy"
 	"backend/internal/presentation/routes"
 
 	"github.com/joho/godotenv"
 )
 
 func main() {
+	// Set when the server stops on an error; deferred first, so the exit comes after every other
+	// deferred cleanup has run
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
 	// Load environment variables from .env file
 	err := godotenv.Load()
 	if err != nil {
 		log.Println("Warning: Error loading .env file:", err)
 	}
 
-	// Initialize database config
-	dbConfig := config.NewDatabaseConfig()
-	db, err := dbConfig.OpenDB()
+	// Read and validate every setting before connecting to anything
+	cfg, err := config.LoadAppConfig()
+	if err != nil {
+		log.Fatal("Invalid configuration:\n", err)
+	}
+
+	// Initialize tracing before opening connections so queries are instrumented
+	shutdownTracer, err := cfg.Tracing.InitTracer(context.Background())
+	if err != nil {
+		log.Fatal("Failed to initialize tracing:", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracer(ctx); err != nil {
+			log.Println("Failed to flush traces:", err)
+		}
+	}()
+
+	// Stop retrying dependencies and running background jobs on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Check dependencies before binding the port, retrying the required ones with backoff
+	checks := startup.New(cfg.Startup)
+	fatal := func(msg string, err error) {
+		checks.LogSummary()
+		log.Fatal(msg, err)
+	}
+
+	// Connect to the primary database
+	db, err := startup.Open(ctx, checks, "database", cfg.Database.OpenDB)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		fatal("Failed to connect to database:", err)
 	}
 	defer db.Close()
 
-	// Setup router
-	r := routes.SetupRouter(db)
+	// Open residency shards (optional)
+	var shards map[string]*sql.DB
+	if len(cfg.ShardDSNs) == 0 {
+		checks.Skip("residency shards", "DB_SHARDS not set")
+	} else if shards, err = startup.Open(ctx, checks, "residency shards", func() (map[string]*sql.DB, error) {
+		return cfg.Database.OpenShards(cfg.ShardDSNs)
+	}); err != nil {
+		fatal("Failed to connect to residency shard:", err)
+	}
+	for _, shard := range shards {
+		defer shard.Close()
+	}
+
+	// Open read replicas (optional)
+	var replicas map[string]*sql.DB
+	if len(cfg.ReplicaDSNs) == 0 {
+		checks.Skip("read replicas", "no replica DSNs set")
+	} else if replicas, err = startup.Open(ctx, checks, "read replicas", func() (map[string]*sql.DB, error) {
+		return cfg.Database.OpenReplicas(cfg.ReplicaDSNs)
+	}); err != nil {
+		fatal("Failed to connect to read replica:", err)
+	}
+	for _, replica := range replicas {
+		defer replica.Close()
+	}
+
+	// Compare the schema of every database with the shipped migrations
+	schemaRepo := repositories.NewSchemaRepository(repositories.NewShardRouter(db, shards, nil))
+	if err := checks.Verify(ctx, "migrations", cfg.Startup.RequireMigrations, startup.Migrations(cfg.Startup.MigrationsDir, schemaRepo)); err != nil {
+		fatal("Database schema is behind the shipped migrations:", err)
+	}
+
+	// Open the request rate limit store (in-memory unless Redis is configured)
+	rateLimitStore, err := cfg.RequestRateLimits.OpenStore()
+	if err != nil {
+		fatal("Failed to open rate limit store:", err)
+	}
+
+	// Open the role and domain lookup cache (in-memory unless Redis is configured)
+	lookupCache, err := startup.Open(ctx, checks, "cache ("+cfg.Cache.Store+")", cfg.Cache.OpenCache)
+	if err != nil {
+		fatal("Failed to connect to cache:", err)
+	}
+
+	// Open the revoked token denylist (the revoked_tokens table unless Redis is configured)
+	revokedTokens, err := startup.Open(ctx, checks, "token revocation ("+cfg.TokenRevocation.Store+")", func() (repositories.RevokedTokenRepository, error) {
+		return cfg.TokenRevocation.OpenStore(db)
+	})
+	if err != nil {
+		fatal("Failed to open token revocation store:", err)
+	}
+
+	// Check the SMTP server once; email is retried per message, so a failure only warns
+	switch {
+	case cfg.Mail.Driver == config.MailDriverLog:
+		checks.Skip("mail", "MAIL_DRIVER=log, emails are logged")
+	case cfg.Mail.Driver != config.MailDriverSMTP:
+		checks.Skip("mail", "sent through the "+cfg.Mail.Driver+" API")
+	case !cfg.Startup.VerifyMail:
+		checks.Skip("mail", "STARTUP_VERIFY_MAIL=false")
+	default:
+		checks.Verify(ctx, "mail", false, func(ctx context.Context) (string, error) {
+			return cfg.Mail.SMTPHost + ":" + cfg.Mail.SMTPPort, mailer.Verify(ctx, cfg.Mail, nil)
+		})
+	}
+
+	// Encrypt the SMTP passwords of domain senders stored before they were encrypted
+	if cfg.Mail.Credentials == nil {
+		checks.Skip("mail credentials", "MAIL_CREDENTIALS_KEY unset, domains can't store SMTP passwords")
+	} else {
+		checks.Verify(ctx, "mail credentials", false, func(ctx context.Context) (string, error) {
+			sealed, err := repositories.NewDomainMailSettingsRepository(db, cfg.Mail.Credentials).SealPlaintextPasswords(ctx)
+			return fmt.Sprintf("%d stored passwords encrypted", sealed), err
+		})
+	}
+
+	// Open the event broker publisher; events wait in the outbox while the broker is unreachable
+	publisher, err := cfg.Broker.OpenPublisher()
+	if err != nil {
+		fatal("Failed to open event broker:", err)
+	}
+	if publisher != nil {
+		defer publisher.Close()
+	}
+
+	// Open the login telemetry sink; the warehouse is first contacted by the export job
+	telemetrySink, err := cfg.Telemetry.OpenSink()
+	if err != nil {
+		fatal("Failed to open telemetry sink:", err)
+	}
+
+	// Open the storage for uploaded files such as avatars
+	objectStore, err := cfg.Storage.Open()
+	if err != nil {
+		fatal("Failed to open file storage:", err)
+	}
+
+	// Load the token signing keys (HS256 with JWT_SECRET unless a private key is configured)
+	signingKeys, err := cfg.JWT.LoadKeys()
+	if err != nil {
+		fatal("Failed to load JWT signing key:", err)
+	}
+	if cfg.JWT.DefaultSecret() {
+		log.Println("WARNING: tokens are signed with the public development JWT secret (JWT_ALLOW_DEFAULT_SECRET=true); set JWT_PRIVATE_KEY_FILE or JWT_SECRET")
+	}
+	checks.LogSummary()
+
+	// The job queue lives on the primary; SetupRouter registers the kinds of jobs it runs
+	jobQueue := jobs.NewQueue(repositories.NewJobRepository(db), cfg.Jobs)
+
+	// Setup router; background jobs stop with ctx
+	r := routes.SetupRouter(ctx, cfg, db, shards, replicas, rateLimitStore, lookupCache, revokedTokens, publisher, telemetrySink, objectStore, signingKeys, jobQueue, mailer.New(cfg.Mail))
+
+	// Start the job workers; on shutdown they finish or requeue their current jobs before the database closes
+	if cfg.Jobs.Workers == 0 {
+		log.Println("Job workers are disabled on this instance (JOB_WORKERS=0); jobs queue up for other instances")
+	}
+	// Jobs work on every tenant's data, like the other background jobs
+	jobQueue.Start(tenancy.AsSystem(ctx))
+	defer func() {
+		stop()
+		jobQueue.Wait()
+	}()
+
+	// Setup HTTP server
+	srv := &http.Server{
+		Addr:              cfg.Server.Addr,
+		Handler:           r,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server listening on %s", cfg.Server.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
 
-	log.Fatal(r.Run(":8080"))
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			log.Println("Server error:", err)
+			exitCode = 1
+			return
+		}
+	case <-ctx.Done():
+		log.Println("Shutdown signal received, draining in-flight requests...")
+	}
+
+	// Drain in-flight requests before the deferred db.Close runs
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("Graceful shutdown failed:", err)
+		exitCode = 1
+		return
+	}
+	log.Println("Server stopped")
 }
@@ -0,0 +1,16 @@
+-- Migration: Create domain_aliases table
+-- Created: 2026-10-15
+
+CREATE TABLE IF NOT EXISTS domain_aliases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    hostname VARCHAR(255) NOT NULL UNIQUE,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on domain_id for faster lookups
+CREATE INDEX IF NOT EXISTS idx_domain_aliases_domain_id ON domain_aliases(domain_id);
+
+-- Only one primary alias per domain
+CREATE UNIQUE INDEX IF NOT EXISTS idx_domain_aliases_primary ON domain_aliases(domain_id) WHERE is_primary;
@@ -0,0 +1,7 @@
+-- Migration: Add data residency to domains
+-- Created: 2026-10-15
+
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS residency VARCHAR(32) NOT NULL DEFAULT 'default';
+
+-- Create index on residency for shard routing lookups
+CREATE INDEX IF NOT EXISTS idx_domains_residency ON domains(residency);
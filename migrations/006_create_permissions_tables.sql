@@ -0,0 +1,26 @@
+-- Migration: Create permissions and role_permissions tables
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (domain_id, name)
+);
+
+-- Create index on domain_id for catalog listings
+CREATE INDEX IF NOT EXISTS idx_permissions_domain_id ON permissions(domain_id);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id UUID NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+-- Create index on permission_id for reverse lookups
+CREATE INDEX IF NOT EXISTS idx_role_permissions_permission_id ON role_permissions(permission_id);
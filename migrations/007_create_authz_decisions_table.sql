@@ -0,0 +1,17 @@
+-- Migration: Create authz_decisions table
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS authz_decisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    role_id UUID NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    matched_rule VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index for replaying a role's recent decisions during simulation
+CREATE INDEX IF NOT EXISTS idx_authz_decisions_domain_role_created ON authz_decisions(domain_id, role_id, created_at DESC);
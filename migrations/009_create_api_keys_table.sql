@@ -0,0 +1,18 @@
+-- Migration: Create api_keys table
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    rate_limit_per_minute INTEGER,
+    daily_quota INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create index on domain_id for faster lookups
+CREATE INDEX IF NOT EXISTS idx_api_keys_domain_id ON api_keys(domain_id);
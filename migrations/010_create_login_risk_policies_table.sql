@@ -0,0 +1,10 @@
+-- Migration: Create login_risk_policies table
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS login_risk_policies (
+    domain_id UUID PRIMARY KEY REFERENCES domains(domain_id) ON DELETE CASCADE,
+    captcha_threshold INTEGER CHECK (captcha_threshold BETWEEN 1 AND 100),
+    mfa_threshold INTEGER CHECK (mfa_threshold BETWEEN 1 AND 100),
+    block_threshold INTEGER CHECK (block_threshold BETWEEN 1 AND 100),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,16 @@
+-- Migration: Create policies table
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    document JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (domain_id, name)
+);
+
+-- Create index on domain_id for faster lookups
+CREATE INDEX IF NOT EXISTS idx_policies_domain_id ON policies(domain_id);
@@ -0,0 +1,5 @@
+-- Migration: Add login mode to domains
+-- Created: 2026-10-16
+
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS login_mode VARCHAR(32) NOT NULL DEFAULT 'password'
+    CHECK (login_mode IN ('password', 'passwordless'));
@@ -0,0 +1,7 @@
+-- Migration: Add external_id to users
+-- Created: 2026-10-16
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS external_id VARCHAR(255);
+
+-- External IDs are unique per domain; users without one are not constrained
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_domain_external_id ON users(domain_id, external_id) WHERE external_id IS NOT NULL;
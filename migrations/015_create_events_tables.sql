@@ -0,0 +1,20 @@
+-- Migration: Create events and event_sequences tables
+-- Created: 2026-10-16
+
+-- Last sequence number handed out per domain; the row lock taken while incrementing it
+-- serializes appends so sequence numbers are gapless and ordered within a domain
+CREATE TABLE IF NOT EXISTS event_sequences (
+    domain_id UUID PRIMARY KEY REFERENCES domains(domain_id) ON DELETE CASCADE,
+    last_sequence BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    sequence BIGINT NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    subject_id UUID,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (domain_id, sequence)
+);
@@ -0,0 +1,9 @@
+-- Migration: Scope username and email uniqueness to the domain
+-- Created: 2026-10-16
+
+-- Usernames and emails were unique across all tenants; two domains may now hold the same one
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_domain_username ON users(domain_id, username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_domain_email ON users(domain_id, email);
@@ -0,0 +1,12 @@
+-- Migration: Add account end dates and deprovisioning state to users
+-- Created: 2026-10-16
+
+-- valid_until is the end date of a time-limited (e.g. contractor) account; NULL never expires
+ALTER TABLE users ADD COLUMN IF NOT EXISTS valid_until TIMESTAMP WITH TIME ZONE;
+-- Set by the deprovisioning sweep once valid_until has passed; disabled users cannot log in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP WITH TIME ZONE;
+-- Tokens issued before this instant are rejected
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sessions_revoked_at TIMESTAMP WITH TIME ZONE;
+
+-- Supports the sweep and the upcoming-expiry report, which only look at active users with an end date
+CREATE INDEX IF NOT EXISTS idx_users_valid_until ON users(valid_until) WHERE valid_until IS NOT NULL AND disabled_at IS NULL;
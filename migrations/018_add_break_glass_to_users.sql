@@ -0,0 +1,9 @@
+-- Migration: Flag break-glass emergency access accounts
+-- Created: 2026-10-16
+
+-- Break-glass accounts sign in with a local password even when the domain uses passwordless login,
+-- skip login challenges, get short-lived sessions and alert on every use. Only platform operators
+-- can create or change them.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS break_glass BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_users_break_glass ON users(domain_id) WHERE break_glass;
@@ -0,0 +1,6 @@
+-- Migration: Add a configurable password policy to domains
+-- Created: 2026-10-16
+
+-- Keys: min_length, require_uppercase, require_lowercase, require_digit, require_symbol,
+-- history_count, max_age_days. Missing keys fall back to the defaults (6 characters, no other rules).
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS password_policy JSONB NOT NULL DEFAULT '{}'::jsonb;
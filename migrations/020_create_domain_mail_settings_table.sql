@@ -0,0 +1,18 @@
+-- Migration: Create domain_mail_settings table
+-- Created: 2026-10-16
+
+-- A domain's own outgoing mail sender. Domains without a row, or with it disabled, use the platform SMTP settings.
+CREATE TABLE IF NOT EXISTS domain_mail_settings (
+    domain_id UUID PRIMARY KEY REFERENCES domains(domain_id) ON DELETE CASCADE,
+    provider VARCHAR(16) NOT NULL DEFAULT 'smtp' CHECK (provider IN ('smtp', 'ses')),
+    host VARCHAR(255) NOT NULL,
+    port VARCHAR(8) NOT NULL DEFAULT '587',
+    region VARCHAR(32),
+    username VARCHAR(255),
+    password TEXT,
+    from_address VARCHAR(320) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_tested_at TIMESTAMP WITH TIME ZONE,
+    last_test_error TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,14 @@
+-- Migration: Create integration_health table
+-- Created: 2026-10-16
+
+-- Result of the scheduled health checks of each tenant integration (e.g. the domain's SMTP sender)
+CREATE TABLE IF NOT EXISTS integration_health (
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('healthy', 'unhealthy')),
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_success_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (domain_id, kind)
+);
@@ -0,0 +1,6 @@
+-- Migration: Track when each user's password was last changed
+-- Created: 2026-10-16
+
+-- Compared against the domain's password_policy max_age_days at login. Existing users start their
+-- password age at the time of this migration rather than being expired at once.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
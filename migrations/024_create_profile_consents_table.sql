@@ -0,0 +1,16 @@
+-- Migration: Create profile_consents table
+-- Created: 2026-10-16
+
+-- Profile fields a user agreed to share with a client app. Clients are the domain's API keys, which
+-- live on the primary database, so client_id carries no foreign key on residency shards.
+CREATE TABLE IF NOT EXISTS profile_consents (
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id UUID NOT NULL,
+    fields TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, client_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_profile_consents_client_id ON profile_consents(client_id);
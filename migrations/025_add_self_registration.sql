@@ -0,0 +1,22 @@
+-- Migration: Add self-registration settings and registration codes
+-- Created: 2026-10-16
+
+-- Keys: open (anyone may sign up) and default_role_id (role given to self-registered users)
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS registration JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- Invitation codes let people sign up to a domain that is closed to open registration. Only a
+-- hash of each code is stored; code_prefix identifies it in listings.
+CREATE TABLE IF NOT EXISTS registration_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    code_prefix VARCHAR(16) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL UNIQUE,
+    role_id UUID REFERENCES roles(id) ON DELETE CASCADE,
+    max_uses INTEGER,
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_registration_codes_domain_id ON registration_codes(domain_id);
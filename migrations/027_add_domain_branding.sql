@@ -0,0 +1,6 @@
+-- Migration: Add branding for the hosted login page
+-- Created: 2026-10-16
+
+-- Keys: display_name, logo_url, primary_color, background_color and redirect_uris (the app pages
+-- the hosted login page may return users to)
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS branding JSONB NOT NULL DEFAULT '{}'::jsonb;
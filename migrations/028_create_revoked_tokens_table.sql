@@ -0,0 +1,13 @@
+-- Migration: Create revoked_tokens table
+-- Created: 2026-10-16
+
+-- Denylist of revoked access tokens by their jti claim, kept on the primary database. Rows are
+-- only needed until the token would have expired anyway.
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create index on expires_at for the sweep of expired entries
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
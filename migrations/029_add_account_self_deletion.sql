@@ -0,0 +1,11 @@
+-- Migration: Add self-service account deletion
+-- Created: 2026-10-16
+
+-- Keys: enabled (whether users may delete their own account through DELETE /auth/me) and
+-- grace_days (how long a requested deletion can still be cancelled; 0 uses the server default)
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS account_deletion JSONB NOT NULL DEFAULT '{}'::jsonb;
+-- When the user's requested deletion takes effect; NULL when no deletion is pending
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMP WITH TIME ZONE;
+
+-- Supports the deletion sweep, which only looks at users with a pending deletion
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;
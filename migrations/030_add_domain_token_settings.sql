@@ -0,0 +1,6 @@
+-- Migration: Add per-domain access token settings
+-- Created: 2026-10-16
+
+-- Keys: access_token_ttl_minutes, refresh_ttl_minutes (lifetime of the hosted login session that
+-- renews access tokens), audience and extra_claims (static claims added to every access token)
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS token_settings JSONB NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,6 @@
+-- Migration: Add per-domain masking of user fields in admin responses
+-- Created: 2026-10-16
+
+-- Keys: fields (user fields masked for admins without the pii:read permission: email, first_name,
+-- last_name, external_id)
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS data_masking JSONB NOT NULL DEFAULT '{}'::jsonb;
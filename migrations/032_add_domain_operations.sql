@@ -0,0 +1,31 @@
+-- Migration: Add domain plans, tags and suspension, and bulk domain jobs
+-- Created: 2026-10-16
+
+-- Plan and tags group domains for platform operators, e.g. to target bulk operations
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS plan VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+-- Set while the domain is suspended; its users cannot sign in and their tokens are rejected
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_domains_plan ON domains(plan);
+CREATE INDEX IF NOT EXISTS idx_domains_tags ON domains USING GIN (tags);
+
+-- Bulk operations run by platform operators across many domains, with per-domain results
+CREATE TABLE IF NOT EXISTS domain_jobs (
+    id UUID PRIMARY KEY,
+    action VARCHAR(32) NOT NULL CHECK (action IN ('suspend', 'unsuspend', 'message', 'update_settings')),
+    selector JSONB NOT NULL,
+    message JSONB,
+    settings JSONB,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    total INTEGER NOT NULL DEFAULT 0,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    results JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_jobs_created_at ON domain_jobs(created_at DESC);
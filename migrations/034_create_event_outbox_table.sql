@@ -0,0 +1,18 @@
+-- Migration: Create event_outbox table
+-- Created: 2026-10-16
+
+-- Events waiting to be published to the message broker, queued in the same transaction as the
+-- event so none is lost while the broker is unreachable. Rows are deleted once published and are
+-- only written when a broker is configured.
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_due ON event_outbox(next_attempt_at, id);
+CREATE INDEX IF NOT EXISTS idx_event_outbox_domain ON event_outbox(domain_id, id);
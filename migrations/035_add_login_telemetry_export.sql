@@ -0,0 +1,17 @@
+-- Migration: Add login telemetry export
+-- Created: 2026-10-16
+
+-- Per-domain opt-in to exporting the anonymized sign-in funnel to the analytics warehouse
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS telemetry JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- How far each opted-in domain's events have been exported. A cursor starts at the domain's
+-- latest event when it is created and is dropped whenever the domain opts in or out, so only
+-- events recorded while a domain was opted in are exported.
+CREATE TABLE IF NOT EXISTS telemetry_export_cursors (
+    domain_id UUID PRIMARY KEY REFERENCES domains(domain_id) ON DELETE CASCADE,
+    last_sequence BIGINT NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    exported_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
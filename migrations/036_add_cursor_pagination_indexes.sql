@@ -0,0 +1,16 @@
+-- Migration: Add cursor pagination indexes
+-- Created: 2026-10-16
+
+-- Cursor-paginated listings order by creation time and then ID. Rows without a creation time
+-- would never appear in them, so give them one first.
+UPDATE domains SET created_at = COALESCE(updated_at, CURRENT_TIMESTAMP) WHERE created_at IS NULL;
+UPDATE users SET created_at = COALESCE(updated_at, CURRENT_TIMESTAMP) WHERE created_at IS NULL;
+UPDATE roles SET created_at = COALESCE(updated_at, CURRENT_TIMESTAMP) WHERE created_at IS NULL;
+
+ALTER TABLE domains ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE users ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE roles ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_domains_created ON domains(created_at, domain_id);
+CREATE INDEX IF NOT EXISTS idx_users_domain_created ON users(domain_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_roles_domain_created ON roles(domain_id, created_at, id);
@@ -0,0 +1,12 @@
+-- Migration: Add trigram index for user search
+-- Created: 2026-10-16
+
+-- User search matches a term anywhere in the username, email and name. A leading wildcard can't
+-- use a btree index, so without this every search scans all of the domain's users. The repository
+-- searches this exact expression; keep the two in sync.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- CONCURRENTLY keeps the users table writable while the index is built. It can't run inside a
+-- transaction, so run_migrations.sh runs this file without --single-transaction.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_search_trgm ON users
+    USING GIN ((username || ' ' || email || ' ' || first_name || ' ' || last_name) gin_trgm_ops);
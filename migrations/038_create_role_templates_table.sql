@@ -0,0 +1,16 @@
+-- Migration: Create the role_templates catalog of roles operators create in domains
+-- Created: 2026-10-16
+
+-- Platform-wide role definitions; roles created from them in a domain copy the claims and are
+-- assigned the listed permissions, which are added to the domain's catalog when missing
+CREATE TABLE IF NOT EXISTS role_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    role_claims JSONB NOT NULL DEFAULT '{}'::jsonb,
+    permissions TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_role_templates_name ON role_templates(name);
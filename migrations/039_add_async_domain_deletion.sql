@@ -0,0 +1,27 @@
+-- Migration: Add background domain deletion
+-- Created: 2026-10-16
+
+-- Set once a domain is queued for deletion; its users can no longer sign in
+ALTER TABLE domains ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMP WITH TIME ZONE;
+
+-- Deletions of a domain's data in batches by a background worker. Rows outlive the domain so the
+-- outcome can still be read, which is why domain_id doesn't reference domains
+CREATE TABLE IF NOT EXISTS domain_deletions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    domain_id UUID NOT NULL,
+    domain_name VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    step VARCHAR(64) NOT NULL DEFAULT '',
+    deleted JSONB NOT NULL DEFAULT '{}'::jsonb,
+    error TEXT NOT NULL DEFAULT '',
+    -- The worker running the deletion holds it until then; an expired lease lets another take over
+    lease_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_deletions_domain_id ON domain_deletions(domain_id, created_at DESC);
+-- At most one unfinished deletion per domain
+CREATE UNIQUE INDEX IF NOT EXISTS idx_domain_deletions_active ON domain_deletions(domain_id)
+    WHERE status IN ('pending', 'running');
@@ -0,0 +1,13 @@
+-- Migration: Create domain_email_branding table
+-- Created: 2026-10-16
+
+-- How a domain's emails are branded. Domains without a row use the domain name and the built-in templates
+CREATE TABLE IF NOT EXISTS domain_email_branding (
+    domain_id UUID PRIMARY KEY REFERENCES domains(domain_id) ON DELETE CASCADE,
+    product_name VARCHAR(255) NOT NULL DEFAULT '',
+    support_email VARCHAR(320) NOT NULL DEFAULT '',
+    footer TEXT NOT NULL DEFAULT '',
+    -- Subject and body overrides by template name, e.g. {"invitation": {"subject": "...", "body": "..."}}
+    templates JSONB NOT NULL DEFAULT '{}'::jsonb,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
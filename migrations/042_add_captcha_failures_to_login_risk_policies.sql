@@ -0,0 +1,6 @@
+-- Migration: Add a failed-login count that requires a CAPTCHA to login_risk_policies
+-- Created: 2026-10-16
+
+-- Logins from an IP with at least this many recent failures are challenged for a CAPTCHA whatever
+-- their risk score; NULL leaves it to captcha_threshold
+ALTER TABLE login_risk_policies ADD COLUMN IF NOT EXISTS captcha_after_failures INTEGER CHECK (captcha_after_failures BETWEEN 1 AND 100);
@@ -0,0 +1,18 @@
+-- Migration: Create trusted_devices table
+-- Created: 2026-10-16
+
+-- Devices a user chose to remember at login. The device keeps a token whose hash is stored here;
+-- logins presenting it skip MFA challenges until it expires or the user revokes it.
+CREATE TABLE IF NOT EXISTS trusted_devices (
+    id UUID PRIMARY KEY,
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    last_ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_trusted_devices_user_id ON trusted_devices(user_id);
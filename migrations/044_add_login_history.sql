@@ -0,0 +1,22 @@
+-- Migration: Add last login columns to users and create the login_history table
+-- Created: 2026-10-16
+
+-- The last successful login, shown in user listings for access reviews
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_ip VARCHAR(64);
+
+-- Sign-in attempts on existing accounts, successful or not. Attempts on unknown usernames have no
+-- user to attach to and are only in the event log.
+CREATE TABLE IF NOT EXISTS login_history (
+    id UUID PRIMARY KEY,
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    success BOOLEAN NOT NULL,
+    method VARCHAR(32) NOT NULL,
+    reason VARCHAR(64) NOT NULL DEFAULT '',
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_history_user_id_created_at ON login_history(user_id, created_at DESC);
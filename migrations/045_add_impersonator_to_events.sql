@@ -0,0 +1,6 @@
+-- Migration: Add the impersonating support engineer to events
+-- Created: 2026-10-16
+
+-- Set on events caused by a request made with an impersonation token. The engineer may belong to
+-- another domain, stored on another shard, so the column has no foreign key.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS impersonator_id UUID;
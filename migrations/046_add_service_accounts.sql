@@ -0,0 +1,12 @@
+-- Migration: Add service accounts
+-- Created: 2026-10-16
+
+-- Service accounts are users that backend services sign in as with a client ID (the user ID) and
+-- secret through the client credentials grant. They have no password and no email.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS type VARCHAR(16) NOT NULL DEFAULT 'human'
+    CHECK (type IN ('human', 'service'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS client_secret_hash VARCHAR(64);
+
+-- Emails stay unique per domain, but any number of accounts may have none
+DROP INDEX IF EXISTS idx_users_domain_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_domain_email ON users(domain_id, email) WHERE email <> '';
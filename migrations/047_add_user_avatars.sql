@@ -0,0 +1,7 @@
+-- Migration: Add user avatars
+-- Created: 2026-10-16
+
+-- avatar_key names the object in file storage, so a replaced avatar can be deleted; avatar_url is
+-- where clients fetch it
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_key VARCHAR(512);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url VARCHAR(1024);
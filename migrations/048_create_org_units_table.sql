@@ -0,0 +1,23 @@
+-- Migration: Create org_units table and add users.org_unit_id
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS org_units (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    -- Units with children can't be deleted; their children are moved or deleted first
+    parent_id UUID REFERENCES org_units(id) ON DELETE RESTRICT,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (domain_id, name)
+);
+
+-- Create index on parent_id for walking subtrees
+CREATE INDEX IF NOT EXISTS idx_org_units_parent_id ON org_units(parent_id);
+
+-- Users of a deleted unit are left without one
+ALTER TABLE users ADD COLUMN IF NOT EXISTS org_unit_id UUID REFERENCES org_units(id) ON DELETE SET NULL;
+
+-- Create index on org_unit_id for listing the users of a subtree
+CREATE INDEX IF NOT EXISTS idx_users_org_unit_id ON users(org_unit_id) WHERE org_unit_id IS NOT NULL;
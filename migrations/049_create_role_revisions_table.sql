@@ -0,0 +1,21 @@
+-- Migration: Create role_revisions table
+-- Created: 2026-10-16
+
+-- One row per role update: the name and claims before and after it, who made it, and the diff of
+-- the claims. Rollbacks are updates too and name the revision they undid.
+CREATE TABLE IF NOT EXISTS role_revisions (
+    id UUID PRIMARY KEY,
+    domain_id UUID NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    -- The admin who made the change, NULL for the platform operator or without admin authorization
+    actor_id UUID,
+    old_role_name VARCHAR(255) NOT NULL,
+    new_role_name VARCHAR(255) NOT NULL,
+    old_claims JSONB NOT NULL DEFAULT '{}',
+    new_claims JSONB NOT NULL DEFAULT '{}',
+    diff JSONB NOT NULL DEFAULT '{}',
+    rollback_of INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (role_id, revision)
+);
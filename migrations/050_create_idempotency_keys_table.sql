@@ -0,0 +1,20 @@
+-- Migration: Create idempotency_keys table
+-- Created: 2026-10-16
+
+-- Responses of create requests sent with an Idempotency-Key header, kept on the primary database so
+-- a retried request gets the original response instead of creating a duplicate. A row without a
+-- status is a request still in flight.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope VARCHAR(255) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INTEGER,
+    content_type VARCHAR(255),
+    response_body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (scope, idempotency_key)
+);
+
+-- Create index on expires_at for the sweep of expired keys
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,84 @@
+-- Migration (optional): Row-level security on tenant tables
+-- Created: 2026-10-16
+
+-- Defense in depth against cross-tenant query bugs. Each tenant table only exposes rows of the
+-- domain in app.domain_id, which the backend sets per statement when DB_ROW_LEVEL_SECURITY=true.
+-- The policies fail closed: a session without app.domain_id, or with it empty, sees no rows and
+-- can't write any. FORCE applies them to the table owner too.
+--
+-- Only system paths (background sweeps and workers, platform operators and system admins, client
+-- credentials lookups, migrations) run as the iam_rls_bypass role, which has BYPASSRLS. The
+-- backend switches to it for those statements and back to its own role for the others; its other
+-- statements without a tenant see no rows. iam_rls_bypass owns the tables, so tables created
+-- by later migrations run as it are owned by it too; the backend's role is made a member of it and
+-- keeps its privileges on them.
+--
+-- Not run by run_migrations.sh; apply it as a superuser to the primary and every shard after the
+-- numbered migrations, and enable DB_ROW_LEVEL_SECURITY at the same time. Once it's applied,
+-- run_migrations.sh and iamctl migrate run the migrations as iam_rls_bypass.
+DO $$
+DECLARE
+    app_role TEXT;
+    owned_table TEXT;
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'iam_rls_bypass') THEN
+        CREATE ROLE iam_rls_bypass NOLOGIN BYPASSRLS;
+    END IF;
+    GRANT USAGE, CREATE ON SCHEMA public TO iam_rls_bypass;
+
+    -- The backend's role owns the tables until this script first runs
+    SELECT tableowner INTO app_role FROM pg_tables WHERE schemaname = 'public' AND tablename = 'users';
+    IF app_role <> 'iam_rls_bypass' THEN
+        EXECUTE format('GRANT iam_rls_bypass TO %I', app_role);
+        FOR owned_table IN SELECT tablename FROM pg_tables WHERE schemaname = 'public' AND tableowner = app_role LOOP
+            EXECUTE format('ALTER TABLE %I OWNER TO iam_rls_bypass', owned_table);
+        END LOOP;
+    END IF;
+END
+$$;
+
+-- Tables with a domain_id column
+DO $$
+DECLARE
+    tenant_table TEXT;
+BEGIN
+    FOREACH tenant_table IN ARRAY ARRAY[
+        'users', 'roles', 'permissions', 'authz_decisions', 'groups', 'policies',
+        'login_codes', 'event_sequences', 'events', 'password_history', 'profile_consents',
+        'trusted_devices', 'login_history', 'registration_codes', 'invitations', 'webhooks', 'webhook_deliveries',
+        'event_outbox', 'telemetry_export_cursors', 'org_units', 'role_revisions'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', tenant_table);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', tenant_table);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', tenant_table);
+        EXECUTE format($policy$
+            CREATE POLICY tenant_isolation ON %I
+            USING (domain_id = NULLIF(current_setting('app.domain_id', true), '')::uuid)
+            WITH CHECK (domain_id = NULLIF(current_setting('app.domain_id', true), '')::uuid)
+        $policy$, tenant_table);
+    END LOOP;
+END
+$$;
+
+-- Join tables, which have no domain_id: a row is visible when the rows it joins are. The
+-- subqueries are limited by the policies of the joined tables.
+DO $$
+DECLARE
+    join_table TEXT;
+    visible TEXT;
+BEGIN
+    FOR join_table, visible IN VALUES
+        ('role_permissions', 'EXISTS (SELECT 1 FROM roles WHERE roles.id = role_permissions.role_id)
+            AND EXISTS (SELECT 1 FROM permissions WHERE permissions.id = role_permissions.permission_id)'),
+        ('group_members', 'EXISTS (SELECT 1 FROM groups WHERE groups.id = group_members.group_id)
+            AND EXISTS (SELECT 1 FROM users WHERE users.id = group_members.user_id)'),
+        ('group_roles', 'EXISTS (SELECT 1 FROM groups WHERE groups.id = group_roles.group_id)
+            AND EXISTS (SELECT 1 FROM roles WHERE roles.id = group_roles.role_id)')
+    LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', join_table);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', join_table);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', join_table);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I USING (%s) WITH CHECK (%s)', join_table, visible, visible);
+    END LOOP;
+END
+$$;
@@ -26,11 +26,28 @@ if ! command -v psql &> /dev/null; then
     exit 1
 fi
 
+# Record applied migrations so /admin/config-snapshot can report the schema version
+psql "$CONN_STR" -c "CREATE TABLE IF NOT EXISTS schema_migrations (version VARCHAR(255) PRIMARY KEY, applied_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP)"
+
+# Once optional/row_level_security.sql is applied, migrations run as its bypass role: it owns the
+# tables, and the policies would hide every row from data changes run as anyone else
+role_args=()
+if [ "$(psql "$CONN_STR" -tAc "SELECT 1 FROM pg_roles WHERE rolname = 'iam_rls_bypass' AND pg_has_role(current_user, oid, 'MEMBER')")" = "1" ]; then
+    role_args=(-c "SET ROLE iam_rls_bypass")
+fi
+
 # Run migrations in order
 for migration_file in $(ls migrations/*.sql | sort); do
     echo "Running migration: $migration_file"
-    psql "$CONN_STR" -f "$migration_file"
-    if [ $? -eq 0 ]; then
+    # Stop at the first failing statement and roll the file back, so a failed migration is never
+    # recorded as applied. CREATE INDEX CONCURRENTLY can't run inside a transaction; files using
+    # it are only stopped at the failing statement.
+    tx_flag="--single-transaction"
+    if grep -q "CONCURRENTLY" "$migration_file"; then
+        tx_flag=""
+    fi
+    if psql "$CONN_STR" -v ON_ERROR_STOP=1 $tx_flag "${role_args[@]}" -f "$migration_file"; then
+        psql "$CONN_STR" -c "INSERT INTO schema_migrations (version) VALUES ('$(basename "$migration_file")') ON CONFLICT DO NOTHING"
         echo "✓ Migration $migration_file completed successfully"
     else
         echo "✗ Migration $migration_file failed"
@@ -0,0 +1,547 @@
+-- SQLite schema for DB_DRIVER=sqlite
+--
+-- The schema the numbered migrations build, written for SQLite, for local development and tests.
+-- The backend applies it to a new, empty database when it opens one. Timestamps default to now(),
+-- which stores them in the format they are compared in. UUIDs, JSONB documents and arrays (in
+-- Postgres' {a,b} text form) are TEXT. There is no trigram index: user search scans the domain's
+-- users.
+--
+-- A migration added to the parent directory must be mirrored here and recorded at the end of the
+-- file, or the startup check reports the SQLite database as behind. The repositories'
+-- TestSQLiteSchemaMatchesMigrations fails until its tables, columns and unique constraints match
+-- those the migrations build.
+
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version VARCHAR(255) PRIMARY KEY,
+    applied_at TIMESTAMP DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS domains (
+    domain_id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    name VARCHAR(255) NOT NULL,
+    domain VARCHAR(255) NOT NULL UNIQUE,
+    residency VARCHAR(32) NOT NULL DEFAULT 'default',
+    login_mode VARCHAR(32) NOT NULL DEFAULT 'password' CHECK (login_mode IN ('password', 'passwordless')),
+    password_policy TEXT NOT NULL DEFAULT '{}',
+    registration TEXT NOT NULL DEFAULT '{}',
+    branding TEXT NOT NULL DEFAULT '{}',
+    account_deletion TEXT NOT NULL DEFAULT '{}',
+    token_settings TEXT NOT NULL DEFAULT '{}',
+    data_masking TEXT NOT NULL DEFAULT '{}',
+    telemetry TEXT NOT NULL DEFAULT '{}',
+    plan VARCHAR(64) NOT NULL DEFAULT '',
+    tags TEXT NOT NULL DEFAULT '{}',
+    suspended_at TIMESTAMP,
+    deletion_requested_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_domains_name ON domains(name);
+CREATE INDEX IF NOT EXISTS idx_domains_residency ON domains(residency);
+CREATE INDEX IF NOT EXISTS idx_domains_plan ON domains(plan);
+CREATE INDEX IF NOT EXISTS idx_domains_created ON domains(created_at, domain_id);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    role_name VARCHAR(255) NOT NULL,
+    role_claims TEXT DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP DEFAULT (now()),
+    UNIQUE (domain_id, role_name)
+);
+CREATE INDEX IF NOT EXISTS idx_roles_domain_created ON roles(domain_id, created_at, id);
+
+CREATE TABLE IF NOT EXISTS org_units (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    parent_id TEXT REFERENCES org_units(id) ON DELETE RESTRICT,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT (now()),
+    updated_at TIMESTAMP DEFAULT (now()),
+    UNIQUE (domain_id, name)
+);
+CREATE INDEX IF NOT EXISTS idx_org_units_parent_id ON org_units(parent_id);
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    role_id TEXT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    org_unit_id TEXT REFERENCES org_units(id) ON DELETE SET NULL,
+    type VARCHAR(16) NOT NULL DEFAULT 'human' CHECK (type IN ('human', 'service')),
+    first_name VARCHAR(255) NOT NULL,
+    last_name VARCHAR(255) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    client_secret_hash VARCHAR(64),
+    external_id VARCHAR(255),
+    avatar_key VARCHAR(512),
+    avatar_url VARCHAR(1024),
+    break_glass BOOLEAN NOT NULL DEFAULT FALSE,
+    valid_until TIMESTAMP,
+    disabled_at TIMESTAMP,
+    sessions_revoked_at TIMESTAMP,
+    password_changed_at TIMESTAMP NOT NULL DEFAULT (now()),
+    deletion_scheduled_at TIMESTAMP,
+    last_login_at TIMESTAMP,
+    last_login_ip VARCHAR(64),
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    updated_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_users_role_id ON users(role_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_domain_username ON users(domain_id, username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_domain_email ON users(domain_id, email) WHERE email <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_domain_external_id ON users(domain_id, external_id) WHERE external_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_users_domain_created ON users(domain_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_users_valid_until ON users(valid_until) WHERE valid_until IS NOT NULL AND disabled_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_break_glass ON users(domain_id) WHERE break_glass;
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_users_org_unit_id ON users(org_unit_id) WHERE org_unit_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS domain_aliases (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    hostname VARCHAR(255) NOT NULL UNIQUE,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_domain_aliases_domain_id ON domain_aliases(domain_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_domain_aliases_primary ON domain_aliases(domain_id) WHERE is_primary;
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT (now()),
+    UNIQUE (domain_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id TEXT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id TEXT NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT (now()),
+    PRIMARY KEY (role_id, permission_id)
+);
+CREATE INDEX IF NOT EXISTS idx_role_permissions_permission_id ON role_permissions(permission_id);
+
+CREATE TABLE IF NOT EXISTS role_revisions (
+    id TEXT PRIMARY KEY,
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    role_id TEXT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    actor_id TEXT,
+    old_role_name VARCHAR(255) NOT NULL,
+    new_role_name VARCHAR(255) NOT NULL,
+    old_claims TEXT NOT NULL DEFAULT '{}',
+    new_claims TEXT NOT NULL DEFAULT '{}',
+    diff TEXT NOT NULL DEFAULT '{}',
+    rollback_of INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    UNIQUE (role_id, revision)
+);
+
+CREATE TABLE IF NOT EXISTS role_templates (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    role_claims TEXT NOT NULL DEFAULT '{}',
+    permissions TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT (now()),
+    updated_at TIMESTAMP DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_role_templates_name ON role_templates(name);
+
+CREATE TABLE IF NOT EXISTS authz_decisions (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    role_id TEXT NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    matched_rule VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_authz_decisions_domain_role_created ON authz_decisions(domain_id, role_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS groups (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT (now()),
+    updated_at TIMESTAMP DEFAULT (now()),
+    UNIQUE (domain_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS group_members (
+    group_id TEXT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT (now()),
+    PRIMARY KEY (group_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_members_user_id ON group_members(user_id);
+
+CREATE TABLE IF NOT EXISTS group_roles (
+    group_id TEXT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    role_id TEXT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT (now()),
+    PRIMARY KEY (group_id, role_id)
+);
+CREATE INDEX IF NOT EXISTS idx_group_roles_role_id ON group_roles(role_id);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    rate_limit_per_minute INTEGER,
+    daily_quota INTEGER,
+    created_at TIMESTAMP DEFAULT (now()),
+    updated_at TIMESTAMP DEFAULT (now()),
+    revoked_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_domain_id ON api_keys(domain_id);
+
+CREATE TABLE IF NOT EXISTS login_risk_policies (
+    domain_id TEXT PRIMARY KEY REFERENCES domains(domain_id) ON DELETE CASCADE,
+    captcha_threshold INTEGER CHECK (captcha_threshold BETWEEN 1 AND 100),
+    mfa_threshold INTEGER CHECK (mfa_threshold BETWEEN 1 AND 100),
+    block_threshold INTEGER CHECK (block_threshold BETWEEN 1 AND 100),
+    captcha_after_failures INTEGER CHECK (captcha_after_failures BETWEEN 1 AND 100),
+    updated_at TIMESTAMP DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS policies (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    document TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT (now()),
+    updated_at TIMESTAMP DEFAULT (now()),
+    UNIQUE (domain_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS login_codes (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    consumed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_login_codes_user_id ON login_codes(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS event_sequences (
+    domain_id TEXT PRIMARY KEY REFERENCES domains(domain_id) ON DELETE CASCADE,
+    last_sequence BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS events (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    sequence BIGINT NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    subject_id TEXT,
+    impersonator_id TEXT,
+    payload TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT (now()),
+    UNIQUE (domain_id, sequence)
+);
+
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id TEXT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT (now()),
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_event_outbox_due ON event_outbox(next_attempt_at, id);
+CREATE INDEX IF NOT EXISTS idx_event_outbox_domain ON event_outbox(domain_id, id);
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    events TEXT NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT (now()),
+    updated_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_domain_id ON webhooks(domain_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    webhook_id TEXT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    event_id TEXT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    event_type VARCHAR(100) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    last_attempt_at TIMESTAMP,
+    response_status INTEGER,
+    error TEXT,
+    created_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS domain_mail_settings (
+    domain_id TEXT PRIMARY KEY REFERENCES domains(domain_id) ON DELETE CASCADE,
+    provider VARCHAR(16) NOT NULL DEFAULT 'smtp' CHECK (provider IN ('smtp', 'ses')),
+    host VARCHAR(255) NOT NULL,
+    port VARCHAR(8) NOT NULL DEFAULT '587',
+    region VARCHAR(32),
+    username VARCHAR(255),
+    password TEXT,
+    from_address VARCHAR(320) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_tested_at TIMESTAMP,
+    last_test_error TEXT,
+    updated_at TIMESTAMP DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS domain_email_branding (
+    domain_id TEXT PRIMARY KEY REFERENCES domains(domain_id) ON DELETE CASCADE,
+    product_name VARCHAR(255) NOT NULL DEFAULT '',
+    support_email VARCHAR(320) NOT NULL DEFAULT '',
+    footer TEXT NOT NULL DEFAULT '',
+    templates TEXT NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS password_history (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_password_history_user_id ON password_history(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS integration_health (
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    kind VARCHAR(32) NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('healthy', 'unhealthy')),
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_checked_at TIMESTAMP NOT NULL,
+    last_success_at TIMESTAMP,
+    PRIMARY KEY (domain_id, kind)
+);
+
+CREATE TABLE IF NOT EXISTS profile_consents (
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id TEXT NOT NULL,
+    fields TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT (now()),
+    updated_at TIMESTAMP DEFAULT (now()),
+    PRIMARY KEY (user_id, client_id)
+);
+CREATE INDEX IF NOT EXISTS idx_profile_consents_client_id ON profile_consents(client_id);
+
+CREATE TABLE IF NOT EXISTS registration_codes (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    code_prefix VARCHAR(16) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL UNIQUE,
+    role_id TEXT REFERENCES roles(id) ON DELETE CASCADE,
+    max_uses INTEGER,
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_registration_codes_domain_id ON registration_codes(domain_id);
+
+CREATE TABLE IF NOT EXISTS invitations (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role_id TEXT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    first_name VARCHAR(255) NOT NULL DEFAULT '',
+    last_name VARCHAR(255) NOT NULL DEFAULT '',
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT (now()),
+    accepted_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_invitations_domain_email ON invitations(domain_id, LOWER(email));
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+CREATE TABLE IF NOT EXISTS domain_jobs (
+    id TEXT PRIMARY KEY,
+    action VARCHAR(32) NOT NULL CHECK (action IN ('suspend', 'unsuspend', 'message', 'update_settings')),
+    selector TEXT NOT NULL,
+    message TEXT,
+    settings TEXT,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    total INTEGER NOT NULL DEFAULT 0,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    results TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT (now()),
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_domain_jobs_created_at ON domain_jobs(created_at DESC);
+
+CREATE TABLE IF NOT EXISTS telemetry_export_cursors (
+    domain_id TEXT PRIMARY KEY REFERENCES domains(domain_id) ON DELETE CASCADE,
+    last_sequence BIGINT NOT NULL,
+    locked_until TIMESTAMP,
+    exported_at TIMESTAMP,
+    last_error TEXT,
+    updated_at TIMESTAMP DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS domain_deletions (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    domain_id TEXT NOT NULL,
+    domain_name VARCHAR(255) NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    step VARCHAR(64) NOT NULL DEFAULT '',
+    deleted TEXT NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    lease_until TIMESTAMP,
+    created_at TIMESTAMP DEFAULT (now()),
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_domain_deletions_domain_id ON domain_deletions(domain_id, created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_domain_deletions_active ON domain_deletions(domain_id)
+    WHERE status IN ('pending', 'running');
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id TEXT PRIMARY KEY DEFAULT (gen_random_uuid()),
+    kind VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL DEFAULT '{}',
+    status VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'running', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    run_at TIMESTAMP NOT NULL DEFAULT (now()),
+    lease_until TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT (now()),
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_due ON jobs(run_at) WHERE status IN ('pending', 'running');
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_jobs_kind ON jobs(kind, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS trusted_devices (
+    id TEXT PRIMARY KEY,
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    last_ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT (now()),
+    last_used_at TIMESTAMP DEFAULT (now()),
+    expires_at TIMESTAMP NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_trusted_devices_user_id ON trusted_devices(user_id);
+
+CREATE TABLE IF NOT EXISTS login_history (
+    id TEXT PRIMARY KEY,
+    domain_id TEXT NOT NULL REFERENCES domains(domain_id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    success BOOLEAN NOT NULL,
+    method VARCHAR(32) NOT NULL,
+    reason VARCHAR(64) NOT NULL DEFAULT '',
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (now())
+);
+CREATE INDEX IF NOT EXISTS idx_login_history_user_id_created_at ON login_history(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope VARCHAR(255) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INTEGER,
+    content_type VARCHAR(255),
+    response_body TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT (now()),
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, idempotency_key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+INSERT INTO schema_migrations (version) VALUES
+    ('001_create_domains_table.sql'),
+    ('002_create_users_table.sql'),
+    ('003_create_roles_table.sql'),
+    ('004_create_domain_aliases_table.sql'),
+    ('005_add_residency_to_domains.sql'),
+    ('006_create_permissions_tables.sql'),
+    ('007_create_authz_decisions_table.sql'),
+    ('008_create_groups_tables.sql'),
+    ('009_create_api_keys_table.sql'),
+    ('010_create_login_risk_policies_table.sql'),
+    ('011_create_policies_table.sql'),
+    ('012_add_login_mode_to_domains.sql'),
+    ('013_create_login_codes_table.sql'),
+    ('014_add_external_id_to_users.sql'),
+    ('015_create_events_tables.sql'),
+    ('016_scope_user_uniqueness_to_domain.sql'),
+    ('017_add_validity_to_users.sql'),
+    ('018_add_break_glass_to_users.sql'),
+    ('019_add_password_policy_to_domains.sql'),
+    ('020_create_domain_mail_settings_table.sql'),
+    ('021_create_password_history_table.sql'),
+    ('022_create_integration_health_table.sql'),
+    ('023_add_password_changed_at_to_users.sql'),
+    ('024_create_profile_consents_table.sql'),
+    ('025_add_self_registration.sql'),
+    ('026_create_invitations_table.sql'),
+    ('027_add_domain_branding.sql'),
+    ('028_create_revoked_tokens_table.sql'),
+    ('029_add_account_self_deletion.sql'),
+    ('030_add_domain_token_settings.sql'),
+    ('031_add_domain_data_masking.sql'),
+    ('032_add_domain_operations.sql'),
+    ('033_create_webhooks_tables.sql'),
+    ('034_create_event_outbox_table.sql'),
+    ('035_add_login_telemetry_export.sql'),
+    ('036_add_cursor_pagination_indexes.sql'),
+    ('037_add_user_search_index.sql'),
+    ('038_create_role_templates_table.sql'),
+    ('039_add_async_domain_deletion.sql'),
+    ('040_create_jobs_table.sql'),
+    ('041_create_domain_email_branding_table.sql'),
+    ('042_add_captcha_failures_to_login_risk_policies.sql'),
+    ('043_create_trusted_devices_table.sql'),
+    ('044_add_login_history.sql'),
+    ('045_add_impersonator_to_events.sql'),
+    ('046_add_service_accounts.sql'),
+    ('047_add_user_avatars.sql'),
+    ('048_create_org_units_table.sql'),
+    ('049_create_role_revisions_table.sql'),
+    ('050_create_idempotency_keys_table.sql');